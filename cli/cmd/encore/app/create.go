@@ -5,9 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"go/token"
 	"io/fs"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strings"
 	"time"
@@ -15,8 +17,10 @@ import (
 	"github.com/briandowns/spinner"
 	"github.com/cockroachdb/errors"
 	"github.com/fatih/color"
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/spf13/cobra"
 	"github.com/tailscale/hujson"
+	"golang.org/x/mod/module"
 	"golang.org/x/term"
 
 	"encr.dev/cli/cmd/encore/auth"
@@ -34,15 +38,41 @@ import (
 )
 
 var (
-	createAppTemplate   string
-	createAppOnPlatform bool
-	createAppLang       = cmdutil.Oneof{
-		Value:     "",
-		Allowed:   cmdutil.LanguageFlagValues(),
-		Flag:      "lang",
-		FlagShort: "l",
-		Desc:      "Programming language to use for the app",
-		TypeDesc:  "string",
+	createAppTemplate        string
+	createAppOnPlatform      bool
+	createAppRepeat          bool
+	createAppDefaultTemplate bool
+	createAppGit             bool
+	createAppAdvanced        bool
+	createAppParentDir       string
+	createAppPath            string
+	createAppInPlace         bool
+	createAppAddonFlags      []string
+	createAppAddons          []Addon // parsed from createAppAddonFlags, or nil if not given
+	createAppTemplateSources []string
+	createAppValidateOnly    bool
+	createAppDryRun          bool
+	createAppYes             bool
+	createAppOffline         bool
+	createAppFromOpenAPI     string
+	createAppResume          string
+	createAppWorkspace       string
+	createAppOrg             string // the org to create the app in on the platform, set by workspace manifests
+	createAppLang            = cmdutil.Oneof{
+		Value:       "",
+		Allowed:     cmdutil.LanguageFlagValues(),
+		AllowedFunc: cachedLanguageFlagValues,
+		Flag:        "lang",
+		FlagShort:   "l",
+		Desc:        "Programming language to use for the app",
+		TypeDesc:    "string",
+	}
+	createAppGitRemote = cmdutil.Oneof{
+		Value:    "",
+		Allowed:  gitRemoteFlagValues,
+		Flag:     "git-remote",
+		Desc:     "Create a private repository for the app on GitHub or GitLab and push it, instead of prompting for it",
+		TypeDesc: "string",
 	}
 	createAppLLMRules = cmdutil.Oneof{
 		Value:     "",
@@ -66,6 +96,70 @@ var createAppCmd = &cobra.Command{
 			name = args[0]
 		}
 
+		if createAppPath != "" {
+			if createAppParentDir != "" {
+				cmdutil.Fatal("--path and --dir cannot be used together")
+			} else if createAppRepeat {
+				cmdutil.Fatal("--path and --repeat cannot be used together")
+			}
+		}
+
+		if createAppInPlace {
+			if createAppParentDir != "" {
+				cmdutil.Fatal("--in-place and --dir cannot be used together")
+			} else if createAppRepeat {
+				cmdutil.Fatal("--in-place and --repeat cannot be used together")
+			}
+			if createAppPath == "" {
+				createAppPath = "."
+			}
+		}
+
+		if createAppResume != "" {
+			if name != "" || createAppTemplate != "" || createAppPath != "" || createAppParentDir != "" ||
+				createAppInPlace || createAppRepeat || createAppFromOpenAPI != "" || cmd.Flags().Changed("addons") {
+				cmdutil.Fatal("--resume cannot be used with an app name or the options of a new app")
+			}
+		}
+
+		if createAppWorkspace != "" {
+			if name != "" || createAppTemplate != "" || createAppLang.Value != "" || createAppInPlace || createAppRepeat ||
+				createAppResume != "" || createAppFromOpenAPI != "" || createAppDryRun || createAppValidateOnly || cmd.Flags().Changed("addons") {
+				cmdutil.Fatal("--workspace cannot be used with an app name or the options of a single app, which are given by the manifest")
+			}
+		}
+
+		if createAppDryRun {
+			if createAppResume != "" {
+				cmdutil.Fatal("--dry-run and --resume cannot be used together")
+			} else if createAppRepeat {
+				cmdutil.Fatal("--dry-run and --repeat cannot be used together")
+			}
+		}
+
+		if createAppFromOpenAPI != "" {
+			if createAppTemplate != "" {
+				cmdutil.Fatal("--from-openapi and --example cannot be used together")
+			} else if createAppRepeat {
+				cmdutil.Fatal("--from-openapi and --repeat cannot be used together")
+			}
+			// The services are generated into the empty template.
+			createAppTemplate = "empty"
+		}
+
+		if cmd.Flags().Changed("addons") {
+			addons, err := parseAddons(createAppAddonFlags)
+			if err != nil {
+				cmdutil.Fatal(err)
+			}
+			createAppAddons = addons
+		}
+
+		if createAppValidateOnly {
+			validateCreateInputs(name, cmdutil.Language(createAppLang.Value), createAppTemplate, createAppTemplateSources)
+			return
+		}
+
 		var tool llm_rules.Tool
 		if createAppLLMRules.Value == "" {
 			cfg, err := userconfig.Global().Get()
@@ -77,7 +171,29 @@ var createAppCmd = &cobra.Command{
 			tool = llm_rules.Tool(createAppLLMRules.Value)
 		}
 
-		if err := createApp(context.Background(), name, createAppTemplate, cmdutil.Language(createAppLang.Value), tool); err != nil {
+		if createAppDryRun {
+			dryRunCreateApp(context.Background(), name, createAppTemplate, cmdutil.Language(createAppLang.Value), tool)
+			return
+		}
+
+		if createAppWorkspace != "" {
+			if err := createWorkspace(context.Background(), createAppWorkspace, tool); errors.Is(err, errCreateCancelled) {
+				_, _ = color.New(color.FgYellow).Fprintln(os.Stderr, "Cancelled, the workspace was not completed.")
+				os.Exit(1)
+			} else if err != nil {
+				cmdutil.Fatal(err)
+			}
+			return
+		}
+
+		create := createApp
+		if createAppRepeat {
+			create = createApps
+		}
+		if err := create(context.Background(), name, createAppTemplate, cmdutil.Language(createAppLang.Value), tool); errors.Is(err, errCreateCancelled) {
+			_, _ = color.New(color.FgYellow).Fprintln(os.Stderr, "Cancelled, no app was created.")
+			os.Exit(1)
+		} else if err != nil {
 			cmdutil.Fatal(err)
 		}
 	},
@@ -87,13 +203,37 @@ func init() {
 	appCmd.AddCommand(createAppCmd)
 	createAppCmd.Flags().BoolVar(&createAppOnPlatform, "platform", true, "whether to create the app with the Encore Platform")
 	createAppCmd.Flags().StringVar(&createAppTemplate, "example", "", "URL to example code to use.")
+	createAppCmd.Flags().BoolVar(&createAppRepeat, "repeat", false, "Offer to create another app after each successful creation")
+	createAppCmd.Flags().BoolVar(&createAppDefaultTemplate, "default-template", false, "Use the default template for the language instead of prompting")
+	createAppCmd.Flags().BoolVar(&createAppAdvanced, "advanced", false, "Show advanced templates when selecting a template")
+	createAppCmd.Flags().StringVar(&createAppParentDir, "dir", "", "Parent directory to create the app in, such as 'services' in a monorepo")
+	createAppCmd.Flags().StringArrayVar(&createAppTemplateSources, "template-source", nil, "Custom source of templates to list along with Encore's: a GitHub repository with a cli-templates.json manifest, the URL of a manifest, or a local manifest or directory (repeatable)")
+	createAppCmd.Flags().StringVar(&createAppPath, "path", "", "Directory to create the app in, instead of one named after the app. It may already exist if it's empty")
+	createAppCmd.Flags().BoolVar(&createAppInPlace, "in-place", false, "Create the app in an existing directory even if it isn't empty, such as a monorepo subfolder: the one given by --path, or the current directory")
+	createAppCmd.Flags().StringVar(&createAppFromOpenAPI, "from-openapi", "", "Generate the app's services from an OpenAPI 3 specification, given as a file path or URL, with one service per tag")
+	createAppCmd.Flags().StringVar(&createAppResume, "resume", "", "Resume creating the app in the given directory, from the last step that succeeded before creating it failed")
+	createAppCmd.Flags().StringVar(&createAppWorkspace, "workspace", "", "Create the related apps of a workspace manifest, such as an API, a worker and a frontend, in a directory with a shared README")
+	createAppCmd.Flags().StringSliceVar(&createAppAddonFlags, "addons", nil, fmt.Sprintf("Infrastructure to add to the app instead of prompting for it (%s)", strings.Join(addonFlagValues(), ", ")))
+	createAppCmd.Flags().BoolVar(&createAppGit, "git", true, "Initialize a git repository in the app directory with an initial commit")
+	createAppCmd.Flags().BoolVar(&createAppValidateOnly, "validate-only", false, "Only validate the app name, language and template, printing the results as JSON")
+	createAppCmd.Flags().BoolVar(&createAppDryRun, "dry-run", false, "Print the plan for creating the app as JSON, such as the template and files it creates, without creating it (implies --yes)")
+	createAppCmd.Flags().BoolVar(&createAppOffline, "offline", false, "Don't use the network, creating the app from cached templates (see 'encore app prefetch-templates')")
+	createAppCmd.Flags().BoolVarP(&createAppYes, "yes", "y", false, "Don't prompt for anything, using the defaults for what's not given (requires an app name)")
+	createAppCmd.Flags().BoolVar(&createAppYes, "defaults", false, "Alias for --yes")
+	_ = createAppCmd.Flags().MarkHidden("defaults")
 	createAppLang.AddFlag(createAppCmd)
 	createAppLLMRules.AddFlag(createAppCmd)
+	createAppGitRemote.AddFlag(createAppCmd)
+}
+
+// canPrompt reports whether to prompt the user,
+// which requires a terminal and that --yes isn't given.
+func canPrompt() bool {
+	return !createAppYes && term.IsTerminal(int(os.Stdin.Fd()))
 }
 
 func promptAccountCreation() {
-	// If shell is non-interactive, don't prompt
-	if !term.IsTerminal(int(os.Stdin.Fd())) {
+	if !canPrompt() {
 		return
 	}
 	cyan := color.New(color.FgCyan)
@@ -128,8 +268,7 @@ func promptAccountCreation() {
 }
 
 func promptRunApp() bool {
-	// If shell is non-interactive, don't prompt
-	if !term.IsTerminal(int(os.Stdin.Fd())) {
+	if !canPrompt() {
 		return false
 	}
 
@@ -157,23 +296,157 @@ func promptRunApp() bool {
 	}
 }
 
+func promptCreateAnother() bool {
+	if !canPrompt() {
+		return false
+	}
+
+	cyan := color.New(color.FgCyan)
+	red := color.New(color.FgRed)
+	for {
+		_, _ = cyan.Fprint(os.Stderr, "Create another app? (y/N): ")
+		var input string
+		_, _ = fmt.Scanln(&input)
+		input = strings.TrimSpace(input)
+		switch input {
+		case "Y", "y", "yes":
+			return true
+		case "N", "n", "no", "", "q", "quit", "exit":
+			return false
+		default:
+			// Try again.
+			_, _ = red.Fprintln(os.Stderr, "Unexpected answer, please enter 'y' or 'n'.")
+		}
+	}
+}
+
+// errCreateCancelled is reported when the user cancels creating an app
+// while its template is being fetched, or declines to create it in place
+// when the template's files conflict with existing ones.
+var errCreateCancelled = errors.New("app creation cancelled")
+
+// createdApp describes an app that was successfully created by scaffoldApp.
+type createdApp struct {
+	Name    string
+	Lang    cmdutil.Language
+	AppRoot string // absolute path to the app root
+	RunDir  string // path to the app root, relative to the working directory
+	Linked  bool   // whether the app was created on the Encore Platform
+}
+
+// appDir reports the directory the app with the given name is created in:
+// path if it's given, and otherwise the directory named after the app
+// in parentDir.
+func appDir(parentDir, path, name string) string {
+	if path != "" {
+		return path
+	}
+	return filepath.Join(parentDir, name)
+}
+
+// dirInUse reports whether dir can't be used to create an app in,
+// since it exists and either isn't a directory or isn't empty.
+func dirInUse(dir string) bool {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return false
+	} else if !fi.IsDir() {
+		return true
+	}
+	entries, err := os.ReadDir(dir)
+	return err != nil || len(entries) > 0
+}
+
+// removeContents removes everything in dir, but not dir itself.
+func removeContents(dir string) {
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		_ = os.RemoveAll(filepath.Join(dir, e.Name()))
+	}
+}
+
 // createApp is the implementation of the "encore app create" command.
-func createApp(ctx context.Context, name, template string, lang cmdutil.Language, llmRules llm_rules.Tool) (err error) {
+func createApp(ctx context.Context, name, template string, lang cmdutil.Language, llmRules llm_rules.Tool) error {
+	app, err := scaffoldApp(ctx, name, template, lang, "", llmRules)
+	if err != nil {
+		return err
+	}
+	return app.nextSteps(ctx)
+}
+
+// createApps is like createApp, but keeps offering to create another app
+// after each successful creation. Each new app starts from fresh state,
+// with the previously selected language as the default.
+func createApps(ctx context.Context, name, template string, lang cmdutil.Language, llmRules llm_rules.Tool) error {
+	var (
+		created     []*createdApp
+		defaultLang cmdutil.Language
+	)
+	for {
+		app, err := scaffoldApp(ctx, name, template, lang, defaultLang, llmRules)
+		if err != nil {
+			return err
+		}
+		created = append(created, app)
+
+		if !promptCreateAnother() {
+			break
+		}
+		cmdutil.ClearTerminalExceptFirstNLines(0)
+		name, template, lang, defaultLang = "", "", "", app.Lang
+	}
+
+	if len(created) == 1 {
+		return created[0].nextSteps(ctx)
+	}
+
+	cyan := color.New(color.FgCyan)
+	green := color.New(color.FgGreen)
+	cmdutil.ClearTerminalExceptFirstNLines(0)
+	_, _ = green.Printf("Successfully created %d apps:\n\n", len(created))
+	for _, app := range created {
+		if createAppEditor != "" {
+			app.maybeOpenEditor()
+		}
+		_, _ = cyan.Printf("    %s\n", app.Name)
+		fmt.Printf("        cd %s && encore run\n\n", app.RunDir)
+	}
+	return nil
+}
+
+// scaffoldApp creates a new app, prompting for any missing input.
+// If the language is not given, defaultLang is preselected in the form.
+func scaffoldApp(ctx context.Context, name, template string, lang, defaultLang cmdutil.Language, llmRules llm_rules.Tool) (created *createdApp, err error) {
 	defer func() {
 		// We need to send the telemetry synchronously to ensure it's sent before the command exits.
 		telemetry.SendSync("app.create", map[string]any{
-			"template": template,
-			"lang":     lang,
-			"error":    err != nil,
+			"template":     template,
+			"lang":         lang,
+			"from_openapi": createAppFromOpenAPI != "",
+			"error":        err != nil,
 		})
 	}()
 	cyan := color.New(color.FgCyan)
 	green := color.New(color.FgGreen)
 
-	promptAccountCreation()
+	if !createAppOffline && createAppResume == "" {
+		promptAccountCreation()
+	}
 
-	if name == "" || template == "" || llmRules == "" {
-		name, template, lang, llmRules = createAppForm(name, template, lang, llmRules, false)
+	var (
+		templateVars map[string]string
+		addons       = createAppAddons
+		journal      *createJournal // the steps completed so far
+	)
+	if createAppResume != "" {
+		if journal, err = readJournal(createAppResume); err != nil {
+			return nil, err
+		}
+		journal.restoreFlags()
+		name, template, lang, llmRules = journal.Name, journal.Template, journal.Lang, journal.LLMRules
+		templateVars, addons = journal.TemplateVars, journal.Addons
+	} else if name == "" || template == "" || llmRules == "" {
+		name, template, lang, llmRules, templateVars, addons = createAppForm(name, template, lang, defaultLang, llmRules, false)
 	}
 	// Treat the special name "empty" as the empty app template
 	// (the rest of the code assumes that's the empty string).
@@ -184,140 +457,322 @@ func createApp(ctx context.Context, name, template string, lang cmdutil.Language
 		template = "ts/empty"
 	}
 
-	if err := validateName(name); err != nil {
-		return err
-	} else if _, err := os.Stat(name); err == nil {
-		return fmt.Errorf("directory %s already exists", name)
+	dir := appDir(createAppParentDir, createAppPath, name)
+	if err := validateNameForLang(name, lang); err != nil {
+		return nil, err
+	} else if journal != nil {
+		// The directory contains the partially created app.
+	} else if createAppInPlace {
+		if err := checkInPlaceDir(dir); err != nil {
+			return nil, err
+		}
+	} else if dirInUse(dir) {
+		return nil, fmt.Errorf("directory %s already exists and is not empty", dir)
+	}
+
+	resuming := journal != nil
+	if !resuming {
+		journal = newJournal(dir)
+		journal.Name, journal.Template, journal.Lang, journal.LLMRules = name, template, lang, llmRules
+		journal.TemplateVars, journal.Addons = templateVars, addons
+		journal.FromOpenAPI, journal.Platform, journal.Git = createAppFromOpenAPI, createAppOnPlatform, createAppGit
+		if createAppInPlace {
+			// Resuming isn't supported when merging into an existing directory.
+			journal.path = ""
+		}
 	}
 
+	var spec *openapi3.T
+	if createAppFromOpenAPI != "" && !journal.done(journalStepFiles) {
+		if spec, err = loadOpenAPISpec(ctx, createAppFromOpenAPI); err != nil {
+			return nil, err
+		}
+	}
+
+	// Let the user cancel fetching the template with Ctrl+C,
+	// in which case the partially created app is removed.
+	fetchCtx, stopFetch := signal.NotifyContext(ctx, os.Interrupt)
+	defer stopFetch()
+	cancelled := func() bool { return fetchCtx.Err() != nil && ctx.Err() == nil }
+
 	// Parse template information, if provided.
+	// Templates of local template sources are copied rather than downloaded,
+	// as are prefetched templates when offline.
 	var ex *github.Tree
-	if template != "" {
+	localTemplate := isLocalTemplate(template)
+	if template != "" && !localTemplate && !createAppOffline && !journal.done(journalStepTemplate) {
 		var err error
-		ex, err = parseTemplate(ctx, template)
-		if err != nil {
-			return err
+		ex, err = parseTemplate(fetchCtx, template)
+		if cancelled() {
+			return nil, errCreateCancelled
+		} else if err != nil {
+			return nil, err
 		}
 	}
 
-	if err := os.Mkdir(name, 0755); err != nil {
-		return err
+	_, statErr := os.Stat(dir)
+	existed := statErr == nil
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	// When creating the app in place in a directory that isn't empty,
+	// the template is set up in srcDir and then merged into it.
+	srcDir := dir
+	mergeInto := createAppInPlace && dirInUse(dir)
+	var merged []string       // the paths added to dir by the merge
+	var original fileSnapshot // the files in dir before the merge
+	if mergeInto {
+		if srcDir, err = os.MkdirTemp("", "encore-app-"); err != nil {
+			return nil, err
+		}
+		defer func() { _ = os.RemoveAll(srcDir) }()
 	}
+
 	defer func() {
 		if err != nil {
-			// Clean up the directory we just created in case of an error.
-			_ = os.RemoveAll(name)
+			// Keep the partially created app if the creation can be resumed.
+			if journal.resumable() && !errors.Is(err, errCreateCancelled) {
+				err = fmt.Errorf("%w\n\nThe app was partially created in %s. Continue creating it with: encore app create --resume %s", err, dir, dir)
+				return
+			}
+
+			// Clean up the directory we just created in case of an error,
+			// or what we created in it if it already existed.
+			journal.remove()
+			switch {
+			case mergeInto:
+				removeCreated(dir, merged)
+				original.restore(dir)
+			case existed:
+				removeContents(dir)
+			default:
+				_ = os.RemoveAll(dir)
+			}
 		}
 	}()
 
-	if ex != nil {
-		s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
-		s.Prefix = fmt.Sprintf("Downloading template %s ", ex.Name())
-		s.Start()
-		err := github.ExtractTree(ctx, ex, name)
-		s.Stop()
-		fmt.Println()
-
-		if err != nil {
-			return fmt.Errorf("failed to download template %s: %v", ex.Name(), err)
-		}
-		gray := color.New(color.Faint)
-		_, _ = gray.Printf("Downloaded template %s.\n", ex.Name())
+	var exCfg exampleConfig
+	if journal.done(journalStepTemplate) {
+		exCfg = *journal.Example
 	} else {
-		// Set up files that we need when we don't have an example
-		if err := xos.WriteFile(filepath.Join(name, ".gitignore"), []byte("/.encore\n"), 0644); err != nil {
-			cmdutil.Fatal(err)
+		if resuming {
+			// Start over from a partially extracted template.
+			removeContents(dir)
 		}
-		encoreModData := []byte("module encore.app\n")
-		if err := xos.WriteFile(filepath.Join(name, "go.mod"), encoreModData, 0644); err != nil {
-			cmdutil.Fatal(err)
+
+		if ex != nil {
+			// Save the journal before downloading the template,
+			// so that a failed download can be resumed.
+			_ = journal.save()
+
+			s := spinner.New(cmdutil.SpinnerCharSet(), 100*time.Millisecond)
+			s.Prefix = fmt.Sprintf("Downloading template %s ", ex.Name())
+			s.Start()
+			err := github.ExtractTree(fetchCtx, ex, srcDir)
+			s.Stop()
+			fmt.Println()
+
+			if cancelled() {
+				return nil, errCreateCancelled
+			} else if err != nil {
+				// Use the cached template, if it's been prefetched.
+				if cached, cacheErr := useCachedTemplate(template, srcDir); cacheErr != nil {
+					return nil, fmt.Errorf("failed to copy cached template %s: %v", ex.Name(), cacheErr)
+				} else if !cached {
+					return nil, fmt.Errorf("failed to download template %s: %v", ex.Name(), err)
+				}
+				_, _ = color.New(color.FgYellow).Printf("Could not download template %s, using cached copy.\n", ex.Name())
+			} else {
+				gray := color.New(color.Faint)
+				_, _ = gray.Printf("Downloaded template %s.\n", ex.Name())
+			}
+			stopFetch()
+		} else if localTemplate {
+			if err := os.CopyFS(srcDir, os.DirFS(template)); err != nil {
+				return nil, fmt.Errorf("failed to copy template %s: %v", template, err)
+			}
+		} else if template != "" {
+			if cached, err := copyCachedTemplate(template, srcDir); err != nil {
+				return nil, fmt.Errorf("failed to copy cached template %s: %v", template, err)
+			} else if !cached {
+				return nil, fmt.Errorf("template %s is not cached for offline use, run 'encore app prefetch-templates --sources' to cache it", template)
+			}
+		} else {
+			// Set up files that we need when we don't have an example
+			if err := xos.WriteFile(filepath.Join(srcDir, ".gitignore"), []byte("/.encore\n"), 0644); err != nil {
+				cmdutil.Fatal(err)
+			}
+			encoreModData := []byte("module encore.app\n")
+			if err := xos.WriteFile(filepath.Join(srcDir, "go.mod"), encoreModData, 0644); err != nil {
+				cmdutil.Fatal(err)
+			}
+		}
+
+		exCfg, err = parseExampleConfig(srcDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse example config: %v", err)
 		}
+
+		// Delete the example config file.
+		_ = os.Remove(exampleJSONPath(srcDir))
+
+		journal.Example = &exCfg
+		journal.complete(journalStepTemplate)
 	}
 
 	_, err = conf.CurrentUser()
 	loggedIn := err == nil
 
-	exCfg, err := parseExampleConfig(name)
-	if err != nil {
-		return fmt.Errorf("failed to parse example config: %v", err)
+	app := journal.App
+	if !journal.done(journalStepPlatform) {
+		if loggedIn && createAppOnPlatform && !createAppOffline {
+			s := spinner.New(cmdutil.SpinnerCharSet(), 100*time.Millisecond)
+			s.Prefix = "Creating app on encore.dev "
+			s.Start()
+			app, err = createAppOnServer(name, exCfg)
+			s.Stop()
+			if err != nil {
+				return nil, fmt.Errorf("creating app on encore.dev: %v", err)
+			}
+			journal.App = app
+		}
+		journal.complete(journalStepPlatform)
 	}
 
-	// Delete the example config file.
-	_ = os.Remove(exampleJSONPath(name))
+	appRootRelpath := filepath.FromSlash(exCfg.EncoreAppPath)
+	if !journal.done(journalStepFiles) {
+		encoreAppPath := filepath.Join(srcDir, appRootRelpath, "encore.app")
+		appData, err := os.ReadFile(encoreAppPath)
+		if err != nil {
+			appData, err = []byte("{}"), nil
+		}
 
-	var app *platform.App
-	if loggedIn && createAppOnPlatform {
-		s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
-		s.Prefix = "Creating app on encore.dev "
-		s.Start()
-		app, err = createAppOnServer(name, exCfg)
-		s.Stop()
+		if app != nil {
+			appData, err = setEncoreAppID(appData, app.Slug, []string{})
+		} else {
+			appData, err = setEncoreAppID(appData, "", []string{
+				"The app is not currently linked to the encore.dev platform.",
+				`Use "encore app link" to link it.`,
+			})
+		}
 		if err != nil {
-			return fmt.Errorf("creating app on encore.dev: %v", err)
+			return nil, errors.Wrap(err, "write encore.app file")
+		}
+		if err := xos.WriteFile(encoreAppPath, appData, 0644); err != nil {
+			return nil, errors.Wrap(err, "write encore.app file")
 		}
-	}
 
-	appRootRelpath := filepath.FromSlash(exCfg.EncoreAppPath)
-	encoreAppPath := filepath.Join(name, appRootRelpath, "encore.app")
-	appData, err := os.ReadFile(encoreAppPath)
-	if err != nil {
-		appData, err = []byte("{}"), nil
-	}
+		// Rewrite any existence of ENCORE_APP_ID to the allocated app id,
+		// and any template variables to their values.
+		var placeholders []string
+		if app != nil {
+			placeholders = append(placeholders, "{{ENCORE_APP_ID}}", app.Slug)
+		}
+		for k, v := range templateVars {
+			placeholders = append(placeholders, "{{"+k+"}}", v)
+		}
+		if len(placeholders) > 0 {
+			if err := rewritePlaceholders(srcDir, placeholders); err != nil {
+				red := color.New(color.FgRed)
+				_, _ = red.Printf("Failed rewriting source code placeholders, skipping: %v\n", err)
+			}
+		}
 
-	if app != nil {
-		appData, err = setEncoreAppID(appData, app.Slug, []string{})
-	} else {
-		appData, err = setEncoreAppID(appData, "", []string{
-			"The app is not currently linked to the encore.dev platform.",
-			`Use "encore app link" to link it.`,
-		})
-	}
-	if err != nil {
-		return errors.Wrap(err, "write encore.app file")
-	}
-	if err := xos.WriteFile(encoreAppPath, appData, 0644); err != nil {
-		return errors.Wrap(err, "write encore.app file")
+		if spec != nil {
+			skipped, err := scaffoldOpenAPI(filepath.Join(srcDir, appRootRelpath), detectLang(filepath.Join(srcDir, appRootRelpath)), spec)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate services from %s: %v", createAppFromOpenAPI, err)
+			}
+			for _, op := range skipped {
+				_, _ = color.New(color.FgYellow).Printf("Skipped %s.\n", op)
+			}
+		}
+
+		if err := scaffoldAddons(filepath.Join(srcDir, appRootRelpath), detectLang(filepath.Join(srcDir, appRootRelpath)), addons); err != nil {
+			red := color.New(color.FgRed)
+			_, _ = red.Printf("Failed adding infrastructure, skipping: %v\n", err)
+		}
+
+		if mergeInto {
+			var conflicts, skipped []string
+			if conflicts, err = templateConflicts(srcDir, dir); err != nil {
+				return nil, fmt.Errorf("failed to check the template's files against %s: %v", dir, err)
+			} else if err := confirmConflicts(dir, conflicts); err != nil {
+				return nil, err
+			}
+
+			// Snapshot the existing project files, as they're modified by
+			// the merge and when installing dependencies.
+			if original, err = snapshotProjectFiles(dir, appRootRelpath); err != nil {
+				return nil, fmt.Errorf("failed to read the existing project files in %s: %v", dir, err)
+			}
+			merged, skipped, err = mergeTemplate(srcDir, dir)
+			if err != nil {
+				return nil, fmt.Errorf("failed to merge template into %s: %v", dir, err)
+			}
+			for _, f := range skipped {
+				_, _ = color.New(color.FgYellow).Printf("Kept the existing %s, skipping the template's.\n", f)
+			}
+		}
+		journal.complete(journalStepFiles)
 	}
 
-	// Update to latest encore.dev release
-	if _, err := os.Stat(filepath.Join(name, appRootRelpath, "go.mod")); err == nil {
+	// Update to latest encore.dev release, unless offline.
+	// The language is detected from the template, rather than the directory it's merged into.
+	if _, err := os.Stat(filepath.Join(srcDir, appRootRelpath, "go.mod")); err == nil {
 		lang = cmdutil.LanguageGo
-		s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
-		s.Prefix = "Running go get encore.dev@latest"
-		s.Start()
-		if err := gogetEncore(filepath.Join(name, appRootRelpath)); err != nil {
-			s.FinalMSG = fmt.Sprintf("failed, skipping: %v", err.Error())
-		}
-		s.Stop()
-	} else if _, err := os.Stat(filepath.Join(name, appRootRelpath, "package.json")); err == nil {
+		if !createAppOffline && !journal.done(journalStepDeps) {
+			s := spinner.New(cmdutil.SpinnerCharSet(), 100*time.Millisecond)
+			s.Prefix = "Running go get encore.dev@latest"
+			s.Start()
+			if err := gogetEncore(filepath.Join(dir, appRootRelpath)); err != nil {
+				s.FinalMSG = fmt.Sprintf("failed, skipping: %v", err.Error())
+			}
+			s.Stop()
+		}
+	} else if _, err := os.Stat(filepath.Join(srcDir, appRootRelpath, "package.json")); err == nil {
 		lang = cmdutil.LanguageTS
-		s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
-		s.Prefix = "Running npm install encore.dev@latest"
-		s.Start()
-		if err := npmInstallEncore(filepath.Join(name, appRootRelpath)); err != nil {
-			s.FinalMSG = fmt.Sprintf("failed, skipping: %v", err.Error())
+		if !createAppOffline && !journal.done(journalStepDeps) {
+			s := spinner.New(cmdutil.SpinnerCharSet(), 100*time.Millisecond)
+			s.Prefix = "Running npm install encore.dev@latest"
+			s.Start()
+			if err := npmInstallEncore(filepath.Join(dir, appRootRelpath)); err != nil {
+				s.FinalMSG = fmt.Sprintf("failed, skipping: %v", err.Error())
+			}
+			s.Stop()
 		}
-		s.Stop()
 	}
 
-	// Rewrite any existence of ENCORE_APP_ID to the allocated app id.
-	if app != nil {
-		if err := rewritePlaceholders(name, app); err != nil {
-			red := color.New(color.FgRed)
-			_, _ = red.Printf("Failed rewriting source code placeholders, skipping: %v\n", err)
+	journal.complete(journalStepDeps)
+
+	if createAppGit && createAppInPlace && insideGitRepo(dir) {
+		fmt.Println("Note: the app directory is inside a git repository, skipping git init.")
+	} else if createAppGit {
+		if err := initGitRepo(dir, app); err != nil {
+			return nil, err
 		}
 	}
+	journal.remove()
 
-	if err := initGitRepo(name, app); err != nil {
-		return err
+	// Offer to push the app to a new repository, if it has one of its own.
+	var repo *remoteRepo
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil && createAppGit && !createAppInPlace && !createAppOffline {
+		if provider := selectedGitProvider(); provider != "" {
+			var err error
+			if repo, err = setupRemoteRepo(dir, name, provider); err != nil {
+				red := color.New(color.FgRed)
+				_, _ = red.Printf("Failed setting up the %s repository, skipping: %v\n", provider.Display(), err)
+			}
+		}
 	}
 
 	// Try to generate wrappers. Don't error out if it fails for some reason,
 	// it's a nice-to-have to avoid IDEs thinking there are compile errors before 'encore run' runs.
-	_ = generateWrappers(filepath.Join(name, appRootRelpath))
+	_ = generateWrappers(filepath.Join(dir, appRootRelpath))
 
 	// Create the app on the daemon.
-	appRoot, err := filepath.Abs(filepath.Join(name, appRootRelpath))
+	appRoot, err := filepath.Abs(filepath.Join(dir, appRootRelpath))
 	if err != nil {
 		cmdutil.Fatalf("failed to get absolute path: %v", err)
 	}
@@ -331,7 +786,7 @@ func createApp(ctx context.Context, name, template string, lang cmdutil.Language
 		color.Red("Failed to create app on daemon: %s\n", err)
 	}
 
-	if err := llm_rules.SetupLLMRules(llmRules, lang, filepath.Join(name, appRootRelpath), appResp.AppId); err != nil {
+	if err := llm_rules.SetupLLMRules(llmRules, lang, filepath.Join(dir, appRootRelpath), appResp.AppId); err != nil {
 		color.Red("Failed to setup LLM rules: %s\n", err)
 	}
 
@@ -344,14 +799,44 @@ func createApp(ctx context.Context, name, template string, lang cmdutil.Language
 		fmt.Printf("Web URL:  %s%s", cyanf("https://app.encore.cloud/"+app.Slug), cmdutil.Newline)
 	}
 	fmt.Printf("App Root: %s\n", cyanf(appRoot))
+	if repo != nil {
+		fmt.Printf("Repo:     %s\n", cyanf(repo.WebURL))
+	}
+	if summary, err := summarizeScaffold(appRoot); err == nil {
+		fmt.Println()
+		fmt.Print(summary)
+		fmt.Println()
+	}
 	llm_rules.PrintLLMRulesInfo(llmRules)
 	greenBoldF := green.Add(color.Bold).SprintfFunc()
-	fmt.Printf("Run your app with: %s\n", greenBoldF("cd %s && encore run", filepath.Join(name, appRootRelpath)))
+	fmt.Printf("Run your app with: %s\n", greenBoldF("cd %s && encore run", filepath.Join(dir, appRootRelpath)))
+	if template != "" && isTemplateName(template) {
+		printPostCreate(template)
+	}
+
+	return &createdApp{
+		Name:    name,
+		Lang:    lang,
+		AppRoot: appRoot,
+		RunDir:  filepath.Join(dir, appRootRelpath),
+		Linked:  app != nil,
+	}, nil
+}
+
+// nextSteps offers to open the newly created app in an editor and run it,
+// and otherwise prints some useful commands to get started.
+func (c *createdApp) nextSteps(ctx context.Context) error {
+	cyan := color.New(color.FgCyan)
+	green := color.New(color.FgGreen)
+	greenBoldF := green.Add(color.Bold).SprintfFunc()
+
 	fmt.Println()
+	c.maybeOpenEditor()
 	if promptRunApp() {
 		cmdutil.ClearTerminalExceptFirstNLines(0)
+		daemon := cmdutil.ConnectDaemon(ctx)
 		stream, err := daemon.Run(ctx, &daemonpb.RunRequest{
-			AppRoot:    appRoot,
+			AppRoot:    c.AppRoot,
 			Watch:      true,
 			WorkingDir: ".",
 			Environ:    os.Environ(),
@@ -371,19 +856,19 @@ func createApp(ctx context.Context, name, template string, lang cmdutil.Language
 	_, _ = cyan.Printf("    encore run\n")
 	fmt.Print("        Run your app locally\n\n")
 
-	if lang == cmdutil.LanguageGo {
+	if c.Lang == cmdutil.LanguageGo {
 		_, _ = cyan.Printf("    encore test ./...\n")
 	} else {
 		_, _ = cyan.Printf("    encore test\n")
 	}
 	fmt.Print("        Run tests\n\n")
 
-	if app != nil {
+	if c.Linked {
 		_, _ = cyan.Printf("    git push encore\n")
 		fmt.Print("        Deploys your app\n\n")
 	}
 
-	fmt.Printf("Get started now: %s\n", greenBoldF("cd %s && encore run", filepath.Join(name, appRootRelpath)))
+	fmt.Printf("Get started now: %s\n", greenBoldF("cd %s && encore run", c.RunDir))
 	return nil
 }
 
@@ -398,6 +883,21 @@ func detectLang(appRoot string) cmdutil.Language {
 	return cmdutil.LanguageGo
 }
 
+// detectProjectLang detects the language of the project in dir from its
+// go.mod or package.json file, which it also returns. It reports false
+// if dir contains both or neither, since the language is then ambiguous.
+func detectProjectLang(dir string) (lang cmdutil.Language, file string, ok bool) {
+	_, goErr := os.Stat(filepath.Join(dir, "go.mod"))
+	_, tsErr := os.Stat(filepath.Join(dir, "package.json"))
+	switch {
+	case goErr == nil && tsErr != nil:
+		return cmdutil.LanguageGo, "go.mod", true
+	case tsErr == nil && goErr != nil:
+		return cmdutil.LanguageTS, "package.json", true
+	}
+	return "", "", false
+}
+
 func validateName(name string) error {
 	ln := len(name)
 	if ln == 0 {
@@ -423,6 +923,40 @@ func validateName(name string) error {
 	return nil
 }
 
+// nodeBuiltinModules are the Node.js core modules,
+// which npm doesn't allow as package names.
+var nodeBuiltinModules = map[string]bool{
+	"assert": true, "buffer": true, "cluster": true, "console": true, "constants": true,
+	"crypto": true, "dgram": true, "dns": true, "domain": true, "events": true,
+	"fs": true, "http": true, "http2": true, "https": true, "inspector": true,
+	"module": true, "net": true, "os": true, "path": true, "process": true,
+	"punycode": true, "querystring": true, "readline": true, "repl": true, "stream": true,
+	"sys": true, "test": true, "timers": true, "tls": true, "tty": true,
+	"url": true, "util": true, "v8": true, "vm": true, "wasi": true, "zlib": true,
+}
+
+// validateNameForLang is like validateName but also checks that the name
+// is usable as a Go module path or npm package name, depending on the language.
+func validateNameForLang(name string, lang cmdutil.Language) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	switch lang {
+	case cmdutil.LanguageGo:
+		if token.IsKeyword(name) {
+			return fmt.Errorf("name cannot be a Go keyword")
+		} else if err := module.CheckImportPath(name); err != nil {
+			return fmt.Errorf("name is not a valid Go module path")
+		}
+	case cmdutil.LanguageTS:
+		if nodeBuiltinModules[name] {
+			return fmt.Errorf("name cannot be a Node.js core module name")
+		}
+	}
+	return nil
+}
+
 func gogetEncore(dir string) error {
 	var goBinPath string
 
@@ -482,6 +1016,7 @@ func createAppOnServer(name string, cfg exampleConfig) (*platform.App, error) {
 		Name:           name,
 		InitialSecrets: cfg.InitialSecrets,
 		AppRootDir:     cfg.EncoreAppPath,
+		Org:            createAppOrg,
 	}
 	return platform.CreateApp(ctx, params)
 }
@@ -489,16 +1024,31 @@ func createAppOnServer(name string, cfg exampleConfig) (*platform.App, error) {
 func parseTemplate(ctx context.Context, tmpl string) (*github.Tree, error) {
 	// If the template does not contain a colon or a dot, it's definitely
 	// not a github.com URL. Assume it's a simple template name.
-	if !strings.Contains(tmpl, ":") && !strings.Contains(tmpl, ".") {
+	if isTemplateName(tmpl) {
 		tmpl = "https://github.com/encoredev/examples/tree/main/" + tmpl
 	}
 	return github.ParseTree(ctx, tmpl)
 }
 
-// initGitRepo initializes the git repo.
+// initGitRepo initializes the git repo and creates an initial commit,
+// respecting any .gitignore file provided by the template.
 // If app is not nil, it configures the repo to push to the given app.
-// If git does not exist, it reports an error matching exec.ErrNotFound.
+//
+// If path is already a git repository, or git is not installed,
+// it prints a note and does nothing.
 func initGitRepo(path string, app *platform.App) (err error) {
+	if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+		fmt.Println("Note: the app directory is already a git repository, skipping git init.")
+		if app != nil {
+			addEncoreRemote(path, app.Slug)
+		}
+		return nil
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		fmt.Println("Note: git is not installed, skipping git init.")
+		return nil
+	}
+
 	defer func() {
 		if e := recover(); e != nil {
 			if ee, ok := e.(error); ok {
@@ -583,7 +1133,8 @@ func gitUserConfigured() (bool, error) {
 
 // rewritePlaceholders recursively rewrites all files within basePath
 // to replace placeholders with the actual values for this particular app.
-func rewritePlaceholders(basePath string, app *platform.App) error {
+// The placeholders are given as pairs of placeholder and value.
+func rewritePlaceholders(basePath string, placeholders []string) error {
 	var first error
 	err := filepath.WalkDir(basePath, func(path string, info fs.DirEntry, err error) error {
 		if err != nil {
@@ -592,7 +1143,7 @@ func rewritePlaceholders(basePath string, app *platform.App) error {
 		if !info.Type().IsRegular() {
 			return nil
 		}
-		if err := rewritePlaceholder(path, info, app); err != nil {
+		if err := rewritePlaceholder(path, info, placeholders); err != nil {
 			if first == nil {
 				first = err
 			}
@@ -608,14 +1159,11 @@ func rewritePlaceholders(basePath string, app *platform.App) error {
 // rewritePlaceholder rewrites a file to replace placeholders with the
 // actual values for this particular app. If the file contains none of
 // the placeholders, this is a no-op.
-func rewritePlaceholder(path string, info fs.DirEntry, app *platform.App) error {
+func rewritePlaceholder(path string, info fs.DirEntry, placeholders []string) error {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return err
 	}
-	placeholders := []string{
-		"{{ENCORE_APP_ID}}", app.Slug,
-	}
 
 	var replaced bool
 	for i := 0; i < len(placeholders); i += 2 {
@@ -653,7 +1201,14 @@ func parseExampleConfig(repoPath string) (cfg exampleConfig, err error) {
 	} else if err != nil {
 		return baseConfig, err
 	}
+	return decodeExampleConfig(data)
+}
 
+// decodeExampleConfig decodes the contents of an example config file.
+func decodeExampleConfig(data []byte) (cfg exampleConfig, err error) {
+	baseConfig := exampleConfig{
+		EncoreAppPath: ".",
+	}
 	data, err = hujson.Standardize(data)
 	if err != nil {
 		return baseConfig, err
@@ -670,8 +1225,11 @@ func parseExampleConfig(repoPath string) (cfg exampleConfig, err error) {
 	return cfg, nil
 }
 
+// exampleJSONName is the name of the example config file of a template.
+const exampleJSONName = "example-initial-setup.json"
+
 func exampleJSONPath(repoPath string) string {
-	return filepath.Join(repoPath, "example-initial-setup.json")
+	return filepath.Join(repoPath, exampleJSONName)
 }
 
 // setEncoreAppID rewrites the encore.app file to replace the app id, preserving comments.
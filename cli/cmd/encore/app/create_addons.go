@@ -0,0 +1,329 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"encr.dev/cli/cmd/encore/cmdutil"
+)
+
+// Addon is a piece of infrastructure that can be added to a new app,
+// by scaffolding its resource declaration in the addonService service.
+type Addon string
+
+const (
+	AddonSQLDB   Addon = "sqldb"
+	AddonPubSub  Addon = "pubsub"
+	AddonCron    Addon = "cron"
+	AddonObjects Addon = "objects"
+	AddonSecrets Addon = "secrets"
+)
+
+// allAddons are the add-ons in the order they're listed.
+var allAddons = []Addon{AddonSQLDB, AddonPubSub, AddonCron, AddonObjects, AddonSecrets}
+
+func (a Addon) Display() string {
+	switch a {
+	case AddonSQLDB:
+		return "PostgreSQL database"
+	case AddonPubSub:
+		return "Pub/Sub topic"
+	case AddonCron:
+		return "Cron job"
+	case AddonObjects:
+		return "Object storage bucket"
+	case AddonSecrets:
+		return "Secrets"
+	default:
+		return string(a)
+	}
+}
+
+// addonFlagValues are the values accepted by --addons.
+func addonFlagValues() []string {
+	values := make([]string, 0, len(allAddons)+1)
+	for _, a := range allAddons {
+		values = append(values, string(a))
+	}
+	return append(values, "none")
+}
+
+// parseAddons parses the values given to --addons.
+// "none" selects no add-ons, and is returned as an empty, non-nil slice.
+func parseAddons(values []string) ([]Addon, error) {
+	addons := []Addon{}
+	for _, v := range values {
+		if v == "none" {
+			continue
+		} else if !slices.Contains(allAddons, Addon(v)) {
+			return nil, fmt.Errorf("unknown add-on %q, must be one of: %s", v, strings.Join(addonFlagValues(), ", "))
+		}
+		if !slices.Contains(addons, Addon(v)) {
+			addons = append(addons, Addon(v))
+		}
+	}
+	return addons, nil
+}
+
+func displayAddons(addons []Addon) string {
+	if len(addons) == 0 {
+		return "None"
+	}
+	names := make([]string, len(addons))
+	for i, a := range addons {
+		names[i] = a.Display()
+	}
+	return strings.Join(names, ", ")
+}
+
+type addonsDone struct{}
+
+// addonsModel is the form step for selecting any number of add-ons.
+type addonsModel struct {
+	selected []bool // indexed like allAddons
+	idx      int    // the highlighted add-on
+}
+
+func newAddonsModel() addonsModel {
+	return addonsModel{selected: make([]bool, len(allAddons))}
+}
+
+// Selected returns the selected add-ons.
+func (m addonsModel) Selected() []Addon {
+	var addons []Addon
+	for i, sel := range m.selected {
+		if sel {
+			addons = append(addons, allAddons[i])
+		}
+	}
+	return addons
+}
+
+func (m addonsModel) Update(msg tea.Msg) (addonsModel, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch key.String() {
+	case "up", "k":
+		m.idx = max(m.idx-1, 0)
+	case "down", "j":
+		m.idx = min(m.idx+1, len(allAddons)-1)
+	case " ", "x":
+		m.selected[m.idx] = !m.selected[m.idx]
+	case "enter":
+		return m, func() tea.Msg { return addonsDone{} }
+	}
+	return m, nil
+}
+
+func (m addonsModel) View() string {
+	var b strings.Builder
+	b.WriteString(cmdutil.InputStyle.Render("Infrastructure"))
+	b.WriteString(cmdutil.DescStyle.Render(" [Use arrows to move, space to select, enter to continue]"))
+	b.WriteByte('\n')
+	for i, a := range allAddons {
+		box := "[ ]"
+		if m.selected[i] {
+			box = "[x]"
+		}
+		line := fmt.Sprintf("%s %s", box, a.Display())
+		if i == m.idx {
+			b.WriteString(cmdutil.InputStyle.Render("> " + line))
+		} else {
+			b.WriteString("  " + line)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// addonService is the name of the service the add-ons are scaffolded in.
+const addonService = "infra"
+
+// scaffoldAddons adds the resource declarations of the given add-ons to the
+// app at appRoot, in a new service named addonService.
+func scaffoldAddons(appRoot string, lang cmdutil.Language, addons []Addon) error {
+	if len(addons) == 0 {
+		return nil
+	}
+	dir := filepath.Join(appRoot, addonService)
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("directory %s already exists", addonService)
+	}
+
+	for _, f := range addonFilesFor(lang, addons) {
+		path := filepath.Join(dir, filepath.FromSlash(f.name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(f.data), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addonFilesFor returns the files of the addonService service
+// that declares the given add-ons in lang.
+func addonFilesFor(lang cmdutil.Language, addons []Addon) []addonFile {
+	if len(addons) == 0 {
+		return nil
+	}
+	files := goAddonFiles
+	if lang == cmdutil.LanguageTS {
+		files = tsAddonFiles
+	}
+	result := []addonFile{files.service}
+	for _, a := range addons {
+		result = append(result, files.addons[a]...)
+	}
+	return result
+}
+
+type addonFile struct {
+	name string // slash-separated path within the service
+	data string
+}
+
+type addonFiles struct {
+	service addonFile // declares the service
+	addons  map[Addon][]addonFile
+}
+
+const addonMigration = `CREATE TABLE items (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+`
+
+var goAddonFiles = addonFiles{
+	service: addonFile{"infra.go", `// Service infra declares the app's infrastructure resources.
+package infra
+
+//encore:service
+type Service struct{}
+`},
+	addons: map[Addon][]addonFile{
+		AddonSQLDB: {
+			{"db.go", `package infra
+
+import "encore.dev/storage/sqldb"
+
+// db is the app's PostgreSQL database.
+var db = sqldb.NewDatabase("db", sqldb.DatabaseConfig{
+	Migrations: "./migrations",
+})
+`},
+			{"migrations/1_create_items.up.sql", addonMigration},
+		},
+		AddonPubSub: {{"pubsub.go", `package infra
+
+import "encore.dev/pubsub"
+
+// Event is the message published to Events.
+type Event struct {
+	Message string
+}
+
+// Events is a Pub/Sub topic of events.
+var Events = pubsub.NewTopic[*Event]("events", pubsub.TopicConfig{
+	DeliveryGuarantee: pubsub.AtLeastOnce,
+})
+`}},
+		AddonCron: {{"cron.go", `package infra
+
+import (
+	"context"
+
+	"encore.dev/cron"
+)
+
+// Cleanup is run every hour by the cleanup cron job.
+//
+//encore:api private
+func Cleanup(ctx context.Context) error {
+	return nil
+}
+
+var _ = cron.NewJob("cleanup", cron.JobConfig{
+	Title:    "Clean up",
+	Every:    cron.Hour,
+	Endpoint: Cleanup,
+})
+`}},
+		AddonObjects: {{"bucket.go", `package infra
+
+import "encore.dev/storage/objects"
+
+// Files is an object storage bucket for files.
+var Files = objects.NewBucket("files", objects.BucketConfig{})
+`}},
+		AddonSecrets: {{"secrets.go", `package infra
+
+// secrets are set with 'encore secret set --type dev,local APIKey'.
+var secrets struct {
+	APIKey string
+}
+`}},
+	},
+}
+
+var tsAddonFiles = addonFiles{
+	service: addonFile{"encore.service.ts", `import { Service } from "encore.dev/service";
+
+// The infra service declares the app's infrastructure resources.
+export default new Service("infra");
+`},
+	addons: map[Addon][]addonFile{
+		AddonSQLDB: {
+			{"db.ts", `import { SQLDatabase } from "encore.dev/storage/sqldb";
+
+// db is the app's PostgreSQL database.
+export const db = new SQLDatabase("db", {
+  migrations: "./migrations",
+});
+`},
+			{"migrations/1_create_items.up.sql", addonMigration},
+		},
+		AddonPubSub: {{"pubsub.ts", `import { Topic } from "encore.dev/pubsub";
+
+// Event is the message published to events.
+export interface Event {
+  message: string;
+}
+
+// events is a Pub/Sub topic of events.
+export const events = new Topic<Event>("events", {
+  deliveryGuarantee: "at-least-once",
+});
+`}},
+		AddonCron: {{"cron.ts", `import { api } from "encore.dev/api";
+import { CronJob } from "encore.dev/cron";
+
+// cleanup is run every hour by the cleanup cron job.
+export const cleanup = api({}, async (): Promise<void> => {});
+
+const _ = new CronJob("cleanup", {
+  title: "Clean up",
+  every: "1h",
+  endpoint: cleanup,
+});
+`}},
+		AddonObjects: {{"bucket.ts", `import { Bucket } from "encore.dev/storage/objects";
+
+// files is an object storage bucket for files.
+export const files = new Bucket("files", {});
+`}},
+		AddonSecrets: {{"secrets.ts", `import { secret } from "encore.dev/config";
+
+// apiKey is set with 'encore secret set --type dev,local APIKey'.
+export const apiKey = secret("APIKey");
+`}},
+	},
+}
@@ -3,25 +3,31 @@ package app
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/fatih/color"
+	"github.com/sahilm/fuzzy"
 	"github.com/tailscale/hujson"
 	"golang.org/x/term"
 
 	"encr.dev/cli/cmd/encore/cmdutil"
 	"encr.dev/cli/cmd/encore/llm_rules"
+	"encr.dev/pkg/github"
 	"encr.dev/pkg/option"
 )
 
@@ -30,9 +36,51 @@ type templateItem struct {
 	Desc      string           `json:"desc"`
 	Template  string           `json:"template"`
 	Lang      cmdutil.Language `json:"lang"`
+
+	// LangName and LangDesc optionally describe the template's language,
+	// so that languages the CLI doesn't know about are listed with a proper name.
+	LangName string `json:"langName,omitempty"`
+	LangDesc string `json:"langDesc,omitempty"`
+
+	// Advanced templates are hidden unless requested.
+	Advanced bool `json:"advanced,omitempty"`
+
+	// Order controls where the template is listed for its language.
+	// Templates with an order are listed first, lowest first and then by title.
+	// Templates without an order keep their position in the manifest.
+	Order int `json:"order,omitempty"`
+
+	// Vars are the variables to prompt for before scaffolding the template.
+	Vars []templateVar `json:"vars,omitempty"`
+
+	// Files optionally lists the top-level files and directories
+	// of the template, with directories suffixed by "/".
+	// If empty they're fetched from GitHub when needed.
+	Files []string `json:"files,omitempty"`
+
+	// Infra lists the infrastructure the template uses,
+	// such as "SQL", "Pub/Sub" or "Secrets".
+	Infra []string `json:"infra,omitempty"`
+
+	// SetupTime is the estimated time to set up the template, such as "5 min".
+	SetupTime string `json:"setupTime,omitempty"`
+
+	// PostCreate are optional instructions to show after the app has been created,
+	// such as environment variables to set. It's markdown or plain text, or an
+	// http(s) URL to fetch the instructions from.
+	PostCreate string `json:"postCreate,omitempty"`
+
+	// showLang, if set, shows the language as a badge in the title.
+	// It's set when searching templates across all languages.
+	showLang bool
 }
 
-func (i templateItem) Title() string       { return i.ItemTitle }
+func (i templateItem) Title() string {
+	if i.showLang {
+		return fmt.Sprintf("[%s] %s", i.Lang.Display(), i.ItemTitle)
+	}
+	return i.ItemTitle
+}
 func (i templateItem) Description() string { return i.Desc }
 func (i templateItem) FilterValue() string { return i.ItemTitle }
 
@@ -43,18 +91,43 @@ const (
 	CreateStepTemplate
 	CreateStepAppName
 	CreateStepLLMRules
+	CreateStepTemplateVars
+	CreateStepConfirmDir
+	CreateStepAddons
 )
 
 type createFormModel struct {
 	steps []CreateStep
 
+	// done are the steps completed so far, in order,
+	// which are returned to when going back.
+	done []CreateStep
+
 	lang      langSelectModel
 	templates templateListModel
 	appName   appNameModel
 	llmRules  llm_rules.ToolSelectModel
+	vars      templateVarsModel
+	addons    addonsModel
+
+	// predefinedAddons are the add-ons given as an option, if any,
+	// in which case they're not prompted for. See CreateFormOptions.Addons.
+	predefinedAddons []Addon
+
+	// confirmDir is set when creating the app in place
+	// in a directory that isn't empty, to confirm doing so.
+	confirmDir option.Option[confirmDirModel]
 
 	initExistingApp bool
 
+	// langDetectedFrom is the file the language was detected from, if any.
+	langDetectedFrom string
+
+	// sessionFile, if set, is where the progress through the form is saved.
+	// resumeVars are the template variables entered in a resumed session.
+	sessionFile string
+	resumeVars  map[string]string
+
 	width   int
 	height  int
 	aborted bool
@@ -77,6 +150,56 @@ func (m *createFormModel) removeStep(s CreateStep) {
 	})
 }
 
+// addStep adds the step s to be prompted for once everything else is done,
+// but before confirming the app's directory.
+func (m *createFormModel) addStep(s CreateStep) {
+	if i := slices.Index(m.steps, CreateStepConfirmDir); i >= 0 {
+		m.steps = slices.Insert(m.steps, i, s)
+		return
+	}
+	m.steps = append(m.steps, s)
+}
+
+// completeStep removes the step s and records it as done,
+// so that it can be returned to.
+func (m *createFormModel) completeStep(s CreateStep) {
+	if m.hasStep(s) {
+		m.removeStep(s)
+		m.done = append(m.done, s)
+	}
+}
+
+// back goes back to the previous template variable, or the previously completed step.
+// It reports false if there's nothing to go back to.
+func (m *createFormModel) back() (tea.Cmd, bool) {
+	if step, ok := m.currentStep().Get(); ok && step == CreateStepTemplateVars && m.vars.idx > 0 {
+		return m.vars.back(), true
+	}
+	if len(m.done) == 0 {
+		return nil, false
+	}
+	prev := m.done[len(m.done)-1]
+	m.done = m.done[:len(m.done)-1]
+	m.steps = append([]CreateStep{prev}, m.steps...)
+
+	var cmd tea.Cmd
+	switch prev {
+	case CreateStepTemplate:
+		// The variables depend on the template, so they're prompted for
+		// again once one is selected.
+		m.removeStep(CreateStepTemplateVars)
+		m.vars = templateVarsModel{}
+		if !m.templates.searchAll {
+			cmd = m.templates.UpdateFilter(m.lang.Selected())
+		}
+	case CreateStepAppName:
+		cmd = m.appName.text.Focus()
+	}
+	m.SetSize(m.width, m.height)
+	m.saveSession()
+	return cmd, true
+}
+
 func (m createFormModel) Init() tea.Cmd {
 	return tea.Batch(
 		m.appName.Init(),
@@ -84,14 +207,31 @@ func (m createFormModel) Init() tea.Cmd {
 	)
 }
 
-const checkmark = "✔"
+var checkmark = cmdutil.Symbol("✔", "[x]")
 
 type appNameDone struct{}
 
 type appNameModel struct {
 	predefined string
 	text       textinput.Model
-	dirExists  bool
+	dirInUse   bool // whether the app's directory exists and isn't empty
+
+	lang      cmdutil.Language // the selected language, if known
+	err       error            // set if the submitted name is invalid
+	parentDir string           // the directory to create the app in, if not the working directory
+	path      string           // the directory to create the app in, if not named after the app
+	inPlace   bool             // whether the app may be created in a directory that isn't empty
+}
+
+// dir reports the directory the app with the given name is created in.
+func (m appNameModel) dir(name string) string {
+	return appDir(m.parentDir, m.path, name)
+}
+
+// customDir reports whether the app isn't created in
+// a directory named after it in the working directory.
+func (m appNameModel) customDir() bool {
+	return m.parentDir != "" || m.path != ""
 }
 
 func (m appNameModel) Init() tea.Cmd {
@@ -113,20 +253,24 @@ func (m appNameModel) Update(msg tea.Msg) (appNameModel, tea.Cmd) {
 	m.text, c = m.text.Update(msg)
 	cmds = append(cmds, c)
 
-	if val := m.text.Value(); val != "" {
-		_, err := os.Stat(val)
-		m.dirExists = err == nil
+	if val := m.text.Value(); val != "" && !m.inPlace {
+		m.dirInUse = dirInUse(m.dir(val))
 	}
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.Type {
 		case tea.KeyEnter:
-			if m.text.Value() != "" && !m.dirExists {
-				cmds = append(cmds, func() tea.Msg {
-					return appNameDone{}
-				})
+			if m.text.Value() != "" && !m.dirInUse {
+				m.err = validateNameForLang(m.text.Value(), m.lang)
+				if m.err == nil {
+					cmds = append(cmds, func() tea.Msg {
+						return appNameDone{}
+					})
+				}
 			}
+		default:
+			m.err = nil
 		}
 	}
 
@@ -138,10 +282,15 @@ func (m appNameModel) View() string {
 	if m.text.Focused() {
 		b.WriteString(cmdutil.InputStyle.Render("App Name"))
 		b.WriteString(cmdutil.DescStyle.Render(" [Use only lowercase letters, digits, and dashes]"))
+		if m.customDir() {
+			b.WriteString(cmdutil.DescStyle.Render(fmt.Sprintf(" (created in %s)", m.dir(m.text.Value()))))
+		}
 		b.WriteByte('\n')
 		b.WriteString(m.text.View())
-		if m.dirExists {
-			b.WriteString(cmdutil.ErrorStyle.Render(" error: dir already exists"))
+		if m.dirInUse {
+			b.WriteString(cmdutil.ErrorStyle.Render(" error: dir already exists and is not empty"))
+		} else if m.err != nil {
+			b.WriteString(cmdutil.ErrorStyle.Render(" error: " + m.err.Error()))
 		}
 	} else {
 		fmt.Fprintf(&b, "%s App Name: %s", checkmark, m.text.Value())
@@ -157,22 +306,202 @@ type templateListModel struct {
 	all     []templateItem
 	list    list.Model
 	loading spinner.Model
+
+	notice string // transient notice shown below the list, if any
+
+	// useDefault, if true, selects the default template for the
+	// chosen language without prompting, if it's available.
+	useDefault bool
+	note       string // note shown above the list, if any
+
+	showAdvanced bool // whether to show advanced templates
+
+	// resume, if set, is the template of a resumed session,
+	// which is selected without prompting once the templates are loaded.
+	resume option.Option[string]
+
+	// searchAll, if set, searches the templates of all languages
+	// for the query rather than listing those of the filtered language.
+	searchAll bool
+	query     textinput.Model
+
+	// filtering, if set, narrows the templates of the filtered language
+	// to those matching the query, as typed after pressing '/'.
+	filtering bool
+
+	// custom are the templates of custom template sources,
+	// listed before Encore's templates.
+	custom []templateSource
+
+	// files caches the fetched top-level files of templates, keyed by slug.
+	files map[string]*templateFiles
+
+	// width is the width of the step, and sidePreview whether
+	// it's wide enough to show the preview next to the list.
+	width       int
+	sidePreview bool
+
+	// conflicts are the duplicate template slugs found
+	// when merging the template manifests.
+	conflicts []slugConflict
+
+	// stale is a warning shown if the templates may be out of date,
+	// as when they couldn't be fetched.
+	stale string
+}
+
+// templateFiles are the top-level files of a template, as shown in the preview.
+type templateFiles struct {
+	loading bool
+	names   []string // nil if not available
+	readme  string   // excerpt of the README, if any
+}
+
+// maxPreviewFiles is the maximum number of lines in the file tree preview.
+const maxPreviewFiles = 6
+
+// templatePreviewHeight is the height reserved for the template preview,
+// including the separating line and the description.
+const templatePreviewHeight = maxPreviewFiles + 2
+
+// minSidePreviewWidth is the minimum width to show the template preview
+// next to the list, rather than below it. The side preview also shows
+// an excerpt of the template's README and what it requires.
+const minSidePreviewWidth = 100
+
+// maxReadmeLines is the maximum number of lines of the README excerpt.
+const maxReadmeLines = 8
+
+// defaultTemplateSlugs are the canonical templates for each language,
+// used when --default-template is set.
+var defaultTemplateSlugs = map[cmdutil.Language]string{
+	cmdutil.LanguageGo: "hello-world",
+	cmdutil.LanguageTS: "ts/hello-world",
 }
 
 func (m templateListModel) Init() tea.Cmd {
 	return tea.Batch(
-		loadTemplates,
+		loadTemplates(m.custom),
 		m.loading.Tick,
 	)
 }
 
 func (m *templateListModel) SetSize(width, height int) {
+	m.width = width
+	m.sidePreview = width >= minSidePreviewWidth
+	if m.sidePreview {
+		// Leave room for the header, the status line and the notice line.
+		m.list.SetWidth(width / 2)
+		m.list.SetHeight(max(height-3, 0))
+		return
+	}
 	m.list.SetWidth(width)
-	m.list.SetHeight(max(height-1, 0))
+	// Leave room for the header, the status line, the preview and the notice line.
+	m.list.SetHeight(max(height-3-templatePreviewHeight, 0))
 }
 
 type templateSelectDone struct{}
 
+// slugCopied is sent when copying a template slug to the clipboard has completed.
+type slugCopied struct {
+	slug string
+	err  error
+}
+
+type clearNotice struct{}
+
+// templateFilesLoaded is sent when fetching the files of a template has completed.
+type templateFilesLoaded struct {
+	slug   string
+	names  []string // nil if not available
+	readme string   // excerpt of the README, if any
+}
+
+// fetchTemplateFiles fetches the top-level files of the given template,
+// and an excerpt of its README if it has one.
+func fetchTemplateFiles(slug string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		msg := templateFilesLoaded{slug: slug}
+		if createAppOffline {
+			return msg
+		}
+		tree, err := parseTemplate(ctx, slug)
+		if err != nil {
+			return msg
+		}
+		entries, err := github.ListTree(ctx, tree)
+		if err != nil {
+			return msg
+		}
+
+		// List directories first, like most file browsers.
+		slices.SortStableFunc(entries, func(a, b github.Entry) int {
+			switch {
+			case a.Dir == b.Dir:
+				return 0
+			case a.Dir:
+				return -1
+			default:
+				return 1
+			}
+		})
+		msg.names = []string{}
+		for _, e := range entries {
+			if e.Dir {
+				msg.names = append(msg.names, e.Name+"/")
+			} else {
+				msg.names = append(msg.names, e.Name)
+			}
+		}
+
+		// The README is only shown in the preview, so it's not an error if it can't be fetched.
+		if idx := slices.IndexFunc(entries, func(e github.Entry) bool {
+			return !e.Dir && strings.EqualFold(e.Name, "README.md")
+		}); idx >= 0 {
+			if data, err := github.ReadFile(ctx, tree, entries[idx].Name, 64*1024); err == nil {
+				msg.readme = readmeExcerpt(string(data))
+			}
+		}
+		return msg
+	}
+}
+
+// readmeExcerpt returns the first paragraph of the given README,
+// skipping headings, badges, images and HTML.
+func readmeExcerpt(readme string) string {
+	var lines []string
+	for _, line := range strings.Split(readme, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			if len(lines) > 0 {
+				return strings.Join(lines, " ")
+			}
+		case strings.HasPrefix(line, "#"), strings.HasPrefix(line, "!["), strings.HasPrefix(line, "[!["),
+			strings.HasPrefix(line, "<"):
+			if len(lines) > 0 {
+				return strings.Join(lines, " ")
+			}
+		default:
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, " ")
+}
+
+// copySlug copies the given template slug to the system clipboard.
+func copySlug(slug string) tea.Cmd {
+	return func() tea.Msg {
+		if clipboard.Unsupported {
+			return slugCopied{slug: slug, err: errors.New("clipboard unavailable")}
+		}
+		return slugCopied{slug: slug, err: clipboard.WriteAll(slug)}
+	}
+}
+
 func (m templateListModel) Update(msg tea.Msg) (templateListModel, tea.Cmd) {
 	var cmds []tea.Cmd
 	switch msg := msg.(type) {
@@ -183,51 +512,426 @@ func (m templateListModel) Update(msg tea.Msg) (templateListModel, tea.Cmd) {
 			if idx := m.list.Index(); idx >= 0 {
 				return m, func() tea.Msg { return templateSelectDone{} }
 			}
+		case tea.KeyTab:
+			return m, m.SetSearchAll(!m.searchAll)
+		case tea.KeyEsc:
+			if m.filtering {
+				return m, m.SetFiltering(false)
+			}
+		}
+
+		if m.typing() {
+			// Typing goes to the search query; the arrows still move in the list.
+			switch msg.Type {
+			case tea.KeyUp, tea.KeyDown, tea.KeyPgUp, tea.KeyPgDown:
+			default:
+				var c tea.Cmd
+				prev := m.query.Value()
+				m.query, c = m.query.Update(msg)
+				if m.query.Value() != prev {
+					m.refreshFilter()
+				}
+				return m, tea.Batch(c, m.fetchFiles())
+			}
+		}
+
+		switch msg.Type {
+		case tea.KeyRunes:
+			switch msg.String() {
+			case "y":
+				if sel, ok := m.SelectedItem(); ok {
+					slug := sel.Template
+					if slug == "" {
+						slug = "empty"
+					}
+					return m, copySlug(slug)
+				}
+			case "a":
+				m.showAdvanced = !m.showAdvanced
+				m.refreshFilter()
+				return m, m.fetchFiles()
+			case "/":
+				return m, m.SetFiltering(true)
+			}
+		}
+
+	case slugCopied:
+		if msg.err != nil {
+			m.notice = cmdutil.ErrorStyle.Render(fmt.Sprintf("could not copy to clipboard: %v", msg.err))
+		} else {
+			m.notice = cmdutil.SuccessStyle.Render(fmt.Sprintf("copied %q!", msg.slug))
 		}
+		return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg { return clearNotice{} })
+
+	case clearNotice:
+		m.notice = ""
+		return m, nil
 
 	case spinner.TickMsg:
-		m.loading, _ = m.loading.Update(msg)
+		var c tea.Cmd
+		m.loading, c = m.loading.Update(msg)
+		if m.fetchingFiles() {
+			// Keep the spinner going while we're fetching template files.
+			cmds = append(cmds, c)
+		}
+
+	case templateFilesLoaded:
+		m.files[msg.slug] = &templateFiles{names: msg.names, readme: msg.readme}
 
 	case loadedTemplates:
-		m.all = msg
+		m.all = msg.items
+		m.conflicts = msg.conflicts
+		m.stale = msg.stale
 		m.refreshFilter()
 		newList, c := m.list.Update(msg)
 		m.list = newList
-		cmds = append(cmds, c)
+		cmds = append(cmds, c, m.selectDefault())
 	}
 
 	newList, c := m.list.Update(msg)
 	m.list = newList
-	cmds = append(cmds, c)
+	cmds = append(cmds, c, m.fetchFiles())
 
 	return m, tea.Batch(cmds...)
 }
 
-func (m *templateListModel) UpdateFilter(lang cmdutil.Language) {
+// fetchFiles starts fetching the files of the highlighted template
+// for the preview, unless they're already known.
+func (m *templateListModel) fetchFiles() tea.Cmd {
+	sel, ok := m.SelectedItem()
+	if !ok || sel.Template == "" || m.files == nil {
+		return nil
+	} else if len(sel.Files) > 0 && !m.sidePreview {
+		// The files are known, and the README isn't shown.
+		return nil
+	} else if _, ok := m.files[sel.Template]; ok {
+		return nil
+	}
+
+	m.files[sel.Template] = &templateFiles{loading: true}
+	return tea.Batch(m.loading.Tick, fetchTemplateFiles(sel.Template))
+}
+
+func (m templateListModel) fetchingFiles() bool {
+	for _, f := range m.files {
+		if f.loading {
+			return true
+		}
+	}
+	return false
+}
+
+// SetSearchAll sets whether to search the templates of all languages.
+func (m *templateListModel) SetSearchAll(searchAll bool) tea.Cmd {
+	m.searchAll = searchAll
+	m.filtering = false
+	m.query.Reset()
+	m.refreshFilter()
+	if searchAll {
+		return tea.Batch(m.query.Focus(), m.fetchFiles())
+	}
+	m.query.Blur()
+	return m.fetchFiles()
+}
+
+// SetFiltering sets whether to filter the templates of the filtered language
+// by the query. Stopping filtering clears the query.
+func (m *templateListModel) SetFiltering(filtering bool) tea.Cmd {
+	m.filtering = filtering
+	m.query.Reset()
+	m.refreshFilter()
+	if filtering {
+		return tea.Batch(m.query.Focus(), m.fetchFiles())
+	}
+	m.query.Blur()
+	return m.fetchFiles()
+}
+
+// typing reports whether keys typed are entered into the query.
+func (m templateListModel) typing() bool {
+	return m.searchAll || m.filtering
+}
+
+// matchQuery reports whether the template matches the search query,
+// fuzzily matching its title, description, slug and infra tags,
+// and how well it matches. Better matches have higher scores.
+func (m templateListModel) matchQuery(it templateItem) (score int, ok bool) {
+	q := strings.TrimSpace(m.query.Value())
+	if q == "" {
+		return 0, true
+	}
+	fields := append([]string{it.ItemTitle, it.Desc, it.Template}, it.Infra...)
+	matches := fuzzy.Find(q, fields)
+	if len(matches) == 0 {
+		return 0, false
+	}
+	// The matches are sorted by score, best first.
+	score = matches[0].Score
+
+	// Rank the templates containing the query as typed above the fuzzy
+	// matches, and those with it in the title above the rest.
+	lower := strings.ToLower(q)
+	for i, f := range fields {
+		if strings.Contains(strings.ToLower(f), lower) {
+			if i == 0 {
+				score += 2000
+			} else {
+				score += 1000
+			}
+			break
+		}
+	}
+	return score, true
+}
+
+func (m *templateListModel) UpdateFilter(lang cmdutil.Language) tea.Cmd {
 	m.filter = lang
 	m.refreshFilter()
+	return m.selectDefault()
+}
+
+// selectDefault selects the template of a resumed session, or the default
+// template for the chosen language if useDefault is set, and completes the
+// template step. It does nothing until both the language and the templates are known.
+func (m *templateListModel) selectDefault() tea.Cmd {
+	if (!m.useDefault && !m.resume.Present()) || m.filter == "" || m.all == nil {
+		return nil
+	}
+	slug, resumed := m.resume.Get()
+	if !resumed {
+		slug = defaultTemplateSlugs[m.filter]
+	}
+	m.useDefault = false
+	m.resume = option.None[string]()
+
+	for i, it := range m.list.Items() {
+		if it.(templateItem).Template == slug {
+			m.list.Select(i)
+			return func() tea.Msg { return templateSelectDone{} }
+		}
+	}
+	if resumed {
+		m.note = "The template of the previous session is not available, please select one."
+	} else {
+		m.note = fmt.Sprintf("The default %s template is not available, please select one.", m.filter.Display())
+	}
+	return nil
 }
 
 func (m *templateListModel) refreshFilter() {
+	// Keep the highlighted template selected, if it's still shown.
+	sel, hasSel := m.SelectedItem()
+
 	var listItems []list.Item
+	scores := make(map[string]int)
 	for _, it := range m.all {
-		if it.Lang == m.filter {
-			listItems = append(listItems, it)
+		if it.Advanced && !m.showAdvanced || (!m.searchAll && it.Lang != m.filter) {
+			continue
 		}
+		score, ok := m.matchQuery(it)
+		if !ok {
+			continue
+		}
+		it.showLang = m.searchAll
+		scores[it.Template] = score
+		listItems = append(listItems, it)
+	}
+	sortTemplates(listItems)
+	if strings.TrimSpace(m.query.Value()) != "" {
+		// List the best matches first.
+		slices.SortStableFunc(listItems, func(a, b list.Item) int {
+			return scores[b.(templateItem).Template] - scores[a.(templateItem).Template]
+		})
 	}
 	m.list.SetItems(listItems)
+
+	if hasSel {
+		for i, it := range listItems {
+			if it.(templateItem).Template == sel.Template {
+				m.list.Select(i)
+				break
+			}
+		}
+	}
+}
+
+// sortTemplates sorts the templates by their order, keeping the
+// relative order of templates without one.
+func sortTemplates(items []list.Item) {
+	slices.SortStableFunc(items, func(a, b list.Item) int {
+		x, y := a.(templateItem), b.(templateItem)
+		switch {
+		case x.Order == 0 && y.Order == 0:
+			return 0
+		case x.Order == 0:
+			return 1
+		case y.Order == 0:
+			return -1
+		case x.Order != y.Order:
+			return x.Order - y.Order
+		default:
+			return strings.Compare(x.ItemTitle, y.ItemTitle)
+		}
+	})
 }
 
 func (m templateListModel) View() string {
 	var b strings.Builder
 	b.WriteString(cmdutil.InputStyle.Render("Template"))
-	b.WriteString(cmdutil.DescStyle.Render(" [Use arrows to move]"))
+	advanced := "show"
+	if m.showAdvanced {
+		advanced = "hide"
+	}
+	if m.searchAll {
+		b.WriteString(cmdutil.DescStyle.Render(" [Type to search all languages, tab to list by language]"))
+	} else if m.filtering {
+		b.WriteString(cmdutil.DescStyle.Render(fmt.Sprintf(" [Type to filter %s templates, esc to clear]", m.filter.Display())))
+	} else {
+		b.WriteString(cmdutil.DescStyle.Render(fmt.Sprintf(" [Use arrows to move, '/' to filter, 'a' to %s advanced, 'y' to copy slug, tab to search]", advanced)))
+	}
 	b.WriteByte('\n')
-	b.WriteString(m.list.View())
+	if m.note != "" {
+		b.WriteString(cmdutil.DescStyle.Render(m.note))
+		b.WriteByte('\n')
+	}
+	if status := m.statusView(); status != "" {
+		b.WriteString(status)
+		b.WriteByte('\n')
+	}
+	if m.sidePreview {
+		pane := lipgloss.NewStyle().
+			Width(max(m.width-m.list.Width()-3, 0)).
+			MaxHeight(max(m.list.Height(), 1)).
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			BorderForeground(cmdutil.ActiveTheme.Desc).
+			PaddingLeft(1)
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, m.list.View(), pane.Render(m.previewView())))
+	} else {
+		b.WriteString(m.list.View())
+		if preview := m.previewView(); preview != "" {
+			b.WriteString("\n\n")
+			b.WriteString(preview)
+		}
+	}
+	if m.notice != "" {
+		b.WriteByte('\n')
+		b.WriteString(m.notice)
+	}
 
 	return b.String()
 }
 
+// statusView renders how many templates match the language filter,
+// or guidance if there are none. It's empty until the templates are loaded.
+func (m templateListModel) statusView() string {
+	if m.all == nil {
+		return ""
+	}
+	if m.typing() {
+		return m.query.View() + cmdutil.DescStyle.Render(fmt.Sprintf("  %d matching", len(m.list.Items())))
+	}
+
+	hidden := 0
+	for _, it := range m.all {
+		if it.Lang == m.filter && it.Advanced && !m.showAdvanced {
+			hidden++
+		}
+	}
+
+	lang := m.filter.Display()
+	shown := len(m.list.Items())
+	if shown == 0 {
+		msg := fmt.Sprintf("No templates available for %s.", lang)
+		if hidden > 0 {
+			msg += " Press 'a' to show advanced templates."
+		} else {
+			msg += " Pick another language, or use --example to create an app from a URL."
+		}
+		return cmdutil.ErrorStyle.Render(msg)
+	}
+
+	status := fmt.Sprintf("%d %s template", shown, lang)
+	if shown != 1 {
+		status += "s"
+	}
+	if hidden > 0 {
+		status += fmt.Sprintf(" (%d advanced hidden)", hidden)
+	}
+	status = cmdutil.DescStyle.Render(status)
+	if m.stale != "" {
+		status += "  " + cmdutil.ErrorStyle.Render(m.stale)
+	}
+	return status
+}
+
+// previewView renders the description and the top-level files
+// of the highlighted template. If the files aren't available
+// only the description is rendered.
+//
+// When the preview is shown next to the list, it also renders
+// the infrastructure the template uses, its estimated setup time
+// and an excerpt of its README.
+func (m templateListModel) previewView() string {
+	sel, ok := m.SelectedItem()
+	if !ok {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(cmdutil.DescStyle.Render(sel.Desc))
+
+	f := m.files[sel.Template]
+	if m.sidePreview {
+		if len(sel.Infra) > 0 {
+			b.WriteString("\nInfrastructure: ")
+			b.WriteString(cmdutil.DescStyle.Render(strings.Join(sel.Infra, ", ")))
+		}
+		if sel.SetupTime != "" {
+			b.WriteString("\nSetup time: ")
+			b.WriteString(cmdutil.DescStyle.Render("~" + sel.SetupTime))
+		}
+		if f != nil && f.readme != "" {
+			b.WriteString("\n\n")
+			width := max(m.width-m.list.Width()-4, 1)
+			b.WriteString(lipgloss.NewStyle().Width(width).MaxHeight(maxReadmeLines).Render(f.readme))
+		}
+		b.WriteString("\n")
+	}
+
+	names := sel.Files
+	if len(names) == 0 {
+		if f != nil && f.loading {
+			b.WriteByte('\n')
+			if cmdutil.UnicodeSupported {
+				b.WriteString(m.loading.View())
+				b.WriteString(cmdutil.DescStyle.Render(" Loading files..."))
+			} else {
+				b.WriteString(cmdutil.DescStyle.Render("loading..."))
+			}
+			return b.String()
+		} else if f != nil {
+			names = f.names
+		}
+	}
+
+	for i, name := range names {
+		prefix := cmdutil.Symbol("├── ", "|-- ")
+		if i == maxPreviewFiles-1 && len(names) > maxPreviewFiles {
+			name = fmt.Sprintf("... and %d more", len(names)-i)
+			prefix = cmdutil.Symbol("└── ", "`-- ")
+		} else if i == len(names)-1 {
+			prefix = cmdutil.Symbol("└── ", "`-- ")
+		}
+		b.WriteByte('\n')
+		b.WriteString(cmdutil.DescStyle.Render(prefix))
+		b.WriteString(name)
+		if i == maxPreviewFiles-1 {
+			break
+		}
+	}
+	return b.String()
+}
+
 func (m templateListModel) Selected() string {
 	if m.predefined != "" {
 		return m.predefined
@@ -248,7 +952,18 @@ func (m createFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
-		case "ctrl+c", "esc":
+		case "esc":
+			// Esc clears the template filter, if any, or goes back a step.
+			// It only aborts if there's nothing to go back to.
+			if step, ok := m.currentStep().Get(); ok && step == CreateStepTemplate && m.templates.filtering {
+				break
+			}
+			if c, ok := m.back(); ok {
+				return m, c
+			}
+			m.aborted = true
+			return m, tea.Quit
+		case "ctrl+c":
 			m.aborted = true
 			return m, tea.Quit
 		case "q":
@@ -257,11 +972,36 @@ func (m createFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.appName.text.Focused() {
 					break
 				}
+			} else if ok && step == CreateStepTemplateVars {
+				break
+			} else if ok && step == CreateStepTemplate && m.templates.typing() {
+				break
 			}
 			m.aborted = true
 			return m, tea.Quit
 		}
 
+		// Don't act on input while the step can't be rendered.
+		if _, tooSmall := m.tooSmallView(); tooSmall {
+			return m, nil
+		}
+
+		if step, ok := m.currentStep().Get(); ok && msg.Type == tea.KeyTab {
+			switch {
+			case step == CreateStepLang && m.hasStep(CreateStepTemplate):
+				// Skip the language step and search the templates of all languages.
+				// The language is set from the selected template.
+				m.removeStep(CreateStepLang)
+				m.SetSize(m.width, m.height)
+				return m, m.templates.SetSearchAll(true)
+			case step == CreateStepTemplate && m.templates.searchAll && !m.langKnown():
+				// No language has been selected yet, so go back to selecting one.
+				m.steps = append([]CreateStep{CreateStepLang}, m.steps...)
+				m.SetSize(m.width, m.height)
+				return m, m.templates.SetSearchAll(false)
+			}
+		}
+
 		if step, ok := m.currentStep().Get(); ok {
 			switch step {
 			case CreateStepLang:
@@ -276,30 +1016,93 @@ func (m createFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case CreateStepLLMRules:
 				m.llmRules, c = m.llmRules.Update(msg)
 				cmds = append(cmds, c)
+			case CreateStepTemplateVars:
+				m.vars, c = m.vars.Update(msg)
+				cmds = append(cmds, c)
+				if msg.Type == tea.KeyEnter {
+					// Save each variable as it's entered.
+					m.saveSession()
+				}
+			case CreateStepAddons:
+				m.addons, c = m.addons.Update(msg)
+				cmds = append(cmds, c)
+			case CreateStepConfirmDir:
+				if cd, ok := m.confirmDir.Get(); ok {
+					cd, c = cd.Update(msg)
+					m.confirmDir = option.Some(cd)
+					cmds = append(cmds, c)
+				}
 			}
 		}
 		return m, tea.Batch(cmds...)
 
+	case loadedTemplates:
+		// List the languages of the loaded templates, keeping the selection.
+		sel := m.lang.Selected()
+		m.lang.List.SetItems(langItems())
+		for i, it := range m.lang.List.Items() {
+			if it.(langItem).lang == sel {
+				m.lang.List.Select(i)
+			}
+		}
+
 	case langSelectDone:
-		m.removeStep(CreateStepLang)
-		m.templates.UpdateFilter(msg.Selected)
+		m.completeStep(CreateStepLang)
+		m.appName.lang = msg.Selected
+		cmds = append(cmds, m.templates.UpdateFilter(msg.Selected))
 		m.SetSize(m.width, m.height)
+		m.saveSession()
 
 	case llm_rules.ToolSelectDone:
-		m.removeStep(CreateStepLLMRules)
+		m.completeStep(CreateStepLLMRules)
 		m.SetSize(m.width, m.height)
+		m.saveSession()
 
 	case templateSelectDone:
-		m.removeStep(CreateStepTemplate)
+		m.completeStep(CreateStepTemplate)
+		// When searching all languages, the template determines the language.
+		if sel, ok := m.templates.SelectedItem(); ok && m.templates.searchAll {
+			m.lang.Predefined = sel.Lang
+			m.appName.lang = sel.Lang
+			m.templates.filter = sel.Lang
+		}
 		if m.appName.predefined != "" {
 			m.removeStep(CreateStepAppName)
 		}
+		// Prompt for any template variables once everything else is done.
+		if sel, ok := m.templates.SelectedItem(); ok && len(sel.Vars) > 0 {
+			m.vars = newTemplateVarsModel(sel.Vars)
+			cmds = append(cmds, m.vars.resume(m.resumeVars))
+			if !m.vars.done() {
+				m.addStep(CreateStepTemplateVars)
+				cmds = append(cmds, textinput.Blink)
+			}
+		}
+		m.resumeVars = nil
+		m.SetSize(m.width, m.height)
+		m.saveSession()
+
+	case templateVarsDone:
+		m.completeStep(CreateStepTemplateVars)
 		m.SetSize(m.width, m.height)
 
 	case appNameDone:
-		m.removeStep(CreateStepAppName)
+		m.completeStep(CreateStepAppName)
+		m.SetSize(m.width, m.height)
+		m.saveSession()
+
+	case addonsDone:
+		m.completeStep(CreateStepAddons)
+		m.SetSize(m.width, m.height)
+
+	case confirmDirDone:
+		m.completeStep(CreateStepConfirmDir)
 		m.SetSize(m.width, m.height)
 
+	case confirmDirDeclined:
+		m.aborted = true
+		return m, tea.Quit
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -321,12 +1124,14 @@ func (m createFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	cmds = append(cmds, c)
 	m.appName, c = m.appName.Update(msg)
 	cmds = append(cmds, c)
+	m.vars, c = m.vars.Update(msg)
+	cmds = append(cmds, c)
 
 	return m, tea.Batch(cmds...)
 }
 
 func (m *createFormModel) SetSize(width, height int) {
-	doneHeight := lipgloss.Height(m.doneView())
+	doneHeight := lipgloss.Height(m.headerView())
 	availHeight := height - doneHeight
 
 	// CreateStepLang
@@ -339,6 +1144,20 @@ func (m *createFormModel) SetSize(width, height int) {
 	m.llmRules.SetSize(width, availHeight)
 }
 
+// selectedAddons returns the add-ons given as an option or selected in the form.
+func (m createFormModel) selectedAddons() []Addon {
+	if m.predefinedAddons != nil {
+		return m.predefinedAddons
+	}
+	return m.addons.Selected()
+}
+
+// langKnown reports whether the language has been selected,
+// either directly or through a template found by searching all languages.
+func (m createFormModel) langKnown() bool {
+	return m.lang.Predefined != "" || m.templates.filter != ""
+}
+
 func (m createFormModel) doneView() string {
 	var b strings.Builder
 
@@ -349,11 +1168,19 @@ func (m createFormModel) doneView() string {
 	}
 
 	renderLangDone := func() {
-		renderDone("Language", m.lang.Selected().Display())
+		lang := m.lang.Selected().Display()
+		if m.langDetectedFrom != "" {
+			lang += cmdutil.DescStyle.Render(fmt.Sprintf(" (detected from %s)", m.langDetectedFrom))
+		}
+		renderDone("Language", lang)
 	}
 
 	renderNameDone := func() {
-		renderDone("App Name", m.appName.Selected())
+		name := m.appName.Selected()
+		if m.appName.customDir() {
+			name = fmt.Sprintf("%s (in %s)", name, m.appName.dir(name))
+		}
+		renderDone("App Name", name)
 	}
 
 	renderTemplateDone := func() {
@@ -367,7 +1194,7 @@ func (m createFormModel) doneView() string {
 	if m.appName.predefined != "" {
 		renderNameDone()
 	}
-	if m.templates.predefined == "" && !m.hasStep(CreateStepLang) {
+	if (m.templates.predefined == "" || m.templates.predefined == "empty") && !m.hasStep(CreateStepLang) && m.langKnown() {
 		renderLangDone()
 	}
 	if !m.initExistingApp {
@@ -379,21 +1206,46 @@ func (m createFormModel) doneView() string {
 				renderLLMRulesDone()
 			}
 		}
+		if addons := m.selectedAddons(); len(addons) > 0 && !m.hasStep(CreateStepAddons) {
+			renderDone("Infrastructure", displayAddons(addons))
+		}
 	}
 	if m.appName.predefined == "" && !m.hasStep(CreateStepAppName) {
 		renderNameDone()
 	}
+	if len(m.vars.vars) > 0 && !m.hasStep(CreateStepTemplateVars) {
+		for i := range m.vars.vars {
+			renderDone(m.vars.prompt(i), m.vars.value(i))
+		}
+	}
+	if cd, ok := m.confirmDir.Get(); ok && !m.hasStep(CreateStepConfirmDir) {
+		renderDone("Directory", cd.doneValue())
+	}
 
 	return b.String()
 }
 
+// headerView renders the completed steps,
+// and how to go back to them if there are any to go back to.
+func (m createFormModel) headerView() string {
+	done := m.doneView()
+	if len(m.done) > 0 && m.currentStep().Present() && !m.aborted {
+		done += cmdutil.DescStyle.Render("Press esc to go back a step, ctrl+c to quit") + "\n"
+	}
+	return done
+}
+
 func (m createFormModel) View() string {
+	if msg, ok := m.tooSmallView(); ok {
+		return msg
+	}
+
 	var b strings.Builder
 
-	doneView := m.doneView()
+	header := m.headerView()
 
-	b.WriteString(doneView)
-	if doneView != "" {
+	b.WriteString(header)
+	if header != "" {
 		b.WriteByte('\n')
 	}
 
@@ -413,11 +1265,57 @@ func (m createFormModel) View() string {
 		if step == CreateStepLLMRules {
 			b.WriteString(m.llmRules.View())
 		}
+
+		if step == CreateStepTemplateVars {
+			b.WriteString(m.vars.View())
+		}
+
+		if step == CreateStepAddons {
+			b.WriteString(m.addons.View())
+		}
+
+		if step == CreateStepConfirmDir {
+			if cd, ok := m.confirmDir.Get(); ok {
+				b.WriteString(cd.View())
+			}
+		}
 	}
 
 	return cmdutil.DocStyle.Render(b.String())
 }
 
+// The minimum terminal size to render the list steps of the form.
+// The app name and template variable steps are single-line prompts,
+// so they're rendered regardless.
+const (
+	minFormWidth  = 40
+	minFormHeight = 10
+)
+
+// tooSmallView renders a message asking the user to enlarge the terminal,
+// if it's too small to render the current step. It reports false otherwise.
+func (m createFormModel) tooSmallView() (string, bool) {
+	step, ok := m.currentStep().Get()
+	if !ok || m.width == 0 || m.height == 0 {
+		// We don't know the size yet.
+		return "", false
+	}
+	switch step {
+	case CreateStepLang, CreateStepTemplate, CreateStepLLMRules:
+	default:
+		return "", false
+	}
+
+	// Account for the steps already completed, shown above the list.
+	minHeight := minFormHeight + lipgloss.Height(m.headerView())
+	if m.width >= minFormWidth && m.height >= minHeight {
+		return "", false
+	}
+	msg := fmt.Sprintf("Terminal too small (%dx%d), please enlarge it to at least %dx%d to continue. Press Ctrl+C to quit.",
+		m.width, m.height, minFormWidth, minHeight)
+	return cmdutil.ErrorStyle.Width(m.width).Render(msg), true
+}
+
 func (m templateListModel) SelectedItem() (templateItem, bool) {
 	if m.predefined != "" {
 		return templateItem{}, false
@@ -430,42 +1328,304 @@ func (m templateListModel) SelectedItem() (templateItem, bool) {
 	return templateItem{}, false
 }
 
-func createAppForm(inputName, inputTemplate string, inputLang cmdutil.Language, inputLLMRules llm_rules.Tool, initExistingApp bool) (appName, template string, selectedLang cmdutil.Language, selectedRules llm_rules.Tool) {
+// ErrCreateAborted is returned by RunCreateForm when the user aborts the form.
+var ErrCreateAborted = errors.New("app creation aborted")
+
+// CreateFormOptions configures RunCreateForm.
+type CreateFormOptions struct {
+	// Name, Template, Lang and LLMRules are the values already chosen.
+	// The form only prompts for the ones that are empty.
+	Name     string
+	Template string
+	Lang     cmdutil.Language
+	LLMRules llm_rules.Tool
+
+	// DefaultLang is the language initially selected in the language list.
+	DefaultLang cmdutil.Language
+
+	// UseDefaultTemplate uses the default template for the language,
+	// if there is one, instead of prompting for a template.
+	UseDefaultTemplate bool
+
+	// ShowAdvanced includes the advanced templates in the list.
+	ShowAdvanced bool
+
+	// ParentDir is the directory the app is created in,
+	// if not the working directory.
+	ParentDir string
+
+	// Path is the directory the app is created in, if not one named
+	// after the app. It takes precedence over ParentDir.
+	Path string
+
+	// Addons are the add-ons to scaffold in the app. If nil, they're prompted
+	// for along with anything else that's prompted for. Use an empty slice
+	// for no add-ons.
+	Addons []Addon
+
+	// InPlace creates the app in Path, or the working directory if not given,
+	// even if it isn't empty, such as a subfolder of a monorepo.
+	// The form then asks for confirmation if it isn't empty.
+	InPlace bool
+
+	// InitExistingApp is set when initializing an existing app,
+	// in which case no template is selected.
+	InitExistingApp bool
+
+	// DetectLangDir, if set, is the directory of the surrounding project,
+	// such as the working directory. If no language is given, the form
+	// preselects the project's language and skips the language step,
+	// unless it's ambiguous. See detectProjectLang.
+	DetectLangDir string
+
+	// NoPrompt, if set, never prompts. The values that aren't given are set
+	// to their defaults: the detected or default language, falling back to Go,
+	// the language's default template, and no LLM rules. The name is required.
+	NoPrompt bool
+
+	// TemplateSources are custom template sources whose templates are
+	// listed before Encore's. See loadTemplateSource.
+	TemplateSources []string
+
+	// SessionFile, if set, is where the progress through the form is saved
+	// on each step, so that it can be resumed if the form is interrupted.
+	// If a session is saved there, the user is asked whether to resume it.
+	// It's removed when the form completes or is aborted.
+	SessionFile string
+
+	// Input and Output are what the form reads from and renders to.
+	// Input defaults to os.Stdin. Output defaults to os.Stdout for the form
+	// itself, and to os.Stderr for the prompts shown before it.
+	//
+	// The form only prompts if Input is a terminal, or isn't a file at all,
+	// so that it can be driven programmatically.
+	Input  io.Reader
+	Output io.Writer
+
+	// resume is the session being resumed, if any.
+	resume *formSession
+}
+
+// CreateFormResult is the outcome of RunCreateForm.
+type CreateFormResult struct {
+	AppName      string
+	Dir          string // the directory the app is created in
+	Template     string
+	Lang         cmdutil.Language
+	LLMRules     llm_rules.Tool
+	TemplateVars map[string]string
+	Addons       []Addon
+}
+
+// createAppForm runs the create form for the CLI, exiting if it's aborted or fails.
+func createAppForm(inputName, inputTemplate string, inputLang, defaultLang cmdutil.Language, inputLLMRules llm_rules.Tool, initExistingApp bool) (appName, template string, selectedLang cmdutil.Language, selectedRules llm_rules.Tool, templateVars map[string]string, addons []Addon) {
+	opts := CreateFormOptions{
+		Name:               inputName,
+		Template:           inputTemplate,
+		Lang:               inputLang,
+		LLMRules:           inputLLMRules,
+		DefaultLang:        defaultLang,
+		UseDefaultTemplate: createAppDefaultTemplate,
+		ShowAdvanced:       createAppAdvanced,
+		InitExistingApp:    initExistingApp,
+		NoPrompt:           createAppYes,
+	}
+	if !initExistingApp {
+		opts.ParentDir = createAppParentDir
+		opts.Path = createAppPath
+		opts.InPlace = createAppInPlace
+		opts.Addons = createAppAddons
+		opts.TemplateSources = createAppTemplateSources
+		opts.DetectLangDir = "."
+		if createAppInPlace && createAppPath != "" {
+			// Detect the language of the project the app is created in.
+			opts.DetectLangDir = createAppPath
+		}
+		if path, err := formSessionPath(); err == nil {
+			opts.SessionFile = path
+		}
+	}
+
+	res, err := RunCreateForm(opts)
+	if errors.Is(err, ErrCreateAborted) {
+		os.Exit(1)
+	} else if err != nil {
+		cmdutil.Fatal(err)
+	}
+	return res.AppName, res.Template, res.Lang, res.LLMRules, res.TemplateVars, res.Addons
+}
+
+// RunCreateForm prompts for the details of the app to create.
+//
+// It returns ErrCreateAborted if the user aborts the form.
+func RunCreateForm(opts CreateFormOptions) (*CreateFormResult, error) {
+	in, out := opts.Input, opts.Output
+	if in == nil {
+		in = os.Stdin
+	}
+	if out == nil {
+		out = os.Stderr
+	}
+	interactive := isInteractive(in) && !opts.NoPrompt
+	if opts.NoPrompt {
+		opts.useDefaults()
+	}
+
+	if opts.Template == "" && opts.UseDefaultTemplate && !opts.InitExistingApp {
+		if slug, ok := defaultTemplateSlugs[opts.Lang]; ok {
+			opts.Template = slug
+		}
+	}
+
+	var custom []templateSource
+	if len(opts.TemplateSources) > 0 && !opts.InitExistingApp && (opts.Template == "" || isTemplateName(opts.Template)) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		var err error
+		custom, err = loadTemplateSources(ctx, opts.TemplateSources)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Make sure a template given by name exists, so we don't fail later
+	// when scaffolding the app.
+	if opts.Template != "" && opts.Template != "empty" && !opts.InitExistingApp && isTemplateName(opts.Template) {
+		if exists, known := templateExists(opts.Template, custom); known && !exists {
+			if !interactive {
+				return nil, fmt.Errorf("template %q not found", opts.Template)
+			} else if !promptPickTemplate(opts.Template, in, out) {
+				return nil, ErrCreateAborted
+			}
+			opts.Template = ""
+		}
+	}
+
+	result := &CreateFormResult{
+		AppName:  opts.Name,
+		Template: opts.Template,
+		Lang:     opts.Lang,
+		LLMRules: opts.LLMRules,
+		Addons:   opts.Addons,
+	}
+
+	if opts.InPlace && opts.Path == "" {
+		opts.Path = "."
+	}
+	confirmDir := opts.InPlace && dirInUse(opts.Path)
+
 	// If all is set, just return
-	if inputName != "" && inputTemplate != "" && inputLLMRules != "" {
-		return inputName, inputTemplate, inputLang, inputLLMRules
+	if opts.Name != "" && opts.Template != "" && opts.LLMRules != "" && (!confirmDir || !interactive) {
+		result.Dir = appDir(opts.ParentDir, opts.Path, result.AppName)
+		return result, nil
+	}
+
+	// If the input is non-interactive, don't prompt
+	if !interactive {
+		if opts.Name == "" {
+			return nil, errors.New("specify an app name")
+		}
+		result.Dir = appDir(opts.ParentDir, opts.Path, result.AppName)
+		return result, nil
+	}
+
+	if opts.SessionFile != "" {
+		if s := readFormSession(opts.SessionFile); s != nil {
+			if promptResumeSession(s, in, out) {
+				opts.resumeSession(s)
+			} else {
+				removeFormSession(opts.SessionFile)
+			}
+		}
+	}
+
+	var progOpts []tea.ProgramOption
+	if opts.Input != nil {
+		progOpts = append(progOpts, tea.WithInput(opts.Input))
+	}
+	if opts.Output != nil {
+		progOpts = append(progOpts, tea.WithOutput(opts.Output))
+	}
+	model := newCreateFormModel(opts)
+	model.templates.custom = custom
+	final, err := tea.NewProgram(model, progOpts...).Run()
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the result.
+	res := final.(createFormModel)
+	printSlugConflicts(out, res.templates.conflicts)
+	if opts.SessionFile != "" {
+		removeFormSession(opts.SessionFile)
+	}
+	if res.aborted {
+		return nil, ErrCreateAborted
+	}
+
+	if result.AppName == "" {
+		result.AppName = res.appName.text.Value()
+	}
+	result.Dir = res.appName.dir(result.AppName)
+	if result.Template == "" && !opts.InitExistingApp {
+		sel, ok := res.templates.SelectedItem()
+		if !ok {
+			return nil, errors.New("no template selected")
+		}
+		result.Template = sel.Template
+	}
+	result.Lang = res.lang.Selected()
+	result.LLMRules = res.llmRules.Selected()
+	result.TemplateVars = res.vars.Values()
+	result.Addons = res.selectedAddons()
+	return result, nil
+}
+
+// useDefaults sets the values that aren't given to their defaults.
+// See CreateFormOptions.NoPrompt.
+func (opts *CreateFormOptions) useDefaults() {
+	if opts.Lang == "" && opts.DetectLangDir != "" {
+		opts.Lang, _, _ = detectProjectLang(opts.DetectLangDir)
 	}
+	if opts.Lang == "" {
+		opts.Lang = opts.DefaultLang
+	}
+	if opts.Lang == "" {
+		opts.Lang = cmdutil.LanguageGo
+	}
+	if opts.Template == "" && !opts.InitExistingApp {
+		opts.Template = defaultTemplateSlugs[opts.Lang]
+	}
+}
+
+// isInteractive reports whether the form can prompt for input read from in.
+// Readers that aren't files are assumed to be driven programmatically.
+func isInteractive(in io.Reader) bool {
+	if f, ok := in.(*os.File); ok {
+		return term.IsTerminal(int(f.Fd()))
+	}
+	return true
+}
 
-	// If shell is non-interactive, don't prompt
-	if !term.IsTerminal(int(os.Stdin.Fd())) {
-		if inputName == "" {
-			cmdutil.Fatal("specify an app name")
+// newCreateFormModel returns the model for the create form,
+// prompting for the values not already set in opts.
+func newCreateFormModel(opts CreateFormOptions) createFormModel {
+	var langDetectedFrom string
+	if opts.Lang == "" && opts.DetectLangDir != "" {
+		if lang, file, ok := detectProjectLang(opts.DetectLangDir); ok {
+			opts.Lang, langDetectedFrom = lang, file
 		}
-		return inputName, inputTemplate, inputLang, inputLLMRules
 	}
 
 	var langModel langSelectModel
 	{
-		ls := list.NewDefaultItemStyles()
-		ls.SelectedTitle = ls.SelectedTitle.Foreground(lipgloss.Color(cmdutil.CodeBlue)).BorderForeground(lipgloss.Color(cmdutil.CodeBlue))
-		ls.SelectedDesc = ls.SelectedDesc.Foreground(lipgloss.Color(cmdutil.CodeBlue)).BorderForeground(lipgloss.Color(cmdutil.CodeBlue))
+		ls := cmdutil.ActiveTheme.ListItemStyles()
 		del := list.NewDefaultDelegate()
 		del.Styles = ls
 		del.ShowDescription = false
 		del.SetSpacing(0)
 
-		items := []list.Item{
-			langItem{
-				lang: cmdutil.LanguageGo,
-				desc: "Build performant and scalable backends with Go",
-			},
-			langItem{
-				lang: cmdutil.LanguageTS,
-				desc: "Build backend and full-stack applications with TypeScript",
-			},
-		}
-
-		ll := list.New(items, del, 0, 0)
+		ll := list.New(langItems(), del, 0, 0)
 		ll.SetShowTitle(false)
 		ll.SetShowHelp(false)
 		ll.SetShowPagination(true)
@@ -473,18 +1633,21 @@ func createAppForm(inputName, inputTemplate string, inputLang cmdutil.Language,
 		ll.SetFilteringEnabled(false)
 		ll.SetShowStatusBar(false)
 		ll.DisableQuitKeybindings() // quit handled by createFormModel
+		for i, it := range ll.Items() {
+			if it.(langItem).lang == opts.DefaultLang {
+				ll.Select(i)
+			}
+		}
 		langModel = langSelectModel{
 			List:       ll,
-			Predefined: inputLang,
+			Predefined: opts.Lang,
 		}
 		langModel.SetSize(0, 20)
 	}
 
 	var templateModel templateListModel
 	{
-		ls := list.NewDefaultItemStyles()
-		ls.SelectedTitle = ls.SelectedTitle.Foreground(lipgloss.Color(cmdutil.CodeBlue)).BorderForeground(lipgloss.Color(cmdutil.CodeBlue))
-		ls.SelectedDesc = ls.SelectedDesc.Foreground(lipgloss.Color(cmdutil.CodeBlue)).BorderForeground(lipgloss.Color(cmdutil.CodeBlue))
+		ls := cmdutil.ActiveTheme.ListItemStyles()
 		del := list.NewDefaultDelegate()
 		del.Styles = ls
 
@@ -500,17 +1663,24 @@ func createAppForm(inputName, inputTemplate string, inputLang cmdutil.Language,
 		sp := spinner.New()
 		sp.Spinner = spinner.Dot
 		sp.Style = cmdutil.InputStyle.Copy().Inline(true)
+		query := textinput.New()
+		query.Prompt = "Search: "
+		query.Placeholder = "e.g. graphql"
+		query.Width = 30
+
 		templateModel = templateListModel{
-			predefined: inputTemplate,
-			list:       ll,
-			loading:    sp,
+			query:        query,
+			predefined:   opts.Template,
+			list:         ll,
+			loading:      sp,
+			useDefault:   opts.UseDefaultTemplate,
+			showAdvanced: opts.ShowAdvanced,
+			files:        make(map[string]*templateFiles),
 		}
 	}
 	var llmRulesModel llm_rules.ToolSelectModel
 	{
-		ls := list.NewDefaultItemStyles()
-		ls.SelectedTitle = ls.SelectedTitle.Foreground(lipgloss.Color(cmdutil.CodeBlue)).BorderForeground(lipgloss.Color(cmdutil.CodeBlue))
-		ls.SelectedDesc = ls.SelectedDesc.Foreground(lipgloss.Color(cmdutil.CodeBlue)).BorderForeground(lipgloss.Color(cmdutil.CodeBlue))
+		ls := cmdutil.ActiveTheme.ListItemStyles()
 		del := list.NewDefaultDelegate()
 		del.Styles = ls
 		del.ShowDescription = false
@@ -533,7 +1703,7 @@ func createAppForm(inputName, inputTemplate string, inputLang cmdutil.Language,
 
 		llmRulesModel = llm_rules.ToolSelectModel{
 			List:       ll,
-			Predefined: inputLLMRules,
+			Predefined: opts.LLMRules,
 		}
 		llmRulesModel.SetSize(0, 20)
 
@@ -547,12 +1717,12 @@ func createAppForm(inputName, inputTemplate string, inputLang cmdutil.Language,
 		text.Width = 30
 		text.Validate = incrementalValidateNameInput
 
-		nameModel = appNameModel{predefined: inputName, text: text}
+		nameModel = appNameModel{predefined: opts.Name, text: text, lang: opts.Lang, parentDir: opts.ParentDir, path: opts.Path, inPlace: opts.InPlace}
 	}
 
 	// Setup what steps and in what order they should be presented
 	var steps []CreateStep
-	if initExistingApp {
+	if opts.InitExistingApp {
 		if langModel.Predefined == "" {
 			steps = append(steps, CreateStepLang)
 		}
@@ -561,76 +1731,120 @@ func createAppForm(inputName, inputTemplate string, inputLang cmdutil.Language,
 			if langModel.Predefined == "" {
 				steps = append(steps, CreateStepLang)
 			} else {
-				templateModel.UpdateFilter(inputLang)
+				// The templates haven't loaded yet, so there's nothing to select.
+				_ = templateModel.UpdateFilter(opts.Lang)
 			}
 			steps = append(steps, CreateStepTemplate)
+		} else if templateModel.predefined == "empty" && langModel.Predefined == "" {
+			// The empty template exists for each language.
+			steps = append(steps, CreateStepLang)
 		}
 		if llmRulesModel.Predefined == "" {
 			steps = append(steps, CreateStepLLMRules)
 		}
+		if opts.Addons == nil {
+			steps = append(steps, CreateStepAddons)
+		}
 	}
 	if nameModel.predefined == "" {
 		steps = append(steps, CreateStepAppName)
 	}
+	var confirmDir option.Option[confirmDirModel]
+	if opts.InPlace && !opts.InitExistingApp && dirInUse(opts.Path) {
+		confirmDir = option.Some(confirmDirModel{dir: opts.Path, existing: existingProjectFiles(opts.Path)})
+		steps = append(steps, CreateStepConfirmDir)
+	}
 
 	m := createFormModel{
-		steps:           steps,
-		lang:            langModel,
-		templates:       templateModel,
-		llmRules:        llmRulesModel,
-		appName:         nameModel,
-		initExistingApp: initExistingApp,
+		steps:            steps,
+		lang:             langModel,
+		templates:        templateModel,
+		llmRules:         llmRulesModel,
+		appName:          nameModel,
+		addons:           newAddonsModel(),
+		predefinedAddons: opts.Addons,
+		confirmDir:       confirmDir,
+		initExistingApp:  opts.InitExistingApp,
+		langDetectedFrom: langDetectedFrom,
+		sessionFile:      opts.SessionFile,
+	}
+	if s := opts.resume; s != nil {
+		m.templates.resume = option.Some(*s.Template)
+		m.resumeVars = s.TemplateVars
 	}
 
 	// If we have a name, start the list without any selection.
 	if m.appName.predefined != "" {
 		m.templates.list.Select(-1)
 	}
+	return m
+}
 
-	p := tea.NewProgram(m)
+type langItem struct {
+	lang cmdutil.Language
+	desc string
+}
 
-	result, err := p.Run()
-	if err != nil {
-		cmdutil.Fatal(err)
-	}
+func (i langItem) FilterValue() string          { return i.lang.Display() }
+func (i langItem) Title() string                { return i.FilterValue() }
+func (i langItem) Description() string          { return "" }
+func (i langItem) SelectedID() cmdutil.Language { return i.lang }
 
-	// Validate the result.
-	res := result.(createFormModel)
-	if res.aborted {
-		os.Exit(1)
+// langItems returns the items of the registered languages.
+func langItems() []list.Item {
+	langs := cmdutil.Languages()
+	items := make([]list.Item, len(langs))
+	for i, l := range langs {
+		items[i] = langItem{lang: l.Lang, desc: l.Desc}
 	}
+	return items
+}
 
-	appName, template = inputName, inputTemplate
-
-	if appName == "" {
-		appName = res.appName.text.Value()
+// registerTemplateLanguages registers the languages of the templates,
+// so that languages added to the manifest are listed.
+func registerTemplateLanguages(items []templateItem) {
+	for _, it := range items {
+		cmdutil.RegisterLanguage(cmdutil.LanguageInfo{Lang: it.Lang, Name: it.LangName, Desc: it.LangDesc})
 	}
+}
 
-	if template == "" && !initExistingApp {
-		sel, ok := res.templates.SelectedItem()
-		if !ok {
-			cmdutil.Fatal("no template selected")
+// registerManifestLanguages registers the languages of the templates in the
+// template manifests, so that languages added to the manifest can be given
+// with --lang. It uses the cached manifests, fetching those that aren't cached
+// if fetch is set.
+func registerManifestLanguages(fetch bool) {
+	for _, url := range []string{templatesURL, tutorialsURL} {
+		items, err := readCachedManifest(url)
+		if err != nil && fetch {
+			items, _ = fetchTemplateManifest(url)
 		}
-		template = sel.Template
+		registerTemplateLanguages(items)
 	}
-
-	return appName, template, res.lang.Selected(), res.llmRules.Selected()
 }
 
-type langItem struct {
-	lang cmdutil.Language
-	desc string
+// languageFlagValues returns the languages apps can be created in,
+// including the languages of the template manifests.
+func languageFlagValues() []string {
+	registerManifestLanguages(true)
+	return cmdutil.LanguageFlagValues()
 }
 
-func (i langItem) FilterValue() string          { return i.lang.Display() }
-func (i langItem) Title() string                { return i.FilterValue() }
-func (i langItem) Description() string          { return "" }
-func (i langItem) SelectedID() cmdutil.Language { return i.lang }
+// cachedLanguageFlagValues is like languageFlagValues but only uses the
+// cached template manifests. It's used when parsing --lang, which happens
+// before flags like --offline are known.
+func cachedLanguageFlagValues() []string {
+	registerManifestLanguages(false)
+	return cmdutil.LanguageFlagValues()
+}
 
 type langSelectModel = cmdutil.SimpleSelectModel[cmdutil.Language, langItem]
 type langSelectDone = cmdutil.SimpleSelectDone[cmdutil.Language]
 
-type loadedTemplates []templateItem
+type loadedTemplates struct {
+	items     []templateItem
+	conflicts []slugConflict
+	stale     string // a warning if the templates may be out of date
+}
 
 var defaultTutorials = []templateItem{
 	{
@@ -689,55 +1903,240 @@ var defaultTemplates = []templateItem{
 		Desc:      "Complete app with Clerk auth, Stripe billing, etc. (advanced)",
 		Template:  "ts/saas-starter",
 		Lang:      "ts",
+		Advanced:  true,
 	},
 	{
 		ItemTitle: "Empty app",
 		Desc:      "Start from scratch (experienced users only)",
 		Template:  "",
 		Lang:      "go",
+		Advanced:  true,
 	},
 	{
 		ItemTitle: "Empty app",
 		Desc:      "Start from scratch (experienced users only)",
 		Template:  "ts/empty",
 		Lang:      "ts",
+		Advanced:  true,
 	},
 }
 
-func fetchTemplates(url string, defaults []templateItem) []templateItem {
+const (
+	templatesURL = "https://raw.githubusercontent.com/encoredev/examples/main/cli-templates.json"
+	tutorialsURL = "https://raw.githubusercontent.com/encoredev/examples/main/cli-tutorials.json"
+)
+
+// templateSource is a list of templates and where it came from.
+type templateSource struct {
+	name  string // the manifest's URL, or builtinTemplates
+	items []templateItem
+
+	// stale describes the templates if they may be out of date,
+	// such as "the built-in templates". It's empty if they're up to date.
+	stale string
+}
+
+// builtinTemplates is the name of the source of the built-in default templates,
+// which only list some of the templates.
+const builtinTemplates = "built-in defaults"
+
+// fetchTemplates fetches the templates listed at url, using the cached
+// manifest instead if it was cached recently. If they can't be fetched
+// it falls back to the cached manifest regardless of its age,
+// and then to defaults.
+func fetchTemplates(url string, defaults []templateItem) templateSource {
+	// A corrupt cache is treated like a missing one.
+	cached, err := readCachedManifest(url)
+	if err != nil {
+		cached = nil
+	}
+	age, err := cachedManifestAge(url)
+	if len(cached) > 0 && err == nil && age < manifestCacheTTL {
+		return templateSource{name: url + " (cached)", items: cached}
+	}
+
+	if items, err := fetchTemplateManifest(url); err == nil {
+		_ = writeCachedManifest(url, items)
+		return templateSource{name: url, items: items}
+	}
+	// Fall back to the cached manifest when offline.
+	if len(cached) > 0 {
+		return templateSource{
+			name:  url + " (cached)",
+			items: cached,
+			stale: fmt.Sprintf("the templates cached %s ago", formatCacheAge(age)),
+		}
+	}
+	return templateSource{name: builtinTemplates, items: defaults, stale: "the built-in templates"}
+}
+
+// slugConflict describes a template slug listed more than once
+// across the merged template manifests.
+type slugConflict struct {
+	slug   string
+	winner string // the source whose entry is used
+	loser  string // the source whose entry is ignored
+}
+
+func (c slugConflict) String() string {
+	slug := c.slug
+	if slug == "" {
+		slug = "(empty)"
+	}
+	if c.winner == c.loser {
+		return fmt.Sprintf("template %s is listed more than once in %s, using the first entry", slug, c.winner)
+	}
+	return fmt.Sprintf("template %s is listed in both %s and %s, using the one from %s", slug, c.winner, c.loser, c.winner)
+}
+
+// mergeTemplates merges the templates of the given sources in order.
+// If a template slug is listed more than once, the first entry wins
+// and the others are reported as conflicts rather than shadowing it.
+func mergeTemplates(sources ...templateSource) ([]templateItem, []slugConflict) {
+	var (
+		items     []templateItem
+		conflicts []slugConflict
+		seen      = make(map[string]string) // slug -> source
+	)
+	for _, src := range sources {
+		for _, it := range src.items {
+			if winner, ok := seen[it.Template]; ok {
+				conflicts = append(conflicts, slugConflict{slug: it.Template, winner: winner, loser: src.name})
+				continue
+			}
+			seen[it.Template] = src.name
+			items = append(items, it)
+		}
+	}
+	return items, conflicts
+}
+
+// printSlugConflicts warns about the given slug conflicts.
+func printSlugConflicts(out io.Writer, conflicts []slugConflict) {
+	yellow := color.New(color.FgYellow)
+	for _, c := range conflicts {
+		_, _ = yellow.Fprintf(out, "Warning: %s.\n", c)
+	}
+}
+
+// errOffline is reported when fetching something over the network with --offline.
+var errOffline = errors.New("offline")
+
+func fetchTemplateManifest(url string) ([]templateItem, error) {
+	if createAppOffline {
+		return nil, errOffline
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	if req, err := http.NewRequestWithContext(ctx, "GET", url, nil); err == nil {
-		if resp, err := http.DefaultClient.Do(req); err == nil {
-			if data, err := io.ReadAll(resp.Body); err == nil {
-				data, err = hujson.Standardize(data)
-				if err == nil {
-					var items []templateItem
-					if err := json.Unmarshal(data, &items); err == nil && len(items) > 0 {
-						return items
-					}
-				}
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return parseTemplateManifest(data)
+}
+
+// parseTemplateManifest parses a template manifest,
+// which is a JSON list of templates allowing comments.
+func parseTemplateManifest(data []byte) ([]templateItem, error) {
+	data, err := hujson.Standardize(data)
+	if err != nil {
+		return nil, err
+	}
+	var items []templateItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	} else if len(items) == 0 {
+		return nil, errors.New("no templates found")
+	}
+	return items, nil
+}
+
+// isTemplateName reports whether tmpl refers to a template by name,
+// as opposed to a URL or a local directory. See parseTemplate.
+func isTemplateName(tmpl string) bool {
+	return !strings.Contains(tmpl, ":") && !strings.Contains(tmpl, ".") && !filepath.IsAbs(tmpl)
+}
+
+// templateExists reports whether a template or tutorial with the given name
+// exists, either in the given custom sources or in Encore's manifests.
+// The manifests are loaded like when listing the templates, using the cache
+// when it's fresh. If they can't be loaded it reports known=false.
+func templateExists(name string, custom []templateSource) (exists, known bool) {
+	known = true
+	sources := slices.Concat(custom, []templateSource{
+		fetchTemplates(templatesURL, defaultTemplates),
+		fetchTemplates(tutorialsURL, defaultTutorials),
+	})
+	for _, src := range sources {
+		if slices.ContainsFunc(src.items, func(it templateItem) bool { return it.Template == name }) {
+			return true, true
+		}
+		if src.name == builtinTemplates {
+			known = false
+		}
+	}
+	return false, known
+}
+
+// promptPickTemplate asks the user whether to pick another template
+// since the given one doesn't exist.
+func promptPickTemplate(name string, in io.Reader, out io.Writer) bool {
+	cyan := color.New(color.FgCyan)
+	red := color.New(color.FgRed)
+	for {
+		_, _ = cyan.Fprintf(out, "Template %q not found. Pick one from the list instead? (Y/n): ", name)
+		var input string
+		_, _ = fmt.Fscanln(in, &input)
+		input = strings.TrimSpace(input)
+		switch input {
+		case "Y", "y", "yes", "":
+			return true
+		case "N", "n", "no", "q", "quit", "exit":
+			return false
+		default:
+			// Try again.
+			_, _ = red.Fprintln(out, "Unexpected answer, please enter 'y' or 'n'.")
+		}
+	}
+}
+
+// loadTemplates loads the templates and tutorials, listing the templates
+// of the given custom sources first.
+func loadTemplates(custom []templateSource) tea.Cmd {
+	return func() tea.Msg {
+		var wg sync.WaitGroup
+		var templates, tutorials templateSource
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			templates = fetchTemplates(templatesURL, defaultTemplates)
+		}()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tutorials = fetchTemplates(tutorialsURL, defaultTutorials)
+		}()
+		wg.Wait()
+		items, conflicts := mergeTemplates(slices.Concat(custom, []templateSource{tutorials, templates})...)
+		registerTemplateLanguages(items)
+		msg := loadedTemplates{items: items, conflicts: conflicts}
+		for _, src := range []templateSource{templates, tutorials} {
+			if src.stale != "" {
+				msg.stale = fmt.Sprintf("Using %s, which may be out of date.", src.stale)
+				break
 			}
 		}
+		return msg
 	}
-	return defaults
-}
-
-func loadTemplates() tea.Msg {
-	var wg sync.WaitGroup
-	var templates, tutorials []templateItem
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		templates = fetchTemplates("https://raw.githubusercontent.com/encoredev/examples/main/cli-templates.json", defaultTemplates)
-	}()
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		tutorials = fetchTemplates("https://raw.githubusercontent.com/encoredev/examples/main/cli-tutorials.json", defaultTutorials)
-	}()
-	wg.Wait()
-	return loadedTemplates(append(tutorials, templates...))
 }
 
 // incrementalValidateNameInput is like validateName but only
@@ -0,0 +1,165 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"encr.dev/cli/cmd/encore/cmdutil"
+	"encr.dev/cli/cmd/encore/llm_rules"
+	"encr.dev/internal/conf"
+)
+
+// formSession is the progress through the create form, saved on each step
+// so that an interrupted form, such as by closing the terminal, can be resumed.
+// It's removed when the form completes or is aborted.
+type formSession struct {
+	Lang     cmdutil.Language `json:"lang,omitempty"`
+	AppName  string           `json:"appName,omitempty"`
+	LLMRules llm_rules.Tool   `json:"llmRules,omitempty"`
+
+	// Template is the selected template, or nil if none has been selected yet.
+	// It's a pointer since the empty Go app has the empty slug.
+	Template *string `json:"template,omitempty"`
+
+	// TemplateVars are the template variables entered so far.
+	TemplateVars map[string]string `json:"templateVars,omitempty"`
+}
+
+func (s *formSession) empty() bool {
+	return s.Lang == "" && s.AppName == "" && s.LLMRules == "" && s.Template == nil
+}
+
+// summary describes the selections made in the session.
+func (s *formSession) summary() string {
+	var parts []string
+	if s.Lang != "" {
+		parts = append(parts, s.Lang.Display())
+	}
+	if s.Template != nil {
+		tmpl := *s.Template
+		if tmpl == "" {
+			tmpl = "empty"
+		}
+		parts = append(parts, "template "+tmpl)
+	}
+	if s.AppName != "" {
+		parts = append(parts, "name "+s.AppName)
+	}
+	if n := len(s.TemplateVars); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d template settings", n))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formSessionPath reports where the create form's session is saved.
+func formSessionPath() (string, error) {
+	dir, err := conf.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "create-session.json"), nil
+}
+
+// readFormSession reads the session saved at path.
+// It returns nil if there's no session to resume.
+func readFormSession(path string) *formSession {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var s formSession
+	if err := json.Unmarshal(data, &s); err != nil || s.empty() {
+		return nil
+	}
+	return &s
+}
+
+func writeFormSession(path string, s formSession) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func removeFormSession(path string) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		_, _ = fmt.Fprintf(os.Stderr, "warning: could not remove saved create session: %v\n", err)
+	}
+}
+
+// promptResumeSession asks the user whether to resume the given session.
+func promptResumeSession(s *formSession, in io.Reader, out io.Writer) bool {
+	cyan := color.New(color.FgCyan)
+	red := color.New(color.FgRed)
+	for {
+		_, _ = cyan.Fprintf(out, "Resume previous session (%s)? (Y/n): ", s.summary())
+		var input string
+		_, _ = fmt.Fscanln(in, &input)
+		switch strings.TrimSpace(input) {
+		case "Y", "y", "yes", "":
+			return true
+		case "N", "n", "no":
+			return false
+		default:
+			// Try again.
+			_, _ = red.Fprintln(out, "Unexpected answer, please enter 'y' or 'n'.")
+		}
+	}
+}
+
+// resumeSession fills in the options that weren't given from the session.
+// The template is selected once the templates are loaded, rather than given
+// as an option, so that its variables are prompted for.
+func (opts *CreateFormOptions) resumeSession(s *formSession) {
+	if opts.Lang == "" {
+		opts.Lang = s.Lang
+	}
+	if opts.Name == "" {
+		opts.Name = s.AppName
+	}
+	if opts.LLMRules == "" {
+		opts.LLMRules = s.LLMRules
+	}
+	if opts.Template == "" && s.Template != nil && s.Lang != "" {
+		opts.resume = s
+	}
+}
+
+// session returns the progress through the form so far.
+func (m createFormModel) session() formSession {
+	s := formSession{Lang: m.appName.lang}
+	if !m.hasStep(CreateStepAppName) && m.appName.predefined == "" {
+		s.AppName = m.appName.text.Value()
+	}
+	if !m.hasStep(CreateStepLLMRules) && m.llmRules.Predefined == "" {
+		s.LLMRules = m.llmRules.Selected()
+	}
+	if !m.hasStep(CreateStepTemplate) && m.templates.predefined == "" {
+		if sel, ok := m.templates.SelectedItem(); ok {
+			s.Template = &sel.Template
+			s.TemplateVars = m.vars.entered()
+		}
+	}
+	return s
+}
+
+// saveSession saves the progress through the form, if enabled.
+// Failing to save it isn't fatal, since it's only needed to resume the form.
+func (m createFormModel) saveSession() {
+	if m.sessionFile == "" {
+		return
+	}
+	_ = writeFormSession(m.sessionFile, m.session())
+}
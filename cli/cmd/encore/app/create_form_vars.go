@@ -0,0 +1,185 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"encr.dev/cli/cmd/encore/cmdutil"
+)
+
+// templateVar is a variable a template needs a value for before scaffolding.
+// Occurrences of "{{NAME}}" in the template's files are replaced with the value.
+type templateVar struct {
+	Name     string `json:"name"`
+	Prompt   string `json:"prompt"`
+	Default  string `json:"default,omitempty"`
+	Validate string `json:"validate,omitempty"` // regular expression the value must match
+}
+
+type templateVarsDone struct{}
+
+type templateVarsModel struct {
+	vars   []templateVar
+	inputs []textinput.Model
+	valid  []*regexp.Regexp // nil entries aren't validated
+	idx    int              // index of the variable being entered
+	err    string           // validation error for the current variable, if any
+}
+
+func newTemplateVarsModel(vars []templateVar) templateVarsModel {
+	m := templateVarsModel{vars: vars}
+	for i, v := range vars {
+		text := textinput.New()
+		text.Placeholder = v.Default
+		text.Width = 40
+		if i == 0 {
+			text.Focus()
+		}
+		m.inputs = append(m.inputs, text)
+
+		var re *regexp.Regexp
+		if v.Validate != "" {
+			// Ignore invalid patterns rather than making the template unusable.
+			re, _ = regexp.Compile("^(?:" + v.Validate + ")$")
+		}
+		m.valid = append(m.valid, re)
+	}
+	return m
+}
+
+// value reports the value of the i'th variable,
+// falling back to its default.
+func (m templateVarsModel) value(i int) string {
+	if val := m.inputs[i].Value(); val != "" {
+		return val
+	}
+	return m.vars[i].Default
+}
+
+// Values returns the entered values, keyed by variable name.
+func (m templateVarsModel) Values() map[string]string {
+	if len(m.vars) == 0 {
+		return nil
+	}
+	values := make(map[string]string, len(m.vars))
+	for i, v := range m.vars {
+		values[v.Name] = m.value(i)
+	}
+	return values
+}
+
+// entered returns the values of the variables entered so far,
+// keyed by variable name.
+func (m templateVarsModel) entered() map[string]string {
+	if m.idx == 0 {
+		return nil
+	}
+	values := make(map[string]string, m.idx)
+	for i := range m.idx {
+		values[m.vars[i].Name] = m.value(i)
+	}
+	return values
+}
+
+// resume fills in the values entered in a previous session,
+// continuing with the first variable without one.
+func (m *templateVarsModel) resume(values map[string]string) tea.Cmd {
+	if len(values) == 0 || m.idx >= len(m.inputs) {
+		return nil
+	}
+	m.inputs[m.idx].Blur()
+	for m.idx < len(m.inputs) {
+		val, ok := values[m.vars[m.idx].Name]
+		if !ok {
+			break
+		}
+		m.inputs[m.idx].SetValue(val)
+		m.idx++
+	}
+	if m.idx < len(m.inputs) {
+		return m.inputs[m.idx].Focus()
+	}
+	return nil
+}
+
+// done reports whether all variables have been entered.
+func (m templateVarsModel) done() bool {
+	return m.idx >= len(m.inputs)
+}
+
+// back goes back to the previous variable, keeping the value entered for it.
+func (m *templateVarsModel) back() tea.Cmd {
+	if m.idx == 0 {
+		return nil
+	}
+	if m.idx < len(m.inputs) {
+		m.inputs[m.idx].Blur()
+	}
+	m.err = ""
+	m.idx--
+	return m.inputs[m.idx].Focus()
+}
+
+func (m templateVarsModel) Update(msg tea.Msg) (templateVarsModel, tea.Cmd) {
+	if m.idx >= len(m.inputs) {
+		return m, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.Type == tea.KeyEnter {
+		val := m.value(m.idx)
+		if val == "" {
+			m.err = "a value is required"
+			return m, nil
+		} else if re := m.valid[m.idx]; re != nil && !re.MatchString(val) {
+			m.err = fmt.Sprintf("must match %s", m.vars[m.idx].Validate)
+			return m, nil
+		}
+
+		m.err = ""
+		m.inputs[m.idx].Blur()
+		m.idx++
+		if m.idx == len(m.inputs) {
+			return m, func() tea.Msg { return templateVarsDone{} }
+		}
+		return m, m.inputs[m.idx].Focus()
+	}
+
+	var c tea.Cmd
+	m.inputs[m.idx], c = m.inputs[m.idx].Update(msg)
+	return m, c
+}
+
+func (m templateVarsModel) prompt(i int) string {
+	if p := m.vars[i].Prompt; p != "" {
+		return p
+	}
+	return m.vars[i].Name
+}
+
+func (m templateVarsModel) View() string {
+	var b strings.Builder
+	b.WriteString(cmdutil.InputStyle.Render("Template Settings"))
+	b.WriteString(cmdutil.DescStyle.Render(" [Press enter to use the default]"))
+	b.WriteByte('\n')
+	for i := range m.vars {
+		if i < m.idx {
+			fmt.Fprintf(&b, "%s %s: %s\n", checkmark, m.prompt(i), m.value(i))
+			continue
+		} else if i > m.idx {
+			break
+		}
+
+		b.WriteString(m.prompt(i))
+		b.WriteByte('\n')
+		b.WriteString(m.inputs[i].View())
+		if m.err != "" {
+			b.WriteString(cmdutil.ErrorStyle.Render(" error: " + m.err))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
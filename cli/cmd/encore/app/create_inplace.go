@@ -0,0 +1,515 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cockroachdb/errors"
+	"github.com/fatih/color"
+	"github.com/tailscale/hujson"
+	"golang.org/x/mod/modfile"
+
+	"encr.dev/cli/cmd/encore/cmdutil"
+)
+
+// Creating an app in place, with --in-place, creates it in an existing
+// directory that may not be empty, such as a subfolder of a monorepo.
+// The template is extracted to a staging directory and then merged into it:
+// existing files are kept, and an existing go.mod or package.json is
+// extended with what the template needs rather than replaced.
+
+// projectFiles are the files of an existing project
+// that the app is wired into when created in place.
+var projectFiles = []string{"go.mod", "package.json"}
+
+// existingProjectFiles reports which of projectFiles exist in dir.
+func existingProjectFiles(dir string) []string {
+	var found []string
+	for _, name := range projectFiles {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			found = append(found, name)
+		}
+	}
+	return found
+}
+
+// checkInPlaceDir reports an error if the app can't be created in dir in place.
+func checkInPlaceDir(dir string) error {
+	if fi, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	} else if !fi.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "encore.app")); err == nil {
+		return fmt.Errorf("directory %s already contains an Encore app", dir)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// mergeTemplate merges the extracted template in src into the existing
+// directory dst. Files that don't exist in dst are copied there, while
+// go.mod, go.sum, package.json and .gitignore at the root are merged
+// with the existing ones. Other files that already exist are kept as is.
+//
+// It returns the paths it created in dst, relative to it,
+// and the template files that were skipped since they already exist.
+func mergeTemplate(src, dst string) (created, skipped []string, err error) {
+	if err := rewriteModulePath(src, dst); err != nil {
+		return nil, nil, err
+	}
+
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == src {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		fi, err := os.Stat(target)
+		if errors.Is(err, fs.ErrNotExist) {
+			if err := copyPath(path, target, d); err != nil {
+				return err
+			}
+			created = append(created, rel)
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			// Merge the contents of directories that exist in both.
+			if fi.IsDir() {
+				return nil
+			}
+			skipped = append(skipped, rel)
+			return filepath.SkipDir
+		}
+		merged, err := mergeProjectFile(rel, path, target)
+		if err != nil {
+			return fmt.Errorf("merge %s: %v", rel, err)
+		} else if !merged {
+			skipped = append(skipped, rel)
+		}
+		return nil
+	})
+	return created, skipped, err
+}
+
+// templateConflicts reports the paths of the extracted template in src, relative to it,
+// that already exist in dst and so would be kept rather than created by mergeTemplate.
+// The project files that are merged, like go.mod, don't conflict.
+func templateConflicts(src, dst string) ([]string, error) {
+	var conflicts []string
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == src {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		fi, err := os.Stat(filepath.Join(dst, rel))
+		if errors.Is(err, fs.ErrNotExist) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if fi.IsDir() {
+				return nil
+			}
+			conflicts = append(conflicts, rel)
+			return filepath.SkipDir
+		}
+		if fi.IsDir() || projectFileMerger(rel) == nil {
+			conflicts = append(conflicts, rel)
+		}
+		return nil
+	})
+	return conflicts, err
+}
+
+// confirmConflicts asks whether to create the app in dir even though the given
+// template files conflict with existing ones, which are kept. If the user can't
+// be prompted it fails with an error listing the conflicting paths instead.
+func confirmConflicts(dir string, conflicts []string) error {
+	if len(conflicts) == 0 {
+		return nil
+	} else if !canPrompt() {
+		return conflictsError(dir, conflicts)
+	}
+
+	yellow := color.New(color.FgYellow)
+	red := color.New(color.FgRed)
+	_, _ = yellow.Fprintf(os.Stderr, "The template has files that already exist in %s:\n", dir)
+	for _, path := range conflicts {
+		_, _ = yellow.Fprintf(os.Stderr, "  %s\n", path)
+	}
+	for {
+		_, _ = color.New(color.FgCyan).Fprint(os.Stderr, "Keep the existing files, skipping the template's? (y/N): ")
+		var input string
+		_, _ = fmt.Scanln(&input)
+		switch strings.TrimSpace(input) {
+		case "Y", "y", "yes":
+			return nil
+		case "N", "n", "no", "", "q", "quit", "exit":
+			return errCreateCancelled
+		default:
+			// Try again.
+			_, _ = red.Fprintln(os.Stderr, "Unexpected answer, please enter 'y' or 'n'.")
+		}
+	}
+}
+
+// conflictsError reports that the template's files conflict with existing files in dir.
+func conflictsError(dir string, conflicts []string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "the template has files that already exist in %s:\n", dir)
+	for _, path := range conflicts {
+		fmt.Fprintf(&b, "  %s\n", path)
+	}
+	b.WriteString("Move or remove them, or run the command interactively to keep them and skip the template's.")
+	return errors.New(b.String())
+}
+
+// copyPath copies the file or directory at src to dst, which doesn't exist.
+func copyPath(src, dst string, d fs.DirEntry) error {
+	if d.IsDir() {
+		return os.CopyFS(dst, os.DirFS(src))
+	}
+	info, err := d.Info()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, info.Mode().Perm())
+}
+
+// projectFileMerger returns the function that merges the template file rel
+// into an existing one, or nil if it's not one of the files that are merged.
+func projectFileMerger(rel string) func(src, dst []byte) ([]byte, error) {
+	switch rel {
+	case "go.mod":
+		return mergeGoMod
+	case "package.json":
+		return mergePackageJSON
+	case "go.sum", ".gitignore":
+		return func(src, dst []byte) ([]byte, error) { return mergeLines(src, dst), nil }
+	default:
+		return nil
+	}
+}
+
+// mergeProjectFile merges the template file src into the existing file dst,
+// if it's one of the files that are merged. It reports whether it was.
+func mergeProjectFile(rel, src, dst string) (bool, error) {
+	merge := projectFileMerger(rel)
+	if merge == nil {
+		return false, nil
+	}
+
+	srcData, err := os.ReadFile(src)
+	if err != nil {
+		return false, err
+	}
+	dstData, err := os.ReadFile(dst)
+	if err != nil {
+		return false, err
+	}
+	data, err := merge(srcData, dstData)
+	if err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(dst, data, 0644)
+}
+
+// rewriteModulePath rewrites the imports of the template's Go module in src
+// to the module of the existing go.mod in dst, if the template and dst
+// both have one.
+func rewriteModulePath(src, dst string) error {
+	from, err := goModulePath(filepath.Join(src, "go.mod"))
+	if err != nil || from == "" {
+		return err
+	}
+	to, err := goModulePath(filepath.Join(dst, "go.mod"))
+	if err != nil || to == "" || to == from {
+		return err
+	}
+	return rewritePlaceholders(src, []string{
+		`"` + from + `/`, `"` + to + `/`,
+		`"` + from + `"`, `"` + to + `"`,
+	})
+}
+
+// goModulePath reports the module path of the go.mod file at path,
+// or "" if there is none.
+func goModulePath(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return modfile.ModulePath(data), nil
+}
+
+// mergeGoMod adds the requirements of the template's go.mod
+// that the existing one doesn't have.
+func mergeGoMod(src, dst []byte) ([]byte, error) {
+	tmpl, err := modfile.ParseLax("go.mod", src, nil)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := modfile.Parse("go.mod", dst, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, req := range tmpl.Require {
+		if !slices.ContainsFunc(existing.Require, func(r *modfile.Require) bool {
+			return r.Mod.Path == req.Mod.Path
+		}) {
+			existing.AddNewRequire(req.Mod.Path, req.Mod.Version, req.Indirect)
+		}
+	}
+	existing.Cleanup()
+	return existing.Format()
+}
+
+// mergePackageJSON adds the fields of the template's package.json
+// that the existing one doesn't have. Fields that are objects in both,
+// such as dependencies and scripts, are merged the same way.
+// The existing package.json keeps its formatting.
+func mergePackageJSON(src, dst []byte) ([]byte, error) {
+	var tmpl map[string]json.RawMessage
+	if err := json.Unmarshal(src, &tmpl); err != nil {
+		return nil, errors.Wrap(err, "parse template package.json")
+	}
+	root, err := hujson.Parse(dst)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := root.Value.(*hujson.Object)
+	if !ok {
+		return nil, errors.New("invalid package.json: not a json object")
+	}
+
+	// Add the fields in a consistent order.
+	keys := make([]string, 0, len(tmpl))
+	for k := range tmpl {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		val := tmpl[key]
+		existing := findMember(obj, key)
+		if existing == nil {
+			obj.Members = append(obj.Members, newMember(key, val))
+			continue
+		}
+
+		var fields map[string]json.RawMessage
+		existingObj, ok := existing.Value.Value.(*hujson.Object)
+		if !ok || json.Unmarshal(val, &fields) != nil {
+			continue
+		}
+		names := make([]string, 0, len(fields))
+		for k := range fields {
+			names = append(names, k)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			if findMember(existingObj, name) == nil {
+				existingObj.Members = append(existingObj.Members, newMember(name, fields[name]))
+			}
+		}
+	}
+
+	root.Format()
+	return root.Pack(), nil
+}
+
+func findMember(obj *hujson.Object, name string) *hujson.ObjectMember {
+	for i := range obj.Members {
+		m := &obj.Members[i]
+		if lit, ok := m.Name.Value.(hujson.Literal); ok && lit.String() == name {
+			return m
+		}
+	}
+	return nil
+}
+
+func newMember(name string, val json.RawMessage) hujson.ObjectMember {
+	key, _ := json.Marshal(name)
+	v, err := hujson.Parse(val)
+	if err != nil {
+		v = hujson.Value{Value: hujson.Literal(val)}
+	}
+	return hujson.ObjectMember{
+		Name:  hujson.Value{Value: hujson.Literal(key)},
+		Value: v,
+	}
+}
+
+// mergeLines appends the lines of src that dst doesn't have.
+func mergeLines(src, dst []byte) []byte {
+	have := make(map[string]bool)
+	for _, ln := range strings.Split(string(dst), "\n") {
+		have[strings.TrimSpace(ln)] = true
+	}
+	var b bytes.Buffer
+	b.Write(dst)
+	if len(dst) > 0 && !bytes.HasSuffix(dst, []byte("\n")) {
+		b.WriteByte('\n')
+	}
+	for _, ln := range strings.Split(string(src), "\n") {
+		if ln := strings.TrimSpace(ln); ln != "" && !have[ln] {
+			b.WriteString(ln + "\n")
+			have[ln] = true
+		}
+	}
+	return b.Bytes()
+}
+
+// snapshotFiles are the files in an existing directory that creating the app
+// in place may modify: the merged project files, and the files that
+// 'go get' and 'npm install' update.
+var snapshotFiles = []string{"go.mod", "go.sum", "package.json", "package-lock.json", ".gitignore"}
+
+// fileSnapshot holds the contents of files before the app is created in place,
+// by their path relative to the directory, so they can be restored if creating
+// the app fails. Files that didn't exist have nil contents.
+type fileSnapshot map[string][]byte
+
+// snapshotProjectFiles snapshots the snapshotFiles in dir,
+// and in the subdirectory appRoot of it if it's not dir itself.
+func snapshotProjectFiles(dir, appRoot string) (fileSnapshot, error) {
+	snap := make(fileSnapshot)
+	for _, sub := range []string{".", appRoot} {
+		for _, name := range snapshotFiles {
+			rel := filepath.Join(sub, name)
+			if _, ok := snap[rel]; ok {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(dir, rel))
+			if errors.Is(err, fs.ErrNotExist) {
+				data, err = nil, nil
+			} else if err != nil {
+				return nil, err
+			} else if data == nil {
+				data = []byte{}
+			}
+			snap[rel] = data
+		}
+	}
+	return snap, nil
+}
+
+// restore writes back the snapshotted files in dir, and removes
+// the ones that didn't exist when the snapshot was taken.
+func (s fileSnapshot) restore(dir string) {
+	for rel, data := range s {
+		path := filepath.Join(dir, rel)
+		if data == nil {
+			_ = os.Remove(path)
+		} else {
+			_ = os.WriteFile(path, data, 0644)
+		}
+	}
+}
+
+// removeCreated removes the paths created in dir by mergeTemplate.
+func removeCreated(dir string, created []string) {
+	for _, rel := range created {
+		_ = os.RemoveAll(filepath.Join(dir, rel))
+	}
+}
+
+// insideGitRepo reports whether dir is in a git repository,
+// such as a subfolder of a monorepo.
+func insideGitRepo(dir string) bool {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
+type confirmDirDone struct{}
+
+type confirmDirDeclined struct{}
+
+// confirmDirModel asks for confirmation before creating the app
+// in place in an existing directory that isn't empty.
+type confirmDirModel struct {
+	dir      string
+	existing []string // the project files in dir, see existingProjectFiles
+}
+
+func (m confirmDirModel) Update(msg tea.Msg) (confirmDirModel, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "y", "Y":
+			return m, func() tea.Msg { return confirmDirDone{} }
+		case "n", "N":
+			return m, func() tea.Msg { return confirmDirDeclined{} }
+		}
+	}
+	return m, nil
+}
+
+func (m confirmDirModel) View() string {
+	var b strings.Builder
+	b.WriteString(cmdutil.InputStyle.Render(fmt.Sprintf("Create the app in %s, which is not empty?", m.dir)))
+	b.WriteString(cmdutil.DescStyle.Render(" (y/n)"))
+	b.WriteByte('\n')
+	if len(m.existing) > 0 {
+		b.WriteString(cmdutil.DescStyle.Render(fmt.Sprintf("The app will use the existing %s.", strings.Join(m.existing, " and "))))
+		b.WriteByte('\n')
+	}
+	b.WriteString(cmdutil.DescStyle.Render("Existing files are kept, skipping the template's files that conflict with them."))
+	b.WriteByte('\n')
+	return b.String()
+}
+
+// doneValue describes the confirmed directory.
+func (m confirmDirModel) doneValue() string {
+	if len(m.existing) == 0 {
+		return m.dir
+	}
+	return m.dir + cmdutil.DescStyle.Render(fmt.Sprintf(" (using the existing %s)", strings.Join(m.existing, " and ")))
+}
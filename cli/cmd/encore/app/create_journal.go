@@ -0,0 +1,166 @@
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"encr.dev/cli/cmd/encore/cmdutil"
+	"encr.dev/cli/cmd/encore/llm_rules"
+	"encr.dev/cli/internal/platform"
+	"encr.dev/internal/conf"
+)
+
+// journalStep is a step of creating an app that's recorded in its journal
+// once it completes.
+type journalStep string
+
+const (
+	journalStepTemplate journalStep = "template" // the template is extracted
+	journalStepPlatform journalStep = "platform" // the app is created on the Encore Platform, if enabled
+	journalStepFiles    journalStep = "files"    // the app's files are set up
+	journalStepDeps     journalStep = "deps"     // encore.dev is updated
+)
+
+// createJournal records the steps of creating an app that have completed,
+// so that a creation that fails halfway, such as when downloading the template
+// or creating the app on the platform fails, can be resumed from the last
+// successful step with 'encore app create --resume'.
+//
+// It's saved in the cache directory rather than the app directory,
+// to keep it out of the app's files, and it's removed once the app is created.
+type createJournal struct {
+	Dir          string            `json:"dir"` // absolute path to the app directory
+	Name         string            `json:"name"`
+	Template     string            `json:"template"`
+	Lang         cmdutil.Language  `json:"lang,omitempty"`
+	LLMRules     llm_rules.Tool    `json:"llmRules,omitempty"`
+	TemplateVars map[string]string `json:"templateVars,omitempty"`
+	Addons       []Addon           `json:"addons,omitempty"`
+	FromOpenAPI  string            `json:"fromOpenAPI,omitempty"`
+	Platform     bool              `json:"platform"`
+	Git          bool              `json:"git"`
+
+	// Example is the template's example config, once it's extracted.
+	Example *exampleConfig `json:"example,omitempty"`
+	// App is the app created on the platform, if any.
+	App *platform.App `json:"app,omitempty"`
+
+	Steps []journalStep `json:"steps,omitempty"`
+
+	path  string // where the journal is saved, or "" if it isn't
+	saved bool   // whether the journal has been saved
+}
+
+// journalPath reports where the journal of creating an app in dir is saved.
+func journalPath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	cache, err := conf.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return filepath.Join(cache, "create-journals", hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// newJournal returns a journal for creating an app in dir.
+// If the journal's path can't be determined it isn't saved,
+// and the creation can't be resumed.
+func newJournal(dir string) *createJournal {
+	j := &createJournal{Dir: dir}
+	if abs, err := filepath.Abs(dir); err == nil {
+		j.Dir = abs
+	}
+	j.path, _ = journalPath(dir)
+	return j
+}
+
+// readJournal reads the journal of an interrupted creation of an app in dir.
+func readJournal(dir string) (*createJournal, error) {
+	path, err := journalPath(dir)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no app creation to resume in %s", dir)
+	} else if err != nil {
+		return nil, err
+	}
+	var j createJournal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("invalid journal %s: %v", path, err)
+	}
+	if _, err := os.Stat(j.Dir); err != nil {
+		return nil, fmt.Errorf("no app creation to resume in %s: %v", dir, err)
+	}
+	j.path, j.saved = path, true
+	return &j, nil
+}
+
+// done reports whether step has completed.
+func (j *createJournal) done(step journalStep) bool {
+	return slices.Contains(j.Steps, step)
+}
+
+// complete records that step has completed.
+// Failing to save the journal isn't fatal, since it's only needed to resume.
+func (j *createJournal) complete(step journalStep) {
+	if !j.done(step) {
+		j.Steps = append(j.Steps, step)
+	}
+	_ = j.save()
+}
+
+// resumable reports whether creating the app can be resumed
+// from the journal, since it has been saved.
+func (j *createJournal) resumable() bool {
+	return j.saved
+}
+
+func (j *createJournal) save() error {
+	if j.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(j.path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(j.path, data, 0600); err != nil {
+		return err
+	}
+	j.saved = true
+	return nil
+}
+
+func (j *createJournal) remove() {
+	if j.path == "" {
+		return
+	}
+	if err := os.Remove(j.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		_, _ = fmt.Fprintf(os.Stderr, "warning: could not remove app creation journal: %v\n", err)
+	}
+}
+
+// restoreFlags sets the flags of 'encore app create' to the ones
+// the app was being created with.
+func (j *createJournal) restoreFlags() {
+	createAppPath = j.Dir
+	createAppParentDir = ""
+	createAppInPlace = false
+	createAppFromOpenAPI = j.FromOpenAPI
+	createAppOnPlatform = j.Platform
+	createAppGit = j.Git
+}
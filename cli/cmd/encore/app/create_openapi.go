@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"encr.dev/cli/cmd/encore/cmdutil"
+	"encr.dev/pkg/openapiscaffold"
+)
+
+// loadOpenAPISpec loads the OpenAPI specification given to --from-openapi,
+// which is a file path or a URL.
+func loadOpenAPISpec(ctx context.Context, location string) (*openapi3.T, error) {
+	if createAppOffline && (strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")) {
+		return nil, fmt.Errorf("cannot fetch OpenAPI specification %s when offline", location)
+	}
+	return openapiscaffold.Load(ctx, location)
+}
+
+// scaffoldOpenAPI generates the services of the OpenAPI specification
+// in the app at appRoot. It returns the operations that were skipped
+// since they couldn't be generated.
+func scaffoldOpenAPI(appRoot string, lang cmdutil.Language, doc *openapi3.T) (skipped []string, err error) {
+	res, err := openapiscaffold.Generate(doc, openAPILang(lang))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(res.Files))
+	for name := range res.Files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(appRoot, filepath.Dir(filepath.FromSlash(name)))); err == nil {
+			return nil, fmt.Errorf("directory %s already exists", filepath.Dir(filepath.FromSlash(name)))
+		}
+	}
+	for _, name := range names {
+		path := filepath.Join(appRoot, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, res.Files[name], 0644); err != nil {
+			return nil, err
+		}
+	}
+	return res.Skipped, nil
+}
+
+// openAPILang returns the language services are generated in for an app in lang.
+func openAPILang(lang cmdutil.Language) openapiscaffold.Lang {
+	if lang == cmdutil.LanguageTS {
+		return openapiscaffold.TypeScript
+	}
+	return openapiscaffold.Go
+}
@@ -0,0 +1,312 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+
+	"encr.dev/cli/cmd/encore/cmdutil"
+	"encr.dev/cli/cmd/encore/llm_rules"
+	"encr.dev/internal/conf"
+	"encr.dev/pkg/github"
+	"encr.dev/pkg/openapiscaffold"
+)
+
+// CreatePlan describes what creating an app would do, without doing it.
+// It's printed by 'encore app create --dry-run', so that tools and editor
+// extensions can show what will happen before creating the app.
+type CreatePlan struct {
+	Name     string           `json:"name"`
+	Lang     cmdutil.Language `json:"lang,omitempty"`
+	Dir      string           `json:"dir"`     // absolute path to the app directory
+	AppRoot  string           `json:"appRoot"` // absolute path to the directory containing encore.app
+	InPlace  bool             `json:"inPlace,omitempty"`
+	Template PlanTemplate     `json:"template"`
+	Files    []PlanFile       `json:"files"`
+	Platform PlanPlatform     `json:"platform"`
+	Git      PlanGit          `json:"git"`
+	LLMRules llm_rules.Tool   `json:"llmRules,omitempty"`
+}
+
+// PlanTemplate is the template an app is created from.
+type PlanTemplate struct {
+	Name   string `json:"name,omitempty"` // empty for the built-in empty Go app
+	Source string `json:"source"`         // "github", "local", "cache" or "builtin"
+	URL    string `json:"url,omitempty"`  // the template's GitHub tree, if from GitHub
+}
+
+// PlanFile is a file in the app directory written when creating the app.
+type PlanFile struct {
+	Path string `json:"path"` // slash-separated, relative to the app directory
+	// Action is "create" for a new file, and when creating the app in place,
+	// "merge" for an existing file that's extended or "keep" for an existing
+	// file that's left as is instead of the template's.
+	Action string `json:"action"`
+}
+
+// PlanPlatform describes whether the app is created on the Encore Platform.
+type PlanPlatform struct {
+	Create bool   `json:"create"`
+	Name   string `json:"name,omitempty"`   // the name the app is registered with
+	Reason string `json:"reason,omitempty"` // why it isn't created, if it isn't
+}
+
+// PlanGit describes how the app's git repository is set up.
+type PlanGit struct {
+	Init     bool     `json:"init"`
+	Reason   string   `json:"reason,omitempty"`   // why it isn't initialized, if it isn't
+	Commands []string `json:"commands,omitempty"` // the commands run in the app directory
+	Remote   string   `json:"remote,omitempty"`   // the provider a repository is created on and pushed to, if any
+}
+
+// planCreateApp reports how an app would be created with the given inputs
+// and flags. The template's files are listed, but it isn't downloaded.
+func planCreateApp(ctx context.Context, name, template string, lang cmdutil.Language, addons []Addon) (*CreatePlan, error) {
+	// Mirror how scaffoldApp treats the empty template.
+	if template == "empty" {
+		template = ""
+	}
+	if template == "" && lang == cmdutil.LanguageTS {
+		template = "ts/empty"
+	}
+
+	dir := appDir(createAppParentDir, createAppPath, name)
+	if err := validateNameForLang(name, lang); err != nil {
+		return nil, err
+	} else if createAppInPlace {
+		if err := checkInPlaceDir(dir); err != nil {
+			return nil, err
+		}
+	} else if dirInUse(dir) {
+		return nil, fmt.Errorf("directory %s already exists and is not empty", dir)
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, files, exCfg, err := planTemplate(ctx, template)
+	if err != nil {
+		return nil, err
+	}
+	plan := &CreatePlan{
+		Name:     name,
+		Lang:     lang,
+		Dir:      absDir,
+		AppRoot:  filepath.Join(absDir, filepath.FromSlash(exCfg.EncoreAppPath)),
+		InPlace:  createAppInPlace,
+		Template: tmpl,
+	}
+
+	appRoot := path.Clean(filepath.ToSlash(exCfg.EncoreAppPath))
+	inAppRoot := func(name string) string { return path.Join(appRoot, name) }
+	if !slices.Contains(files, inAppRoot("encore.app")) {
+		files = append(files, inAppRoot("encore.app"))
+	}
+
+	// The language is detected from the template, like when creating the app.
+	genLang := cmdutil.LanguageGo
+	if slices.Contains(files, inAppRoot("go.mod")) {
+		plan.Lang = cmdutil.LanguageGo
+	} else if slices.Contains(files, inAppRoot("package.json")) {
+		plan.Lang, genLang = cmdutil.LanguageTS, cmdutil.LanguageTS
+	}
+
+	if createAppFromOpenAPI != "" {
+		spec, err := loadOpenAPISpec(ctx, createAppFromOpenAPI)
+		if err != nil {
+			return nil, err
+		}
+		res, err := openapiscaffold.Generate(spec, openAPILang(genLang))
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate services from %s: %v", createAppFromOpenAPI, err)
+		}
+		for name := range res.Files {
+			files = append(files, inAppRoot(name))
+		}
+	}
+	for _, f := range addonFilesFor(genLang, addons) {
+		files = append(files, inAppRoot(path.Join(addonService, f.name)))
+	}
+
+	slices.Sort(files)
+	plan.Files = make([]PlanFile, len(files))
+	for i, f := range files {
+		action := "create"
+		if createAppInPlace {
+			action = inPlaceAction(dir, f)
+		}
+		plan.Files[i] = PlanFile{Path: f, Action: action}
+	}
+
+	_, userErr := conf.CurrentUser()
+	switch {
+	case !createAppOnPlatform:
+		plan.Platform.Reason = "disabled with --platform=false"
+	case createAppOffline:
+		plan.Platform.Reason = "offline"
+	case userErr != nil:
+		plan.Platform.Reason = "not logged in"
+	default:
+		plan.Platform.Create, plan.Platform.Name = true, name
+	}
+
+	plan.Git = planGit(dir, plan.Platform.Create)
+	return plan, nil
+}
+
+// planTemplate reports where the template is created from, its files
+// and its example config. The example config file isn't among the files,
+// since it's removed when creating the app.
+func planTemplate(ctx context.Context, template string) (tmpl PlanTemplate, files []string, cfg exampleConfig, err error) {
+	cfg = exampleConfig{EncoreAppPath: "."}
+	var readConfig func() ([]byte, error)
+	switch {
+	case template == "":
+		return PlanTemplate{Source: "builtin"}, []string{".gitignore", "go.mod"}, cfg, nil
+
+	case isLocalTemplate(template):
+		tmpl = PlanTemplate{Name: template, Source: "local"}
+		files, err = listFiles(template)
+		readConfig = func() ([]byte, error) { return os.ReadFile(exampleJSONPath(template)) }
+
+	case createAppOffline:
+		tmpl = PlanTemplate{Name: template, Source: "cache"}
+		var src string
+		if src, err = cachedSourceDir(template); err != nil {
+			return tmpl, nil, cfg, err
+		} else if _, err := os.Stat(src); err != nil {
+			return tmpl, nil, cfg, fmt.Errorf("template %s is not cached for offline use, run 'encore app prefetch-templates --sources' to cache it", template)
+		}
+		files, err = listFiles(src)
+		readConfig = func() ([]byte, error) { return os.ReadFile(exampleJSONPath(src)) }
+
+	default:
+		var tree *github.Tree
+		if tree, err = parseTemplate(ctx, template); err != nil {
+			return tmpl, nil, cfg, err
+		}
+		tmpl = PlanTemplate{Name: template, Source: "github", URL: treeURL(tree)}
+		files, err = github.ListFiles(ctx, tree)
+		readConfig = func() ([]byte, error) { return github.ReadFile(ctx, tree, exampleJSONName, 64*1024) }
+	}
+	if err != nil {
+		return tmpl, nil, cfg, fmt.Errorf("failed to list the files of template %s: %v", template, err)
+	}
+
+	if idx := slices.Index(files, exampleJSONName); idx >= 0 {
+		files = slices.Delete(files, idx, idx+1)
+		data, err := readConfig()
+		if err != nil {
+			return tmpl, nil, cfg, fmt.Errorf("failed to read example config: %v", err)
+		}
+		if cfg, err = decodeExampleConfig(data); err != nil {
+			return tmpl, nil, cfg, fmt.Errorf("failed to parse example config: %v", err)
+		}
+	}
+	return tmpl, files, cfg, nil
+}
+
+// listFiles lists the regular files in dir, as slash-separated paths relative to it.
+func listFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	return files, err
+}
+
+// treeURL returns the URL of tree on GitHub.
+func treeURL(tree *github.Tree) string {
+	u := fmt.Sprintf("https://github.com/%s/%s/tree/%s", tree.Owner, tree.Repo, tree.Branch)
+	if p := path.Clean(tree.Path); p != "." {
+		u += "/" + p
+	}
+	return u
+}
+
+// inPlaceAction reports what merging the template into dir does with the file rel,
+// as done by mergeTemplate.
+func inPlaceAction(dir, rel string) string {
+	_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(rel)))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return "create"
+	case err == nil && projectFileMerger(rel) != nil:
+		return "merge"
+	default:
+		return "keep"
+	}
+}
+
+// planGit reports how the git repository of the app in dir is set up.
+func planGit(dir string, onPlatform bool) PlanGit {
+	var g PlanGit
+	switch {
+	case !createAppGit:
+		g.Reason = "disabled with --git=false"
+		return g
+	case createAppInPlace && insideGitRepo(dir):
+		g.Reason = "the app directory is inside a git repository"
+		return g
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		g.Reason = "git is not installed"
+		return g
+	}
+
+	g.Init = true
+	g.Commands = []string{
+		"git init",
+		"git config --local push.default current",
+		"git add -A",
+		`git commit -m "Initial commit"`,
+	}
+	if onPlatform {
+		// The app ID is only known once the app is created on the platform.
+		g.Commands = append(g.Commands, fmt.Sprintf("git remote add %s %s<app-id>", defaultGitRemoteName, defaultGitRemoteURL))
+	}
+	// Repositories are only created when asked to with --git-remote,
+	// since the plan doesn't prompt for it.
+	if v := createAppGitRemote.Value; v != "" && v != "none" && !createAppInPlace && !createAppOffline {
+		g.Remote = v
+	}
+	return g
+}
+
+// dryRunCreateApp implements "encore app create --dry-run".
+// It prints the plan for creating the app as JSON, without prompting for anything.
+func dryRunCreateApp(ctx context.Context, name, template string, lang cmdutil.Language, llmRules llm_rules.Tool) {
+	createAppYes = true
+	addons := createAppAddons
+	if name == "" || template == "" || llmRules == "" {
+		name, template, lang, llmRules, _, addons = createAppForm(name, template, lang, "", llmRules, false)
+	}
+
+	plan, err := planCreateApp(ctx, name, template, lang, addons)
+	if err != nil {
+		cmdutil.Fatal(err)
+	}
+	plan.LLMRules = llmRules
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(plan); err != nil {
+		cmdutil.Fatal(err)
+	}
+}
@@ -0,0 +1,288 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/fatih/color"
+)
+
+// gitProvider is a git hosting provider that a repository
+// can be created on for a new app, to push the app to.
+type gitProvider string
+
+const (
+	gitProviderGitHub gitProvider = "github"
+	gitProviderGitLab gitProvider = "gitlab"
+)
+
+func (p gitProvider) Display() string {
+	switch p {
+	case gitProviderGitHub:
+		return "GitHub"
+	case gitProviderGitLab:
+		return "GitLab"
+	default:
+		return string(p)
+	}
+}
+
+// remoteRepo is a repository created on a git provider.
+type remoteRepo struct {
+	WebURL   string // the repository's page
+	CloneURL string // the https URL to clone it with
+}
+
+// repoHost creates repositories on a git provider's API.
+type repoHost struct {
+	provider gitProvider
+	apiURL   string // the base URL of the API
+	token    string
+}
+
+// newRepoHost returns the host of provider, authenticated with
+// the token from the environment or the provider's CLI.
+func newRepoHost(provider gitProvider) (*repoHost, error) {
+	h := &repoHost{provider: provider}
+	switch provider {
+	case gitProviderGitHub:
+		h.apiURL = "https://api.github.com"
+		h.token = envToken("GH_TOKEN", "GITHUB_TOKEN")
+		if h.token == "" {
+			h.token = cliToken("gh", "auth", "token")
+		}
+	case gitProviderGitLab:
+		host := "gitlab.com"
+		if env := os.Getenv("GITLAB_HOST"); env != "" {
+			host = strings.TrimPrefix(strings.TrimPrefix(env, "https://"), "http://")
+		}
+		h.apiURL = "https://" + strings.TrimSuffix(host, "/") + "/api/v4"
+		h.token = envToken("GITLAB_TOKEN")
+		if h.token == "" {
+			h.token = cliToken("glab", "config", "get", "token", "--host", host)
+		}
+	default:
+		return nil, fmt.Errorf("unknown git provider %q", provider)
+	}
+
+	if h.token == "" {
+		switch provider {
+		case gitProviderGitHub:
+			return nil, errors.New("no GitHub token found: set GITHUB_TOKEN or log in with 'gh auth login'")
+		default:
+			return nil, errors.New("no GitLab token found: set GITLAB_TOKEN or log in with 'glab auth login'")
+		}
+	}
+	return h, nil
+}
+
+func envToken(keys ...string) string {
+	for _, k := range keys {
+		if v := strings.TrimSpace(os.Getenv(k)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// cliToken runs the provider's CLI to get its token,
+// returning "" if it's not installed or not logged in.
+func cliToken(name string, args ...string) string {
+	if _, err := exec.LookPath(name); err != nil {
+		return ""
+	}
+	out, err := exec.Command(name, args...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// createRepo creates a private repository with the given name,
+// owned by the authenticated user.
+func (h *repoHost) createRepo(ctx context.Context, name string) (*remoteRepo, error) {
+	var (
+		path string
+		body any
+	)
+	switch h.provider {
+	case gitProviderGitHub:
+		path, body = "/user/repos", map[string]any{"name": name, "private": true}
+	default:
+		path, body = "/projects", map[string]any{"name": name, "path": name, "visibility": "private"}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", h.apiURL+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.provider == gitProviderGitHub {
+		req.Header.Set("Authorization", "Bearer "+h.token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+	} else {
+		req.Header.Set("PRIVATE-TOKEN", h.token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "send request")
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusCreated {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("create %s repository %s: %s: %s", h.provider.Display(), name, resp.Status, bytes.TrimSpace(msg))
+	}
+
+	var repo struct {
+		HTMLURL       string `json:"html_url"`         // GitHub
+		CloneURL      string `json:"clone_url"`        // GitHub
+		WebURL        string `json:"web_url"`          // GitLab
+		HTTPURLToRepo string `json:"http_url_to_repo"` // GitLab
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&repo); err != nil {
+		return nil, errors.Wrap(err, "decode response")
+	}
+	if h.provider == gitProviderGitHub {
+		return &remoteRepo{WebURL: repo.HTMLURL, CloneURL: repo.CloneURL}, nil
+	}
+	return &remoteRepo{WebURL: repo.WebURL, CloneURL: repo.HTTPURLToRepo}, nil
+}
+
+// authURL returns the clone URL of repo with the token as its credentials.
+func (h *repoHost) authURL(repo *remoteRepo) (string, error) {
+	u, err := url.Parse(repo.CloneURL)
+	if err != nil {
+		return "", err
+	}
+	user := "x-access-token"
+	if h.provider == gitProviderGitLab {
+		user = "oauth2"
+	}
+	u.User = url.UserPassword(user, h.token)
+	return u.String(), nil
+}
+
+// pushToRepo adds repo as the origin remote of the git repository in dir,
+// and pushes the current branch to it.
+//
+// The branch is pushed using the token, which isn't stored in the remote,
+// so the user's own git credentials are used afterwards.
+func (h *repoHost) pushToRepo(dir string, repo *remoteRepo) error {
+	git := func(args ...string) error {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			// Don't leak the token in the error.
+			msg := strings.ReplaceAll(string(bytes.TrimSpace(out)), h.token, "***")
+			return fmt.Errorf("git %s: %s (%v)", args[0], msg, err)
+		}
+		return nil
+	}
+
+	branch, err := exec.Command("git", "-C", dir, "symbolic-ref", "--short", "HEAD").Output()
+	if err != nil {
+		return fmt.Errorf("determine the current branch: %v", err)
+	}
+	b := strings.TrimSpace(string(branch))
+
+	authURL, err := h.authURL(repo)
+	if err != nil {
+		return err
+	}
+	if err := git("remote", "add", "origin", repo.CloneURL); err != nil {
+		return err
+	}
+	if err := git("push", authURL, "HEAD:refs/heads/"+b); err != nil {
+		return err
+	}
+	// Track the pushed branch, without fetching it again.
+	if err := git("update-ref", "refs/remotes/origin/"+b, "HEAD"); err != nil {
+		return err
+	}
+	return git("branch", "--set-upstream-to=origin/"+b)
+}
+
+// hasGitRemote reports whether the git repository in dir has the given remote.
+func hasGitRemote(dir, name string) bool {
+	return exec.Command("git", "-C", dir, "remote", "get-url", name).Run() == nil
+}
+
+// setupRemoteRepo creates a repository for the app on provider
+// and pushes the app's initial commit to it.
+func setupRemoteRepo(dir, name string, provider gitProvider) (*remoteRepo, error) {
+	if hasGitRemote(dir, "origin") {
+		return nil, errors.New("the git repository already has an origin remote")
+	}
+	host, err := newRepoHost(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	repo, err := host.createRepo(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := host.pushToRepo(dir, repo); err != nil {
+		return repo, fmt.Errorf("push to %s: %v", repo.WebURL, err)
+	}
+	return repo, nil
+}
+
+// promptGitProvider asks the user whether to create a repository for the app,
+// and on which provider. It returns "" if not.
+func promptGitProvider() gitProvider {
+	if !canPrompt() {
+		return ""
+	}
+
+	cyan := color.New(color.FgCyan)
+	red := color.New(color.FgRed)
+	for {
+		_, _ = cyan.Fprint(os.Stderr, "Create a repository for the app and push it? (github/gitlab/N): ")
+		var input string
+		_, _ = fmt.Scanln(&input)
+		switch strings.ToLower(strings.TrimSpace(input)) {
+		case "github", "gh":
+			return gitProviderGitHub
+		case "gitlab", "gl":
+			return gitProviderGitLab
+		case "n", "no", "", "q", "quit", "exit":
+			return ""
+		default:
+			// Try again.
+			_, _ = red.Fprintln(os.Stderr, "Unexpected answer, please enter 'github', 'gitlab' or 'n'.")
+		}
+	}
+}
+
+// gitRemoteFlagValues are the values accepted by --git-remote.
+var gitRemoteFlagValues = []string{string(gitProviderGitHub), string(gitProviderGitLab), "none"}
+
+// selectedGitProvider reports the provider to create the app's repository on,
+// given by --git-remote or otherwise prompted for. It returns "" for none.
+func selectedGitProvider() gitProvider {
+	switch createAppGitRemote.Value {
+	case "":
+		return promptGitProvider()
+	case "none":
+		return ""
+	default:
+		return gitProvider(createAppGitRemote.Value)
+	}
+}
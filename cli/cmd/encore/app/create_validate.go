@@ -0,0 +1,109 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"encr.dev/cli/cmd/encore/cmdutil"
+)
+
+// ValidationResult is the result of validating one of the inputs for creating an app.
+type ValidationResult struct {
+	Field string `json:"field"` // "name", "lang" or "template"
+	Value string `json:"value"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// ValidateCreateInputs validates the inputs for creating an app,
+// without prompting for anything or creating any files.
+// Only the inputs that are given are validated.
+//
+// Templates given by name are checked against the template manifest,
+// which is fetched if it isn't cached, and the manifests of the given
+// custom template sources. Templates given by URL are only validated
+// when the app is created.
+func ValidateCreateInputs(name string, lang cmdutil.Language, template string, templateSources []string) []ValidationResult {
+	var results []ValidationResult
+	check := func(field, value string, err error) {
+		res := ValidationResult{Field: field, Value: value, OK: err == nil}
+		if err != nil {
+			res.Error = err.Error()
+		}
+		results = append(results, res)
+	}
+
+	langOK := lang == ""
+	if lang != "" {
+		var err error
+		if langs := languageFlagValues(); slices.Contains(langs, string(lang)) {
+			langOK = true
+		} else {
+			err = fmt.Errorf("unsupported language, must be one of %v", langs)
+		}
+		check("lang", string(lang), err)
+	}
+
+	if name != "" {
+		err := incrementalValidateNameInput(name)
+		if err == nil && langOK {
+			err = validateNameForLang(name, lang)
+		}
+		check("name", name, err)
+	}
+
+	if template != "" && template != "empty" && isTemplateName(template) {
+		check("template", template, validateTemplateName(template, lang, templateSources))
+	}
+
+	return results
+}
+
+// validateTemplateName checks that the template with the given name exists,
+// either in Encore's manifests or in the given custom template sources,
+// and that it's for the given language, if any.
+func validateTemplateName(template string, lang cmdutil.Language, templateSources []string) error {
+	var custom []templateSource
+	if len(templateSources) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		var err error
+		if custom, err = loadTemplateSources(ctx, templateSources); err != nil {
+			return err
+		}
+	}
+
+	exists, known := templateExists(template, custom)
+	switch {
+	case !known:
+		return fmt.Errorf("couldn't load the list of templates")
+	case !exists:
+		return fmt.Errorf("template not found")
+	}
+	if it, ok := findTemplate(template); ok && lang != "" && it.Lang != "" && it.Lang != lang {
+		return fmt.Errorf("template is for %s, not %s", it.Lang.Display(), lang.Display())
+	}
+	return nil
+}
+
+// validateCreateInputs implements "encore app create --validate-only".
+// It prints the results as JSON and exits with a non-zero status
+// if any input is invalid.
+func validateCreateInputs(name string, lang cmdutil.Language, template string, templateSources []string) {
+	results := ValidateCreateInputs(name, lang, template, templateSources)
+	if results == nil {
+		results = []ValidationResult{}
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(results); err != nil {
+		cmdutil.Fatal(err)
+	}
+	if slices.ContainsFunc(results, func(r ValidationResult) bool { return !r.OK }) {
+		os.Exit(1)
+	}
+}
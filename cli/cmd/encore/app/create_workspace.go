@@ -0,0 +1,245 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/tailscale/hujson"
+
+	"encr.dev/cli/cmd/encore/cmdutil"
+	"encr.dev/cli/cmd/encore/llm_rules"
+	"encr.dev/pkg/xos"
+)
+
+// workspaceManifest describes a workspace of related apps, such as an API,
+// a worker and a frontend, that are created together with
+// 'encore app create --workspace'. It's a JSON file, which may contain comments:
+//
+//	{
+//	  "name": "shop",
+//	  "org": "acme",
+//	  "secrets": ["StripeSecretKey"],
+//	  "apps": [
+//	    {"name": "api", "lang": "go", "template": "hello-world", "addons": ["sqldb"]},
+//	    {"name": "worker", "lang": "go", "template": "empty", "addons": ["pubsub", "cron"]},
+//	    {"name": "frontend", "lang": "ts"}
+//	  ]
+//	}
+type workspaceManifest struct {
+	Name string `json:"name"` // the name of the workspace directory
+	// Org is the org the apps are created in on the Encore Platform,
+	// or the user's own if empty.
+	Org string `json:"org"`
+	// Secrets are the names of the secrets the apps share,
+	// which are listed in the workspace's README.
+	Secrets []string       `json:"secrets"`
+	Apps    []workspaceApp `json:"apps"`
+}
+
+// workspaceApp is an app of a workspace.
+type workspaceApp struct {
+	Name     string           `json:"name"`
+	Lang     cmdutil.Language `json:"lang"`
+	Template string           `json:"template"` // defaults to the language's default template
+	Addons   []Addon          `json:"addons"`
+}
+
+// readWorkspaceManifest reads and validates the workspace manifest at path.
+func readWorkspaceManifest(path string) (*workspaceManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err = hujson.Standardize(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workspace manifest %s: %v", path, err)
+	}
+	var m workspaceManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid workspace manifest %s: %v", path, err)
+	}
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("invalid workspace manifest %s: %v", path, err)
+	}
+	return &m, nil
+}
+
+func (m *workspaceManifest) validate() error {
+	if err := validateName(m.Name); err != nil {
+		return fmt.Errorf("workspace name: %v", err)
+	} else if len(m.Apps) == 0 {
+		return fmt.Errorf("no apps")
+	}
+
+	var names []string
+	for _, app := range m.Apps {
+		if slices.Contains(names, app.Name) {
+			return fmt.Errorf("app %q: listed more than once", app.Name)
+		}
+		names = append(names, app.Name)
+
+		if app.Lang != "" && !slices.Contains(languageFlagValues(), string(app.Lang)) {
+			return fmt.Errorf("app %q: unsupported language %q, must be one of %v", app.Name, app.Lang, languageFlagValues())
+		} else if err := validateNameForLang(app.Name, app.Lang); err != nil {
+			return fmt.Errorf("app %q: %v", app.Name, err)
+		}
+		for _, a := range app.Addons {
+			if !slices.Contains(allAddons, a) {
+				return fmt.Errorf("app %q: unknown add-on %q, must be one of: %s", app.Name, a, strings.Join(addonFlagValues(), ", "))
+			}
+		}
+	}
+	for _, s := range m.Secrets {
+		if !isSecretName(s) {
+			return fmt.Errorf("invalid secret name %q, must be a valid identifier", s)
+		}
+	}
+	return nil
+}
+
+// isSecretName reports whether name can be used as the name of a secret,
+// which is a field of the secrets struct in Go apps.
+func isSecretName(name string) bool {
+	for i, r := range name {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return name != ""
+}
+
+// createWorkspace implements "encore app create --workspace".
+// It creates the apps of the workspace manifest at manifestPath in
+// a directory named after the workspace, with a top-level README.
+//
+// Apps that already exist in the workspace directory are skipped,
+// so running it again after creating an app failed creates the rest.
+func createWorkspace(ctx context.Context, manifestPath string, llmRules llm_rules.Tool) error {
+	m, err := readWorkspaceManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	dir := appDir(createAppParentDir, createAppPath, m.Name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	if !createAppOffline {
+		promptAccountCreation()
+	}
+
+	// The apps are created like with --yes, in a single git repository
+	// for the whole workspace rather than one for each app.
+	git := createAppGit
+	createAppYes, createAppGit, createAppInPlace = true, false, false
+	createAppParentDir, createAppPath, createAppOrg = dir, "", m.Org
+
+	yellow := color.New(color.FgYellow)
+	var apps []*createdApp
+	for _, wsApp := range m.Apps {
+		appPath := filepath.Join(dir, wsApp.Name)
+		if _, err := os.Stat(filepath.Join(appPath, "encore.app")); err == nil {
+			_, _ = yellow.Printf("App %s already exists, skipping it.\n", wsApp.Name)
+			apps = append(apps, &createdApp{Name: wsApp.Name, Lang: detectLang(appPath), RunDir: appPath})
+			continue
+		}
+
+		template := wsApp.Template
+		if template == "" {
+			template = defaultTemplateSlugs[wsApp.Lang]
+		}
+		createAppAddons = wsApp.Addons
+		if createAppAddons == nil {
+			createAppAddons = []Addon{}
+		}
+		app, err := scaffoldApp(ctx, wsApp.Name, template, wsApp.Lang, "", llmRules)
+		if err != nil {
+			return fmt.Errorf("create app %s of workspace %s: %w", wsApp.Name, m.Name, err)
+		}
+		apps = append(apps, app)
+	}
+
+	readme := filepath.Join(dir, "README.md")
+	if _, err := os.Stat(readme); err != nil {
+		if err := xos.WriteFile(readme, []byte(workspaceReadme(m, apps)), 0644); err != nil {
+			return err
+		}
+	}
+
+	var repo *remoteRepo
+	if git && insideGitRepo(filepath.Dir(dir)) {
+		fmt.Println("Note: the workspace directory is inside a git repository, skipping git init.")
+	} else if git {
+		if err := initGitRepo(dir, nil); err != nil {
+			return err
+		}
+		if !createAppOffline {
+			if provider := selectedGitProvider(); provider != "" {
+				if repo, err = setupRemoteRepo(dir, m.Name, provider); err != nil {
+					red := color.New(color.FgRed)
+					_, _ = red.Printf("Failed setting up the %s repository, skipping: %v\n", provider.Display(), err)
+				}
+			}
+		}
+	}
+
+	cyan := color.New(color.FgCyan)
+	green := color.New(color.FgGreen)
+	cmdutil.ClearTerminalExceptFirstNLines(0)
+	_, _ = green.Printf("Successfully created workspace %s with %d apps:\n\n", m.Name, len(apps))
+	for _, app := range apps {
+		_, _ = cyan.Printf("    %s\n", app.Name)
+		fmt.Printf("        cd %s && encore run\n\n", app.RunDir)
+	}
+	if repo != nil {
+		fmt.Printf("Repo: %s\n", cyan.Sprint(repo.WebURL))
+	}
+	return nil
+}
+
+// workspaceReadme returns the top-level README of the workspace,
+// describing its apps and the conventions they share.
+func workspaceReadme(m *workspaceManifest, apps []*createdApp) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", m.Name)
+	fmt.Fprintf(&b, "This workspace contains the Encore apps of %s", m.Name)
+	if m.Org != "" {
+		fmt.Fprintf(&b, ", which belong to the %s org on Encore Cloud", m.Org)
+	}
+	b.WriteString(".\n\n")
+
+	b.WriteString("| App | Language | Directory |\n| --- | --- | --- |\n")
+	for _, app := range apps {
+		fmt.Fprintf(&b, "| %s | %s | [%s](./%s) |\n", app.Name, app.Lang.Display(), app.Name, app.Name)
+	}
+
+	b.WriteString("\n## Running the apps\n\n")
+	b.WriteString("Each app is run from its own directory. To run several at once, give each its own port:\n\n")
+	b.WriteString("```shell\n")
+	for i, app := range apps {
+		if i == 0 {
+			fmt.Fprintf(&b, "cd %s && encore run\n", app.Name)
+		} else {
+			fmt.Fprintf(&b, "cd %s && encore run --port %d\n", app.Name, 4000+i)
+		}
+	}
+	b.WriteString("```\n")
+
+	if len(m.Secrets) > 0 {
+		b.WriteString("\n## Secrets\n\n")
+		b.WriteString("The apps use the same names for the secrets they share:\n\n")
+		for _, s := range m.Secrets {
+			fmt.Fprintf(&b, "- `%s`\n", s)
+		}
+		b.WriteString("\nSet them for each app that uses them, from the app's directory:\n\n")
+		fmt.Fprintf(&b, "```shell\nencore secret set --type dev,local %s\n```\n", m.Secrets[0])
+	}
+	return b.String()
+}
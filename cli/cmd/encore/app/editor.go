@@ -0,0 +1,125 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"encr.dev/cli/internal/telemetry"
+)
+
+// createAppEditor is the editor to open the app in after creation.
+// It's empty if not requested, and "auto" to use $VISUAL or $EDITOR.
+var createAppEditor string
+
+func init() {
+	createAppCmd.Flags().StringVar(&createAppEditor, "open", "", "Open the app in an editor after creation, such as 'code', 'goland' or 'cursor' (defaults to $VISUAL or $EDITOR)")
+	createAppCmd.Flags().Lookup("open").NoOptDefVal = "auto"
+}
+
+// guiEditors are editors that open in their own window, and are therefore
+// started in the background rather than taking over the terminal.
+var guiEditors = map[string]bool{
+	"code":     true,
+	"cursor":   true,
+	"windsurf": true,
+	"zed":      true,
+	"subl":     true,
+	"goland":   true,
+	"webstorm": true,
+	"idea":     true,
+	"fleet":    true,
+}
+
+// defaultEditor reports the editor command configured by the environment,
+// or "" if none is configured.
+func defaultEditor() string {
+	for _, key := range []string{"VISUAL", "EDITOR"} {
+		if ed := strings.TrimSpace(os.Getenv(key)); ed != "" {
+			return ed
+		}
+	}
+	return ""
+}
+
+// maybeOpenEditor opens the app in an editor if requested with --open,
+// or, in interactive mode, if the user accepts the prompt to do so.
+// Failing to open the editor is not fatal.
+func (c *createdApp) maybeOpenEditor() {
+	editor := createAppEditor
+	if editor == "auto" {
+		if editor = defaultEditor(); editor == "" {
+			_, _ = color.New(color.FgYellow).Fprintln(os.Stderr, "Note: --open was given but neither $VISUAL nor $EDITOR is set, skipping.")
+			return
+		}
+	} else if editor == "" {
+		// Not explicitly requested; offer to open the app in the default editor.
+		editor = defaultEditor()
+		if editor == "" || !promptOpenEditor(editor) {
+			return
+		}
+	}
+
+	if err := openEditor(editor, c.AppRoot); err != nil {
+		_, _ = color.New(color.FgYellow).Fprintf(os.Stderr, "Note: could not open the app in %s: %v\n", editor, err)
+	}
+}
+
+func promptOpenEditor(editor string) bool {
+	if !canPrompt() {
+		return false
+	}
+
+	cyan := color.New(color.FgCyan)
+	red := color.New(color.FgRed)
+	for {
+		_, _ = cyan.Fprintf(os.Stderr, "Open your app in %s? (y/N): ", editor)
+		var input string
+		_, _ = fmt.Scanln(&input)
+		input = strings.TrimSpace(input)
+		switch input {
+		case "Y", "y", "yes":
+			telemetry.Send("app.create.editor", map[string]any{"response": true})
+			return true
+		case "N", "n", "no", "", "q", "quit", "exit":
+			telemetry.Send("app.create.editor", map[string]any{"response": false})
+			return false
+		default:
+			// Try again.
+			_, _ = red.Fprintln(os.Stderr, "Unexpected answer, please enter 'y' or 'n'.")
+		}
+	}
+}
+
+// openEditor opens dir in the given editor command, which may include arguments
+// (like "code --new-window").
+//
+// Editors that open their own window are started in the background.
+// Other editors (like vim) take over the terminal until they exit.
+func openEditor(editor, dir string) error {
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		return fmt.Errorf("no editor command given")
+	}
+	path, err := exec.LookPath(fields[0])
+	if err != nil {
+		return fmt.Errorf("editor not found: %v", err)
+	}
+
+	cmd := exec.Command(path, append(fields[1:], dir)...)
+	cmd.Dir = dir
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if guiEditors[name] {
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		return cmd.Process.Release()
+	}
+
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
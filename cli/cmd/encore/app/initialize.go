@@ -80,9 +80,9 @@ func initializeApp(name string) error {
 	cyan := color.New(color.FgCyan)
 	promptAccountCreation()
 
-	name, _, lang, _ := createAppForm(name, "", cmdutil.Language(initAppLang.Value), llm_rules.LLMRulesToolNone, true)
+	name, _, lang, _, _, _ := createAppForm(name, "", cmdutil.Language(initAppLang.Value), "", llm_rules.LLMRulesToolNone, true)
 
-	if err := validateName(name); err != nil {
+	if err := validateNameForLang(name, lang); err != nil {
 		return err
 	}
 
@@ -90,7 +90,7 @@ func initializeApp(name string) error {
 	appSlugComments := ""
 	// Create the app on the server.
 	if _, err := conf.CurrentUser(); err == nil {
-		s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+		s := spinner.New(cmdutil.SpinnerCharSet(), 100*time.Millisecond)
 		s.Prefix = "Creating app on encore.dev "
 		s.Start()
 
@@ -122,7 +122,7 @@ func initializeApp(name string) error {
 	// Update to latest encore.dev release
 	if _, err := os.Stat("go.mod"); err == nil {
 		lang = cmdutil.LanguageGo
-		s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+		s := spinner.New(cmdutil.SpinnerCharSet(), 100*time.Millisecond)
 		s.Prefix = "Running go get encore.dev@latest"
 		s.Start()
 		if err := gogetEncore("."); err != nil {
@@ -131,7 +131,7 @@ func initializeApp(name string) error {
 		s.Stop()
 	} else if _, err := os.Stat("package.json"); err == nil {
 		lang = cmdutil.LanguageTS
-		s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+		s := spinner.New(cmdutil.SpinnerCharSet(), 100*time.Millisecond)
 		s.Prefix = "Running npm install encore.dev@latest"
 		s.Start()
 		if err := npmInstallEncore("."); err != nil {
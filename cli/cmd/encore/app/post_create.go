@@ -0,0 +1,162 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+
+	"encr.dev/cli/cmd/encore/cmdutil"
+)
+
+// maxPostCreateSize is the maximum size of post-create instructions fetched from a URL.
+const maxPostCreateSize = 64 * 1024
+
+// findTemplate finds the manifest entry for the given template,
+// preferring the cached manifests to avoid network requests.
+func findTemplate(template string) (templateItem, bool) {
+	for _, url := range []string{templatesURL, tutorialsURL} {
+		items, err := readCachedManifest(url)
+		if err != nil {
+			items, _ = fetchTemplateManifest(url)
+		}
+		for _, it := range items {
+			if it.Template == template {
+				return it, true
+			}
+		}
+	}
+	return templateItem{}, false
+}
+
+// printPostCreate prints the template's post-create instructions, if any.
+// Failing to fetch them is not an error, since they're not essential.
+func printPostCreate(template string) {
+	it, ok := findTemplate(template)
+	if !ok || it.PostCreate == "" {
+		return
+	}
+
+	text := it.PostCreate
+	if strings.HasPrefix(text, "https://") || strings.HasPrefix(text, "http://") {
+		var err error
+		if text, err = fetchPostCreate(text); err != nil {
+			return
+		}
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+
+	width := 80
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+		width = min(w, 100)
+	}
+	fmt.Println()
+	fmt.Println(cmdutil.InputStyle.Bold(true).Render("Before you start:"))
+	fmt.Print(renderInstructions(text, width-2))
+	fmt.Println()
+}
+
+// fetchPostCreate fetches post-create instructions from url.
+func fetchPostCreate(url string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPostCreateSize))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// renderInstructions renders markdown or plain text instructions for the terminal,
+// wrapping text to the given width. Headings are emphasized, list items are
+// wrapped with a hanging indent, and code blocks are left as-is.
+func renderInstructions(text string, width int) string {
+	heading := lipgloss.NewStyle().Bold(true)
+	code := cmdutil.DescStyle
+
+	var b strings.Builder
+	inCode := false
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			inCode = !inCode
+			continue
+		case inCode:
+			b.WriteString("    " + code.Render(line) + "\n")
+			continue
+		case trimmed == "":
+			b.WriteString("\n")
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			b.WriteString("  " + heading.Render(strings.TrimSpace(strings.TrimLeft(trimmed, "#"))) + "\n")
+			continue
+		}
+
+		// Wrap list items with a hanging indent, so they stay readable.
+		indent := "  " + line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		marker := listMarker(trimmed)
+		for i, l := range wrapWords(strings.TrimPrefix(trimmed, marker), width-len(indent)-len(marker)) {
+			if i == 0 {
+				b.WriteString(indent + marker + l + "\n")
+			} else {
+				b.WriteString(indent + strings.Repeat(" ", len(marker)) + l + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// listMarker returns the list marker (like "- " or "1. ") the line starts with, if any.
+func listMarker(line string) string {
+	if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") {
+		return line[:2]
+	}
+	if i := strings.Index(line, ". "); i > 0 && i <= 3 && strings.Trim(line[:i], "0123456789") == "" {
+		return line[:i+2]
+	}
+	return ""
+}
+
+// wrapWords wraps text into lines of at most width characters,
+// breaking at spaces. Words longer than width are kept whole.
+func wrapWords(text string, width int) []string {
+	width = max(width, 20)
+	var lines []string
+	var curr strings.Builder
+	for _, word := range strings.Fields(text) {
+		if curr.Len() > 0 && curr.Len()+1+len(word) > width {
+			lines = append(lines, curr.String())
+			curr.Reset()
+		}
+		if curr.Len() > 0 {
+			curr.WriteByte(' ')
+		}
+		curr.WriteString(word)
+	}
+	if curr.Len() > 0 {
+		lines = append(lines, curr.String())
+	}
+	return lines
+}
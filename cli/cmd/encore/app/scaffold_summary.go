@@ -0,0 +1,161 @@
+package app
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"encr.dev/cli/cmd/encore/cmdutil"
+)
+
+// maxSummaryEntries is the number of top-level entries to list in the summary.
+const maxSummaryEntries = 8
+
+// scaffoldSummary summarizes the files created when scaffolding an app.
+type scaffoldSummary struct {
+	Files int
+	Dirs  int
+
+	// Entries are the top-level entries, with directories suffixed by "/".
+	Entries []string
+
+	// Notable are notable files, such as the app config and entrypoint.
+	Notable []notableFile
+}
+
+type notableFile struct {
+	Path string // relative to the app root, using forward slashes
+	Desc string
+}
+
+// notableFiles are the top-level files worth pointing out, in display order.
+var notableFiles = []notableFile{
+	{"encore.app", "App configuration"},
+	{"go.mod", "Go module"},
+	{"package.json", "Dependencies and scripts"},
+	{"README.md", "Template documentation"},
+}
+
+// skippedDirs are directories not created by the template, or not interesting to the user.
+var skippedDirs = map[string]bool{
+	".git":         true,
+	".encore":      true,
+	"encore.gen":   true,
+	"node_modules": true,
+}
+
+// summarizeScaffold summarizes the files in the app at appRoot.
+func summarizeScaffold(appRoot string) (*scaffoldSummary, error) {
+	s := &scaffoldSummary{}
+	var topDirs, topFiles []string
+	var entrypoint string
+	err := filepath.WalkDir(appRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		} else if path == appRoot {
+			return nil
+		}
+		rel, err := filepath.Rel(appRoot, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		topLevel := !strings.Contains(rel, "/")
+
+		if d.IsDir() {
+			if skippedDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			s.Dirs++
+			if topLevel {
+				topDirs = append(topDirs, rel+"/")
+			}
+			return nil
+		}
+
+		s.Files++
+		if topLevel {
+			topFiles = append(topFiles, rel)
+		}
+		if entrypoint == "" && isEntrypoint(path) {
+			entrypoint = rel
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range notableFiles {
+		if _, err := os.Stat(filepath.Join(appRoot, f.Path)); err == nil {
+			s.Notable = append(s.Notable, f)
+		}
+	}
+	if entrypoint != "" {
+		s.Notable = append(s.Notable, notableFile{entrypoint, "Entrypoint"})
+	}
+
+	// List directories first, as they typically hold the services.
+	s.Entries = append(topDirs, topFiles...)
+	return s, nil
+}
+
+// isEntrypoint reports whether the file at path defines an Encore service,
+// which makes it a good place to start reading the app.
+func isEntrypoint(path string) bool {
+	switch {
+	case filepath.Base(path) == "encore.service.ts":
+		return true
+	case strings.HasSuffix(path, ".go") && !strings.HasSuffix(path, "_test.go"):
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return false
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.HasPrefix(line, "//encore:api") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// String renders the summary for display after the app has been created.
+func (s *scaffoldSummary) String() string {
+	var b strings.Builder
+	b.WriteString(cmdutil.SuccessStyle.Render(fmt.Sprintf("Created %s in %s", plural(s.Files, "file"), plural(s.Dirs, "directory"))))
+	b.WriteByte('\n')
+
+	entries := s.Entries
+	if len(entries) > maxSummaryEntries {
+		entries = entries[:maxSummaryEntries]
+	}
+	line := strings.Join(entries, "  ")
+	if more := len(s.Entries) - len(entries); more > 0 {
+		line += cmdutil.DescStyle.Render(fmt.Sprintf("  (+%d more)", more))
+	}
+	fmt.Fprintf(&b, "  %s\n", line)
+
+	if len(s.Notable) > 0 {
+		width := 0
+		for _, f := range s.Notable {
+			width = max(width, len(f.Path))
+		}
+		b.WriteByte('\n')
+		for _, f := range s.Notable {
+			fmt.Fprintf(&b, "  %-*s  %s\n", width, f.Path, cmdutil.DescStyle.Render(f.Desc))
+		}
+	}
+	return b.String()
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	} else if strings.HasSuffix(noun, "y") {
+		return fmt.Sprintf("%d %sies", n, noun[:len(noun)-1])
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,263 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"encr.dev/cli/cmd/encore/cmdutil"
+	"encr.dev/internal/conf"
+	"encr.dev/pkg/github"
+)
+
+// The template cache lets 'encore app create' work without network access.
+// Manifests are cached whenever they're fetched successfully, and template
+// sources are cached by 'encore app prefetch-templates --sources'.
+
+// manifestCacheTTL is how long a cached manifest is used
+// without fetching it again.
+const manifestCacheTTL = 24 * time.Hour
+
+var prefetchSources bool
+
+var prefetchTemplatesCmd = &cobra.Command{
+	Use:   "prefetch-templates",
+	Short: "Download the app templates so 'encore app create' works offline",
+	Args:  cobra.NoArgs,
+
+	DisableFlagsInUseLine: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := prefetchTemplates(cmd.Context(), prefetchSources); err != nil {
+			cmdutil.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	prefetchTemplatesCmd.Flags().BoolVar(&prefetchSources, "sources", false, "Also download the source code of each template")
+	appCmd.AddCommand(prefetchTemplatesCmd)
+}
+
+// templateCacheDir reports the directory to cache templates in.
+func templateCacheDir() (string, error) {
+	dir, err := conf.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "templates"), nil
+}
+
+// cachedManifestPath reports where the manifest at the given url is cached.
+func cachedManifestPath(url string) (string, error) {
+	dir, err := templateCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "manifests", path.Base(url)), nil
+}
+
+func writeCachedManifest(url string, items []templateItem) error {
+	p, err := cachedManifestPath(url)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename it into place,
+	// so that the cache is never partially written.
+	f, err := os.CreateTemp(filepath.Dir(p), ".manifest-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	} else if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
+
+func readCachedManifest(url string) ([]templateItem, error) {
+	p, err := cachedManifestPath(url)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	var items []templateItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// cachedManifestAge reports how long ago the manifest at the given url was cached.
+func cachedManifestAge(url string) (time.Duration, error) {
+	p, err := cachedManifestPath(url)
+	if err != nil {
+		return 0, err
+	}
+	fi, err := os.Stat(p)
+	if err != nil {
+		return 0, err
+	}
+	return time.Since(fi.ModTime()), nil
+}
+
+// formatCacheAge formats the age of a stale cache, which is at least a day.
+func formatCacheAge(age time.Duration) string {
+	if days := int(age.Hours() / 24); days > 1 {
+		return fmt.Sprintf("%d days", days)
+	}
+	return "1 day"
+}
+
+// cachedSourceDir reports where the source of the given template is cached.
+func cachedSourceDir(template string) (string, error) {
+	dir, err := templateCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	// Templates can be names or URLs; make them safe to use as a directory name.
+	key := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, template)
+	return filepath.Join(dir, "sources", key), nil
+}
+
+// copyCachedTemplate copies the cached source of the given template to dst.
+// It reports false if the template isn't cached.
+func copyCachedTemplate(template, dst string) (bool, error) {
+	src, err := cachedSourceDir(template)
+	if err != nil {
+		return false, err
+	} else if _, err := os.Stat(src); err != nil {
+		return false, nil
+	}
+	return true, os.CopyFS(dst, os.DirFS(src))
+}
+
+// useCachedTemplate replaces the contents of the app directory dst with the
+// cached source of the given template, if it's cached.
+// It's used when downloading the template failed partway through.
+func useCachedTemplate(template, dst string) (bool, error) {
+	if src, err := cachedSourceDir(template); err != nil {
+		return false, err
+	} else if _, err := os.Stat(src); err != nil {
+		return false, nil
+	}
+
+	// Clear out any partially downloaded files.
+	if err := os.RemoveAll(dst); err != nil {
+		return false, err
+	}
+	return copyCachedTemplate(template, dst)
+}
+
+// prefetchTemplate downloads the source of the given template into the cache,
+// replacing any previously cached version.
+func prefetchTemplate(ctx context.Context, template string) error {
+	dst, err := cachedSourceDir(template)
+	if err != nil {
+		return err
+	}
+	tree, err := parseTemplate(ctx, template)
+	if err != nil {
+		return err
+	}
+
+	// Download into a temporary directory so a failed download
+	// doesn't clobber a previously cached version.
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dst), ".download-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	if err := github.ExtractTree(ctx, tree, tmp); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+// prefetchTemplates downloads the template manifests into the cache,
+// and the template sources as well if sources is true.
+func prefetchTemplates(ctx context.Context, sources bool) error {
+	green := color.New(color.FgGreen)
+	red := color.New(color.FgRed)
+
+	var manifests []templateSource
+	for _, url := range []string{tutorialsURL, templatesURL} {
+		manifest, err := fetchTemplateManifest(url)
+		if err != nil {
+			return fmt.Errorf("fetch template manifest %s: %v", url, err)
+		} else if err := writeCachedManifest(url, manifest); err != nil {
+			return fmt.Errorf("cache template manifest: %v", err)
+		}
+		manifests = append(manifests, templateSource{name: url, items: manifest})
+	}
+	items, conflicts := mergeTemplates(manifests...)
+	printSlugConflicts(os.Stdout, conflicts)
+	_, _ = green.Printf("Cached %d templates.\n", len(items))
+	if !sources {
+		return nil
+	}
+
+	var templates []string
+	for _, it := range items {
+		if it.Template != "" && !slices.Contains(templates, it.Template) {
+			templates = append(templates, it.Template)
+		}
+	}
+
+	var failed []string
+	for i, tmpl := range templates {
+		fmt.Printf("[%d/%d] Downloading %s... ", i+1, len(templates), tmpl)
+		ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+		err := prefetchTemplate(ctx, tmpl)
+		cancel()
+		if err != nil {
+			_, _ = red.Printf("failed: %v\n", err)
+			failed = append(failed, tmpl)
+			continue
+		}
+		_, _ = green.Println("done")
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to download %d templates: %s", len(failed), strings.Join(failed, ", "))
+	}
+	_, _ = green.Println("All templates downloaded; 'encore app create' now works offline.")
+	return nil
+}
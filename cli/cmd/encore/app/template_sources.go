@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"encr.dev/pkg/github"
+)
+
+// Custom template sources let organizations list their own templates,
+// such as scaffolds with pre-approved auth and observability setups,
+// in 'encore app create' alongside Encore's templates.
+
+// templateManifestName is the name of the manifest of a template source
+// given as a GitHub repository or a local directory.
+const templateManifestName = "cli-templates.json"
+
+// loadTemplateSources loads the templates of the given sources, in order.
+func loadTemplateSources(ctx context.Context, sources []string) ([]templateSource, error) {
+	var loaded []templateSource
+	for _, src := range sources {
+		s, err := loadTemplateSource(ctx, src)
+		if err != nil {
+			return nil, fmt.Errorf("load template source %s: %v", src, err)
+		}
+		loaded = append(loaded, s)
+	}
+	return loaded, nil
+}
+
+// loadTemplateSource loads the templates listed in the given source, which is one of:
+//
+//   - a GitHub repository or tree, like github.com/myorg/templates,
+//     with a cli-templates.json manifest at its root;
+//   - the http(s) URL of a manifest;
+//   - a local manifest, or a directory with a cli-templates.json manifest.
+//
+// Templates listed by name are resolved relative to the GitHub tree
+// or the local directory of the manifest.
+func loadTemplateSource(ctx context.Context, src string) (templateSource, error) {
+	switch {
+	case strings.HasPrefix(src, "github.com/") || strings.HasPrefix(src, "https://github.com/"):
+		tree, err := github.ParseTree(ctx, src)
+		if err != nil {
+			return templateSource{}, err
+		}
+		data, err := github.ReadFile(ctx, tree, templateManifestName, 1024*1024)
+		if err != nil {
+			return templateSource{}, err
+		}
+		items, err := parseTemplateManifest(data)
+		if err != nil {
+			return templateSource{}, err
+		}
+		for i, it := range items {
+			if it.Template != "" && isTemplateName(it.Template) {
+				items[i].Template = fmt.Sprintf("https://github.com/%s/%s/tree/%s/%s",
+					tree.Owner, tree.Repo, tree.Branch, strings.TrimPrefix(path.Join(tree.Path, it.Template), "/"))
+			}
+		}
+		return templateSource{name: src, items: items}, nil
+
+	case strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://"):
+		items, err := fetchTemplateManifest(src)
+		if err != nil {
+			return templateSource{}, err
+		}
+		return templateSource{name: src, items: items}, nil
+
+	default:
+		file, err := filepath.Abs(src)
+		if err != nil {
+			return templateSource{}, err
+		}
+		if fi, err := os.Stat(file); err != nil {
+			return templateSource{}, err
+		} else if fi.IsDir() {
+			file = filepath.Join(file, templateManifestName)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return templateSource{}, err
+		}
+		items, err := parseTemplateManifest(data)
+		if err != nil {
+			return templateSource{}, err
+		}
+		for i, it := range items {
+			if it.Template != "" && !isTemplateURL(it.Template) && !filepath.IsAbs(it.Template) {
+				items[i].Template = filepath.Join(filepath.Dir(file), it.Template)
+			}
+		}
+		return templateSource{name: src, items: items}, nil
+	}
+}
+
+// isTemplateURL reports whether tmpl refers to a template by URL.
+func isTemplateURL(tmpl string) bool {
+	return strings.Contains(tmpl, "://") || strings.HasPrefix(tmpl, "github.com/")
+}
+
+// isLocalTemplate reports whether tmpl refers to a template in a local directory,
+// as listed by a local template source.
+func isLocalTemplate(tmpl string) bool {
+	if !filepath.IsAbs(tmpl) {
+		return false
+	}
+	fi, err := os.Stat(tmpl)
+	return err == nil && fi.IsDir()
+}
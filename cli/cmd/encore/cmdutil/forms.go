@@ -16,11 +16,11 @@ const (
 )
 
 var (
-	InputStyle   = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Dark: CodeBlue, Light: CodeBlue})
-	DescStyle    = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Dark: CodeGreen, Light: CodePurple})
+	InputStyle   = lipgloss.NewStyle().Foreground(ActiveTheme.Primary)
+	DescStyle    = lipgloss.NewStyle().Foreground(ActiveTheme.Desc)
 	DocStyle     = lipgloss.NewStyle().Padding(0, 2, 0, 2)
-	ErrorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color(ValidationFail))
-	SuccessStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#00C200"))
+	ErrorStyle   = lipgloss.NewStyle().Foreground(ActiveTheme.Error)
+	SuccessStyle = lipgloss.NewStyle().Foreground(ActiveTheme.Success)
 )
 
 type SelectedID[T any] interface {
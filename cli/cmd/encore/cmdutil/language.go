@@ -1,5 +1,10 @@
 package cmdutil
 
+import (
+	"slices"
+	"sync"
+)
+
 type Language string
 
 const (
@@ -7,28 +12,98 @@ const (
 	LanguageTS Language = "ts"
 )
 
+// AllLanguages are the languages built into the CLI.
+// Other languages may be registered at runtime, see RegisterLanguage.
 var AllLanguages = []Language{
 	LanguageGo,
 	LanguageTS,
 }
 
+// LanguageFlagValues returns the registered languages, as flag values.
 func LanguageFlagValues() []string {
-	result := make([]string, 0, len(AllLanguages))
-	for _, r := range AllLanguages {
-		result = append(result, string(r))
+	langs := Languages()
+	result := make([]string, 0, len(langs))
+	for _, l := range langs {
+		result = append(result, string(l.Lang))
 	}
 	return result
 }
 
+// LanguageInfo describes a language that apps can be created in.
+type LanguageInfo struct {
+	Lang Language
+	Name string // the display name, such as "TypeScript"
+	Desc string // a short description, shown when selecting the language
+}
+
+var (
+	languagesMu sync.RWMutex
+
+	// languages is the registry of languages, in the order they're listed.
+	// It starts out with the built-in languages, and the ones the templates
+	// are written in are registered as the templates are loaded, so that
+	// new languages are listed without a CLI release.
+	languages = []LanguageInfo{
+		{Lang: LanguageGo, Name: "Go", Desc: "Build performant and scalable backends with Go"},
+		{Lang: LanguageTS, Name: "TypeScript", Desc: "Build backend and full-stack applications with TypeScript"},
+	}
+)
+
+// RegisterLanguage adds the language to the registry, if it isn't already in it.
+// The name and description of a registered language are only set if they're missing.
+func RegisterLanguage(info LanguageInfo) {
+	if info.Lang == "" {
+		return
+	}
+	languagesMu.Lock()
+	defer languagesMu.Unlock()
+	idx := slices.IndexFunc(languages, func(l LanguageInfo) bool { return l.Lang == info.Lang })
+	if idx < 0 {
+		languages = append(languages, info)
+		return
+	}
+	if languages[idx].Name == "" {
+		languages[idx].Name = info.Name
+	}
+	if languages[idx].Desc == "" {
+		languages[idx].Desc = info.Desc
+	}
+}
+
+// SnapshotLanguages returns a function that restores the registry
+// to its current state, for tests that register languages.
+func SnapshotLanguages() (restore func()) {
+	snapshot := Languages()
+	return func() {
+		languagesMu.Lock()
+		defer languagesMu.Unlock()
+		languages = snapshot
+	}
+}
+
+// Languages returns the registered languages.
+func Languages() []LanguageInfo {
+	languagesMu.RLock()
+	defer languagesMu.RUnlock()
+	return slices.Clone(languages)
+}
+
+// LookupLanguage returns the registered language lang, if any.
+func LookupLanguage(lang Language) (info LanguageInfo, ok bool) {
+	languagesMu.RLock()
+	defer languagesMu.RUnlock()
+	idx := slices.IndexFunc(languages, func(l LanguageInfo) bool { return l.Lang == lang })
+	if idx < 0 {
+		return LanguageInfo{}, false
+	}
+	return languages[idx], true
+}
+
 func (lang Language) Display() string {
-	switch lang {
-	case LanguageGo:
-		return "Go"
-	case LanguageTS:
-		return "TypeScript"
-	default:
-		return string(lang)
+	if info, ok := LookupLanguage(lang); ok && info.Name != "" {
+		return info.Name
 	}
+	return string(lang)
 }
 
 func (lang Language) SelectPrompt() string {
@@ -12,12 +12,13 @@ import (
 
 type Oneof struct {
 	Value       string
-	Allowed     []string
-	Flag        string // defaults to "output" if empty
-	FlagShort   string // defaults to "o" if both Flag and FlagShort are empty
-	Desc        string // usage desc
-	TypeDesc    string // type description, defaults to the name of the flag
-	NoOptDefVal string // default value when no option is provided
+	Allowed     []string        // the allowed values, as listed in the usage
+	AllowedFunc func() []string // if set, further allowed values when setting the flag, for values only known at runtime
+	Flag        string          // defaults to "output" if empty
+	FlagShort   string          // defaults to "o" if both Flag and FlagShort are empty
+	Desc        string          // usage desc
+	TypeDesc    string          // type description, defaults to the name of the flag
+	NoOptDefVal string          // default value when no option is provided
 }
 
 func (o *Oneof) AddFlag(cmd *cobra.Command) {
@@ -32,7 +33,7 @@ func (o *Oneof) AddFlag(cmd *cobra.Command) {
 			DefValue:    o.String(),
 		})
 	_ = cmd.RegisterFlagCompletionFunc(name, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return o.Allowed, cobra.ShellCompDirectiveNoFileComp
+		return o.allowed(), cobra.ShellCompDirectiveNoFileComp
 	})
 }
 
@@ -57,17 +58,32 @@ func (o *Oneof) Type() string {
 }
 
 func (o *Oneof) Set(v string) error {
+	// Only look up the values known at runtime for values that aren't
+	// statically allowed, since doing so can be slow.
 	if slices.Contains(o.Allowed, v) {
 		o.Value = v
 		return nil
 	}
+	allowed := o.allowed()
+	if slices.Contains(allowed, v) {
+		o.Value = v
+		return nil
+	}
 
 	var b strings.Builder
 	b.WriteString("must be one of ")
-	o.oneOf(&b)
+	writeOneOf(&b, allowed)
 	return errors.New(b.String())
 }
 
+// allowed returns the values the flag can be set to.
+func (o *Oneof) allowed() []string {
+	if o.AllowedFunc != nil {
+		return o.AllowedFunc()
+	}
+	return o.Allowed
+}
+
 func (o *Oneof) Usage() string {
 	var b strings.Builder
 	desc := o.Desc
@@ -75,7 +91,7 @@ func (o *Oneof) Usage() string {
 		desc = "Output format"
 	}
 	b.WriteString(desc + ". One of (")
-	o.oneOf(&b)
+	writeOneOf(&b, o.Allowed)
 	b.WriteString(").")
 	return b.String()
 }
@@ -92,9 +108,9 @@ func (o *Oneof) Alternatives() string {
 	return b.String()
 }
 
-func (o *Oneof) oneOf(b *strings.Builder) {
-	n := len(o.Allowed)
-	for i, s := range o.Allowed {
+func writeOneOf(b *strings.Builder, allowed []string) {
+	n := len(allowed)
+	for i, s := range allowed {
 		if i > 0 {
 			switch {
 			case n == 2:
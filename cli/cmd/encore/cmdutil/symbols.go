@@ -0,0 +1,59 @@
+package cmdutil
+
+import (
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/briandowns/spinner"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// UnicodeSupported reports whether the terminal is expected to render
+// Unicode symbols, like checkmarks and spinners, correctly.
+//
+// It's determined from the terminal type and locale environment variables,
+// so that constrained terminals (like serial consoles or SSH sessions
+// without a UTF-8 locale) fall back to ASCII.
+var UnicodeSupported = unicodeFromEnv()
+
+func unicodeFromEnv() bool {
+	switch os.Getenv("TERM") {
+	case "dumb", "linux", "vt100", "vt102", "vt220":
+		return false
+	}
+
+	// Windows doesn't use locale environment variables,
+	// and its terminals support Unicode.
+	if runtime.GOOS == "windows" {
+		return true
+	}
+
+	// The first non-empty variable determines the character set.
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := strings.ToLower(os.Getenv(key)); v != "" {
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
+	}
+	return false
+}
+
+// Symbol returns unicode if the terminal supports Unicode, and ascii otherwise.
+func Symbol(unicode, ascii string) string {
+	if UnicodeSupported {
+		return unicode
+	}
+	return ascii
+}
+
+// asciiBorder is used to mark the selected list item
+// when the terminal doesn't support Unicode.
+var asciiBorder = lipgloss.Border{Left: ">"}
+
+// SpinnerCharSet returns the character set to use for progress spinners.
+func SpinnerCharSet() []string {
+	if UnicodeSupported {
+		return spinner.CharSets[14]
+	}
+	return spinner.CharSets[9]
+}
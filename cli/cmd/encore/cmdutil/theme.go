@@ -0,0 +1,113 @@
+package cmdutil
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme describes the colors used by the interactive forms.
+type Theme struct {
+	Name string
+
+	Primary lipgloss.TerminalColor // prompts and the selected list item
+	Desc    lipgloss.TerminalColor // hints and descriptions
+	Error   lipgloss.TerminalColor
+	Success lipgloss.TerminalColor
+
+	// Plain, if true, means the theme has no colors and
+	// emphasis is conveyed with text attributes instead.
+	Plain bool
+}
+
+var (
+	DefaultTheme = Theme{
+		Name:    "default",
+		Primary: lipgloss.AdaptiveColor{Dark: CodeBlue, Light: CodeBlue},
+		Desc:    lipgloss.AdaptiveColor{Dark: CodeGreen, Light: CodePurple},
+		Error:   lipgloss.Color(ValidationFail),
+		Success: lipgloss.Color("#00C200"),
+	}
+
+	HighContrastTheme = Theme{
+		Name:    "high-contrast",
+		Primary: lipgloss.AdaptiveColor{Dark: "#87AFFF", Light: "#0000AF"},
+		Desc:    lipgloss.AdaptiveColor{Dark: "#FFFFFF", Light: "#000000"},
+		Error:   lipgloss.AdaptiveColor{Dark: "#FF5F5F", Light: "#AF0000"},
+		Success: lipgloss.AdaptiveColor{Dark: "#5FFF5F", Light: "#005F00"},
+	}
+
+	// ColorblindTheme uses the Okabe-Ito palette, which avoids
+	// relying on a red/green distinction.
+	ColorblindTheme = Theme{
+		Name:    "colorblind",
+		Primary: lipgloss.AdaptiveColor{Dark: "#56B4E9", Light: "#0072B2"},
+		Desc:    lipgloss.AdaptiveColor{Dark: "#E69F00", Light: "#CC79A7"},
+		Error:   lipgloss.Color("#D55E00"),
+		Success: lipgloss.AdaptiveColor{Dark: "#56B4E9", Light: "#0072B2"},
+	}
+
+	PlainTheme = Theme{
+		Name:    "plain",
+		Primary: lipgloss.NoColor{},
+		Desc:    lipgloss.NoColor{},
+		Error:   lipgloss.NoColor{},
+		Success: lipgloss.NoColor{},
+		Plain:   true,
+	}
+)
+
+// AllThemes lists the available themes.
+var AllThemes = []Theme{DefaultTheme, HighContrastTheme, ColorblindTheme, PlainTheme}
+
+// ActiveTheme is the theme used by the interactive forms.
+// It's selected with the ENCORE_THEME environment variable,
+// and falls back to the plain theme if NO_COLOR is set
+// or the terminal doesn't support colors.
+var ActiveTheme = themeFromEnv()
+
+func themeFromEnv() Theme {
+	if name := strings.TrimSpace(os.Getenv("ENCORE_THEME")); name != "" {
+		if t, ok := LookupTheme(name); ok {
+			return t
+		}
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return PlainTheme
+	}
+	return DefaultTheme
+}
+
+// LookupTheme returns the theme with the given name.
+func LookupTheme(name string) (Theme, bool) {
+	for _, t := range AllThemes {
+		if strings.EqualFold(t.Name, name) {
+			return t, true
+		}
+	}
+	return Theme{}, false
+}
+
+// ListItemStyles returns the list item styles for the theme,
+// with the selected item highlighted.
+func (t Theme) ListItemStyles() list.DefaultItemStyles {
+	ls := list.NewDefaultItemStyles()
+	if !UnicodeSupported {
+		ls.SelectedTitle = ls.SelectedTitle.Border(asciiBorder, false, false, false, true)
+		ls.SelectedDesc = ls.SelectedDesc.Border(asciiBorder, false, false, false, true)
+	}
+	if t.Plain {
+		ls.SelectedTitle = ls.SelectedTitle.Bold(true).UnsetForeground().UnsetBorderForeground()
+		ls.SelectedDesc = ls.SelectedDesc.UnsetForeground().UnsetBorderForeground()
+		ls.NormalTitle = ls.NormalTitle.UnsetForeground()
+		ls.NormalDesc = ls.NormalDesc.UnsetForeground()
+		ls.DimmedTitle = ls.DimmedTitle.UnsetForeground()
+		ls.DimmedDesc = ls.DimmedDesc.UnsetForeground()
+		return ls
+	}
+	ls.SelectedTitle = ls.SelectedTitle.Foreground(t.Primary).BorderForeground(t.Primary)
+	ls.SelectedDesc = ls.SelectedDesc.Foreground(t.Primary).BorderForeground(t.Primary)
+	return ls
+}
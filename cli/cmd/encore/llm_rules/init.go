@@ -10,7 +10,6 @@ import (
 	"encr.dev/pkg/appfile"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 	"github.com/cockroachdb/errors"
 	"github.com/spf13/cobra"
 )
@@ -60,9 +59,7 @@ func initLLMRules(tool Tool) error {
 	if tool == "" {
 		var llmRulesModel ToolSelectModel
 		{
-			ls := list.NewDefaultItemStyles()
-			ls.SelectedTitle = ls.SelectedTitle.Foreground(lipgloss.Color(cmdutil.CodeBlue)).BorderForeground(lipgloss.Color(cmdutil.CodeBlue))
-			ls.SelectedDesc = ls.SelectedDesc.Foreground(lipgloss.Color(cmdutil.CodeBlue)).BorderForeground(lipgloss.Color(cmdutil.CodeBlue))
+			ls := cmdutil.ActiveTheme.ListItemStyles()
 			del := list.NewDefaultDelegate()
 			del.Styles = ls
 			del.ShowDescription = false
@@ -301,7 +301,7 @@ func downloadLLMInstructions(lang cmdutil.Language) (string, error) {
 	default:
 		return "", fmt.Errorf("unsupported language")
 	}
-	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+	s := spinner.New(cmdutil.SpinnerCharSet(), 100*time.Millisecond)
 	s.Prefix = "Downloading LLM instructions..."
 	s.Start()
 	defer s.Stop()
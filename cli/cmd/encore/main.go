@@ -16,6 +16,7 @@ import (
 	_ "encr.dev/cli/cmd/encore/config"
 	_ "encr.dev/cli/cmd/encore/k8s"
 	_ "encr.dev/cli/cmd/encore/namespace"
+	_ "encr.dev/cli/cmd/encore/pubsub"
 	_ "encr.dev/cli/cmd/encore/secrets"
 )
 
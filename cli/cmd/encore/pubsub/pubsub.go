@@ -0,0 +1,132 @@
+package pubsub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"encr.dev/cli/cmd/encore/cmdutil"
+	"encr.dev/cli/cmd/encore/root"
+	daemonpb "encr.dev/proto/encore/daemon"
+)
+
+var pubsubCmd = &cobra.Command{
+	Use:   "pubsub",
+	Short: "Pub/Sub management commands",
+}
+
+var dlqCmd = &cobra.Command{
+	Use:   "dlq",
+	Short: "Manage the dead-letter queues of subscriptions in the running app",
+	Long: `Manage the dead-letter queues of subscriptions in the running app.
+
+Messages are moved to a subscription's dead-letter queue once they've run out of retries.
+These commands let you inspect them, replay them to the subscription, or purge them.`,
+}
+
+// deadLetters performs op on the dead-letter queue of the given subscription.
+func deadLetters(op daemonpb.PubSubDeadLettersRequest_Op, topic, subscription string, ids []string) *daemonpb.PubSubDeadLettersResponse {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	appRoot, _ := cmdutil.AppRoot()
+	daemon := cmdutil.ConnectDaemon(ctx)
+	resp, err := daemon.PubSubDeadLetters(ctx, &daemonpb.PubSubDeadLettersRequest{
+		AppRoot:      appRoot,
+		Topic:        topic,
+		Subscription: subscription,
+		Op:           op,
+		MessageIds:   ids,
+	})
+	if err != nil {
+		cmdutil.Fatal(err)
+	}
+	return resp
+}
+
+func init() {
+	output := cmdutil.Oneof{Value: "columns", Allowed: []string{"columns", "json"}}
+	listCmd := &cobra.Command{
+		Use:     "list TOPIC SUBSCRIPTION",
+		Short:   "List the messages in a subscription's dead-letter queue",
+		Aliases: []string{"ls"},
+		Args:    cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			resp := deadLetters(daemonpb.PubSubDeadLettersRequest_OP_LIST, args[0], args[1], nil)
+
+			if output.Value == "json" {
+				var buf bytes.Buffer
+				buf.WriteByte('[')
+				for i, msg := range resp.Messages {
+					data, err := protojson.MarshalOptions{
+						UseProtoNames:   true,
+						EmitUnpopulated: true,
+					}.Marshal(msg)
+					if err != nil {
+						cmdutil.Fatal(err)
+					}
+					if i > 0 {
+						buf.WriteByte(',')
+					}
+					buf.Write(data)
+				}
+				buf.WriteByte(']')
+
+				var dst bytes.Buffer
+				if err := json.Indent(&dst, buf.Bytes(), "", "  "); err != nil {
+					cmdutil.Fatal(err)
+				}
+				_, _ = fmt.Fprintln(os.Stdout, dst.String())
+				return
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.StripEscape)
+			_, _ = fmt.Fprint(w, "ID\tPUBLISHED\tATTEMPT\tMESSAGE\n")
+			for _, msg := range resp.Messages {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", msg.Id,
+					msg.PublishTime.AsTime().Local().Format(time.DateTime), msg.DeliveryAttempt, msg.Data)
+			}
+			_ = w.Flush()
+		},
+	}
+	output.AddFlag(listCmd)
+	dlqCmd.AddCommand(listCmd)
+}
+
+var replayCmd = &cobra.Command{
+	Use:   "replay TOPIC SUBSCRIPTION [MESSAGE_ID...]",
+	Short: "Replay messages from a subscription's dead-letter queue",
+	Long: `Replay messages from a subscription's dead-letter queue.
+
+The messages are delivered to the subscription again, and removed from the dead-letter queue.
+If no message ids are given, all messages in the dead-letter queue are replayed.`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		resp := deadLetters(daemonpb.PubSubDeadLettersRequest_OP_REPLAY, args[0], args[1], args[2:])
+		_, _ = fmt.Fprintf(os.Stdout, "replayed %d message(s)\n", resp.Count)
+	},
+}
+
+var purgeCmd = &cobra.Command{
+	Use:   "purge TOPIC SUBSCRIPTION",
+	Short: "Permanently delete all messages in a subscription's dead-letter queue",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		resp := deadLetters(daemonpb.PubSubDeadLettersRequest_OP_PURGE, args[0], args[1], nil)
+		_, _ = fmt.Fprintf(os.Stdout, "purged %d message(s)\n", resp.Count)
+	},
+}
+
+func init() {
+	dlqCmd.AddCommand(replayCmd)
+	dlqCmd.AddCommand(purgeCmd)
+	pubsubCmd.AddCommand(dlqCmd)
+	root.Cmd.AddCommand(pubsubCmd)
+}
@@ -130,6 +130,34 @@ func (m *Manager) getStorageBuckets(ctx context.Context, request mcp.CallToolReq
 			bucketInfo["doc"] = *bucket.Doc
 		}
 
+		// Add lifecycle rules if available
+		if len(bucket.LifecycleRules) > 0 {
+			rules := make([]map[string]interface{}, 0, len(bucket.LifecycleRules))
+			for _, rule := range bucket.LifecycleRules {
+				rules = append(rules, map[string]interface{}{
+					"prefix":                rule.Prefix,
+					"expire_after_days":     rule.ExpireAfterDays,
+					"transition_after_days": rule.TransitionAfterDays,
+					"transition_to":         rule.TransitionTo,
+				})
+			}
+			bucketInfo["lifecycle_rules"] = rules
+		}
+
+		// Add topic notifications if available
+		if len(bucket.TopicNotifications) > 0 {
+			notifications := make([]map[string]interface{}, 0, len(bucket.TopicNotifications))
+			for _, n := range bucket.TopicNotifications {
+				notifications = append(notifications, map[string]interface{}{
+					"topic":   n.TopicName,
+					"service": n.ServiceName,
+					"events":  n.Events,
+					"prefix":  n.Prefix,
+				})
+			}
+			bucketInfo["topic_notifications"] = notifications
+		}
+
 		// Add location information if available
 		if location, exists := bucketDefLocations[bucket.Name]; exists {
 			bucketInfo["definition"] = location
@@ -103,6 +103,9 @@ func (m *Manager) getPubSub(ctx context.Context, request mcp.CallToolRequest) (*
 			if subscription.MaxConcurrency != nil {
 				subscriptionInfo["max_concurrency"] = *subscription.MaxConcurrency
 			}
+			if subscription.AckDeduplication {
+				subscriptionInfo["ack_deduplication"] = true
+			}
 
 			// Add retry policy if available
 			if subscription.RetryPolicy != nil {
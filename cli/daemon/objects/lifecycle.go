@@ -0,0 +1,99 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"encr.dev/pkg/emulators/storage/gcsemu"
+	meta "encr.dev/proto/encore/parser/meta/v1"
+)
+
+// expiryInterval is how often the lifecycle rules of the buckets are applied.
+const expiryInterval = time.Minute
+
+// runExpiry periodically deletes expired objects until ctx is canceled,
+// simulating the lifecycle rules the cloud providers apply to buckets.
+// Storage class transitions have no local equivalent and are ignored.
+func (s *Server) runExpiry(ctx context.Context) {
+	ticker := time.NewTicker(expiryInterval)
+	defer ticker.Stop()
+	for {
+		for _, bkt := range s.buckets {
+			if err := expireObjects(ctx, s.store, bkt, time.Now()); err != nil {
+				log.Error().Err(err).Str("bucket", bkt.Name).Msg("unable to expire objects")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// expireObjects deletes the objects in bkt that have expired as of now,
+// according to its lifecycle rules.
+func expireObjects(ctx context.Context, store gcsemu.Store, bkt *meta.Bucket, now time.Time) error {
+	rules := make([]*meta.Bucket_LifecycleRule, 0, len(bkt.LifecycleRules))
+	for _, rule := range bkt.LifecycleRules {
+		if rule.ExpireAfterDays > 0 {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	// Collect the names first, as the store can't be modified while walking it.
+	var names []string
+	err := store.Walk(ctx, bkt.Name, func(ctx context.Context, filename string, fInfo os.FileInfo) error {
+		if filename != "" && (fInfo == nil || !fInfo.IsDir()) {
+			names = append(names, filename)
+		}
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		days := expiryDays(rules, name)
+		if days == 0 {
+			continue
+		}
+		obj, err := store.GetMeta("", bkt.Name, name)
+		if err != nil {
+			return err
+		} else if obj == nil {
+			continue
+		}
+		created, err := time.Parse(time.RFC3339Nano, obj.TimeCreated)
+		if err != nil || now.Before(created.AddDate(0, 0, days)) {
+			continue
+		}
+		if err := store.Delete(bkt.Name, name); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// expiryDays reports the number of days after which the object with the given name expires,
+// which is the earliest expiry of the rules that apply to it. It reports 0 if none apply.
+func expiryDays(rules []*meta.Bucket_LifecycleRule, name string) int {
+	days := 0
+	for _, rule := range rules {
+		if strings.HasPrefix(name, rule.Prefix) && (days == 0 || int(rule.ExpireAfterDays) < days) {
+			days = int(rule.ExpireAfterDays)
+		}
+	}
+	return days
+}
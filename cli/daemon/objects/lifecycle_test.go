@@ -0,0 +1,72 @@
+package objects
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/api/storage/v1"
+
+	"encr.dev/pkg/emulators/storage/gcsemu"
+	meta "encr.dev/proto/encore/parser/meta/v1"
+)
+
+func TestExpireObjects(t *testing.T) {
+	t.Run("mem", func(t *testing.T) {
+		testExpireObjects(t, gcsemu.NewMemStore())
+	})
+	t.Run("dir", func(t *testing.T) {
+		testExpireObjects(t, gcsemu.NewFileStore(t.TempDir()))
+	})
+}
+
+func testExpireObjects(t *testing.T, store gcsemu.Store) {
+	now := time.Now()
+	add := func(name string, age time.Duration) {
+		t.Helper()
+		created := now.Add(-age).UTC().Format(time.RFC3339Nano)
+		if err := store.Add("bkt", name, []byte("x"), &storage.Object{TimeCreated: created}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	day := 24 * time.Hour
+	add("tmp/old", 8*day)
+	add("tmp/new", 6*day)
+	add("logs/old", 8*day)
+	add("logs/older", 31*day)
+	add("other", 365*day)
+
+	bkt := &meta.Bucket{
+		Name: "bkt",
+		LifecycleRules: []*meta.Bucket_LifecycleRule{
+			{Prefix: "tmp/", ExpireAfterDays: 7},
+			{Prefix: "logs/", ExpireAfterDays: 30},
+			{TransitionAfterDays: 1, TransitionTo: "cold"},
+		},
+	}
+	if err := expireObjects(context.Background(), store, bkt, now); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]bool{
+		"tmp/old":    false,
+		"tmp/new":    true,
+		"logs/old":   true,
+		"logs/older": false,
+		"other":      true,
+	}
+	for name, exists := range want {
+		obj, err := store.GetMeta("", "bkt", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := obj != nil; got != exists {
+			t.Errorf("%s: got exists=%v, want %v", name, got, exists)
+		}
+	}
+
+	// Buckets that don't exist yet have nothing to expire.
+	if err := expireObjects(context.Background(), store, &meta.Bucket{Name: "missing", LifecycleRules: bkt.LifecycleRules}, now); err != nil {
+		t.Errorf("got error %v for missing bucket, want nil", err)
+	}
+}
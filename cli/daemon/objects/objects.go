@@ -3,12 +3,14 @@ package objects
 import (
 	// nosemgrep
 
+	"context"
 	"fmt"
 	"net"
 	"net/http"
 
 	"encr.dev/cli/daemon/namespace"
 	"encr.dev/pkg/emulators/storage/gcsemu"
+	"encr.dev/pkg/emulators/storage/s3emu"
 	"github.com/cockroachdb/errors"
 	"github.com/rs/xid"
 	"github.com/rs/zerolog/log"
@@ -24,33 +26,39 @@ type Server struct {
 	cancel    func() // set by Start
 	store     gcsemu.Store
 	emu       *gcsemu.GcsEmu
+	notifier  *gcsemu.Notifier
 	ln        net.Listener
 	srv       *http.Server
-	inMemory  bool
+	buckets   []*meta.Bucket // set by Initialize
 }
 
 func NewInMemoryServer(public *PublicBucketServer) *Server {
 	id := xid.New().String()
 	store := gcsemu.NewMemStore()
-	return newServer(public, id, store, true)
+	return newServer(public, id, store)
 }
 
 func NewDirServer(public *PublicBucketServer, nsID namespace.ID, baseDir string) *Server {
 	store := gcsemu.NewFileStore(baseDir)
-	return newServer(public, nsID.String(), store, false)
+	return newServer(public, nsID.String(), store)
 }
 
-func newServer(public *PublicBucketServer, id string, store gcsemu.Store, isInMem bool) *Server {
+func newServer(public *PublicBucketServer, id string, store gcsemu.Store) *Server {
+	// Record notifications of all changes to objects, including signed uploads
+	// through the public bucket server, to emulate bucket event notifications.
+	notifier := gcsemu.NewNotifier()
+	store = notifier.Store(store)
 	return &Server{
 		public:   public,
 		id:       id,
 		store:    store,
 		emu:      gcsemu.NewGcsEmu(gcsemu.Options{Store: store}),
-		inMemory: isInMem,
+		notifier: notifier,
 	}
 }
 
 func (s *Server) Initialize(md *meta.Data) error {
+	s.buckets = md.Buckets
 	for _, bucket := range md.Buckets {
 		if err := s.emu.InitBucket(bucket.Name); err != nil {
 			return errors.Wrap(err, "initialize object storage bucket")
@@ -61,15 +69,18 @@ func (s *Server) Initialize(md *meta.Data) error {
 
 func (s *Server) Start() error {
 	return s.startOnce.Do(func() error {
-		if s.inMemory {
-			s.public.Register(s.id, s.store)
-		}
+		s.public.Register(s.id, s.store)
 		mux := http.NewServeMux()
 		ln, err := net.Listen("tcp", "127.0.0.1:0")
 		if err != nil {
 			return errors.Wrap(err, "listen tcp")
 		}
 		s.emu.Register(mux)
+		mux.HandleFunc("GET /_emulator/notifications/{bucket}", s.notifier.Handler)
+		mux.Handle("/s3/", http.StripPrefix("/s3", s3emu.New(s3emu.Options{
+			Store:   s.store,
+			Buckets: s.bucketNames,
+		})))
 		s.ln = ln
 		s.srv = &http.Server{Handler: mux}
 
@@ -79,15 +90,20 @@ func (s *Server) Start() error {
 			}
 		}()
 
+		ctx, cancel := context.WithCancel(context.Background())
+		s.cancel = cancel
+		go s.runExpiry(ctx)
+
 		return nil
 	})
 }
 
 func (s *Server) Stop() {
-	_ = s.srv.Close()
-	if s.inMemory {
-		s.public.Deregister(s.id)
+	if s.cancel != nil {
+		s.cancel()
 	}
+	_ = s.srv.Close()
+	s.public.Deregister(s.id)
 }
 
 func (s *Server) Endpoint() string {
@@ -99,6 +115,20 @@ func (s *Server) Endpoint() string {
 	return fmt.Sprintf("http://localhost:%d", port)
 }
 
+// S3Endpoint returns the endpoint of the S3-compatible API for the buckets,
+// for use with tools built for S3. It only supports path-style requests.
+func (s *Server) S3Endpoint() string {
+	return s.Endpoint() + "/s3"
+}
+
+func (s *Server) bucketNames() []string {
+	names := make([]string, len(s.buckets))
+	for i, b := range s.buckets {
+		names[i] = b.Name
+	}
+	return names
+}
+
 func (s *Server) PublicBaseURL() string {
 	return fmt.Sprintf("%s/%s", s.public.BaseAddr(), s.id)
 }
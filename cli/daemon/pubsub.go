@@ -0,0 +1,96 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	daemonpb "encr.dev/proto/encore/daemon"
+)
+
+// PubSubDeadLetters manages the dead-letter queue of a subscription in the running app,
+// by calling the app's internal dead-letter queue endpoints.
+func (s *Server) PubSubDeadLetters(ctx context.Context, req *daemonpb.PubSubDeadLettersRequest) (*daemonpb.PubSubDeadLettersResponse, error) {
+	app, err := s.apps.Track(req.AppRoot)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	run := s.mgr.FindRunByAppID(app.PlatformOrLocalID())
+	if run == nil {
+		return nil, status.Error(codes.FailedPrecondition, "app is not running; start it with 'encore run'")
+	}
+
+	path := "http://" + run.ListenAddr + "/__encore/pubsub/dlq/" + url.PathEscape(req.Topic) + "/" + url.PathEscape(req.Subscription)
+	var (
+		method = http.MethodGet
+		body   []byte
+	)
+	switch req.Op {
+	case daemonpb.PubSubDeadLettersRequest_OP_LIST:
+	case daemonpb.PubSubDeadLettersRequest_OP_REPLAY:
+		method, path = http.MethodPost, path+"/replay"
+		body, _ = json.Marshal(map[string]any{"ids": req.MessageIds})
+	case daemonpb.PubSubDeadLettersRequest_OP_PURGE:
+		method = http.MethodDelete
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown operation %v", req.Op)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, path, bytes.NewReader(body))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "unable to reach app: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "unable to read response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &errResp) == nil && errResp.Message != "" {
+			return nil, status.Error(codes.Unknown, errResp.Message)
+		}
+		return nil, status.Errorf(codes.Unknown, "app responded with %s", resp.Status)
+	}
+
+	var result struct {
+		Count    int32 `json:"count"`
+		Messages []struct {
+			ID              string            `json:"id"`
+			PublishTime     time.Time         `json:"publish_time"`
+			DeliveryAttempt int32             `json:"delivery_attempt"`
+			Attributes      map[string]string `json:"attributes"`
+			Data            json.RawMessage   `json:"data"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, status.Errorf(codes.Internal, "invalid response from app: %v", err)
+	}
+
+	out := &daemonpb.PubSubDeadLettersResponse{Count: result.Count}
+	for _, m := range result.Messages {
+		out.Messages = append(out.Messages, &daemonpb.PubSubDeadLettersResponse_DeadLetter{
+			Id:              m.ID,
+			PublishTime:     timestamppb.New(m.PublishTime),
+			DeliveryAttempt: m.DeliveryAttempt,
+			Attributes:      m.Attributes,
+			Data:            m.Data,
+		})
+	}
+	return out, nil
+}
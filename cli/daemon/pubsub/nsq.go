@@ -3,6 +3,7 @@ package pubsub
 import (
 	"os"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/nsqio/go-nsq"
@@ -62,6 +63,10 @@ func (n *NSQDaemon) Start() error {
 			n.Opts.HTTPAddress = "127.0.0.1:0"
 			n.Opts.HTTPSAddress = "127.0.0.1:0"
 			n.Opts.MaxMsgSize = 10 * 1024 * 1024 // 10MB
+
+			// Allow messages published with pubsub.WithDeliveryDelay to be deferred
+			// for longer than the default of an hour.
+			n.Opts.MaxReqTimeout = 7 * 24 * time.Hour
 		}
 		nsq, err := nsqd.New(n.Opts)
 		if err != nil {
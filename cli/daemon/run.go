@@ -206,6 +206,9 @@ func (s *Server) Run(req *daemonpb.RunRequest, stream daemonpb.Daemon_RunServer)
 		"%s/%s", s.mgr.DashBaseURL, app.PlatformOrLocalID())))
 	_, _ = fmt.Fprintf(stderr, "  MCP SSE URL:                %s\n", aurora.Cyan(fmt.Sprintf(
 		"%s/sse?appID=%s", s.mcp.BaseURL, app.PlatformOrLocalID())))
+	if obj := runInstance.ResourceManager.GetObjects(); obj != nil {
+		_, _ = fmt.Fprintf(stderr, "  Object Storage S3 URL:      %s\n", aurora.Cyan(obj.S3Endpoint()))
+	}
 
 	if ns := runInstance.NS; !ns.Active || ns.Name != "default" {
 		_, _ = fmt.Fprintf(stderr, "  Namespace:                  %s\n", aurora.Cyan(ns.Name))
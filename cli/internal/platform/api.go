@@ -19,6 +19,7 @@ type CreateAppParams struct {
 	Name           string
 	InitialSecrets map[string]string
 	AppRootDir     string
+	Org            string `json:",omitempty"` // slug of the org to create the app in; the user's own if empty
 }
 
 type App struct {
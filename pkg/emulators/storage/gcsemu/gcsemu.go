@@ -8,6 +8,7 @@ import (
 	"crypto/md5"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -122,7 +123,11 @@ func (g *GcsEmu) Handler(w http.ResponseWriter, r *http.Request) {
 
 	switch r.Method {
 	case "DELETE":
-		g.handleGcsDelete(ctx, w, bucket, object, conds)
+		if id := r.Form.Get("upload_id"); id != "" {
+			g.handleGcsCancelUpload(w, id)
+		} else {
+			g.handleGcsDelete(ctx, w, bucket, object, conds)
+		}
 	case "GET":
 		if object == "" {
 			if strings.HasSuffix(r.URL.Path, "/o") {
@@ -133,7 +138,7 @@ func (g *GcsEmu) Handler(w http.ResponseWriter, r *http.Request) {
 		} else {
 			alt := r.URL.Query().Get("alt")
 			if alt == "media" || (p.IsPublic && alt == "") {
-				g.handleGcsMediaRequest(baseUrl, w, r.Header.Get("Accept-Encoding"), bucket, object)
+				g.handleGcsMediaRequest(baseUrl, w, r.Header.Get("Accept-Encoding"), r.Header.Get("Range"), bucket, object)
 			} else if alt == "json" || (!p.IsPublic && alt == "") {
 				g.handleGcsMetadataRequest(baseUrl, w, bucket, object)
 			} else {
@@ -277,7 +282,7 @@ func (g *GcsEmu) handleGcsDelete(ctx context.Context, w http.ResponseWriter, buc
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (g *GcsEmu) handleGcsMediaRequest(baseUrl HttpBaseUrl, w http.ResponseWriter, acceptEncoding, bucket, filename string) {
+func (g *GcsEmu) handleGcsMediaRequest(baseUrl HttpBaseUrl, w http.ResponseWriter, acceptEncoding, rangeHeader, bucket, filename string) {
 	obj, contents, err := g.store.Get(baseUrl, bucket, filename)
 	if err != nil {
 		g.gapiError(w, http.StatusInternalServerError, fmt.Sprintf("failed to check existence of %s/%s: %s", bucket, filename, err))
@@ -292,7 +297,7 @@ func (g *GcsEmu) handleGcsMediaRequest(baseUrl HttpBaseUrl, w http.ResponseWrite
 	w.Header().Set("X-Goog-Generation", strconv.FormatInt(obj.Generation, 10))
 	w.Header().Set("X-Goog-Metageneration", strconv.FormatInt(obj.Metageneration, 10))
 	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Expose-Headers", "Content-Type, Content-Length, Content-Encoding, Date, X-Goog-Generation, X-Goog-Metageneration")
+	w.Header().Set("Access-Control-Expose-Headers", "Content-Type, Content-Length, Content-Encoding, Date, X-Goog-Generation, X-Goog-Metageneration, X-Goog-Hash")
 	w.Header().Set("Content-Disposition", obj.ContentDisposition)
 
 	if obj.ContentEncoding == "gzip" {
@@ -315,8 +320,29 @@ func (g *GcsEmu) handleGcsMediaRequest(baseUrl HttpBaseUrl, w http.ResponseWrite
 		}
 	}
 
+	// Report the checksum of the object as stored, which clients verify full downloads against.
+	if obj.Crc32c != "" {
+		w.Header().Set("X-Goog-Hash", "crc32c="+obj.Crc32c)
+	}
+
+	// Serve the requested range of the contents, if any.
+	status := http.StatusOK
+	if rangeHeader != "" {
+		br, ok := parseRangeHeader(rangeHeader, int64(len(contents)))
+		if !ok {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", len(contents)))
+			g.gapiError(w, http.StatusRequestedRangeNotSatisfiable, fmt.Sprintf("range %q not satisfiable for %s/%s", rangeHeader, bucket, filename))
+			return
+		} else if br != nil {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", br.lo, br.hi, br.sz))
+			contents = contents[br.lo : br.hi+1]
+			status = http.StatusPartialContent
+		}
+	}
+
 	// Just write the contents
 	w.Header().Set("Content-Length", strconv.Itoa(len(contents)))
+	w.WriteHeader(status)
 	if _, err := w.Write(contents); err != nil {
 		g.gapiError(w, http.StatusInternalServerError, fmt.Sprintf("failed to copy from %s/%s: %s", bucket, filename, err))
 	}
@@ -455,6 +481,9 @@ type uploadData struct {
 	Object storage.Object
 	Conds  cloudstorage.Conditions
 	data   []byte
+
+	// finished is the uploaded object, once the upload has completed.
+	finished *storage.Object
 }
 
 func (g *GcsEmu) handleGcsNewBucket(ctx context.Context, w http.ResponseWriter, r *http.Request, _ cloudstorage.Conditions) {
@@ -559,6 +588,9 @@ func (g *GcsEmu) handleGcsNewObject(ctx context.Context, baseUrl HttpBaseUrl, w
 
 func (g *GcsEmu) handleGcsNewObjectResume(ctx context.Context, baseUrl HttpBaseUrl, w http.ResponseWriter, r *http.Request, id string) {
 	found, err := g.uploadIds.GetIFPresent(id)
+	if errors.Is(err, gcache.KeyNotFoundError) {
+		found, err = nil, nil
+	}
 	if err != nil {
 		g.gapiError(w, http.StatusInternalServerError, fmt.Sprintf("unexpected error: %s", err))
 		return
@@ -569,6 +601,11 @@ func (g *GcsEmu) handleGcsNewObjectResume(ctx context.Context, baseUrl HttpBaseU
 	}
 
 	u := found.(*uploadData)
+	if u.finished != nil {
+		// Like GCS, report the uploaded object for requests to a completed upload.
+		g.jsonRespond(w, u.finished)
+		return
+	}
 
 	contents, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -608,7 +645,9 @@ func (g *GcsEmu) handleGcsNewObjectResume(ctx context.Context, baseUrl HttpBaseU
 	// Are we done?
 	if byteRange.sz < 0 || len(u.data) < int(byteRange.sz) {
 		// Not finished; save the contents and tell the client to resume.
-		w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(u.data)-1))
+		if len(u.data) > 0 {
+			w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(u.data)-1))
+		}
 		w.Header().Set("Content-Type", u.Object.ContentType)
 		if r.Header.Get("X-Guploader-No-308") == "yes" {
 			w.Header().Set("X-Http-Status-Code-Override", "308")
@@ -626,12 +665,22 @@ func (g *GcsEmu) handleGcsNewObjectResume(ctx context.Context, baseUrl HttpBaseU
 		return
 	}
 
-	g.uploadIds.Remove(id)
+	u.finished, u.data = meta, nil
 	w.Header().Set("x-goog-generation", strconv.FormatInt(meta.Generation, 10))
 	w.Header().Set("X-Goog-Metageneration", strconv.FormatInt(meta.Metageneration, 10))
 	g.jsonRespond(w, meta)
 }
 
+// handleGcsCancelUpload cancels a resumable upload, discarding the uploaded data.
+func (g *GcsEmu) handleGcsCancelUpload(w http.ResponseWriter, id string) {
+	if !g.uploadIds.Remove(id) {
+		g.gapiError(w, http.StatusNotFound, "no such id")
+		return
+	}
+	// GCS responds with 499 Client Closed Request when an upload is canceled.
+	w.WriteHeader(499)
+}
+
 func (g *GcsEmu) finishUpload(ctx context.Context, baseUrl HttpBaseUrl, obj *storage.Object, contents []byte, bucket string, conds cloudstorage.Conditions) (*storage.Object, error) {
 	filename := obj.Name
 	bHash := md5.Sum(contents)
@@ -647,6 +696,7 @@ func (g *GcsEmu) finishUpload(ctx context.Context, baseUrl HttpBaseUrl, obj *sto
 		}
 	}
 	obj.Md5Hash = md5Hash
+	obj.Crc32c = crc32cHash(contents)
 	obj.Etag = strconv.Quote(md5Hash)
 
 	err := g.locks.Run(ctx, lockName(bucket, filename), func(ctx context.Context) error {
@@ -802,6 +852,7 @@ func (g *GcsEmu) finishCompose(baseUrl HttpBaseUrl, bucket string, dst composeOb
 	}
 	// composite objects do not have an MD5 hash (https://cloud.google.com/storage/docs/composite-objects)
 	meta.Md5Hash = ""
+	meta.Crc32c = crc32cHash(data)
 
 	dstMeta, err := g.store.GetMeta(baseUrl, bucket, dst.filename)
 	if err != nil {
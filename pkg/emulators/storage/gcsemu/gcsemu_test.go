@@ -28,6 +28,7 @@ var (
 	}{
 		{"Basics", testBasics},
 		{"MultipleFiles", testMultipleFiles},
+		{"DelimiterPages", testDelimiterPages},
 		{"HugeFile", testHugeFile},
 		{"HugeFile_MultipleOfChunkSize", testHugeFileMultipleOfChunkSize},
 		{"HugeFileWithConditional", testHugeFileWithConditional},
@@ -172,6 +173,34 @@ func testMultipleFiles(t *testing.T, bh BucketHandle) {
 	assert.Equal(t, iterator.Done, err, "iteration not finished or failed after first bucket object")
 }
 
+func testDelimiterPages(t *testing.T, bh BucketHandle) {
+	dir := "delimiter-test/"
+	ctx := context.Background()
+
+	for _, f := range []string{"a", "b/1", "b/2", "c", "d/1"} {
+		w := bh.Object(dir + f).NewWriter(ctx)
+		assert.NilError(t, write(w, v1), "failed to write file %s", dir+f)
+	}
+
+	// Each prefix is listed once, even when pages end on a prefix.
+	var names []string
+	token := ""
+	for {
+		var page []*storage.ObjectAttrs
+		it := bh.Objects(ctx, &storage.Query{Prefix: dir, Delimiter: "/"})
+		next, err := iterator.NewPager(it, 1, token).NextPage(&page)
+		assert.NilError(t, err, "failed to list page")
+		for _, obj := range page {
+			names = append(names, obj.Name+obj.Prefix)
+		}
+		if token = next; token == "" {
+			break
+		}
+	}
+	want := []string{dir + "a", dir + "b/", dir + "c", dir + "d/"}
+	assert.DeepEqual(t, want, names)
+}
+
 // Tests resumable GCS uploads.
 func testHugeFile(t *testing.T, bh BucketHandle) {
 	doHugeFile(t, bh, "gscemu-test/huge.txt", googleapi.DefaultUploadChunkSize+4*1024*1024)
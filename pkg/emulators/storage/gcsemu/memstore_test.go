@@ -58,4 +58,9 @@ func TestMemStore(t *testing.T) {
 		t.Parallel()
 		testRawHttp(t, bh, http.DefaultClient, svr.URL)
 	})
+
+	t.Run("ResumableSession", func(t *testing.T) {
+		t.Parallel()
+		testResumableSession(t, bh, svr.URL)
+	})
 }
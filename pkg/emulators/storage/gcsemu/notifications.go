@@ -0,0 +1,177 @@
+package gcsemu
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/api/storage/v1"
+)
+
+// Emulated GCS Pub/Sub notifications.
+//
+// Instead of publishing to Pub/Sub, the notifications are kept in memory
+// and clients poll for them over HTTP, using the notification IDs as a cursor.
+
+const (
+	// maxNotifications is the number of notifications kept in memory.
+	maxNotifications = 1000
+
+	// maxNotificationWait is the longest a poll for notifications waits for new ones.
+	maxNotificationWait = 30 * time.Second
+)
+
+// Notification is an emulated GCS Pub/Sub notification of a change to an object,
+// with the object resource as its payload (the JSON_API_V1 payload format).
+type Notification struct {
+	// ID orders the notifications.
+	ID int64 `json:"id"`
+
+	// Attributes are the Pub/Sub message attributes, like eventType and objectId.
+	Attributes map[string]string `json:"attributes"`
+
+	// Data is the object resource.
+	Data *storage.Object `json:"data"`
+}
+
+// Notifier records notifications of changes to the objects in a store.
+type Notifier struct {
+	mu            sync.Mutex
+	nextID        int64
+	notifications []*Notification
+	changed       chan struct{} // closed and replaced when a notification is added
+}
+
+// NewNotifier creates a new Notifier.
+func NewNotifier() *Notifier {
+	return &Notifier{nextID: 1, changed: make(chan struct{})}
+}
+
+// Store wraps store to record notifications of the changes made through it.
+func (n *Notifier) Store(store Store) Store {
+	return &notifyingStore{Store: store, n: n}
+}
+
+// Handler serves the notifications for a bucket, given by the "bucket" path value.
+// It responds with the notifications after the ID given by the "after" query parameter,
+// waiting for new ones if there are none. Without it, it responds immediately
+// with the ID to poll after for future notifications.
+func (n *Notifier) Handler(w http.ResponseWriter, r *http.Request) {
+	bucket := r.PathValue("bucket")
+	var resp struct {
+		Notifications []*Notification `json:"notifications"`
+		Next          int64           `json:"next"`
+	}
+
+	after, err := strconv.ParseInt(r.URL.Query().Get("after"), 10, 64)
+	if err != nil {
+		n.mu.Lock()
+		resp.Next = n.nextID - 1
+		n.mu.Unlock()
+	} else {
+		resp.Notifications, resp.Next = n.wait(r.Context(), bucket, after)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(&resp)
+}
+
+// wait waits for notifications for bucket after the given ID,
+// returning them and the ID to poll after next.
+func (n *Notifier) wait(ctx context.Context, bucket string, after int64) ([]*Notification, int64) {
+	timeout := time.NewTimer(maxNotificationWait)
+	defer timeout.Stop()
+	for {
+		res, next, changed := n.since(bucket, after)
+		if len(res) > 0 {
+			return res, next
+		}
+		select {
+		case <-changed:
+		case <-timeout.C:
+			return nil, next
+		case <-ctx.Done():
+			return nil, next
+		}
+	}
+}
+
+// since returns the notifications for bucket after the given ID, the ID
+// to poll after next, and a channel that's closed when there are new notifications.
+func (n *Notifier) since(bucket string, after int64) (res []*Notification, next int64, changed <-chan struct{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, notif := range n.notifications {
+		if notif.ID > after && notif.Attributes["bucketId"] == bucket {
+			res = append(res, notif)
+		}
+	}
+	return res, max(after, n.nextID-1), n.changed
+}
+
+func (n *Notifier) notify(eventType, bucket, filename string, obj *storage.Object) {
+	if obj == nil {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	notif := &Notification{
+		ID: n.nextID,
+		Attributes: map[string]string{
+			"eventType":        eventType,
+			"payloadFormat":    "JSON_API_V1",
+			"bucketId":         bucket,
+			"objectId":         filename,
+			"objectGeneration": strconv.FormatInt(obj.Generation, 10),
+			"eventTime":        time.Now().UTC().Format(time.RFC3339Nano),
+		},
+		Data: obj,
+	}
+	n.nextID++
+	n.notifications = append(n.notifications, notif)
+	if len(n.notifications) > maxNotifications {
+		n.notifications = n.notifications[len(n.notifications)-maxNotifications:]
+	}
+	close(n.changed)
+	n.changed = make(chan struct{})
+}
+
+// notifyingStore is a Store recording notifications of the changes made through it.
+type notifyingStore struct {
+	Store
+	n *Notifier
+}
+
+func (s *notifyingStore) Add(bucket string, filename string, contents []byte, meta *storage.Object) error {
+	if err := s.Store.Add(bucket, filename, contents, meta); err != nil {
+		return err
+	}
+	s.finalized(bucket, filename)
+	return nil
+}
+
+func (s *notifyingStore) Copy(srcBucket string, srcFile string, dstBucket string, dstFile string) (bool, error) {
+	ok, err := s.Store.Copy(srcBucket, srcFile, dstBucket, dstFile)
+	if ok && err == nil {
+		s.finalized(dstBucket, dstFile)
+	}
+	return ok, err
+}
+
+func (s *notifyingStore) Delete(bucket string, filename string) error {
+	obj, _ := s.Store.GetMeta(dontNeedUrls, bucket, filename)
+	if err := s.Store.Delete(bucket, filename); err != nil {
+		return err
+	}
+	s.n.notify("OBJECT_DELETE", bucket, filename, obj)
+	return nil
+}
+
+func (s *notifyingStore) finalized(bucket, filename string) {
+	obj, _ := s.Store.GetMeta(dontNeedUrls, bucket, filename)
+	s.n.notify("OBJECT_FINALIZE", bucket, filename, obj)
+}
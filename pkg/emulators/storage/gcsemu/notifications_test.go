@@ -0,0 +1,58 @@
+package gcsemu
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"google.golang.org/api/storage/v1"
+	"gotest.tools/v3/assert"
+)
+
+func TestNotifier(t *testing.T) {
+	n := NewNotifier()
+	store := n.Store(NewMemStore())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/notifications/{bucket}", n.Handler)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	type response struct {
+		Notifications []*Notification `json:"notifications"`
+		Next          int64           `json:"next"`
+	}
+	poll := func(bucket, query string) response {
+		t.Helper()
+		resp, err := http.Get(srv.URL + "/notifications/" + bucket + query)
+		assert.NilError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		var r response
+		assert.NilError(t, json.NewDecoder(resp.Body).Decode(&r))
+		return r
+	}
+
+	// Polling without a cursor returns the cursor for future notifications.
+	assert.NilError(t, store.Add("bucket", "old.txt", []byte("old"), &storage.Object{}))
+	start := poll("bucket", "")
+	assert.Equal(t, len(start.Notifications), 0)
+
+	assert.NilError(t, store.Add("bucket", "a.txt", []byte("hello"), &storage.Object{}))
+	assert.NilError(t, store.Add("other", "b.txt", []byte("other"), &storage.Object{}))
+	assert.NilError(t, store.Delete("bucket", "a.txt"))
+
+	got := poll("bucket", "?after="+strconv.FormatInt(start.Next, 10))
+	assert.Equal(t, len(got.Notifications), 2)
+	created, deleted := got.Notifications[0], got.Notifications[1]
+	assert.Equal(t, created.Attributes["eventType"], "OBJECT_FINALIZE")
+	assert.Equal(t, created.Attributes["objectId"], "a.txt")
+	assert.Equal(t, created.Data.Size, uint64(5))
+	assert.Equal(t, deleted.Attributes["eventType"], "OBJECT_DELETE")
+	assert.Equal(t, deleted.Attributes["objectId"], "a.txt")
+	assert.Equal(t, deleted.Attributes["objectGeneration"], created.Attributes["objectGeneration"])
+
+	// The cursor skips past notifications for other buckets.
+	assert.Equal(t, got.Next, start.Next+3)
+}
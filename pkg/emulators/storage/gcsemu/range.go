@@ -50,3 +50,46 @@ func parseByteRange(in string) *byteRange {
 
 	return &ret
 }
+
+// parseRangeHeader parses a Range request header with a single range,
+// like "bytes=0-99", "bytes=100-" or "bytes=-100", for an object of size sz.
+// It returns nil if the header is missing or malformed, in which case the
+// whole object is served, and satisfiable=false if the range is outside the object.
+func parseRangeHeader(in string, sz int64) (r *byteRange, satisfiable bool) {
+	spec, ok := strings.CutPrefix(in, "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return nil, true
+	}
+	first, last, ok := strings.Cut(spec, "-")
+	if !ok {
+		return nil, true
+	}
+
+	ret := byteRange{lo: 0, hi: sz - 1, sz: sz}
+	if first == "" {
+		// A suffix range, of the last n bytes.
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n <= 0 {
+			return nil, true
+		}
+		ret.lo = max(sz-n, 0)
+	} else {
+		lo, err := strconv.ParseInt(first, 10, 64)
+		if err != nil || lo < 0 {
+			return nil, true
+		}
+		ret.lo = lo
+		if last != "" {
+			hi, err := strconv.ParseInt(last, 10, 64)
+			if err != nil || hi < lo {
+				return nil, true
+			}
+			ret.hi = min(hi, sz-1)
+		}
+	}
+
+	if ret.lo >= sz {
+		return nil, false
+	}
+	return &ret, true
+}
@@ -3,6 +3,7 @@ package gcsemu
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"gotest.tools/v3/assert"
 )
 
@@ -21,3 +22,27 @@ func TestParseByteRange(t *testing.T) {
 		assert.Equal(t, tc.expect, *parseByteRange(tc.in))
 	}
 }
+
+func TestParseRangeHeader(t *testing.T) {
+	tcs := []struct {
+		in          string
+		expect      *byteRange
+		unsatisfied bool
+	}{
+		{in: "bytes=0-99", expect: &byteRange{lo: 0, hi: 99, sz: 1000}},
+		{in: "bytes=900-", expect: &byteRange{lo: 900, hi: 999, sz: 1000}},
+		{in: "bytes=-100", expect: &byteRange{lo: 900, hi: 999, sz: 1000}},
+		{in: "bytes=500-5000", expect: &byteRange{lo: 500, hi: 999, sz: 1000}},
+		{in: "bytes=1000-", unsatisfied: true},
+		{in: "bytes=0-1,5-6"},
+		{in: "items=0-1"},
+		{in: "bytes=5-1"},
+	}
+
+	for _, tc := range tcs {
+		t.Logf("test case: %s", tc.in)
+		got, ok := parseRangeHeader(tc.in, 1000)
+		assert.Equal(t, !tc.unsatisfied, ok)
+		assert.DeepEqual(t, tc.expect, got, cmp.AllowUnexported(byteRange{}))
+	}
+}
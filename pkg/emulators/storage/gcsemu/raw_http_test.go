@@ -297,3 +297,55 @@ func testRawHttp(t *testing.T, bh BucketHandle, httpClient *http.Client, url str
 		}
 	})
 }
+
+// Tests querying, completing and canceling resumable upload sessions.
+func testResumableSession(t *testing.T, bh BucketHandle, url string) {
+	const name = "gscemu-test-session.txt"
+
+	begin := func() string {
+		u := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?uploadType=resumable", url, bh.Name)
+		rsp, err := http.Post(u, "application/json", strings.NewReader(`{"name": "`+name+`"}`))
+		assert.NilError(t, err)
+		defer rsp.Body.Close()
+		assert.Equal(t, http.StatusCreated, rsp.StatusCode)
+		return rsp.Header.Get("Location")
+	}
+	send := func(method, session, contentRange, body string) *http.Response {
+		req, err := http.NewRequest(method, session, strings.NewReader(body))
+		assert.NilError(t, err)
+		if contentRange != "" {
+			req.Header.Set("Content-Range", contentRange)
+		}
+		rsp, err := http.DefaultClient.Do(req)
+		assert.NilError(t, err)
+		_ = rsp.Body.Close()
+		return rsp
+	}
+
+	// A new session has no data persisted.
+	session := begin()
+	rsp := send("PUT", session, "bytes */*", "")
+	assert.Equal(t, http.StatusPermanentRedirect, rsp.StatusCode)
+	assert.Equal(t, "", rsp.Header.Get("Range"))
+
+	rsp = send("PUT", session, "bytes 0-4/*", "hello")
+	assert.Equal(t, http.StatusPermanentRedirect, rsp.StatusCode)
+	assert.Equal(t, "bytes=0-4", rsp.Header.Get("Range"))
+
+	// Completed sessions report the uploaded object.
+	rsp = send("PUT", session, "bytes */5", "")
+	assert.Equal(t, http.StatusOK, rsp.StatusCode)
+	rsp = send("PUT", session, "bytes */*", "")
+	assert.Equal(t, http.StatusOK, rsp.StatusCode)
+
+	// Canceled sessions are gone.
+	session = begin()
+	rsp = send("DELETE", session, "", "")
+	assert.Equal(t, 499, rsp.StatusCode)
+	rsp = send("PUT", session, "bytes */*", "")
+	assert.Equal(t, http.StatusNotFound, rsp.StatusCode)
+
+	// Canceling doesn't delete the object.
+	_, err := bh.Object(name).Attrs(context.Background())
+	assert.NilError(t, err)
+}
@@ -1,8 +1,11 @@
 package gcsemu
 
 import (
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
+	"hash/crc32"
 	"net/http"
 	"regexp"
 	"strings"
@@ -139,3 +142,11 @@ func parseForwardedHeader(s string) forwarded {
 
 	return f
 }
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// crc32cHash returns the base64-encoded, big-endian CRC32C checksum of contents,
+// as GCS reports it in object metadata.
+func crc32cHash(contents []byte) string {
+	return base64.StdEncoding.EncodeToString(binary.BigEndian.AppendUint32(nil, crc32.Checksum(contents, crc32cTable)))
+}
@@ -33,6 +33,7 @@ func (g *GcsEmu) makeBucketListResults(ctx context.Context, baseUrl HttpBaseUrl,
 
 	moreResults := false
 	count := 0
+	lastName := "" // the last item or prefix recorded, for the next page token
 	err := g.store.Walk(ctx, bucket, func(ctx context.Context, filename string, fInfo os.FileInfo) error {
 		dbgWalk("walk: %s", filename)
 
@@ -65,12 +66,6 @@ func (g *GcsEmu) makeBucketListResults(ctx context.Context, baseUrl HttpBaseUrl,
 			return nil
 		}
 
-		if count >= maxResults {
-			moreResults = true
-			return errAbort
-		}
-		count++
-
 		if delimiter != "" {
 			// See if the filename (beyond the prefix) contains delimiter, if it does, don't record the item,
 			// instead record the prefix (including the delimiter).
@@ -79,14 +74,29 @@ func (g *GcsEmu) makeBucketListResults(ctx context.Context, baseUrl HttpBaseUrl,
 			if delimiterPos >= 0 {
 				// Got a hit, reconstruct the item's prefix, including the trailing delimiter
 				itemPrefix := filename[:len(prefix)+delimiterPos+len(delimiter)]
-				if !seenPrefixes[itemPrefix] {
-					seenPrefixes[itemPrefix] = true
-					prefixes = append(prefixes, itemPrefix)
+				// Prefixes up to the cursor were returned on previous pages.
+				if seenPrefixes[itemPrefix] || itemPrefix <= cursor {
+					return nil
+				}
+				if count >= maxResults {
+					moreResults = true
+					return errAbort
 				}
+				count++
+				seenPrefixes[itemPrefix] = true
+				prefixes = append(prefixes, itemPrefix)
+				lastName = itemPrefix
 				return nil
 			}
 		}
 
+		if count >= maxResults {
+			moreResults = true
+			return errAbort
+		}
+		count++
+		lastName = filename
+
 		found = append(found, item{
 			filename: filename,
 			fInfo:    fInfo,
@@ -123,9 +133,13 @@ func (g *GcsEmu) makeBucketListResults(ctx context.Context, baseUrl HttpBaseUrl,
 	}
 
 	var nextPageToken = ""
-	if moreResults && len(items) > 0 {
-		lastItemName := items[len(items)-1].Name
-		nextPageToken = gcsutil.EncodePageToken(lastItemName)
+	if moreResults {
+		if len(items) == len(found) {
+			nextPageToken = gcsutil.EncodePageToken(lastName)
+		} else if len(items) > 0 {
+			// Resume after the last resolved item.
+			nextPageToken = gcsutil.EncodePageToken(items[len(items)-1].Name)
+		}
 	}
 
 	rsp := storage.Objects{
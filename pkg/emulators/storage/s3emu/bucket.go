@@ -0,0 +1,286 @@
+package s3emu
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/storage/v1"
+)
+
+// maxKeys is the maximum number of keys returned by a list request.
+const maxKeys = 1000
+
+func (s *Server) listBuckets(w http.ResponseWriter, req *http.Request) {
+	type bucket struct {
+		Name         string
+		CreationDate string
+	}
+	var buckets []bucket
+	if s.buckets != nil {
+		for _, name := range s.buckets() {
+			meta, err := s.store.GetBucketMeta("", name)
+			if err != nil {
+				writeError(w, req, err)
+				return
+			} else if meta != nil {
+				buckets = append(buckets, bucket{Name: name, CreationDate: formatTime(meta.Updated)})
+			}
+		}
+	}
+
+	writeXML(w, http.StatusOK, struct {
+		XMLName xml.Name `xml:"ListAllMyBucketsResult"`
+		NS      string   `xml:"xmlns,attr"`
+		Buckets []bucket `xml:"Buckets>Bucket"`
+	}{NS: s3NS, Buckets: buckets})
+}
+
+func (s *Server) handleBucket(w http.ResponseWriter, req *http.Request) {
+	bucket := req.PathValue("bucket")
+	if meta, err := s.store.GetBucketMeta("", bucket); err != nil {
+		writeError(w, req, err)
+		return
+	} else if meta == nil {
+		writeError(w, req, errNoSuchBucket(bucket))
+		return
+	}
+
+	q := req.URL.Query()
+	var err error
+	switch {
+	case req.Method == http.MethodHead:
+		// The bucket exists.
+	case req.Method == http.MethodGet && q.Has("location"):
+		writeXML(w, http.StatusOK, struct {
+			XMLName xml.Name `xml:"LocationConstraint"`
+			NS      string   `xml:"xmlns,attr"`
+		}{NS: s3NS})
+	case req.Method == http.MethodGet:
+		err = s.listObjects(w, req, bucket)
+	case req.Method == http.MethodPost && q.Has("delete"):
+		err = s.deleteObjects(w, req, bucket)
+	default:
+		err = errNotImplemented(req)
+	}
+	if err != nil {
+		writeError(w, req, err)
+	}
+}
+
+// listObjects handles ListObjects and ListObjectsV2 requests.
+func (s *Server) listObjects(w http.ResponseWriter, req *http.Request, bucket string) error {
+	q := req.URL.Query()
+	v2 := q.Get("list-type") == "2"
+	prefix, delimiter := q.Get("prefix"), q.Get("delimiter")
+	limit := maxKeys
+	if v := q.Get("max-keys"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return s3Errorf(http.StatusBadRequest, "InvalidArgument", "invalid max-keys %q", v)
+		}
+		limit = min(n, maxKeys)
+	}
+
+	// Keys are listed after the marker, or for V2 the continuation token or start-after key.
+	after := q.Get("marker")
+	if v2 {
+		after = q.Get("start-after")
+		if token := q.Get("continuation-token"); token != "" {
+			after = token
+		}
+	}
+
+	names, err := s.objectNames(req.Context(), bucket, prefix)
+	if err != nil {
+		return err
+	}
+
+	type object struct {
+		Key          string
+		LastModified string
+		ETag         string
+		Size         uint64
+		StorageClass string
+	}
+	type commonPrefix struct {
+		Prefix string
+	}
+	var (
+		contents  []object
+		prefixes  []commonPrefix
+		truncated bool
+		last      string
+	)
+	for _, name := range names {
+		if name <= after {
+			continue
+		}
+		if len(contents)+len(prefixes) >= limit {
+			truncated = true
+			break
+		}
+
+		if delimiter != "" {
+			if idx := strings.Index(name[len(prefix):], delimiter); idx >= 0 {
+				p := name[:len(prefix)+idx+len(delimiter)]
+				if len(prefixes) == 0 || prefixes[len(prefixes)-1].Prefix != p {
+					prefixes = append(prefixes, commonPrefix{Prefix: p})
+				}
+				// Skip the rest of the keys with the prefix, by continuing after the
+				// last possible key with it. The delimiter is never empty here.
+				last = p + "\U0010FFFF"
+				after = last
+				continue
+			}
+		}
+
+		meta, err := s.store.GetMeta("", bucket, name)
+		if err != nil {
+			return err
+		} else if meta == nil {
+			// Removed since it was listed.
+			continue
+		}
+		contents = append(contents, object{
+			Key:          name,
+			LastModified: formatTime(meta.Updated),
+			ETag:         etag(meta),
+			Size:         meta.Size,
+			StorageClass: "STANDARD",
+		})
+		last = name
+	}
+
+	type result struct {
+		XMLName        xml.Name       `xml:"ListBucketResult"`
+		NS             string         `xml:"xmlns,attr"`
+		Name           string         `xml:"Name"`
+		Prefix         string         `xml:"Prefix"`
+		Delimiter      string         `xml:"Delimiter,omitempty"`
+		MaxKeys        int            `xml:"MaxKeys"`
+		IsTruncated    bool           `xml:"IsTruncated"`
+		Contents       []object       `xml:"Contents"`
+		CommonPrefixes []commonPrefix `xml:"CommonPrefixes"`
+
+		// ListObjects (V1) fields.
+		Marker     *string `xml:"Marker"`
+		NextMarker string  `xml:"NextMarker,omitempty"`
+
+		// ListObjectsV2 fields.
+		KeyCount              *int   `xml:"KeyCount"`
+		ContinuationToken     string `xml:"ContinuationToken,omitempty"`
+		NextContinuationToken string `xml:"NextContinuationToken,omitempty"`
+		StartAfter            string `xml:"StartAfter,omitempty"`
+	}
+	res := result{
+		NS:             s3NS,
+		Name:           req.PathValue("bucket"),
+		Prefix:         prefix,
+		Delimiter:      delimiter,
+		MaxKeys:        limit,
+		IsTruncated:    truncated,
+		Contents:       contents,
+		CommonPrefixes: prefixes,
+	}
+	if v2 {
+		n := len(contents) + len(prefixes)
+		res.KeyCount = &n
+		res.ContinuationToken = q.Get("continuation-token")
+		res.StartAfter = q.Get("start-after")
+		if truncated {
+			res.NextContinuationToken = last
+		}
+	} else {
+		marker := q.Get("marker")
+		res.Marker = &marker
+		if truncated {
+			res.NextMarker = last
+		}
+	}
+	writeXML(w, http.StatusOK, res)
+	return nil
+}
+
+// objectNames returns the sorted names of the objects in bucket with the given prefix.
+func (s *Server) objectNames(ctx context.Context, bucket, prefix string) ([]string, error) {
+	var names []string
+	err := s.store.Walk(ctx, bucket, func(ctx context.Context, filename string, fInfo os.FileInfo) error {
+		if (fInfo == nil || !fInfo.IsDir()) && strings.HasPrefix(filename, prefix) {
+			names = append(names, filename)
+		}
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errNoSuchBucket(bucket)
+	} else if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// deleteObjects handles DeleteObjects requests.
+func (s *Server) deleteObjects(w http.ResponseWriter, req *http.Request, bucket string) error {
+	var body struct {
+		Quiet   bool
+		Objects []struct {
+			Key string
+		} `xml:"Object"`
+	}
+	if err := readXML(req, &body); err != nil {
+		return err
+	}
+
+	type deleted struct {
+		Key string
+	}
+	type deleteError struct {
+		Key     string
+		Code    string
+		Message string
+	}
+	var (
+		deletedKeys []deleted
+		errs        []deleteError
+	)
+	for _, obj := range body.Objects {
+		if err := s.deleteObject(bucket, obj.Key); err != nil {
+			errs = append(errs, deleteError{Key: obj.Key, Code: "InternalError", Message: err.Error()})
+		} else if !body.Quiet {
+			deletedKeys = append(deletedKeys, deleted{Key: obj.Key})
+		}
+	}
+
+	writeXML(w, http.StatusOK, struct {
+		XMLName xml.Name      `xml:"DeleteResult"`
+		NS      string        `xml:"xmlns,attr"`
+		Deleted []deleted     `xml:"Deleted"`
+		Errors  []deleteError `xml:"Error"`
+	}{NS: s3NS, Deleted: deletedKeys, Errors: errs})
+	return nil
+}
+
+// formatTime formats a GCS timestamp for S3 responses.
+func formatTime(rfc3339 string) string {
+	t, err := time.Parse(time.RFC3339Nano, rfc3339)
+	if err != nil {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// etag returns the S3 ETag of an object: the quoted hex MD5 hash
+// of its contents if known, and otherwise its quoted GCS ETag.
+func etag(meta *storage.Object) string {
+	if md5, err := decodeMD5(meta.Md5Hash); err == nil && md5 != "" {
+		return `"` + md5 + `"`
+	}
+	return `"` + meta.Etag + `"`
+}
@@ -0,0 +1,94 @@
+package s3emu
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// isAWSChunked reports whether the request body is sent with the aws-chunked
+// encoding, which AWS SDKs use for streaming uploads and checksum trailers.
+func isAWSChunked(req *http.Request) bool {
+	return strings.HasPrefix(req.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") ||
+		strings.Contains(req.Header.Get("Content-Encoding"), "aws-chunked")
+}
+
+// stripAWSChunked removes aws-chunked from a Content-Encoding header value,
+// as it's the encoding of the request rather than of the object.
+func stripAWSChunked(encoding string) string {
+	var encs []string
+	for _, enc := range strings.Split(encoding, ",") {
+		if enc = strings.TrimSpace(enc); enc != "" && enc != "aws-chunked" {
+			encs = append(encs, enc)
+		}
+	}
+	return strings.Join(encs, ",")
+}
+
+// chunkedReader decodes an aws-chunked body. Each chunk is its hex-encoded size,
+// optionally followed by a signature, and its data, each terminated by CRLF.
+// The body ends with a zero-sized chunk, which may be followed by trailers.
+// Chunk signatures and checksum trailers are not verified.
+type chunkedReader struct {
+	r    *bufio.Reader
+	left int64 // bytes left in the current chunk
+	done bool
+}
+
+func newChunkedReader(r io.Reader) *chunkedReader {
+	return &chunkedReader{r: bufio.NewReader(r)}
+}
+
+func (c *chunkedReader) Read(p []byte) (int, error) {
+	for c.left == 0 {
+		if c.done {
+			return 0, io.EOF
+		}
+		if err := c.nextChunk(); err != nil {
+			return 0, err
+		}
+	}
+
+	if int64(len(p)) > c.left {
+		p = p[:c.left]
+	}
+	n, err := c.r.Read(p)
+	c.left -= int64(n)
+	if c.left == 0 && err == nil {
+		err = c.readCRLF()
+	}
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// nextChunk reads the header of the next chunk.
+func (c *chunkedReader) nextChunk() error {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("read chunk header: %w", io.ErrUnexpectedEOF)
+	}
+	sizeStr, _, _ := strings.Cut(strings.TrimRight(line, "\r\n"), ";")
+	size, err := strconv.ParseInt(sizeStr, 16, 64)
+	if err != nil || size < 0 {
+		return fmt.Errorf("invalid chunk size %q", sizeStr)
+	}
+	c.left = size
+	c.done = size == 0
+	return nil
+}
+
+func (c *chunkedReader) readCRLF() error {
+	var buf [2]byte
+	if _, err := io.ReadFull(c.r, buf[:]); err != nil {
+		return err
+	} else if string(buf[:]) != "\r\n" {
+		return errors.New("malformed chunk: missing CRLF after chunk data")
+	}
+	return nil
+}
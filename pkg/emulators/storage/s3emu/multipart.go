@@ -0,0 +1,167 @@
+package s3emu
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/rs/xid"
+	"google.golang.org/api/storage/v1"
+)
+
+// multipartUpload is an in-progress multipart upload.
+// Its parts are kept in memory until it's completed.
+type multipartUpload struct {
+	bucket, key string
+	meta        *storage.Object
+	parts       map[int][]byte
+}
+
+func (s *Server) createMultipartUpload(w http.ResponseWriter, req *http.Request, bucket, key string) error {
+	id := xid.New().String()
+	s.mu.Lock()
+	s.uploads[id] = &multipartUpload{
+		bucket: bucket,
+		key:    key,
+		meta:   objectMeta(req.Header),
+		parts:  make(map[int][]byte),
+	}
+	s.mu.Unlock()
+
+	writeXML(w, http.StatusOK, struct {
+		XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+		NS       string   `xml:"xmlns,attr"`
+		Bucket   string
+		Key      string
+		UploadId string
+	}{NS: s3NS, Bucket: bucket, Key: key, UploadId: id})
+	return nil
+}
+
+func (s *Server) uploadPart(w http.ResponseWriter, req *http.Request, bucket, key string) error {
+	partNumber, err := strconv.Atoi(req.URL.Query().Get("partNumber"))
+	if err != nil || partNumber < 1 || partNumber > 10000 {
+		return s3Errorf(http.StatusBadRequest, "InvalidArgument", "invalid part number %q", req.URL.Query().Get("partNumber"))
+	}
+	data, err := readBody(req)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	u, err := s.upload(req, bucket, key)
+	if err == nil {
+		u.parts[partNumber] = data
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("ETag", partETag(data))
+	return nil
+}
+
+func (s *Server) completeMultipartUpload(w http.ResponseWriter, req *http.Request, bucket, key string) error {
+	var body struct {
+		Parts []struct {
+			PartNumber int
+			ETag       string
+		} `xml:"Part"`
+	}
+	if err := readXML(req, &body); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	u, err := s.upload(req, bucket, key)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// Assemble the object from the listed parts, which must be in ascending order.
+	var (
+		contents bytes.Buffer
+		hashes   []byte
+		prev     int
+	)
+	for _, p := range body.Parts {
+		if p.PartNumber <= prev {
+			return s3Errorf(http.StatusBadRequest, "InvalidPartOrder", "parts must be listed in ascending order")
+		}
+		prev = p.PartNumber
+
+		s.mu.Lock()
+		data, ok := u.parts[p.PartNumber]
+		s.mu.Unlock()
+		if !ok || partETag(data) != p.ETag {
+			return s3Errorf(http.StatusBadRequest, "InvalidPart", "part %d was not uploaded or its ETag doesn't match", p.PartNumber)
+		}
+		contents.Write(data)
+		sum := md5.Sum(data)
+		hashes = append(hashes, sum[:]...)
+	}
+	if len(body.Parts) == 0 {
+		return s3Errorf(http.StatusBadRequest, "MalformedXML", "no parts listed")
+	}
+
+	meta := u.meta
+	meta.Md5Hash = md5Hash(contents.Bytes())
+	meta.Crc32c = crc32cHash(contents.Bytes())
+	if err := s.store.Add(bucket, key, contents.Bytes(), meta); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	delete(s.uploads, req.URL.Query().Get("uploadId"))
+	s.mu.Unlock()
+
+	// Like S3, the ETag of a multipart object is the hash of its parts' hashes and the number of parts.
+	sum := md5.Sum(hashes)
+	writeXML(w, http.StatusOK, struct {
+		XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+		NS      string   `xml:"xmlns,attr"`
+		Bucket  string
+		Key     string
+		ETag    string
+	}{NS: s3NS, Bucket: bucket, Key: key, ETag: fmt.Sprintf(`"%s-%d"`, hex.EncodeToString(sum[:]), len(body.Parts))})
+	return nil
+}
+
+func (s *Server) abortMultipartUpload(w http.ResponseWriter, req *http.Request) error {
+	id := req.URL.Query().Get("uploadId")
+	s.mu.Lock()
+	_, ok := s.uploads[id]
+	delete(s.uploads, id)
+	s.mu.Unlock()
+	if !ok {
+		return errNoSuchUpload(id)
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// upload returns the multipart upload the request is for.
+// It must be called with s.mu held.
+func (s *Server) upload(req *http.Request, bucket, key string) (*multipartUpload, error) {
+	id := req.URL.Query().Get("uploadId")
+	u, ok := s.uploads[id]
+	if !ok || u.bucket != bucket || u.key != key {
+		return nil, errNoSuchUpload(id)
+	}
+	return u, nil
+}
+
+func errNoSuchUpload(id string) error {
+	return s3Errorf(http.StatusNotFound, "NoSuchUpload", "the multipart upload %s does not exist", id)
+}
+
+// partETag returns the ETag of an uploaded part, the quoted hex MD5 hash of its data.
+func partETag(data []byte) string {
+	sum := md5.Sum(data)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
@@ -0,0 +1,221 @@
+package s3emu
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/api/storage/v1"
+)
+
+// metaHeaderPrefix is the prefix of headers with user-defined object metadata.
+const metaHeaderPrefix = "X-Amz-Meta-"
+
+func (s *Server) handleObject(w http.ResponseWriter, req *http.Request) {
+	bucket, key := req.PathValue("bucket"), req.PathValue("key")
+	if meta, err := s.store.GetBucketMeta("", bucket); err != nil {
+		writeError(w, req, err)
+		return
+	} else if meta == nil {
+		writeError(w, req, errNoSuchBucket(bucket))
+		return
+	}
+
+	q := req.URL.Query()
+	var err error
+	switch {
+	case (req.Method == http.MethodGet || req.Method == http.MethodHead) && !q.Has("uploadId"):
+		err = s.getObject(w, req, bucket, key)
+	case req.Method == http.MethodPut && q.Has("uploadId"):
+		err = s.uploadPart(w, req, bucket, key)
+	case req.Method == http.MethodPut && req.Header.Get("X-Amz-Copy-Source") != "":
+		err = s.copyObject(w, req, bucket, key)
+	case req.Method == http.MethodPut:
+		err = s.putObject(w, req, bucket, key)
+	case req.Method == http.MethodDelete && q.Has("uploadId"):
+		err = s.abortMultipartUpload(w, req)
+	case req.Method == http.MethodDelete:
+		if err = s.deleteObject(bucket, key); err == nil {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	case req.Method == http.MethodPost && q.Has("uploads"):
+		err = s.createMultipartUpload(w, req, bucket, key)
+	case req.Method == http.MethodPost && q.Has("uploadId"):
+		err = s.completeMultipartUpload(w, req, bucket, key)
+	default:
+		err = errNotImplemented(req)
+	}
+	if err != nil {
+		writeError(w, req, err)
+	}
+}
+
+// getObject handles GetObject and HeadObject requests,
+// including range and conditional requests.
+func (s *Server) getObject(w http.ResponseWriter, req *http.Request, bucket, key string) error {
+	meta, contents, err := s.store.Get("", bucket, key)
+	if err != nil {
+		return err
+	} else if meta == nil {
+		return errNoSuchKey(key)
+	}
+
+	h := w.Header()
+	h.Set("ETag", etag(meta))
+	h.Set("Accept-Ranges", "bytes")
+	if meta.ContentType != "" {
+		h.Set("Content-Type", meta.ContentType)
+	}
+	if meta.ContentEncoding != "" {
+		h.Set("Content-Encoding", meta.ContentEncoding)
+	}
+	if meta.ContentDisposition != "" {
+		h.Set("Content-Disposition", meta.ContentDisposition)
+	}
+	if meta.CacheControl != "" {
+		h.Set("Cache-Control", meta.CacheControl)
+	}
+	for k, v := range meta.Metadata {
+		h.Set(metaHeaderPrefix+k, v)
+	}
+
+	// ServeContent handles HEAD requests, ranges and conditional requests.
+	modTime, _ := time.Parse(time.RFC3339Nano, meta.Updated)
+	http.ServeContent(w, req, key, modTime, bytes.NewReader(contents))
+	return nil
+}
+
+// putObject handles PutObject requests.
+func (s *Server) putObject(w http.ResponseWriter, req *http.Request, bucket, key string) error {
+	contents, err := readBody(req)
+	if err != nil {
+		return err
+	}
+	meta := objectMeta(req.Header)
+	meta.Md5Hash = md5Hash(contents)
+	meta.Crc32c = crc32cHash(contents)
+	if err := s.store.Add(bucket, key, contents, meta); err != nil {
+		return err
+	}
+	w.Header().Set("ETag", etag(meta))
+	return nil
+}
+
+// copyObject handles CopyObject requests. The metadata is copied from
+// the source object, unless the metadata directive is REPLACE.
+func (s *Server) copyObject(w http.ResponseWriter, req *http.Request, bucket, key string) error {
+	source := req.Header.Get("X-Amz-Copy-Source")
+	source, _, _ = strings.Cut(source, "?") // ignore any versionId
+	source, err := url.PathUnescape(strings.TrimPrefix(source, "/"))
+	if err != nil {
+		return s3Errorf(http.StatusBadRequest, "InvalidArgument", "invalid copy source %q", req.Header.Get("X-Amz-Copy-Source"))
+	}
+	srcBucket, srcKey, ok := strings.Cut(source, "/")
+	if !ok || srcKey == "" {
+		return s3Errorf(http.StatusBadRequest, "InvalidArgument", "invalid copy source %q", req.Header.Get("X-Amz-Copy-Source"))
+	}
+
+	srcMeta, contents, err := s.store.Get("", srcBucket, srcKey)
+	if err != nil {
+		return err
+	} else if srcMeta == nil {
+		return errNoSuchKey(srcKey)
+	}
+
+	if req.Header.Get("X-Amz-Metadata-Directive") == "REPLACE" {
+		meta := objectMeta(req.Header)
+		meta.Md5Hash, meta.Crc32c = srcMeta.Md5Hash, srcMeta.Crc32c
+		err = s.store.Add(bucket, key, contents, meta)
+	} else if ok, err = s.store.Copy(srcBucket, srcKey, bucket, key); err == nil && !ok {
+		err = errNoSuchKey(srcKey)
+	}
+	if err != nil {
+		return err
+	}
+
+	meta, err := s.store.GetMeta("", bucket, key)
+	if err != nil {
+		return err
+	} else if meta == nil {
+		return errNoSuchKey(key)
+	}
+	writeXML(w, http.StatusOK, struct {
+		XMLName      xml.Name `xml:"CopyObjectResult"`
+		NS           string   `xml:"xmlns,attr"`
+		ETag         string
+		LastModified string
+	}{NS: s3NS, ETag: etag(meta), LastModified: formatTime(meta.Updated)})
+	return nil
+}
+
+// deleteObject deletes an object. Deleting a missing object is not an error.
+func (s *Server) deleteObject(bucket, key string) error {
+	if err := s.store.Delete(bucket, key); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// objectMeta returns the object metadata to store from the request headers.
+func objectMeta(h http.Header) *storage.Object {
+	meta := &storage.Object{
+		ContentType:        h.Get("Content-Type"),
+		ContentEncoding:    stripAWSChunked(h.Get("Content-Encoding")),
+		ContentDisposition: h.Get("Content-Disposition"),
+		CacheControl:       h.Get("Cache-Control"),
+	}
+	for k, vs := range h {
+		if name, ok := strings.CutPrefix(http.CanonicalHeaderKey(k), metaHeaderPrefix); ok && len(vs) > 0 {
+			if meta.Metadata == nil {
+				meta.Metadata = make(map[string]string)
+			}
+			meta.Metadata[strings.ToLower(name)] = vs[0]
+		}
+	}
+	return meta
+}
+
+// readBody reads the request body, decoding it if it's sent with the aws-chunked encoding.
+func readBody(req *http.Request) ([]byte, error) {
+	var r io.Reader = req.Body
+	if isAWSChunked(req) {
+		r = newChunkedReader(req.Body)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, s3Errorf(http.StatusBadRequest, "IncompleteBody", "%v", err)
+	}
+	return data, nil
+}
+
+// md5Hash returns the base64-encoded MD5 hash of data, as stored in GCS object metadata.
+func md5Hash(data []byte) string {
+	sum := md5.Sum(data)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// crc32cHash returns the base64-encoded CRC32C checksum of data, as stored in GCS object metadata.
+func crc32cHash(data []byte) string {
+	return base64.StdEncoding.EncodeToString(binary.BigEndian.AppendUint32(nil, crc32.Checksum(data, crc32cTable)))
+}
+
+// decodeMD5 converts a base64-encoded MD5 hash to hex.
+func decodeMD5(hash string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(hash)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
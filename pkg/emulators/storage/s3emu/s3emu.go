@@ -0,0 +1,176 @@
+// Package s3emu serves an S3-compatible API on top of a gcsemu.Store,
+// so that tools built for S3 can be used with the local object storage emulator.
+//
+// Only path-style requests are supported, like GET /bucket/key.
+// Requests are not authenticated: any credentials are accepted, and
+// presigned URLs are only checked for having expired.
+package s3emu
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"encr.dev/pkg/emulators/storage/gcsemu"
+)
+
+// s3NS is the XML namespace of S3 API responses.
+const s3NS = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+// Options are the options for New.
+type Options struct {
+	// Store is the store to serve the buckets from.
+	Store gcsemu.Store
+
+	// Buckets returns the names of the buckets, for listing them.
+	Buckets func() []string
+}
+
+// Server serves the S3-compatible API.
+type Server struct {
+	store   gcsemu.Store
+	buckets func() []string
+	mux     *http.ServeMux
+
+	mu      sync.Mutex
+	uploads map[string]*multipartUpload // in-progress multipart uploads, by id
+}
+
+// New returns a new Server.
+func New(opts Options) *Server {
+	s := &Server{
+		store:   opts.Store,
+		buckets: opts.Buckets,
+		mux:     http.NewServeMux(),
+		uploads: make(map[string]*multipartUpload),
+	}
+	s.mux.HandleFunc("GET /{$}", s.listBuckets)
+	s.mux.HandleFunc("/{bucket}", s.handleBucket)
+	s.mux.HandleFunc("/{bucket}/{$}", s.handleBucket)
+	s.mux.HandleFunc("/{bucket}/{key...}", s.handleObject)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// Allow browsers to use the API directly, such as for uploads from a frontend.
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Expose-Headers", "ETag, Content-Type, Content-Length, Content-Range, Last-Modified, x-amz-version-id")
+	if req.Method == http.MethodOptions {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, PUT, POST, DELETE")
+		if h := req.Header.Get("Access-Control-Request-Headers"); h != "" {
+			w.Header().Set("Access-Control-Allow-Headers", h)
+		}
+		w.Header().Set("Access-Control-Max-Age", "3600")
+		return
+	}
+
+	if err := checkPresigned(req, time.Now()); err != nil {
+		writeError(w, req, err)
+		return
+	}
+	s.mux.ServeHTTP(w, req)
+}
+
+// checkPresigned checks that a presigned request hasn't expired.
+// The signature itself isn't verified.
+func checkPresigned(req *http.Request, now time.Time) error {
+	const dateLayout = "20060102T150405Z"
+	const gracePeriod = 30 * time.Second
+
+	q := req.URL.Query()
+	if !q.Has("X-Amz-Signature") {
+		return nil
+	}
+	for _, param := range []string{"X-Amz-Algorithm", "X-Amz-Credential", "X-Amz-Date", "X-Amz-Expires", "X-Amz-Signature"} {
+		if q.Get(param) == "" {
+			return s3Errorf(http.StatusForbidden, "AuthorizationQueryParametersError", "missing or empty query param %q", param)
+		}
+	}
+
+	date, err := time.Parse(dateLayout, q.Get("X-Amz-Date"))
+	if err != nil {
+		return s3Errorf(http.StatusForbidden, "AuthorizationQueryParametersError", "invalid X-Amz-Date %q", q.Get("X-Amz-Date"))
+	}
+	expires, err := strconv.Atoi(q.Get("X-Amz-Expires"))
+	if err != nil || expires < 0 || expires > 7*24*60*60 {
+		return s3Errorf(http.StatusForbidden, "AuthorizationQueryParametersError", "invalid X-Amz-Expires %q", q.Get("X-Amz-Expires"))
+	}
+
+	if date.After(now.Add(gracePeriod)) {
+		return s3Errorf(http.StatusForbidden, "AccessDenied", "request is not valid yet")
+	} else if date.Add(time.Duration(expires) * time.Second).Before(now.Add(-gracePeriod)) {
+		return s3Errorf(http.StatusForbidden, "AccessDenied", "request has expired")
+	}
+	return nil
+}
+
+// s3Error is an error returned to the client as an S3 error response.
+type s3Error struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *s3Error) Error() string { return e.code + ": " + e.message }
+
+func s3Errorf(status int, code, format string, args ...any) error {
+	return &s3Error{status: status, code: code, message: fmt.Sprintf(format, args...)}
+}
+
+func errNoSuchBucket(bucket string) error {
+	return s3Errorf(http.StatusNotFound, "NoSuchBucket", "the bucket %s does not exist", bucket)
+}
+
+func errNoSuchKey(key string) error {
+	return s3Errorf(http.StatusNotFound, "NoSuchKey", "the key %s does not exist", key)
+}
+
+func errNotImplemented(req *http.Request) error {
+	return s3Errorf(http.StatusNotImplemented, "NotImplemented", "%s %s is not supported by the local emulator", req.Method, req.URL.RequestURI())
+}
+
+// writeError writes err as an S3 error response.
+// Errors that aren't s3Errors are reported as internal errors.
+func writeError(w http.ResponseWriter, req *http.Request, err error) {
+	var e *s3Error
+	if !errors.As(err, &e) {
+		e = &s3Error{status: http.StatusInternalServerError, code: "InternalError", message: err.Error()}
+	}
+
+	// HEAD responses have no body, so the status is all the client gets.
+	if req.Method == http.MethodHead {
+		w.WriteHeader(e.status)
+		return
+	}
+	writeXML(w, e.status, struct {
+		XMLName  xml.Name `xml:"Error"`
+		Code     string
+		Message  string
+		Resource string
+	}{Code: e.code, Message: e.message, Resource: req.URL.Path})
+}
+
+// writeXML writes an XML response.
+func writeXML(w http.ResponseWriter, status int, v any) {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(data)
+}
+
+// readXML reads an XML request body into v.
+func readXML(req *http.Request, v any) error {
+	if err := xml.NewDecoder(req.Body).Decode(v); err != nil {
+		return s3Errorf(http.StatusBadRequest, "MalformedXML", "%v", err)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package s3emu
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+
+	"encr.dev/pkg/emulators/storage/gcsemu"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	store := gcsemu.NewMemStore()
+	assert.NilError(t, store.CreateBucket("bucket"))
+	srv := httptest.NewServer(New(Options{
+		Store:   store,
+		Buckets: func() []string { return []string{"bucket"} },
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// do sends a request and returns the response status, headers and body.
+func do(t *testing.T, method, url string, body string, headers ...string) (int, http.Header, string) {
+	t.Helper()
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, url, r)
+	assert.NilError(t, err)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	resp, err := http.DefaultClient.Do(req)
+	assert.NilError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	assert.NilError(t, err)
+	return resp.StatusCode, resp.Header, string(data)
+}
+
+func TestObjects(t *testing.T) {
+	srv := newTestServer(t)
+	u := srv.URL + "/bucket/dir/hello.txt"
+
+	status, h, _ := do(t, "PUT", u, "hello world", "Content-Type", "text/plain", "X-Amz-Meta-Owner", "alice")
+	assert.Equal(t, status, http.StatusOK)
+	assert.Equal(t, h.Get("ETag"), `"5eb63bbbe01eeed093cb22bb8f5acdc3"`)
+
+	status, h, body := do(t, "GET", u, "")
+	assert.Equal(t, status, http.StatusOK)
+	assert.Equal(t, body, "hello world")
+	assert.Equal(t, h.Get("Content-Type"), "text/plain")
+	assert.Equal(t, h.Get("X-Amz-Meta-Owner"), "alice")
+
+	status, h, _ = do(t, "HEAD", u, "")
+	assert.Equal(t, status, http.StatusOK)
+	assert.Equal(t, h.Get("Content-Length"), "11")
+
+	status, h, body = do(t, "GET", u, "", "Range", "bytes=6-")
+	assert.Equal(t, status, http.StatusPartialContent)
+	assert.Equal(t, body, "world")
+	assert.Equal(t, h.Get("Content-Range"), "bytes 6-10/11")
+
+	status, _, body = do(t, "PUT", srv.URL+"/bucket/copy.txt", "", "X-Amz-Copy-Source", "/bucket/dir/hello.txt")
+	assert.Equal(t, status, http.StatusOK)
+	assert.Check(t, is.Contains(body, "<ETag>&#34;5eb63bbbe01eeed093cb22bb8f5acdc3&#34;</ETag>"))
+	_, _, body = do(t, "GET", srv.URL+"/bucket/copy.txt", "")
+	assert.Equal(t, body, "hello world")
+
+	status, _, _ = do(t, "DELETE", u, "")
+	assert.Equal(t, status, http.StatusNoContent)
+	status, _, body = do(t, "GET", u, "")
+	assert.Equal(t, status, http.StatusNotFound)
+	assert.Check(t, is.Contains(body, "<Code>NoSuchKey</Code>"))
+
+	status, _, body = do(t, "GET", srv.URL+"/missing/key", "")
+	assert.Equal(t, status, http.StatusNotFound)
+	assert.Check(t, is.Contains(body, "<Code>NoSuchBucket</Code>"))
+}
+
+func TestListObjects(t *testing.T) {
+	srv := newTestServer(t)
+	for _, key := range []string{"a.txt", "dir/b.txt", "dir/c.txt", "dir/sub/d.txt", "e.txt"} {
+		status, _, _ := do(t, "PUT", srv.URL+"/bucket/"+key, "data")
+		assert.Equal(t, status, http.StatusOK)
+	}
+
+	_, _, body := do(t, "GET", srv.URL+"/bucket?list-type=2&delimiter=/", "")
+	assert.Check(t, is.Contains(body, "<Key>a.txt</Key><LastModified>"))
+	assert.Check(t, is.Contains(body, "<CommonPrefixes><Prefix>dir/</Prefix></CommonPrefixes>"))
+	assert.Check(t, is.Contains(body, "<KeyCount>3</KeyCount>"))
+	assert.Check(t, !strings.Contains(body, "dir/b.txt"))
+
+	_, _, body = do(t, "GET", srv.URL+"/bucket?list-type=2&prefix=dir/&delimiter=/", "")
+	assert.Check(t, is.Contains(body, "<Key>dir/b.txt</Key>"))
+	assert.Check(t, is.Contains(body, "<Key>dir/c.txt</Key>"))
+	assert.Check(t, is.Contains(body, "<Prefix>dir/sub/</Prefix>"))
+
+	// Paginate through all keys.
+	var keys []string
+	token := ""
+	for {
+		_, _, body = do(t, "GET", srv.URL+"/bucket?list-type=2&max-keys=2&continuation-token="+token, "")
+		for _, part := range strings.Split(body, "<Key>")[1:] {
+			key, _, _ := strings.Cut(part, "</Key>")
+			keys = append(keys, key)
+		}
+		_, rest, ok := strings.Cut(body, "<NextContinuationToken>")
+		if !ok {
+			break
+		}
+		token, _, _ = strings.Cut(rest, "</NextContinuationToken>")
+	}
+	assert.DeepEqual(t, keys, []string{"a.txt", "dir/b.txt", "dir/c.txt", "dir/sub/d.txt", "e.txt"})
+
+	status, _, body := do(t, "POST", srv.URL+"/bucket?delete",
+		`<Delete><Object><Key>a.txt</Key></Object><Object><Key>e.txt</Key></Object></Delete>`)
+	assert.Equal(t, status, http.StatusOK)
+	assert.Check(t, is.Contains(body, "<Deleted><Key>e.txt</Key></Deleted>"))
+	_, _, body = do(t, "GET", srv.URL+"/bucket?list-type=2&delimiter=/", "")
+	assert.Check(t, is.Contains(body, "<KeyCount>1</KeyCount>"))
+}
+
+func TestMultipartUpload(t *testing.T) {
+	srv := newTestServer(t)
+	u := srv.URL + "/bucket/big.bin"
+
+	status, _, body := do(t, "POST", u+"?uploads", "", "Content-Type", "application/octet-stream")
+	assert.Equal(t, status, http.StatusOK)
+	_, rest, _ := strings.Cut(body, "<UploadId>")
+	id, _, _ := strings.Cut(rest, "</UploadId>")
+
+	_, h1, _ := do(t, "PUT", u+"?partNumber=1&uploadId="+id, "hello ")
+	_, h2, _ := do(t, "PUT", u+"?partNumber=2&uploadId="+id, "world")
+
+	status, _, body = do(t, "POST", u+"?uploadId="+id, "<CompleteMultipartUpload>"+
+		"<Part><PartNumber>2</PartNumber><ETag>"+h2.Get("ETag")+"</ETag></Part>"+
+		"<Part><PartNumber>1</PartNumber><ETag>"+h1.Get("ETag")+"</ETag></Part>"+
+		"</CompleteMultipartUpload>")
+	assert.Equal(t, status, http.StatusBadRequest)
+	assert.Check(t, is.Contains(body, "InvalidPartOrder"))
+
+	status, _, body = do(t, "POST", u+"?uploadId="+id, "<CompleteMultipartUpload>"+
+		"<Part><PartNumber>1</PartNumber><ETag>"+h1.Get("ETag")+"</ETag></Part>"+
+		"<Part><PartNumber>2</PartNumber><ETag>"+h2.Get("ETag")+"</ETag></Part>"+
+		"</CompleteMultipartUpload>")
+	assert.Equal(t, status, http.StatusOK, body)
+	assert.Check(t, is.Contains(body, "-2&#34;</ETag>"))
+
+	_, h, body := do(t, "GET", u, "")
+	assert.Equal(t, body, "hello world")
+	assert.Equal(t, h.Get("Content-Type"), "application/octet-stream")
+
+	// The upload is gone once completed.
+	status, _, _ = do(t, "DELETE", u+"?uploadId="+id, "")
+	assert.Equal(t, status, http.StatusNotFound)
+}
+
+func TestAWSChunkedUpload(t *testing.T) {
+	srv := newTestServer(t)
+	u := srv.URL + "/bucket/chunked.txt"
+
+	body := "6;chunk-signature=abc\r\nhello \r\n5;chunk-signature=def\r\nworld\r\n0;chunk-signature=ghi\r\n\r\n"
+	status, _, _ := do(t, "PUT", u, body,
+		"X-Amz-Content-Sha256", "STREAMING-AWS4-HMAC-SHA256-PAYLOAD",
+		"Content-Encoding", "aws-chunked")
+	assert.Equal(t, status, http.StatusOK)
+
+	status, h, got := do(t, "GET", u, "")
+	assert.Equal(t, status, http.StatusOK)
+	assert.Equal(t, got, "hello world")
+	assert.Equal(t, h.Get("Content-Encoding"), "")
+
+	status, _, _ = do(t, "PUT", u, "zz\r\nbad", "Content-Encoding", "aws-chunked")
+	assert.Equal(t, status, http.StatusBadRequest)
+}
+
+func TestCheckPresigned(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tcs := []struct {
+		name  string
+		query string
+		err   string
+	}{
+		{name: "unsigned", query: ""},
+		{name: "valid", query: "X-Amz-Date=20240101T115500Z&X-Amz-Expires=600"},
+		{name: "expired", query: "X-Amz-Date=20240101T110000Z&X-Amz-Expires=600", err: "request has expired"},
+		{name: "future", query: "X-Amz-Date=20240101T130000Z&X-Amz-Expires=600", err: "not valid yet"},
+		{name: "too_long", query: "X-Amz-Date=20240101T115500Z&X-Amz-Expires=604801", err: "invalid X-Amz-Expires"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			q := tc.query
+			if q != "" {
+				q += "&X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Credential=key/20240101/us-east-1/s3/aws4_request&X-Amz-Signature=abc"
+			}
+			req := httptest.NewRequest("GET", "/bucket/key?"+q, nil)
+			err := checkPresigned(req, now)
+			if tc.err == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -124,9 +124,109 @@ func slurpJSON(req *http.Request, respData any) error {
 	return nil
 }
 
+// Entry is a file or directory in a GitHub repository.
+type Entry struct {
+	Name string // base name of the entry
+	Dir  bool   // whether the entry is a directory
+}
+
+// ListTree lists the top-level files and directories of a (sub-)tree
+// in a GitHub repository, using GitHub's API.
+func ListTree(ctx context.Context, tree *Tree) ([]Entry, error) {
+	p := strings.Trim(path.Clean(tree.Path), "/")
+	if p == "." {
+		p = ""
+	}
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/contents/%s?ref=%s",
+		tree.Owner, tree.Repo, p, url.QueryEscape(tree.Branch))
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "create request")
+	}
+
+	var resp []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+	if err := slurpJSON(req, &resp); err != nil {
+		return nil, errors.Wrap(err, "list tree")
+	}
+
+	entries := make([]Entry, 0, len(resp))
+	for _, e := range resp {
+		entries = append(entries, Entry{Name: e.Name, Dir: e.Type == "dir"})
+	}
+	return entries, nil
+}
+
+// ListFiles lists the files in a (sub-)tree in a GitHub repository,
+// recursively, using GitHub's API. The paths are slash-separated
+// and relative to the tree's Path.
+func ListFiles(ctx context.Context, tree *Tree) ([]string, error) {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/trees/%s?recursive=1",
+		tree.Owner, tree.Repo, url.PathEscape(tree.Branch))
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "create request")
+	}
+
+	var resp struct {
+		Tree []struct {
+			Path string `json:"path"`
+			Type string `json:"type"`
+		} `json:"tree"`
+		Truncated bool `json:"truncated"`
+	}
+	if err := slurpJSON(req, &resp); err != nil {
+		return nil, errors.Wrap(err, "list files")
+	} else if resp.Truncated {
+		return nil, errors.New("list files: too many files in repository")
+	}
+
+	prefix := strings.Trim(path.Clean(tree.Path), "/") + "/"
+	if prefix == "./" {
+		prefix = ""
+	}
+	var files []string
+	for _, e := range resp.Tree {
+		if e.Type == "blob" && strings.HasPrefix(e.Path, prefix) {
+			files = append(files, strings.TrimPrefix(e.Path, prefix))
+		}
+	}
+	if len(files) == 0 {
+		return nil, ErrEmptyTree
+	}
+	return files, nil
+}
+
+// ReadFile reads the file with the given name in a (sub-)tree
+// in a GitHub repository. It reads at most maxSize bytes.
+func ReadFile(ctx context.Context, tree *Tree, name string, maxSize int64) ([]byte, error) {
+	u := fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s/%s",
+		tree.Owner, tree.Repo, tree.Branch, strings.TrimPrefix(path.Join(tree.Path, name), "/"))
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "create request")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "send request")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, errors.Newf("GET %s: got non-200 response: %s", u, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize))
+	if err != nil {
+		return nil, errors.Wrap(err, "read file")
+	}
+	return data, nil
+}
+
 var ErrEmptyTree = errors.New("empty tree")
 
 // ExtractTree downloads a (sub-)tree from a GitHub repository and writes it to dst.
+// If ctx is canceled it stops, leaving any files written so far, and returns ctx.Err().
 func ExtractTree(ctx context.Context, tree *Tree, dst string) error {
 	url := fmt.Sprintf("https://codeload.github.com/%s/%s/tar.gz/%s", tree.Owner, tree.Repo, tree.Branch)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -153,6 +253,10 @@ func ExtractTree(ctx context.Context, tree *Tree, dst string) error {
 	files := 0
 
 	for {
+		// Stop promptly if canceled, rather than when the next read fails.
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		hdr, err := tr.Next()
 		if err == io.EOF {
 			if files == 0 {
@@ -0,0 +1,173 @@
+package openapiscaffold
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"slices"
+	"strings"
+)
+
+// genGo generates the Go package of the service.
+func genGo(svc *service) (map[string][]byte, error) {
+	g := &goGen{imports: make(map[string]bool)}
+
+	var types bytes.Buffer
+	for _, d := range svc.decls {
+		g.writeDecl(&types, d)
+	}
+
+	var api bytes.Buffer
+	for _, ep := range svc.endpoints {
+		g.writeEndpoint(&api, ep)
+	}
+
+	files := make(map[string][]byte)
+	apiImports := []string{"context", "encore.dev/beta/errs"}
+	src, err := goFile(fmt.Sprintf("// Service %s was generated from an OpenAPI specification.\npackage %s\n", svc.name, svc.name), apiImports, api.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	files["api.go"] = src
+
+	if types.Len() > 0 {
+		var imports []string
+		for imp := range g.imports {
+			imports = append(imports, imp)
+		}
+		slices.Sort(imports)
+		src, err := goFile("package "+svc.name+"\n", imports, types.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		files["types.go"] = src
+	}
+	return files, nil
+}
+
+type goGen struct {
+	imports map[string]bool // the imports used by the types
+}
+
+// goFile formats a Go file with the given package clause, imports and body.
+func goFile(pkg string, imports []string, body []byte) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(pkg)
+	if len(imports) > 0 {
+		b.WriteString("\nimport (\n")
+		for i, imp := range imports {
+			// Separate the standard library imports from the others.
+			if i > 0 && !strings.Contains(imports[i-1], ".") && strings.Contains(imp, ".") {
+				b.WriteByte('\n')
+			}
+			fmt.Fprintf(&b, "\t%q\n", imp)
+		}
+		b.WriteString(")\n")
+	}
+	b.Write(body)
+	return format.Source(b.Bytes())
+}
+
+func (g *goGen) writeDecl(b *bytes.Buffer, d *decl) {
+	b.WriteByte('\n')
+	writeGoDoc(b, "", d.doc)
+	fmt.Fprintf(b, "type %s struct {\n", d.name)
+	names := make(map[string]bool)
+	for _, f := range d.fields {
+		if f.in == "path" {
+			// Path parameters are passed as arguments.
+			continue
+		}
+		name := ident(f.name)
+		for i := 2; names[name]; i++ {
+			name = fmt.Sprintf("%s%d", ident(f.name), i)
+		}
+		names[name] = true
+
+		writeGoDoc(b, "\t", f.doc)
+		var tags []string
+		switch f.in {
+		case "query", "header":
+			tags = append(tags, fmt.Sprintf("%s:%q", f.in, f.name))
+		default:
+			jsonName := f.name
+			if f.optional {
+				jsonName += ",omitempty"
+			}
+			tags = append(tags, fmt.Sprintf("json:%q", jsonName))
+		}
+		if f.optional {
+			tags = append(tags, `encore:"optional"`)
+		}
+		fmt.Fprintf(b, "\t%s %s `%s`\n", name, g.typ(f.typ, f.optional), strings.Join(tags, " "))
+	}
+	b.WriteString("}\n")
+}
+
+func (g *goGen) writeEndpoint(b *bytes.Buffer, ep *endpoint) {
+	b.WriteByte('\n')
+	doc := ep.doc
+	if doc == "" {
+		doc = fmt.Sprintf("%s handles %s %s.", ep.name, ep.method, ep.path)
+	}
+	if ep.wrappedResponse {
+		doc += "\n\nIts response is wrapped in the Data field, since Encore responses are objects."
+	}
+	writeGoDoc(b, "", doc)
+	fmt.Fprintf(b, "//\n//encore:api public method=%s path=%s\n", ep.method, ep.path)
+
+	params := []string{"ctx context.Context"}
+	for _, p := range ep.pathParams {
+		params = append(params, fmt.Sprintf("%s %s", p.name, g.typ(p.typ, false)))
+	}
+	if ep.request != nil {
+		params = append(params, "req *"+ep.request.name)
+	}
+
+	unimplemented := `errs.B().Code(errs.Unimplemented).Msg("not implemented").Err()`
+	if ep.response != nil {
+		fmt.Fprintf(b, "func %s(%s) (*%s, error) {\n\treturn nil, %s\n}\n", ep.name, strings.Join(params, ", "), ep.response.name, unimplemented)
+	} else {
+		fmt.Fprintf(b, "func %s(%s) error {\n\treturn %s\n}\n", ep.name, strings.Join(params, ", "), unimplemented)
+	}
+}
+
+// typ returns the Go type of t. Optional named types are pointers.
+func (g *goGen) typ(t *typ, optional bool) string {
+	switch t.kind {
+	case namedKind:
+		if optional {
+			return "*" + t.decl.name
+		}
+		return t.decl.name
+	case listKind:
+		return "[]" + g.typ(t.elem, false)
+	case mapKind:
+		return "map[string]" + g.typ(t.elem, false)
+	}
+	switch t.builtin {
+	case "time":
+		g.imports["time"] = true
+		return "time.Time"
+	case "bytes":
+		return "[]byte"
+	case "any":
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	default:
+		return t.builtin
+	}
+}
+
+func writeGoDoc(b *bytes.Buffer, indent, doc string) {
+	if doc == "" {
+		return
+	}
+	for _, ln := range strings.Split(doc, "\n") {
+		if ln = strings.TrimRight(ln, " \t"); ln == "" {
+			fmt.Fprintf(b, "%s//\n", indent)
+		} else {
+			fmt.Fprintf(b, "%s// %s\n", indent, ln)
+		}
+	}
+}
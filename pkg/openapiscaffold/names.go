@@ -0,0 +1,53 @@
+package openapiscaffold
+
+import (
+	"go/token"
+	"strings"
+	"unicode"
+
+	"encr.dev/pkg/idents"
+)
+
+// ident converts name, such as an operation ID or a schema name,
+// to an exported identifier.
+func ident(name string) string {
+	id := idents.Convert(name, idents.PascalCase)
+	if id == "" {
+		return "Unnamed"
+	} else if !unicode.IsLetter(rune(id[0])) {
+		return "X" + id
+	}
+	// Follow the Go convention for the common "ID" suffix, as in "UserID".
+	if base, ok := strings.CutSuffix(id, "Id"); ok {
+		id = base + "ID"
+	}
+	return id
+}
+
+// paramName converts the name of a path parameter
+// to an identifier usable as a function parameter.
+func paramName(name string) string {
+	id := idents.Convert(name, idents.CamelCase)
+	if id == "" || !unicode.IsLetter(rune(id[0])) {
+		id = "p" + ident(id)
+	}
+	if token.IsKeyword(id) || id == "ctx" {
+		id += "Param"
+	}
+	return id
+}
+
+// serviceName converts a tag to a service name,
+// which is also used as the name of its Go package.
+func serviceName(tag string) string {
+	name := strings.ReplaceAll(idents.Convert(tag, idents.SnakeCase), "_", "")
+	if name == "" {
+		return "api"
+	} else if !unicode.IsLetter(rune(name[0])) {
+		name = "svc" + name
+	}
+	if token.IsKeyword(name) {
+		name += "svc"
+	}
+	return name
+}
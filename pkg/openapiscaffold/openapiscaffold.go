@@ -0,0 +1,492 @@
+// Package openapiscaffold generates the skeleton of an Encore app from an
+// OpenAPI 3 specification, for migrating an existing REST API to Encore.
+//
+// Each tag of the specification becomes a service, with an endpoint for each
+// of its operations. The endpoints have typed request and response structs
+// generated from the operation's parameters, request body and response, but
+// are left unimplemented.
+package openapiscaffold
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Lang is the language to generate the app skeleton in.
+type Lang string
+
+const (
+	Go         Lang = "go"
+	TypeScript Lang = "ts"
+)
+
+// Load loads the OpenAPI 3 specification at location,
+// which is either a file path or an http(s) URL.
+func Load(ctx context.Context, location string) (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	loader.Context = ctx
+	loader.IsExternalRefsAllowed = true
+
+	var (
+		doc *openapi3.T
+		err error
+	)
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		var u *url.URL
+		if u, err = url.Parse(location); err == nil {
+			doc, err = loader.LoadFromURI(u)
+		}
+	} else {
+		doc, err = loader.LoadFromFile(location)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "load OpenAPI specification %s", location)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, errors.Newf("%s is not an OpenAPI 3 specification", location)
+	}
+	return doc, nil
+}
+
+// Result is the generated app skeleton.
+type Result struct {
+	// Files are the contents of the generated files,
+	// keyed by their slash-separated path relative to the app root.
+	Files map[string][]byte
+
+	// Skipped describes the operations that couldn't be generated, if any.
+	Skipped []string
+}
+
+// Generate generates the app skeleton for the given specification.
+func Generate(doc *openapi3.T, lang Lang) (*Result, error) {
+	services, skipped, err := parse(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{Files: make(map[string][]byte), Skipped: skipped}
+	for _, svc := range services {
+		var files map[string][]byte
+		switch lang {
+		case Go:
+			files, err = genGo(svc)
+		case TypeScript:
+			files, err = genTS(svc)
+		default:
+			return nil, errors.Newf("unsupported language %q", lang)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "generate service %s", svc.name)
+		}
+		for name, data := range files {
+			res.Files[path.Join(svc.name, name)] = data
+		}
+	}
+	return res, nil
+}
+
+// service is a service to generate, from the operations with the same tag.
+type service struct {
+	name      string
+	endpoints []*endpoint
+	decls     []*decl // the named types used by the endpoints, in order
+
+	names    map[string]bool  // the identifiers in use
+	declRefs map[string]*decl // the declarations of referenced schemas, by ref
+	schemas  map[*openapi3.Schema]*decl
+}
+
+type endpoint struct {
+	name       string // PascalCase
+	doc        string
+	method     string
+	path       string // the Encore path, like /users/:id
+	pathParams []field
+	// request is the request type, which is nil if there are no parameters
+	// besides the path parameters. If it's specific to the endpoint,
+	// it includes the path parameters, as fields in "path".
+	request  *decl
+	response *decl // nil if there's no response body
+
+	// wrappedResponse is set if the response isn't an object,
+	// and so is wrapped in response's Data field.
+	wrappedResponse bool
+}
+
+// decl is a named object type.
+type decl struct {
+	name   string
+	doc    string
+	fields []field
+}
+
+type field struct {
+	name     string // the name in the request or response
+	doc      string
+	typ      *typ
+	optional bool
+	in       string // "path", "query" or "header" for parameters, "" otherwise
+}
+
+type typKind int
+
+const (
+	builtinKind typKind = iota
+	namedKind
+	listKind
+	mapKind
+)
+
+type typ struct {
+	kind    typKind
+	builtin string   // for builtinKind: string, bool, int, int32, int64, float32, float64, time, bytes or any
+	enum    []string // string literals the builtin string is one of, if any
+	decl    *decl    // for namedKind
+	elem    *typ     // for listKind and mapKind
+}
+
+// parse groups the operations of the specification into services.
+func parse(doc *openapi3.T) (services []*service, skipped []string, err error) {
+	byName := make(map[string]*service)
+
+	paths := make([]string, 0, len(doc.Paths))
+	for p := range doc.Paths {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+
+	for _, p := range paths {
+		item := doc.Paths[p]
+		for _, method := range methods {
+			op := item.GetOperation(method)
+			if op == nil {
+				continue
+			}
+			encorePath, err := convertPath(p)
+			if err != nil {
+				skipped = append(skipped, fmt.Sprintf("%s %s: %v", method, p, err))
+				continue
+			}
+
+			name := "api"
+			if len(op.Tags) > 0 {
+				name = serviceName(op.Tags[0])
+			}
+			svc := byName[name]
+			if svc == nil {
+				svc = &service{
+					name:     name,
+					names:    make(map[string]bool),
+					declRefs: make(map[string]*decl),
+					schemas:  make(map[*openapi3.Schema]*decl),
+				}
+				byName[name] = svc
+				services = append(services, svc)
+			}
+
+			params := slices.Concat(item.Parameters, op.Parameters)
+			if err := svc.addEndpoint(method, p, encorePath, op, params); err != nil {
+				skipped = append(skipped, fmt.Sprintf("%s %s: %v", method, p, err))
+			}
+		}
+	}
+
+	services = slices.DeleteFunc(services, func(svc *service) bool { return len(svc.endpoints) == 0 })
+	slices.SortFunc(services, func(a, b *service) int { return strings.Compare(a.name, b.name) })
+	if len(services) == 0 {
+		return nil, skipped, errors.New("the OpenAPI specification has no operations")
+	}
+	return services, skipped, nil
+}
+
+// pathParamTypes are the builtin types supported for path parameters.
+var pathParamTypes = []string{"string", "bool", "int", "int32", "int64", "float32", "float64"}
+
+var methods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS", "TRACE"}
+
+var pathParamRe = regexp.MustCompile(`^\{([^{}]+)\}$`)
+
+// convertPath converts an OpenAPI path, like /users/{id},
+// to an Encore path, like /users/:id.
+func convertPath(p string) (string, error) {
+	segs := strings.Split(strings.TrimPrefix(p, "/"), "/")
+	for i, seg := range segs {
+		if m := pathParamRe.FindStringSubmatch(seg); m != nil {
+			segs[i] = ":" + paramName(m[1])
+		} else if strings.ContainsAny(seg, "{}:*") {
+			return "", errors.Newf("path segment %q is not supported by Encore", seg)
+		}
+	}
+	return "/" + strings.Join(segs, "/"), nil
+}
+
+func (svc *service) addEndpoint(method, rawPath, encorePath string, op *openapi3.Operation, params openapi3.Parameters) error {
+	ep := &endpoint{
+		method: method,
+		path:   encorePath,
+		doc:    strings.TrimSpace(op.Summary),
+	}
+	if ep.doc == "" {
+		ep.doc = strings.TrimSpace(op.Description)
+	}
+
+	name := op.OperationID
+	if name == "" {
+		name = strings.ToLower(method) + " " + rawPath
+	}
+	ep.name = svc.ident(name)
+
+	var reqFields []field
+	for _, ref := range params {
+		p := ref.Value
+		if p == nil {
+			continue
+		}
+		f := field{name: p.Name, doc: p.Description, typ: svc.schemaType(p.Schema, ep.name+ident(p.Name)), optional: !p.Required}
+		switch p.In {
+		case openapi3.ParameterInPath:
+			f.name = paramName(p.Name)
+			f.optional = false
+			f.in = p.In
+			if f.typ.kind != builtinKind || !slices.Contains(pathParamTypes, f.typ.builtin) {
+				return errors.Newf("path parameter %s must be a string, number or boolean", p.Name)
+			}
+			ep.pathParams = append(ep.pathParams, f)
+		case openapi3.ParameterInQuery, openapi3.ParameterInHeader:
+			f.in = p.In
+			reqFields = append(reqFields, f)
+		}
+	}
+
+	// Declare any path parameters missing from the parameters as strings.
+	for _, seg := range strings.Split(encorePath, "/") {
+		if name, ok := strings.CutPrefix(seg, ":"); ok && !slices.ContainsFunc(ep.pathParams, func(f field) bool { return f.name == name }) {
+			ep.pathParams = append(ep.pathParams, field{name: name, typ: &typ{kind: builtinKind, builtin: "string"}, in: openapi3.ParameterInPath})
+		}
+	}
+	// Order the path parameters as they appear in the path, like Encore does.
+	slices.SortStableFunc(ep.pathParams, func(a, b field) int {
+		return strings.Index(encorePath, ":"+a.name) - strings.Index(encorePath, ":"+b.name)
+	})
+
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		if mt := jsonContent(op.RequestBody.Value.Content); mt != nil && mt.Schema != nil {
+			body := svc.schemaType(mt.Schema, ep.name+"Request")
+			switch {
+			case body.kind != namedKind:
+				return errors.New("the request body must be an object")
+			case mt.Schema.Ref == "":
+				// The body is specific to the endpoint, so it's the request.
+				ep.request = body.decl
+				ep.request.doc = fmt.Sprintf("%s is the request of %s.", body.decl.name, ep.name)
+				ep.request.fields = slices.Concat(ep.pathParams, reqFields, body.decl.fields)
+			case len(reqFields) == 0 && len(ep.pathParams) == 0:
+				ep.request = body.decl
+			default:
+				reqFields = append(reqFields, body.decl.fields...)
+			}
+		}
+	}
+	if ep.request == nil && len(reqFields) > 0 {
+		name := svc.ident(ep.name + "Request")
+		ep.request = svc.addDecl(&decl{
+			name:   name,
+			doc:    fmt.Sprintf("%s is the request of %s.", name, ep.name),
+			fields: slices.Concat(ep.pathParams, reqFields),
+		})
+	}
+
+	if resp := successResponse(op.Responses); resp != nil {
+		if mt := jsonContent(resp.Content); mt != nil && mt.Schema != nil {
+			t := svc.schemaType(mt.Schema, ep.name+"Response")
+			if t.kind == namedKind {
+				ep.response = t.decl
+			} else {
+				// Encore responses are objects, so wrap anything else.
+				name := svc.ident(ep.name + "Response")
+				ep.response = svc.addDecl(&decl{
+					name:   name,
+					doc:    fmt.Sprintf("%s is the response of %s.", name, ep.name),
+					fields: []field{{name: "data", typ: t}},
+				})
+				ep.wrappedResponse = true
+			}
+		}
+	}
+
+	svc.endpoints = append(svc.endpoints, ep)
+	return nil
+}
+
+// successResponse returns the response of the first 2xx status, if any.
+func successResponse(responses openapi3.Responses) *openapi3.Response {
+	codes := make([]string, 0, len(responses))
+	for code := range responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	slices.Sort(codes)
+	for _, code := range codes {
+		if r := responses[code]; r != nil && r.Value != nil {
+			return r.Value
+		}
+	}
+	return nil
+}
+
+// jsonContent returns the JSON media type of the content, if any.
+func jsonContent(content openapi3.Content) *openapi3.MediaType {
+	if mt := content.Get("application/json"); mt != nil {
+		return mt
+	}
+	for typ, mt := range content {
+		if strings.HasSuffix(typ, "+json") {
+			return mt
+		}
+	}
+	return nil
+}
+
+// schemaType returns the type of the schema. Objects are declared as named types,
+// named after the schema they're referenced by, or otherwise name.
+func (svc *service) schemaType(ref *openapi3.SchemaRef, name string) *typ {
+	if ref == nil || ref.Value == nil {
+		return &typ{kind: builtinKind, builtin: "any"}
+	}
+	s := ref.Value
+	if ref.Ref != "" {
+		name = path.Base(ref.Ref)
+	}
+
+	switch {
+	case len(s.AllOf) > 0:
+		return svc.objectType(ref, name)
+	case len(s.OneOf) > 0 || len(s.AnyOf) > 0:
+		return &typ{kind: builtinKind, builtin: "any"}
+	}
+
+	switch s.Type {
+	case "string":
+		t := &typ{kind: builtinKind, builtin: "string"}
+		switch s.Format {
+		case "date-time":
+			t.builtin = "time"
+		case "byte", "binary":
+			t.builtin = "bytes"
+		}
+		for _, v := range s.Enum {
+			if str, ok := v.(string); ok {
+				t.enum = append(t.enum, str)
+			}
+		}
+		return t
+	case "integer":
+		switch s.Format {
+		case "int32":
+			return &typ{kind: builtinKind, builtin: "int32"}
+		case "int64":
+			return &typ{kind: builtinKind, builtin: "int64"}
+		}
+		return &typ{kind: builtinKind, builtin: "int"}
+	case "number":
+		if s.Format == "float" {
+			return &typ{kind: builtinKind, builtin: "float32"}
+		}
+		return &typ{kind: builtinKind, builtin: "float64"}
+	case "boolean":
+		return &typ{kind: builtinKind, builtin: "bool"}
+	case "array":
+		return &typ{kind: listKind, elem: svc.schemaType(s.Items, name+"Item")}
+	case "object", "":
+		if len(s.Properties) == 0 {
+			if ap := s.AdditionalProperties.Schema; ap != nil {
+				return &typ{kind: mapKind, elem: svc.schemaType(ap, name+"Value")}
+			} else if s.Type == "object" && (s.AdditionalProperties.Has == nil || *s.AdditionalProperties.Has) {
+				return &typ{kind: mapKind, elem: &typ{kind: builtinKind, builtin: "any"}}
+			} else if s.Type == "" {
+				return &typ{kind: builtinKind, builtin: "any"}
+			}
+		}
+		return svc.objectType(ref, name)
+	}
+	return &typ{kind: builtinKind, builtin: "any"}
+}
+
+// objectType declares the object schema as a named type, once per schema.
+func (svc *service) objectType(ref *openapi3.SchemaRef, name string) *typ {
+	if ref.Ref != "" {
+		if d, ok := svc.declRefs[ref.Ref]; ok {
+			return &typ{kind: namedKind, decl: d}
+		}
+	} else if d, ok := svc.schemas[ref.Value]; ok {
+		return &typ{kind: namedKind, decl: d}
+	}
+
+	// Declare the type before its fields, which may refer to it.
+	s := ref.Value
+	d := &decl{name: svc.ident(name), doc: strings.TrimSpace(s.Description)}
+	if ref.Ref != "" {
+		svc.declRefs[ref.Ref] = d
+	} else {
+		svc.schemas[s] = d
+	}
+	svc.addDecl(d)
+
+	svc.addFields(d, s)
+	return &typ{kind: namedKind, decl: d}
+}
+
+// addFields adds the properties of the object schema s to d,
+// including those of the schemas it's composed of with allOf.
+func (svc *service) addFields(d *decl, s *openapi3.Schema) {
+	for _, part := range s.AllOf {
+		if part.Ref == "" && part.Value != nil {
+			svc.addFields(d, part.Value)
+		} else if t := svc.schemaType(part, d.name); t.kind == namedKind && t.decl != d {
+			d.fields = append(d.fields, t.decl.fields...)
+		}
+	}
+	props := make([]string, 0, len(s.Properties))
+	for p := range s.Properties {
+		props = append(props, p)
+	}
+	slices.Sort(props)
+	for _, p := range props {
+		prop := s.Properties[p]
+		f := field{
+			name:     p,
+			typ:      svc.schemaType(prop, d.name+ident(p)),
+			optional: !slices.Contains(s.Required, p),
+		}
+		if prop.Value != nil {
+			f.doc = strings.TrimSpace(prop.Value.Description)
+		}
+		d.fields = append(d.fields, f)
+	}
+}
+
+func (svc *service) addDecl(d *decl) *decl {
+	svc.decls = append(svc.decls, d)
+	return d
+}
+
+// ident returns a unique exported identifier in the service for name.
+func (svc *service) ident(name string) string {
+	id := ident(name)
+	unique := id
+	for i := 2; svc.names[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", id, i)
+	}
+	svc.names[unique] = true
+	return unique
+}
@@ -0,0 +1,103 @@
+package openapiscaffold
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"slices"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestGenerate_Go(t *testing.T) {
+	c := qt.New(t)
+	doc, err := Load(context.Background(), "testdata/petstore.yaml")
+	c.Assert(err, qt.IsNil)
+
+	res, err := Generate(doc, Go)
+	c.Assert(err, qt.IsNil)
+	c.Assert(res.Skipped, qt.HasLen, 0)
+	c.Assert(keys(res.Files), qt.DeepEquals, []string{"api/api.go", "pets/api.go", "pets/types.go"})
+	for name, data := range res.Files {
+		_, err := parser.ParseFile(token.NewFileSet(), name, data, parser.ParseComments)
+		c.Assert(err, qt.IsNil, qt.Commentf("%s", data))
+	}
+
+	api := string(res.Files["pets/api.go"])
+	c.Assert(api, qt.Contains, "//encore:api public method=GET path=/pets/:petId\nfunc GetPet(ctx context.Context, petId string) (*Pet, error) {")
+	c.Assert(api, qt.Contains, "func CreatePet(ctx context.Context, req *NewPet) (*Pet, error) {")
+	c.Assert(api, qt.Contains, "func DeletePet(ctx context.Context, petId string) error {")
+	c.Assert(api, qt.Contains, "func ListPets(ctx context.Context, req *ListPetsRequest) (*ListPetsResponse, error) {")
+
+	types := string(res.Files["pets/types.go"])
+	c.Assert(types, qt.Contains, "Limit      int32  `query:\"limit\" encore:\"optional\"`")
+	c.Assert(types, qt.Contains, "XRequestID string `header:\"X-Request-ID\" encore:\"optional\"`")
+	c.Assert(types, qt.Contains, "ID     string            `json:\"id\"`")
+	c.Assert(types, qt.Contains, "Data []Pet `json:\"data\"`")
+	c.Assert(types, qt.Not(qt.Contains), "Pet2")
+}
+
+func TestGenerate_TypeScript(t *testing.T) {
+	c := qt.New(t)
+	doc, err := Load(context.Background(), "testdata/petstore.yaml")
+	c.Assert(err, qt.IsNil)
+
+	res, err := Generate(doc, TypeScript)
+	c.Assert(err, qt.IsNil)
+	c.Assert(keys(res.Files), qt.DeepEquals, []string{
+		"api/api.ts", "api/encore.service.ts", "pets/api.ts", "pets/encore.service.ts",
+	})
+	c.Assert(string(res.Files["pets/encore.service.ts"]), qt.Contains, `export default new Service("pets");`)
+
+	api := string(res.Files["pets/api.ts"])
+	c.Assert(api, qt.Contains, `import { api, APIError, Header, Query } from "encore.dev/api";`)
+	c.Assert(api, qt.Contains, "  limit?: Query<number>;\n  xRequestID?: Header<\"X-Request-ID\">;\n")
+	c.Assert(api, qt.Contains, `  status?: "available" | "sold";`)
+	c.Assert(api, qt.Contains, `  async (req: { petId: string }): Promise<Pet> => {`)
+	c.Assert(api, qt.Contains, `  { expose: true, method: "DELETE", path: "/pets/:petId" },`)
+	c.Assert(string(res.Files["api/api.ts"]), qt.Contains, `import { api, APIError } from "encore.dev/api";`)
+}
+
+func TestConvertPath(t *testing.T) {
+	c := qt.New(t)
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"/pets", "/pets", false},
+		{"/pets/{petId}", "/pets/:petId", false},
+		{"/users/{id}/pets/{petId}", "/users/:id/pets/:petId", false},
+		{"/files/{name}.json", "", true},
+	}
+	for _, test := range tests {
+		got, err := convertPath(test.path)
+		if test.wantErr {
+			c.Check(err, qt.IsNotNil, qt.Commentf("path %s", test.path))
+		} else {
+			c.Check(err, qt.IsNil)
+			c.Check(got, qt.Equals, test.want)
+		}
+	}
+}
+
+func TestNames(t *testing.T) {
+	c := qt.New(t)
+	c.Check(ident("list_pets"), qt.Equals, "ListPets")
+	c.Check(ident("userId"), qt.Equals, "UserID")
+	c.Check(ident("2fa"), qt.Equals, "X2fa")
+	c.Check(paramName("type"), qt.Equals, "typeParam")
+	c.Check(paramName("pet-id"), qt.Equals, "petId")
+	c.Check(serviceName("Pet Store"), qt.Equals, "petstore")
+	c.Check(serviceName(""), qt.Equals, "api")
+}
+
+func keys(files map[string][]byte) []string {
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
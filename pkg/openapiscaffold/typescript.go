@@ -0,0 +1,172 @@
+package openapiscaffold
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"encr.dev/pkg/idents"
+)
+
+// genTS generates the TypeScript service.
+func genTS(svc *service) (map[string][]byte, error) {
+	g := &tsGen{imports: map[string]bool{"api": true, "APIError": true}}
+
+	var body bytes.Buffer
+	for _, d := range svc.decls {
+		g.writeDecl(&body, d)
+	}
+	for _, ep := range svc.endpoints {
+		g.writeEndpoint(&body, ep)
+	}
+
+	var api bytes.Buffer
+	imports := make([]string, 0, len(g.imports))
+	for imp := range g.imports {
+		imports = append(imports, imp)
+	}
+	slices.SortFunc(imports, func(a, b string) int { return strings.Compare(strings.ToLower(a), strings.ToLower(b)) })
+	fmt.Fprintf(&api, "import { %s } from \"encore.dev/api\";\n", strings.Join(imports, ", "))
+	api.Write(body.Bytes())
+
+	service := fmt.Sprintf(`import { Service } from "encore.dev/service";
+
+// The %s service was generated from an OpenAPI specification.
+export default new Service(%q);
+`, svc.name, svc.name)
+
+	return map[string][]byte{
+		"encore.service.ts": []byte(service),
+		"api.ts":            api.Bytes(),
+	}, nil
+}
+
+type tsGen struct {
+	imports map[string]bool // the names imported from encore.dev/api
+}
+
+func (g *tsGen) writeDecl(b *bytes.Buffer, d *decl) {
+	b.WriteByte('\n')
+	writeTSDoc(b, "", d.doc)
+	fmt.Fprintf(b, "export interface %s {\n", d.name)
+	g.writeFields(b, d.fields)
+	b.WriteString("}\n")
+}
+
+func (g *tsGen) writeFields(b *bytes.Buffer, fields []field) {
+	for _, f := range fields {
+		writeTSDoc(b, "  ", f.doc)
+		name, typ := f.name, g.typ(f.typ)
+		switch f.in {
+		case "query":
+			g.imports["Query"] = true
+			typ = fmt.Sprintf("Query<%s>", typ)
+		case "header":
+			g.imports["Header"] = true
+			name = idents.Convert(f.name, idents.CamelCase)
+			typ = fmt.Sprintf("Header<%q>", f.name)
+		}
+		if !tsIdentRe.MatchString(name) {
+			name = strconv.Quote(name)
+		}
+		if f.optional {
+			name += "?"
+		}
+		fmt.Fprintf(b, "  %s: %s;\n", name, typ)
+	}
+}
+
+func (g *tsGen) writeEndpoint(b *bytes.Buffer, ep *endpoint) {
+	b.WriteByte('\n')
+	doc := ep.doc
+	if doc == "" {
+		doc = fmt.Sprintf("%s handles %s %s.", ep.name, ep.method, ep.path)
+	}
+	if ep.wrappedResponse {
+		doc += "\n\nIts response is wrapped in the data field, since Encore responses are objects."
+	}
+	writeTSDoc(b, "", doc)
+
+	var param string
+	switch {
+	case ep.request != nil:
+		param = "req: " + ep.request.name
+	case len(ep.pathParams) > 0:
+		fields := make([]string, len(ep.pathParams))
+		for i, p := range ep.pathParams {
+			fields[i] = fmt.Sprintf("%s: %s", p.name, g.typ(p.typ))
+		}
+		param = fmt.Sprintf("req: { %s }", strings.Join(fields, "; "))
+	}
+	resp := "void"
+	if ep.response != nil {
+		resp = ep.response.name
+	}
+
+	fmt.Fprintf(b, "export const %s = api(\n", idents.Convert(ep.name, idents.CamelCase))
+	fmt.Fprintf(b, "  { expose: true, method: %q, path: %q },\n", ep.method, ep.path)
+	fmt.Fprintf(b, "  async (%s): Promise<%s> => {\n", param, resp)
+	b.WriteString("    throw APIError.unimplemented(\"not implemented\");\n")
+	b.WriteString("  },\n);\n")
+}
+
+var tsIdentRe = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// typ returns the TypeScript type of t.
+func (g *tsGen) typ(t *typ) string {
+	switch t.kind {
+	case namedKind:
+		return t.decl.name
+	case listKind:
+		elem := g.typ(t.elem)
+		if strings.Contains(elem, " ") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case mapKind:
+		return fmt.Sprintf("Record<string, %s>", g.typ(t.elem))
+	}
+	switch t.builtin {
+	case "string":
+		if len(t.enum) > 0 {
+			lits := make([]string, len(t.enum))
+			for i, e := range t.enum {
+				lits[i] = strconv.Quote(e)
+			}
+			return strings.Join(lits, " | ")
+		}
+		return "string"
+	case "time", "bytes":
+		// Both are represented as strings in JSON.
+		return "string"
+	case "bool":
+		return "boolean"
+	case "any":
+		return "any"
+	default:
+		return "number"
+	}
+}
+
+func writeTSDoc(b *bytes.Buffer, indent, doc string) {
+	if doc == "" {
+		return
+	}
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s/** %s */\n", indent, doc)
+		return
+	}
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, ln := range lines {
+		if ln = strings.TrimRight(ln, " \t"); ln == "" {
+			fmt.Fprintf(b, "%s *\n", indent)
+		} else {
+			fmt.Fprintf(b, "%s * %s\n", indent, ln)
+		}
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
@@ -400,8 +400,9 @@ func (c *legacyConverter) Convert() (*config.Runtime, error) {
 							switch pc := sub.ProviderConfig.(type) {
 							case *runtimev1.PubSubSubscription_GcpConfig:
 								return &config.PubsubSubscriptionGCPData{
-									ProjectID:          pc.GcpConfig.ProjectId,
-									PushServiceAccount: pc.GcpConfig.GetPushServiceAccount(),
+									ProjectID:           pc.GcpConfig.ProjectId,
+									PushServiceAccount:  pc.GcpConfig.GetPushServiceAccount(),
+									ExactlyOnceDelivery: pc.GcpConfig.ExactlyOnceDelivery,
 								}
 							}
 							return nil
@@ -509,16 +509,27 @@ func (tp *traceParser) rpcCallEnd() *tracepb2.RPCCallEnd {
 }
 
 func (tp *traceParser) dbQueryStart() *tracepb2.DBQueryStart {
-	return &tracepb2.DBQueryStart{
+	ev := &tracepb2.DBQueryStart{
 		Query: tp.String(),
 		Stack: tp.stack(),
 	}
+	if tp.version >= 21 {
+		ev.Replica = tp.OptString()
+	}
+	if tp.version >= 23 {
+		ev.Name = tp.OptString()
+	}
+	return ev
 }
 
 func (tp *traceParser) dbQueryEnd() *tracepb2.DBQueryEnd {
-	return &tracepb2.DBQueryEnd{
+	ev := &tracepb2.DBQueryEnd{
 		Err: tp.errWithStack(),
 	}
+	if tp.version >= 22 {
+		ev.Rows = tp.OptUVarint()
+	}
+	return ev
 }
 
 func (tp *traceParser) dbTransactionStart() *tracepb2.DBTransactionStart {
@@ -596,7 +607,7 @@ func (tp *traceParser) httpCallEnd() *tracepb2.HTTPCallEnd {
 }
 
 func (tp *traceParser) cacheCallStart() *tracepb2.CacheCallStart {
-	return &tracepb2.CacheCallStart{
+	ev := &tracepb2.CacheCallStart{
 		Operation: tp.String(),
 		Write:     tp.Bool(),
 		Stack:     tp.stack(),
@@ -609,6 +620,10 @@ func (tp *traceParser) cacheCallStart() *tracepb2.CacheCallStart {
 			return keys
 		})(),
 	}
+	if tp.version >= 20 {
+		ev.BatchSize = tp.OptUVarint()
+	}
+	return ev
 }
 
 func (tp *traceParser) cacheCallEnd() *tracepb2.CacheCallEnd {
@@ -633,12 +648,16 @@ func (tp *traceParser) cacheCallEnd() *tracepb2.CacheCallEnd {
 }
 
 func (tp *traceParser) bucketObjectUploadStart() *tracepb2.BucketObjectUploadStart {
-	return &tracepb2.BucketObjectUploadStart{
+	ev := &tracepb2.BucketObjectUploadStart{
 		Bucket: tp.String(),
 		Object: tp.String(),
 		Attrs:  tp.bucketObjectAttrs(),
 		Stack:  tp.stack(),
 	}
+	if tp.version >= 18 {
+		ev.KmsKey = tp.OptString()
+	}
+	return ev
 }
 
 func (tp *traceParser) bucketObjectAttrs() *tracepb2.BucketObjectAttributes {
@@ -651,11 +670,15 @@ func (tp *traceParser) bucketObjectAttrs() *tracepb2.BucketObjectAttributes {
 }
 
 func (tp *traceParser) bucketObjectUploadEnd() *tracepb2.BucketObjectUploadEnd {
-	return &tracepb2.BucketObjectUploadEnd{
+	ev := &tracepb2.BucketObjectUploadEnd{
 		Size:    tp.OptUVarint(),
 		Version: tp.OptString(),
 		Err:     tp.errWithStack(),
 	}
+	if tp.version >= 19 {
+		ev.Checksum = tp.OptString()
+	}
+	return ev
 }
 
 func (tp *traceParser) bucketObjectDownloadStart() *tracepb2.BucketObjectDownloadStart {
@@ -668,10 +691,14 @@ func (tp *traceParser) bucketObjectDownloadStart() *tracepb2.BucketObjectDownloa
 }
 
 func (tp *traceParser) bucketObjectDownloadEnd() *tracepb2.BucketObjectDownloadEnd {
-	return &tracepb2.BucketObjectDownloadEnd{
+	ev := &tracepb2.BucketObjectDownloadEnd{
 		Size: tp.OptUVarint(),
 		Err:  tp.errWithStack(),
 	}
+	if tp.version >= 19 {
+		ev.Checksum = tp.OptString()
+	}
+	return ev
 }
 
 func (tp *traceParser) bucketDeleteObjectsStart() *tracepb2.BucketDeleteObjectsStart {
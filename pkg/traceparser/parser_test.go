@@ -377,6 +377,33 @@ func TestParse(t *testing.T) {
 			},
 		},
 
+		{
+			Name: "DBQueryStart_ReplicaAndName",
+			Emit: func(l *trace2.Log) {
+				l.DBQueryStart(trace2.DBQueryStartParams{
+					EventParams: ep,
+					Query:       "query",
+					Replica:     ptr("replica-1"),
+					Name:        ptr("list-todos"),
+				})
+			},
+			Want: &tracepb2.TraceEvent{
+				TraceId: pbTraceID,
+				SpanId:  pbSpanID,
+				Event: &tracepb2.TraceEvent_SpanEvent{SpanEvent: &tracepb2.SpanEvent{
+					Goid:   goid,
+					DefLoc: &udefLoc,
+					Data: &tracepb2.SpanEvent_DbQueryStart{
+						DbQueryStart: &tracepb2.DBQueryStart{
+							Query:   "query",
+							Replica: ptr("replica-1"),
+							Name:    ptr("list-todos"),
+						},
+					},
+				}},
+			},
+		},
+
 		{
 			Name: "DBQueryEnd",
 			Emit: func(l *trace2.Log) {
@@ -398,6 +425,27 @@ func TestParse(t *testing.T) {
 			},
 		},
 
+		{
+			Name: "DBQueryEndWithRows",
+			Emit: func(l *trace2.Log) {
+				l.DBQueryEndWithRows(ep, 1, 42, nil)
+			},
+			Want: &tracepb2.TraceEvent{
+				TraceId: pbTraceID,
+				SpanId:  pbSpanID,
+				Event: &tracepb2.TraceEvent_SpanEvent{SpanEvent: &tracepb2.SpanEvent{
+					Goid:               goid,
+					DefLoc:             &udefLoc,
+					CorrelationEventId: ptr[uint64](1),
+					Data: &tracepb2.SpanEvent_DbQueryEnd{
+						DbQueryEnd: &tracepb2.DBQueryEnd{
+							Rows: ptr[uint64](42),
+						},
+					},
+				}},
+			},
+		},
+
 		{
 			Name: "DBTransactionStart",
 			Emit: func(l *trace2.Log) {
@@ -574,6 +622,36 @@ func TestParse(t *testing.T) {
 			},
 		},
 
+		{
+			Name: "CacheCallStart_BatchSize",
+			Emit: func(l *trace2.Log) {
+				l.CacheCallStart(trace2.CacheCallStartParams{
+					EventParams: ep,
+					Operation:   "multi set",
+					IsWrite:     true,
+					Keys:        []string{"one", "two"},
+					Stack:       stack.Stack{},
+					BatchSize:   ptr[uint64](2),
+				})
+			},
+			Want: &tracepb2.TraceEvent{
+				TraceId: pbTraceID,
+				SpanId:  pbSpanID,
+				Event: &tracepb2.TraceEvent_SpanEvent{SpanEvent: &tracepb2.SpanEvent{
+					Goid:   goid,
+					DefLoc: &udefLoc,
+					Data: &tracepb2.SpanEvent_CacheCallStart{
+						CacheCallStart: &tracepb2.CacheCallStart{
+							Operation: "multi set",
+							Write:     true,
+							Keys:      []string{"one", "two"},
+							BatchSize: ptr[uint64](2),
+						},
+					},
+				}},
+			},
+		},
+
 		{
 			Name: "CacheCallEnd",
 			Emit: func(l *trace2.Log) {
@@ -671,6 +749,92 @@ func TestParse(t *testing.T) {
 				}},
 			},
 		},
+
+		{
+			Name: "BucketObjectUploadStart",
+			Emit: func(l *trace2.Log) {
+				l.BucketObjectUploadStart(trace2.BucketObjectUploadStartParams{
+					EventParams: ep,
+					Bucket:      "bucket",
+					Object:      "object",
+					Attrs:       trace2.BucketObjectAttributes{ContentType: ptr("text/plain")},
+					Stack:       stack.Stack{},
+					KMSKey:      ptr("pii"),
+				})
+			},
+			Want: &tracepb2.TraceEvent{
+				TraceId: pbTraceID,
+				SpanId:  pbSpanID,
+				Event: &tracepb2.TraceEvent_SpanEvent{SpanEvent: &tracepb2.SpanEvent{
+					Goid:   goid,
+					DefLoc: &udefLoc,
+					Data: &tracepb2.SpanEvent_BucketObjectUploadStart{
+						BucketObjectUploadStart: &tracepb2.BucketObjectUploadStart{
+							Bucket: "bucket",
+							Object: "object",
+							Attrs:  &tracepb2.BucketObjectAttributes{ContentType: ptr("text/plain")},
+							KmsKey: ptr("pii"),
+						},
+					},
+				}},
+			},
+		},
+
+		{
+			Name: "BucketObjectUploadEnd",
+			Emit: func(l *trace2.Log) {
+				l.BucketObjectUploadEnd(trace2.BucketObjectUploadEndParams{
+					EventParams: ep,
+					StartID:     1,
+					Size:        5,
+					Version:     ptr("v1"),
+					Checksum:    ptr("crc32c:mnG7TA=="),
+				})
+			},
+			Want: &tracepb2.TraceEvent{
+				TraceId: pbTraceID,
+				SpanId:  pbSpanID,
+				Event: &tracepb2.TraceEvent_SpanEvent{SpanEvent: &tracepb2.SpanEvent{
+					Goid:               goid,
+					DefLoc:             &udefLoc,
+					CorrelationEventId: ptr[uint64](1),
+					Data: &tracepb2.SpanEvent_BucketObjectUploadEnd{
+						BucketObjectUploadEnd: &tracepb2.BucketObjectUploadEnd{
+							Size:     ptr[uint64](5),
+							Version:  ptr("v1"),
+							Checksum: ptr("crc32c:mnG7TA=="),
+						},
+					},
+				}},
+			},
+		},
+
+		{
+			Name: "BucketObjectDownloadEnd",
+			Emit: func(l *trace2.Log) {
+				l.BucketObjectDownloadEnd(trace2.BucketObjectDownloadEndParams{
+					EventParams: ep,
+					StartID:     1,
+					Size:        5,
+					Checksum:    ptr("crc32c:mnG7TA=="),
+				})
+			},
+			Want: &tracepb2.TraceEvent{
+				TraceId: pbTraceID,
+				SpanId:  pbSpanID,
+				Event: &tracepb2.TraceEvent_SpanEvent{SpanEvent: &tracepb2.SpanEvent{
+					Goid:               goid,
+					DefLoc:             &udefLoc,
+					CorrelationEventId: ptr[uint64](1),
+					Data: &tracepb2.SpanEvent_BucketObjectDownloadEnd{
+						BucketObjectDownloadEnd: &tracepb2.BucketObjectDownloadEnd{
+							Size:     ptr[uint64](5),
+							Checksum: ptr("crc32c:mnG7TA=="),
+						},
+					},
+				}},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -10,6 +10,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -276,6 +277,55 @@ func (DumpMetaRequest_Format) EnumDescriptor() ([]byte, []int) {
 	return file_encore_daemon_daemon_proto_rawDescGZIP(), []int{41, 0}
 }
 
+type PubSubDeadLettersRequest_Op int32
+
+const (
+	PubSubDeadLettersRequest_OP_LIST   PubSubDeadLettersRequest_Op = 0
+	PubSubDeadLettersRequest_OP_REPLAY PubSubDeadLettersRequest_Op = 1
+	PubSubDeadLettersRequest_OP_PURGE  PubSubDeadLettersRequest_Op = 2
+)
+
+// Enum value maps for PubSubDeadLettersRequest_Op.
+var (
+	PubSubDeadLettersRequest_Op_name = map[int32]string{
+		0: "OP_LIST",
+		1: "OP_REPLAY",
+		2: "OP_PURGE",
+	}
+	PubSubDeadLettersRequest_Op_value = map[string]int32{
+		"OP_LIST":   0,
+		"OP_REPLAY": 1,
+		"OP_PURGE":  2,
+	}
+)
+
+func (x PubSubDeadLettersRequest_Op) Enum() *PubSubDeadLettersRequest_Op {
+	p := new(PubSubDeadLettersRequest_Op)
+	*p = x
+	return p
+}
+
+func (x PubSubDeadLettersRequest_Op) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PubSubDeadLettersRequest_Op) Descriptor() protoreflect.EnumDescriptor {
+	return file_encore_daemon_daemon_proto_enumTypes[5].Descriptor()
+}
+
+func (PubSubDeadLettersRequest_Op) Type() protoreflect.EnumType {
+	return &file_encore_daemon_daemon_proto_enumTypes[5]
+}
+
+func (x PubSubDeadLettersRequest_Op) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PubSubDeadLettersRequest_Op.Descriptor instead.
+func (PubSubDeadLettersRequest_Op) EnumDescriptor() ([]byte, []int) {
+	return file_encore_daemon_daemon_proto_rawDescGZIP(), []int{44, 0}
+}
+
 type CommandMessage struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Types that are valid to be assigned to Msg:
@@ -3375,6 +3425,138 @@ func (*SQLCPlugin) Descriptor() ([]byte, []int) {
 	return file_encore_daemon_daemon_proto_rawDescGZIP(), []int{43}
 }
 
+type PubSubDeadLettersRequest struct {
+	state        protoimpl.MessageState      `protogen:"open.v1"`
+	AppRoot      string                      `protobuf:"bytes,1,opt,name=app_root,json=appRoot,proto3" json:"app_root,omitempty"`
+	Topic        string                      `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	Subscription string                      `protobuf:"bytes,3,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	Op           PubSubDeadLettersRequest_Op `protobuf:"varint,4,opt,name=op,proto3,enum=encore.daemon.PubSubDeadLettersRequest_Op" json:"op,omitempty"`
+	// message_ids are the messages to replay.
+	// If empty, all messages are replayed.
+	MessageIds    []string `protobuf:"bytes,5,rep,name=message_ids,json=messageIds,proto3" json:"message_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PubSubDeadLettersRequest) Reset() {
+	*x = PubSubDeadLettersRequest{}
+	mi := &file_encore_daemon_daemon_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PubSubDeadLettersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PubSubDeadLettersRequest) ProtoMessage() {}
+
+func (x *PubSubDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_encore_daemon_daemon_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PubSubDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*PubSubDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_encore_daemon_daemon_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *PubSubDeadLettersRequest) GetAppRoot() string {
+	if x != nil {
+		return x.AppRoot
+	}
+	return ""
+}
+
+func (x *PubSubDeadLettersRequest) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *PubSubDeadLettersRequest) GetSubscription() string {
+	if x != nil {
+		return x.Subscription
+	}
+	return ""
+}
+
+func (x *PubSubDeadLettersRequest) GetOp() PubSubDeadLettersRequest_Op {
+	if x != nil {
+		return x.Op
+	}
+	return PubSubDeadLettersRequest_OP_LIST
+}
+
+func (x *PubSubDeadLettersRequest) GetMessageIds() []string {
+	if x != nil {
+		return x.MessageIds
+	}
+	return nil
+}
+
+type PubSubDeadLettersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// messages are the dead-lettered messages, when listing them.
+	Messages []*PubSubDeadLettersResponse_DeadLetter `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	// count is the number of messages replayed or purged.
+	Count         int32 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PubSubDeadLettersResponse) Reset() {
+	*x = PubSubDeadLettersResponse{}
+	mi := &file_encore_daemon_daemon_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PubSubDeadLettersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PubSubDeadLettersResponse) ProtoMessage() {}
+
+func (x *PubSubDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_encore_daemon_daemon_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PubSubDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*PubSubDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_encore_daemon_daemon_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *PubSubDeadLettersResponse) GetMessages() []*PubSubDeadLettersResponse_DeadLetter {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *PubSubDeadLettersResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
 type SQLCPlugin_File struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -3385,7 +3567,7 @@ type SQLCPlugin_File struct {
 
 func (x *SQLCPlugin_File) Reset() {
 	*x = SQLCPlugin_File{}
-	mi := &file_encore_daemon_daemon_proto_msgTypes[44]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3397,7 +3579,7 @@ func (x *SQLCPlugin_File) String() string {
 func (*SQLCPlugin_File) ProtoMessage() {}
 
 func (x *SQLCPlugin_File) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_daemon_daemon_proto_msgTypes[44]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3440,7 +3622,7 @@ type SQLCPlugin_Settings struct {
 
 func (x *SQLCPlugin_Settings) Reset() {
 	*x = SQLCPlugin_Settings{}
-	mi := &file_encore_daemon_daemon_proto_msgTypes[45]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3452,7 +3634,7 @@ func (x *SQLCPlugin_Settings) String() string {
 func (*SQLCPlugin_Settings) ProtoMessage() {}
 
 func (x *SQLCPlugin_Settings) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_daemon_daemon_proto_msgTypes[45]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3517,7 +3699,7 @@ type SQLCPlugin_Codegen struct {
 
 func (x *SQLCPlugin_Codegen) Reset() {
 	*x = SQLCPlugin_Codegen{}
-	mi := &file_encore_daemon_daemon_proto_msgTypes[46]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3529,7 +3711,7 @@ func (x *SQLCPlugin_Codegen) String() string {
 func (*SQLCPlugin_Codegen) ProtoMessage() {}
 
 func (x *SQLCPlugin_Codegen) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_daemon_daemon_proto_msgTypes[46]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3599,7 +3781,7 @@ type SQLCPlugin_Catalog struct {
 
 func (x *SQLCPlugin_Catalog) Reset() {
 	*x = SQLCPlugin_Catalog{}
-	mi := &file_encore_daemon_daemon_proto_msgTypes[47]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3611,7 +3793,7 @@ func (x *SQLCPlugin_Catalog) String() string {
 func (*SQLCPlugin_Catalog) ProtoMessage() {}
 
 func (x *SQLCPlugin_Catalog) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_daemon_daemon_proto_msgTypes[47]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3668,7 +3850,7 @@ type SQLCPlugin_Schema struct {
 
 func (x *SQLCPlugin_Schema) Reset() {
 	*x = SQLCPlugin_Schema{}
-	mi := &file_encore_daemon_daemon_proto_msgTypes[48]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3680,7 +3862,7 @@ func (x *SQLCPlugin_Schema) String() string {
 func (*SQLCPlugin_Schema) ProtoMessage() {}
 
 func (x *SQLCPlugin_Schema) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_daemon_daemon_proto_msgTypes[48]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3741,7 +3923,7 @@ type SQLCPlugin_CompositeType struct {
 
 func (x *SQLCPlugin_CompositeType) Reset() {
 	*x = SQLCPlugin_CompositeType{}
-	mi := &file_encore_daemon_daemon_proto_msgTypes[49]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3753,7 +3935,7 @@ func (x *SQLCPlugin_CompositeType) String() string {
 func (*SQLCPlugin_CompositeType) ProtoMessage() {}
 
 func (x *SQLCPlugin_CompositeType) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_daemon_daemon_proto_msgTypes[49]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3794,7 +3976,7 @@ type SQLCPlugin_Enum struct {
 
 func (x *SQLCPlugin_Enum) Reset() {
 	*x = SQLCPlugin_Enum{}
-	mi := &file_encore_daemon_daemon_proto_msgTypes[50]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3806,7 +3988,7 @@ func (x *SQLCPlugin_Enum) String() string {
 func (*SQLCPlugin_Enum) ProtoMessage() {}
 
 func (x *SQLCPlugin_Enum) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_daemon_daemon_proto_msgTypes[50]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3854,7 +4036,7 @@ type SQLCPlugin_Table struct {
 
 func (x *SQLCPlugin_Table) Reset() {
 	*x = SQLCPlugin_Table{}
-	mi := &file_encore_daemon_daemon_proto_msgTypes[51]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3866,7 +4048,7 @@ func (x *SQLCPlugin_Table) String() string {
 func (*SQLCPlugin_Table) ProtoMessage() {}
 
 func (x *SQLCPlugin_Table) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_daemon_daemon_proto_msgTypes[51]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3914,7 +4096,7 @@ type SQLCPlugin_Identifier struct {
 
 func (x *SQLCPlugin_Identifier) Reset() {
 	*x = SQLCPlugin_Identifier{}
-	mi := &file_encore_daemon_daemon_proto_msgTypes[52]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3926,7 +4108,7 @@ func (x *SQLCPlugin_Identifier) String() string {
 func (*SQLCPlugin_Identifier) ProtoMessage() {}
 
 func (x *SQLCPlugin_Identifier) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_daemon_daemon_proto_msgTypes[52]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3988,7 +4170,7 @@ type SQLCPlugin_Column struct {
 
 func (x *SQLCPlugin_Column) Reset() {
 	*x = SQLCPlugin_Column{}
-	mi := &file_encore_daemon_daemon_proto_msgTypes[53]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4000,7 +4182,7 @@ func (x *SQLCPlugin_Column) String() string {
 func (*SQLCPlugin_Column) ProtoMessage() {}
 
 func (x *SQLCPlugin_Column) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_daemon_daemon_proto_msgTypes[53]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4144,7 +4326,7 @@ type SQLCPlugin_Query struct {
 
 func (x *SQLCPlugin_Query) Reset() {
 	*x = SQLCPlugin_Query{}
-	mi := &file_encore_daemon_daemon_proto_msgTypes[54]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4156,7 +4338,7 @@ func (x *SQLCPlugin_Query) String() string {
 func (*SQLCPlugin_Query) ProtoMessage() {}
 
 func (x *SQLCPlugin_Query) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_daemon_daemon_proto_msgTypes[54]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4238,7 +4420,7 @@ type SQLCPlugin_Parameter struct {
 
 func (x *SQLCPlugin_Parameter) Reset() {
 	*x = SQLCPlugin_Parameter{}
-	mi := &file_encore_daemon_daemon_proto_msgTypes[55]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4250,7 +4432,7 @@ func (x *SQLCPlugin_Parameter) String() string {
 func (*SQLCPlugin_Parameter) ProtoMessage() {}
 
 func (x *SQLCPlugin_Parameter) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_daemon_daemon_proto_msgTypes[55]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4294,7 +4476,7 @@ type SQLCPlugin_GenerateRequest struct {
 
 func (x *SQLCPlugin_GenerateRequest) Reset() {
 	*x = SQLCPlugin_GenerateRequest{}
-	mi := &file_encore_daemon_daemon_proto_msgTypes[56]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4306,7 +4488,7 @@ func (x *SQLCPlugin_GenerateRequest) String() string {
 func (*SQLCPlugin_GenerateRequest) ProtoMessage() {}
 
 func (x *SQLCPlugin_GenerateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_daemon_daemon_proto_msgTypes[56]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4373,7 +4555,7 @@ type SQLCPlugin_GenerateResponse struct {
 
 func (x *SQLCPlugin_GenerateResponse) Reset() {
 	*x = SQLCPlugin_GenerateResponse{}
-	mi := &file_encore_daemon_daemon_proto_msgTypes[57]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4385,7 +4567,7 @@ func (x *SQLCPlugin_GenerateResponse) String() string {
 func (*SQLCPlugin_GenerateResponse) ProtoMessage() {}
 
 func (x *SQLCPlugin_GenerateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_daemon_daemon_proto_msgTypes[57]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4417,7 +4599,7 @@ type SQLCPlugin_Codegen_Process struct {
 
 func (x *SQLCPlugin_Codegen_Process) Reset() {
 	*x = SQLCPlugin_Codegen_Process{}
-	mi := &file_encore_daemon_daemon_proto_msgTypes[58]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4429,7 +4611,7 @@ func (x *SQLCPlugin_Codegen_Process) String() string {
 func (*SQLCPlugin_Codegen_Process) ProtoMessage() {}
 
 func (x *SQLCPlugin_Codegen_Process) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_daemon_daemon_proto_msgTypes[58]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4462,7 +4644,7 @@ type SQLCPlugin_Codegen_WASM struct {
 
 func (x *SQLCPlugin_Codegen_WASM) Reset() {
 	*x = SQLCPlugin_Codegen_WASM{}
-	mi := &file_encore_daemon_daemon_proto_msgTypes[59]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4474,7 +4656,7 @@ func (x *SQLCPlugin_Codegen_WASM) String() string {
 func (*SQLCPlugin_Codegen_WASM) ProtoMessage() {}
 
 func (x *SQLCPlugin_Codegen_WASM) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_daemon_daemon_proto_msgTypes[59]
+	mi := &file_encore_daemon_daemon_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4504,11 +4686,87 @@ func (x *SQLCPlugin_Codegen_WASM) GetSha256() string {
 	return ""
 }
 
+type PubSubDeadLettersResponse_DeadLetter struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PublishTime     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=publish_time,json=publishTime,proto3" json:"publish_time,omitempty"`
+	DeliveryAttempt int32                  `protobuf:"varint,3,opt,name=delivery_attempt,json=deliveryAttempt,proto3" json:"delivery_attempt,omitempty"`
+	Attributes      map[string]string      `protobuf:"bytes,4,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Data            []byte                 `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"` // the message as JSON
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PubSubDeadLettersResponse_DeadLetter) Reset() {
+	*x = PubSubDeadLettersResponse_DeadLetter{}
+	mi := &file_encore_daemon_daemon_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PubSubDeadLettersResponse_DeadLetter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PubSubDeadLettersResponse_DeadLetter) ProtoMessage() {}
+
+func (x *PubSubDeadLettersResponse_DeadLetter) ProtoReflect() protoreflect.Message {
+	mi := &file_encore_daemon_daemon_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PubSubDeadLettersResponse_DeadLetter.ProtoReflect.Descriptor instead.
+func (*PubSubDeadLettersResponse_DeadLetter) Descriptor() ([]byte, []int) {
+	return file_encore_daemon_daemon_proto_rawDescGZIP(), []int{45, 0}
+}
+
+func (x *PubSubDeadLettersResponse_DeadLetter) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PubSubDeadLettersResponse_DeadLetter) GetPublishTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishTime
+	}
+	return nil
+}
+
+func (x *PubSubDeadLettersResponse_DeadLetter) GetDeliveryAttempt() int32 {
+	if x != nil {
+		return x.DeliveryAttempt
+	}
+	return 0
+}
+
+func (x *PubSubDeadLettersResponse_DeadLetter) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *PubSubDeadLettersResponse_DeadLetter) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
 var File_encore_daemon_daemon_proto protoreflect.FileDescriptor
 
 const file_encore_daemon_daemon_proto_rawDesc = "" +
 	"\n" +
-	"\x1aencore/daemon/daemon.proto\x12\rencore.daemon\x1a\x1bgoogle/protobuf/empty.proto\"\xc0\x01\n" +
+	"\x1aencore/daemon/daemon.proto\x12\rencore.daemon\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc0\x01\n" +
 	"\x0eCommandMessage\x126\n" +
 	"\x06output\x18\x01 \x01(\v2\x1c.encore.daemon.CommandOutputH\x00R\x06output\x120\n" +
 	"\x04exit\x18\x02 \x01(\v2\x1a.encore.daemon.CommandExitH\x00R\x04exit\x12=\n" +
@@ -4869,7 +5127,33 @@ const file_encore_daemon_daemon_proto_rawDesc = "" +
 	"\x0eplugin_options\x18\x05 \x01(\fR\x0eplugin_options\x12&\n" +
 	"\x0eglobal_options\x18\x06 \x01(\fR\x0eglobal_options\x1aH\n" +
 	"\x10GenerateResponse\x124\n" +
-	"\x05files\x18\x01 \x03(\v2\x1e.encore.daemon.SQLCPlugin.FileR\x05files*p\n" +
+	"\x05files\x18\x01 \x03(\v2\x1e.encore.daemon.SQLCPlugin.FileR\x05files\"\xfc\x01\n" +
+	"\x18PubSubDeadLettersRequest\x12\x19\n" +
+	"\bapp_root\x18\x01 \x01(\tR\aappRoot\x12\x14\n" +
+	"\x05topic\x18\x02 \x01(\tR\x05topic\x12\"\n" +
+	"\fsubscription\x18\x03 \x01(\tR\fsubscription\x12:\n" +
+	"\x02op\x18\x04 \x01(\x0e2*.encore.daemon.PubSubDeadLettersRequest.OpR\x02op\x12\x1f\n" +
+	"\vmessage_ids\x18\x05 \x03(\tR\n" +
+	"messageIds\".\n" +
+	"\x02Op\x12\v\n" +
+	"\aOP_LIST\x10\x00\x12\r\n" +
+	"\tOP_REPLAY\x10\x01\x12\f\n" +
+	"\bOP_PURGE\x10\x02\"\xc3\x03\n" +
+	"\x19PubSubDeadLettersResponse\x12O\n" +
+	"\bmessages\x18\x01 \x03(\v23.encore.daemon.PubSubDeadLettersResponse.DeadLetterR\bmessages\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x1a\xbe\x02\n" +
+	"\n" +
+	"DeadLetter\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12=\n" +
+	"\fpublish_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\vpublishTime\x12)\n" +
+	"\x10delivery_attempt\x18\x03 \x01(\x05R\x0fdeliveryAttempt\x12c\n" +
+	"\n" +
+	"attributes\x18\x04 \x03(\v2C.encore.daemon.PubSubDeadLettersResponse.DeadLetter.AttributesEntryR\n" +
+	"attributes\x12\x12\n" +
+	"\x04data\x18\x05 \x01(\fR\x04data\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01*p\n" +
 	"\x06DBRole\x12\x17\n" +
 	"\x13DB_ROLE_UNSPECIFIED\x10\x00\x12\x15\n" +
 	"\x11DB_ROLE_SUPERUSER\x10\x01\x12\x11\n" +
@@ -4880,7 +5164,7 @@ const file_encore_daemon_daemon_proto_rawDesc = "" +
 	"\x1bDB_CLUSTER_TYPE_UNSPECIFIED\x10\x00\x12\x17\n" +
 	"\x13DB_CLUSTER_TYPE_RUN\x10\x01\x12\x18\n" +
 	"\x14DB_CLUSTER_TYPE_TEST\x10\x02\x12\x1a\n" +
-	"\x16DB_CLUSTER_TYPE_SHADOW\x10\x032\xc0\r\n" +
+	"\x16DB_CLUSTER_TYPE_SHADOW\x10\x032\xa8\x0e\n" +
 	"\x06Daemon\x12A\n" +
 	"\x03Run\x12\x19.encore.daemon.RunRequest\x1a\x1d.encore.daemon.CommandMessage0\x01\x12I\n" +
 	"\aRunSpec\x12\x1d.encore.daemon.RunSpecRequest\x1a\x1d.encore.daemon.RunSpecMessage0\x01\x12C\n" +
@@ -4904,7 +5188,8 @@ const file_encore_daemon_daemon_proto_rawDesc = "" +
 	"\x0fDeleteNamespace\x12%.encore.daemon.DeleteNamespaceRequest\x1a\x16.google.protobuf.Empty\x12K\n" +
 	"\bDumpMeta\x12\x1e.encore.daemon.DumpMetaRequest\x1a\x1f.encore.daemon.DumpMetaResponse\x12C\n" +
 	"\tTelemetry\x12\x1e.encore.daemon.TelemetryConfig\x1a\x16.google.protobuf.Empty\x12N\n" +
-	"\tCreateApp\x12\x1f.encore.daemon.CreateAppRequest\x1a .encore.daemon.CreateAppResponseB\x1eZ\x1cencr.dev/proto/encore/daemonb\x06proto3"
+	"\tCreateApp\x12\x1f.encore.daemon.CreateAppRequest\x1a .encore.daemon.CreateAppResponse\x12f\n" +
+	"\x11PubSubDeadLetters\x12'.encore.daemon.PubSubDeadLettersRequest\x1a(.encore.daemon.PubSubDeadLettersResponseB\x1eZ\x1cencr.dev/proto/encore/daemonb\x06proto3"
 
 var (
 	file_encore_daemon_daemon_proto_rawDescOnce sync.Once
@@ -4918,166 +5203,178 @@ func file_encore_daemon_daemon_proto_rawDescGZIP() []byte {
 	return file_encore_daemon_daemon_proto_rawDescData
 }
 
-var file_encore_daemon_daemon_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
-var file_encore_daemon_daemon_proto_msgTypes = make([]protoimpl.MessageInfo, 60)
+var file_encore_daemon_daemon_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_encore_daemon_daemon_proto_msgTypes = make([]protoimpl.MessageInfo, 64)
 var file_encore_daemon_daemon_proto_goTypes = []any{
-	(DBRole)(0),                         // 0: encore.daemon.DBRole
-	(DBClusterType)(0),                  // 1: encore.daemon.DBClusterType
-	(RunRequest_BrowserMode)(0),         // 2: encore.daemon.RunRequest.BrowserMode
-	(RunRequest_DebugMode)(0),           // 3: encore.daemon.RunRequest.DebugMode
-	(DumpMetaRequest_Format)(0),         // 4: encore.daemon.DumpMetaRequest.Format
-	(*CommandMessage)(nil),              // 5: encore.daemon.CommandMessage
-	(*CommandOutput)(nil),               // 6: encore.daemon.CommandOutput
-	(*CommandExit)(nil),                 // 7: encore.daemon.CommandExit
-	(*CommandDisplayErrors)(nil),        // 8: encore.daemon.CommandDisplayErrors
-	(*CreateAppRequest)(nil),            // 9: encore.daemon.CreateAppRequest
-	(*CreateAppResponse)(nil),           // 10: encore.daemon.CreateAppResponse
-	(*RunRequest)(nil),                  // 11: encore.daemon.RunRequest
-	(*RunSpecRequest)(nil),              // 12: encore.daemon.RunSpecRequest
-	(*SpecCommand)(nil),                 // 13: encore.daemon.SpecCommand
-	(*CurlCommand)(nil),                 // 14: encore.daemon.CurlCommand
-	(*RunSpecMessage)(nil),              // 15: encore.daemon.RunSpecMessage
-	(*SpecCommandResult)(nil),           // 16: encore.daemon.SpecCommandResult
-	(*SpecComplete)(nil),                // 17: encore.daemon.SpecComplete
-	(*TestRequest)(nil),                 // 18: encore.daemon.TestRequest
-	(*TestSpecRequest)(nil),             // 19: encore.daemon.TestSpecRequest
-	(*TestSpecResponse)(nil),            // 20: encore.daemon.TestSpecResponse
-	(*ExecScriptRequest)(nil),           // 21: encore.daemon.ExecScriptRequest
-	(*ExecSpecRequest)(nil),             // 22: encore.daemon.ExecSpecRequest
-	(*ExecSpecMessage)(nil),             // 23: encore.daemon.ExecSpecMessage
-	(*ExecSpecResponse)(nil),            // 24: encore.daemon.ExecSpecResponse
-	(*CheckRequest)(nil),                // 25: encore.daemon.CheckRequest
-	(*ExportRequest)(nil),               // 26: encore.daemon.ExportRequest
-	(*DockerExportParams)(nil),          // 27: encore.daemon.DockerExportParams
-	(*DBConnectRequest)(nil),            // 28: encore.daemon.DBConnectRequest
-	(*DBConnectResponse)(nil),           // 29: encore.daemon.DBConnectResponse
-	(*DBProxyRequest)(nil),              // 30: encore.daemon.DBProxyRequest
-	(*DBResetRequest)(nil),              // 31: encore.daemon.DBResetRequest
-	(*GenClientRequest)(nil),            // 32: encore.daemon.GenClientRequest
-	(*GenClientResponse)(nil),           // 33: encore.daemon.GenClientResponse
-	(*GenWrappersRequest)(nil),          // 34: encore.daemon.GenWrappersRequest
-	(*GenWrappersResponse)(nil),         // 35: encore.daemon.GenWrappersResponse
-	(*SecretsRefreshRequest)(nil),       // 36: encore.daemon.SecretsRefreshRequest
-	(*SecretsRefreshResponse)(nil),      // 37: encore.daemon.SecretsRefreshResponse
-	(*VersionResponse)(nil),             // 38: encore.daemon.VersionResponse
-	(*Namespace)(nil),                   // 39: encore.daemon.Namespace
-	(*CreateNamespaceRequest)(nil),      // 40: encore.daemon.CreateNamespaceRequest
-	(*SwitchNamespaceRequest)(nil),      // 41: encore.daemon.SwitchNamespaceRequest
-	(*ListNamespacesRequest)(nil),       // 42: encore.daemon.ListNamespacesRequest
-	(*DeleteNamespaceRequest)(nil),      // 43: encore.daemon.DeleteNamespaceRequest
-	(*ListNamespacesResponse)(nil),      // 44: encore.daemon.ListNamespacesResponse
-	(*TelemetryConfig)(nil),             // 45: encore.daemon.TelemetryConfig
-	(*DumpMetaRequest)(nil),             // 46: encore.daemon.DumpMetaRequest
-	(*DumpMetaResponse)(nil),            // 47: encore.daemon.DumpMetaResponse
-	(*SQLCPlugin)(nil),                  // 48: encore.daemon.SQLCPlugin
-	(*SQLCPlugin_File)(nil),             // 49: encore.daemon.SQLCPlugin.File
-	(*SQLCPlugin_Settings)(nil),         // 50: encore.daemon.SQLCPlugin.Settings
-	(*SQLCPlugin_Codegen)(nil),          // 51: encore.daemon.SQLCPlugin.Codegen
-	(*SQLCPlugin_Catalog)(nil),          // 52: encore.daemon.SQLCPlugin.Catalog
-	(*SQLCPlugin_Schema)(nil),           // 53: encore.daemon.SQLCPlugin.Schema
-	(*SQLCPlugin_CompositeType)(nil),    // 54: encore.daemon.SQLCPlugin.CompositeType
-	(*SQLCPlugin_Enum)(nil),             // 55: encore.daemon.SQLCPlugin.Enum
-	(*SQLCPlugin_Table)(nil),            // 56: encore.daemon.SQLCPlugin.Table
-	(*SQLCPlugin_Identifier)(nil),       // 57: encore.daemon.SQLCPlugin.Identifier
-	(*SQLCPlugin_Column)(nil),           // 58: encore.daemon.SQLCPlugin.Column
-	(*SQLCPlugin_Query)(nil),            // 59: encore.daemon.SQLCPlugin.Query
-	(*SQLCPlugin_Parameter)(nil),        // 60: encore.daemon.SQLCPlugin.Parameter
-	(*SQLCPlugin_GenerateRequest)(nil),  // 61: encore.daemon.SQLCPlugin.GenerateRequest
-	(*SQLCPlugin_GenerateResponse)(nil), // 62: encore.daemon.SQLCPlugin.GenerateResponse
-	(*SQLCPlugin_Codegen_Process)(nil),  // 63: encore.daemon.SQLCPlugin.Codegen.Process
-	(*SQLCPlugin_Codegen_WASM)(nil),     // 64: encore.daemon.SQLCPlugin.Codegen.WASM
-	(*emptypb.Empty)(nil),               // 65: google.protobuf.Empty
+	(DBRole)(0),                                  // 0: encore.daemon.DBRole
+	(DBClusterType)(0),                           // 1: encore.daemon.DBClusterType
+	(RunRequest_BrowserMode)(0),                  // 2: encore.daemon.RunRequest.BrowserMode
+	(RunRequest_DebugMode)(0),                    // 3: encore.daemon.RunRequest.DebugMode
+	(DumpMetaRequest_Format)(0),                  // 4: encore.daemon.DumpMetaRequest.Format
+	(PubSubDeadLettersRequest_Op)(0),             // 5: encore.daemon.PubSubDeadLettersRequest.Op
+	(*CommandMessage)(nil),                       // 6: encore.daemon.CommandMessage
+	(*CommandOutput)(nil),                        // 7: encore.daemon.CommandOutput
+	(*CommandExit)(nil),                          // 8: encore.daemon.CommandExit
+	(*CommandDisplayErrors)(nil),                 // 9: encore.daemon.CommandDisplayErrors
+	(*CreateAppRequest)(nil),                     // 10: encore.daemon.CreateAppRequest
+	(*CreateAppResponse)(nil),                    // 11: encore.daemon.CreateAppResponse
+	(*RunRequest)(nil),                           // 12: encore.daemon.RunRequest
+	(*RunSpecRequest)(nil),                       // 13: encore.daemon.RunSpecRequest
+	(*SpecCommand)(nil),                          // 14: encore.daemon.SpecCommand
+	(*CurlCommand)(nil),                          // 15: encore.daemon.CurlCommand
+	(*RunSpecMessage)(nil),                       // 16: encore.daemon.RunSpecMessage
+	(*SpecCommandResult)(nil),                    // 17: encore.daemon.SpecCommandResult
+	(*SpecComplete)(nil),                         // 18: encore.daemon.SpecComplete
+	(*TestRequest)(nil),                          // 19: encore.daemon.TestRequest
+	(*TestSpecRequest)(nil),                      // 20: encore.daemon.TestSpecRequest
+	(*TestSpecResponse)(nil),                     // 21: encore.daemon.TestSpecResponse
+	(*ExecScriptRequest)(nil),                    // 22: encore.daemon.ExecScriptRequest
+	(*ExecSpecRequest)(nil),                      // 23: encore.daemon.ExecSpecRequest
+	(*ExecSpecMessage)(nil),                      // 24: encore.daemon.ExecSpecMessage
+	(*ExecSpecResponse)(nil),                     // 25: encore.daemon.ExecSpecResponse
+	(*CheckRequest)(nil),                         // 26: encore.daemon.CheckRequest
+	(*ExportRequest)(nil),                        // 27: encore.daemon.ExportRequest
+	(*DockerExportParams)(nil),                   // 28: encore.daemon.DockerExportParams
+	(*DBConnectRequest)(nil),                     // 29: encore.daemon.DBConnectRequest
+	(*DBConnectResponse)(nil),                    // 30: encore.daemon.DBConnectResponse
+	(*DBProxyRequest)(nil),                       // 31: encore.daemon.DBProxyRequest
+	(*DBResetRequest)(nil),                       // 32: encore.daemon.DBResetRequest
+	(*GenClientRequest)(nil),                     // 33: encore.daemon.GenClientRequest
+	(*GenClientResponse)(nil),                    // 34: encore.daemon.GenClientResponse
+	(*GenWrappersRequest)(nil),                   // 35: encore.daemon.GenWrappersRequest
+	(*GenWrappersResponse)(nil),                  // 36: encore.daemon.GenWrappersResponse
+	(*SecretsRefreshRequest)(nil),                // 37: encore.daemon.SecretsRefreshRequest
+	(*SecretsRefreshResponse)(nil),               // 38: encore.daemon.SecretsRefreshResponse
+	(*VersionResponse)(nil),                      // 39: encore.daemon.VersionResponse
+	(*Namespace)(nil),                            // 40: encore.daemon.Namespace
+	(*CreateNamespaceRequest)(nil),               // 41: encore.daemon.CreateNamespaceRequest
+	(*SwitchNamespaceRequest)(nil),               // 42: encore.daemon.SwitchNamespaceRequest
+	(*ListNamespacesRequest)(nil),                // 43: encore.daemon.ListNamespacesRequest
+	(*DeleteNamespaceRequest)(nil),               // 44: encore.daemon.DeleteNamespaceRequest
+	(*ListNamespacesResponse)(nil),               // 45: encore.daemon.ListNamespacesResponse
+	(*TelemetryConfig)(nil),                      // 46: encore.daemon.TelemetryConfig
+	(*DumpMetaRequest)(nil),                      // 47: encore.daemon.DumpMetaRequest
+	(*DumpMetaResponse)(nil),                     // 48: encore.daemon.DumpMetaResponse
+	(*SQLCPlugin)(nil),                           // 49: encore.daemon.SQLCPlugin
+	(*PubSubDeadLettersRequest)(nil),             // 50: encore.daemon.PubSubDeadLettersRequest
+	(*PubSubDeadLettersResponse)(nil),            // 51: encore.daemon.PubSubDeadLettersResponse
+	(*SQLCPlugin_File)(nil),                      // 52: encore.daemon.SQLCPlugin.File
+	(*SQLCPlugin_Settings)(nil),                  // 53: encore.daemon.SQLCPlugin.Settings
+	(*SQLCPlugin_Codegen)(nil),                   // 54: encore.daemon.SQLCPlugin.Codegen
+	(*SQLCPlugin_Catalog)(nil),                   // 55: encore.daemon.SQLCPlugin.Catalog
+	(*SQLCPlugin_Schema)(nil),                    // 56: encore.daemon.SQLCPlugin.Schema
+	(*SQLCPlugin_CompositeType)(nil),             // 57: encore.daemon.SQLCPlugin.CompositeType
+	(*SQLCPlugin_Enum)(nil),                      // 58: encore.daemon.SQLCPlugin.Enum
+	(*SQLCPlugin_Table)(nil),                     // 59: encore.daemon.SQLCPlugin.Table
+	(*SQLCPlugin_Identifier)(nil),                // 60: encore.daemon.SQLCPlugin.Identifier
+	(*SQLCPlugin_Column)(nil),                    // 61: encore.daemon.SQLCPlugin.Column
+	(*SQLCPlugin_Query)(nil),                     // 62: encore.daemon.SQLCPlugin.Query
+	(*SQLCPlugin_Parameter)(nil),                 // 63: encore.daemon.SQLCPlugin.Parameter
+	(*SQLCPlugin_GenerateRequest)(nil),           // 64: encore.daemon.SQLCPlugin.GenerateRequest
+	(*SQLCPlugin_GenerateResponse)(nil),          // 65: encore.daemon.SQLCPlugin.GenerateResponse
+	(*SQLCPlugin_Codegen_Process)(nil),           // 66: encore.daemon.SQLCPlugin.Codegen.Process
+	(*SQLCPlugin_Codegen_WASM)(nil),              // 67: encore.daemon.SQLCPlugin.Codegen.WASM
+	(*PubSubDeadLettersResponse_DeadLetter)(nil), // 68: encore.daemon.PubSubDeadLettersResponse.DeadLetter
+	nil,                           // 69: encore.daemon.PubSubDeadLettersResponse.DeadLetter.AttributesEntry
+	(*timestamppb.Timestamp)(nil), // 70: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),         // 71: google.protobuf.Empty
 }
 var file_encore_daemon_daemon_proto_depIdxs = []int32{
-	6,  // 0: encore.daemon.CommandMessage.output:type_name -> encore.daemon.CommandOutput
-	7,  // 1: encore.daemon.CommandMessage.exit:type_name -> encore.daemon.CommandExit
-	8,  // 2: encore.daemon.CommandMessage.errors:type_name -> encore.daemon.CommandDisplayErrors
+	7,  // 0: encore.daemon.CommandMessage.output:type_name -> encore.daemon.CommandOutput
+	8,  // 1: encore.daemon.CommandMessage.exit:type_name -> encore.daemon.CommandExit
+	9,  // 2: encore.daemon.CommandMessage.errors:type_name -> encore.daemon.CommandDisplayErrors
 	2,  // 3: encore.daemon.RunRequest.browser:type_name -> encore.daemon.RunRequest.BrowserMode
 	3,  // 4: encore.daemon.RunRequest.debug_mode:type_name -> encore.daemon.RunRequest.DebugMode
-	13, // 5: encore.daemon.RunSpecRequest.commands:type_name -> encore.daemon.SpecCommand
-	14, // 6: encore.daemon.SpecCommand.curl:type_name -> encore.daemon.CurlCommand
-	6,  // 7: encore.daemon.RunSpecMessage.output:type_name -> encore.daemon.CommandOutput
-	16, // 8: encore.daemon.RunSpecMessage.result:type_name -> encore.daemon.SpecCommandResult
-	17, // 9: encore.daemon.RunSpecMessage.complete:type_name -> encore.daemon.SpecComplete
-	6,  // 10: encore.daemon.ExecSpecMessage.output:type_name -> encore.daemon.CommandOutput
-	24, // 11: encore.daemon.ExecSpecMessage.spec:type_name -> encore.daemon.ExecSpecResponse
-	27, // 12: encore.daemon.ExportRequest.docker:type_name -> encore.daemon.DockerExportParams
+	14, // 5: encore.daemon.RunSpecRequest.commands:type_name -> encore.daemon.SpecCommand
+	15, // 6: encore.daemon.SpecCommand.curl:type_name -> encore.daemon.CurlCommand
+	7,  // 7: encore.daemon.RunSpecMessage.output:type_name -> encore.daemon.CommandOutput
+	17, // 8: encore.daemon.RunSpecMessage.result:type_name -> encore.daemon.SpecCommandResult
+	18, // 9: encore.daemon.RunSpecMessage.complete:type_name -> encore.daemon.SpecComplete
+	7,  // 10: encore.daemon.ExecSpecMessage.output:type_name -> encore.daemon.CommandOutput
+	25, // 11: encore.daemon.ExecSpecMessage.spec:type_name -> encore.daemon.ExecSpecResponse
+	28, // 12: encore.daemon.ExportRequest.docker:type_name -> encore.daemon.DockerExportParams
 	1,  // 13: encore.daemon.DBConnectRequest.cluster_type:type_name -> encore.daemon.DBClusterType
 	0,  // 14: encore.daemon.DBConnectRequest.role:type_name -> encore.daemon.DBRole
 	1,  // 15: encore.daemon.DBProxyRequest.cluster_type:type_name -> encore.daemon.DBClusterType
 	0,  // 16: encore.daemon.DBProxyRequest.role:type_name -> encore.daemon.DBRole
 	1,  // 17: encore.daemon.DBResetRequest.cluster_type:type_name -> encore.daemon.DBClusterType
-	39, // 18: encore.daemon.ListNamespacesResponse.namespaces:type_name -> encore.daemon.Namespace
+	40, // 18: encore.daemon.ListNamespacesResponse.namespaces:type_name -> encore.daemon.Namespace
 	4,  // 19: encore.daemon.DumpMetaRequest.format:type_name -> encore.daemon.DumpMetaRequest.Format
-	51, // 20: encore.daemon.SQLCPlugin.Settings.codegen:type_name -> encore.daemon.SQLCPlugin.Codegen
-	63, // 21: encore.daemon.SQLCPlugin.Codegen.process:type_name -> encore.daemon.SQLCPlugin.Codegen.Process
-	64, // 22: encore.daemon.SQLCPlugin.Codegen.wasm:type_name -> encore.daemon.SQLCPlugin.Codegen.WASM
-	53, // 23: encore.daemon.SQLCPlugin.Catalog.schemas:type_name -> encore.daemon.SQLCPlugin.Schema
-	56, // 24: encore.daemon.SQLCPlugin.Schema.tables:type_name -> encore.daemon.SQLCPlugin.Table
-	55, // 25: encore.daemon.SQLCPlugin.Schema.enums:type_name -> encore.daemon.SQLCPlugin.Enum
-	54, // 26: encore.daemon.SQLCPlugin.Schema.composite_types:type_name -> encore.daemon.SQLCPlugin.CompositeType
-	57, // 27: encore.daemon.SQLCPlugin.Table.rel:type_name -> encore.daemon.SQLCPlugin.Identifier
-	58, // 28: encore.daemon.SQLCPlugin.Table.columns:type_name -> encore.daemon.SQLCPlugin.Column
-	57, // 29: encore.daemon.SQLCPlugin.Column.table:type_name -> encore.daemon.SQLCPlugin.Identifier
-	57, // 30: encore.daemon.SQLCPlugin.Column.type:type_name -> encore.daemon.SQLCPlugin.Identifier
-	57, // 31: encore.daemon.SQLCPlugin.Column.embed_table:type_name -> encore.daemon.SQLCPlugin.Identifier
-	58, // 32: encore.daemon.SQLCPlugin.Query.columns:type_name -> encore.daemon.SQLCPlugin.Column
-	60, // 33: encore.daemon.SQLCPlugin.Query.params:type_name -> encore.daemon.SQLCPlugin.Parameter
-	57, // 34: encore.daemon.SQLCPlugin.Query.insert_into_table:type_name -> encore.daemon.SQLCPlugin.Identifier
-	58, // 35: encore.daemon.SQLCPlugin.Parameter.column:type_name -> encore.daemon.SQLCPlugin.Column
-	50, // 36: encore.daemon.SQLCPlugin.GenerateRequest.settings:type_name -> encore.daemon.SQLCPlugin.Settings
-	52, // 37: encore.daemon.SQLCPlugin.GenerateRequest.catalog:type_name -> encore.daemon.SQLCPlugin.Catalog
-	59, // 38: encore.daemon.SQLCPlugin.GenerateRequest.queries:type_name -> encore.daemon.SQLCPlugin.Query
-	49, // 39: encore.daemon.SQLCPlugin.GenerateResponse.files:type_name -> encore.daemon.SQLCPlugin.File
-	11, // 40: encore.daemon.Daemon.Run:input_type -> encore.daemon.RunRequest
-	12, // 41: encore.daemon.Daemon.RunSpec:input_type -> encore.daemon.RunSpecRequest
-	18, // 42: encore.daemon.Daemon.Test:input_type -> encore.daemon.TestRequest
-	19, // 43: encore.daemon.Daemon.TestSpec:input_type -> encore.daemon.TestSpecRequest
-	21, // 44: encore.daemon.Daemon.ExecScript:input_type -> encore.daemon.ExecScriptRequest
-	22, // 45: encore.daemon.Daemon.ExecSpec:input_type -> encore.daemon.ExecSpecRequest
-	25, // 46: encore.daemon.Daemon.Check:input_type -> encore.daemon.CheckRequest
-	26, // 47: encore.daemon.Daemon.Export:input_type -> encore.daemon.ExportRequest
-	28, // 48: encore.daemon.Daemon.DBConnect:input_type -> encore.daemon.DBConnectRequest
-	30, // 49: encore.daemon.Daemon.DBProxy:input_type -> encore.daemon.DBProxyRequest
-	31, // 50: encore.daemon.Daemon.DBReset:input_type -> encore.daemon.DBResetRequest
-	32, // 51: encore.daemon.Daemon.GenClient:input_type -> encore.daemon.GenClientRequest
-	34, // 52: encore.daemon.Daemon.GenWrappers:input_type -> encore.daemon.GenWrappersRequest
-	36, // 53: encore.daemon.Daemon.SecretsRefresh:input_type -> encore.daemon.SecretsRefreshRequest
-	65, // 54: encore.daemon.Daemon.Version:input_type -> google.protobuf.Empty
-	40, // 55: encore.daemon.Daemon.CreateNamespace:input_type -> encore.daemon.CreateNamespaceRequest
-	41, // 56: encore.daemon.Daemon.SwitchNamespace:input_type -> encore.daemon.SwitchNamespaceRequest
-	42, // 57: encore.daemon.Daemon.ListNamespaces:input_type -> encore.daemon.ListNamespacesRequest
-	43, // 58: encore.daemon.Daemon.DeleteNamespace:input_type -> encore.daemon.DeleteNamespaceRequest
-	46, // 59: encore.daemon.Daemon.DumpMeta:input_type -> encore.daemon.DumpMetaRequest
-	45, // 60: encore.daemon.Daemon.Telemetry:input_type -> encore.daemon.TelemetryConfig
-	9,  // 61: encore.daemon.Daemon.CreateApp:input_type -> encore.daemon.CreateAppRequest
-	5,  // 62: encore.daemon.Daemon.Run:output_type -> encore.daemon.CommandMessage
-	15, // 63: encore.daemon.Daemon.RunSpec:output_type -> encore.daemon.RunSpecMessage
-	5,  // 64: encore.daemon.Daemon.Test:output_type -> encore.daemon.CommandMessage
-	20, // 65: encore.daemon.Daemon.TestSpec:output_type -> encore.daemon.TestSpecResponse
-	5,  // 66: encore.daemon.Daemon.ExecScript:output_type -> encore.daemon.CommandMessage
-	23, // 67: encore.daemon.Daemon.ExecSpec:output_type -> encore.daemon.ExecSpecMessage
-	5,  // 68: encore.daemon.Daemon.Check:output_type -> encore.daemon.CommandMessage
-	5,  // 69: encore.daemon.Daemon.Export:output_type -> encore.daemon.CommandMessage
-	29, // 70: encore.daemon.Daemon.DBConnect:output_type -> encore.daemon.DBConnectResponse
-	5,  // 71: encore.daemon.Daemon.DBProxy:output_type -> encore.daemon.CommandMessage
-	5,  // 72: encore.daemon.Daemon.DBReset:output_type -> encore.daemon.CommandMessage
-	33, // 73: encore.daemon.Daemon.GenClient:output_type -> encore.daemon.GenClientResponse
-	35, // 74: encore.daemon.Daemon.GenWrappers:output_type -> encore.daemon.GenWrappersResponse
-	37, // 75: encore.daemon.Daemon.SecretsRefresh:output_type -> encore.daemon.SecretsRefreshResponse
-	38, // 76: encore.daemon.Daemon.Version:output_type -> encore.daemon.VersionResponse
-	39, // 77: encore.daemon.Daemon.CreateNamespace:output_type -> encore.daemon.Namespace
-	39, // 78: encore.daemon.Daemon.SwitchNamespace:output_type -> encore.daemon.Namespace
-	44, // 79: encore.daemon.Daemon.ListNamespaces:output_type -> encore.daemon.ListNamespacesResponse
-	65, // 80: encore.daemon.Daemon.DeleteNamespace:output_type -> google.protobuf.Empty
-	47, // 81: encore.daemon.Daemon.DumpMeta:output_type -> encore.daemon.DumpMetaResponse
-	65, // 82: encore.daemon.Daemon.Telemetry:output_type -> google.protobuf.Empty
-	10, // 83: encore.daemon.Daemon.CreateApp:output_type -> encore.daemon.CreateAppResponse
-	62, // [62:84] is the sub-list for method output_type
-	40, // [40:62] is the sub-list for method input_type
-	40, // [40:40] is the sub-list for extension type_name
-	40, // [40:40] is the sub-list for extension extendee
-	0,  // [0:40] is the sub-list for field type_name
+	5,  // 20: encore.daemon.PubSubDeadLettersRequest.op:type_name -> encore.daemon.PubSubDeadLettersRequest.Op
+	68, // 21: encore.daemon.PubSubDeadLettersResponse.messages:type_name -> encore.daemon.PubSubDeadLettersResponse.DeadLetter
+	54, // 22: encore.daemon.SQLCPlugin.Settings.codegen:type_name -> encore.daemon.SQLCPlugin.Codegen
+	66, // 23: encore.daemon.SQLCPlugin.Codegen.process:type_name -> encore.daemon.SQLCPlugin.Codegen.Process
+	67, // 24: encore.daemon.SQLCPlugin.Codegen.wasm:type_name -> encore.daemon.SQLCPlugin.Codegen.WASM
+	56, // 25: encore.daemon.SQLCPlugin.Catalog.schemas:type_name -> encore.daemon.SQLCPlugin.Schema
+	59, // 26: encore.daemon.SQLCPlugin.Schema.tables:type_name -> encore.daemon.SQLCPlugin.Table
+	58, // 27: encore.daemon.SQLCPlugin.Schema.enums:type_name -> encore.daemon.SQLCPlugin.Enum
+	57, // 28: encore.daemon.SQLCPlugin.Schema.composite_types:type_name -> encore.daemon.SQLCPlugin.CompositeType
+	60, // 29: encore.daemon.SQLCPlugin.Table.rel:type_name -> encore.daemon.SQLCPlugin.Identifier
+	61, // 30: encore.daemon.SQLCPlugin.Table.columns:type_name -> encore.daemon.SQLCPlugin.Column
+	60, // 31: encore.daemon.SQLCPlugin.Column.table:type_name -> encore.daemon.SQLCPlugin.Identifier
+	60, // 32: encore.daemon.SQLCPlugin.Column.type:type_name -> encore.daemon.SQLCPlugin.Identifier
+	60, // 33: encore.daemon.SQLCPlugin.Column.embed_table:type_name -> encore.daemon.SQLCPlugin.Identifier
+	61, // 34: encore.daemon.SQLCPlugin.Query.columns:type_name -> encore.daemon.SQLCPlugin.Column
+	63, // 35: encore.daemon.SQLCPlugin.Query.params:type_name -> encore.daemon.SQLCPlugin.Parameter
+	60, // 36: encore.daemon.SQLCPlugin.Query.insert_into_table:type_name -> encore.daemon.SQLCPlugin.Identifier
+	61, // 37: encore.daemon.SQLCPlugin.Parameter.column:type_name -> encore.daemon.SQLCPlugin.Column
+	53, // 38: encore.daemon.SQLCPlugin.GenerateRequest.settings:type_name -> encore.daemon.SQLCPlugin.Settings
+	55, // 39: encore.daemon.SQLCPlugin.GenerateRequest.catalog:type_name -> encore.daemon.SQLCPlugin.Catalog
+	62, // 40: encore.daemon.SQLCPlugin.GenerateRequest.queries:type_name -> encore.daemon.SQLCPlugin.Query
+	52, // 41: encore.daemon.SQLCPlugin.GenerateResponse.files:type_name -> encore.daemon.SQLCPlugin.File
+	70, // 42: encore.daemon.PubSubDeadLettersResponse.DeadLetter.publish_time:type_name -> google.protobuf.Timestamp
+	69, // 43: encore.daemon.PubSubDeadLettersResponse.DeadLetter.attributes:type_name -> encore.daemon.PubSubDeadLettersResponse.DeadLetter.AttributesEntry
+	12, // 44: encore.daemon.Daemon.Run:input_type -> encore.daemon.RunRequest
+	13, // 45: encore.daemon.Daemon.RunSpec:input_type -> encore.daemon.RunSpecRequest
+	19, // 46: encore.daemon.Daemon.Test:input_type -> encore.daemon.TestRequest
+	20, // 47: encore.daemon.Daemon.TestSpec:input_type -> encore.daemon.TestSpecRequest
+	22, // 48: encore.daemon.Daemon.ExecScript:input_type -> encore.daemon.ExecScriptRequest
+	23, // 49: encore.daemon.Daemon.ExecSpec:input_type -> encore.daemon.ExecSpecRequest
+	26, // 50: encore.daemon.Daemon.Check:input_type -> encore.daemon.CheckRequest
+	27, // 51: encore.daemon.Daemon.Export:input_type -> encore.daemon.ExportRequest
+	29, // 52: encore.daemon.Daemon.DBConnect:input_type -> encore.daemon.DBConnectRequest
+	31, // 53: encore.daemon.Daemon.DBProxy:input_type -> encore.daemon.DBProxyRequest
+	32, // 54: encore.daemon.Daemon.DBReset:input_type -> encore.daemon.DBResetRequest
+	33, // 55: encore.daemon.Daemon.GenClient:input_type -> encore.daemon.GenClientRequest
+	35, // 56: encore.daemon.Daemon.GenWrappers:input_type -> encore.daemon.GenWrappersRequest
+	37, // 57: encore.daemon.Daemon.SecretsRefresh:input_type -> encore.daemon.SecretsRefreshRequest
+	71, // 58: encore.daemon.Daemon.Version:input_type -> google.protobuf.Empty
+	41, // 59: encore.daemon.Daemon.CreateNamespace:input_type -> encore.daemon.CreateNamespaceRequest
+	42, // 60: encore.daemon.Daemon.SwitchNamespace:input_type -> encore.daemon.SwitchNamespaceRequest
+	43, // 61: encore.daemon.Daemon.ListNamespaces:input_type -> encore.daemon.ListNamespacesRequest
+	44, // 62: encore.daemon.Daemon.DeleteNamespace:input_type -> encore.daemon.DeleteNamespaceRequest
+	47, // 63: encore.daemon.Daemon.DumpMeta:input_type -> encore.daemon.DumpMetaRequest
+	46, // 64: encore.daemon.Daemon.Telemetry:input_type -> encore.daemon.TelemetryConfig
+	10, // 65: encore.daemon.Daemon.CreateApp:input_type -> encore.daemon.CreateAppRequest
+	50, // 66: encore.daemon.Daemon.PubSubDeadLetters:input_type -> encore.daemon.PubSubDeadLettersRequest
+	6,  // 67: encore.daemon.Daemon.Run:output_type -> encore.daemon.CommandMessage
+	16, // 68: encore.daemon.Daemon.RunSpec:output_type -> encore.daemon.RunSpecMessage
+	6,  // 69: encore.daemon.Daemon.Test:output_type -> encore.daemon.CommandMessage
+	21, // 70: encore.daemon.Daemon.TestSpec:output_type -> encore.daemon.TestSpecResponse
+	6,  // 71: encore.daemon.Daemon.ExecScript:output_type -> encore.daemon.CommandMessage
+	24, // 72: encore.daemon.Daemon.ExecSpec:output_type -> encore.daemon.ExecSpecMessage
+	6,  // 73: encore.daemon.Daemon.Check:output_type -> encore.daemon.CommandMessage
+	6,  // 74: encore.daemon.Daemon.Export:output_type -> encore.daemon.CommandMessage
+	30, // 75: encore.daemon.Daemon.DBConnect:output_type -> encore.daemon.DBConnectResponse
+	6,  // 76: encore.daemon.Daemon.DBProxy:output_type -> encore.daemon.CommandMessage
+	6,  // 77: encore.daemon.Daemon.DBReset:output_type -> encore.daemon.CommandMessage
+	34, // 78: encore.daemon.Daemon.GenClient:output_type -> encore.daemon.GenClientResponse
+	36, // 79: encore.daemon.Daemon.GenWrappers:output_type -> encore.daemon.GenWrappersResponse
+	38, // 80: encore.daemon.Daemon.SecretsRefresh:output_type -> encore.daemon.SecretsRefreshResponse
+	39, // 81: encore.daemon.Daemon.Version:output_type -> encore.daemon.VersionResponse
+	40, // 82: encore.daemon.Daemon.CreateNamespace:output_type -> encore.daemon.Namespace
+	40, // 83: encore.daemon.Daemon.SwitchNamespace:output_type -> encore.daemon.Namespace
+	45, // 84: encore.daemon.Daemon.ListNamespaces:output_type -> encore.daemon.ListNamespacesResponse
+	71, // 85: encore.daemon.Daemon.DeleteNamespace:output_type -> google.protobuf.Empty
+	48, // 86: encore.daemon.Daemon.DumpMeta:output_type -> encore.daemon.DumpMetaResponse
+	71, // 87: encore.daemon.Daemon.Telemetry:output_type -> google.protobuf.Empty
+	11, // 88: encore.daemon.Daemon.CreateApp:output_type -> encore.daemon.CreateAppResponse
+	51, // 89: encore.daemon.Daemon.PubSubDeadLetters:output_type -> encore.daemon.PubSubDeadLettersResponse
+	67, // [67:90] is the sub-list for method output_type
+	44, // [44:67] is the sub-list for method input_type
+	44, // [44:44] is the sub-list for extension type_name
+	44, // [44:44] is the sub-list for extension extendee
+	0,  // [0:44] is the sub-list for field type_name
 }
 
 func init() { file_encore_daemon_daemon_proto_init() }
@@ -5120,8 +5417,8 @@ func file_encore_daemon_daemon_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_encore_daemon_daemon_proto_rawDesc), len(file_encore_daemon_daemon_proto_rawDesc)),
-			NumEnums:      5,
-			NumMessages:   60,
+			NumEnums:      6,
+			NumMessages:   64,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -20,28 +20,29 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Daemon_Run_FullMethodName             = "/encore.daemon.Daemon/Run"
-	Daemon_RunSpec_FullMethodName         = "/encore.daemon.Daemon/RunSpec"
-	Daemon_Test_FullMethodName            = "/encore.daemon.Daemon/Test"
-	Daemon_TestSpec_FullMethodName        = "/encore.daemon.Daemon/TestSpec"
-	Daemon_ExecScript_FullMethodName      = "/encore.daemon.Daemon/ExecScript"
-	Daemon_ExecSpec_FullMethodName        = "/encore.daemon.Daemon/ExecSpec"
-	Daemon_Check_FullMethodName           = "/encore.daemon.Daemon/Check"
-	Daemon_Export_FullMethodName          = "/encore.daemon.Daemon/Export"
-	Daemon_DBConnect_FullMethodName       = "/encore.daemon.Daemon/DBConnect"
-	Daemon_DBProxy_FullMethodName         = "/encore.daemon.Daemon/DBProxy"
-	Daemon_DBReset_FullMethodName         = "/encore.daemon.Daemon/DBReset"
-	Daemon_GenClient_FullMethodName       = "/encore.daemon.Daemon/GenClient"
-	Daemon_GenWrappers_FullMethodName     = "/encore.daemon.Daemon/GenWrappers"
-	Daemon_SecretsRefresh_FullMethodName  = "/encore.daemon.Daemon/SecretsRefresh"
-	Daemon_Version_FullMethodName         = "/encore.daemon.Daemon/Version"
-	Daemon_CreateNamespace_FullMethodName = "/encore.daemon.Daemon/CreateNamespace"
-	Daemon_SwitchNamespace_FullMethodName = "/encore.daemon.Daemon/SwitchNamespace"
-	Daemon_ListNamespaces_FullMethodName  = "/encore.daemon.Daemon/ListNamespaces"
-	Daemon_DeleteNamespace_FullMethodName = "/encore.daemon.Daemon/DeleteNamespace"
-	Daemon_DumpMeta_FullMethodName        = "/encore.daemon.Daemon/DumpMeta"
-	Daemon_Telemetry_FullMethodName       = "/encore.daemon.Daemon/Telemetry"
-	Daemon_CreateApp_FullMethodName       = "/encore.daemon.Daemon/CreateApp"
+	Daemon_Run_FullMethodName               = "/encore.daemon.Daemon/Run"
+	Daemon_RunSpec_FullMethodName           = "/encore.daemon.Daemon/RunSpec"
+	Daemon_Test_FullMethodName              = "/encore.daemon.Daemon/Test"
+	Daemon_TestSpec_FullMethodName          = "/encore.daemon.Daemon/TestSpec"
+	Daemon_ExecScript_FullMethodName        = "/encore.daemon.Daemon/ExecScript"
+	Daemon_ExecSpec_FullMethodName          = "/encore.daemon.Daemon/ExecSpec"
+	Daemon_Check_FullMethodName             = "/encore.daemon.Daemon/Check"
+	Daemon_Export_FullMethodName            = "/encore.daemon.Daemon/Export"
+	Daemon_DBConnect_FullMethodName         = "/encore.daemon.Daemon/DBConnect"
+	Daemon_DBProxy_FullMethodName           = "/encore.daemon.Daemon/DBProxy"
+	Daemon_DBReset_FullMethodName           = "/encore.daemon.Daemon/DBReset"
+	Daemon_GenClient_FullMethodName         = "/encore.daemon.Daemon/GenClient"
+	Daemon_GenWrappers_FullMethodName       = "/encore.daemon.Daemon/GenWrappers"
+	Daemon_SecretsRefresh_FullMethodName    = "/encore.daemon.Daemon/SecretsRefresh"
+	Daemon_Version_FullMethodName           = "/encore.daemon.Daemon/Version"
+	Daemon_CreateNamespace_FullMethodName   = "/encore.daemon.Daemon/CreateNamespace"
+	Daemon_SwitchNamespace_FullMethodName   = "/encore.daemon.Daemon/SwitchNamespace"
+	Daemon_ListNamespaces_FullMethodName    = "/encore.daemon.Daemon/ListNamespaces"
+	Daemon_DeleteNamespace_FullMethodName   = "/encore.daemon.Daemon/DeleteNamespace"
+	Daemon_DumpMeta_FullMethodName          = "/encore.daemon.Daemon/DumpMeta"
+	Daemon_Telemetry_FullMethodName         = "/encore.daemon.Daemon/Telemetry"
+	Daemon_CreateApp_FullMethodName         = "/encore.daemon.Daemon/CreateApp"
+	Daemon_PubSubDeadLetters_FullMethodName = "/encore.daemon.Daemon/PubSubDeadLetters"
 )
 
 // DaemonClient is the client API for Daemon service.
@@ -95,6 +96,8 @@ type DaemonClient interface {
 	Telemetry(ctx context.Context, in *TelemetryConfig, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// InitTutorial sets the tutorial flag of the app
 	CreateApp(ctx context.Context, in *CreateAppRequest, opts ...grpc.CallOption) (*CreateAppResponse, error)
+	// PubSubDeadLetters manages the dead-letter queue of a subscription in the running app.
+	PubSubDeadLetters(ctx context.Context, in *PubSubDeadLettersRequest, opts ...grpc.CallOption) (*PubSubDeadLettersResponse, error)
 }
 
 type daemonClient struct {
@@ -406,6 +409,16 @@ func (c *daemonClient) CreateApp(ctx context.Context, in *CreateAppRequest, opts
 	return out, nil
 }
 
+func (c *daemonClient) PubSubDeadLetters(ctx context.Context, in *PubSubDeadLettersRequest, opts ...grpc.CallOption) (*PubSubDeadLettersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PubSubDeadLettersResponse)
+	err := c.cc.Invoke(ctx, Daemon_PubSubDeadLetters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DaemonServer is the server API for Daemon service.
 // All implementations must embed UnimplementedDaemonServer
 // for forward compatibility.
@@ -457,6 +470,8 @@ type DaemonServer interface {
 	Telemetry(context.Context, *TelemetryConfig) (*emptypb.Empty, error)
 	// InitTutorial sets the tutorial flag of the app
 	CreateApp(context.Context, *CreateAppRequest) (*CreateAppResponse, error)
+	// PubSubDeadLetters manages the dead-letter queue of a subscription in the running app.
+	PubSubDeadLetters(context.Context, *PubSubDeadLettersRequest) (*PubSubDeadLettersResponse, error)
 	mustEmbedUnimplementedDaemonServer()
 }
 
@@ -533,6 +548,9 @@ func (UnimplementedDaemonServer) Telemetry(context.Context, *TelemetryConfig) (*
 func (UnimplementedDaemonServer) CreateApp(context.Context, *CreateAppRequest) (*CreateAppResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateApp not implemented")
 }
+func (UnimplementedDaemonServer) PubSubDeadLetters(context.Context, *PubSubDeadLettersRequest) (*PubSubDeadLettersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PubSubDeadLetters not implemented")
+}
 func (UnimplementedDaemonServer) mustEmbedUnimplementedDaemonServer() {}
 func (UnimplementedDaemonServer) testEmbeddedByValue()                {}
 
@@ -887,6 +905,24 @@ func _Daemon_CreateApp_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Daemon_PubSubDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PubSubDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServer).PubSubDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Daemon_PubSubDeadLetters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServer).PubSubDeadLetters(ctx, req.(*PubSubDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Daemon_ServiceDesc is the grpc.ServiceDesc for Daemon service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -946,6 +982,10 @@ var Daemon_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateApp",
 			Handler:    _Daemon_CreateApp_Handler,
 		},
+		{
+			MethodName: "PubSubDeadLetters",
+			Handler:    _Daemon_PubSubDeadLetters_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -2573,9 +2573,13 @@ func (x *DBTransactionEnd) GetErr() *Error {
 }
 
 type DBQueryStart struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
-	Stack         *StackTrace            `protobuf:"bytes,2,opt,name=stack,proto3" json:"stack,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Stack *StackTrace            `protobuf:"bytes,2,opt,name=stack,proto3" json:"stack,omitempty"`
+	// The name of the read replica the query was routed to, if any.
+	Replica *string `protobuf:"bytes,3,opt,name=replica,proto3,oneof" json:"replica,omitempty"`
+	// The name of the statement, as given with sqldb.WithStatementName.
+	Name          *string `protobuf:"bytes,4,opt,name=name,proto3,oneof" json:"name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -2624,9 +2628,25 @@ func (x *DBQueryStart) GetStack() *StackTrace {
 	return nil
 }
 
+func (x *DBQueryStart) GetReplica() string {
+	if x != nil && x.Replica != nil {
+		return *x.Replica
+	}
+	return ""
+}
+
+func (x *DBQueryStart) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
 type DBQueryEnd struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Err           *Error                 `protobuf:"bytes,1,opt,name=err,proto3,oneof" json:"err,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Err   *Error                 `protobuf:"bytes,1,opt,name=err,proto3,oneof" json:"err,omitempty"`
+	// The number of rows processed, for queries that record it like COPY.
+	Rows          *uint64 `protobuf:"varint,2,opt,name=rows,proto3,oneof" json:"rows,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -2668,6 +2688,13 @@ func (x *DBQueryEnd) GetErr() *Error {
 	return nil
 }
 
+func (x *DBQueryEnd) GetRows() uint64 {
+	if x != nil && x.Rows != nil {
+		return *x.Rows
+	}
+	return 0
+}
+
 type PubsubPublishStart struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Topic         string                 `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
@@ -2869,11 +2896,13 @@ func (x *ServiceInitEnd) GetErr() *Error {
 }
 
 type CacheCallStart struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Operation     string                 `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
-	Keys          []string               `protobuf:"bytes,2,rep,name=keys,proto3" json:"keys,omitempty"`
-	Write         bool                   `protobuf:"varint,3,opt,name=write,proto3" json:"write,omitempty"`
-	Stack         *StackTrace            `protobuf:"bytes,4,opt,name=stack,proto3" json:"stack,omitempty"` // TODO include more info (like inputs)
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Operation string                 `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	Keys      []string               `protobuf:"bytes,2,rep,name=keys,proto3" json:"keys,omitempty"`
+	Write     bool                   `protobuf:"varint,3,opt,name=write,proto3" json:"write,omitempty"`
+	Stack     *StackTrace            `protobuf:"bytes,4,opt,name=stack,proto3" json:"stack,omitempty"`
+	// The number of keys or entries of a batch operation, like MultiGet or MultiSet.
+	BatchSize     *uint64 `protobuf:"varint,5,opt,name=batch_size,json=batchSize,proto3,oneof" json:"batch_size,omitempty"` // TODO include more info (like inputs)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -2936,6 +2965,13 @@ func (x *CacheCallStart) GetStack() *StackTrace {
 	return nil
 }
 
+func (x *CacheCallStart) GetBatchSize() uint64 {
+	if x != nil && x.BatchSize != nil {
+		return *x.BatchSize
+	}
+	return 0
+}
+
 type CacheCallEnd struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Result        CacheCallEnd_Result    `protobuf:"varint,1,opt,name=result,proto3,enum=encore.engine.trace2.CacheCallEnd_Result" json:"result,omitempty"`
@@ -2989,11 +3025,13 @@ func (x *CacheCallEnd) GetErr() *Error {
 }
 
 type BucketObjectUploadStart struct {
-	state         protoimpl.MessageState  `protogen:"open.v1"`
-	Bucket        string                  `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
-	Object        string                  `protobuf:"bytes,2,opt,name=object,proto3" json:"object,omitempty"`
-	Attrs         *BucketObjectAttributes `protobuf:"bytes,3,opt,name=attrs,proto3" json:"attrs,omitempty"`
-	Stack         *StackTrace             `protobuf:"bytes,4,opt,name=stack,proto3" json:"stack,omitempty"`
+	state  protoimpl.MessageState  `protogen:"open.v1"`
+	Bucket string                  `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Object string                  `protobuf:"bytes,2,opt,name=object,proto3" json:"object,omitempty"`
+	Attrs  *BucketObjectAttributes `protobuf:"bytes,3,opt,name=attrs,proto3" json:"attrs,omitempty"`
+	Stack  *StackTrace             `protobuf:"bytes,4,opt,name=stack,proto3" json:"stack,omitempty"`
+	// The reference of the KMS key the object is encrypted with, if any.
+	KmsKey        *string `protobuf:"bytes,5,opt,name=kms_key,json=kmsKey,proto3,oneof" json:"kms_key,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -3056,11 +3094,20 @@ func (x *BucketObjectUploadStart) GetStack() *StackTrace {
 	return nil
 }
 
+func (x *BucketObjectUploadStart) GetKmsKey() string {
+	if x != nil && x.KmsKey != nil {
+		return *x.KmsKey
+	}
+	return ""
+}
+
 type BucketObjectUploadEnd struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Err           *Error                 `protobuf:"bytes,1,opt,name=err,proto3,oneof" json:"err,omitempty"`
-	Size          *uint64                `protobuf:"varint,2,opt,name=size,proto3,oneof" json:"size,omitempty"`
-	Version       *string                `protobuf:"bytes,3,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Err     *Error                 `protobuf:"bytes,1,opt,name=err,proto3,oneof" json:"err,omitempty"`
+	Size    *uint64                `protobuf:"varint,2,opt,name=size,proto3,oneof" json:"size,omitempty"`
+	Version *string                `protobuf:"bytes,3,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	// The checksum of the uploaded content, like "crc32c:yZRlqg==".
+	Checksum      *string `protobuf:"bytes,4,opt,name=checksum,proto3,oneof" json:"checksum,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -3116,6 +3163,13 @@ func (x *BucketObjectUploadEnd) GetVersion() string {
 	return ""
 }
 
+func (x *BucketObjectUploadEnd) GetChecksum() string {
+	if x != nil && x.Checksum != nil {
+		return *x.Checksum
+	}
+	return ""
+}
+
 type BucketObjectDownloadStart struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Bucket        string                 `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
@@ -3185,9 +3239,11 @@ func (x *BucketObjectDownloadStart) GetStack() *StackTrace {
 }
 
 type BucketObjectDownloadEnd struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Err           *Error                 `protobuf:"bytes,1,opt,name=err,proto3,oneof" json:"err,omitempty"`
-	Size          *uint64                `protobuf:"varint,2,opt,name=size,proto3,oneof" json:"size,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Err   *Error                 `protobuf:"bytes,1,opt,name=err,proto3,oneof" json:"err,omitempty"`
+	Size  *uint64                `protobuf:"varint,2,opt,name=size,proto3,oneof" json:"size,omitempty"`
+	// The checksum of the downloaded content, if it was read in full.
+	Checksum      *string `protobuf:"bytes,3,opt,name=checksum,proto3,oneof" json:"checksum,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -3236,6 +3292,13 @@ func (x *BucketObjectDownloadEnd) GetSize() uint64 {
 	return 0
 }
 
+func (x *BucketObjectDownloadEnd) GetChecksum() string {
+	if x != nil && x.Checksum != nil {
+		return *x.Checksum
+	}
+	return ""
+}
+
 type BucketObjectGetAttrsStart struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Bucket        string                 `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
@@ -5595,14 +5658,21 @@ const file_encore_engine_trace2_trace2_proto_rawDesc = "" +
 	"\bROLLBACK\x10\x00\x12\n" +
 	"\n" +
 	"\x06COMMIT\x10\x01B\x06\n" +
-	"\x04_err\"\\\n" +
+	"\x04_err\"\xa9\x01\n" +
 	"\fDBQueryStart\x12\x14\n" +
 	"\x05query\x18\x01 \x01(\tR\x05query\x126\n" +
-	"\x05stack\x18\x02 \x01(\v2 .encore.engine.trace2.StackTraceR\x05stack\"H\n" +
+	"\x05stack\x18\x02 \x01(\v2 .encore.engine.trace2.StackTraceR\x05stack\x12\x1d\n" +
+	"\areplica\x18\x03 \x01(\tH\x00R\areplica\x88\x01\x01\x12\x17\n" +
+	"\x04name\x18\x04 \x01(\tH\x01R\x04name\x88\x01\x01B\n" +
+	"\n" +
+	"\b_replicaB\a\n" +
+	"\x05_name\"j\n" +
 	"\n" +
 	"DBQueryEnd\x122\n" +
-	"\x03err\x18\x01 \x01(\v2\x1b.encore.engine.trace2.ErrorH\x00R\x03err\x88\x01\x01B\x06\n" +
-	"\x04_err\"|\n" +
+	"\x03err\x18\x01 \x01(\v2\x1b.encore.engine.trace2.ErrorH\x00R\x03err\x88\x01\x01\x12\x17\n" +
+	"\x04rows\x18\x02 \x01(\x04H\x01R\x04rows\x88\x01\x01B\x06\n" +
+	"\x04_errB\a\n" +
+	"\x05_rows\"|\n" +
 	"\x12PubsubPublishStart\x12\x14\n" +
 	"\x05topic\x18\x01 \x01(\tR\x05topic\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\fR\amessage\x126\n" +
@@ -5617,12 +5687,15 @@ const file_encore_engine_trace2_trace2_proto_rawDesc = "" +
 	"\aservice\x18\x01 \x01(\tR\aservice\"L\n" +
 	"\x0eServiceInitEnd\x122\n" +
 	"\x03err\x18\x01 \x01(\v2\x1b.encore.engine.trace2.ErrorH\x00R\x03err\x88\x01\x01B\x06\n" +
-	"\x04_err\"\x90\x01\n" +
+	"\x04_err\"\xc3\x01\n" +
 	"\x0eCacheCallStart\x12\x1c\n" +
 	"\toperation\x18\x01 \x01(\tR\toperation\x12\x12\n" +
 	"\x04keys\x18\x02 \x03(\tR\x04keys\x12\x14\n" +
 	"\x05write\x18\x03 \x01(\bR\x05write\x126\n" +
-	"\x05stack\x18\x04 \x01(\v2 .encore.engine.trace2.StackTraceR\x05stack\"\xd4\x01\n" +
+	"\x05stack\x18\x04 \x01(\v2 .encore.engine.trace2.StackTraceR\x05stack\x12\"\n" +
+	"\n" +
+	"batch_size\x18\x05 \x01(\x04H\x00R\tbatchSize\x88\x01\x01B\r\n" +
+	"\v_batch_size\"\xd4\x01\n" +
 	"\fCacheCallEnd\x12A\n" +
 	"\x06result\x18\x01 \x01(\x0e2).encore.engine.trace2.CacheCallEnd.ResultR\x06result\x122\n" +
 	"\x03err\x18\x02 \x01(\v2\x1b.encore.engine.trace2.ErrorH\x00R\x03err\x88\x01\x01\"E\n" +
@@ -5632,32 +5705,39 @@ const file_encore_engine_trace2_trace2_proto_rawDesc = "" +
 	"\vNO_SUCH_KEY\x10\x02\x12\f\n" +
 	"\bCONFLICT\x10\x03\x12\a\n" +
 	"\x03ERR\x10\x04B\x06\n" +
-	"\x04_err\"\xc5\x01\n" +
+	"\x04_err\"\xef\x01\n" +
 	"\x17BucketObjectUploadStart\x12\x16\n" +
 	"\x06bucket\x18\x01 \x01(\tR\x06bucket\x12\x16\n" +
 	"\x06object\x18\x02 \x01(\tR\x06object\x12B\n" +
 	"\x05attrs\x18\x03 \x01(\v2,.encore.engine.trace2.BucketObjectAttributesR\x05attrs\x126\n" +
-	"\x05stack\x18\x04 \x01(\v2 .encore.engine.trace2.StackTraceR\x05stack\"\xa0\x01\n" +
+	"\x05stack\x18\x04 \x01(\v2 .encore.engine.trace2.StackTraceR\x05stack\x12\x1c\n" +
+	"\akms_key\x18\x05 \x01(\tH\x00R\x06kmsKey\x88\x01\x01B\n" +
+	"\n" +
+	"\b_kms_key\"\xce\x01\n" +
 	"\x15BucketObjectUploadEnd\x122\n" +
 	"\x03err\x18\x01 \x01(\v2\x1b.encore.engine.trace2.ErrorH\x00R\x03err\x88\x01\x01\x12\x17\n" +
 	"\x04size\x18\x02 \x01(\x04H\x01R\x04size\x88\x01\x01\x12\x1d\n" +
-	"\aversion\x18\x03 \x01(\tH\x02R\aversion\x88\x01\x01B\x06\n" +
+	"\aversion\x18\x03 \x01(\tH\x02R\aversion\x88\x01\x01\x12\x1f\n" +
+	"\bchecksum\x18\x04 \x01(\tH\x03R\bchecksum\x88\x01\x01B\x06\n" +
 	"\x04_errB\a\n" +
 	"\x05_sizeB\n" +
 	"\n" +
-	"\b_version\"\xae\x01\n" +
+	"\b_versionB\v\n" +
+	"\t_checksum\"\xae\x01\n" +
 	"\x19BucketObjectDownloadStart\x12\x16\n" +
 	"\x06bucket\x18\x01 \x01(\tR\x06bucket\x12\x16\n" +
 	"\x06object\x18\x02 \x01(\tR\x06object\x12\x1d\n" +
 	"\aversion\x18\x03 \x01(\tH\x00R\aversion\x88\x01\x01\x126\n" +
 	"\x05stack\x18\x04 \x01(\v2 .encore.engine.trace2.StackTraceR\x05stackB\n" +
 	"\n" +
-	"\b_version\"w\n" +
+	"\b_version\"\xa5\x01\n" +
 	"\x17BucketObjectDownloadEnd\x122\n" +
 	"\x03err\x18\x01 \x01(\v2\x1b.encore.engine.trace2.ErrorH\x00R\x03err\x88\x01\x01\x12\x17\n" +
-	"\x04size\x18\x02 \x01(\x04H\x01R\x04size\x88\x01\x01B\x06\n" +
+	"\x04size\x18\x02 \x01(\x04H\x01R\x04size\x88\x01\x01\x12\x1f\n" +
+	"\bchecksum\x18\x03 \x01(\tH\x02R\bchecksum\x88\x01\x01B\x06\n" +
 	"\x04_errB\a\n" +
-	"\x05_size\"\xae\x01\n" +
+	"\x05_sizeB\v\n" +
+	"\t_checksum\"\xae\x01\n" +
 	"\x19BucketObjectGetAttrsStart\x12\x16\n" +
 	"\x06bucket\x18\x01 \x01(\tR\x06bucket\x12\x16\n" +
 	"\x06object\x18\x02 \x01(\tR\x06object\x12\x1d\n" +
@@ -6121,10 +6201,13 @@ func file_encore_engine_trace2_trace2_proto_init() {
 	}
 	file_encore_engine_trace2_trace2_proto_msgTypes[16].OneofWrappers = []any{}
 	file_encore_engine_trace2_trace2_proto_msgTypes[20].OneofWrappers = []any{}
+	file_encore_engine_trace2_trace2_proto_msgTypes[21].OneofWrappers = []any{}
 	file_encore_engine_trace2_trace2_proto_msgTypes[22].OneofWrappers = []any{}
 	file_encore_engine_trace2_trace2_proto_msgTypes[24].OneofWrappers = []any{}
 	file_encore_engine_trace2_trace2_proto_msgTypes[26].OneofWrappers = []any{}
+	file_encore_engine_trace2_trace2_proto_msgTypes[27].OneofWrappers = []any{}
 	file_encore_engine_trace2_trace2_proto_msgTypes[28].OneofWrappers = []any{}
+	file_encore_engine_trace2_trace2_proto_msgTypes[29].OneofWrappers = []any{}
 	file_encore_engine_trace2_trace2_proto_msgTypes[30].OneofWrappers = []any{}
 	file_encore_engine_trace2_trace2_proto_msgTypes[31].OneofWrappers = []any{}
 	file_encore_engine_trace2_trace2_proto_msgTypes[32].OneofWrappers = []any{}
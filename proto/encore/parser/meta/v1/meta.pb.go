@@ -2791,13 +2791,15 @@ func (x *DBMigration) GetDescription() string {
 }
 
 type Bucket struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Doc           *string                `protobuf:"bytes,2,opt,name=doc,proto3,oneof" json:"doc,omitempty"`
-	Versioned     bool                   `protobuf:"varint,3,opt,name=versioned,proto3" json:"versioned,omitempty"`
-	Public        bool                   `protobuf:"varint,4,opt,name=public,proto3" json:"public,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state              protoimpl.MessageState      `protogen:"open.v1"`
+	Name               string                      `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Doc                *string                     `protobuf:"bytes,2,opt,name=doc,proto3,oneof" json:"doc,omitempty"`
+	Versioned          bool                        `protobuf:"varint,3,opt,name=versioned,proto3" json:"versioned,omitempty"`
+	Public             bool                        `protobuf:"varint,4,opt,name=public,proto3" json:"public,omitempty"`
+	LifecycleRules     []*Bucket_LifecycleRule     `protobuf:"bytes,5,rep,name=lifecycle_rules,json=lifecycleRules,proto3" json:"lifecycle_rules,omitempty"`             // Rules for managing objects as they age
+	TopicNotifications []*Bucket_TopicNotification `protobuf:"bytes,6,rep,name=topic_notifications,json=topicNotifications,proto3" json:"topic_notifications,omitempty"` // Topics the bucket's object events are published to
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *Bucket) Reset() {
@@ -2858,6 +2860,20 @@ func (x *Bucket) GetPublic() bool {
 	return false
 }
 
+func (x *Bucket) GetLifecycleRules() []*Bucket_LifecycleRule {
+	if x != nil {
+		return x.LifecycleRules
+	}
+	return nil
+}
+
+func (x *Bucket) GetTopicNotifications() []*Bucket_TopicNotification {
+	if x != nil {
+		return x.TopicNotifications
+	}
+	return nil
+}
+
 type PubSubTopic struct {
 	state             protoimpl.MessageState        `protogen:"open.v1"`
 	Name              string                        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                                                                                              // The pub sub topic name (unique per application)
@@ -3309,6 +3325,142 @@ func (x *Gateway_Explicit) GetAuthHandler() *AuthHandler {
 	return nil
 }
 
+type Bucket_LifecycleRule struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Prefix              string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`                                                         // The object name prefix the rule applies to; empty means all objects
+	ExpireAfterDays     int32                  `protobuf:"varint,2,opt,name=expire_after_days,json=expireAfterDays,proto3" json:"expire_after_days,omitempty"`             // Days after creation to delete objects, or 0 to keep them
+	TransitionAfterDays int32                  `protobuf:"varint,3,opt,name=transition_after_days,json=transitionAfterDays,proto3" json:"transition_after_days,omitempty"` // Days after creation to transition objects, or 0 to not transition them
+	TransitionTo        string                 `protobuf:"bytes,4,opt,name=transition_to,json=transitionTo,proto3" json:"transition_to,omitempty"`                         // The storage class to transition to ("infrequent", "cold" or "archive")
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *Bucket_LifecycleRule) Reset() {
+	*x = Bucket_LifecycleRule{}
+	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Bucket_LifecycleRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bucket_LifecycleRule) ProtoMessage() {}
+
+func (x *Bucket_LifecycleRule) ProtoReflect() protoreflect.Message {
+	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bucket_LifecycleRule.ProtoReflect.Descriptor instead.
+func (*Bucket_LifecycleRule) Descriptor() ([]byte, []int) {
+	return file_encore_parser_meta_v1_meta_proto_rawDescGZIP(), []int{26, 0}
+}
+
+func (x *Bucket_LifecycleRule) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *Bucket_LifecycleRule) GetExpireAfterDays() int32 {
+	if x != nil {
+		return x.ExpireAfterDays
+	}
+	return 0
+}
+
+func (x *Bucket_LifecycleRule) GetTransitionAfterDays() int32 {
+	if x != nil {
+		return x.TransitionAfterDays
+	}
+	return 0
+}
+
+func (x *Bucket_LifecycleRule) GetTransitionTo() string {
+	if x != nil {
+		return x.TransitionTo
+	}
+	return ""
+}
+
+type Bucket_TopicNotification struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TopicName     string                 `protobuf:"bytes,1,opt,name=topic_name,json=topicName,proto3" json:"topic_name,omitempty"`       // The topic the events are published to
+	ServiceName   string                 `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"` // The service that publishes the events
+	Events        []string               `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"`                              // The events to publish ("object_created" or "object_deleted"); empty means all
+	Prefix        string                 `protobuf:"bytes,4,opt,name=prefix,proto3" json:"prefix,omitempty"`                              // The object name prefix the events are limited to; empty means all objects
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Bucket_TopicNotification) Reset() {
+	*x = Bucket_TopicNotification{}
+	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Bucket_TopicNotification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bucket_TopicNotification) ProtoMessage() {}
+
+func (x *Bucket_TopicNotification) ProtoReflect() protoreflect.Message {
+	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bucket_TopicNotification.ProtoReflect.Descriptor instead.
+func (*Bucket_TopicNotification) Descriptor() ([]byte, []int) {
+	return file_encore_parser_meta_v1_meta_proto_rawDescGZIP(), []int{26, 1}
+}
+
+func (x *Bucket_TopicNotification) GetTopicName() string {
+	if x != nil {
+		return x.TopicName
+	}
+	return ""
+}
+
+func (x *Bucket_TopicNotification) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *Bucket_TopicNotification) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *Bucket_TopicNotification) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
 type PubSubTopic_Publisher struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ServiceName   string                 `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"` // The service the publisher is in
@@ -3318,7 +3470,7 @@ type PubSubTopic_Publisher struct {
 
 func (x *PubSubTopic_Publisher) Reset() {
 	*x = PubSubTopic_Publisher{}
-	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[36]
+	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3330,7 +3482,7 @@ func (x *PubSubTopic_Publisher) String() string {
 func (*PubSubTopic_Publisher) ProtoMessage() {}
 
 func (x *PubSubTopic_Publisher) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[36]
+	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3363,13 +3515,16 @@ type PubSubTopic_Subscription struct {
 	// How many messages each instance can process concurrently.
 	// If not set, the default is provider-specific.
 	MaxConcurrency *int32 `protobuf:"varint,6,opt,name=max_concurrency,json=maxConcurrency,proto3,oneof" json:"max_concurrency,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Whether redeliveries of already acknowledged messages should be deduplicated,
+	// using the provider's exactly-once delivery where available.
+	AckDeduplication bool `protobuf:"varint,7,opt,name=ack_deduplication,json=ackDeduplication,proto3" json:"ack_deduplication,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *PubSubTopic_Subscription) Reset() {
 	*x = PubSubTopic_Subscription{}
-	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[37]
+	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3381,7 +3536,7 @@ func (x *PubSubTopic_Subscription) String() string {
 func (*PubSubTopic_Subscription) ProtoMessage() {}
 
 func (x *PubSubTopic_Subscription) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[37]
+	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3439,6 +3594,13 @@ func (x *PubSubTopic_Subscription) GetMaxConcurrency() int32 {
 	return 0
 }
 
+func (x *PubSubTopic_Subscription) GetAckDeduplication() bool {
+	if x != nil {
+		return x.AckDeduplication
+	}
+	return false
+}
+
 type PubSubTopic_RetryPolicy struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	MinBackoff    int64                  `protobuf:"varint,1,opt,name=min_backoff,json=minBackoff,proto3" json:"min_backoff,omitempty"` // min backoff in nanoseconds
@@ -3450,7 +3612,7 @@ type PubSubTopic_RetryPolicy struct {
 
 func (x *PubSubTopic_RetryPolicy) Reset() {
 	*x = PubSubTopic_RetryPolicy{}
-	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[38]
+	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3462,7 +3624,7 @@ func (x *PubSubTopic_RetryPolicy) String() string {
 func (*PubSubTopic_RetryPolicy) ProtoMessage() {}
 
 func (x *PubSubTopic_RetryPolicy) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[38]
+	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3512,7 +3674,7 @@ type CacheCluster_Keyspace struct {
 
 func (x *CacheCluster_Keyspace) Reset() {
 	*x = CacheCluster_Keyspace{}
-	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[39]
+	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3524,7 +3686,7 @@ func (x *CacheCluster_Keyspace) String() string {
 func (*CacheCluster_Keyspace) ProtoMessage() {}
 
 func (x *CacheCluster_Keyspace) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[39]
+	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3586,7 +3748,7 @@ type Metric_Label struct {
 
 func (x *Metric_Label) Reset() {
 	*x = Metric_Label{}
-	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[40]
+	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3598,7 +3760,7 @@ func (x *Metric_Label) String() string {
 func (*Metric_Label) ProtoMessage() {}
 
 func (x *Metric_Label) ProtoReflect() protoreflect.Message {
-	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[40]
+	mi := &file_encore_parser_meta_v1_meta_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3928,13 +4090,26 @@ const file_encore_parser_meta_v1_meta_proto_rawDesc = "" +
 	"\vDBMigration\x12\x1a\n" +
 	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x16\n" +
 	"\x06number\x18\x02 \x01(\x04R\x06number\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\"q\n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\"\xe0\x04\n" +
 	"\x06Bucket\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x15\n" +
 	"\x03doc\x18\x02 \x01(\tH\x00R\x03doc\x88\x01\x01\x12\x1c\n" +
 	"\tversioned\x18\x03 \x01(\bR\tversioned\x12\x16\n" +
-	"\x06public\x18\x04 \x01(\bR\x06publicB\x06\n" +
-	"\x04_doc\"\xb8\a\n" +
+	"\x06public\x18\x04 \x01(\bR\x06public\x12T\n" +
+	"\x0flifecycle_rules\x18\x05 \x03(\v2+.encore.parser.meta.v1.Bucket.LifecycleRuleR\x0elifecycleRules\x12`\n" +
+	"\x13topic_notifications\x18\x06 \x03(\v2/.encore.parser.meta.v1.Bucket.TopicNotificationR\x12topicNotifications\x1a\xac\x01\n" +
+	"\rLifecycleRule\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12*\n" +
+	"\x11expire_after_days\x18\x02 \x01(\x05R\x0fexpireAfterDays\x122\n" +
+	"\x15transition_after_days\x18\x03 \x01(\x05R\x13transitionAfterDays\x12#\n" +
+	"\rtransition_to\x18\x04 \x01(\tR\ftransitionTo\x1a\x85\x01\n" +
+	"\x11TopicNotification\x12\x1d\n" +
+	"\n" +
+	"topic_name\x18\x01 \x01(\tR\ttopicName\x12!\n" +
+	"\fservice_name\x18\x02 \x01(\tR\vserviceName\x12\x16\n" +
+	"\x06events\x18\x03 \x03(\tR\x06events\x12\x16\n" +
+	"\x06prefix\x18\x04 \x01(\tR\x06prefixB\x06\n" +
+	"\x04_doc\"\xe5\a\n" +
 	"\vPubSubTopic\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x15\n" +
 	"\x03doc\x18\x02 \x01(\tH\x00R\x03doc\x88\x01\x01\x12@\n" +
@@ -3946,14 +4121,15 @@ const file_encore_parser_meta_v1_meta_proto_rawDesc = "" +
 	"publishers\x12U\n" +
 	"\rsubscriptions\x18\a \x03(\v2/.encore.parser.meta.v1.PubSubTopic.SubscriptionR\rsubscriptions\x1a.\n" +
 	"\tPublisher\x12!\n" +
-	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x1a\xaa\x02\n" +
+	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x1a\xd7\x02\n" +
 	"\fSubscription\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12!\n" +
 	"\fservice_name\x18\x02 \x01(\tR\vserviceName\x12!\n" +
 	"\fack_deadline\x18\x03 \x01(\x03R\vackDeadline\x12+\n" +
 	"\x11message_retention\x18\x04 \x01(\x03R\x10messageRetention\x12Q\n" +
 	"\fretry_policy\x18\x05 \x01(\v2..encore.parser.meta.v1.PubSubTopic.RetryPolicyR\vretryPolicy\x12,\n" +
-	"\x0fmax_concurrency\x18\x06 \x01(\x05H\x00R\x0emaxConcurrency\x88\x01\x01B\x12\n" +
+	"\x0fmax_concurrency\x18\x06 \x01(\x05H\x00R\x0emaxConcurrency\x88\x01\x01\x12+\n" +
+	"\x11ack_deduplication\x18\a \x01(\bR\x10ackDeduplicationB\x12\n" +
 	"\x10_max_concurrency\x1ap\n" +
 	"\vRetryPolicy\x12\x1f\n" +
 	"\vmin_backoff\x18\x01 \x01(\x03R\n" +
@@ -4014,7 +4190,7 @@ func file_encore_parser_meta_v1_meta_proto_rawDescGZIP() []byte {
 }
 
 var file_encore_parser_meta_v1_meta_proto_enumTypes = make([]protoimpl.EnumInfo, 11)
-var file_encore_parser_meta_v1_meta_proto_msgTypes = make([]protoimpl.MessageInfo, 41)
+var file_encore_parser_meta_v1_meta_proto_msgTypes = make([]protoimpl.MessageInfo, 43)
 var file_encore_parser_meta_v1_meta_proto_goTypes = []any{
 	(Lang)(0),                             // 0: encore.parser.meta.v1.Lang
 	(BucketUsage_Operation)(0),            // 1: encore.parser.meta.v1.BucketUsage.Operation
@@ -4063,19 +4239,21 @@ var file_encore_parser_meta_v1_meta_proto_goTypes = []any{
 	(*RPC_StaticAssets_HeaderValues)(nil), // 44: encore.parser.meta.v1.RPC.StaticAssets.HeaderValues
 	nil,                                   // 45: encore.parser.meta.v1.RPC.StaticAssets.HeadersEntry
 	(*Gateway_Explicit)(nil),              // 46: encore.parser.meta.v1.Gateway.Explicit
-	(*PubSubTopic_Publisher)(nil),         // 47: encore.parser.meta.v1.PubSubTopic.Publisher
-	(*PubSubTopic_Subscription)(nil),      // 48: encore.parser.meta.v1.PubSubTopic.Subscription
-	(*PubSubTopic_RetryPolicy)(nil),       // 49: encore.parser.meta.v1.PubSubTopic.RetryPolicy
-	(*CacheCluster_Keyspace)(nil),         // 50: encore.parser.meta.v1.CacheCluster.Keyspace
-	(*Metric_Label)(nil),                  // 51: encore.parser.meta.v1.Metric.Label
-	(*v1.Decl)(nil),                       // 52: encore.parser.schema.v1.Decl
-	(*v1.Type)(nil),                       // 53: encore.parser.schema.v1.Type
-	(*v1.Loc)(nil),                        // 54: encore.parser.schema.v1.Loc
-	(*v1.ValidationExpr)(nil),             // 55: encore.parser.schema.v1.ValidationExpr
-	(v1.Builtin)(0),                       // 56: encore.parser.schema.v1.Builtin
+	(*Bucket_LifecycleRule)(nil),          // 47: encore.parser.meta.v1.Bucket.LifecycleRule
+	(*Bucket_TopicNotification)(nil),      // 48: encore.parser.meta.v1.Bucket.TopicNotification
+	(*PubSubTopic_Publisher)(nil),         // 49: encore.parser.meta.v1.PubSubTopic.Publisher
+	(*PubSubTopic_Subscription)(nil),      // 50: encore.parser.meta.v1.PubSubTopic.Subscription
+	(*PubSubTopic_RetryPolicy)(nil),       // 51: encore.parser.meta.v1.PubSubTopic.RetryPolicy
+	(*CacheCluster_Keyspace)(nil),         // 52: encore.parser.meta.v1.CacheCluster.Keyspace
+	(*Metric_Label)(nil),                  // 53: encore.parser.meta.v1.Metric.Label
+	(*v1.Decl)(nil),                       // 54: encore.parser.schema.v1.Decl
+	(*v1.Type)(nil),                       // 55: encore.parser.schema.v1.Type
+	(*v1.Loc)(nil),                        // 56: encore.parser.schema.v1.Loc
+	(*v1.ValidationExpr)(nil),             // 57: encore.parser.schema.v1.ValidationExpr
+	(v1.Builtin)(0),                       // 58: encore.parser.schema.v1.Builtin
 }
 var file_encore_parser_meta_v1_meta_proto_depIdxs = []int32{
-	52, // 0: encore.parser.meta.v1.Data.decls:type_name -> encore.parser.schema.v1.Decl
+	54, // 0: encore.parser.meta.v1.Data.decls:type_name -> encore.parser.schema.v1.Decl
 	13, // 1: encore.parser.meta.v1.Data.pkgs:type_name -> encore.parser.meta.v1.Package
 	14, // 2: encore.parser.meta.v1.Data.svcs:type_name -> encore.parser.meta.v1.Service
 	18, // 3: encore.parser.meta.v1.Data.auth_handler:type_name -> encore.parser.meta.v1.AuthHandler
@@ -4096,20 +4274,20 @@ var file_encore_parser_meta_v1_meta_proto_depIdxs = []int32{
 	1,  // 18: encore.parser.meta.v1.BucketUsage.operations:type_name -> encore.parser.meta.v1.BucketUsage.Operation
 	2,  // 19: encore.parser.meta.v1.Selector.type:type_name -> encore.parser.meta.v1.Selector.Type
 	3,  // 20: encore.parser.meta.v1.RPC.access_type:type_name -> encore.parser.meta.v1.RPC.AccessType
-	53, // 21: encore.parser.meta.v1.RPC.request_schema:type_name -> encore.parser.schema.v1.Type
-	53, // 22: encore.parser.meta.v1.RPC.response_schema:type_name -> encore.parser.schema.v1.Type
+	55, // 21: encore.parser.meta.v1.RPC.request_schema:type_name -> encore.parser.schema.v1.Type
+	55, // 22: encore.parser.meta.v1.RPC.response_schema:type_name -> encore.parser.schema.v1.Type
 	4,  // 23: encore.parser.meta.v1.RPC.proto:type_name -> encore.parser.meta.v1.RPC.Protocol
-	54, // 24: encore.parser.meta.v1.RPC.loc:type_name -> encore.parser.schema.v1.Loc
+	56, // 24: encore.parser.meta.v1.RPC.loc:type_name -> encore.parser.schema.v1.Loc
 	31, // 25: encore.parser.meta.v1.RPC.path:type_name -> encore.parser.meta.v1.Path
 	16, // 26: encore.parser.meta.v1.RPC.tags:type_name -> encore.parser.meta.v1.Selector
 	41, // 27: encore.parser.meta.v1.RPC.expose:type_name -> encore.parser.meta.v1.RPC.ExposeEntry
-	53, // 28: encore.parser.meta.v1.RPC.handshake_schema:type_name -> encore.parser.schema.v1.Type
+	55, // 28: encore.parser.meta.v1.RPC.handshake_schema:type_name -> encore.parser.schema.v1.Type
 	43, // 29: encore.parser.meta.v1.RPC.static_assets:type_name -> encore.parser.meta.v1.RPC.StaticAssets
-	54, // 30: encore.parser.meta.v1.AuthHandler.loc:type_name -> encore.parser.schema.v1.Loc
-	53, // 31: encore.parser.meta.v1.AuthHandler.auth_data:type_name -> encore.parser.schema.v1.Type
-	53, // 32: encore.parser.meta.v1.AuthHandler.params:type_name -> encore.parser.schema.v1.Type
+	56, // 30: encore.parser.meta.v1.AuthHandler.loc:type_name -> encore.parser.schema.v1.Loc
+	55, // 31: encore.parser.meta.v1.AuthHandler.auth_data:type_name -> encore.parser.schema.v1.Type
+	55, // 32: encore.parser.meta.v1.AuthHandler.params:type_name -> encore.parser.schema.v1.Type
 	12, // 33: encore.parser.meta.v1.Middleware.name:type_name -> encore.parser.meta.v1.QualifiedName
-	54, // 34: encore.parser.meta.v1.Middleware.loc:type_name -> encore.parser.schema.v1.Loc
+	56, // 34: encore.parser.meta.v1.Middleware.loc:type_name -> encore.parser.schema.v1.Loc
 	16, // 35: encore.parser.meta.v1.Middleware.target:type_name -> encore.parser.meta.v1.Selector
 	21, // 36: encore.parser.meta.v1.TraceNode.rpc_def:type_name -> encore.parser.meta.v1.RPCDefNode
 	22, // 37: encore.parser.meta.v1.TraceNode.rpc_call:type_name -> encore.parser.meta.v1.RPCCallNode
@@ -4127,32 +4305,34 @@ var file_encore_parser_meta_v1_meta_proto_depIdxs = []int32{
 	6,  // 49: encore.parser.meta.v1.Path.type:type_name -> encore.parser.meta.v1.Path.Type
 	7,  // 50: encore.parser.meta.v1.PathSegment.type:type_name -> encore.parser.meta.v1.PathSegment.SegmentType
 	8,  // 51: encore.parser.meta.v1.PathSegment.value_type:type_name -> encore.parser.meta.v1.PathSegment.ParamType
-	55, // 52: encore.parser.meta.v1.PathSegment.validation:type_name -> encore.parser.schema.v1.ValidationExpr
+	57, // 52: encore.parser.meta.v1.PathSegment.validation:type_name -> encore.parser.schema.v1.ValidationExpr
 	46, // 53: encore.parser.meta.v1.Gateway.explicit:type_name -> encore.parser.meta.v1.Gateway.Explicit
 	12, // 54: encore.parser.meta.v1.CronJob.endpoint:type_name -> encore.parser.meta.v1.QualifiedName
 	36, // 55: encore.parser.meta.v1.SQLDatabase.migrations:type_name -> encore.parser.meta.v1.DBMigration
-	53, // 56: encore.parser.meta.v1.PubSubTopic.message_type:type_name -> encore.parser.schema.v1.Type
-	9,  // 57: encore.parser.meta.v1.PubSubTopic.delivery_guarantee:type_name -> encore.parser.meta.v1.PubSubTopic.DeliveryGuarantee
-	47, // 58: encore.parser.meta.v1.PubSubTopic.publishers:type_name -> encore.parser.meta.v1.PubSubTopic.Publisher
-	48, // 59: encore.parser.meta.v1.PubSubTopic.subscriptions:type_name -> encore.parser.meta.v1.PubSubTopic.Subscription
-	50, // 60: encore.parser.meta.v1.CacheCluster.keyspaces:type_name -> encore.parser.meta.v1.CacheCluster.Keyspace
-	56, // 61: encore.parser.meta.v1.Metric.value_type:type_name -> encore.parser.schema.v1.Builtin
-	10, // 62: encore.parser.meta.v1.Metric.kind:type_name -> encore.parser.meta.v1.Metric.MetricKind
-	51, // 63: encore.parser.meta.v1.Metric.labels:type_name -> encore.parser.meta.v1.Metric.Label
-	42, // 64: encore.parser.meta.v1.RPC.ExposeEntry.value:type_name -> encore.parser.meta.v1.RPC.ExposeOptions
-	45, // 65: encore.parser.meta.v1.RPC.StaticAssets.headers:type_name -> encore.parser.meta.v1.RPC.StaticAssets.HeadersEntry
-	44, // 66: encore.parser.meta.v1.RPC.StaticAssets.HeadersEntry.value:type_name -> encore.parser.meta.v1.RPC.StaticAssets.HeaderValues
-	18, // 67: encore.parser.meta.v1.Gateway.Explicit.auth_handler:type_name -> encore.parser.meta.v1.AuthHandler
-	49, // 68: encore.parser.meta.v1.PubSubTopic.Subscription.retry_policy:type_name -> encore.parser.meta.v1.PubSubTopic.RetryPolicy
-	53, // 69: encore.parser.meta.v1.CacheCluster.Keyspace.key_type:type_name -> encore.parser.schema.v1.Type
-	53, // 70: encore.parser.meta.v1.CacheCluster.Keyspace.value_type:type_name -> encore.parser.schema.v1.Type
-	31, // 71: encore.parser.meta.v1.CacheCluster.Keyspace.path_pattern:type_name -> encore.parser.meta.v1.Path
-	56, // 72: encore.parser.meta.v1.Metric.Label.type:type_name -> encore.parser.schema.v1.Builtin
-	73, // [73:73] is the sub-list for method output_type
-	73, // [73:73] is the sub-list for method input_type
-	73, // [73:73] is the sub-list for extension type_name
-	73, // [73:73] is the sub-list for extension extendee
-	0,  // [0:73] is the sub-list for field type_name
+	47, // 56: encore.parser.meta.v1.Bucket.lifecycle_rules:type_name -> encore.parser.meta.v1.Bucket.LifecycleRule
+	48, // 57: encore.parser.meta.v1.Bucket.topic_notifications:type_name -> encore.parser.meta.v1.Bucket.TopicNotification
+	55, // 58: encore.parser.meta.v1.PubSubTopic.message_type:type_name -> encore.parser.schema.v1.Type
+	9,  // 59: encore.parser.meta.v1.PubSubTopic.delivery_guarantee:type_name -> encore.parser.meta.v1.PubSubTopic.DeliveryGuarantee
+	49, // 60: encore.parser.meta.v1.PubSubTopic.publishers:type_name -> encore.parser.meta.v1.PubSubTopic.Publisher
+	50, // 61: encore.parser.meta.v1.PubSubTopic.subscriptions:type_name -> encore.parser.meta.v1.PubSubTopic.Subscription
+	52, // 62: encore.parser.meta.v1.CacheCluster.keyspaces:type_name -> encore.parser.meta.v1.CacheCluster.Keyspace
+	58, // 63: encore.parser.meta.v1.Metric.value_type:type_name -> encore.parser.schema.v1.Builtin
+	10, // 64: encore.parser.meta.v1.Metric.kind:type_name -> encore.parser.meta.v1.Metric.MetricKind
+	53, // 65: encore.parser.meta.v1.Metric.labels:type_name -> encore.parser.meta.v1.Metric.Label
+	42, // 66: encore.parser.meta.v1.RPC.ExposeEntry.value:type_name -> encore.parser.meta.v1.RPC.ExposeOptions
+	45, // 67: encore.parser.meta.v1.RPC.StaticAssets.headers:type_name -> encore.parser.meta.v1.RPC.StaticAssets.HeadersEntry
+	44, // 68: encore.parser.meta.v1.RPC.StaticAssets.HeadersEntry.value:type_name -> encore.parser.meta.v1.RPC.StaticAssets.HeaderValues
+	18, // 69: encore.parser.meta.v1.Gateway.Explicit.auth_handler:type_name -> encore.parser.meta.v1.AuthHandler
+	51, // 70: encore.parser.meta.v1.PubSubTopic.Subscription.retry_policy:type_name -> encore.parser.meta.v1.PubSubTopic.RetryPolicy
+	55, // 71: encore.parser.meta.v1.CacheCluster.Keyspace.key_type:type_name -> encore.parser.schema.v1.Type
+	55, // 72: encore.parser.meta.v1.CacheCluster.Keyspace.value_type:type_name -> encore.parser.schema.v1.Type
+	31, // 73: encore.parser.meta.v1.CacheCluster.Keyspace.path_pattern:type_name -> encore.parser.meta.v1.Path
+	58, // 74: encore.parser.meta.v1.Metric.Label.type:type_name -> encore.parser.schema.v1.Builtin
+	75, // [75:75] is the sub-list for method output_type
+	75, // [75:75] is the sub-list for method input_type
+	75, // [75:75] is the sub-list for extension type_name
+	75, // [75:75] is the sub-list for extension extendee
+	0,  // [0:75] is the sub-list for field type_name
 }
 
 func init() { file_encore_parser_meta_v1_meta_proto_init() }
@@ -4185,14 +4365,14 @@ func file_encore_parser_meta_v1_meta_proto_init() {
 	file_encore_parser_meta_v1_meta_proto_msgTypes[29].OneofWrappers = []any{}
 	file_encore_parser_meta_v1_meta_proto_msgTypes[32].OneofWrappers = []any{}
 	file_encore_parser_meta_v1_meta_proto_msgTypes[35].OneofWrappers = []any{}
-	file_encore_parser_meta_v1_meta_proto_msgTypes[37].OneofWrappers = []any{}
+	file_encore_parser_meta_v1_meta_proto_msgTypes[39].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_encore_parser_meta_v1_meta_proto_rawDesc), len(file_encore_parser_meta_v1_meta_proto_rawDesc)),
 			NumEnums:      11,
-			NumMessages:   41,
+			NumMessages:   43,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
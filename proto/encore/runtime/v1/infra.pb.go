@@ -1698,7 +1698,7 @@ type Gateway struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// The unique id for this resource.
 	Rid string `protobuf:"bytes,1,opt,name=rid,proto3" json:"rid,omitempty"`
-	// The encore name of the gateway.
+	//  The encore name of the gateway.
 	EncoreName string `protobuf:"bytes,2,opt,name=encore_name,json=encoreName,proto3" json:"encore_name,omitempty"`
 	// The base url for reaching this gateway, for returning to the application
 	// via e.g. the metadata APIs.
@@ -2224,8 +2224,10 @@ type PubSubSubscription_GCPConfig struct {
 	// The audience to use when validating JWTs delivered over push.
 	// If set, the JWT audience claim must match. If unset, any JWT audience is allowed.
 	PushJwtAudience *string `protobuf:"bytes,3,opt,name=push_jwt_audience,json=pushJwtAudience,proto3,oneof" json:"push_jwt_audience,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	// Whether exactly-once delivery is enabled on the subscription.
+	ExactlyOnceDelivery bool `protobuf:"varint,4,opt,name=exactly_once_delivery,json=exactlyOnceDelivery,proto3" json:"exactly_once_delivery,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
 }
 
 func (x *PubSubSubscription_GCPConfig) Reset() {
@@ -2279,6 +2281,13 @@ func (x *PubSubSubscription_GCPConfig) GetPushJwtAudience() string {
 	return ""
 }
 
+func (x *PubSubSubscription_GCPConfig) GetExactlyOnceDelivery() bool {
+	if x != nil {
+		return x.ExactlyOnceDelivery
+	}
+	return false
+}
+
 type BucketCluster_S3 struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Region to connect to.
@@ -2827,7 +2836,7 @@ const file_encore_runtime_v1_infra_proto_rawDesc = "" +
 	" DELIVERY_GUARANTEE_AT_LEAST_ONCE\x10\x01\x12#\n" +
 	"\x1fDELIVERY_GUARANTEE_EXACTLY_ONCE\x10\x02B\x11\n" +
 	"\x0fprovider_configB\x10\n" +
-	"\x0e_ordering_attr\"\xb4\x04\n" +
+	"\x0e_ordering_attr\"\xe8\x04\n" +
 	"\x12PubSubSubscription\x12\x10\n" +
 	"\x03rid\x18\x01 \x01(\tR\x03rid\x12*\n" +
 	"\x11topic_encore_name\x18\x02 \x01(\tR\x0ftopicEncoreName\x128\n" +
@@ -2837,12 +2846,13 @@ const file_encore_runtime_v1_infra_proto_rawDesc = "" +
 	"\tpush_only\x18\x06 \x01(\bR\bpushOnly\x12P\n" +
 	"\n" +
 	"gcp_config\x18\n" +
-	" \x01(\v2/.encore.runtime.v1.PubSubSubscription.GCPConfigH\x00R\tgcpConfig\x1a\xc1\x01\n" +
+	" \x01(\v2/.encore.runtime.v1.PubSubSubscription.GCPConfigH\x00R\tgcpConfig\x1a\xf5\x01\n" +
 	"\tGCPConfig\x12\x1d\n" +
 	"\n" +
 	"project_id\x18\x01 \x01(\tR\tprojectId\x125\n" +
 	"\x14push_service_account\x18\x02 \x01(\tH\x00R\x12pushServiceAccount\x88\x01\x01\x12/\n" +
-	"\x11push_jwt_audience\x18\x03 \x01(\tH\x01R\x0fpushJwtAudience\x88\x01\x01B\x17\n" +
+	"\x11push_jwt_audience\x18\x03 \x01(\tH\x01R\x0fpushJwtAudience\x88\x01\x01\x122\n" +
+	"\x15exactly_once_delivery\x18\x04 \x01(\bR\x13exactlyOnceDeliveryB\x17\n" +
 	"\x15_push_service_accountB\x14\n" +
 	"\x12_push_jwt_audienceB\x11\n" +
 	"\x0fprovider_config\"\xec\x05\n" +
@@ -849,6 +849,7 @@ pub fn map_infra_to_runtime(infra: InfraConfig) -> RuntimeConfig {
                                                         .push_config
                                                         .as_ref()
                                                         .map(|pc| pc.jwt_audience.clone()),
+                                                    exactly_once_delivery: false,
                                                 },
                                             ),
                                         ),
@@ -7,12 +7,18 @@ import (
 
 	"encore.dev/appruntime/shared/jsonapi"
 	"encore.dev/beta/errs"
+	"encore.dev/pubsub"
 )
 
 func (s *Server) registerEncoreRoutes() {
 	s.encore.HandlerFunc(wildcardMethod, "/healthz", s.handleHealthz)
 	s.encore.Handle("POST", "/pubsub/push/:subscription_id", s.handlePubsubPush)
 	s.encore.Handle("POST", "/authhandler", s.handleRemoteAuthCall)
+	s.encore.Handle("GET", "/pubsub/dlq/:topic/:subscription", s.handlePubsubDeadLetters(pubsub.DeadLetterList))
+	s.encore.Handle("POST", "/pubsub/dlq/:topic/:subscription/replay", s.handlePubsubDeadLetters(pubsub.DeadLetterReplay))
+	s.encore.Handle("DELETE", "/pubsub/dlq/:topic/:subscription", s.handlePubsubDeadLetters(pubsub.DeadLetterPurge))
+	s.encore.Handle("GET", "/pubsub/flow/:topic/:subscription", s.handlePubsubFlowControl)
+	s.encore.Handle("PUT", "/pubsub/flow/:topic/:subscription", s.handlePubsubFlowControl)
 }
 
 // handleHealthz returns the current health and deployment details of the running Encore application
@@ -94,3 +100,15 @@ func (s *Server) handlePubsubPush(w http.ResponseWriter, req *http.Request, ps h
 
 	s.pubsubMgr.HandlePubSubPush(w, req, subscriptionID)
 }
+
+// handlePubsubDeadLetters returns a handler performing op on the dead-letter queue of a subscription.
+func (s *Server) handlePubsubDeadLetters(op pubsub.DeadLetterOp) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		s.pubsubMgr.HandleDeadLetterQueue(w, req, ps.ByName("topic"), ps.ByName("subscription"), op)
+	}
+}
+
+// handlePubsubFlowControl reports or changes the flow control of a subscription.
+func (s *Server) handlePubsubFlowControl(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	s.pubsubMgr.HandleFlowControl(w, req, ps.ByName("topic"), ps.ByName("subscription"))
+}
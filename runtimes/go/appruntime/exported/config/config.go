@@ -18,6 +18,10 @@ type Static struct {
 	CORSExposeHeaders []string // Headers to be exposed by cors
 	PubsubTopics      map[string]*StaticPubsubTopic
 
+	// BucketSubscriptions are the object event subscriptions,
+	// keyed by bucket name and then by subscription name.
+	BucketSubscriptions map[string]map[string]*StaticBucketSubscription
+
 	Testing         bool
 	TestServiceMap  map[string]string // map of service names to their filesystem root
 	TestAppRootPath string            // the root path of the app when running tests
@@ -62,6 +66,7 @@ type Runtime struct {
 	RedisDatabases   []*RedisDatabase        `json:"redis_databases,omitempty"`
 	BucketProviders  []*BucketProvider       `json:"bucket_providers,omitempty"`
 	Buckets          map[string]*Bucket      `json:"buckets,omitempty"`
+	ObjectStorage    *ObjectStorageSettings  `json:"object_storage,omitempty"`
 	Metrics          *Metrics                `json:"metrics,omitempty"`
 	Gateways         []Gateway               `json:"gateways,omitempty"`          // Gateways defines the gateways which should be served by the container
 	HostedServices   []string                `json:"hosted_services,omitempty"`   // List of services to be hosted within this container (zero length means all services, unless there's a gateway running)
@@ -290,6 +295,21 @@ type PubsubTopic struct {
 	// GCP contains GCP-specific configuration.
 	// It is set if the provider is GCP.
 	GCP *PubsubTopicGCPData `json:"gcp,omitempty"`
+
+	// SchemaRegistry is the schema registry to validate the topic's
+	// message schema against before publishing, if any.
+	SchemaRegistry *PubsubSchemaRegistry `json:"schema_registry,omitempty"`
+}
+
+// PubsubSchemaRegistry configures the schema registry for a topic.
+type PubsubSchemaRegistry struct {
+	// URL is the base URL of a registry implementing the Confluent Schema Registry API.
+	// It may include basic auth credentials.
+	URL string `json:"url"`
+
+	// Subject is the subject the schema is registered under.
+	// If empty it defaults to "<topic name>-value".
+	Subject string `json:"subject,omitempty"`
 }
 
 type PubsubSubscription struct {
@@ -316,6 +336,10 @@ type PubsubSubscriptionGCPData struct {
 	// messages being delivered over push.
 	// If empty pushes are not accepted.
 	PushServiceAccount string `json:"push_service_account"`
+
+	// ExactlyOnceDelivery is whether exactly-once delivery
+	// is enabled on the subscription.
+	ExactlyOnceDelivery bool `json:"exactly_once_delivery,omitempty"`
 }
 
 type StaticPubsubTopic struct {
@@ -330,6 +354,12 @@ type StaticPubsubSubscription struct {
 	ScrubPaths []scrub.Path
 }
 
+type StaticBucketSubscription struct {
+	Service string   // the service that subscription is in
+	SvcNum  uint16   // the service number the subscription is in
+	Events  []string // the event types to deliver; empty means all events
+}
+
 type SQLServer struct {
 	// Host is the host to connect to.
 	// Valid formats are "hostname", "hostname:port", and "/path/to/unix.socket".
@@ -357,6 +387,17 @@ type SQLDatabase struct {
 	// MaxConnections is the maximum number of open connections to use
 	// for this database. If zero it defaults to 30.
 	MaxConnections int `json:"max_connections"`
+
+	// ReadReplicas are read-only replicas of the database, used by
+	// (*sqldb.Database).ReadOnly. They're connected to with the same
+	// database name and credentials as the primary.
+	ReadReplicas []*SQLReadReplica `json:"read_replicas,omitempty"`
+}
+
+// SQLReadReplica is a read-only replica of a SQL database.
+type SQLReadReplica struct {
+	Name     string `json:"name"`      // the name of the replica, unique within the database
+	ServerID int    `json:"server_id"` // the index into (*Runtime).SQLServers
 }
 
 type RedisServer struct {
@@ -420,9 +461,30 @@ type S3BucketProvider struct {
 	// Must be set for non-AWS endpoints.
 	Endpoint *string `json:"endpoint"`
 
+	// PathStyle, if true, addresses buckets using the request path
+	// (https://host/bucket/key) rather than a virtual host (https://bucket.host/key).
+	// It's required by most S3-compatible providers, like MinIO.
+	PathStyle bool `json:"path_style,omitempty"`
+
 	// The access key to use. If either is nil, the default credentials are used.
 	AccessKeyID     *string `json:"access_key_id"`
 	SecretAccessKey *string `json:"secret_access_key"`
+
+	// AssumeRole, if set, is an IAM role to assume when accessing the buckets,
+	// such as for cross-account access. The role is assumed using the
+	// credentials above, or the default credentials.
+	AssumeRole *S3AssumeRole `json:"assume_role,omitempty"`
+}
+
+type S3AssumeRole struct {
+	RoleARN string `json:"role_arn"`
+
+	// ExternalID is the external ID required by the role's trust policy, if any.
+	ExternalID string `json:"external_id,omitempty"`
+
+	// SessionName is the name of the role session.
+	// If empty, a name is generated.
+	SessionName string `json:"session_name,omitempty"`
 }
 
 type GCSBucketProvider struct {
@@ -449,6 +511,97 @@ type Bucket struct {
 	// The public base url for the bucket.
 	// Only set if the bucket is public.
 	PublicBaseURL string `json:"public_base_url"`
+
+	// Failover, if set, is a replica bucket that reads are sent to
+	// when the primary bucket is unavailable.
+	Failover *BucketFailover `json:"failover,omitempty"`
+
+	// Encryption, if set, configures the master key used for
+	// client-side encryption of objects in the bucket.
+	Encryption *BucketEncryption `json:"encryption,omitempty"`
+
+	// UploadQuota, if positive, caps the total number of bytes
+	// the process may upload to the bucket.
+	UploadQuota int64 `json:"upload_quota,omitempty"`
+
+	// SoftDelete, if set, makes removed objects recoverable
+	// by moving them to the trash instead of deleting them.
+	SoftDelete *BucketSoftDelete `json:"soft_delete,omitempty"`
+
+	// RequesterPays, if set, accesses the bucket as a requester-pays
+	// bucket, billing the requests to the requester.
+	RequesterPays *BucketRequesterPays `json:"requester_pays,omitempty"`
+
+	// Events, if set, is where the bucket's native event notifications
+	// are received from, for delivering object events to the application.
+	Events *BucketEvents `json:"events,omitempty"`
+
+	// KMSKeys are the KMS keys objects may be encrypted with using
+	// objects.WithKMSKey, keyed by the reference the application uses.
+	// The values are the provider's key IDs.
+	KMSKeys map[string]string `json:"kms_keys,omitempty"`
+}
+
+// ObjectStorageSettings tunes the HTTP clients used to talk to object storage providers,
+// and configures the audit log. Zero values mean the provider's defaults are used.
+type ObjectStorageSettings struct {
+	MaxIdleConns        int           `json:"max_idle_conns,omitempty"`          // max idle connections in total
+	MaxIdleConnsPerHost int           `json:"max_idle_conns_per_host,omitempty"` // max idle connections per host
+	MaxConnsPerHost     int           `json:"max_conns_per_host,omitempty"`      // max connections per host, including active ones
+	IdleConnTimeout     time.Duration `json:"idle_conn_timeout,omitempty"`       // how long idle connections are kept open
+
+	// MaxConcurrentRequests caps the number of concurrent requests to all
+	// object storage providers. Requests beyond the limit are queued.
+	// Zero means unlimited.
+	MaxConcurrentRequests int `json:"max_concurrent_requests,omitempty"`
+
+	// AuditLog, if set, logs an audit record of each object storage operation.
+	AuditLog *ObjectStorageAuditLog `json:"audit_log,omitempty"`
+}
+
+// ObjectStorageAuditLog configures the audit log of object storage operations,
+// recording which objects were accessed, when, and by which request and user.
+// It's logged regardless of the log level.
+type ObjectStorageAuditLog struct {
+	// RedactPrefixes are prefixes of object names that are sensitive,
+	// such as "users/". The rest of the name of objects under them is
+	// logged as a hash rather than in plain text.
+	RedactPrefixes []string `json:"redact_prefixes,omitempty"`
+}
+
+type BucketFailover struct {
+	ProviderID int    `json:"cluster_id"` // the index into (*Runtime).BucketProviders
+	CloudName  string `json:"cloud_name"` // the cloud name for the replica bucket
+}
+
+type BucketEncryption struct {
+	KeyID     string `json:"key_id"`     // identifies the master key, stored with each object
+	MasterKey string `json:"master_key"` // the base64-encoded 256-bit master key
+}
+
+type BucketSoftDelete struct {
+	// TrashPrefix is the prefix removed objects are moved under.
+	// If empty it defaults to ".trash/".
+	TrashPrefix string `json:"trash_prefix,omitempty"`
+}
+
+type BucketRequesterPays struct {
+	// BillingProject is the GCP project requests are billed to.
+	// It's required for GCS. S3 bills the account of the credentials used.
+	BillingProject string `json:"billing_project,omitempty"`
+}
+
+// BucketEvents configures the queue receiving a bucket's native event notifications,
+// which are provisioned when the application subscribes to the bucket's events.
+type BucketEvents struct {
+	// SQSQueueURL is the URL of the SQS queue receiving
+	// the S3 event notifications for the bucket.
+	SQSQueueURL string `json:"sqs_queue_url,omitempty"`
+
+	// GCPProjectID and GCPSubscriptionID identify the Pub/Sub subscription
+	// receiving the GCS Pub/Sub notifications for the bucket.
+	GCPProjectID      string `json:"gcp_project_id,omitempty"`
+	GCPSubscriptionID string `json:"gcp_subscription_id,omitempty"`
 }
 
 type Metrics struct {
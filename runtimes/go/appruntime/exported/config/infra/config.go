@@ -1,12 +1,14 @@
 package infra
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/url"
 	"os"
+	"strings"
 )
 
 type InfraConfig struct {
@@ -141,12 +143,16 @@ func (p *ObjectStorage) UnmarshalJSON(data []byte) error {
 }
 
 type S3 struct {
-	Region   string `json:"region"`
-	Endpoint string `json:"endpoint,omitempty"`
+	Region    string `json:"region"`
+	Endpoint  string `json:"endpoint,omitempty"`
+	PathStyle bool   `json:"path_style,omitempty"`
 
 	AccessKeyID     string    `json:"access_key_id,omitempty"`
 	SecretAccessKey EnvString `json:"secret_access_key,omitempty"`
 
+	// AssumeRole, if set, is an IAM role to assume when accessing the buckets.
+	AssumeRole *S3AssumeRole `json:"assume_role,omitempty"`
+
 	Buckets map[string]*Bucket `json:"buckets,omitempty"`
 }
 
@@ -155,9 +161,27 @@ func (a *S3) Validate(v *validator) {
 	if a.AccessKeyID != "" {
 		v.ValidatePtrEnvRef("secret_access_key", &a.SecretAccessKey, "S3 Secret Access Key", NotZero[string])
 	}
+	v.ValidateChild("assume_role", a.AssumeRole)
 	ValidateChildMap(v, "buckets", a.Buckets)
 }
 
+// S3AssumeRole configures an IAM role to assume for accessing S3,
+// such as for cross-account access.
+type S3AssumeRole struct {
+	RoleARN     string `json:"role_arn,omitempty"`
+	ExternalID  string `json:"external_id,omitempty"`
+	SessionName string `json:"session_name,omitempty"`
+}
+
+func (a *S3AssumeRole) Validate(v *validator) {
+	v.ValidateField("role_arn", func() error {
+		if !strings.HasPrefix(a.RoleARN, "arn:") || !strings.Contains(a.RoleARN, ":role/") {
+			return fmt.Errorf("Must be an IAM role ARN, like arn:aws:iam::123456789012:role/name")
+		}
+		return nil
+	})
+}
+
 type GCS struct {
 	Endpoint string             `json:"endpoint,omitempty"`
 	Buckets  map[string]*Bucket `json:"buckets,omitempty"`
@@ -165,12 +189,43 @@ type GCS struct {
 
 func (a *GCS) Validate(v *validator) {
 	ValidateChildMap(v, "buckets", a.Buckets)
+	for name, bkt := range a.Buckets {
+		if rp := bkt.RequesterPays; rp != nil && rp.BillingProject == "" {
+			v.ValidateField("buckets."+name+".requester_pays.billing_project", Err("must be set for GCS buckets"))
+		}
+		for ref, key := range bkt.KMSKeys {
+			if key != "" && (!strings.HasPrefix(key, "projects/") || !strings.Contains(key, "/cryptoKeys/")) {
+				v.ValidateField("buckets."+name+".kms_keys."+ref, Err("must be a Cloud KMS key resource name, like projects/p/locations/l/keyRings/r/cryptoKeys/k"))
+			}
+		}
+	}
 }
 
 type Bucket struct {
-	Name          string `json:"name,omitempty"`
-	KeyPrefix     string `json:"key_prefix,omitempty"`
-	PublicBaseURL string `json:"public_base_url,omitempty"`
+	Name          string          `json:"name,omitempty"`
+	KeyPrefix     string          `json:"key_prefix,omitempty"`
+	PublicBaseURL string          `json:"public_base_url,omitempty"`
+	Failover      *BucketFailover `json:"failover,omitempty"`
+
+	// Encryption, if set, configures client-side encryption for the bucket.
+	Encryption *BucketEncryption `json:"encryption,omitempty"`
+
+	// UploadQuota, if set, caps the total number of bytes
+	// each process may upload to the bucket.
+	UploadQuota int64 `json:"upload_quota,omitempty"`
+
+	// SoftDelete, if set, moves removed objects to the trash
+	// instead of deleting them.
+	SoftDelete *BucketSoftDelete `json:"soft_delete,omitempty"`
+
+	// RequesterPays, if set, accesses the bucket as a requester-pays
+	// bucket, billing the requests to the requester.
+	RequesterPays *BucketRequesterPays `json:"requester_pays,omitempty"`
+
+	// KMSKeys are the KMS keys objects may be encrypted with, by the reference
+	// passed to objects.WithKMSKey. The values are AWS KMS key IDs or ARNs for
+	// S3, and Cloud KMS key resource names for GCS.
+	KMSKeys map[string]string `json:"kms_keys,omitempty"`
 }
 
 func (a *Bucket) Validate(v *validator) {
@@ -184,6 +239,76 @@ func (a *Bucket) Validate(v *validator) {
 		}
 		return nil
 	})
+	v.ValidateChild("failover", a.Failover)
+	v.ValidateChild("encryption", a.Encryption)
+	v.ValidateField("upload_quota", func() error {
+		if a.UploadQuota < 0 {
+			return fmt.Errorf("Must not be negative")
+		}
+		return nil
+	})
+	v.ValidateChild("soft_delete", a.SoftDelete)
+	for ref, key := range a.KMSKeys {
+		v.ValidateField("kms_keys."+ref, NotZero(key))
+	}
+}
+
+// BucketFailover configures a replica bucket that reads fail over to
+// when the primary bucket is unavailable. The replica uses the same key prefix.
+type BucketFailover struct {
+	Name     string         `json:"name,omitempty"`
+	Provider *ObjectStorage `json:"provider,omitempty"`
+}
+
+func (a *BucketFailover) Validate(v *validator) {
+	v.ValidateField("name", NotZero(a.Name))
+	if a.Provider == nil {
+		v.ValidateField("provider", Err("must be set"))
+	}
+	v.ValidateChild("provider", a.Provider)
+}
+
+// BucketEncryption configures the master key that the data keys of
+// client-side encrypted objects are wrapped with.
+type BucketEncryption struct {
+	KeyID     string    `json:"key_id,omitempty"`
+	MasterKey EnvString `json:"master_key,omitempty"`
+}
+
+func (a *BucketEncryption) Validate(v *validator) {
+	v.ValidateField("key_id", NotZero(a.KeyID))
+	v.ValidateEnvString("master_key", a.MasterKey, "Bucket Encryption Master Key", func(key string) Predicate {
+		return func() error {
+			data, err := base64.StdEncoding.DecodeString(key)
+			if err != nil {
+				return fmt.Errorf("Must be base64-encoded: %v", err)
+			} else if len(data) != 32 {
+				return fmt.Errorf("Must be a 256-bit key, got %d bits", len(data)*8)
+			}
+			return nil
+		}
+	})
+}
+
+// BucketSoftDelete configures the trash that removed objects are moved to.
+type BucketSoftDelete struct {
+	TrashPrefix string `json:"trash_prefix,omitempty"`
+}
+
+func (a *BucketSoftDelete) Validate(v *validator) {
+	v.ValidateField("trash_prefix", func() error {
+		if a.TrashPrefix != "" && !strings.HasSuffix(a.TrashPrefix, "/") {
+			return fmt.Errorf("Must end with a '/'")
+		}
+		return nil
+	})
+}
+
+// BucketRequesterPays configures access to a requester-pays bucket.
+type BucketRequesterPays struct {
+	// BillingProject is the GCP project requests are billed to.
+	// It's required for GCS, and unused for S3.
+	BillingProject string `json:"billing_project,omitempty"`
 }
 
 type Metadata struct {
@@ -489,6 +614,10 @@ type SQLDatabase struct {
 	Username       EnvString   `json:"username,omitempty"`
 	Password       EnvString   `json:"password,omitempty"`
 	ClientCert     *ClientCert `json:"client_cert,omitempty"`
+
+	// ReadReplicas are read-only replicas of the database,
+	// used for queries made through (*sqldb.Database).ReadOnly.
+	ReadReplicas []*SQLReadReplica `json:"read_replicas,omitempty"`
 }
 
 func (s *SQLDatabase) Validate(v *validator) {
@@ -497,6 +626,21 @@ func (s *SQLDatabase) Validate(v *validator) {
 	v.ValidateEnvString("username", s.Username, "Database Username", NotZero[string])
 	v.ValidateEnvString("password", s.Password, "Database Password", NotZero[string])
 	v.ValidateChild("client_cert", s.ClientCert)
+	ValidateChildList(v, "read_replicas", s.ReadReplicas)
+}
+
+// SQLReadReplica is a read-only replica of a SQL database,
+// connected to with the same credentials as the primary.
+type SQLReadReplica struct {
+	Name      string     `json:"name,omitempty"`
+	Host      string     `json:"host,omitempty"`
+	TLSConfig *TLSConfig `json:"tls_config,omitempty"`
+}
+
+func (r *SQLReadReplica) Validate(v *validator) {
+	v.ValidateField("name", NotZero(r.Name))
+	v.ValidateField("host", NotZero(r.Host))
+	v.ValidateChild("tls_config", r.TLSConfig)
 }
 
 type Redis struct {
@@ -597,6 +741,23 @@ func (p *PubSub) GetTopics() map[string]PubsubTopic {
 type PubsubTopic interface {
 	GetSubscriptions() map[string]PubsubSubscription
 	DeleteSubscription(name string)
+	GetSchemaRegistry() *SchemaRegistry
+}
+
+// SchemaRegistry configures the schema registry a topic's
+// message schema is validated against before publishing.
+type SchemaRegistry struct {
+	// URL is the base URL of a registry implementing the Confluent Schema Registry API.
+	// It may include basic auth credentials.
+	URL string `json:"url,omitempty"`
+
+	// Subject is the subject the schema is registered under.
+	// It defaults to "<topic name>-value".
+	Subject string `json:"subject,omitempty"`
+}
+
+func (s *SchemaRegistry) Validate(v *validator) {
+	v.ValidateField("url", NotZero(s.URL))
 }
 
 type PubsubSubscription interface{}
@@ -627,9 +788,10 @@ func (g *GCPPubsub) DeleteTopic(name string) {
 }
 
 type GCPTopic struct {
-	Name          string             `json:"name,omitempty"`
-	ProjectID     string             `json:"project_id,omitempty"`
-	Subscriptions map[string]*GCPSub `json:"subscriptions,omitempty"`
+	Name           string             `json:"name,omitempty"`
+	ProjectID      string             `json:"project_id,omitempty"`
+	Subscriptions  map[string]*GCPSub `json:"subscriptions,omitempty"`
+	SchemaRegistry *SchemaRegistry    `json:"schema_registry,omitempty"`
 }
 
 func (g *GCPTopic) Validate(v *validator) {
@@ -637,6 +799,11 @@ func (g *GCPTopic) Validate(v *validator) {
 	pubsub := Ancestor[*PubSub](v)
 	v.ValidateField("project_id", AnyNonZero(g.ProjectID, pubsub.GCP.ProjectID))
 	ValidateChildMap(v, "subscriptions", g.Subscriptions)
+	v.ValidateChild("schema_registry", g.SchemaRegistry)
+}
+
+func (g *GCPTopic) GetSchemaRegistry() *SchemaRegistry {
+	return g.SchemaRegistry
 }
 
 func (g *GCPTopic) GetSubscriptions() map[string]PubsubSubscription {
@@ -694,13 +861,19 @@ func (a *AWSSNS_SQS) DeleteTopic(name string) {
 }
 
 type AWSTopic struct {
-	ARN           string             `json:"arn,omitempty"`
-	Subscriptions map[string]*AWSSub `json:"subscriptions,omitempty"`
+	ARN            string             `json:"arn,omitempty"`
+	Subscriptions  map[string]*AWSSub `json:"subscriptions,omitempty"`
+	SchemaRegistry *SchemaRegistry    `json:"schema_registry,omitempty"`
 }
 
 func (a *AWSTopic) Validate(v *validator) {
 	v.ValidateField("arn", NotZero(a.ARN))
 	ValidateChildMap(v, "subscriptions", a.Subscriptions)
+	v.ValidateChild("schema_registry", a.SchemaRegistry)
+}
+
+func (a *AWSTopic) GetSchemaRegistry() *SchemaRegistry {
+	return a.SchemaRegistry
 }
 
 func (a *AWSTopic) GetSubscriptions() map[string]PubsubSubscription {
@@ -743,13 +916,19 @@ func (n *NSQPubsub) DeleteTopic(name string) {
 }
 
 type NSQTopic struct {
-	Name          string             `json:"name,omitempty"`
-	Subscriptions map[string]*NSQSub `json:"subscriptions,omitempty"`
+	Name           string             `json:"name,omitempty"`
+	Subscriptions  map[string]*NSQSub `json:"subscriptions,omitempty"`
+	SchemaRegistry *SchemaRegistry    `json:"schema_registry,omitempty"`
 }
 
 func (n *NSQTopic) Validate(v *validator) {
 	v.ValidateField("name", NotZero(n.Name))
 	ValidateChildMap(v, "subscriptions", n.Subscriptions)
+	v.ValidateChild("schema_registry", n.SchemaRegistry)
+}
+
+func (n *NSQTopic) GetSchemaRegistry() *SchemaRegistry {
+	return n.SchemaRegistry
 }
 
 func (n *NSQTopic) GetSubscriptions() map[string]PubsubSubscription {
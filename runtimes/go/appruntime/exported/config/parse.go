@@ -229,19 +229,10 @@ func parseInfraConfigEnv(infraCfgPath string) *Runtime {
 	// Map SQL servers configuration
 	cfg.SQLServers = make([]*SQLServer, len(infraCfg.SQLServers))
 	for i, sqlServer := range infraCfg.SQLServers {
-		cfg.SQLServers[i] = &SQLServer{
-			Host: sqlServer.Host,
-		}
-		if sqlServer.TLSConfig != nil && !sqlServer.TLSConfig.Disabled {
-			cfg.SQLServers[i].ServerCACert = sqlServer.TLSConfig.CA
-			if sqlServer.TLSConfig.ClientCert != nil {
-				cfg.SQLServers[i].ClientCert = sqlServer.TLSConfig.ClientCert.Cert
-				cfg.SQLServers[i].ClientKey = sqlServer.TLSConfig.ClientCert.Key.Value()
-			}
-		}
+		cfg.SQLServers[i] = mapSQLServer(sqlServer.Host, sqlServer.TLSConfig)
 
 		for dbName, db := range sqlServer.Databases {
-			cfg.SQLDatabases = append(cfg.SQLDatabases, &SQLDatabase{
+			sqlDB := &SQLDatabase{
 				ServerID:       i,
 				EncoreName:     orDefault(db.Name, dbName),
 				DatabaseName:   dbName,
@@ -249,7 +240,17 @@ func parseInfraConfigEnv(infraCfgPath string) *Runtime {
 				Password:       db.Password.Value(),
 				MinConnections: db.MinConnections,
 				MaxConnections: db.MaxConnections,
-			})
+			}
+			// Each replica is a separate server, added after the primary servers
+			// so that the server IDs of the primaries are unaffected.
+			for _, replica := range db.ReadReplicas {
+				cfg.SQLServers = append(cfg.SQLServers, mapSQLServer(replica.Host, replica.TLSConfig))
+				sqlDB.ReadReplicas = append(sqlDB.ReadReplicas, &SQLReadReplica{
+					Name:     replica.Name,
+					ServerID: len(cfg.SQLServers) - 1,
+				})
+			}
+			cfg.SQLDatabases = append(cfg.SQLDatabases, sqlDB)
 		}
 	}
 
@@ -338,6 +339,12 @@ func parseInfraConfigEnv(infraCfgPath string) *Runtime {
 					Subscriptions: map[string]*PubsubSubscription{},
 				}
 			}
+			if reg := topic.GetSchemaRegistry(); reg != nil {
+				cfg.PubsubTopics[topicName].SchemaRegistry = &PubsubSchemaRegistry{
+					URL:     reg.URL,
+					Subject: reg.Subject,
+				}
+			}
 
 			for subName, subscription := range topic.GetSubscriptions() {
 				switch subscription := subscription.(type) {
@@ -384,23 +391,7 @@ func parseInfraConfigEnv(infraCfgPath string) *Runtime {
 	// Map Buckets
 	cfg.BucketProviders = make([]*BucketProvider, len(infraCfg.ObjectStorage))
 	for i, storage := range infraCfg.ObjectStorage {
-		switch storage.Type {
-		case "gcs":
-			cfg.BucketProviders[i] = &BucketProvider{
-				GCS: &GCSBucketProvider{
-					Endpoint: storage.GCS.Endpoint,
-				},
-			}
-		case "s3":
-			cfg.BucketProviders[i] = &BucketProvider{
-				S3: &S3BucketProvider{
-					Region:          storage.S3.Region,
-					Endpoint:        nilOr(storage.S3.Endpoint),
-					AccessKeyID:     nilOr(storage.S3.AccessKeyID),
-					SecretAccessKey: nilOr(storage.S3.SecretAccessKey.Value()),
-				},
-			}
-		}
+		cfg.BucketProviders[i] = mapBucketProvider(storage)
 		cfg.Buckets = map[string]*Bucket{}
 		for bucketName, bucket := range storage.GetBuckets() {
 			cfg.Buckets[bucketName] = &Bucket{
@@ -409,6 +400,36 @@ func parseInfraConfigEnv(infraCfgPath string) *Runtime {
 				CloudName:     bucket.Name,
 				KeyPrefix:     bucket.KeyPrefix,
 				PublicBaseURL: bucket.PublicBaseURL,
+				UploadQuota:   bucket.UploadQuota,
+				KMSKeys:       bucket.KMSKeys,
+			}
+
+			if rp := bucket.RequesterPays; rp != nil {
+				cfg.Buckets[bucketName].RequesterPays = &BucketRequesterPays{
+					BillingProject: rp.BillingProject,
+				}
+			}
+
+			if sd := bucket.SoftDelete; sd != nil {
+				cfg.Buckets[bucketName].SoftDelete = &BucketSoftDelete{
+					TrashPrefix: sd.TrashPrefix,
+				}
+			}
+
+			if enc := bucket.Encryption; enc != nil {
+				cfg.Buckets[bucketName].Encryption = &BucketEncryption{
+					KeyID:     enc.KeyID,
+					MasterKey: enc.MasterKey.Value(),
+				}
+			}
+
+			// Failover replicas get their own provider entry.
+			if fo := bucket.Failover; fo != nil && fo.Provider != nil {
+				cfg.BucketProviders = append(cfg.BucketProviders, mapBucketProvider(fo.Provider))
+				cfg.Buckets[bucketName].Failover = &BucketFailover{
+					ProviderID: len(cfg.BucketProviders) - 1,
+					CloudName:  fo.Name,
+				}
 			}
 		}
 	}
@@ -435,6 +456,40 @@ func parseInfraConfigEnv(infraCfgPath string) *Runtime {
 	return &cfg
 }
 
+func mapBucketProvider(storage *infra.ObjectStorage) *BucketProvider {
+	switch storage.Type {
+	case "gcs":
+		return &BucketProvider{
+			GCS: &GCSBucketProvider{
+				Endpoint: storage.GCS.Endpoint,
+			},
+		}
+	case "s3":
+		return &BucketProvider{
+			S3: &S3BucketProvider{
+				Region:          storage.S3.Region,
+				Endpoint:        nilOr(storage.S3.Endpoint),
+				PathStyle:       storage.S3.PathStyle,
+				AccessKeyID:     nilOr(storage.S3.AccessKeyID),
+				SecretAccessKey: nilOr(storage.S3.SecretAccessKey.Value()),
+				AssumeRole:      mapS3AssumeRole(storage.S3.AssumeRole),
+			},
+		}
+	}
+	return nil
+}
+
+func mapS3AssumeRole(role *infra.S3AssumeRole) *S3AssumeRole {
+	if role == nil {
+		return nil
+	}
+	return &S3AssumeRole{
+		RoleARN:     role.RoleARN,
+		ExternalID:  role.ExternalID,
+		SessionName: role.SessionName,
+	}
+}
+
 func nilOr[T comparable](val T) *T {
 	var zero T
 	if val == zero {
@@ -444,6 +499,19 @@ func nilOr[T comparable](val T) *T {
 
 }
 
+// mapSQLServer maps the infra config for a SQL server to the runtime config.
+func mapSQLServer(host string, tlsConfig *infra.TLSConfig) *SQLServer {
+	srv := &SQLServer{Host: host}
+	if tlsConfig != nil && !tlsConfig.Disabled {
+		srv.ServerCACert = tlsConfig.CA
+		if tlsConfig.ClientCert != nil {
+			srv.ClientCert = tlsConfig.ClientCert.Cert
+			srv.ClientKey = tlsConfig.ClientCert.Key.Value()
+		}
+	}
+	return srv
+}
+
 func orDefaultPtr[T any](val *T, def T) T {
 	if val == nil {
 		return def
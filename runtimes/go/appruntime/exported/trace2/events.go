@@ -489,6 +489,8 @@ type DBQueryStartParams struct {
 	TxStartID EventID // zero if not in a transaction
 	Stack     stack.Stack
 	Query     string
+	Replica   *string // the read replica the query was routed to, if any
+	Name      *string // the name of the statement, if any
 }
 
 func (l *Log) DBQueryStart(p DBQueryStartParams) EventID {
@@ -500,6 +502,8 @@ func (l *Log) DBQueryStart(p DBQueryStartParams) EventID {
 
 	tb.String(p.Query)
 	tb.Stack(p.Stack)
+	tb.OptString(p.Replica)
+	tb.OptString(p.Name)
 
 	return l.Add(Event{
 		Type:    DBQueryStart,
@@ -510,12 +514,23 @@ func (l *Log) DBQueryStart(p DBQueryStartParams) EventID {
 }
 
 func (l *Log) DBQueryEnd(p EventParams, startID EventID, err error) {
+	l.dbQueryEnd(p, startID, nil, err)
+}
+
+// DBQueryEndWithRows is like DBQueryEnd for queries that
+// record the number of rows they processed.
+func (l *Log) DBQueryEndWithRows(p EventParams, startID EventID, rows uint64, err error) {
+	l.dbQueryEnd(p, startID, &rows, err)
+}
+
+func (l *Log) dbQueryEnd(p EventParams, startID EventID, rows *uint64, err error) {
 	tb := l.newEvent(eventData{
 		Common:             p,
 		ExtraSpace:         64,
 		CorrelationEventID: startID,
 	})
 	tb.ErrWithStack(err)
+	tb.OptUVarint(rows)
 	l.Add(Event{
 		Type:    DBQueryEnd,
 		TraceID: p.TraceID,
@@ -660,6 +675,7 @@ type CacheCallStartParams struct {
 	IsWrite   bool
 	Keys      []string
 	Stack     stack.Stack
+	BatchSize *uint64 // the number of keys or entries of a batch operation, if any
 }
 
 func (l *Log) CacheCallStart(p CacheCallStartParams) EventID {
@@ -676,6 +692,7 @@ func (l *Log) CacheCallStart(p CacheCallStartParams) EventID {
 	for _, k := range p.Keys {
 		tb.String(k)
 	}
+	tb.OptUVarint(p.BatchSize)
 
 	return l.Add(Event{
 		Type:    CacheCallStart,
@@ -763,6 +780,7 @@ type BucketObjectUploadStartParams struct {
 	Object string
 	Attrs  BucketObjectAttributes
 	Stack  stack.Stack
+	KMSKey *string // the reference of the KMS key the object is encrypted with, if any
 }
 
 type BucketObjectAttributes struct {
@@ -782,6 +800,7 @@ func (l *Log) BucketObjectUploadStart(p BucketObjectUploadStartParams) EventID {
 	tb.String(p.Object)
 	tb.bucketObjectAttrs(&p.Attrs)
 	tb.Stack(p.Stack)
+	tb.OptString(p.KMSKey)
 
 	return l.Add(Event{
 		Type:    BucketObjectUploadStart,
@@ -804,8 +823,9 @@ type BucketObjectUploadEndParams struct {
 
 	Err error
 	// Set iff err == nil
-	Size    uint64
-	Version *string
+	Size     uint64
+	Version  *string
+	Checksum *string // like "crc32c:yZRlqg=="
 }
 
 func (l *Log) BucketObjectUploadEnd(p BucketObjectUploadEndParams) {
@@ -818,6 +838,7 @@ func (l *Log) BucketObjectUploadEnd(p BucketObjectUploadEndParams) {
 	tb.UVarint(p.Size)
 	tb.OptString(p.Version)
 	tb.ErrWithStack(p.Err)
+	tb.OptString(p.Checksum)
 
 	l.Add(Event{
 		Type:    BucketObjectUploadEnd,
@@ -860,7 +881,8 @@ type BucketObjectDownloadEndParams struct {
 
 	Err error
 	// Set iff err == nil
-	Size uint64
+	Size     uint64
+	Checksum *string // set if the object was read in full
 }
 
 func (l *Log) BucketObjectDownloadEnd(p BucketObjectDownloadEndParams) {
@@ -872,6 +894,7 @@ func (l *Log) BucketObjectDownloadEnd(p BucketObjectDownloadEndParams) {
 
 	tb.UVarint(p.Size)
 	tb.ErrWithStack(p.Err)
+	tb.OptString(p.Checksum)
 
 	l.Add(Event{
 		Type:    BucketObjectDownloadEnd,
@@ -32,6 +32,7 @@ type Logger interface {
 	RPCCallEnd(call *model.APICall, goid uint32, err error)
 	DBQueryStart(p DBQueryStartParams) EventID
 	DBQueryEnd(EventParams, EventID, error)
+	DBQueryEndWithRows(p EventParams, startID EventID, rows uint64, err error)
 	DBTransactionStart(EventParams, stack.Stack) EventID
 	DBTransactionEnd(DBTransactionEndParams)
 	PubsubPublishStart(PubsubPublishStartParams) EventID
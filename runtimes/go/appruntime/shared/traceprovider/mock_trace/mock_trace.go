@@ -257,6 +257,18 @@ func (mr *MockLoggerMockRecorder) DBQueryEnd(arg0, arg1, arg2 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DBQueryEnd", reflect.TypeOf((*MockLogger)(nil).DBQueryEnd), arg0, arg1, arg2)
 }
 
+// DBQueryEndWithRows mocks base method.
+func (m *MockLogger) DBQueryEndWithRows(arg0 trace2.EventParams, arg1 trace2.EventID, arg2 uint64, arg3 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DBQueryEndWithRows", arg0, arg1, arg2, arg3)
+}
+
+// DBQueryEndWithRows indicates an expected call of DBQueryEndWithRows.
+func (mr *MockLoggerMockRecorder) DBQueryEndWithRows(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DBQueryEndWithRows", reflect.TypeOf((*MockLogger)(nil).DBQueryEndWithRows), arg0, arg1, arg2, arg3)
+}
+
 // DBQueryStart mocks base method.
 func (m *MockLogger) DBQueryStart(p trace2.DBQueryStartParams) trace2.EventID {
 	m.ctrl.T.Helper()
@@ -0,0 +1,111 @@
+package pubsub
+
+import (
+	"context"
+	"time"
+
+	"encore.dev/beta/errs"
+	"encore.dev/pubsub/internal/types"
+	"encore.dev/pubsub/internal/utils"
+)
+
+// DeadLetterQueue gives access to the messages a subscription dead-lettered
+// after they ran out of retries, as configured by the subscription's RetryPolicy.
+//
+// It lets you inspect poison messages, replay them to the subscription once
+// the underlying issue has been fixed, or purge them altogether.
+//
+// Dead-letter queues are supported when running locally, and on AWS and GCP using
+// the dead-letter queue or topic configured for the subscription by the provider.
+// Other providers return an error with code errs.Unimplemented.
+type DeadLetterQueue[T any] struct {
+	sub *Subscription[T]
+}
+
+// DeadLetter is a message that was dead-lettered by a subscription.
+type DeadLetter[T any] struct {
+	// ID is the unique ID of the message, as returned by Publish.
+	ID string
+
+	// PublishTime is when the message was published.
+	PublishTime time.Time
+
+	// DeliveryAttempt is the delivery attempt on which
+	// the message was dead-lettered.
+	DeliveryAttempt int
+
+	// Message is the dead-lettered message.
+	Message T
+}
+
+// DeadLetterQueue returns the subscription's dead-letter queue.
+func (s *Subscription[T]) DeadLetterQueue() *DeadLetterQueue[T] {
+	return &DeadLetterQueue[T]{sub: s}
+}
+
+// List returns the messages in the dead-letter queue, oldest first.
+func (q *DeadLetterQueue[T]) List(ctx context.Context) ([]*DeadLetter[T], error) {
+	dlq, err := q.impl()
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := dlq.List(ctx)
+	if err != nil {
+		return nil, q.wrapErr(err, "list")
+	}
+
+	list := make([]*DeadLetter[T], 0, len(raw))
+	for _, dl := range raw {
+		msg, err := utils.UnmarshalMessage[T](dl.Attrs, dl.Data)
+		if err != nil {
+			return nil, errs.B().Cause(err).Code(errs.Internal).Msgf("failed to unmarshal dead-lettered message %s", dl.ID).Err()
+		}
+		list = append(list, &DeadLetter[T]{
+			ID:              dl.ID,
+			PublishTime:     dl.PublishTime,
+			DeliveryAttempt: dl.DeliveryAttempt,
+			Message:         msg,
+		})
+	}
+	return list, nil
+}
+
+// Replay redelivers the dead-lettered messages with the given ids to the subscription,
+// removing them from the dead-letter queue. If no ids are given, all messages are replayed.
+//
+// Replayed messages are only delivered to this subscription, and not to other
+// subscriptions to the topic. It returns the number of messages replayed.
+func (q *DeadLetterQueue[T]) Replay(ctx context.Context, ids ...string) (replayed int, err error) {
+	dlq, err := q.impl()
+	if err != nil {
+		return 0, err
+	}
+	replayed, err = dlq.Replay(ctx, ids)
+	return replayed, q.wrapErr(err, "replay")
+}
+
+// Purge permanently removes all messages from the dead-letter queue.
+// It returns the number of messages removed.
+func (q *DeadLetterQueue[T]) Purge(ctx context.Context) (purged int, err error) {
+	dlq, err := q.impl()
+	if err != nil {
+		return 0, err
+	}
+	purged, err = dlq.Purge(ctx)
+	return purged, q.wrapErr(err, "purge")
+}
+
+func (q *DeadLetterQueue[T]) impl() (types.DeadLetterQueue, error) {
+	if q.sub.dlq == nil {
+		return nil, errs.B().Code(errs.Unimplemented).Msgf("dead-letter queues are not supported for subscription %s", q.sub.name).Err()
+	}
+	return q.sub.dlq, nil
+}
+
+func (q *DeadLetterQueue[T]) wrapErr(err error, op string) error {
+	if err == nil {
+		return nil
+	}
+	return errs.B().Cause(err).Code(errs.Unavailable).Msgf("failed to %s dead-letter queue for subscription %s", op, q.sub.name).Err()
+}
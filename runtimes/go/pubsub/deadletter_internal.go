@@ -0,0 +1,115 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"encore.dev/beta/errs"
+	"encore.dev/internal/platformauth"
+	"encore.dev/pubsub/internal/types"
+)
+
+func (mgr *Manager) registerDeadLetterQueue(topic, subscription string, dlq types.DeadLetterQueue) {
+	mgr.subsMu.Lock()
+	defer mgr.subsMu.Unlock()
+	mgr.deadLetters[subscriptionKey{topic, subscription}] = dlq
+}
+
+// DeadLetterOp is an operation on a subscription's dead-letter queue.
+type DeadLetterOp string
+
+const (
+	DeadLetterList   DeadLetterOp = "list"
+	DeadLetterReplay DeadLetterOp = "replay"
+	DeadLetterPurge  DeadLetterOp = "purge"
+)
+
+// deadLetterJSON is a dead-lettered message, as returned by HandleDeadLetterQueue.
+type deadLetterJSON struct {
+	ID              string            `json:"id"`
+	PublishTime     time.Time         `json:"publish_time"`
+	DeliveryAttempt int               `json:"delivery_attempt"`
+	Attributes      map[string]string `json:"attributes,omitempty"`
+	Data            json.RawMessage   `json:"data"`
+}
+
+// HandleDeadLetterQueue is an HTTP handler that performs op on the dead-letter queue
+// of the given subscription, for managing it from the Encore platform and CLI.
+//
+// Listing responds with {"messages": [...]}. Replaying accepts an optional
+// {"ids": [...]} body, and both replaying and purging respond with {"count": n}.
+//
+// This is an internal API for Encore and should not be called directly.
+func (mgr *Manager) HandleDeadLetterQueue(w http.ResponseWriter, req *http.Request, topic, subscription string, op DeadLetterOp) {
+	if !platformauth.IsEncorePlatformRequest(req.Context()) {
+		errs.HTTPErrorWithCode(w, errs.B().Code(errs.PermissionDenied).Msg("permission denied").Err(), 0)
+		return
+	}
+
+	mgr.subsMu.Lock()
+	dlq, found := mgr.deadLetters[subscriptionKey{topic, subscription}]
+	mgr.subsMu.Unlock()
+	if !found {
+		errs.HTTPError(w, errs.B().Code(errs.NotFound).Msgf("no dead-letter queue for subscription %s on topic %s", subscription, topic).Err())
+		return
+	}
+
+	var resp any
+	switch op {
+	case DeadLetterList:
+		list, err := dlq.List(req.Context())
+		if err != nil {
+			errs.HTTPError(w, err)
+			return
+		}
+		msgs := make([]deadLetterJSON, len(list))
+		for i, dl := range list {
+			msgs[i] = deadLetterJSON{
+				ID:              dl.ID,
+				PublishTime:     dl.PublishTime,
+				DeliveryAttempt: dl.DeliveryAttempt,
+				Attributes:      dl.Attrs,
+				Data:            dl.Data,
+			}
+		}
+		resp = map[string]any{"messages": msgs}
+
+	case DeadLetterReplay:
+		var params struct {
+			IDs []string `json:"ids"`
+		}
+		if req.ContentLength != 0 {
+			if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+				errs.HTTPError(w, errs.B().Cause(err).Code(errs.InvalidArgument).Msg("invalid request body").Err())
+				return
+			}
+		}
+		n, err := dlq.Replay(req.Context(), params.IDs)
+		if err != nil {
+			errs.HTTPError(w, err)
+			return
+		}
+		resp = map[string]any{"count": n}
+
+	case DeadLetterPurge:
+		n, err := dlq.Purge(req.Context())
+		if err != nil {
+			errs.HTTPError(w, err)
+			return
+		}
+		resp = map[string]any{"count": n}
+
+	default:
+		errs.HTTPError(w, errs.B().Code(errs.InvalidArgument).Msgf("unknown dead-letter queue operation %q", op).Err())
+		return
+	}
+
+	data, err := mgr.json.Marshal(resp)
+	if err != nil {
+		errs.HTTPError(w, errs.B().Cause(err).Code(errs.Internal).Msg("failed to marshal response").Err())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,90 @@
+package pubsub
+
+import (
+	"sync"
+
+	"encore.dev/beta/errs"
+	"encore.dev/pubsub/internal/utils"
+)
+
+// FlowControl controls how many messages a subscription
+// processes at a time, per instance of the service.
+type FlowControl struct {
+	// MaxConcurrency is the maximum number of messages processed
+	// concurrently. If negative there is no limit, other than
+	// the one imposed by Prefetch.
+	MaxConcurrency int
+
+	// Prefetch is the maximum number of messages received from the
+	// provider ahead of being processed, which also bounds how many
+	// are processed concurrently.
+	//
+	// It is zero until changed, meaning the provider's default based
+	// on the subscription's configured MaxConcurrency.
+	//
+	// Changing it is supported for GCP and when running locally.
+	Prefetch int
+}
+
+// subscriptionFlow is the flow control state of a running subscription.
+type subscriptionFlow struct {
+	limiter     *utils.Limiter
+	setPrefetch func(prefetch int) error // nil if the provider doesn't support it
+
+	mu       sync.Mutex
+	prefetch int
+}
+
+func (f *subscriptionFlow) get() FlowControl {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return FlowControl{MaxConcurrency: f.limiter.Limit(), Prefetch: f.prefetch}
+}
+
+func (f *subscriptionFlow) set(fc FlowControl) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if fc.Prefetch < 0 {
+		return errs.B().Code(errs.InvalidArgument).Msg("prefetch cannot be negative").Err()
+	} else if fc.Prefetch > 0 && fc.Prefetch != f.prefetch {
+		if f.setPrefetch == nil {
+			return errs.B().Code(errs.Unimplemented).Msg("changing the prefetch is not supported by the provider").Err()
+		}
+		if err := f.setPrefetch(fc.Prefetch); err != nil {
+			return err
+		}
+		f.prefetch = fc.Prefetch
+	}
+
+	if fc.MaxConcurrency != 0 {
+		f.limiter.SetLimit(fc.MaxConcurrency)
+	}
+	return nil
+}
+
+// FlowControl returns the current flow control of the subscription
+// on this instance of the service.
+func (s *Subscription[T]) FlowControl() FlowControl {
+	if s.flow == nil {
+		return FlowControl{}
+	}
+	return s.flow.get()
+}
+
+// SetFlowControl changes the flow control of the subscription on this instance of
+// the service while it's running, for example to throttle it during an incident.
+// Fields that are zero are left unchanged.
+//
+// The change lasts until the service restarts, after which the subscription's
+// configuration applies again.
+//
+// Lowering MaxConcurrency doesn't affect messages already being processed.
+// Messages waiting for longer than the subscription's AckDeadline to be
+// processed are retried, so consider lowering Prefetch as well.
+func (s *Subscription[T]) SetFlowControl(fc FlowControl) error {
+	if s.flow == nil {
+		return errs.B().Code(errs.FailedPrecondition).Msgf("subscription %s is not running on this instance", s.name).Err()
+	}
+	return s.flow.set(fc)
+}
@@ -0,0 +1,64 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"encore.dev/beta/errs"
+	"encore.dev/internal/platformauth"
+)
+
+func (mgr *Manager) registerFlow(topic, subscription string, flow *subscriptionFlow) {
+	mgr.subsMu.Lock()
+	defer mgr.subsMu.Unlock()
+	mgr.flows[subscriptionKey{topic, subscription}] = flow
+}
+
+// flowControlJSON is the flow control of a subscription, as used by HandleFlowControl.
+type flowControlJSON struct {
+	MaxConcurrency int `json:"max_concurrency"`
+	Prefetch       int `json:"prefetch"`
+}
+
+// HandleFlowControl is an HTTP handler that reports the flow control of the given subscription
+// on this instance, or changes it for PUT requests, for throttling it from the Encore platform.
+//
+// Both respond with {"max_concurrency": n, "prefetch": n}, and PUT requests
+// accept the same body. Fields that are zero or omitted are left unchanged.
+//
+// This is an internal API for Encore and should not be called directly.
+func (mgr *Manager) HandleFlowControl(w http.ResponseWriter, req *http.Request, topic, subscription string) {
+	if !platformauth.IsEncorePlatformRequest(req.Context()) {
+		errs.HTTPErrorWithCode(w, errs.B().Code(errs.PermissionDenied).Msg("permission denied").Err(), 0)
+		return
+	}
+
+	mgr.subsMu.Lock()
+	flow, found := mgr.flows[subscriptionKey{topic, subscription}]
+	mgr.subsMu.Unlock()
+	if !found {
+		errs.HTTPError(w, errs.B().Code(errs.NotFound).Msgf("subscription %s on topic %s is not running on this instance", subscription, topic).Err())
+		return
+	}
+
+	if req.Method == http.MethodPut {
+		var params flowControlJSON
+		if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+			errs.HTTPError(w, errs.B().Cause(err).Code(errs.InvalidArgument).Msg("invalid request body").Err())
+			return
+		}
+		if err := flow.set(FlowControl{MaxConcurrency: params.MaxConcurrency, Prefetch: params.Prefetch}); err != nil {
+			errs.HTTPError(w, err)
+			return
+		}
+	}
+
+	fc := flow.get()
+	data, err := mgr.json.Marshal(flowControlJSON{MaxConcurrency: fc.MaxConcurrency, Prefetch: fc.Prefetch})
+	if err != nil {
+		errs.HTTPError(w, errs.B().Cause(err).Code(errs.Internal).Msg("failed to marshal response").Err())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,112 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+
+	"encore.dev/appruntime/exported/config"
+	"encore.dev/pubsub/internal/types"
+)
+
+// newBatchTestTopic returns a topic publishing to a fake SNS server
+// that fails the message with index 3 and succeeds all others.
+// It records the number of messages in each PublishBatch call in batches.
+func newBatchTestTopic(t *testing.T, batches *[]int) *topic {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if got := r.Form.Get("Action"); got != "PublishBatch" {
+			t.Fatalf("got action %q, want PublishBatch", got)
+		}
+		var ok, failed strings.Builder
+		n := 1
+		for ; ; n++ {
+			id := r.Form.Get(fmt.Sprintf("PublishBatchRequestEntries.member.%d.Id", n))
+			if id == "" {
+				break
+			}
+			if id == "3" {
+				fmt.Fprintf(&failed, "<member><Id>%s</Id><Code>InvalidParameter</Code><Message>bad message</Message><SenderFault>true</SenderFault></member>", id)
+			} else {
+				fmt.Fprintf(&ok, "<member><Id>%s</Id><MessageId>msg-%s</MessageId></member>", id, id)
+			}
+		}
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprintf(w, `<PublishBatchResponse xmlns="http://sns.amazonaws.com/doc/2010-03-31/">
+<PublishBatchResult><Successful>%s</Successful><Failed>%s</Failed></PublishBatchResult>
+</PublishBatchResponse>`, ok.String(), failed.String())
+		*batches = append(*batches, n-1)
+	}))
+	t.Cleanup(srv.Close)
+
+	return &topic{
+		snsClient: sns.New(sns.Options{
+			BaseEndpoint: aws.String(srv.URL),
+			Region:       "us-east-1",
+			Credentials:  aws.AnonymousCredentials{},
+		}),
+		staticCfg:  types.TopicConfig{DeliveryGuarantee: types.AtLeastOnce},
+		runtimeCfg: &config.PubsubTopic{ProviderName: testTopicARN},
+	}
+}
+
+func TestPublishBatch(t *testing.T) {
+	var batches []int
+	tp := newBatchTestTopic(t, &batches)
+
+	msgs := make([]types.BatchMessage, 12)
+	for i := range msgs {
+		msgs[i] = types.BatchMessage{Attrs: map[string]string{"n": strconv.Itoa(i)}, Data: []byte(`{}`)}
+	}
+	results := tp.PublishBatch(context.Background(), msgs)
+
+	if want := []int{10, 2}; !slices.Equal(batches, want) {
+		t.Errorf("got batches of %v messages, want %v", batches, want)
+	}
+	if len(results) != len(msgs) {
+		t.Fatalf("got %d results, want %d", len(results), len(msgs))
+	}
+	for i, res := range results {
+		if i == 3 {
+			if res.Err == nil || !strings.Contains(res.Err.Error(), "bad message") {
+				t.Errorf("result %d: got err %v, want failure", i, res.Err)
+			}
+			continue
+		}
+		if res.Err != nil || res.ID != fmt.Sprintf("msg-%d", i) {
+			t.Errorf("result %d: got (%q, %v), want msg-%d", i, res.ID, res.Err, i)
+		}
+	}
+}
+
+func TestPublishBatch_PayloadSize(t *testing.T) {
+	var batches []int
+	tp := newBatchTestTopic(t, &batches)
+
+	// SNS accepts at most 256 KiB per PublishBatch call, so only two of these fit at once.
+	data := []byte(strings.Repeat("x", 100*1024))
+	msgs := make([]types.BatchMessage, 5)
+	for i := range msgs {
+		msgs[i] = types.BatchMessage{Attrs: map[string]string{"n": strconv.Itoa(i)}, Data: data}
+	}
+	results := tp.PublishBatch(context.Background(), msgs)
+
+	if want := []int{2, 2, 1}; !slices.Equal(batches, want) {
+		t.Errorf("got batches of %v messages, want %v", batches, want)
+	}
+	for i, res := range results {
+		if i != 3 && (res.Err != nil || res.ID != fmt.Sprintf("msg-%d", i)) {
+			t.Errorf("result %d: got (%q, %v), want msg-%d", i, res.ID, res.Err, i)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqsTypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/rs/xid"
+
+	"encore.dev/appruntime/exported/config"
+	"encore.dev/beta/errs"
+	"encore.dev/pubsub/internal/types"
+)
+
+// deadLetterVisibility is how long messages read from a dead-letter queue
+// are hidden for while they're being read, in seconds.
+const deadLetterVisibility = 60
+
+var _ types.DeadLetterer = (*topic)(nil)
+
+func (t *topic) DeadLetterQueue(subCfg *config.PubsubSubscription) types.DeadLetterQueue {
+	return &deadLetterQueue{topic: t, queueURL: subCfg.ProviderName}
+}
+
+// deadLetterQueue gives access to the messages SQS moved to the dead-letter
+// queue of a subscription's queue, as configured by the queue's redrive policy.
+//
+// SQS can't peek at messages, so they're received until no more arrive,
+// and the ones being kept are made visible again.
+type deadLetterQueue struct {
+	topic    *topic
+	queueURL string // the subscription's queue
+}
+
+func (q *deadLetterQueue) List(ctx context.Context) ([]*types.DeadLetter, error) {
+	var list []*types.DeadLetter
+	err := q.read(ctx, func(dl *types.DeadLetter, _ sqsTypes.Message) bool {
+		list = append(list, dl)
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(list, func(a, b *types.DeadLetter) int {
+		return a.PublishTime.Compare(b.PublishTime)
+	})
+	return list, nil
+}
+
+// Replay sends the messages back to the subscription's queue,
+// so unlike publishing them again they're only delivered to this subscription.
+func (q *deadLetterQueue) Replay(ctx context.Context, ids []string) (int, error) {
+	fifo := strings.HasSuffix(q.queueURL, ".fifo")
+
+	var (
+		replayed int
+		firstErr error
+	)
+	err := q.read(ctx, func(dl *types.DeadLetter, msg sqsTypes.Message) bool {
+		if len(ids) > 0 && !slices.Contains(ids, dl.ID) {
+			return false
+		}
+
+		input := &sqs.SendMessageInput{
+			QueueUrl:    aws.String(q.queueURL),
+			MessageBody: msg.Body,
+		}
+		if fifo {
+			input.MessageGroupId = aws.String(msg.Attributes[string(sqsTypes.MessageSystemAttributeNameMessageGroupId)])
+			input.MessageDeduplicationId = aws.String(fmt.Sprintf("replay_%s", xid.New().String()))
+		}
+		if _, err := q.topic.sqsClient.SendMessage(ctx, input); err != nil {
+			if firstErr == nil {
+				firstErr = errs.B().Cause(err).Code(errs.Internal).Msgf("failed to replay message %s", dl.ID).Err()
+			}
+			return false
+		}
+		replayed++
+		return true
+	})
+	if err == nil {
+		err = firstErr
+	}
+	return replayed, err
+}
+
+func (q *deadLetterQueue) Purge(ctx context.Context) (int, error) {
+	var purged int
+	err := q.read(ctx, func(*types.DeadLetter, sqsTypes.Message) bool {
+		purged++
+		return true
+	})
+	return purged, err
+}
+
+// read calls fn for each message in the dead-letter queue, deleting
+// the messages it returns true for and making the others visible again.
+func (q *deadLetterQueue) read(ctx context.Context, fn func(dl *types.DeadLetter, msg sqsTypes.Message) (remove bool)) error {
+	dlqURL, maxReceiveCount, err := q.deadLetterQueueURL(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Receive messages until none arrive.
+	var (
+		msgs []sqsTypes.Message
+		seen = make(map[string]bool)
+	)
+	for {
+		resp, err := q.topic.sqsClient.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(dlqURL),
+			AttributeNames:      []sqsTypes.QueueAttributeName{sqsTypes.QueueAttributeName(sqsTypes.MessageSystemAttributeNameMessageGroupId)},
+			MaxNumberOfMessages: 10,                   // Maximum allowed
+			VisibilityTimeout:   deadLetterVisibility, // Hide them until we're done
+			WaitTimeSeconds:     1,                    // Long poll to query all SQS servers
+		})
+		if err != nil {
+			q.release(dlqURL, msgs)
+			return errs.B().Cause(err).Code(errs.Unavailable).Msg("failed to receive dead-lettered messages").Err()
+		}
+		if len(resp.Messages) == 0 {
+			break
+		}
+		for _, m := range resp.Messages {
+			id := aws.ToString(m.MessageId)
+			if seen[id] {
+				q.release(dlqURL, []sqsTypes.Message{m})
+				continue
+			}
+			seen[id] = true
+			msgs = append(msgs, m)
+		}
+	}
+
+	var keep []sqsTypes.Message
+	for i, m := range msgs {
+		dl, ok := toDeadLetter(m, maxReceiveCount)
+		if !ok || !fn(dl, m) {
+			keep = append(keep, m)
+			continue
+		}
+		_, err := q.topic.sqsClient.DeleteMessage(q.topic.ctxs.Connection, &sqs.DeleteMessageInput{
+			QueueUrl:      aws.String(dlqURL),
+			ReceiptHandle: m.ReceiptHandle,
+		})
+		if err != nil {
+			q.release(dlqURL, append(keep, msgs[i+1:]...))
+			return errs.B().Cause(err).Code(errs.Unavailable).Msgf("failed to remove dead-lettered message %s", dl.ID).Err()
+		}
+	}
+	q.release(dlqURL, keep)
+	return nil
+}
+
+// release makes msgs visible again in the dead-letter queue.
+func (q *deadLetterQueue) release(dlqURL string, msgs []sqsTypes.Message) {
+	for _, m := range msgs {
+		_, _ = q.topic.sqsClient.ChangeMessageVisibility(q.topic.ctxs.Connection, &sqs.ChangeMessageVisibilityInput{
+			QueueUrl:          aws.String(dlqURL),
+			ReceiptHandle:     m.ReceiptHandle,
+			VisibilityTimeout: 0,
+		})
+	}
+}
+
+// deadLetterQueueURL returns the URL of the dead-letter queue of the subscription's
+// queue, and after how many receives messages are moved to it, from its redrive policy.
+func (q *deadLetterQueue) deadLetterQueueURL(ctx context.Context) (url string, maxReceiveCount int, err error) {
+	attrs, err := q.topic.sqsClient.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(q.queueURL),
+		AttributeNames: []sqsTypes.QueueAttributeName{sqsTypes.QueueAttributeNameRedrivePolicy},
+	})
+	if err != nil {
+		return "", 0, errs.B().Cause(err).Code(errs.Unavailable).Msg("failed to get the redrive policy of the subscription's queue").Err()
+	}
+	policy, ok := attrs.Attributes[string(sqsTypes.QueueAttributeNameRedrivePolicy)]
+	if !ok {
+		return "", 0, errs.B().Code(errs.FailedPrecondition).Msgf("queue %s has no redrive policy", q.queueURL).Err()
+	}
+	queueARN, maxReceiveCount, err := parseRedrivePolicy(policy)
+	if err != nil {
+		return "", 0, errs.B().Cause(err).Code(errs.Internal).Msg("invalid redrive policy").Err()
+	}
+
+	resp, err := q.topic.sqsClient.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{
+		QueueName:              aws.String(queueARN.Resource),
+		QueueOwnerAWSAccountId: aws.String(queueARN.AccountID),
+	})
+	if err != nil {
+		return "", 0, errs.B().Cause(err).Code(errs.Unavailable).Msg("failed to get the URL of the dead-letter queue").Err()
+	}
+	return aws.ToString(resp.QueueUrl), maxReceiveCount, nil
+}
+
+// parseRedrivePolicy parses the RedrivePolicy attribute of an SQS queue.
+func parseRedrivePolicy(policy string) (deadLetterTarget arn.ARN, maxReceiveCount int, err error) {
+	var p struct {
+		DeadLetterTargetArn string          `json:"deadLetterTargetArn"`
+		MaxReceiveCount     json.RawMessage `json:"maxReceiveCount"` // a number or a string
+	}
+	if err := json.Unmarshal([]byte(policy), &p); err != nil {
+		return arn.ARN{}, 0, err
+	}
+	deadLetterTarget, err = arn.Parse(p.DeadLetterTargetArn)
+	if err != nil {
+		return arn.ARN{}, 0, err
+	}
+	maxReceiveCount, err = strconv.Atoi(strings.Trim(string(p.MaxReceiveCount), `"`))
+	if err != nil {
+		return arn.ARN{}, 0, fmt.Errorf("invalid maxReceiveCount: %v", err)
+	}
+	return deadLetterTarget, maxReceiveCount, nil
+}
+
+// toDeadLetter converts a message received from a dead-letter queue,
+// reporting false if it's not a message published through SNS.
+//
+// SQS moves messages to the dead-letter queue once they've been received
+// maxReceiveCount times, so that's the delivery attempt they were dead-lettered on.
+func toDeadLetter(msg sqsTypes.Message, maxReceiveCount int) (*types.DeadLetter, bool) {
+	wrapper := &SNSMessageWrapper{}
+	if err := json.Unmarshal([]byte(aws.ToString(msg.Body)), wrapper); err != nil || wrapper.MessageId == "" {
+		return nil, false
+	}
+	attrs := make(map[string]string, len(wrapper.MessageAttributes))
+	for key, value := range wrapper.MessageAttributes {
+		if value.Type == "String" {
+			attrs[key] = value.Value
+		}
+	}
+	return &types.DeadLetter{
+		ID:              wrapper.MessageId,
+		PublishTime:     wrapper.Timestamp,
+		DeliveryAttempt: maxReceiveCount,
+		Attrs:           attrs,
+		Data:            []byte(wrapper.Message),
+	}, true
+}
@@ -0,0 +1,64 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	sqsTypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+func TestParseRedrivePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		wantMax int
+		wantErr bool
+	}{
+		{name: "number", policy: `{"deadLetterTargetArn":"arn:aws:sqs:us-west-2:123456789012:sub-dlq","maxReceiveCount":5}`, wantMax: 5},
+		{name: "string", policy: `{"deadLetterTargetArn":"arn:aws:sqs:us-west-2:123456789012:sub-dlq","maxReceiveCount":"10"}`, wantMax: 10},
+		{name: "invalid_arn", policy: `{"deadLetterTargetArn":"sub-dlq","maxReceiveCount":5}`, wantErr: true},
+		{name: "missing_count", policy: `{"deadLetterTargetArn":"arn:aws:sqs:us-west-2:123456789012:sub-dlq"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, maxReceiveCount, err := parseRedrivePolicy(tt.policy)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("got nil, want an error")
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if target.Resource != "sub-dlq" || target.AccountID != "123456789012" {
+				t.Errorf("got target %+v, want queue sub-dlq of account 123456789012", target)
+			}
+			if maxReceiveCount != tt.wantMax {
+				t.Errorf("got maxReceiveCount %d, want %d", maxReceiveCount, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestToDeadLetter(t *testing.T) {
+	body := `{"Type":"Notification","MessageId":"msg-1","Message":"{\"a\":1}","Timestamp":"2024-01-02T03:04:05Z",` +
+		`"MessageAttributes":{"attr":{"Type":"String","Value":"x"},"bin":{"Type":"Binary","Value":"eA=="}}}`
+	dl, ok := toDeadLetter(sqsTypes.Message{Body: aws.String(body)}, 5)
+	if !ok {
+		t.Fatal("got false, want the message converted")
+	}
+	if dl.ID != "msg-1" || string(dl.Data) != `{"a":1}` || dl.DeliveryAttempt != 5 {
+		t.Errorf("got %+v, want message msg-1 dead-lettered on attempt 5", dl)
+	}
+	if !dl.PublishTime.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("got publish time %v", dl.PublishTime)
+	}
+	if len(dl.Attrs) != 1 || dl.Attrs["attr"] != "x" {
+		t.Errorf("got attrs %v, want only the string attribute", dl.Attrs)
+	}
+
+	if _, ok := toDeadLetter(sqsTypes.Message{Body: aws.String("not json")}, 5); ok {
+		t.Error("got true for a message not sent through SNS, want false")
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"encore.dev/appruntime/exported/config"
+	"encore.dev/beta/errs"
 	"encore.dev/pubsub/internal/types"
 	"encore.dev/pubsub/internal/utils"
 )
@@ -31,9 +32,121 @@ type topic struct {
 	runtimeCfg  *config.PubsubTopic
 }
 
-var _ types.TopicImplementation = (*topic)(nil)
+var (
+	_ types.TopicImplementation = (*topic)(nil)
+	_ types.DelayedPublisher    = (*topic)(nil)
+	_ types.BatchPublisher      = (*topic)(nil)
+)
 
 func (t *topic) PublishMessage(ctx context.Context, orderingKey string, attrs map[string]string, data []byte) (id string, err error) {
+	params := &sns.PublishInput{
+		Message:           aws.String(string(data)),
+		MessageAttributes: messageAttributes(attrs),
+		TopicArn:          aws.String(t.runtimeCfg.ProviderName),
+	}
+	params.MessageGroupId, params.MessageDeduplicationId = t.messageGroup(orderingKey)
+
+	result, err := t.snsClient.Publish(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(result.MessageId), nil
+}
+
+const (
+	// maxBatchSize is the maximum number of messages SNS accepts in a single PublishBatch call.
+	maxBatchSize = 10
+
+	// maxBatchPayload is the maximum total size in bytes of the messages,
+	// including their attributes, SNS accepts in a single PublishBatch call.
+	maxBatchPayload = 256 * 1024
+)
+
+// PublishBatch publishes the messages using SNS PublishBatch, splitting them
+// into multiple calls if there are more, or they're larger, than SNS allows at once.
+func (t *topic) PublishBatch(ctx context.Context, msgs []types.BatchMessage) []types.BatchResult {
+	results := make([]types.BatchResult, len(msgs))
+	for start, end := 0, 0; start < len(msgs); start = end {
+		end = batchEnd(msgs, start)
+
+		entries := make([]snsTypes.PublishBatchRequestEntry, 0, end-start)
+		for i := start; i < end; i++ {
+			entry := snsTypes.PublishBatchRequestEntry{
+				Id:                aws.String(strconv.Itoa(i)),
+				Message:           aws.String(string(msgs[i].Data)),
+				MessageAttributes: messageAttributes(msgs[i].Attrs),
+			}
+			entry.MessageGroupId, entry.MessageDeduplicationId = t.messageGroup(msgs[i].OrderingKey)
+			entries = append(entries, entry)
+		}
+
+		out, err := t.snsClient.PublishBatch(ctx, &sns.PublishBatchInput{
+			PublishBatchRequestEntries: entries,
+			TopicArn:                   aws.String(t.runtimeCfg.ProviderName),
+		})
+		if err != nil {
+			for i := start; i < end; i++ {
+				results[i].Err = err
+			}
+			continue
+		}
+
+		// Each entry is reported as either successful or failed, identified by its index.
+		for _, e := range out.Successful {
+			if i, ok := batchIndex(e.Id, start, end); ok {
+				results[i].ID = aws.ToString(e.MessageId)
+			}
+		}
+		for _, e := range out.Failed {
+			if i, ok := batchIndex(e.Id, start, end); ok {
+				results[i].Err = fmt.Errorf("%s: %s", aws.ToString(e.Code), aws.ToString(e.Message))
+			}
+		}
+		for i := start; i < end; i++ {
+			if results[i].ID == "" && results[i].Err == nil {
+				results[i].Err = errors.New("message missing from publish batch response")
+			}
+		}
+	}
+	return results
+}
+
+// batchEnd returns the end of the batch of messages starting at start,
+// so that it's within both the message count and payload limits of SNS.
+// A message larger than the payload limit is put in a batch of its own,
+// for SNS to report it as failed.
+func batchEnd(msgs []types.BatchMessage, start int) int {
+	end, size := start, 0
+	for end < len(msgs) && end-start < maxBatchSize {
+		size += messageSize(msgs[end])
+		if size > maxBatchPayload && end > start {
+			break
+		}
+		end++
+	}
+	return end
+}
+
+// messageSize returns the size of the message as counted by SNS
+// towards the payload limit: its data and the names, types and values
+// of its attributes.
+func messageSize(msg types.BatchMessage) int {
+	size := len(msg.Data)
+	for key, value := range msg.Attrs {
+		size += len(key) + len("String") + len(value)
+	}
+	return size
+}
+
+// batchIndex parses the index of the message with the given batch entry id,
+// reporting whether it's a valid index within [start, end).
+func batchIndex(id *string, start, end int) (int, bool) {
+	i, err := strconv.Atoi(aws.ToString(id))
+	return i, err == nil && i >= start && i < end
+}
+
+// messageAttributes converts attrs to SNS message attributes.
+func messageAttributes(attrs map[string]string) map[string]snsTypes.MessageAttributeValue {
 	attributes := make(map[string]snsTypes.MessageAttributeValue)
 	for key, value := range attrs {
 		attributes[key] = snsTypes.MessageAttributeValue{
@@ -41,11 +154,15 @@ func (t *topic) PublishMessage(ctx context.Context, orderingKey string, attrs ma
 			StringValue: aws.String(value),
 		}
 	}
+	return attributes
+}
 
-	params := &sns.PublishInput{
-		Message:           aws.String(string(data)),
-		MessageAttributes: attributes,
-		TopicArn:          aws.String(t.runtimeCfg.ProviderName),
+// messageGroup returns the message group ID and deduplication ID
+// to publish a message with, if any.
+func (t *topic) messageGroup(orderingKey string) (groupID, deduplicationID *string) {
+	// If we have an explicit ordering key, use that as the message group ID and mark the topic as FIFO
+	if orderingKey != "" {
+		return aws.String(orderingKey), aws.String(fmt.Sprintf("msg_%s", xid.New().String()))
 	}
 
 	// For exactly-once delivery on AWS we need to:
@@ -53,21 +170,32 @@ func (t *topic) PublishMessage(ctx context.Context, orderingKey string, attrs ma
 	// 1. Set a message group ID (as this is a requirement for FIFO queues)
 	// 2. Set a message deduplication ID as this is required to enable exactly-once delivery
 	if t.staticCfg.DeliveryGuarantee == types.ExactlyOnce {
-		params.MessageGroupId = aws.String(fmt.Sprintf("inst_%s", t.publisherID.String()))
-		params.MessageDeduplicationId = aws.String(fmt.Sprintf("msg_%s", xid.New().String()))
+		return aws.String(fmt.Sprintf("inst_%s", t.publisherID.String())), aws.String(fmt.Sprintf("msg_%s", xid.New().String()))
 	}
+	return nil, nil
+}
 
-	// If we have an explicit ordering key, use that as the message group ID and mark the topic as FIFO
-	if orderingKey != "" {
-		params.MessageGroupId = aws.String(orderingKey)
-		params.MessageDeduplicationId = aws.String(fmt.Sprintf("msg_%s", xid.New().String()))
-	}
+// maxSQSDelay is the longest delay SQS supports for a single message.
+const maxSQSDelay = 15 * time.Minute
 
-	result, err := t.snsClient.Publish(ctx, params)
-	if err != nil {
-		return "", err
+// PublishDelayedMessage publishes a message with the time it's due as an attribute,
+// as SNS can't delay messages. Subscribers send it back to their SQS queue with
+// a delay until it's due.
+func (t *topic) PublishDelayedMessage(ctx context.Context, delay time.Duration, attrs map[string]string, data []byte) (id string, err error) {
+	// FIFO queues don't support delaying individual messages.
+	if t.staticCfg.DeliveryGuarantee == types.ExactlyOnce {
+		return "", errs.B().Code(errs.InvalidArgument).Msgf("delivery delays are not supported for topic %s with exactly-once delivery on AWS", t.runtimeCfg.EncoreName).Err()
 	}
-	return aws.ToString(result.MessageId), nil
+	utils.SetDeliverAt(attrs, delay)
+	return t.PublishMessage(ctx, "", attrs, data)
+}
+
+var _ types.DeliveryDeduplicator = (*topic)(nil)
+
+// DeduplicatesDelivery reports whether the topic is backed by SNS and SQS FIFO queues,
+// which deduplicate messages for exactly-once delivery.
+func (t *topic) DeduplicatesDelivery(subCfg *config.PubsubSubscription) bool {
+	return t.staticCfg.DeliveryGuarantee == types.ExactlyOnce
 }
 
 func (t *topic) Subscribe(logger *zerolog.Logger, maxConcurrency int, ackDeadline time.Duration, retryPolicy *types.RetryPolicy, implCfg *config.PubsubSubscription, f types.RawSubscriptionCallback) {
@@ -138,6 +266,16 @@ func (t *topic) Subscribe(logger *zerolog.Logger, maxConcurrency int, ackDeadlin
 						}
 					}
 
+					// If the message was published with a delivery delay and isn't due yet,
+					// send it back to the queue delayed until it is (or for as long as SQS allows).
+					// Unlike hiding it with the visibility timeout, this doesn't count as
+					// another receive of the message, which would count towards the queue's
+					// redrive policy and the delivery attempt.
+					if due := time.Until(utils.DeliverAt(attributes)); due > 0 {
+						t.deferMessage(logger, implCfg, msg, msgWrapper.MessageId, due)
+						return nil
+					}
+
 					// Call the callback, and if there was no error, then we can delete the message
 					msgCtx, cancel := context.WithTimeout(ctx, ackDeadline)
 					defer cancel()
@@ -193,6 +331,31 @@ func (t *topic) Subscribe(logger *zerolog.Logger, maxConcurrency int, ackDeadlin
 	}()
 }
 
+// deferMessage sends msg back to the subscription's queue, delayed by due
+// (up to the maximum SQS supports), and deletes the original.
+// If sending it fails the original is left to become visible again
+// once its visibility timeout expires.
+func (t *topic) deferMessage(logger *zerolog.Logger, implCfg *config.PubsubSubscription, msg sqsTypes.Message, msgID string, due time.Duration) {
+	delay := utils.Clamp((due + time.Second - 1).Truncate(time.Second), time.Second, maxSQSDelay)
+	_, err := t.sqsClient.SendMessage(t.ctxs.Connection, &sqs.SendMessageInput{
+		QueueUrl:     aws.String(implCfg.ProviderName),
+		MessageBody:  msg.Body,
+		DelaySeconds: int32(delay.Seconds()),
+	})
+	if err != nil {
+		logger.Warn().Err(err).Str("msg_id", msgID).Msg("unable to send message back to SQS queue to delay delivery")
+		return
+	}
+
+	_, err = t.sqsClient.DeleteMessage(t.ctxs.Connection, &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(implCfg.ProviderName),
+		ReceiptHandle: msg.ReceiptHandle,
+	})
+	if err != nil {
+		logger.Err(err).Str("msg_id", msgID).Msg("unable to delete delayed message from SQS queue")
+	}
+}
+
 func parseInt(m map[string]string, key string) (int64, error) {
 	value, ok := m[key]
 	if !ok {
@@ -64,8 +64,19 @@ func (t *topic) sender() *azservicebus.Sender {
 	return t._sender
 }
 
+var _ types.DelayedPublisher = (*topic)(nil)
+
 func (t *topic) PublishMessage(ctx context.Context, groupingKey string, attrs map[string]string, data []byte) (id string, err error) {
+	return t.publish(ctx, 0, attrs, data)
+}
 
+// PublishDelayedMessage publishes a message which Service Bus
+// schedules to be enqueued once the delay has passed.
+func (t *topic) PublishDelayedMessage(ctx context.Context, delay time.Duration, attrs map[string]string, data []byte) (id string, err error) {
+	return t.publish(ctx, delay, attrs, data)
+}
+
+func (t *topic) publish(ctx context.Context, delay time.Duration, attrs map[string]string, data []byte) (id string, err error) {
 	messageID, err := uuid.NewV4()
 	if err != nil {
 		return "", fmt.Errorf("failed to generate message ID: %v", err.Error())
@@ -78,6 +89,9 @@ func (t *topic) PublishMessage(ctx context.Context, groupingKey string, attrs ma
 	for k, v := range attrs {
 		msg.ApplicationProperties[k] = v
 	}
+	if delay > 0 {
+		msg.ScheduledEnqueueTime = to.Ptr(time.Now().Add(delay))
+	}
 
 	// Attempt to publish the message
 	err = t.sender().SendMessage(ctx, msg, nil)
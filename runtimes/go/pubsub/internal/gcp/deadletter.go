@@ -0,0 +1,243 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/pubsub/v2"
+	"cloud.google.com/go/pubsub/v2/apiv1/pubsubpb"
+	"google.golang.org/api/iterator"
+
+	"encore.dev/appruntime/exported/config"
+	"encore.dev/beta/errs"
+	"encore.dev/pubsub/internal/types"
+	"encore.dev/pubsub/internal/utils"
+)
+
+// deadLetterPullTimeout is how long to wait for more messages when pulling
+// from a dead-letter subscription before considering all of them read.
+const deadLetterPullTimeout = 2 * time.Second
+
+// deadLetterAttrPrefix is the prefix of the attributes GCP adds
+// to messages when forwarding them to a dead-letter topic.
+const deadLetterAttrPrefix = "CloudPubSubDeadLetter"
+
+var _ types.DeadLetterer = (*topic)(nil)
+
+func (t *topic) DeadLetterQueue(subCfg *config.PubsubSubscription) types.DeadLetterQueue {
+	return &deadLetterQueue{topic: t, subCfg: subCfg}
+}
+
+// deadLetterQueue gives access to the messages GCP forwarded to the dead-letter
+// topic of a subscription, as configured by the subscription's dead-letter policy.
+//
+// They're read through the single subscription to the dead-letter topic.
+// Pub/Sub can't peek at messages, so they're pulled until no more arrive,
+// and the ones being kept are nacked.
+type deadLetterQueue struct {
+	topic  *topic
+	subCfg *config.PubsubSubscription
+}
+
+func (q *deadLetterQueue) List(ctx context.Context) ([]*types.DeadLetter, error) {
+	var list []*types.DeadLetter
+	err := q.read(ctx, func(dl *types.DeadLetter) bool {
+		list = append(list, dl)
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(list, func(a, b *types.DeadLetter) int {
+		return a.PublishTime.Compare(b.PublishTime)
+	})
+	return list, nil
+}
+
+func (q *deadLetterQueue) Replay(ctx context.Context, ids []string) (int, error) {
+	var (
+		replayed int
+		firstErr error
+	)
+	err := q.read(ctx, func(dl *types.DeadLetter) bool {
+		if len(ids) > 0 && !slices.Contains(ids, dl.ID) {
+			return false
+		}
+
+		// Publish the message to the topic again, only for this subscription.
+		attrs := dl.Attrs
+		if attrs == nil {
+			attrs = make(map[string]string)
+		}
+		attrs[utils.TargetSubscriptionAttribute] = q.subCfg.EncoreName
+		_, err := q.topic.gcpTopic.Publish(ctx, &pubsub.Message{Data: dl.Data, Attributes: attrs}).Get(ctx)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = errs.B().Cause(err).Code(errs.Internal).Msgf("failed to replay message %s", dl.ID).Err()
+			}
+			return false
+		}
+		replayed++
+		return true
+	})
+	if err == nil {
+		err = firstErr
+	}
+	return replayed, err
+}
+
+func (q *deadLetterQueue) Purge(ctx context.Context) (int, error) {
+	var purged int
+	err := q.read(ctx, func(*types.DeadLetter) bool {
+		purged++
+		return true
+	})
+	return purged, err
+}
+
+// read calls fn for each message in the dead-letter topic, acking
+// the messages it returns true for and nacking the others.
+func (q *deadLetterQueue) read(ctx context.Context, fn func(dl *types.DeadLetter) (remove bool)) error {
+	if q.subCfg.GCP == nil {
+		return errs.B().Code(errs.FailedPrecondition).Msgf("subscription %s has no GCP configuration", q.subCfg.EncoreName).Err()
+	}
+	client := q.topic.mgr.getClientForProject(q.subCfg.GCP.ProjectID).SubscriptionAdminClient
+	dlqSub, err := q.deadLetterSubscription(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Pull messages until none arrive for a while.
+	var (
+		msgs []*pubsubpb.ReceivedMessage
+		seen = make(map[string]bool)
+	)
+	for ctx.Err() == nil {
+		pullCtx, cancel := context.WithTimeout(ctx, deadLetterPullTimeout)
+		resp, err := client.Pull(pullCtx, &pubsubpb.PullRequest{Subscription: dlqSub, MaxMessages: 1000})
+		cancel()
+		if err != nil {
+			if pullCtx.Err() != nil && ctx.Err() == nil {
+				break // no more messages
+			}
+			q.nack(dlqSub, msgs)
+			return errs.B().Cause(err).Code(errs.Unavailable).Msg("failed to pull dead-lettered messages").Err()
+		}
+		if len(resp.ReceivedMessages) == 0 {
+			break
+		}
+		for _, m := range resp.ReceivedMessages {
+			// Messages may be redelivered while reading if their ack deadline expires.
+			if seen[m.Message.MessageId] {
+				q.nack(dlqSub, []*pubsubpb.ReceivedMessage{m})
+				continue
+			}
+			seen[m.Message.MessageId] = true
+			msgs = append(msgs, m)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		q.nack(dlqSub, msgs)
+		return err
+	}
+
+	var remove, keep []*pubsubpb.ReceivedMessage
+	for _, m := range msgs {
+		if fn(toDeadLetter(m)) {
+			remove = append(remove, m)
+		} else {
+			keep = append(keep, m)
+		}
+	}
+	q.nack(dlqSub, keep)
+	if len(remove) > 0 {
+		err := client.Acknowledge(q.topic.mgr.ctxs.Connection, &pubsubpb.AcknowledgeRequest{
+			Subscription: dlqSub,
+			AckIds:       ackIDs(remove),
+		})
+		if err != nil {
+			return errs.B().Cause(err).Code(errs.Unavailable).Msg("failed to remove dead-lettered messages").Err()
+		}
+	}
+	return nil
+}
+
+// nack makes msgs available again on the dead-letter subscription.
+func (q *deadLetterQueue) nack(dlqSub string, msgs []*pubsubpb.ReceivedMessage) {
+	if len(msgs) == 0 {
+		return
+	}
+	client := q.topic.mgr.getClientForProject(q.subCfg.GCP.ProjectID).SubscriptionAdminClient
+	_ = client.ModifyAckDeadline(q.topic.mgr.ctxs.Connection, &pubsubpb.ModifyAckDeadlineRequest{
+		Subscription:       dlqSub,
+		AckIds:             ackIDs(msgs),
+		AckDeadlineSeconds: 0,
+	})
+}
+
+// deadLetterSubscription returns the full name of the subscription to read
+// the dead-lettered messages from: the only subscription to the dead-letter
+// topic of the subscription's dead-letter policy.
+func (q *deadLetterQueue) deadLetterSubscription(ctx context.Context) (string, error) {
+	client := q.topic.mgr.getClientForProject(q.subCfg.GCP.ProjectID)
+	sub, err := client.SubscriptionAdminClient.GetSubscription(ctx, &pubsubpb.GetSubscriptionRequest{
+		Subscription: fmt.Sprintf("projects/%s/subscriptions/%s", q.subCfg.GCP.ProjectID, q.subCfg.ProviderName),
+	})
+	if err != nil {
+		return "", errs.B().Cause(err).Code(errs.Unavailable).Msg("failed to get subscription").Err()
+	}
+	dlt := sub.GetDeadLetterPolicy().GetDeadLetterTopic()
+	if dlt == "" {
+		return "", errs.B().Code(errs.FailedPrecondition).Msgf("subscription %s has no dead-letter policy", q.subCfg.EncoreName).Err()
+	}
+
+	var subs []string
+	it := client.TopicAdminClient.ListTopicSubscriptions(ctx, &pubsubpb.ListTopicSubscriptionsRequest{Topic: dlt})
+	for {
+		name, err := it.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return "", errs.B().Cause(err).Code(errs.Unavailable).Msg("failed to list subscriptions to the dead-letter topic").Err()
+		}
+		subs = append(subs, name)
+	}
+	if len(subs) != 1 {
+		return "", errs.B().Code(errs.FailedPrecondition).Msgf("dead-letter topic %s must have exactly one subscription to read messages from, found %d", dlt, len(subs)).Err()
+	}
+	return subs[0], nil
+}
+
+// toDeadLetter converts a message pulled from a dead-letter subscription.
+//
+// GCP forwards dead-lettered messages as new messages, so the ID and publish time
+// are those of the message on the dead-letter topic.
+func toDeadLetter(m *pubsubpb.ReceivedMessage) *types.DeadLetter {
+	msg := m.Message
+	attempt, _ := strconv.Atoi(msg.Attributes[deadLetterAttrPrefix+"SourceDeliveryCount"])
+	attrs := make(map[string]string, len(msg.Attributes))
+	for k, v := range msg.Attributes {
+		if !strings.HasPrefix(k, deadLetterAttrPrefix) && k != utils.TargetSubscriptionAttribute {
+			attrs[k] = v
+		}
+	}
+	return &types.DeadLetter{
+		ID:              msg.MessageId,
+		PublishTime:     msg.PublishTime.AsTime(),
+		DeliveryAttempt: attempt,
+		Attrs:           attrs,
+		Data:            msg.Data,
+	}
+}
+
+func ackIDs(msgs []*pubsubpb.ReceivedMessage) []string {
+	ids := make([]string, len(msgs))
+	for i, m := range msgs {
+		ids[i] = m.AckId
+	}
+	return ids
+}
@@ -0,0 +1,56 @@
+package gcp
+
+import (
+	"context"
+	"sync"
+
+	"encore.dev/appruntime/exported/config"
+	"encore.dev/beta/errs"
+	"encore.dev/pubsub/internal/types"
+)
+
+// receiveFlow holds the flow control settings of a streaming subscription.
+//
+// The client library doesn't allow changing the settings of a running
+// subscription, so changing them restarts the subscription's Receive call.
+type receiveFlow struct {
+	mu             sync.Mutex
+	maxOutstanding int
+	cancel         context.CancelFunc // cancels the running Receive call, if any
+}
+
+// receive returns a context for the next Receive call, along
+// with the max outstanding messages it should be made with.
+func (f *receiveFlow) receive(parent context.Context) (ctx context.Context, cancel context.CancelFunc, maxOutstanding int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ctx, cancel = context.WithCancel(parent)
+	f.cancel = cancel
+	return ctx, cancel, f.maxOutstanding
+}
+
+func (f *receiveFlow) setMaxOutstanding(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if n == f.maxOutstanding {
+		return
+	}
+	f.maxOutstanding = n
+	if f.cancel != nil {
+		f.cancel()
+	}
+}
+
+var _ types.FlowController = (*topic)(nil)
+
+// SetPrefetch changes the max outstanding messages of a streaming subscription.
+func (t *topic) SetPrefetch(subCfg *config.PubsubSubscription, prefetch int) error {
+	t.flowMu.Lock()
+	flow, ok := t.flows[subCfg.EncoreName]
+	t.flowMu.Unlock()
+	if !ok {
+		return errs.B().Code(errs.FailedPrecondition).Msgf("subscription %s is not a streaming subscription", subCfg.EncoreName).Err()
+	}
+	flow.setMaxOutstanding(prefetch)
+	return nil
+}
@@ -2,6 +2,7 @@ package gcp
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"encore.dev/beta/errs"
 	"encore.dev/internal/platformauth"
 	"encore.dev/pubsub/internal/types"
+	"encore.dev/pubsub/internal/utils"
 )
 
 // This is documented in https://cloud.google.com/pubsub/docs/push
@@ -27,6 +29,9 @@ type pushPayload struct {
 	DeliveryAttempt int    `json:"deliveryAttempt,omitempty"` // Field documented in: https://cloud.google.com/pubsub/docs/handling-failures#track_delivery_attempts
 }
 
+// errNotDue is returned for pushed messages that aren't due for delivery yet.
+var errNotDue = errs.B().Code(errs.Unavailable).Msg("message not yet due for delivery").Err()
+
 func (mgr *Manager) registerPushEndpoint(logger *zerolog.Logger, subscriptionConfig *config.PubsubSubscription, f types.RawSubscriptionCallback) {
 	handler := func(req *http.Request) error {
 		// If the request has not come from the Encore platform it must have
@@ -43,10 +48,26 @@ func (mgr *Manager) registerPushEndpoint(logger *zerolog.Logger, subscriptionCon
 			return errs.WrapCode(err, errs.InvalidArgument, "invalid push payload")
 		}
 
+		// Skip messages replayed from the dead-letter queue of another subscription.
+		if utils.ForOtherSubscription(payload.Message.Attributes, subscriptionConfig.EncoreName) {
+			return nil
+		}
+
+		// Reject messages published with a delivery delay until they're due,
+		// so they're pushed again according to the subscription's retry policy.
+		if deliverAt := utils.DeliverAt(payload.Message.Attributes); time.Now().Before(deliverAt) {
+			mgr.deferrals.Defer(payload.Message.MessageID, deliverAt)
+			return errNotDue
+		}
+		deliveryAttempt := payload.DeliveryAttempt
+		if deliveryAttempt > 0 {
+			deliveryAttempt = mgr.deferrals.Attempt(payload.Message.MessageID, deliveryAttempt)
+		}
+
 		// Call the subscription callback
 		return f(
 			req.Context(),
-			payload.Message.MessageID, payload.Message.PublishTime, payload.DeliveryAttempt,
+			payload.Message.MessageID, payload.Message.PublishTime, deliveryAttempt,
 			payload.Message.Attributes, payload.Message.Data,
 		)
 	}
@@ -55,7 +76,7 @@ func (mgr *Manager) registerPushEndpoint(logger *zerolog.Logger, subscriptionCon
 		types.SubscriptionID(subscriptionConfig.ID),
 		func(w http.ResponseWriter, request *http.Request) {
 			err := handler(request)
-			if err != nil {
+			if err != nil && !errors.Is(err, errNotDue) {
 				logger.Err(err).Msg("error while handling PubSub subscription message")
 			}
 			errs.HTTPError(w, err)
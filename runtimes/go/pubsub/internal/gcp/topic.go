@@ -12,6 +12,7 @@ import (
 
 	"encore.dev/appruntime/exported/config"
 	"encore.dev/appruntime/exported/experiments"
+	"encore.dev/beta/errs"
 	"encore.dev/pubsub/internal/types"
 	"encore.dev/pubsub/internal/utils"
 )
@@ -21,6 +22,7 @@ type Manager struct {
 	runtime      *config.Runtime
 	pushRegistry types.PushEndpointRegistry
 	experiments  *experiments.Set // The set of experiments enabled for this runtime
+	deferrals    *utils.Deferrals // The redeliveries of messages that weren't due yet
 
 	clientsMu sync.Mutex                // clientsMu protects access to the clients map
 	clients   map[string]*pubsub.Client // A map of project ID to pubsub client
@@ -28,13 +30,16 @@ type Manager struct {
 
 func NewManager(ctxs *utils.Contexts, static *config.Static, runtime *config.Runtime, pushRegistry types.PushEndpointRegistry) *Manager {
 	experiments := experiments.FromConfig(static, runtime)
-	return &Manager{ctxs: ctxs, runtime: runtime, pushRegistry: pushRegistry, experiments: experiments, clients: make(map[string]*pubsub.Client)}
+	return &Manager{ctxs: ctxs, runtime: runtime, pushRegistry: pushRegistry, experiments: experiments, deferrals: utils.NewDeferrals(), clients: make(map[string]*pubsub.Client)}
 }
 
 type topic struct {
 	mgr      *Manager
 	gcpTopic *pubsub.Publisher
 	topicCfg *config.PubsubTopic
+
+	flowMu sync.Mutex
+	flows  map[string]*receiveFlow // flow control of streaming subscriptions, by encore name
 }
 
 func (mgr *Manager) ProviderName() string { return "gcp" }
@@ -59,7 +64,7 @@ func (mgr *Manager) NewTopic(_ *config.PubsubProvider, staticCfg types.TopicConf
 		panic(fmt.Sprintf("pubsub topic %s status call failed: %s", runtimeCfg.EncoreName, err))
 	}
 
-	return &topic{mgr, gcpTopic, runtimeCfg}
+	return &topic{mgr: mgr, gcpTopic: gcpTopic, topicCfg: runtimeCfg, flows: make(map[string]*receiveFlow)}
 }
 
 func (t *topic) PublishMessage(ctx context.Context, orderingKey string, attrs map[string]string, data []byte) (id string, err error) {
@@ -78,6 +83,56 @@ func (t *topic) PublishMessage(ctx context.Context, orderingKey string, attrs ma
 	return id, err
 }
 
+var _ types.BatchPublisher = (*topic)(nil)
+
+// PublishBatch publishes all the messages before waiting for any of the results,
+// letting the client library bundle them into as few requests as its batch settings allow.
+func (t *topic) PublishBatch(ctx context.Context, msgs []types.BatchMessage) []types.BatchResult {
+	pending := make([]*pubsub.PublishResult, len(msgs))
+	for i, msg := range msgs {
+		pending[i] = t.gcpTopic.Publish(ctx, &pubsub.Message{
+			Data:        msg.Data,
+			Attributes:  msg.Attrs,
+			OrderingKey: msg.OrderingKey,
+		})
+	}
+
+	results := make([]types.BatchResult, len(msgs))
+	for i, res := range pending {
+		results[i].ID, results[i].Err = res.Get(ctx)
+		if t.gcpTopic.EnableMessageOrdering && results[i].Err != nil {
+			t.gcpTopic.ResumePublish(msgs[i].OrderingKey)
+		}
+	}
+	return results
+}
+
+var _ types.DelayedPublisher = (*topic)(nil)
+
+// maxDeliveryDelay is the longest delivery delay supported. GCP Pub/Sub can't
+// delay messages, so subscribers nack them until they're due, and each of those
+// redeliveries counts towards the subscription's dead-letter policy.
+const maxDeliveryDelay = time.Hour
+
+// PublishDelayedMessage publishes a message with the time it's due as an attribute.
+// Subscribers nack the message until then, so it's redelivered according to
+// the subscription's retry policy.
+func (t *topic) PublishDelayedMessage(ctx context.Context, delay time.Duration, attrs map[string]string, data []byte) (id string, err error) {
+	if delay > maxDeliveryDelay {
+		return "", errs.B().Code(errs.InvalidArgument).Msgf("delivery delay %v exceeds the maximum of %v on GCP", delay, maxDeliveryDelay).Err()
+	}
+	utils.SetDeliverAt(attrs, delay)
+	return t.PublishMessage(ctx, "", attrs, data)
+}
+
+var _ types.DeliveryDeduplicator = (*topic)(nil)
+
+// DeduplicatesDelivery reports whether the subscription has exactly-once delivery enabled.
+// GCP only supports it for pull subscriptions.
+func (t *topic) DeduplicatesDelivery(subCfg *config.PubsubSubscription) bool {
+	return !subCfg.PushOnly && subCfg.GCP != nil && subCfg.GCP.ExactlyOnceDelivery
+}
+
 func (t *topic) Subscribe(logger *zerolog.Logger, maxConcurrency int, ackDeadline time.Duration, retryPolicy *types.RetryPolicy, subCfg *config.PubsubSubscription, f types.RawSubscriptionCallback) {
 	if subCfg.PushOnly && subCfg.ID == "" {
 		panic("push-only subscriptions must have a subscription ID")
@@ -105,7 +160,10 @@ func (t *topic) Subscribe(logger *zerolog.Logger, maxConcurrency int, ackDeadlin
 		if maxConcurrency == 0 {
 			maxConcurrency = 1000 // FIXME(domblack): This retains the old behaviour, but allows user customisation - in a future release we should remove this
 		}
-		subscription.ReceiveSettings.MaxOutstandingMessages = maxConcurrency
+		flow := &receiveFlow{maxOutstanding: maxConcurrency}
+		t.flowMu.Lock()
+		t.flows[subCfg.EncoreName] = flow
+		t.flowMu.Unlock()
 
 		if experiments.AdaptiveGCPPubSubGoroutines.Enabled(t.mgr.experiments) {
 			// Compute the number of goroutines to use for this subscription.
@@ -123,13 +181,32 @@ func (t *topic) Subscribe(logger *zerolog.Logger, maxConcurrency int, ackDeadlin
 		// Start the subscription with the GCP library
 		go func() {
 			for t.mgr.ctxs.Fetch.Err() == nil {
+				// Receive with the current flow control settings, until they change.
+				receiveCtx, cancel, maxOutstanding := flow.receive(t.mgr.ctxs.Fetch)
+				subscription.ReceiveSettings.MaxOutstandingMessages = maxOutstanding
+
 				// Subscribe to the topic to receive messages
-				err := subscription.Receive(t.mgr.ctxs.Fetch, func(_ context.Context, msg *pubsub.Message) {
+				err := subscription.Receive(receiveCtx, func(_ context.Context, msg *pubsub.Message) {
 					deliveryAttempt := 1
 					if msg.DeliveryAttempt != nil {
 						deliveryAttempt = *msg.DeliveryAttempt
 					}
 
+					// Skip messages replayed from the dead-letter queue of another subscription.
+					if utils.ForOtherSubscription(msg.Attributes, subCfg.EncoreName) {
+						msg.Ack()
+						return
+					}
+
+					// Nack messages published with a delivery delay until they're due,
+					// rather than holding on to them and taking up a slot in the meantime.
+					if deliverAt := utils.DeliverAt(msg.Attributes); time.Now().Before(deliverAt) {
+						t.mgr.deferrals.Defer(msg.ID, deliverAt)
+						msg.Nack()
+						return
+					}
+					deliveryAttempt = t.mgr.deferrals.Attempt(msg.ID, deliveryAttempt)
+
 					// Create a context from the handler context with a deadline of the ackdeadline
 					ctx, cancel := context.WithTimeout(t.mgr.ctxs.Handler, ackDeadline)
 					defer cancel()
@@ -159,11 +236,12 @@ func (t *topic) Subscribe(logger *zerolog.Logger, maxConcurrency int, ackDeadlin
 					}
 				})
 
-				// If there was an error and we're not shutting down, log it and then sleep for a bit before trying again
-				if err != nil && t.mgr.ctxs.Fetch.Err() == nil {
+				// If there was an error and we're not shutting down or restarting, log it and then sleep for a bit before trying again
+				if err != nil && receiveCtx.Err() == nil {
 					logger.Warn().Err(err).Msg("pubsub subscription failed, retrying in 5 seconds")
 					time.Sleep(5 * time.Second)
 				}
+				cancel()
 			}
 		}()
 	}
@@ -0,0 +1,223 @@
+package nsq
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nsqio/go-nsq"
+
+	"encore.dev/appruntime/exported/config"
+	"encore.dev/beta/errs"
+	"encore.dev/pubsub/internal/types"
+)
+
+// deadLetterWrapper is a message dead-lettered by a subscription,
+// as published to the subscription's dead-letter topic.
+type deadLetterWrapper struct {
+	messageWrapper
+	PublishTime     time.Time
+	DeliveryAttempt int
+}
+
+// deadLetterChannel is the channel used to read the dead-letter topics.
+const deadLetterChannel = "dlq"
+
+// deadLetterIdle is how long to wait for more messages when reading
+// a dead-letter topic before considering all of them read.
+const deadLetterIdle = 250 * time.Millisecond
+
+// deadLetterTopic returns the name of the dead-letter topic
+// for the given subscription to the given topic.
+func deadLetterTopic(topic, subscription string) string {
+	name := topic + "." + subscription + ".dlq"
+	const maxLen = 64 // from nsq's topic name validation
+	if len(name) > maxLen {
+		sum := sha256.Sum256([]byte(name))
+		name = hex.EncodeToString(sum[:16]) + ".dlq"
+	}
+	return name
+}
+
+// deadLetter publishes msg to the dead-letter topic of the given subscription.
+func (l *topic) deadLetter(subscription string, m *nsq.Message, msg *messageWrapper, attempt int) error {
+	producer, err := l.getProducer()
+	if err != nil {
+		return err
+	}
+	wrapper := &deadLetterWrapper{
+		messageWrapper:  *msg,
+		PublishTime:     time.Unix(0, m.Timestamp),
+		DeliveryAttempt: attempt,
+	}
+	wrapper.TargetSubscription = ""
+	data, err := json.Marshal(wrapper)
+	if err != nil {
+		return errs.B().Cause(err).Code(errs.Internal).Msg("failed to marshal message").Err()
+	}
+	return producer.Publish(deadLetterTopic(l.name, subscription), data)
+}
+
+var _ types.DeadLetterer = (*topic)(nil)
+
+func (l *topic) DeadLetterQueue(subCfg *config.PubsubSubscription) types.DeadLetterQueue {
+	return &deadLetterQueue{topic: l, subscription: subCfg.EncoreName}
+}
+
+// deadLetterQueue gives access to the dead-letter topic of a subscription.
+//
+// nsqd can't peek at messages, so they're read by consuming the dead-letter
+// topic until no more messages arrive, and the ones being kept are requeued.
+type deadLetterQueue struct {
+	topic        *topic
+	subscription string
+}
+
+func (q *deadLetterQueue) List(ctx context.Context) ([]*types.DeadLetter, error) {
+	var list []*types.DeadLetter
+	err := q.read(ctx, func(dl *deadLetterWrapper) bool {
+		list = append(list, &types.DeadLetter{
+			ID:              dl.ID,
+			PublishTime:     dl.PublishTime,
+			DeliveryAttempt: dl.DeliveryAttempt,
+			Attrs:           dl.Attributes,
+			Data:            dl.Data,
+		})
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(list, func(a, b *types.DeadLetter) int {
+		return a.PublishTime.Compare(b.PublishTime)
+	})
+	return list, nil
+}
+
+func (q *deadLetterQueue) Replay(ctx context.Context, ids []string) (int, error) {
+	producer, err := q.topic.getProducer()
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		replayed int
+		firstErr error
+	)
+	err = q.read(ctx, func(dl *deadLetterWrapper) bool {
+		if len(ids) > 0 && !slices.Contains(ids, dl.ID) {
+			return false
+		}
+
+		// Publish the message to the topic again, only for this subscription.
+		msg := dl.messageWrapper
+		msg.TargetSubscription = q.subscription
+		data, err := json.Marshal(&msg)
+		if err == nil {
+			err = producer.Publish(q.topic.name, data)
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = errs.B().Cause(err).Code(errs.Internal).Msgf("failed to replay message %s", dl.ID).Err()
+			}
+			return false
+		}
+		replayed++
+		return true
+	})
+	if err == nil {
+		err = firstErr
+	}
+	return replayed, err
+}
+
+func (q *deadLetterQueue) Purge(ctx context.Context) (int, error) {
+	var purged int
+	err := q.read(ctx, func(*deadLetterWrapper) bool {
+		purged++
+		return true
+	})
+	return purged, err
+}
+
+// read calls fn for each message in the dead-letter topic, removing
+// the messages it returns true for and requeueing the others.
+func (q *deadLetterQueue) read(ctx context.Context, fn func(dl *deadLetterWrapper) (remove bool)) error {
+	cfg := nsq.NewConfig()
+	cfg.MaxInFlight = 2500 // the most nsqd allows by default
+	// Disable nsqd's output buffering, as it would otherwise hold
+	// back messages for as long as we wait for more to arrive.
+	cfg.OutputBufferSize = -1
+	consumer, err := nsq.NewConsumer(deadLetterTopic(q.topic.name, q.subscription), deadLetterChannel, cfg)
+	if err != nil {
+		return errs.B().Cause(err).Code(errs.Internal).Msg("failed to read dead-letter queue").Err()
+	}
+	log := q.topic.mgr.rt.Logger().With().Str("topic", q.topic.name).Str("subscription", q.subscription).Logger()
+	consumer.SetLogger(&LogAdapter{Logger: &log}, nsq.LogLevelWarning)
+	defer func() {
+		consumer.Stop()
+		<-consumer.StopChan
+	}()
+
+	var (
+		mu       sync.Mutex
+		done     bool
+		received = make(chan *nsq.Message, cfg.MaxInFlight)
+	)
+	consumer.AddHandler(nsq.HandlerFunc(func(m *nsq.Message) error {
+		m.DisableAutoResponse()
+		mu.Lock()
+		defer mu.Unlock()
+		if done {
+			// Messages arriving once we're done reading are handed straight back.
+			m.RequeueWithoutBackoff(0)
+			return nil
+		}
+		received <- m
+		return nil
+	}))
+	if err := consumer.ConnectToNSQD(q.topic.addr); err != nil {
+		return errs.B().Cause(err).Code(errs.Internal).Msg("failed to connect to NSQD").Err()
+	}
+
+	// Read messages until none have arrived for a while.
+	var msgs []*nsq.Message
+	idle := time.NewTimer(deadLetterIdle)
+	defer idle.Stop()
+read:
+	for {
+		select {
+		case m := <-received:
+			msgs = append(msgs, m)
+			idle.Reset(deadLetterIdle)
+		case <-idle.C:
+			break read
+		case <-ctx.Done():
+			err = ctx.Err()
+			break read
+		}
+	}
+
+	// Stop receiving messages, and include any that arrived in the meantime.
+	consumer.ChangeMaxInFlight(0)
+	mu.Lock()
+	done = true
+	mu.Unlock()
+	for len(received) > 0 {
+		msgs = append(msgs, <-received)
+	}
+
+	for _, m := range msgs {
+		dl := &deadLetterWrapper{}
+		if err == nil && json.Unmarshal(m.Body, dl) == nil && fn(dl) {
+			m.Finish()
+		} else {
+			m.RequeueWithoutBackoff(0)
+		}
+	}
+	return err
+}
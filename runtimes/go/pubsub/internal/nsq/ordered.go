@@ -0,0 +1,175 @@
+package nsq
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/nsqio/go-nsq"
+	"github.com/rs/zerolog"
+
+	"encore.dev/pubsub/internal/types"
+	"encore.dev/pubsub/internal/utils"
+)
+
+// orderedMaxInFlight is the number of messages nsqd may have in flight to
+// an ordered subscription. It's higher than the subscription's concurrency
+// as messages held back behind earlier messages with the same ordering key
+// count as in flight.
+const orderedMaxInFlight = 1000
+
+// orderedHandler is the nsq.Handler for subscriptions to topics with an OrderingAttribute.
+//
+// nsqd has no notion of ordering keys, so orderedHandler simulates them the way
+// the cloud providers behave: messages with the same ordering key are processed one
+// at a time in the order nsqd delivered them, and a failing message is retried
+// before any later messages with the same key are processed.
+//
+// It must be registered with a single handler goroutine, so that messages are
+// queued in the order they're received.
+type orderedHandler struct {
+	ctx          context.Context
+	logger       *zerolog.Logger
+	orderingAttr string
+	retryPolicy  *types.RetryPolicy
+	touchEvery   time.Duration // how often to touch held back messages, so nsqd doesn't time them out
+	sem          chan struct{} // limits the number of messages processed concurrently
+	process      func(m *nsq.Message, msg *messageWrapper, attempt int) error
+	deadLetter   func(m *nsq.Message, msg *messageWrapper, attempt int) // responds to a message that ran out of retries
+
+	mu     sync.Mutex
+	queues map[string][]*orderedMessage // pending messages by ordering key; the first one is being processed
+}
+
+type orderedMessage struct {
+	m   *nsq.Message
+	msg *messageWrapper
+}
+
+func newOrderedHandler(ctx context.Context, logger *zerolog.Logger, orderingAttr string, maxConcurrency int, msgTimeout time.Duration, retryPolicy *types.RetryPolicy, process func(m *nsq.Message, msg *messageWrapper, attempt int) error, deadLetter func(m *nsq.Message, msg *messageWrapper, attempt int)) *orderedHandler {
+	if msgTimeout <= 0 {
+		msgTimeout = time.Minute // the nsqd default
+	}
+	return &orderedHandler{
+		ctx:          ctx,
+		logger:       logger,
+		orderingAttr: orderingAttr,
+		retryPolicy:  retryPolicy,
+		touchEvery:   msgTimeout / 2,
+		sem:          make(chan struct{}, maxConcurrency),
+		process:      process,
+		deadLetter:   deadLetter,
+		queues:       make(map[string][]*orderedMessage),
+	}
+}
+
+func (h *orderedHandler) HandleMessage(m *nsq.Message) error {
+	msg := &messageWrapper{}
+	if err := json.Unmarshal(m.Body, msg); err != nil {
+		// Retrying won't help and would hold back the messages after it, so drop it.
+		h.logger.Error().Err(err).Msg("failed to unmarshal message wrapper. Dropping message")
+		return nil
+	}
+
+	// The message is responded to once it's been processed.
+	m.DisableAutoResponse()
+
+	key := msg.Attributes[h.orderingAttr]
+	h.mu.Lock()
+	q := h.queues[key]
+	h.queues[key] = append(q, &orderedMessage{m: m, msg: msg})
+	h.mu.Unlock()
+
+	// If there was nothing queued for the key, start processing it.
+	if len(q) == 0 {
+		go h.run(key)
+	}
+	return nil
+}
+
+// run processes the messages queued for the given ordering key in order,
+// until there are none left.
+func (h *orderedHandler) run(key string) {
+	stopTouching := make(chan struct{})
+	defer close(stopTouching)
+	go h.touch(key, stopTouching)
+
+	for {
+		h.mu.Lock()
+		next := h.queues[key][0]
+		h.mu.Unlock()
+
+		if !h.deliver(next) {
+			// The subscription is stopping; hand the remaining messages back to nsqd.
+			h.mu.Lock()
+			for _, om := range h.queues[key] {
+				om.m.RequeueWithoutBackoff(0)
+			}
+			delete(h.queues, key)
+			h.mu.Unlock()
+			return
+		}
+
+		h.mu.Lock()
+		q := h.queues[key][1:]
+		if len(q) == 0 {
+			delete(h.queues, key)
+			h.mu.Unlock()
+			return
+		}
+		h.queues[key] = q
+		h.mu.Unlock()
+	}
+}
+
+// deliver processes om until it succeeds or runs out of retries.
+// It reports false if the subscription stopped first.
+func (h *orderedHandler) deliver(om *orderedMessage) bool {
+	for attempt := int(om.m.Attempts); ; attempt++ {
+		select {
+		case h.sem <- struct{}{}:
+		case <-h.ctx.Done():
+			return false
+		}
+		err := h.process(om.m, om.msg, attempt)
+		<-h.sem
+
+		if err == nil {
+			om.m.Finish()
+			return true
+		}
+
+		retry, delay := utils.GetDelay(h.retryPolicy.MaxRetries, h.retryPolicy.MinBackoff, h.retryPolicy.MaxBackoff, uint16(attempt))
+		if !retry {
+			h.deadLetter(om.m, om.msg, attempt)
+			return true
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-h.ctx.Done():
+			timer.Stop()
+			return false
+		}
+	}
+}
+
+// touch periodically touches the messages queued for key until stop is closed.
+func (h *orderedHandler) touch(key string, stop <-chan struct{}) {
+	ticker := time.NewTicker(h.touchEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			h.mu.Lock()
+			for _, om := range h.queues[key] {
+				om.m.Touch()
+			}
+			h.mu.Unlock()
+		case <-stop:
+			return
+		}
+	}
+}
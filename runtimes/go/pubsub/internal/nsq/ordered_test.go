@@ -0,0 +1,85 @@
+package nsq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nsqio/go-nsq"
+	"github.com/rs/zerolog"
+
+	"encore.dev/pubsub/internal/types"
+)
+
+type finishDelegate struct {
+	wg *sync.WaitGroup
+}
+
+func (d finishDelegate) OnFinish(*nsq.Message)                       { d.wg.Done() }
+func (d finishDelegate) OnRequeue(*nsq.Message, time.Duration, bool) { d.wg.Done() }
+func (d finishDelegate) OnTouch(*nsq.Message)                        {}
+
+func TestOrderedHandler(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		processed = map[string][]string{} // processed message ids by key
+		failed    = map[string]bool{}
+	)
+	process := func(m *nsq.Message, msg *messageWrapper, attempt int) error {
+		// Give later messages a chance to overtake this one if ordering was broken.
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		key := msg.Attributes["key"]
+		processed[key] = append(processed[key], msg.ID)
+
+		// Fail the first attempt of the first message.
+		if msg.ID == "a1" && !failed[msg.ID] {
+			failed[msg.ID] = true
+			return errors.New("failed")
+		}
+		return nil
+	}
+
+	logger := zerolog.Nop()
+	// A max backoff below the min backoff makes retries use the max backoff.
+	retry := &types.RetryPolicy{MinBackoff: time.Second, MaxBackoff: time.Millisecond, MaxRetries: 5}
+	deadLetter := func(m *nsq.Message, msg *messageWrapper, attempt int) {
+		t.Errorf("message %s unexpectedly dead-lettered", msg.ID)
+		m.Finish()
+	}
+	h := newOrderedHandler(context.Background(), &logger, "key", 10, time.Minute, retry, process, deadLetter)
+
+	var wg sync.WaitGroup
+	for _, id := range []string{"a1", "b1", "a2", "b2", "a3"} {
+		body, err := json.Marshal(&messageWrapper{ID: id, Attributes: map[string]string{"key": id[:1]}, Data: json.RawMessage(`{}`)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := nsq.NewMessage(nsq.MessageID{}, body)
+		m.Attempts = 1
+		m.Delegate = finishDelegate{&wg}
+		wg.Add(1)
+		if err := h.HandleMessage(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := map[string][]string{
+		"a": {"a1", "a1", "a2", "a3"},
+		"b": {"b1", "b2"},
+	}
+	for key, ids := range want {
+		if got := processed[key]; !slices.Equal(got, ids) {
+			t.Errorf("key %s: got %v, want %v", key, got, ids)
+		}
+	}
+}
@@ -30,12 +30,13 @@ func NewManager(ctxs *utils.Contexts, rt *reqtrack.RequestTracker) *Manager {
 // topic is the nsq implementation of pubsub.Topic. It exposes methods to publish
 // and subscribe to messages of a topic
 type topic struct {
-	mgr       *Manager
-	name      string
-	addr      string
-	m         sync.Mutex
-	producer  *nsq.Producer
-	consumers map[string]*nsq.Consumer
+	mgr          *Manager
+	name         string
+	addr         string
+	orderingAttr string // the topic's OrderingAttribute, if any
+	m            sync.Mutex
+	producer     *nsq.Producer
+	consumers    map[string]*nsq.Consumer
 }
 
 func (mgr *Manager) ProviderName() string { return "nsq" }
@@ -44,13 +45,14 @@ func (mgr *Manager) Matches(cfg *config.PubsubProvider) bool {
 	return cfg.NSQ != nil
 }
 
-func (mgr *Manager) NewTopic(providerCfg *config.PubsubProvider, _ types.TopicConfig, runtimeCfg *config.PubsubTopic) types.TopicImplementation {
+func (mgr *Manager) NewTopic(providerCfg *config.PubsubProvider, staticCfg types.TopicConfig, runtimeCfg *config.PubsubTopic) types.TopicImplementation {
 	return &topic{
-		mgr:       mgr,
-		name:      runtimeCfg.EncoreName,
-		addr:      providerCfg.NSQ.Host,
-		producer:  nil,
-		consumers: make(map[string]*nsq.Consumer),
+		mgr:          mgr,
+		name:         runtimeCfg.EncoreName,
+		addr:         providerCfg.NSQ.Host,
+		orderingAttr: staticCfg.OrderingAttribute,
+		producer:     nil,
+		consumers:    make(map[string]*nsq.Consumer),
 	}
 }
 
@@ -61,6 +63,10 @@ type messageWrapper struct {
 	ID         string
 	Attributes map[string]string
 	Data       json.RawMessage
+
+	// TargetSubscription is set when a message is only meant for a single
+	// subscription, such as when it's replayed from its dead-letter queue.
+	TargetSubscription string `json:",omitempty"`
 }
 
 func (l *topic) Subscribe(logger *zerolog.Logger, maxConcurrency int, ackDeadline time.Duration, retryPolicy *types.RetryPolicy, implCfg *config.PubsubSubscription, f types.RawSubscriptionCallback) {
@@ -86,6 +92,9 @@ func (l *topic) Subscribe(logger *zerolog.Logger, maxConcurrency int, ackDeadlin
 	}
 
 	conCfg := getConsumerConfig(maxConcurrency, ackDeadline, retryPolicy)
+	if l.orderingAttr != "" {
+		conCfg.MaxInFlight = orderedMaxInFlight
+	}
 	consumer, err := nsq.NewConsumer(l.name, implCfg.EncoreName, conCfg)
 	if err != nil {
 		panic(fmt.Sprintf("unable to setup subscription %s for topic %s: %v", implCfg.EncoreName, l.name, err))
@@ -93,41 +102,64 @@ func (l *topic) Subscribe(logger *zerolog.Logger, maxConcurrency int, ackDeadlin
 	// only log warnings and above from the NSQ library
 	consumer.SetLogger(&LogAdapter{Logger: logger}, nsq.LogLevelWarning)
 
-	// create a dedicated handler which forwards messages to the encore subscription
-	consumer.AddConcurrentHandlers(nsq.HandlerFunc(func(m *nsq.Message) error {
-		// create a message to unmarshal the raw nsq body into
-		msg := &messageWrapper{}
-
-		defer func() {
-			if !m.HasResponded() {
-				retry, delay := utils.GetDelay(retryPolicy.MaxRetries, retryPolicy.MinBackoff, retryPolicy.MaxBackoff, m.Attempts)
-				if !retry {
-
-					logger.Error().Str("msg_id", msg.ID).Int("retry", int(m.Attempts)-1).Msg("depleted message retries. Dropping message")
-					// TODO; offload this to the dead letter queue
-					m.Finish()
-					return
-				}
-				m.RequeueWithoutBackoff(delay)
-			}
-		}()
-
-		err = json.Unmarshal(m.Body, msg)
-		if err != nil {
-			return errs.B().Cause(err).Code(errs.InvalidArgument).Msg("failed to unmarshal message wrapper").Err()
+	// process forwards a message to the encore subscription
+	process := func(m *nsq.Message, msg *messageWrapper, attempt int) error {
+		if msg.TargetSubscription != "" && msg.TargetSubscription != implCfg.EncoreName {
+			return nil // the message is meant for another subscription
 		}
-
-		// forward the message to the subscriber
 		msgCtx, cancel := context.WithTimeout(l.mgr.ctxs.Handler, ackDeadline)
 		defer cancel()
+		return f(msgCtx, msg.ID, time.Unix(0, m.Timestamp), attempt, msg.Attributes, msg.Data)
+	}
 
-		err = f(msgCtx, msg.ID, time.Unix(0, m.Timestamp), int(m.Attempts), msg.Attributes, msg.Data)
-		if err != nil {
-			return err
+	// deadLetter moves a message that ran out of retries to the subscription's dead-letter queue
+	deadLetter := func(m *nsq.Message, msg *messageWrapper, attempt int) {
+		logger.Error().Str("msg_id", msg.ID).Int("retry", attempt-1).Msg("depleted message retries. Moving message to the dead-letter queue")
+		if err := l.deadLetter(implCfg.EncoreName, m, msg, attempt); err != nil {
+			logger.Error().Err(err).Str("msg_id", msg.ID).Msg("failed to dead-letter message. Dropping message")
 		}
 		m.Finish()
-		return nil
-	}), maxConcurrency)
+	}
+
+	if l.orderingAttr != "" {
+		// Ordered topics dispatch messages from a single handler, which preserves their order per ordering key.
+		consumer.AddHandler(newOrderedHandler(l.mgr.ctxs.Fetch, logger, l.orderingAttr, maxConcurrency, conCfg.MsgTimeout, retryPolicy, process, deadLetter))
+	} else {
+		// create a dedicated handler which forwards messages to the encore subscription
+		consumer.AddConcurrentHandlers(nsq.HandlerFunc(func(m *nsq.Message) error {
+			// create a message to unmarshal the raw nsq body into
+			msg := &messageWrapper{}
+
+			defer func() {
+				if !m.HasResponded() {
+					retry, delay := utils.GetDelay(retryPolicy.MaxRetries, retryPolicy.MinBackoff, retryPolicy.MaxBackoff, m.Attempts)
+					if !retry {
+						if msg.ID == "" {
+							// The message couldn't be unmarshalled, so there's nothing to dead-letter.
+							logger.Error().Int("retry", int(m.Attempts)-1).Msg("depleted message retries. Dropping message")
+							m.Finish()
+							return
+						}
+						deadLetter(m, msg, int(m.Attempts))
+						return
+					}
+					m.RequeueWithoutBackoff(delay)
+				}
+			}()
+
+			err := json.Unmarshal(m.Body, msg)
+			if err != nil {
+				return errs.B().Cause(err).Code(errs.InvalidArgument).Msg("failed to unmarshal message wrapper").Err()
+			}
+
+			// forward the message to the subscriber
+			if err := process(m, msg, int(m.Attempts)); err != nil {
+				return err
+			}
+			m.Finish()
+			return nil
+		}), maxConcurrency)
+	}
 
 	// add the consumer to the known consumers
 	l.consumers[implCfg.EncoreName] = consumer
@@ -150,23 +182,37 @@ func (l *topic) Subscribe(logger *zerolog.Logger, maxConcurrency int, ackDeadlin
 	}()
 }
 
+var _ types.FlowController = (*topic)(nil)
+
+// SetPrefetch changes the number of messages nsqd sends the subscription's consumer ahead of processing.
+func (l *topic) SetPrefetch(subCfg *config.PubsubSubscription, prefetch int) error {
+	l.m.Lock()
+	consumer, ok := l.consumers[subCfg.EncoreName]
+	l.m.Unlock()
+	if !ok {
+		return errs.B().Code(errs.NotFound).Msgf("subscription %s is not running", subCfg.EncoreName).Err()
+	}
+	consumer.ChangeMaxInFlight(prefetch)
+	return nil
+}
+
+var _ types.DelayedPublisher = (*topic)(nil)
+
 // PublishMessage publishes a message to an nsq Topic
 func (l *topic) PublishMessage(ctx context.Context, orderingKey string, attrs map[string]string, data []byte) (id string, err error) {
-	// instantiate a Producer if there isn;t one already
-	if l.producer == nil {
-		l.m.Lock()
-		defer l.m.Unlock()
-		if l.producer == nil {
-			cfg := nsq.NewConfig()
-			producer, err := nsq.NewProducer(l.addr, cfg)
-			if err != nil {
-				return "", errs.B().Cause(err).Code(errs.Internal).Msg("failed to connect to NSQD").Err()
-			}
-			// only log warnings and above from the NSQ library
-			log := l.mgr.rt.Logger().With().Str("topic", l.name).Logger()
-			producer.SetLogger(&LogAdapter{Logger: &log}, nsq.LogLevelWarning)
-			l.producer = producer
-		}
+	return l.publish(0, attrs, data)
+}
+
+// PublishDelayedMessage publishes a message to an nsq Topic, which nsqd
+// defers the delivery of until the delay has passed.
+func (l *topic) PublishDelayedMessage(ctx context.Context, delay time.Duration, attrs map[string]string, data []byte) (id string, err error) {
+	return l.publish(delay, attrs, data)
+}
+
+func (l *topic) publish(delay time.Duration, attrs map[string]string, data []byte) (id string, err error) {
+	producer, err := l.getProducer()
+	if err != nil {
+		return "", err
 	}
 
 	// generate a new message ID
@@ -177,13 +223,67 @@ func (l *topic) PublishMessage(ctx context.Context, orderingKey string, attrs ma
 	if err != nil {
 		return "", errs.B().Cause(err).Code(errs.Internal).Msg("failed to marshal message").Err()
 	}
-	err = l.producer.Publish(l.name, data)
+	if delay > 0 {
+		err = producer.DeferredPublish(l.name, delay, data)
+	} else {
+		err = producer.Publish(l.name, data)
+	}
 	if err != nil {
 		return "", errs.B().Cause(err).Code(errs.Internal).Msg("failed to connect to NSQD").Err()
 	}
 	return msgID, nil
 }
 
+var _ types.BatchPublisher = (*topic)(nil)
+
+// PublishBatch publishes the messages to an nsq Topic in a single multi-publish.
+// nsqd accepts or rejects them all together.
+func (l *topic) PublishBatch(ctx context.Context, msgs []types.BatchMessage) []types.BatchResult {
+	results := make([]types.BatchResult, len(msgs))
+	fail := func(err error) []types.BatchResult {
+		for i := range results {
+			results[i] = types.BatchResult{Err: err}
+		}
+		return results
+	}
+
+	producer, err := l.getProducer()
+	if err != nil {
+		return fail(err)
+	}
+
+	body := make([][]byte, len(msgs))
+	for i, msg := range msgs {
+		results[i].ID = xid.New().String()
+		body[i], err = json.Marshal(&messageWrapper{ID: results[i].ID, Data: msg.Data, Attributes: msg.Attrs})
+		if err != nil {
+			return fail(errs.B().Cause(err).Code(errs.Internal).Msg("failed to marshal message").Err())
+		}
+	}
+	if err := producer.MultiPublish(l.name, body); err != nil {
+		return fail(errs.B().Cause(err).Code(errs.Internal).Msg("failed to connect to NSQD").Err())
+	}
+	return results
+}
+
+// getProducer returns the topic's producer, creating it if there isn't one already.
+func (l *topic) getProducer() (*nsq.Producer, error) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	if l.producer == nil {
+		cfg := nsq.NewConfig()
+		producer, err := nsq.NewProducer(l.addr, cfg)
+		if err != nil {
+			return nil, errs.B().Cause(err).Code(errs.Internal).Msg("failed to connect to NSQD").Err()
+		}
+		// only log warnings and above from the NSQ library
+		log := l.mgr.rt.Logger().With().Str("topic", l.name).Logger()
+		producer.SetLogger(&LogAdapter{Logger: &log}, nsq.LogLevelWarning)
+		l.producer = producer
+	}
+	return l.producer, nil
+}
+
 func getConsumerConfig(maxConcurrency int, ackDeadline time.Duration, retryPolicy *types.RetryPolicy) *nsq.Config {
 	conCfg := nsq.NewConfig()
 	conCfg.MsgTimeout = utils.Clamp(ackDeadline, 0, 15*time.Minute)
@@ -191,17 +291,21 @@ func getConsumerConfig(maxConcurrency int, ackDeadline time.Duration, retryPolic
 	conCfg.DefaultRequeueDelay = utils.Clamp(retryPolicy.MinBackoff, 0, 60*time.Minute)
 	conCfg.MaxRequeueDelay = utils.Clamp(retryPolicy.MaxBackoff, 0, 60*time.Minute)
 
+	// The nsq library finishes messages exceeding MaxAttempts without handing them to us,
+	// so allow one attempt more than the retries for the last one to be dead-lettered.
 	switch retryPolicy.MaxRetries {
 	case 0:
 		conCfg.MaxAttempts = 100
 	case types.InfiniteRetries:
 		conCfg.MaxAttempts = 65535
+	case types.NoRetries:
+		conCfg.MaxAttempts = 1
 	default:
 		const maxVal = 65535 // from the nsq library config
-		if retryPolicy.MaxRetries > maxVal {
+		if retryPolicy.MaxRetries >= maxVal {
 			conCfg.MaxAttempts = maxVal
 		} else {
-			conCfg.MaxAttempts = uint16(retryPolicy.MaxRetries)
+			conCfg.MaxAttempts = uint16(retryPolicy.MaxRetries + 1)
 		}
 	}
 
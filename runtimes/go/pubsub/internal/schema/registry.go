@@ -0,0 +1,121 @@
+package schema
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Registry is a client for a schema registry implementing
+// the Confluent Schema Registry REST API.
+type Registry struct {
+	URL    string // the base URL of the registry, optionally with basic auth credentials
+	Client *http.Client
+}
+
+// subjectNotFound is the registry's error code for unknown subjects.
+const subjectNotFound = 40401
+
+// Latest returns the latest version of the schema registered under subject,
+// or nil if there is none.
+func (r *Registry) Latest(ctx context.Context, subject string) (*Schema, error) {
+	var resp struct {
+		Schema string `json:"schema"`
+	}
+	if err := r.do(ctx, http.MethodGet, "/subjects/"+url.PathEscape(subject)+"/versions/latest", nil, &resp); err != nil {
+		if e, ok := err.(*registryError); ok && e.Code == subjectNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	s := &Schema{}
+	if err := json.Unmarshal([]byte(resp.Schema), s); err != nil {
+		return nil, fmt.Errorf("invalid schema registered for subject %s: %w", subject, err)
+	}
+	return s, nil
+}
+
+// Register registers s as the latest version of the schema under subject.
+// Registering a schema identical to an existing version is a no-op.
+func (r *Registry) Register(ctx context.Context, subject string, s *Schema) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	req := map[string]string{"schemaType": "JSON", "schema": string(data)}
+	return r.do(ctx, http.MethodPost, "/subjects/"+url.PathEscape(subject)+"/versions", req, nil)
+}
+
+// Check checks that s is compatible with the latest schema registered under
+// subject, if any, and registers it as the latest version if so.
+// It returns an *IncompatibleError if s is incompatible.
+func (r *Registry) Check(ctx context.Context, subject string, s *Schema) error {
+	prev, err := r.Latest(ctx, subject)
+	if err != nil {
+		return err
+	}
+	if prev != nil {
+		if err := Compatible(prev, s); err != nil {
+			return err
+		}
+	}
+	return r.Register(ctx, subject, s)
+}
+
+// registryError is an error response from the registry.
+type registryError struct {
+	Code    int    `json:"error_code"`
+	Message string `json:"message"`
+}
+
+func (e *registryError) Error() string {
+	return fmt.Sprintf("schema registry error %d: %s", e.Code, e.Message)
+}
+
+func (r *Registry) do(ctx context.Context, method, path string, body, dst any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(r.URL, "/")+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		e := &registryError{Code: resp.StatusCode}
+		if err := json.NewDecoder(resp.Body).Decode(e); err != nil || e.Message == "" {
+			e.Message = resp.Status
+		}
+		return e
+	}
+	if dst == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
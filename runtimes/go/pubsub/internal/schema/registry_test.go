@@ -0,0 +1,108 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeRegistry is an in-memory implementation of the parts
+// of the schema registry API used by Registry.
+type fakeRegistry struct {
+	mu       sync.Mutex
+	versions map[string][]string
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	subject, ok := strings.CutPrefix(req.URL.Path, "/subjects/")
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	subject, _, _ = strings.Cut(subject, "/versions")
+
+	switch req.Method {
+	case http.MethodGet:
+		versions := f.versions[subject]
+		if len(versions) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(registryError{Code: subjectNotFound, Message: "Subject not found."})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"schema": versions[len(versions)-1]})
+	case http.MethodPost:
+		var body struct {
+			SchemaType string `json:"schemaType"`
+			Schema     string `json:"schema"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.SchemaType != "JSON" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_ = json.NewEncoder(w).Encode(registryError{Code: 42201, Message: "Invalid schema"})
+			return
+		}
+		f.versions[subject] = append(f.versions[subject], body.Schema)
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": len(f.versions[subject])})
+	}
+}
+
+func TestRegistry_Check(t *testing.T) {
+	type v1 struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	type v2 struct {
+		ID    int64  `json:"id"`
+		Name  string `json:"name"`
+		Email string `json:"email,omitempty"`
+	}
+	type v3 struct {
+		ID    string `json:"id"`
+		Email string `json:"email,omitempty"`
+	}
+
+	fake := &fakeRegistry{versions: make(map[string][]string)}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	ctx := context.Background()
+	reg := &Registry{URL: srv.URL + "/"}
+
+	if s, err := reg.Latest(ctx, "users-value"); s != nil || err != nil {
+		t.Fatalf("got Latest() = %v, %v for unknown subject, want nil, nil", s, err)
+	}
+	if err := reg.Check(ctx, "users-value", For(reflect.TypeFor[v1]())); err != nil {
+		t.Fatalf("Check(v1): %v", err)
+	}
+	if err := reg.Check(ctx, "users-value", For(reflect.TypeFor[v2]())); err != nil {
+		t.Fatalf("Check(v2): %v", err)
+	}
+
+	var incompatible *IncompatibleError
+	if err := reg.Check(ctx, "users-value", For(reflect.TypeFor[v3]())); !errors.As(err, &incompatible) {
+		t.Fatalf("got Check(v3) = %v, want an IncompatibleError", err)
+	}
+	want := []string{"field id changed type from integer to string", "required field name was removed"}
+	if !reflect.DeepEqual(incompatible.Problems, want) {
+		t.Errorf("got problems %q, want %q", incompatible.Problems, want)
+	}
+
+	if got := len(fake.versions["users-value"]); got != 2 {
+		t.Errorf("got %d registered versions, want 2", got)
+	}
+	latest, err := reg.Latest(ctx, "users-value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(latest, For(reflect.TypeFor[v2]())) {
+		t.Errorf("got latest schema %+v, want the v2 schema", latest)
+	}
+}
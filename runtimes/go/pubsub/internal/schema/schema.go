@@ -0,0 +1,189 @@
+// Package schema derives JSON schemas for message types, and checks
+// changes to them for compatibility with existing consumers.
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Schema is the subset of JSON Schema describing how a Go type is encoded as JSON.
+// An empty Schema allows any value.
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	jsonMarshaler = reflect.TypeFor[json.Marshaler]()
+	textMarshaler = reflect.TypeFor[encoding.TextMarshaler]()
+	timeType      = reflect.TypeFor[time.Time]()
+)
+
+// For returns the schema of the JSON encoding of values of type t,
+// following the rules of encoding/json.
+func For(t reflect.Type) *Schema {
+	return (&builder{seen: make(map[reflect.Type]bool)}).build(t)
+}
+
+type builder struct {
+	seen map[reflect.Type]bool // struct types being built, to stop on recursive types
+}
+
+func (b *builder) build(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string"}
+	case t.Implements(jsonMarshaler) || reflect.PointerTo(t).Implements(jsonMarshaler):
+		return &Schema{} // can't tell how it's encoded
+	case t.Implements(textMarshaler) || reflect.PointerTo(t).Implements(textMarshaler):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string"} // base64 encoded
+		}
+		return &Schema{Type: "array", Items: b.build(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.build(t.Elem())}
+	case reflect.Struct:
+		if b.seen[t] {
+			return &Schema{}
+		}
+		b.seen[t] = true
+		defer delete(b.seen, t)
+
+		s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		b.addFields(s, t)
+		slices.Sort(s.Required)
+		return s
+	default:
+		return &Schema{}
+	}
+}
+
+// addFields adds the fields of the struct type t to s,
+// including the fields of embedded structs.
+func (b *builder) addFields(s *Schema, t reflect.Type) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				b.addFields(s, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		s.Properties[name] = b.build(f.Type)
+		optional := f.Type.Kind() == reflect.Pointer || slices.ContainsFunc(strings.Split(opts, ","), func(opt string) bool {
+			return opt == "omitempty" || opt == "omitzero"
+		})
+		if !optional {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// IncompatibleError is returned by Compatible for incompatible schema changes.
+type IncompatibleError struct {
+	Problems []string
+}
+
+func (e *IncompatibleError) Error() string {
+	return "incompatible schema change: " + strings.Join(e.Problems, "; ")
+}
+
+// Compatible reports whether consumers of messages with the prev schema can consume
+// messages with the next schema. If not, it returns an *IncompatibleError describing why.
+func Compatible(prev, next *Schema) error {
+	var problems []string
+	compare(&problems, "", prev, next)
+	if len(problems) == 0 {
+		return nil
+	}
+	return &IncompatibleError{Problems: problems}
+}
+
+func compare(problems *[]string, path string, prev, next *Schema) {
+	// Integers are valid numbers, but not the other way around.
+	if prev.Type != "" && prev.Type != next.Type && !(prev.Type == "number" && next.Type == "integer") {
+		nextType := next.Type
+		if nextType == "" {
+			nextType = "any"
+		}
+		*problems = append(*problems, fmt.Sprintf("%s changed type from %s to %s", describe(path), prev.Type, nextType))
+		return
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(prev.Properties)) {
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+		required := slices.Contains(prev.Required, name)
+		nextProp, ok := next.Properties[name]
+		switch {
+		case !ok && required:
+			*problems = append(*problems, fmt.Sprintf("required field %s was removed", fieldPath))
+		case ok && required && !slices.Contains(next.Required, name):
+			*problems = append(*problems, fmt.Sprintf("required field %s is no longer required", fieldPath))
+		}
+		if ok {
+			compare(problems, fieldPath, prev.Properties[name], nextProp)
+		}
+	}
+
+	if prev.Items != nil && next.Items != nil {
+		compare(problems, path+"[]", prev.Items, next.Items)
+	}
+	if prev.AdditionalProperties != nil && next.AdditionalProperties != nil {
+		compare(problems, path+"{}", prev.AdditionalProperties, next.AdditionalProperties)
+	}
+}
+
+func describe(path string) string {
+	if path == "" {
+		return "message"
+	}
+	return "field " + path
+}
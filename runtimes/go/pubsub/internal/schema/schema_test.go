@@ -0,0 +1,140 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+type address struct {
+	City string
+	Zip  *string `json:"zip"`
+}
+
+type base struct {
+	ID int64 `json:"id"`
+}
+
+type event struct {
+	base
+	Name      string            `json:"name"`
+	Email     string            `json:"email,omitempty"`
+	Score     float64           `json:"score"`
+	Tags      []string          `json:"tags"`
+	Data      []byte            `json:"data"`
+	Labels    map[string]int    `json:"labels"`
+	Address   *address          `json:"address"`
+	CreatedAt time.Time         `json:"created_at"`
+	Raw       json.RawMessage   `json:"raw"`
+	Ignored   string            `json:"-"`
+	internal  string            //nolint:unused
+	Next      *event            `json:"next"`
+	Extra     map[string]string `json:"extra,omitzero"`
+}
+
+func TestFor(t *testing.T) {
+	s := For(reflect.TypeFor[*event]())
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `{"type":"object","properties":{` +
+		`"address":{"type":"object","properties":{"City":{"type":"string"},"zip":{"type":"string"}},"required":["City"]},` +
+		`"created_at":{"type":"string"},` +
+		`"data":{"type":"string"},` +
+		`"email":{"type":"string"},` +
+		`"extra":{"type":"object","additionalProperties":{"type":"string"}},` +
+		`"id":{"type":"integer"},` +
+		`"labels":{"type":"object","additionalProperties":{"type":"integer"}},` +
+		`"name":{"type":"string"},` +
+		`"next":{},` +
+		`"raw":{},` +
+		`"score":{"type":"number"},` +
+		`"tags":{"type":"array","items":{"type":"string"}}},` +
+		`"required":["created_at","data","id","labels","name","raw","score","tags"]}`
+	if string(data) != want {
+		t.Errorf("got schema:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestCompatible(t *testing.T) {
+	type v1 struct {
+		ID    int64    `json:"id"`
+		Name  string   `json:"name"`
+		Score float64  `json:"score"`
+		Tags  []string `json:"tags"`
+		Note  string   `json:"note,omitempty"`
+	}
+
+	tests := []struct {
+		name     string
+		next     any
+		problems []string
+	}{
+		{
+			name: "unchanged",
+			next: v1{},
+		},
+		{
+			name: "added and removed optional fields",
+			next: struct {
+				ID    int64    `json:"id"`
+				Name  string   `json:"name"`
+				Score float64  `json:"score"`
+				Tags  []string `json:"tags"`
+				Added string   `json:"added"`
+			}{},
+		},
+		{
+			name: "narrowed number to integer",
+			next: struct {
+				ID    int64    `json:"id"`
+				Name  string   `json:"name"`
+				Score int      `json:"score"`
+				Tags  []string `json:"tags"`
+			}{},
+		},
+		{
+			name: "breaking changes",
+			next: struct {
+				ID    string  `json:"id"`
+				Name  *string `json:"name"`
+				Score float64 `json:"score"`
+				Tags  []int   `json:"tags"`
+			}{},
+			problems: []string{
+				"field id changed type from integer to string",
+				"required field name is no longer required",
+				"field tags[] changed type from string to integer",
+			},
+		},
+		{
+			name: "removed required field",
+			next: struct {
+				ID   int64    `json:"id"`
+				Name string   `json:"name"`
+				Tags []string `json:"tags"`
+			}{},
+			problems: []string{"required field score was removed"},
+		},
+	}
+
+	prev := For(reflect.TypeFor[v1]())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Compatible(prev, For(reflect.TypeOf(tt.next)))
+			var incompatible *IncompatibleError
+			if errors.As(err, &incompatible) {
+				if !slices.Equal(incompatible.Problems, tt.problems) {
+					t.Errorf("got problems %q, want %q", incompatible.Problems, tt.problems)
+				}
+			} else if err != nil || tt.problems != nil {
+				t.Errorf("got err %v, want problems %q", err, tt.problems)
+			}
+		})
+	}
+}
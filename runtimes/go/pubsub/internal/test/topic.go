@@ -39,10 +39,22 @@ func NewTopic[T any](ts *testsupport.Manager, name string) types.TopicImplementa
 	}
 }
 
+var _ types.DelayedPublisher = (*TestTopic[any])(nil)
+
 // PublishMessage will record the message against the test instance
 // and if subscribers are enabled for the test instance, it will also trigger
 // those subscribers. (The default behaviour is subscribers are disabled in tests)
 func (t *TestTopic[T]) PublishMessage(ctx context.Context, orderingKey string, attrs map[string]string, data []byte) (id string, err error) {
+	return t.publish(ctx, 0, attrs, data)
+}
+
+// PublishDelayedMessage records the message like PublishMessage,
+// but only triggers subscribers once the delay has passed.
+func (t *TestTopic[T]) PublishDelayedMessage(ctx context.Context, delay time.Duration, attrs map[string]string, data []byte) (id string, err error) {
+	return t.publish(ctx, delay, attrs, data)
+}
+
+func (t *TestTopic[T]) publish(ctx context.Context, delay time.Duration, attrs map[string]string, data []byte) (id string, err error) {
 	if err := ctx.Err(); err != nil {
 		return "", err
 	}
@@ -69,6 +81,15 @@ func (t *TestTopic[T]) PublishMessage(ctx context.Context, orderingKey string, a
 			name := name
 			sub := sub
 			t.ts.RunAsyncCodeInTest(test, func(ctx context.Context) {
+				if delay > 0 {
+					timer := time.NewTimer(delay)
+					defer timer.Stop()
+					select {
+					case <-timer.C:
+					case <-ctx.Done():
+						return
+					}
+				}
 				if err := sub(ctx, msgID, published, 1, attrs, data); err != nil {
 					test.Errorf("an error was returned while processing subscription %s for message %s: %s", name, msgID, err)
 					test.Fail()
@@ -17,3 +17,74 @@ type TopicImplementation interface {
 	PublishMessage(ctx context.Context, orderingKey string, attrs map[string]string, data []byte) (id string, err error)
 	Subscribe(logger *zerolog.Logger, maxConcurrency int, ackDeadline time.Duration, retryPolicy *RetryPolicy, implCfg *config.PubsubSubscription, f RawSubscriptionCallback)
 }
+
+// DelayedPublisher is implemented by topics that can delay
+// the delivery of messages to subscribers.
+type DelayedPublisher interface {
+	PublishDelayedMessage(ctx context.Context, delay time.Duration, attrs map[string]string, data []byte) (id string, err error)
+}
+
+// BatchMessage is a single message published as part of a batch.
+type BatchMessage struct {
+	OrderingKey string
+	Attrs       map[string]string
+	Data        []byte
+}
+
+// BatchResult is the result of publishing a single message in a batch.
+type BatchResult struct {
+	ID  string
+	Err error
+}
+
+// BatchPublisher is implemented by topics that can publish multiple
+// messages in a single call to the provider.
+//
+// PublishBatch must return exactly one result per message, in the same order.
+type BatchPublisher interface {
+	PublishBatch(ctx context.Context, msgs []BatchMessage) []BatchResult
+}
+
+// DeadLetter is a message that was dead-lettered by a subscription
+// after it ran out of retries.
+type DeadLetter struct {
+	ID              string
+	PublishTime     time.Time
+	DeliveryAttempt int
+	Attrs           map[string]string
+	Data            []byte
+}
+
+// DeadLetterQueue gives access to the messages dead-lettered by a subscription.
+type DeadLetterQueue interface {
+	// List returns the dead-lettered messages.
+	List(ctx context.Context) ([]*DeadLetter, error)
+
+	// Replay redelivers the dead-lettered messages with the given ids to the
+	// subscription, or all of them if ids is empty, and removes them from the queue.
+	// It returns the number of messages replayed.
+	Replay(ctx context.Context, ids []string) (int, error)
+
+	// Purge removes all dead-lettered messages, returning the number removed.
+	Purge(ctx context.Context) (int, error)
+}
+
+// DeadLetterer is implemented by topics that give access
+// to the dead-letter queues of their subscriptions.
+type DeadLetterer interface {
+	DeadLetterQueue(subCfg *config.PubsubSubscription) DeadLetterQueue
+}
+
+// DeliveryDeduplicator is implemented by topics whose provider can itself
+// guarantee exactly-once delivery to some of their subscriptions.
+type DeliveryDeduplicator interface {
+	// DeduplicatesDelivery reports whether the provider guarantees
+	// that messages aren't redelivered to the subscription once acked.
+	DeduplicatesDelivery(subCfg *config.PubsubSubscription) bool
+}
+
+// FlowController is implemented by topics that can change how many
+// messages a running subscription receives ahead of processing them.
+type FlowController interface {
+	SetPrefetch(subCfg *config.PubsubSubscription, prefetch int) error
+}
@@ -128,7 +128,8 @@ type TopicConfig struct {
 	// - AWS: 300 messages per second for the topic (see [AWS SQS Quotas]).
 	// - GCP: 1MB/s for each ordering key (see [GCP PubSub Quotas]).
 	//
-	// Note: OrderingAttribute currently has no effect during local development.
+	// During local development, ordering is simulated by processing messages
+	// with the same ordering key one at a time, in the order they're received.
 	//
 	// [AWS SQS Quotas]: https://docs.aws.amazon.com/AWSSimpleQueueService/latest/SQSDeveloperGuide/quotas-messages.html
 	// [GCP PubSub Quotas]: https://cloud.google.com/pubsub/quotas#resource_limits
@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DedupStatus is the result of Deduplicator.Start.
+type DedupStatus int
+
+const (
+	// Process means the message should be processed.
+	Process DedupStatus = iota
+	// Duplicate means the message was already processed, and should be acked.
+	Duplicate
+	// InFlight means the message is being processed by another delivery.
+	InFlight
+)
+
+// Deduplicator keeps track of the ids of recently processed messages,
+// so that redeliveries of them can be skipped.
+//
+// It is kept in memory, so it only deduplicates redeliveries
+// to the same instance of the service, and forgets them on restart.
+// It's a best-effort complement to the provider's own deduplication,
+// not a guarantee of exactly-once processing.
+type Deduplicator struct {
+	window     time.Duration // how long to remember processed messages for
+	maxEntries int           // the most processed messages to remember
+
+	mu        sync.Mutex
+	inFlight  map[string]chan struct{} // closed when the message is no longer in flight
+	processed map[string]time.Time     // message id -> when it expires
+	order     []string                 // processed message ids, oldest first
+}
+
+func NewDeduplicator(window time.Duration, maxEntries int) *Deduplicator {
+	return &Deduplicator{
+		window:     window,
+		maxEntries: maxEntries,
+		inFlight:   make(map[string]chan struct{}),
+		processed:  make(map[string]time.Time),
+	}
+}
+
+// Start reports whether the message with the given id should be processed.
+// If so, it's marked as in flight until Finish is called.
+func (d *Deduplicator) Start(id string) DedupStatus {
+	status, _ := d.start(id)
+	return status
+}
+
+// Acquire is like Start, but if the message is being processed by another
+// delivery it waits for that delivery to finish, and reports whether the
+// message should then be processed.
+//
+// A message is redelivered while it's being processed when processing it takes
+// longer than the ack deadline. Waiting for it rather than failing the redelivery
+// means it isn't counted as a failed delivery attempt, which could otherwise
+// move a message that's being processed successfully to the dead-letter queue.
+func (d *Deduplicator) Acquire(ctx context.Context, id string) (DedupStatus, error) {
+	for {
+		status, done := d.start(id)
+		if status != InFlight {
+			return status, nil
+		}
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return InFlight, ctx.Err()
+		}
+	}
+}
+
+// start is like Start, but also returns a channel that's closed
+// when the message is no longer in flight if it's InFlight.
+func (d *Deduplicator) start(id string) (DedupStatus, <-chan struct{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.evict(time.Now())
+
+	if _, ok := d.processed[id]; ok {
+		return Duplicate, nil
+	} else if done, ok := d.inFlight[id]; ok {
+		return InFlight, done
+	}
+	d.inFlight[id] = make(chan struct{})
+	return Process, nil
+}
+
+// Finish marks the message with the given id as no longer in flight,
+// and remembers it as processed if it was processed successfully.
+func (d *Deduplicator) Finish(id string, processed bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if done, ok := d.inFlight[id]; ok {
+		close(done)
+		delete(d.inFlight, id)
+	}
+	if !processed {
+		return
+	}
+
+	now := time.Now()
+	d.evict(now)
+	if len(d.order) >= d.maxEntries {
+		delete(d.processed, d.order[0])
+		d.order = d.order[1:]
+	}
+	d.processed[id] = now.Add(d.window)
+	d.order = append(d.order, id)
+}
+
+// evict forgets the processed messages that have expired.
+func (d *Deduplicator) evict(now time.Time) {
+	n := 0
+	for n < len(d.order) && !now.Before(d.processed[d.order[n]]) {
+		delete(d.processed, d.order[n])
+		n++
+	}
+	if n > 0 {
+		d.order = d.order[n:]
+	}
+}
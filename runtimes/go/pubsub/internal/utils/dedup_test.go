@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDeduplicator(t *testing.T) {
+	d := NewDeduplicator(50*time.Millisecond, 2)
+
+	// A message being processed can't be processed concurrently.
+	Assert(t, d.Start("a"), Equals, Process)
+	Assert(t, d.Start("a"), Equals, InFlight)
+
+	// Failed messages can be processed again.
+	d.Finish("a", false)
+	Assert(t, d.Start("a"), Equals, Process)
+
+	// Processed messages are duplicates.
+	d.Finish("a", true)
+	Assert(t, d.Start("a"), Equals, Duplicate)
+
+	// Only maxEntries processed messages are remembered.
+	Assert(t, d.Start("b"), Equals, Process)
+	d.Finish("b", true)
+	Assert(t, d.Start("c"), Equals, Process)
+	d.Finish("c", true)
+	Assert(t, d.Start("a"), Equals, Process)
+	Assert(t, d.Start("b"), Equals, Duplicate)
+	d.Finish("a", false)
+
+	// Processed messages are forgotten once the window has passed.
+	time.Sleep(60 * time.Millisecond)
+	Assert(t, d.Start("b"), Equals, Process)
+	Assert(t, d.Start("c"), Equals, Process)
+}
+
+func TestDeduplicatorAcquire(t *testing.T) {
+	d := NewDeduplicator(time.Minute, 10)
+	const maxRetries = 1
+
+	// deliver delivers the message like a provider would, processing it with
+	// Acquire and dead-lettering it if its last allowed attempt fails.
+	var (
+		mu           sync.Mutex
+		processed    int
+		deadLettered bool
+	)
+	started, release := make(chan struct{}, 2), make(chan struct{})
+	deliver := func(ctx context.Context, attempt int) error {
+		err := func() (err error) {
+			if status, err := d.Acquire(ctx, "a"); err != nil {
+				return err
+			} else if status == Duplicate {
+				return nil
+			}
+			defer func() { d.Finish("a", err == nil) }()
+			mu.Lock()
+			processed++
+			mu.Unlock()
+			started <- struct{}{}
+			<-release
+			return nil
+		}()
+		if err != nil && attempt > maxRetries {
+			mu.Lock()
+			deadLettered = true
+			mu.Unlock()
+		}
+		return err
+	}
+
+	// Redeliver the message while the first delivery is still being processed,
+	// as happens when processing it takes longer than the ack deadline.
+	first := make(chan error, 1)
+	go func() { first <- deliver(context.Background(), 1) }()
+	<-started
+	second := make(chan error, 1)
+	go func() { second <- deliver(context.Background(), 2) }()
+
+	// The redelivery waits for the first delivery rather than failing.
+	select {
+	case err := <-second:
+		t.Fatalf("redelivery finished with %v while the message was in flight, want it to wait", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	Assert(t, <-first, IsNil)
+	Assert(t, <-second, IsNil)
+	Assert(t, processed, Equals, 1)
+	Assert(t, !deadLettered, IsTrue)
+
+	// A redelivery of a message whose processing failed processes it again.
+	Assert(t, d.Start("b"), Equals, Process)
+	ctx, cancel := context.WithCancel(context.Background())
+	status := make(chan DedupStatus, 1)
+	go func() {
+		s, _ := d.Acquire(ctx, "b")
+		status <- s
+	}()
+	d.Finish("b", false)
+	Assert(t, <-status, Equals, Process)
+	d.Finish("b", true)
+
+	// Waiting stops when the context is done.
+	Assert(t, d.Start("c"), Equals, Process)
+	cancel()
+	_, err := d.Acquire(ctx, "c")
+	Assert(t, errors.Is(err, context.Canceled), IsTrue)
+}
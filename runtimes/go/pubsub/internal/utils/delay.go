@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// DeliverAtAttribute is set on messages published with a delivery delay to
+// providers that can't delay messages themselves, to when the message is due.
+// Subscribers defer processing the message until then.
+const DeliverAtAttribute = "encore_deliver_at"
+
+// SetDeliverAt records in attrs that the message is due once delay has passed.
+func SetDeliverAt(attrs map[string]string, delay time.Duration) {
+	attrs[DeliverAtAttribute] = time.Now().Add(delay).UTC().Format(time.RFC3339Nano)
+}
+
+// DeliverAt returns when the message with the given attributes is due,
+// or the zero time if it wasn't published with a delivery delay.
+func DeliverAt(attrs map[string]string) time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, attrs[DeliverAtAttribute])
+	return t
+}
+
+// Deferrals counts how many times messages have been redelivered before they
+// were due, so those redeliveries can be left out of the delivery attempt
+// passed to the subscription handler.
+//
+// It is kept in memory, so it only counts the deferrals made by
+// the same instance of the service.
+type Deferrals struct {
+	mu      sync.Mutex
+	entries map[string]*deferral // message id -> deferrals
+}
+
+type deferral struct {
+	count     int
+	deliverAt time.Time
+}
+
+func NewDeferrals() *Deferrals {
+	return &Deferrals{entries: make(map[string]*deferral)}
+}
+
+// Defer records that the message with the given id was redelivered
+// before it was due at deliverAt.
+func (d *Deferrals) Defer(id string, deliverAt time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Forget messages that have long been due, as they were
+	// most likely processed by another instance.
+	now := time.Now()
+	for msgID, e := range d.entries {
+		if now.Sub(e.deliverAt) > time.Hour {
+			delete(d.entries, msgID)
+		}
+	}
+
+	e := d.entries[id]
+	if e == nil {
+		e = &deferral{deliverAt: deliverAt}
+		d.entries[id] = e
+	}
+	e.count++
+}
+
+// Attempt returns the delivery attempt of the message with the given id
+// without the times it was deferred, and forgets about the message.
+func (d *Deferrals) Attempt(id string, attempt int) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if e := d.entries[id]; e != nil {
+		delete(d.entries, id)
+		attempt = max(attempt-e.count, 1)
+	}
+	return attempt
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeliverAt(t *testing.T) {
+	// Messages without a delay are due immediately.
+	Assert(t, DeliverAt(map[string]string{}).IsZero(), IsTrue)
+
+	attrs := map[string]string{}
+	SetDeliverAt(attrs, time.Hour)
+	due := time.Until(DeliverAt(attrs))
+	Assert(t, due > 59*time.Minute && due <= time.Hour, IsTrue)
+}
+
+func TestDeferrals(t *testing.T) {
+	d := NewDeferrals()
+	deliverAt := time.Now().Add(time.Minute)
+
+	// Messages that weren't deferred keep their delivery attempt.
+	Assert(t, d.Attempt("a", 3), Equals, 3)
+
+	d.Defer("a", deliverAt)
+	d.Defer("a", deliverAt)
+	Assert(t, d.Attempt("a", 3), Equals, 1)
+
+	// The message is forgotten once its attempt has been returned.
+	Assert(t, d.Attempt("a", 3), Equals, 3)
+
+	// The attempt is never less than 1, even if deferrals
+	// were counted for an earlier delivery of the message.
+	d.Defer("b", deliverAt)
+	d.Defer("b", deliverAt)
+	Assert(t, d.Attempt("b", 1), Equals, 1)
+
+	// Messages that have long been due are forgotten.
+	d.Defer("c", time.Now().Add(-2*time.Hour))
+	d.Defer("d", deliverAt)
+	Assert(t, d.Attempt("c", 2), Equals, 2)
+	Assert(t, d.Attempt("d", 2), Equals, 1)
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"context"
+	"sync"
+)
+
+// Limiter limits the number of operations running concurrently,
+// to a limit that can be changed while it's in use.
+type Limiter struct {
+	mu      sync.Mutex
+	limit   int           // <= 0 means unlimited
+	active  int           // the number of running operations
+	changed chan struct{} // closed when an operation finishes or the limit changes
+}
+
+func NewLimiter(limit int) *Limiter {
+	return &Limiter{limit: limit, changed: make(chan struct{})}
+}
+
+// Acquire waits until the operation can run, or ctx is done.
+// Release must be called once the operation has finished.
+func (l *Limiter) Acquire(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if l.limit <= 0 || l.active < l.limit {
+			l.active++
+			l.mu.Unlock()
+			return nil
+		}
+		changed := l.changed
+		l.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Release marks an operation started with Acquire as finished.
+func (l *Limiter) Release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active--
+	l.notify()
+}
+
+// Limit returns the current limit.
+func (l *Limiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// SetLimit changes the limit. Running operations are unaffected when
+// it's lowered, but no new ones start until they're below the new limit.
+func (l *Limiter) SetLimit(limit int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = limit
+	l.notify()
+}
+
+// notify wakes up the operations waiting in Acquire.
+// It must be called with l.mu held.
+func (l *Limiter) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
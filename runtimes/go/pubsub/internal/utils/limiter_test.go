@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+	l := NewLimiter(1)
+	Assert(t, l.Acquire(ctx), IsNil)
+
+	// Operations beyond the limit wait for a running one to be released.
+	acquired := make(chan error, 1)
+	go func() { acquired <- l.Acquire(ctx) }()
+	select {
+	case <-acquired:
+		t.Fatal("acquired beyond the limit")
+	case <-time.After(20 * time.Millisecond):
+	}
+	l.Release()
+	Assert(t, <-acquired, IsNil)
+
+	// Raising the limit lets waiting operations run.
+	go func() { acquired <- l.Acquire(ctx) }()
+	time.Sleep(10 * time.Millisecond)
+	l.SetLimit(2)
+	Assert(t, <-acquired, IsNil)
+	Assert(t, l.Limit(), Equals, 2)
+
+	// Waiting stops when the context is done.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	Assert(t, l.Acquire(timeoutCtx), Equals, context.DeadlineExceeded)
+
+	// A limit of zero or less is unlimited.
+	l.SetLimit(0)
+	Assert(t, l.Acquire(ctx), IsNil)
+}
@@ -0,0 +1,13 @@
+package utils
+
+// TargetSubscriptionAttribute is set on messages that are only meant for a
+// single subscription, such as when they're replayed from its dead-letter queue,
+// to the Encore name of that subscription. Other subscriptions skip them.
+const TargetSubscriptionAttribute = "encore_target_subscription"
+
+// ForOtherSubscription reports whether the message with the given attributes
+// is only meant for a subscription other than the one with the given name.
+func ForOtherSubscription(attrs map[string]string, subscription string) bool {
+	target := attrs[TargetSubscriptionAttribute]
+	return target != "" && target != subscription
+}
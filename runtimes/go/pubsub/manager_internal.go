@@ -29,6 +29,9 @@ type Manager struct {
 
 	publishCounter  uint64
 	pushHandlers    map[types.SubscriptionID]http.HandlerFunc
+	subsMu          sync.Mutex // protects deadLetters and flows
+	deadLetters     map[subscriptionKey]types.DeadLetterQueue
+	flows           map[subscriptionKey]*subscriptionFlow
 	runningFetches  sync.WaitGroup
 	runningHandlers sync.WaitGroup
 }
@@ -44,6 +47,8 @@ func NewManager(static *config.Static, runtime *config.Runtime, rt *reqtrack.Req
 		rootLogger:   rootLogger,
 		json:         json,
 		pushHandlers: make(map[types.SubscriptionID]http.HandlerFunc),
+		deadLetters:  make(map[subscriptionKey]types.DeadLetterQueue),
+		flows:        make(map[subscriptionKey]*subscriptionFlow),
 	}
 
 	for _, p := range providerRegistry {
@@ -53,6 +58,11 @@ func NewManager(static *config.Static, runtime *config.Runtime, rt *reqtrack.Req
 	return mgr
 }
 
+// subscriptionKey identifies a subscription to a topic.
+type subscriptionKey struct {
+	topic, subscription string
+}
+
 // Shutdown stops the manager from fetching new messages and processing them.
 func (mgr *Manager) Shutdown(p *shutdown.Process) error {
 	// Once it's time to force-close tasks, cancel the base context.
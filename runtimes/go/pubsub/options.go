@@ -0,0 +1,44 @@
+package pubsub
+
+import "time"
+
+// PublishOption describes available options for the Publish operation.
+type PublishOption interface {
+	//publicapigen:keep
+	publishOption()
+
+	applyPublish(*publishOptions)
+}
+
+type publishOptions struct {
+	delay time.Duration
+}
+
+// WithDeliveryDelay is a PublishOption for delaying the delivery of the message
+// to subscribers until at least d has passed, such as for retrying something
+// later or sending reminders, without needing a separate scheduler.
+//
+// Delays are supported by NSQ (used when running locally) and Azure Service Bus natively.
+// On AWS, subscribers send the message back to their SQS queue with a delay
+// of up to 15 minutes at a time until it's due, which requires them to be allowed
+// to send messages to the queue. On GCP, where Pub/Sub has no delayed delivery,
+// subscribers nack the message until it's due, so it's redelivered according to
+// the subscription's retry policy and each redelivery counts towards its dead-letter
+// policy. Delays on GCP are therefore limited to one hour. In tests, subscribers
+// are called once the delay has passed, if enabled.
+//
+// Delays can't be used with topics that have an OrderingAttribute,
+// nor with topics with exactly-once delivery on AWS.
+func WithDeliveryDelay(d time.Duration) withDeliveryDelayOption {
+	return withDeliveryDelayOption{delay: d}
+}
+
+//publicapigen:keep
+type withDeliveryDelayOption struct {
+	delay time.Duration
+}
+
+//publicapigen:keep
+func (o withDeliveryDelayOption) publishOption() {}
+
+func (o withDeliveryDelayOption) applyPublish(opts *publishOptions) { opts.delay = o.delay }
@@ -22,7 +22,7 @@ type TopicPerms[T any] interface {
 // to Encore's static analysis restrictions that apply to MyTopic.
 type Publisher[T any] interface {
 	// Publish publishes a message to the topic.
-	Publish(ctx context.Context, msg T) (id string, err error)
+	Publish(ctx context.Context, msg T, options ...PublishOption) (id string, err error)
 
 	// Meta returns metadata about the topic.
 	Meta() TopicMeta
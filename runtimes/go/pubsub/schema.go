@@ -0,0 +1,71 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+
+	"encore.dev/appruntime/exported/config"
+	"encore.dev/beta/errs"
+	"encore.dev/pubsub/internal/schema"
+)
+
+// schemaCheck validates a topic's message schema against its schema registry,
+// once per running instance.
+type schemaCheck struct {
+	topic    string
+	subject  string
+	schema   *schema.Schema
+	registry *schema.Registry
+
+	mu   sync.Mutex
+	done bool  // whether the check has completed
+	err  error // the result of the check, if done
+}
+
+// newSchemaCheck returns the schema check for messages of type T published to the topic,
+// or nil if the topic has no schema registry configured.
+func newSchemaCheck[T any](cfg *config.PubsubTopic) *schemaCheck {
+	if cfg.SchemaRegistry == nil || cfg.SchemaRegistry.URL == "" {
+		return nil
+	}
+	subject := cfg.SchemaRegistry.Subject
+	if subject == "" {
+		subject = cfg.EncoreName + "-value"
+	}
+	return &schemaCheck{
+		topic:    cfg.EncoreName,
+		subject:  subject,
+		schema:   schema.For(reflect.TypeFor[T]()),
+		registry: &schema.Registry{URL: cfg.SchemaRegistry.URL},
+	}
+}
+
+// check checks the message schema is compatible with the latest registered version,
+// registering it if so. Incompatible schemas are refused for the lifetime of the
+// instance, while failures to reach the registry are retried on the next call.
+func (c *schemaCheck) check(ctx context.Context) error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.done {
+		return c.err
+	}
+
+	err := c.registry.Check(ctx, c.subject, c.schema)
+	var incompatible *schema.IncompatibleError
+	switch {
+	case errors.As(err, &incompatible):
+		c.done = true
+		c.err = errs.B().Cause(err).Code(errs.FailedPrecondition).Msgf("message schema for topic %s is incompatible with the registered schema", c.topic).Err()
+		return c.err
+	case err != nil:
+		return errs.B().Cause(err).Code(errs.Unavailable).Msgf("failed to validate message schema for topic %s", c.topic).Err()
+	}
+	c.done = true
+	return nil
+}
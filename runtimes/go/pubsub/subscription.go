@@ -13,15 +13,25 @@ import (
 	"encore.dev/appruntime/shared/cfgutil"
 	"encore.dev/beta/errs"
 	"encore.dev/pubsub/internal/noop"
+	"encore.dev/pubsub/internal/types"
 	"encore.dev/pubsub/internal/utils"
 )
 
+// dedupWindow and dedupMaxEntries bound how long, and how many, processed
+// messages are remembered for subscriptions deduplicated by Encore.
+const (
+	dedupWindow     = 15 * time.Minute
+	dedupMaxEntries = 100_000
+)
+
 // Subscription represents a subscription to a Topic.
 type Subscription[T any] struct {
 	topic *Topic[T]
 	name  string
 	cfg   SubscriptionConfig[T]
 	mgr   *Manager
+	dlq   types.DeadLetterQueue // nil if not supported
+	flow  *subscriptionFlow     // nil if not running on this instance
 }
 
 // NewSubscription is used to declare a Subscription to a topic. The passed in handler will be called
@@ -119,11 +129,49 @@ func NewSubscription[T any](topic *Topic[T], name string, cfg SubscriptionConfig
 		Str("subscription", name).
 		Logger()
 
+	// Limit the concurrency ourselves as well, so it can be changed while running.
+	limit := cfg.MaxConcurrency
+	if limit <= 0 {
+		limit = -1 // only limited by the provider
+	}
+	flow := &subscriptionFlow{limiter: utils.NewLimiter(limit)}
+	if fc, ok := topic.topic.(types.FlowController); ok {
+		flow.setPrefetch = func(prefetch int) error { return fc.SetPrefetch(subscription, prefetch) }
+	}
+
+	// Deduplicate redeliveries ourselves if the provider doesn't.
+	// That's only done per instance, so make sure it's not mistaken for a guarantee.
+	var dedup *utils.Deduplicator
+	if cfg.AckDeduplication {
+		if d, ok := topic.topic.(types.DeliveryDeduplicator); !ok || !d.DeduplicatesDelivery(subscription) {
+			log.Warn().Msg("the provider doesn't support exactly-once delivery for this subscription; " +
+				"redeliveries are only deduplicated per instance on a best-effort basis, so the handler must be idempotent")
+			dedup = utils.NewDeduplicator(dedupWindow, dedupMaxEntries)
+		}
+	}
+
 	// Subscribe to the topic
 	topic.topic.Subscribe(&log, cfg.MaxConcurrency, cfg.AckDeadline, cfg.RetryPolicy, subscription, func(ctx context.Context, msgID string, publishTime time.Time, deliveryAttempt int, attrs map[string]string, data []byte) (err error) {
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
+
+		if err := flow.limiter.Acquire(ctx); err != nil {
+			return err
+		}
+		defer flow.limiter.Release()
+
+		if dedup != nil {
+			// Wait for any delivery of the message that's still being processed,
+			// rather than failing this one, which would count as a failed attempt.
+			if status, err := dedup.Acquire(ctx, msgID); err != nil {
+				return err
+			} else if status == utils.Duplicate {
+				log.Debug().Str("msg_id", msgID).Int("delivery_attempt", deliveryAttempt).Msg("skipping already processed message")
+				return nil
+			}
+			defer func() { dedup.Finish(msgID, err == nil) }()
+		}
 		mgr.runningHandlers.Add(1)
 		defer mgr.runningHandlers.Done()
 
@@ -247,7 +295,14 @@ func NewSubscription[T any](topic *Topic[T], name string, cfg SubscriptionConfig
 		log.Trace().Msg("registered subscription")
 	}
 
-	return &Subscription[T]{topic: topic, name: name, cfg: cfg, mgr: mgr}
+	var dlq types.DeadLetterQueue
+	if d, ok := topic.topic.(types.DeadLetterer); ok {
+		dlq = d.DeadLetterQueue(subscription)
+		mgr.registerDeadLetterQueue(topic.runtimeCfg.EncoreName, name, dlq)
+	}
+	mgr.registerFlow(topic.runtimeCfg.EncoreName, name, flow)
+
+	return &Subscription[T]{topic: topic, name: name, cfg: cfg, mgr: mgr, dlq: dlq, flow: flow}
 }
 
 // SubscriptionMeta contains metadata about a subscription.
@@ -3,11 +3,13 @@ package pubsub
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"encore.dev/appruntime/exported/config"
 	"encore.dev/appruntime/exported/model"
 	"encore.dev/appruntime/exported/stack"
 	"encore.dev/appruntime/exported/trace2"
+	"encore.dev/appruntime/shared/reqtrack"
 	"encore.dev/beta/errs"
 	"encore.dev/internal/limiter"
 	"encore.dev/pubsub/internal/noop"
@@ -29,6 +31,7 @@ type Topic[T any] struct {
 	runtimeCfg     *config.PubsubTopic // The config for this running instance of the application
 	topic          types.TopicImplementation
 	publishLimiter limiter.Limiter
+	schemaCheck    *schemaCheck // nil if the topic has no schema registry
 }
 
 func newTopic[T any](mgr *Manager, name string, cfg TopicConfig) *Topic[T] {
@@ -72,6 +75,7 @@ func newTopic[T any](mgr *Manager, name string, cfg TopicConfig) *Topic[T] {
 				runtimeCfg:     topic,
 				topic:          impl,
 				publishLimiter: limiter.New(topic.Limiter),
+				schemaCheck:    newSchemaCheck[T](topic),
 			}
 		}
 		tried = append(tried, p.ProviderName())
@@ -106,7 +110,13 @@ func (t *Topic[T]) Meta() TopicMeta {
 //
 // If an error is returned, it is probable that the message failed to be published, however it is possible
 // that the message could still be received by subscriptions to the topic.
-func (t *Topic[T]) Publish(ctx context.Context, msg T) (id string, err error) {
+//
+// To delay the delivery of the message to subscribers, use WithDeliveryDelay.
+//
+// If the topic has a schema registry configured, the message type's schema is
+// checked against the latest registered version before the first publish, and
+// an error with code FailedPrecondition is returned if the change is incompatible.
+func (t *Topic[T]) Publish(ctx context.Context, msg T, options ...PublishOption) (id string, err error) {
 	if ctx.Err() != nil {
 		return "", ctx.Err()
 	}
@@ -115,16 +125,173 @@ func (t *Topic[T]) Publish(ctx context.Context, msg T) (id string, err error) {
 		return "", errs.B().Code(errs.Unimplemented).Msg("pubsub topic was not created using pubsub.NewTopic").Err()
 	}
 
+	var opt publishOptions
+	for _, o := range options {
+		o.applyPublish(&opt)
+	}
+	delayed, err := t.delayedPublisher(opt.delay)
+	if err != nil {
+		return "", err
+	}
+	if err := t.schemaCheck.check(ctx); err != nil {
+		return "", err
+	}
+
+	out, err := t.marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	// Start the trace span
+	curr := t.mgr.rt.Current()
+	startEventID := t.traceStart(curr, out.data, stack.Build(1))
+
+	// Publish once the rate limiter allows it
+	if err = t.publishLimiter.Wait(ctx); err == nil {
+		// Publish to the clouds topic
+		if delayed != nil {
+			id, err = delayed.PublishDelayedMessage(ctx, opt.delay, out.attrs, out.data)
+		} else {
+			id, err = t.topic.PublishMessage(ctx, out.orderingKey, out.attrs, out.data)
+		}
+	}
+
+	// End the trace span
+	t.traceEnd(curr, startEventID, id, err)
+
+	if err != nil {
+		return "", errs.B().Cause(err).Code(errs.Unavailable).Msgf("failed to publish message to %s", t.runtimeCfg.EncoreName).Err()
+	}
+
+	return id, nil
+}
+
+// PublishResult is the result of publishing a single message with PublishBatch.
+type PublishResult struct {
+	// ID is the unique ID of the published message, if it was published successfully.
+	ID string
+
+	// Err is the error publishing the message, if any.
+	Err error
+}
+
+// PublishBatch publishes multiple messages to the topic, batching them into as few
+// calls to the provider as possible, which reduces the latency and cost of publishing
+// many messages at once compared to calling Publish for each one.
+//
+// It returns one PublishResult per message, in the same order as msgs.
+// Messages in the batch can fail independently of each other, so each
+// result must be checked. The returned error is only non-nil if the batch
+// as a whole could not be published, such as if ctx is canceled.
+//
+// Batches are sent using SNS's PublishBatch on AWS, the client's batching on GCP,
+// and a multi-publish on NSQ (used when running locally). Other providers
+// publish the messages one at a time.
+func (t *Topic[T]) PublishBatch(ctx context.Context, msgs ...T) ([]PublishResult, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if t.runtimeCfg == nil || t.topic == nil {
+		return nil, errs.B().Code(errs.Unimplemented).Msg("pubsub topic was not created using pubsub.NewTopic").Err()
+	}
+	if err := t.schemaCheck.check(ctx); err != nil {
+		return nil, err
+	}
+
+	results := make([]PublishResult, len(msgs))
+	batch := make([]types.BatchMessage, 0, len(msgs))
+	idx := make([]int, 0, len(msgs)) // index in msgs of each message in batch
+	for i, msg := range msgs {
+		out, err := t.marshal(msg)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		batch = append(batch, types.BatchMessage{OrderingKey: out.orderingKey, Attrs: out.attrs, Data: out.data})
+		idx = append(idx, i)
+	}
+	if len(batch) == 0 {
+		return results, nil
+	}
+
+	// Start the trace spans
+	curr := t.mgr.rt.Current()
+	stk := stack.Build(1)
+	startEventIDs := make([]trace2.EventID, len(batch))
+	for i, msg := range batch {
+		startEventIDs[i] = t.traceStart(curr, msg.Data, stk)
+	}
+
+	// Publish once the rate limiter allows all the messages
+	var batchResults []types.BatchResult
+	var err error
+	for range batch {
+		if err = t.publishLimiter.Wait(ctx); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		batchResults = t.publishBatch(ctx, batch)
+	}
+
+	// End the trace spans
+	for i := range batch {
+		var id string
+		msgErr := err
+		if batchResults != nil {
+			id, msgErr = batchResults[i].ID, batchResults[i].Err
+		}
+		t.traceEnd(curr, startEventIDs[i], id, msgErr)
+	}
+
+	if err != nil {
+		return nil, errs.B().Cause(err).Code(errs.Unavailable).Msgf("failed to publish messages to %s", t.runtimeCfg.EncoreName).Err()
+	}
+
+	for i, res := range batchResults {
+		if res.Err != nil {
+			res.Err = errs.B().Cause(res.Err).Code(errs.Unavailable).Msgf("failed to publish message to %s", t.runtimeCfg.EncoreName).Err()
+		}
+		results[idx[i]] = PublishResult{ID: res.ID, Err: res.Err}
+	}
+	return results, nil
+}
+
+// publishBatch publishes msgs using the provider's batch publishing if it has any,
+// or one at a time otherwise.
+func (t *Topic[T]) publishBatch(ctx context.Context, msgs []types.BatchMessage) []types.BatchResult {
+	if b, ok := t.topic.(types.BatchPublisher); ok {
+		return b.PublishBatch(ctx, msgs)
+	}
+
+	results := make([]types.BatchResult, len(msgs))
+	for i, msg := range msgs {
+		results[i].ID, results[i].Err = t.topic.PublishMessage(ctx, msg.OrderingKey, msg.Attrs, msg.Data)
+	}
+	return results
+}
+
+// outgoingMessage is a message marshalled for publishing to the topic.
+type outgoingMessage struct {
+	attrs       map[string]string
+	data        []byte
+	orderingKey string
+}
+
+// marshal extracts the attributes and ordering key of msg and marshals it
+// to JSON, adding the attributes used to correlate it with the current request.
+func (t *Topic[T]) marshal(msg T) (*outgoingMessage, error) {
 	// Extract the message attributes
 	attrs, err := utils.MarshalFields(msg, utils.AttrTag)
 	if err != nil {
-		return "", errs.B().Cause(err).Code(errs.InvalidArgument).Msgf("failed to extract message attributes for topic %s", t.runtimeCfg.EncoreName).Err()
+		return nil, errs.B().Cause(err).Code(errs.InvalidArgument).Msgf("failed to extract message attributes for topic %s", t.runtimeCfg.EncoreName).Err()
 	}
 
 	// Marshal the message to JSON
 	data, err := json.Marshal(msg)
 	if err != nil {
-		return "", errs.B().Cause(err).Code(errs.InvalidArgument).Msgf("failed to marshal message to JSON for topic %s", t.runtimeCfg.EncoreName).Err()
+		return nil, errs.B().Cause(err).Code(errs.InvalidArgument).Msgf("failed to marshal message to JSON for topic %s", t.runtimeCfg.EncoreName).Err()
 	}
 
 	// Add the ordering attribute if it is set
@@ -133,11 +300,11 @@ func (t *Topic[T]) Publish(ctx context.Context, msg T) (id string, err error) {
 		value, found := attrs[t.appCfg.OrderingAttribute]
 		if !found {
 			// This is checked statically, so this should never happen
-			return "", errs.B().Code(errs.InvalidArgument).Msgf("ordering attribute %s not found in message for topic %s", t.appCfg.OrderingAttribute, t.runtimeCfg.EncoreName).Err()
+			return nil, errs.B().Code(errs.InvalidArgument).Msgf("ordering attribute %s not found in message for topic %s", t.appCfg.OrderingAttribute, t.runtimeCfg.EncoreName).Err()
 		}
 
 		if value == "" {
-			return "", errs.B().Code(errs.InvalidArgument).Msgf("ordering attribute %s cannot be an empty string for topic %s", t.appCfg.OrderingAttribute, t.runtimeCfg.EncoreName).Err()
+			return nil, errs.B().Code(errs.InvalidArgument).Msgf("ordering attribute %s cannot be an empty string for topic %s", t.appCfg.OrderingAttribute, t.runtimeCfg.EncoreName).Err()
 		}
 
 		orderingKey = value
@@ -167,51 +334,63 @@ func (t *Topic[T]) Publish(ctx context.Context, msg T) (id string, err error) {
 		}
 	}
 
-	// Start the trace span
-	curr := t.mgr.rt.Current()
-	var startEventID trace2.EventID
-	if curr.Req != nil && curr.Trace != nil {
-		desc := &model.PubSubTopicDesc{
-			Topic: t.runtimeCfg.EncoreName,
-		}
-		if t.staticCfg != nil {
-			desc.ScrubPaths = t.staticCfg.ScrubPaths
-		}
-		startEventID = curr.Trace.PubsubPublishStart(trace2.PubsubPublishStartParams{
-			EventParams: trace2.EventParams{
-				TraceID: curr.Req.TraceID,
-				SpanID:  curr.Req.SpanID,
-				Goid:    curr.Goctr,
-			},
-			Desc:    desc,
-			Message: data,
-			Stack:   stack.Build(1),
-		})
-	}
+	return &outgoingMessage{attrs: attrs, data: data, orderingKey: orderingKey}, nil
+}
 
-	// Publish once the rate limiter allows it
-	if err = t.publishLimiter.Wait(ctx); err == nil {
-		// Publish to the clouds topic
-		id, err = t.topic.PublishMessage(ctx, orderingKey, attrs, data)
+// traceStart starts a trace span for publishing data, if the current request is traced.
+func (t *Topic[T]) traceStart(curr reqtrack.Current, data []byte, stack stack.Stack) trace2.EventID {
+	if curr.Req == nil || curr.Trace == nil {
+		return 0
+	}
+	desc := &model.PubSubTopicDesc{
+		Topic: t.runtimeCfg.EncoreName,
+	}
+	if t.staticCfg != nil {
+		desc.ScrubPaths = t.staticCfg.ScrubPaths
 	}
+	return curr.Trace.PubsubPublishStart(trace2.PubsubPublishStartParams{
+		EventParams: trace2.EventParams{
+			TraceID: curr.Req.TraceID,
+			SpanID:  curr.Req.SpanID,
+			Goid:    curr.Goctr,
+		},
+		Desc:    desc,
+		Message: data,
+		Stack:   stack,
+	})
+}
 
-	// End the trace span
-	if curr.Req != nil && curr.Trace != nil {
-		curr.Trace.PubsubPublishEnd(trace2.PubsubPublishEndParams{
-			EventParams: trace2.EventParams{
-				TraceID: curr.Req.TraceID,
-				SpanID:  curr.Req.SpanID,
-				Goid:    curr.Goctr,
-			},
-			StartID:   startEventID,
-			MessageID: id,
-			Err:       err,
-		})
+// traceEnd ends the trace span started by traceStart.
+func (t *Topic[T]) traceEnd(curr reqtrack.Current, startEventID trace2.EventID, id string, err error) {
+	if curr.Req == nil || curr.Trace == nil {
+		return
 	}
+	curr.Trace.PubsubPublishEnd(trace2.PubsubPublishEndParams{
+		EventParams: trace2.EventParams{
+			TraceID: curr.Req.TraceID,
+			SpanID:  curr.Req.SpanID,
+			Goid:    curr.Goctr,
+		},
+		StartID:   startEventID,
+		MessageID: id,
+		Err:       err,
+	})
+}
 
-	if err != nil {
-		return "", errs.B().Cause(err).Code(errs.Unavailable).Msgf("failed to publish message to %s", t.runtimeCfg.EncoreName).Err()
+// delayedPublisher returns the topic's implementation for publishing messages
+// with the given delivery delay, or nil if the message isn't delayed.
+func (t *Topic[T]) delayedPublisher(delay time.Duration) (types.DelayedPublisher, error) {
+	switch {
+	case delay == 0:
+		return nil, nil
+	case delay < 0:
+		return nil, errs.B().Code(errs.InvalidArgument).Msgf("negative delivery delay for topic %s", t.runtimeCfg.EncoreName).Err()
+	case t.appCfg.OrderingAttribute != "":
+		return nil, errs.B().Code(errs.InvalidArgument).Msgf("delivery delays are not supported for ordered topic %s", t.runtimeCfg.EncoreName).Err()
 	}
 
-	return id, nil
+	if d, ok := t.topic.(types.DelayedPublisher); ok {
+		return d, nil
+	}
+	return nil, errs.B().Code(errs.Unimplemented).Msgf("delivery delays are not supported by the pubsub provider for topic %s", t.runtimeCfg.EncoreName).Err()
 }
@@ -91,6 +91,22 @@ type SubscriptionConfig[T any] struct {
 	// RetryPolicy defines how a message should be retried when
 	// the subscriber returns an error
 	RetryPolicy *RetryPolicy
+
+	// AckDeduplication deduplicates redeliveries of messages
+	// that were already acknowledged.
+	//
+	// Only where the provider supports exactly-once delivery is each message
+	// guaranteed to be processed once (exactly-once delivery on GCP, and FIFO
+	// queues for topics with exactly-once delivery on AWS). Otherwise Encore
+	// deduplicates redeliveries by the message id for a limited time, in memory
+	// on each instance of the service. That's best-effort: a message redelivered
+	// to another instance, or after a restart, is processed again, so the handler
+	// must still be idempotent. A warning is logged on startup when that's the case.
+	//
+	// A message being redelivered while it's still being processed waits for
+	// that processing to finish, and is only processed again if it failed.
+	// This doesn't count as a failed delivery attempt.
+	AckDeduplication bool
 }
 
 type RetryPolicy = types.RetryPolicy
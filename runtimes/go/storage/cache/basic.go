@@ -47,6 +47,17 @@ func (s *StringKeyspace[K]) MultiGet(ctx context.Context, keys ...K) ([]Result[s
 	return s.basicKeyspace.MultiGet(ctx, keys...)
 }
 
+// MultiSet sets the values stored at multiple keys,
+// sending all the updates to the cache in a single round trip.
+//
+// The updates are not applied atomically. If any of them fail,
+// it reports the first error, and the others may have been applied.
+//
+// See https://redis.io/commands/set/ for more information.
+func (s *StringKeyspace[K]) MultiSet(ctx context.Context, entries ...Entry[K, string]) error {
+	return s.basicKeyspace.MultiSet(ctx, entries...)
+}
+
 // Set updates the value stored at key to val.
 //
 // See https://redis.io/commands/set/ for more information.
@@ -97,6 +108,18 @@ func (s *StringKeyspace[K]) Delete(ctx context.Context, keys ...K) (deleted int,
 	return s.client.Delete(ctx, keys...)
 }
 
+// OnInvalidate registers fn to be called whenever a key in the keyspace is changed,
+// deleted, or expires, by any instance of the application. It's intended for keeping
+// in-process caches of keyspace values coherent with the shared cache cluster.
+//
+// The callbacks are driven by Redis keyspace notifications, which must be enabled on the
+// cluster (see https://redis.io/docs/manual/keyspace-notifications/). Encore logs a warning
+// if they aren't. fn is called sequentially on a background goroutine, and may miss
+// invalidations while disconnected from the cluster.
+func (s *StringKeyspace[K]) OnInvalidate(fn func(key K)) {
+	s.client.OnInvalidate(fn)
+}
+
 // With returns a reference to the same keyspace but with customized write options.
 // The primary use case is for overriding the expiration time for certain cache operations.
 //
@@ -250,6 +273,17 @@ func (s *IntKeyspace[K]) MultiGet(ctx context.Context, keys ...K) ([]Result[int6
 	return s.basicKeyspace.MultiGet(ctx, keys...)
 }
 
+// MultiSet sets the values stored at multiple keys,
+// sending all the updates to the cache in a single round trip.
+//
+// The updates are not applied atomically. If any of them fail,
+// it reports the first error, and the others may have been applied.
+//
+// See https://redis.io/commands/set/ for more information.
+func (s *IntKeyspace[K]) MultiSet(ctx context.Context, entries ...Entry[K, int64]) error {
+	return s.basicKeyspace.MultiSet(ctx, entries...)
+}
+
 // Set updates the value stored at key to val.
 //
 // See https://redis.io/commands/set/ for more information.
@@ -300,6 +334,18 @@ func (s *IntKeyspace[K]) Delete(ctx context.Context, keys ...K) (deleted int, er
 	return s.client.Delete(ctx, keys...)
 }
 
+// OnInvalidate registers fn to be called whenever a key in the keyspace is changed,
+// deleted, or expires, by any instance of the application. It's intended for keeping
+// in-process caches of keyspace values coherent with the shared cache cluster.
+//
+// The callbacks are driven by Redis keyspace notifications, which must be enabled on the
+// cluster (see https://redis.io/docs/manual/keyspace-notifications/). Encore logs a warning
+// if they aren't. fn is called sequentially on a background goroutine, and may miss
+// invalidations while disconnected from the cluster.
+func (s *IntKeyspace[K]) OnInvalidate(fn func(key K)) {
+	s.client.OnInvalidate(fn)
+}
+
 // Increment increments the number stored in key by delta,
 // and returns the new value.
 //
@@ -401,6 +447,17 @@ func (s *FloatKeyspace[K]) MultiGet(ctx context.Context, keys ...K) ([]Result[fl
 	return s.basicKeyspace.MultiGet(ctx, keys...)
 }
 
+// MultiSet sets the values stored at multiple keys,
+// sending all the updates to the cache in a single round trip.
+//
+// The updates are not applied atomically. If any of them fail,
+// it reports the first error, and the others may have been applied.
+//
+// See https://redis.io/commands/set/ for more information.
+func (s *FloatKeyspace[K]) MultiSet(ctx context.Context, entries ...Entry[K, float64]) error {
+	return s.basicKeyspace.MultiSet(ctx, entries...)
+}
+
 // Set updates the value stored at key to val.
 //
 // See https://redis.io/commands/set/ for more information.
@@ -451,6 +508,18 @@ func (s *FloatKeyspace[K]) Delete(ctx context.Context, keys ...K) (deleted int,
 	return s.client.Delete(ctx, keys...)
 }
 
+// OnInvalidate registers fn to be called whenever a key in the keyspace is changed,
+// deleted, or expires, by any instance of the application. It's intended for keeping
+// in-process caches of keyspace values coherent with the shared cache cluster.
+//
+// The callbacks are driven by Redis keyspace notifications, which must be enabled on the
+// cluster (see https://redis.io/docs/manual/keyspace-notifications/). Encore logs a warning
+// if they aren't. fn is called sequentially on a background goroutine, and may miss
+// invalidations while disconnected from the cluster.
+func (s *FloatKeyspace[K]) OnInvalidate(fn func(key K)) {
+	s.client.OnInvalidate(fn)
+}
+
 // Increment increments the number stored in key by delta,
 // and returns the new value.
 //
@@ -531,7 +600,7 @@ func (s *basicKeyspace[K, V]) Get(ctx context.Context, key K) (val V, err error)
 func (s *basicKeyspace[K, V]) MultiGet(ctx context.Context, keys ...K) ([]Result[V], error) {
 	const op = "multi get"
 	ks, err := s.keys(keys, op)
-	endTrace := s.doTrace(op, false, ks...)
+	endTrace := s.doBatchTrace(op, false, ks)
 	defer func() { endTrace(err) }()
 	if err != nil {
 		return nil, err
@@ -565,6 +634,42 @@ func (s *basicKeyspace[K, V]) MultiGet(ctx context.Context, keys ...K) ([]Result
 	return results, nil
 }
 
+// Entry is a key and value to set with MultiSet.
+type Entry[K, V any] struct {
+	Key   K
+	Value V
+}
+
+func (s *basicKeyspace[K, V]) MultiSet(ctx context.Context, entries ...Entry[K, V]) (err error) {
+	const op = "multi set"
+	ks, err := s.keys(fnMap(entries, func(e Entry[K, V]) K { return e.Key }), op)
+	endTrace := s.doBatchTrace(op, true, ks)
+	defer func() { endTrace(err) }()
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+
+	// Send all the commands in a single round trip.
+	pipe := s.redis.Pipeline()
+	cmds := make([]*redis.StatusCmd, len(entries))
+	for i, e := range entries {
+		redisVal, err := s.toRedis(e.Value)
+		if err != nil {
+			return toErr(err, op, ks[i])
+		}
+		cmds[i] = redis.NewStatusCmd(ctx, s.setArgs(ks[i], redisVal, 0)...)
+		_ = pipe.Process(ctx, cmds[i])
+	}
+	_, _ = pipe.Exec(ctx)
+
+	for i, cmd := range cmds {
+		if err := cmd.Err(); err != nil {
+			return toErr(err, op, ks[i])
+		}
+	}
+	return nil
+}
+
 func (s *basicKeyspace[K, V]) Set(ctx context.Context, key K, val V) error {
 	_, _, err := s.set(ctx, key, val, 0, "set")
 	return err
@@ -648,7 +753,6 @@ func (s *basicKeyspace[K, V]) set(ctx context.Context, key K, val V, flag setFla
 
 	get := (flag & setGet) == setGet
 	nx := (flag & setNX) == setNX
-	xx := (flag & setXX) == setXX
 
 	if nx {
 		// If this is a setNX, convert Miss to KeyExists.
@@ -663,6 +767,27 @@ func (s *basicKeyspace[K, V]) set(ctx context.Context, key K, val V, flag setFla
 	if err != nil {
 		return "", k, toErr(err, op, k)
 	}
+	args := s.setArgs(k, redisVal, flag)
+
+	if get {
+		cmd := redis.NewStringCmd(ctx, args...)
+		_ = s.redis.Process(ctx, cmd)
+		res, err := cmd.Result()
+		err = toErr(err, op, k)
+		return res, k, err
+	}
+
+	cmd := redis.NewStatusCmd(ctx, args...)
+	_ = s.redis.Process(ctx, cmd)
+	return "", k, toErr(cmd.Err(), op, k)
+}
+
+// setArgs returns the arguments for a SET command of key to redisVal
+// with the given flags, including the keyspace's expiry.
+func (s *basicKeyspace[K, V]) setArgs(k string, redisVal any, flag setFlag) []any {
+	get := (flag & setGet) == setGet
+	nx := (flag & setNX) == setNX
+	xx := (flag & setXX) == setXX
 
 	args := make([]any, 3, 7)
 	args[0] = "set"
@@ -699,18 +824,7 @@ func (s *basicKeyspace[K, V]) set(ctx context.Context, key K, val V, flag setFla
 			}
 		}
 	}
-
-	if get {
-		cmd := redis.NewStringCmd(ctx, args...)
-		_ = s.redis.Process(ctx, cmd)
-		res, err := cmd.Result()
-		err = toErr(err, op, k)
-		return res, k, err
-	}
-
-	cmd := redis.NewStatusCmd(ctx, args...)
-	_ = s.redis.Process(ctx, cmd)
-	return "", k, toErr(cmd.Err(), op, k)
+	return args
 }
 
 func usePreciseDur(dur time.Duration) bool {
@@ -155,6 +155,22 @@ func TestMultiGet(t *testing.T) {
 	}
 }
 
+func TestMultiSet(t *testing.T) {
+	kt := newStringTest(t)
+	ks, ctx := kt.ks, kt.ctx
+
+	check(ks.MultiSet(ctx))
+	check(ks.With(ExpireIn(time.Minute)).MultiSet(ctx,
+		Entry[string, string]{Key: "key1", Value: "value1"},
+		Entry[string, string]{Key: "key2", Value: "value2"},
+	))
+	kt.Val("key1", "value1")
+	kt.Val("key2", "value2")
+	if got := kt.srv.TTL("key1"); got != time.Minute {
+		t.Errorf("key1: got ttl %v, want %v", got, time.Minute)
+	}
+}
+
 func newStringTest(t *testing.T) *stringTester {
 	cluster, srv := newTestCluster(t)
 	ks := NewStringKeyspace[string](cluster, KeyspaceConfig{
@@ -6,6 +6,7 @@ import (
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/go-redis/redis/v8"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/rs/zerolog"
 
 	"encore.dev/appruntime/exported/config"
@@ -22,7 +23,8 @@ func newTestCluster(t *testing.T) (*Cluster, *miniredis.Miniredis) {
 			// We're testing the "production mode" of the cache, not the test mode.
 			Testing: false,
 		},
-		rt: rt,
+		rt:   rt,
+		json: jsoniter.ConfigCompatibleWithStandardLibrary,
 	}
 	cluster := &Cluster{
 		mgr: mgr,
@@ -0,0 +1,277 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog"
+)
+
+// OnInvalidate calls fn with each key in the keyspace that's changed, deleted, or expires,
+// as reported by Redis keyspace notifications.
+func (s *client[K, V]) OnInvalidate(fn func(key K)) {
+	parse := newKeyParser[K](string(s.cfg.KeyPattern))
+	db := s.redis.Options().DB
+	prefix := fmt.Sprintf("__keyspace@%d__:", db)
+
+	pattern := prefix + keyPatternGlob(string(s.cfg.KeyPattern))
+	if s.testing {
+		// Keys are prefixed by the name of the test using them.
+		pattern = prefix + "*::" + keyPatternGlob(string(s.cfg.KeyPattern))
+	}
+
+	// Subscribe in the background, as keyspaces are usually declared during initialization.
+	go func() {
+		ctx := context.Background()
+		if !s.testing {
+			// Notifications are emulated in tests.
+			checkKeyspaceNotifications(ctx, s.redis, s.mgr.rt.Logger(), string(s.cfg.KeyPattern))
+		}
+		ps := s.redis.PSubscribe(ctx, pattern)
+		if _, err := ps.Receive(ctx); errors.Is(err, ErrNoopClient) {
+			// This service doesn't use the cluster.
+			_ = ps.Close()
+			return
+		}
+		s.mgr.addSubscription(ps)
+
+		for msg := range ps.Channel() {
+			key := strings.TrimPrefix(msg.Channel, prefix)
+			if s.testing {
+				_, key, _ = strings.Cut(key, "::")
+			}
+			if k, ok := parse(key); ok {
+				fn(k)
+			}
+		}
+	}()
+}
+
+// keyspaceEvents are the keyspace notification flags OnInvalidate relies on:
+// keyspace events (K) for generic commands (g), strings ($), lists (l), sets (s),
+// and expired (x) and evicted (e) keys.
+const keyspaceEvents = "Kg$lsxe"
+
+// checkKeyspaceNotifications logs a warning if the Redis server doesn't have the
+// keyspace notifications OnInvalidate relies on enabled. It doesn't enable them itself,
+// as that would change the configuration of the whole server, for all its clients.
+func checkKeyspaceNotifications(ctx context.Context, cl *redis.Client, logger *zerolog.Logger, keyPattern string) {
+	res, err := cl.ConfigGet(ctx, "notify-keyspace-events").Result()
+	if err != nil || len(res) != 2 {
+		// Some managed Redis services don't allow reading the configuration.
+		return
+	}
+	flags, _ := res[1].(string)
+	if missing := missingKeyspaceEvents(flags); missing != "" {
+		logger.Warn().
+			Str("key_pattern", keyPattern).
+			Str("notify_keyspace_events", flags).
+			Str("missing", missing).
+			Msg("cache keyspace notifications are not enabled, OnInvalidate callbacks will miss invalidations; " +
+				"add the missing flags to the notify-keyspace-events configuration of the Redis server")
+	}
+}
+
+// missingKeyspaceEvents returns the flags of keyspaceEvents
+// that aren't enabled by the notify-keyspace-events flags.
+func missingKeyspaceEvents(flags string) string {
+	var missing strings.Builder
+	for _, c := range keyspaceEvents {
+		// A is an alias for all event classes.
+		if !strings.ContainsRune(flags, c) && (c == 'K' || !strings.ContainsRune(flags, 'A')) {
+			missing.WriteRune(c)
+		}
+	}
+	return missing.String()
+}
+
+// keyPatternGlob returns a Redis glob pattern matching the keys of a keyspace
+// with the given key pattern.
+func keyPatternGlob(keyPattern string) string {
+	segs := strings.Split(keyPattern, "/")
+	for i, seg := range segs {
+		if strings.HasPrefix(seg, ":") {
+			segs[i] = "*"
+		} else {
+			segs[i] = globEscaper.Replace(seg)
+		}
+	}
+	return strings.Join(segs, "/")
+}
+
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// newKeyParser returns a function that parses keys produced by the key mapper Encore
+// generates for the key pattern back to keys of type K, reporting whether it's a valid key.
+//
+// Keys of basic types are given by the :key segment, and keys of struct types
+// by the segments named after their fields.
+func newKeyParser[K any](keyPattern string) func(key string) (K, bool) {
+	patternSegs := strings.Split(keyPattern, "/")
+	keyType := reflect.TypeFor[K]()
+
+	return func(key string) (k K, ok bool) {
+		segs := splitKey(key)
+		if len(segs) != len(patternSegs) {
+			return k, false
+		}
+
+		dst := reflect.ValueOf(&k).Elem()
+		for i, pseg := range patternSegs {
+			name, isParam := strings.CutPrefix(pseg, ":")
+			if !isParam {
+				if segs[i] != pseg {
+					return k, false
+				}
+				continue
+			}
+
+			field := dst
+			if keyType.Kind() == reflect.Struct {
+				field = dst.FieldByName(name)
+				if !field.IsValid() {
+					return k, false
+				}
+			}
+			if !setKeyValue(field, segs[i]) {
+				return k, false
+			}
+		}
+		return k, true
+	}
+}
+
+// splitKey splits a key into its segments, unescaping slashes within them.
+func splitKey(key string) []string {
+	var (
+		segs []string
+		seg  strings.Builder
+	)
+	for i := 0; i < len(key); i++ {
+		switch {
+		case key[i] == '\\' && i+1 < len(key) && key[i+1] == '/':
+			seg.WriteByte('/')
+			i++
+		case key[i] == '/':
+			segs = append(segs, seg.String())
+			seg.Reset()
+		default:
+			seg.WriteByte(key[i])
+		}
+	}
+	return append(segs, seg.String())
+}
+
+// setKeyValue parses str as formatted by the key mapper into dst.
+func setKeyValue(dst reflect.Value, str string) bool {
+	switch dst.Kind() {
+	case reflect.String:
+		dst.SetString(str)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return false
+		}
+		dst.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(str, 10, dst.Type().Bits())
+		if err != nil {
+			return false
+		}
+		dst.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(str, 10, dst.Type().Bits())
+		if err != nil {
+			return false
+		}
+		dst.SetUint(n)
+	case reflect.Slice:
+		if dst.Type().Elem().Kind() != reflect.Uint8 {
+			return false
+		}
+		dst.SetBytes([]byte(str))
+	default:
+		return false
+	}
+	return true
+}
+
+// notifyHook emulates Redis keyspace notifications for the writes
+// made through a client, for miniredis which doesn't support them.
+type notifyHook struct {
+	srv *miniredis.Miniredis
+	db  int
+}
+
+func (h *notifyHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+func (h *notifyHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	h.notify(cmd)
+	return nil
+}
+
+func (h *notifyHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+func (h *notifyHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	for _, cmd := range cmds {
+		h.notify(cmd)
+	}
+	return nil
+}
+
+func (h *notifyHook) notify(cmd redis.Cmder) {
+	if err := cmd.Err(); err != nil && !errors.Is(err, redis.Nil) {
+		return
+	}
+	name := cmd.Name()
+	for _, key := range writtenKeys(name, cmd.Args()) {
+		h.srv.Publish(fmt.Sprintf("__keyspace@%d__:%s", h.db, key), name)
+	}
+}
+
+// writtenKeys returns the keys written by the command with the given name and arguments.
+func writtenKeys(name string, args []any) []string {
+	arg := func(i int) []string {
+		if i < len(args) {
+			if s, ok := args[i].(string); ok {
+				return []string{s}
+			}
+		}
+		return nil
+	}
+
+	switch name {
+	case "set", "setnx", "setex", "psetex", "getset", "getdel", "getex", "append",
+		"incr", "incrby", "incrbyfloat", "decr", "decrby",
+		"expire", "pexpire", "expireat", "pexpireat", "persist",
+		"lpush", "rpush", "lpushx", "rpushx", "lpop", "rpop", "lset", "ltrim", "lrem", "linsert",
+		"sadd", "srem", "spop", "sdiffstore", "sinterstore", "sunionstore",
+		"xadd", "xtrim", "xdel":
+		return arg(1)
+	case "lmove", "rpoplpush", "smove":
+		return append(arg(1), arg(2)...)
+	case "del", "unlink":
+		var keys []string
+		for i := 1; i < len(args); i++ {
+			keys = append(keys, arg(i)...)
+		}
+		return keys
+	case "mset":
+		var keys []string
+		for i := 1; i < len(args); i += 2 {
+			keys = append(keys, arg(i)...)
+		}
+		return keys
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type invalidateKey struct {
+	ID   int
+	Name string
+}
+
+func TestOnInvalidate(t *testing.T) {
+	cluster, srv := newTestCluster(t)
+	cluster.cl.AddHook(&notifyHook{srv: srv})
+
+	ks := NewStringKeyspace[invalidateKey](cluster, KeyspaceConfig{
+		KeyPattern: "users/:ID/:Name",
+		EncoreInternal_KeyMapper: func(k invalidateKey) string {
+			return fmt.Sprintf("users/%v/%s", k.ID, strings.ReplaceAll(k.Name, "/", `\/`))
+		},
+	})
+	other := NewStringKeyspace[string](cluster, KeyspaceConfig{
+		KeyPattern:               "other/:key",
+		EncoreInternal_KeyMapper: func(k string) string { return "other/" + k },
+	})
+
+	invalidated := make(chan invalidateKey, 10)
+	ks.OnInvalidate(func(key invalidateKey) { invalidated <- key })
+	expect := func(want invalidateKey) {
+		t.Helper()
+		select {
+		case got := <-invalidated:
+			if got != want {
+				t.Errorf("got invalidated key %+v, want %+v", got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("key %+v was not invalidated", want)
+		}
+	}
+
+	// Wait for the subscription to be set up.
+	deadline := time.Now().Add(time.Second)
+	for srv.PubSubNumPat() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for subscription")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx := context.Background()
+	alice := invalidateKey{ID: 1, Name: "alice/a"}
+	bob := invalidateKey{ID: 2, Name: "bob"}
+	check(ks.Set(ctx, alice, "a"))
+	expect(alice)
+	check(other.Set(ctx, "x", "x"))
+	check(ks.MultiSet(ctx, Entry[invalidateKey, string]{Key: bob, Value: "b"}))
+	expect(bob)
+	must(ks.Delete(ctx, alice, bob))
+	expect(alice)
+	expect(bob)
+}
+
+func TestKeyParser(t *testing.T) {
+	parseStruct := newKeyParser[invalidateKey]("users/:ID/:Name")
+	if got, ok := parseStruct(`users/5/a\/b`); !ok || got != (invalidateKey{ID: 5, Name: "a/b"}) {
+		t.Errorf("got %+v, %v", got, ok)
+	}
+	for _, key := range []string{"users/x/a", "users/5", "people/5/a", "users/5/a/b"} {
+		if got, ok := parseStruct(key); ok {
+			t.Errorf("parse(%q) = %+v, want invalid", key, got)
+		}
+	}
+
+	parseInt := newKeyParser[int64]("counter/:key")
+	if got, ok := parseInt("counter/-3"); !ok || got != -3 {
+		t.Errorf("got %v, %v", got, ok)
+	}
+
+	if got, want := keyPatternGlob("users/:ID/[x]*"), `users/*/\[x\]\*`; got != want {
+		t.Errorf("keyPatternGlob() = %q, want %q", got, want)
+	}
+}
+
+func TestMissingKeyspaceEvents(t *testing.T) {
+	tests := []struct {
+		flags, want string
+	}{
+		{flags: "", want: "Kg$lsxe"},
+		{flags: "KA", want: ""},
+		{flags: "AK", want: ""},
+		{flags: "EA", want: "K"},
+		{flags: "Kg$lsxe", want: ""},
+		{flags: "Kg$x", want: "lse"},
+	}
+	for _, test := range tests {
+		if got := missingKeyspaceEvents(test.flags); got != test.want {
+			t.Errorf("missingKeyspaceEvents(%q) = %q, want %q", test.flags, got, test.want)
+		}
+	}
+}
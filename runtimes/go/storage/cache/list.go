@@ -58,6 +58,18 @@ func (s *ListKeyspace[K, V]) Delete(ctx context.Context, keys ...K) (deleted int
 	return s.client.Delete(ctx, keys...)
 }
 
+// OnInvalidate registers fn to be called whenever a key in the keyspace is changed,
+// deleted, or expires, by any instance of the application. It's intended for keeping
+// in-process caches of keyspace values coherent with the shared cache cluster.
+//
+// The callbacks are driven by Redis keyspace notifications, which must be enabled on the
+// cluster (see https://redis.io/docs/manual/keyspace-notifications/). Encore logs a warning
+// if they aren't. fn is called sequentially on a background goroutine, and may miss
+// invalidations while disconnected from the cluster.
+func (s *ListKeyspace[K, V]) OnInvalidate(fn func(key K)) {
+	s.client.OnInvalidate(fn)
+}
+
 // PushLeft pushes one or more values at the head of the list stored at key.
 // If the key does not already exist, it is first created as an empty list.
 //
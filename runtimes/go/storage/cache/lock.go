@@ -0,0 +1,288 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	mathrand "math/rand" // nosemgrep
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// NewLockKeyspace creates a keyspace of distributed locks in the given cluster.
+//
+// The type parameter K specifies the key type, which can either be a
+// named struct type or a basic type (string, int, etc).
+//
+// Locks are held by a single owner at a time, identified by a random token,
+// and automatically expire after a time-to-live so that a crashed owner
+// can't hold a lock forever. Owners holding a lock for longer than its
+// time-to-live must extend it with Renew before it expires.
+//
+// When running tests, locks are emulated in memory rather than stored in the cache.
+func NewLockKeyspace[K any](cluster *Cluster, cfg KeyspaceConfig) *LockKeyspace[K] {
+	fromRedis := func(val string) (string, error) { return val, nil }
+	toRedis := func(val string) (any, error) { return val, nil }
+
+	var backend lockBackend = &redisLocks{cl: cluster.cl}
+	if cluster.mgr.static.Testing {
+		backend = &cluster.mgr.testLocks
+	}
+
+	return &LockKeyspace[K]{
+		client:  newClient[K, string](cluster, cfg, fromRedis, toRedis),
+		backend: backend,
+	}
+}
+
+// LockKeyspace represents a set of cache keys, each holding a distributed lock.
+type LockKeyspace[K any] struct {
+	*client[K, string]
+	backend lockBackend
+}
+
+// LockLost is the error reported when renewing or releasing a lock
+// that is no longer held, because it expired before being renewed.
+// It must be checked against with errors.Is.
+var LockLost = errors.New("lock no longer held")
+
+// Acquire acquires the lock stored at key, waiting for it to be released
+// or to expire if it's held by someone else.
+//
+// The lock expires after ttl, which must be at least a millisecond, unless it's renewed with Renew.
+// If ctx is done before the lock could be acquired it returns ctx.Err().
+//
+// See https://redis.io/docs/manual/patterns/distributed-locks/ for more information.
+func (l *LockKeyspace[K]) Acquire(ctx context.Context, key K, ttl time.Duration) (lock *Lock, err error) {
+	const op = "lock acquire"
+	k, err := l.key(key, op)
+	endTrace := l.doTrace(op, true, k)
+	defer func() { endTrace(err) }()
+	if err != nil {
+		return nil, err
+	} else if err := checkLockTTL(ttl); err != nil {
+		return nil, toErr(err, op, k)
+	}
+
+	token, err := newLockToken()
+	if err != nil {
+		return nil, toErr(err, op, k)
+	}
+
+	for {
+		start := time.Now()
+		ok, err := l.backend.tryAcquire(ctx, k, token, ttl)
+		if err != nil {
+			return nil, toErr(err, op, k)
+		} else if ok {
+			lock = &Lock{backend: l.backend, trace: l.doTrace, key: k, token: token}
+			lock.setValidity(start, ttl)
+			return lock, nil
+		}
+
+		// Retry after a random delay, to avoid owners contending for the lock in lockstep.
+		delay := lockRetryDelay/2 + time.Duration(mathrand.Int63n(int64(lockRetryDelay)))
+		select {
+		case <-ctx.Done():
+			return nil, toErr(ctx.Err(), op, k)
+		case <-time.After(delay):
+		}
+	}
+}
+
+// checkLockTTL reports an error if ttl is too short to expire a lock after,
+// as Redis expires keys with millisecond precision.
+func checkLockTTL(ttl time.Duration) error {
+	if ttl < time.Millisecond {
+		return errors.New("lock ttl must be at least a millisecond")
+	}
+	return nil
+}
+
+// lockRetryDelay is the average delay between attempts to acquire a held lock.
+const lockRetryDelay = 50 * time.Millisecond
+
+// Lock is a distributed lock acquired with LockKeyspace.Acquire.
+type Lock struct {
+	backend lockBackend
+	trace   func(op string, write bool, keys ...string) func(error)
+	key     string
+	token   string
+
+	mu         sync.Mutex
+	validUntil time.Time
+}
+
+// ValidUntil reports the time until which the lock is guaranteed to be held,
+// unless it's renewed. It accounts for the time taken to acquire or renew
+// the lock and for clock drift between the application and the cache.
+func (l *Lock) ValidUntil() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.validUntil
+}
+
+// Renew extends the lock to expire after ttl from now,
+// which must be at least a millisecond.
+//
+// If the lock has already expired it reports an error matching LockLost,
+// and the lock must be acquired again.
+func (l *Lock) Renew(ctx context.Context, ttl time.Duration) (err error) {
+	const op = "lock renew"
+	endTrace := l.trace(op, true, l.key)
+	defer func() { endTrace(err) }()
+	if err := checkLockTTL(ttl); err != nil {
+		return toErr(err, op, l.key)
+	}
+
+	start := time.Now()
+	ok, err := l.backend.renew(ctx, l.key, l.token, ttl)
+	if err != nil {
+		return toErr(err, op, l.key)
+	} else if !ok {
+		return toErr(LockLost, op, l.key)
+	}
+	l.setValidity(start, ttl)
+	return nil
+}
+
+// Release releases the lock, allowing others to acquire it.
+//
+// If the lock has already expired it reports an error matching LockLost.
+func (l *Lock) Release(ctx context.Context) (err error) {
+	const op = "lock release"
+	endTrace := l.trace(op, true, l.key)
+	defer func() { endTrace(err) }()
+
+	ok, err := l.backend.release(ctx, l.key, l.token)
+	if err != nil {
+		return toErr(err, op, l.key)
+	} else if !ok {
+		return toErr(LockLost, op, l.key)
+	}
+	l.mu.Lock()
+	l.validUntil = time.Time{}
+	l.mu.Unlock()
+	return nil
+}
+
+// setValidity sets the lock's validity after acquiring or renewing it
+// for ttl at start, following the Redlock algorithm.
+func (l *Lock) setValidity(start time.Time, ttl time.Duration) {
+	drift := ttl/100 + 2*time.Millisecond
+	l.mu.Lock()
+	l.validUntil = start.Add(ttl - drift)
+	l.mu.Unlock()
+}
+
+func newLockToken() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// lockBackend stores the locks of a LockKeyspace.
+type lockBackend interface {
+	// tryAcquire acquires the lock at key for the owner token,
+	// reporting whether it was acquired.
+	tryAcquire(ctx context.Context, key, token string, ttl time.Duration) (bool, error)
+
+	// renew extends the lock at key if held by token, reporting whether it was.
+	renew(ctx context.Context, key, token string, ttl time.Duration) (bool, error)
+
+	// release releases the lock at key if held by token, reporting whether it was.
+	release(ctx context.Context, key, token string) (bool, error)
+}
+
+// redisLocks stores locks in Redis, as keys holding the owner's token.
+type redisLocks struct {
+	cl *redis.Client
+}
+
+var (
+	renewLockScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return 0`)
+
+	releaseLockScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0`)
+)
+
+func (r *redisLocks) tryAcquire(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	return r.cl.SetNX(ctx, key, token, ttl).Result()
+}
+
+func (r *redisLocks) renew(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	res, err := renewLockScript.Run(ctx, r.cl, []string{key}, token, ttl.Milliseconds()).Int()
+	return res == 1, err
+}
+
+func (r *redisLocks) release(ctx context.Context, key, token string) (bool, error) {
+	res, err := releaseLockScript.Run(ctx, r.cl, []string{key}, token).Int()
+	return res == 1, err
+}
+
+// memLocks emulates locks in memory, for use in tests.
+// The zero value is ready to use.
+type memLocks struct {
+	mu    sync.Mutex
+	locks map[string]memLock
+}
+
+type memLock struct {
+	token   string
+	expires time.Time
+}
+
+func (m *memLocks) tryAcquire(_ context.Context, key, token string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.held(key, "") {
+		return false, nil
+	}
+	if m.locks == nil {
+		m.locks = make(map[string]memLock)
+	}
+	m.locks[key] = memLock{token: token, expires: time.Now().Add(ttl)}
+	return true, nil
+}
+
+func (m *memLocks) renew(_ context.Context, key, token string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.held(key, token) {
+		return false, nil
+	}
+	m.locks[key] = memLock{token: token, expires: time.Now().Add(ttl)}
+	return true, nil
+}
+
+func (m *memLocks) release(_ context.Context, key, token string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.held(key, token) {
+		return false, nil
+	}
+	delete(m.locks, key)
+	return true, nil
+}
+
+// held reports whether the lock at key is held and hasn't expired,
+// by token if it's non-empty. It must be called with m.mu held.
+func (m *memLocks) held(key, token string) bool {
+	lock, ok := m.locks[key]
+	if !ok || !time.Now().Before(lock.expires) {
+		return false
+	}
+	return token == "" || lock.token == token
+}
@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLocks(t *testing.T) {
+	for _, inMemory := range []bool{false, true} {
+		name := "redis"
+		if inMemory {
+			name = "memory"
+		}
+		t.Run(name, func(t *testing.T) {
+			cluster, srv := newTestCluster(t)
+			ks := NewLockKeyspace[string](cluster, KeyspaceConfig{
+				EncoreInternal_KeyMapper: func(s string) string { return s },
+			})
+			if inMemory {
+				// Use the emulation used when running tests.
+				ks.backend = &cluster.mgr.testLocks
+			}
+			ctx := context.Background()
+
+			lock := must(ks.Acquire(ctx, "job", time.Minute))
+			if until := time.Until(lock.ValidUntil()); until <= 0 || until > time.Minute {
+				t.Errorf("got lock valid for %v, want (0, 1m]", until)
+			}
+
+			// Acquiring a held lock waits until ctx is done.
+			shortCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+			defer cancel()
+			if _, err := ks.Acquire(shortCtx, "job", time.Minute); !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("Acquire(held) = %v, want %v", err, context.DeadlineExceeded)
+			}
+
+			// Other keys are independent.
+			other := must(ks.Acquire(ctx, "other", time.Minute))
+			check(other.Release(ctx))
+
+			// Sub-millisecond ttls are rejected, rather than expiring the lock right away.
+			for _, ttl := range []time.Duration{0, -time.Second, time.Microsecond} {
+				if _, err := ks.Acquire(ctx, "short", ttl); err == nil {
+					t.Errorf("Acquire(ttl=%v) succeeded, want an error", ttl)
+				}
+				if err := lock.Renew(ctx, ttl); err == nil {
+					t.Errorf("Renew(ttl=%v) succeeded, want an error", ttl)
+				}
+			}
+
+			check(lock.Renew(ctx, time.Minute))
+			check(lock.Release(ctx))
+			if err := lock.Release(ctx); !errors.Is(err, LockLost) {
+				t.Errorf("Release(released) = %v, want %v", err, LockLost)
+			}
+
+			// Waiting acquirers get the lock once it's released.
+			lock = must(ks.Acquire(ctx, "job", time.Minute))
+			acquired := make(chan *Lock)
+			go func() { acquired <- must(ks.Acquire(ctx, "job", time.Minute)) }()
+			time.Sleep(20 * time.Millisecond)
+			check(lock.Release(ctx))
+			select {
+			case next := <-acquired:
+				check(next.Release(ctx))
+			case <-time.After(time.Second):
+				t.Fatal("lock was not acquired after release")
+			}
+
+			// Expired locks can be acquired by others, and are lost to their previous owner.
+			lock = must(ks.Acquire(ctx, "job", 50*time.Millisecond))
+			if inMemory {
+				time.Sleep(60 * time.Millisecond)
+			} else {
+				srv.FastForward(60 * time.Millisecond)
+			}
+			next := must(ks.Acquire(ctx, "job", time.Minute))
+			if err := lock.Renew(ctx, time.Minute); !errors.Is(err, LockLost) {
+				t.Errorf("Renew(expired) = %v, want %v", err, LockLost)
+			}
+			if err := lock.Release(ctx); !errors.Is(err, LockLost) {
+				t.Errorf("Release(expired) = %v, want %v", err, LockLost)
+			}
+			check(next.Release(ctx))
+		})
+	}
+}
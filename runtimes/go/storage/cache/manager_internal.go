@@ -39,6 +39,11 @@ type Manager struct {
 
 	clientMu sync.RWMutex
 	clients  map[string]*redis.Client
+
+	testLocks memLocks // locks emulated in tests
+
+	subsMu sync.Mutex
+	subs   []*redis.PubSub // keyspace notification subscriptions, closed on shutdown
 }
 
 func NewManager(static *config.Static, runtime *config.Runtime, rt *reqtrack.RequestTracker, ts *testsupport.Manager, json jsoniter.API) *Manager {
@@ -165,6 +170,7 @@ func (mgr *Manager) newMiniredisClient() (*redis.Client, error) {
 		PoolSize:     runtime.GOMAXPROCS(0) * 10,
 	}
 	cl := redis.NewClient(opts)
+	cl.AddHook(&notifyHook{srv: mgr.testSrv, db: opts.DB})
 
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	err = cl.Ping(ctx).Err()
@@ -178,6 +184,12 @@ func (mgr *Manager) Shutdown(p *shutdown.Process) error {
 	<-p.ServicesShutdownCompleted.Done()
 	<-p.OutstandingTasks.Done()
 
+	mgr.subsMu.Lock()
+	for _, ps := range mgr.subs {
+		_ = ps.Close()
+	}
+	mgr.subsMu.Unlock()
+
 	mgr.clientMu.Lock()
 	mgr.clientMu.Unlock()
 	for _, c := range mgr.clients {
@@ -186,6 +198,12 @@ func (mgr *Manager) Shutdown(p *shutdown.Process) error {
 	return nil
 }
 
+func (mgr *Manager) addSubscription(ps *redis.PubSub) {
+	mgr.subsMu.Lock()
+	defer mgr.subsMu.Unlock()
+	mgr.subs = append(mgr.subs, ps)
+}
+
 func newClient[K, V any](cluster *Cluster, cfg KeyspaceConfig,
 	fromRedis func(string) (V, error),
 	toRedis func(V) (any, error),
@@ -212,6 +230,8 @@ func newClient[K, V any](cluster *Cluster, cfg KeyspaceConfig,
 	}
 
 	return &client[K, V]{
+		mgr:       cluster.mgr,
+		testing:   cluster.mgr.static.Testing,
 		rt:        cluster.mgr.rt,
 		redis:     cluster.cl,
 		cfg:       cfg,
@@ -223,6 +243,8 @@ func newClient[K, V any](cluster *Cluster, cfg KeyspaceConfig,
 }
 
 type client[K, V any] struct {
+	mgr       *Manager
+	testing   bool // whether keys are prefixed by the current test's name
 	rt        *reqtrack.RequestTracker
 	redis     *redis.Client
 	cfg       KeyspaceConfig
@@ -328,13 +350,23 @@ func (s *client[K, V]) expiryDur() time.Duration {
 }
 
 func (c *client[K, V]) doTrace(op string, write bool, keys ...string) func(error) {
-	eventID := c.traceStart(op, write, keys...)
+	eventID := c.traceStart(op, write, nil, keys...)
+	return func(err error) {
+		c.traceEnd(eventID, err)
+	}
+}
+
+// doBatchTrace is like doTrace for operations on a batch of keys,
+// recording the size of the batch.
+func (c *client[K, V]) doBatchTrace(op string, write bool, keys []string) func(error) {
+	batchSize := uint64(len(keys))
+	eventID := c.traceStart(op, write, &batchSize, keys...)
 	return func(err error) {
 		c.traceEnd(eventID, err)
 	}
 }
 
-func (c *client[K, V]) traceStart(op string, write bool, keys ...string) (eventID model.TraceEventID) {
+func (c *client[K, V]) traceStart(op string, write bool, batchSize *uint64, keys ...string) (eventID model.TraceEventID) {
 	if curr := c.rt.Current(); curr.Trace != nil && curr.Req != nil {
 		eventID = curr.Trace.CacheCallStart(trace2.CacheCallStartParams{
 			EventParams: trace2.EventParams{
@@ -347,6 +379,7 @@ func (c *client[K, V]) traceStart(op string, write bool, keys ...string) (eventI
 			IsWrite:   write,
 			Keys:      keys,
 			Stack:     stack.Build(3),
+			BatchSize: batchSize,
 		})
 	}
 
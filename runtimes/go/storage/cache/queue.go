@@ -0,0 +1,366 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// NewQueueKeyspace creates a keyspace that stores work queues in the given cluster,
+// each backed by a Redis Stream.
+//
+// The type parameter K specifies the key type, which can either be a
+// named struct type or a basic type (string, int, etc).
+//
+// The type parameter V specifies the named struct type of the queued values.
+//
+// Values are consumed by consumer groups: each value pushed to a queue is delivered
+// to one consumer in each group that pops from it, and stays pending until the consumer
+// acknowledges it with Ack. Values that are never acknowledged, such as because the
+// consumer crashed, can be taken over by another consumer with Claim.
+//
+// Values are removed from the queue once they've been acknowledged by every consumer group,
+// so groups created later don't receive them. Since values are removed from the start of
+// the queue, a value is only removed once the values before it have been removed too.
+func NewQueueKeyspace[K, V any](cluster *Cluster, cfg KeyspaceConfig) *QueueKeyspace[K, V] {
+	json := cluster.mgr.json
+	fromRedis := func(val string) (V, error) {
+		var v V
+		err := json.UnmarshalFromString(val, &v)
+		return v, err
+	}
+	toRedis := func(val V) (any, error) {
+		return json.MarshalToString(val)
+	}
+
+	return &QueueKeyspace[K, V]{
+		newClient[K, V](cluster, cfg, fromRedis, toRedis),
+	}
+}
+
+// QueueKeyspace represents a set of cache keys,
+// each containing a work queue of values of type V.
+type QueueKeyspace[K, V any] struct {
+	*client[K, V]
+}
+
+// QueueMessage is a value popped from a queue.
+type QueueMessage[V any] struct {
+	// ID is the unique ID of the message within the queue,
+	// used to acknowledge it with Ack.
+	ID string
+
+	// Value is the queued value.
+	Value V
+}
+
+// queueValueField is the stream entry field holding the queued value.
+const queueValueField = "value"
+
+// With returns a reference to the same keyspace but with customized write options.
+// The primary use case is for overriding the expiration time for certain cache operations.
+//
+// It is intended to be used with method chaining:
+//
+//	myKeyspace.With(cache.ExpireIn(3 * time.Second)).Push(...)
+func (k *QueueKeyspace[K, V]) With(opts ...WriteOption) *QueueKeyspace[K, V] {
+	return &QueueKeyspace[K, V]{k.client.with(opts)}
+}
+
+// Delete deletes the specified keys, including all queued
+// values and the state of their consumer groups.
+//
+// If a key does not exist it is ignored.
+//
+// It reports the number of keys that were deleted.
+//
+// See https://redis.io/commands/del/ for more information.
+func (q *QueueKeyspace[K, V]) Delete(ctx context.Context, keys ...K) (deleted int, err error) {
+	return q.client.Delete(ctx, keys...)
+}
+
+// Push adds val to the end of the queue stored at key and returns its message ID.
+// If the key does not already exist, it is first created as an empty queue.
+//
+// See https://redis.io/commands/xadd/ for more information.
+func (q *QueueKeyspace[K, V]) Push(ctx context.Context, key K, val V) (id string, err error) {
+	const op = "queue push"
+	k, err := q.key(key, op)
+	endTrace := q.doTrace(op, true, k)
+	defer func() { endTrace(err) }()
+	if err != nil {
+		return "", err
+	}
+
+	redisVal, err := q.toRedis(val)
+	if err != nil {
+		return "", toErr(err, op, k)
+	}
+	id, err = do(q.client, ctx, k, func(c cmdable) *redis.StringCmd {
+		return c.XAdd(ctx, &redis.XAddArgs{
+			Stream: k,
+			Values: []any{queueValueField, redisVal},
+		})
+	}).Result()
+	err = toErr(err, op, k)
+	return id, err
+}
+
+// Pop returns up to count values from the queue stored at key that haven't yet been
+// delivered to the given consumer group, and marks them as pending for consumer.
+// It does not wait for new values to be pushed.
+//
+// The consumer group is created the first time it's used, and starts from the beginning
+// of the queue. Each value is delivered to only one consumer in a group, and stays pending
+// until it's acknowledged with Ack.
+//
+// If there are no new values it returns an empty slice and no error.
+//
+// See https://redis.io/commands/xreadgroup/ for more information.
+func (q *QueueKeyspace[K, V]) Pop(ctx context.Context, key K, group, consumer string, count int) (msgs []QueueMessage[V], err error) {
+	const op = "queue pop"
+	k, err := q.key(key, op)
+	endTrace := q.doTrace(op, true, k)
+	defer func() { endTrace(err) }()
+	if err != nil {
+		return nil, err
+	}
+
+	read := func() ([]redis.XStream, error) {
+		return do(q.client, ctx, k, func(c cmdable) *redis.XStreamSliceCmd {
+			return c.XReadGroup(ctx, &redis.XReadGroupArgs{
+				Group:    group,
+				Consumer: consumer,
+				Streams:  []string{k, ">"},
+				Count:    int64(count),
+				Block:    -1, // don't block
+			})
+		}).Result()
+	}
+
+	res, err := read()
+	if isNoGroup(err) {
+		if err = q.createGroup(ctx, k, group); err == nil {
+			res, err = read()
+		}
+	}
+	if errors.Is(err, redis.Nil) {
+		return []QueueMessage[V]{}, nil
+	} else if err != nil {
+		return nil, toErr(err, op, k)
+	}
+
+	var entries []redis.XMessage
+	if len(res) > 0 {
+		entries = res[0].Messages
+	}
+	msgs, err = q.messages(entries)
+	return msgs, toErr(err, op, k)
+}
+
+// Ack acknowledges that the given messages popped from the queue stored at key
+// have been processed by the consumer group, so they're no longer pending.
+// It then removes the values at the start of the queue that have been
+// acknowledged by every consumer group.
+//
+// It reports the number of messages that were acknowledged,
+// not including messages that weren't pending.
+//
+// See https://redis.io/commands/xack/ for more information.
+func (q *QueueKeyspace[K, V]) Ack(ctx context.Context, key K, group string, ids ...string) (acked int, err error) {
+	const op = "queue ack"
+	k, err := q.key(key, op)
+	endTrace := q.doTrace(op, true, k)
+	defer func() { endTrace(err) }()
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := q.redis.XAck(ctx, k, group, ids...).Result()
+	if err == nil && res > 0 {
+		// Removing processed values is best effort: the message is acknowledged
+		// either way, and the values are removed by the next Ack instead.
+		_ = q.trimProcessed(ctx, k)
+	}
+	err = toErr(err, op, k)
+	return int(res), err
+}
+
+// Claim transfers up to count messages that have been pending in the consumer group
+// for at least minIdle, such as because the consumer that popped them crashed,
+// to consumer and returns them.
+//
+// If no messages have been pending for that long it returns an empty slice and no error.
+//
+// See https://redis.io/commands/xclaim/ for more information.
+func (q *QueueKeyspace[K, V]) Claim(ctx context.Context, key K, group, consumer string, minIdle time.Duration, count int) (msgs []QueueMessage[V], err error) {
+	const op = "queue claim"
+	k, err := q.key(key, op)
+	endTrace := q.doTrace(op, true, k)
+	defer func() { endTrace(err) }()
+	if err != nil {
+		return nil, err
+	}
+
+	// Use XPENDING and XCLAIM rather than XAUTOCLAIM,
+	// as the Redis client can't parse its response from Redis 7.
+	pending, err := q.redis.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: k,
+		Group:  group,
+		Idle:   minIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  int64(count),
+	}).Result()
+	if isNoGroup(err) || errors.Is(err, redis.Nil) {
+		return []QueueMessage[V]{}, nil
+	} else if err != nil {
+		return nil, toErr(err, op, k)
+	} else if len(pending) == 0 {
+		return []QueueMessage[V]{}, nil
+	}
+
+	ids := fnMap(pending, func(p redis.XPendingExt) string { return p.ID })
+	entries, err := q.redis.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   k,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		return nil, toErr(err, op, k)
+	}
+	msgs, err = q.messages(entries)
+	return msgs, toErr(err, op, k)
+}
+
+// Len reports the number of values in the queue stored at key, including values
+// that have been popped but not yet acknowledged by every consumer group.
+//
+// If the key does not exist it reports 0, nil.
+//
+// See https://redis.io/commands/xlen/ for more information.
+func (q *QueueKeyspace[K, V]) Len(ctx context.Context, key K) (length int64, err error) {
+	const op = "queue len"
+	k, err := q.key(key, op)
+	endTrace := q.doTrace(op, false, k)
+	defer func() { endTrace(err) }()
+	if err != nil {
+		return 0, err
+	}
+
+	length, err = q.redis.XLen(ctx, k).Result()
+	err = toErr(err, op, k)
+	return length, err
+}
+
+// createGroup creates the consumer group for the stream at key,
+// starting from the beginning of the stream.
+func (q *QueueKeyspace[K, V]) createGroup(ctx context.Context, key, group string) error {
+	err := q.redis.XGroupCreateMkStream(ctx, key, group, "0").Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		// Someone else created the group concurrently.
+		return nil
+	}
+	return err
+}
+
+// trimProcessed removes the values at the start of the stream at key
+// that have been delivered to and acknowledged by every consumer group.
+//
+// See https://redis.io/commands/xtrim/ for more information.
+func (q *QueueKeyspace[K, V]) trimProcessed(ctx context.Context, key string) error {
+	groups, err := q.redis.XInfoGroups(ctx, key).Result()
+	if err != nil || len(groups) == 0 {
+		return err
+	}
+
+	// Find the ID of the first value that some group hasn't processed:
+	// its oldest pending value, or otherwise the first one not yet delivered to it.
+	var minID streamID
+	for i, g := range groups {
+		first, ok := parseStreamID(g.LastDeliveredID)
+		if !ok {
+			return fmt.Errorf("invalid stream ID %q", g.LastDeliveredID)
+		}
+		first = first.next()
+		if g.Pending > 0 {
+			pending, err := q.redis.XPending(ctx, key, g.Name).Result()
+			if err != nil {
+				return err
+			} else if first, ok = parseStreamID(pending.Lower); !ok {
+				return fmt.Errorf("invalid stream ID %q", pending.Lower)
+			}
+		}
+		if i == 0 || first.less(minID) {
+			minID = first
+		}
+	}
+	return q.redis.XTrimMinID(ctx, key, minID.String()).Err()
+}
+
+// streamID is the ID of a Redis stream entry.
+type streamID struct {
+	ms, seq uint64
+}
+
+// parseStreamID parses a stream ID of the form <ms>-<seq>, or <ms>.
+func parseStreamID(id string) (streamID, bool) {
+	msStr, seqStr, hasSeq := strings.Cut(id, "-")
+	ms, err := strconv.ParseUint(msStr, 10, 64)
+	if err != nil {
+		return streamID{}, false
+	}
+	var seq uint64
+	if hasSeq {
+		if seq, err = strconv.ParseUint(seqStr, 10, 64); err != nil {
+			return streamID{}, false
+		}
+	}
+	return streamID{ms: ms, seq: seq}, true
+}
+
+// next returns the smallest ID greater than id.
+func (id streamID) next() streamID {
+	if id.seq == math.MaxUint64 {
+		return streamID{ms: id.ms + 1}
+	}
+	return streamID{ms: id.ms, seq: id.seq + 1}
+}
+
+func (id streamID) less(other streamID) bool {
+	return id.ms < other.ms || (id.ms == other.ms && id.seq < other.seq)
+}
+
+func (id streamID) String() string {
+	return fmt.Sprintf("%d-%d", id.ms, id.seq)
+}
+
+func (q *QueueKeyspace[K, V]) messages(entries []redis.XMessage) ([]QueueMessage[V], error) {
+	msgs := make([]QueueMessage[V], 0, len(entries))
+	for _, e := range entries {
+		raw, ok := e.Values[queueValueField].(string)
+		if !ok {
+			return nil, fmt.Errorf("queue message %s has no value", e.ID)
+		}
+		val, err := q.fromRedis(raw)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, QueueMessage[V]{ID: e.ID, Value: val})
+	}
+	return msgs, nil
+}
+
+// isNoGroup reports whether err is Redis' error for a consumer group
+// or stream that doesn't exist.
+func isNoGroup(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "NOGROUP")
+}
@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type queueJob struct {
+	N int
+}
+
+func TestQueues(t *testing.T) {
+	cluster, srv := newTestCluster(t)
+	ks := NewQueueKeyspace[string, queueJob](cluster, KeyspaceConfig{
+		EncoreInternal_KeyMapper: func(s string) string { return s },
+	})
+	ctx := context.Background()
+	now := time.Now()
+	srv.SetTime(now)
+
+	jobs := func(msgs []QueueMessage[queueJob]) []int {
+		ns := make([]int, len(msgs))
+		for i, m := range msgs {
+			ns[i] = m.Value.N
+		}
+		return ns
+	}
+
+	// Popping before anything is pushed creates the group.
+	if got := must(ks.Pop(ctx, "q", "workers", "a", 10)); len(got) != 0 {
+		t.Fatalf("Pop() on empty queue = %v, want none", got)
+	}
+
+	var ids []string
+	for n := 1; n <= 3; n++ {
+		ids = append(ids, must(ks.Push(ctx, "q", queueJob{N: n})))
+	}
+	if got, want := must(ks.Len(ctx, "q")), int64(3); got != want {
+		t.Errorf("Len() = %d, want %d", got, want)
+	}
+
+	// Values are split between consumers in the group.
+	popA := must(ks.Pop(ctx, "q", "workers", "a", 2))
+	if got, want := jobs(popA), []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Pop(a) = %v, want %v", got, want)
+	}
+	popB := must(ks.Pop(ctx, "q", "workers", "b", 2))
+	if got, want := jobs(popB), []int{3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Pop(b) = %v, want %v", got, want)
+	}
+	if popA[0].ID != ids[0] {
+		t.Errorf("got message ID %s, want %s", popA[0].ID, ids[0])
+	}
+
+	// Another group gets all values, including ones pushed before it was created.
+	if got, want := jobs(must(ks.Pop(ctx, "q", "audit", "a", 10))), []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Pop(audit) = %v, want %v", got, want)
+	}
+
+	// Acknowledged messages can't be claimed.
+	if got, want := must(ks.Ack(ctx, "q", "workers", popA[0].ID, popA[0].ID)), 1; got != want {
+		t.Errorf("Ack() = %d, want %d", got, want)
+	}
+	if got := must(ks.Claim(ctx, "q", "workers", "c", time.Minute, 10)); len(got) != 0 {
+		t.Errorf("Claim() before idle = %v, want none", got)
+	}
+	srv.SetTime(now.Add(2 * time.Minute))
+	claimed := must(ks.Claim(ctx, "q", "workers", "c", time.Minute, 10))
+	if got, want := jobs(claimed), []int{2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Claim() = %v, want %v", got, want)
+	}
+
+	// Claiming a queue or group that doesn't exist yields nothing.
+	if got := must(ks.Claim(ctx, "missing", "workers", "c", 0, 10)); len(got) != 0 {
+		t.Errorf("Claim() on missing queue = %v, want none", got)
+	}
+}
+
+func TestQueues_RemoveProcessed(t *testing.T) {
+	cluster, _ := newTestCluster(t)
+	ks := NewQueueKeyspace[string, queueJob](cluster, KeyspaceConfig{
+		EncoreInternal_KeyMapper: func(s string) string { return s },
+	})
+	ctx := context.Background()
+
+	for n := 1; n <= 3; n++ {
+		must(ks.Push(ctx, "q", queueJob{N: n}))
+	}
+	workers := must(ks.Pop(ctx, "q", "workers", "a", 10))
+	audit := must(ks.Pop(ctx, "q", "audit", "a", 10))
+	ack := func(group string, msgs ...QueueMessage[queueJob]) {
+		t.Helper()
+		for _, m := range msgs {
+			must(ks.Ack(ctx, "q", group, m.ID))
+		}
+	}
+	expectLen := func(want int64) {
+		t.Helper()
+		if got := must(ks.Len(ctx, "q")); got != want {
+			t.Errorf("Len() = %d, want %d", got, want)
+		}
+	}
+
+	// Values are kept until every group has acknowledged them.
+	ack("workers", workers...)
+	expectLen(3)
+
+	// Values are removed from the start of the queue.
+	ack("audit", audit[1])
+	expectLen(3)
+	ack("audit", audit[0])
+	expectLen(1)
+
+	// Values not yet delivered to a group are kept.
+	must(ks.Push(ctx, "q", queueJob{N: 4}))
+	ack("audit", audit[2])
+	expectLen(1)
+	if got := must(ks.Pop(ctx, "q", "workers", "a", 10)); len(got) != 1 || got[0].Value.N != 4 {
+		t.Errorf("Pop() = %v, want job 4", got)
+	}
+}
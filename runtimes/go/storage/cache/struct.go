@@ -59,6 +59,17 @@ func (s *StructKeyspace[K, V]) MultiGet(ctx context.Context, keys ...K) ([]Resul
 	return s.basicKeyspace.MultiGet(ctx, keys...)
 }
 
+// MultiSet sets the values stored at multiple keys,
+// sending all the updates to the cache in a single round trip.
+//
+// The updates are not applied atomically. If any of them fail,
+// it reports the first error, and the others may have been applied.
+//
+// See https://redis.io/commands/set/ for more information.
+func (s *StructKeyspace[K, V]) MultiSet(ctx context.Context, entries ...Entry[K, V]) error {
+	return s.basicKeyspace.MultiSet(ctx, entries...)
+}
+
 // Set updates the value stored at key to val.
 //
 // See https://redis.io/commands/set/ for more information.
@@ -108,3 +119,15 @@ func (s *StructKeyspace[K, V]) GetAndDelete(ctx context.Context, key K) (oldVal
 func (s *StructKeyspace[K, V]) Delete(ctx context.Context, keys ...K) (deleted int, err error) {
 	return s.client.Delete(ctx, keys...)
 }
+
+// OnInvalidate registers fn to be called whenever a key in the keyspace is changed,
+// deleted, or expires, by any instance of the application. It's intended for keeping
+// in-process caches of keyspace values coherent with the shared cache cluster.
+//
+// The callbacks are driven by Redis keyspace notifications, which must be enabled on the
+// cluster (see https://redis.io/docs/manual/keyspace-notifications/). Encore logs a warning
+// if they aren't. fn is called sequentially on a background goroutine, and may miss
+// invalidations while disconnected from the cluster.
+func (s *StructKeyspace[K, V]) OnInvalidate(fn func(key K)) {
+	s.client.OnInvalidate(fn)
+}
@@ -0,0 +1,84 @@
+package objects
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"encore.dev/appruntime/exported/config"
+	"encore.dev/appruntime/exported/model"
+	"encore.dev/appruntime/shared/reqtrack"
+)
+
+// auditLog records each object storage operation: which object was accessed,
+// when, and by which request and user. It's separate from the regular logging:
+// it's enabled by the runtime config rather than the log level, and records
+// are logged regardless of the log level.
+type auditLog struct {
+	logger zerolog.Logger
+	rt     *reqtrack.RequestTracker
+	redact []string // sensitive object name prefixes
+}
+
+func newAuditLog(cfg *config.ObjectStorageAuditLog, rt *reqtrack.RequestTracker, rootLogger zerolog.Logger) *auditLog {
+	return &auditLog{
+		logger: rootLogger.With().Str("audit", "objects").Logger(),
+		rt:     rt,
+		redact: cfg.RedactPrefixes,
+	}
+}
+
+// hook returns the hook that records each operation when it has completed.
+func (a *auditLog) hook() Hook {
+	return Hook{
+		After: func(ctx context.Context, op *Operation, err error) {
+			a.record(a.rt.Current(), op, err)
+		},
+	}
+}
+
+func (a *auditLog) record(curr reqtrack.Current, op *Operation, err error) {
+	// Log without a level, so records aren't filtered by the log level.
+	ev := a.logger.Log().
+		Str("op", op.Name).
+		Str("bucket", op.Bucket).
+		Str("object", a.redactName(op.Object)).
+		Time("start", op.Start).
+		Dur("duration", time.Since(op.Start))
+
+	if req := curr.Req; req != nil {
+		if svc := req.Service(); svc != "" {
+			ev = ev.Str("service", svc)
+		}
+		if req.Type == model.RPCCall && req.RPCData != nil {
+			ev = ev.Str("endpoint", req.RPCData.Desc.Endpoint)
+			if uid := req.RPCData.UserID; uid != "" {
+				ev = ev.Str("uid", string(uid))
+			}
+		}
+		if req.TraceID != (model.TraceID{}) {
+			ev = ev.Str("trace_id", req.TraceID.String())
+		}
+	}
+	if err != nil {
+		ev = ev.Err(err)
+	}
+	ev.Msg("object storage operation")
+}
+
+// redactName returns the object name to log. For names under a sensitive
+// prefix, the rest of the name is replaced with a hash of the full name,
+// so accesses to the same object can still be correlated.
+func (a *auditLog) redactName(name string) string {
+	for _, prefix := range a.redact {
+		if len(name) > len(prefix) && strings.HasPrefix(name, prefix) {
+			sum := sha256.Sum256([]byte(name))
+			return prefix + "sha256:" + hex.EncodeToString(sum[:8])
+		}
+	}
+	return name
+}
@@ -0,0 +1,62 @@
+package objects
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"encore.dev/appruntime/exported/config"
+	"encore.dev/appruntime/exported/model"
+	"encore.dev/appruntime/shared/reqtrack"
+)
+
+func TestAuditLog(t *testing.T) {
+	var buf bytes.Buffer
+	// The audit log is written regardless of the log level.
+	logger := zerolog.New(&buf).Level(zerolog.ErrorLevel)
+	a := newAuditLog(&config.ObjectStorageAuditLog{RedactPrefixes: []string{"users/"}}, nil, logger)
+
+	curr := reqtrack.Current{Req: &model.Request{
+		Type: model.RPCCall,
+		RPCData: &model.RPCData{
+			Desc:   &model.RPCDesc{Service: "profiles", Endpoint: "GetAvatar"},
+			UserID: "user-1",
+		},
+	}}
+	a.record(curr, &Operation{Bucket: "avatars", Name: "download", Object: "users/1.png", Start: time.Now()}, nil)
+	a.record(reqtrack.Current{}, &Operation{Bucket: "avatars", Name: "list", Object: "users/", Start: time.Now()}, errors.New("boom"))
+
+	var records []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var rec map[string]any
+		if err := json.Unmarshal(line, &rec); err != nil {
+			t.Fatalf("invalid record %s: %v", line, err)
+		}
+		records = append(records, rec)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+
+	got := records[0]
+	for key, want := range map[string]string{
+		"audit": "objects", "op": "download", "bucket": "avatars",
+		"service": "profiles", "endpoint": "GetAvatar", "uid": "user-1",
+	} {
+		if got[key] != want {
+			t.Errorf("got %s %v, want %q", key, got[key], want)
+		}
+	}
+	if obj, _ := got["object"].(string); obj != a.redactName("users/1.png") || obj == "users/1.png" {
+		t.Errorf("got object %q, want it redacted", obj)
+	}
+
+	// The prefix itself isn't redacted.
+	if got := records[1]; got["object"] != "users/" || got["error"] != "boom" || got["uid"] != nil {
+		t.Errorf("got record %v, want the unredacted prefix and the error", got)
+	}
+}
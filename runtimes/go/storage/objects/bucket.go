@@ -3,6 +3,7 @@ package objects
 import (
 	"context"
 	"errors"
+	"io"
 	"iter"
 	"net/url"
 	"strings"
@@ -12,7 +13,9 @@ import (
 	"encore.dev/appruntime/exported/stack"
 	"encore.dev/appruntime/exported/trace2"
 	"encore.dev/appruntime/shared/reqtrack"
+	"encore.dev/storage/objects/internal/encryption"
 	"encore.dev/storage/objects/internal/providers/noop"
+	"encore.dev/storage/objects/internal/transport"
 	"encore.dev/storage/objects/internal/types"
 )
 
@@ -30,6 +33,19 @@ type Bucket struct {
 
 	// publicBaseURL, if the bucket is public
 	publicBaseURL *url.URL
+
+	// throttle tracks throttling responses from the provider.
+	throttle throttler
+
+	// encKey is the key for client-side encryption, if configured.
+	encKey *encryption.MasterKey
+
+	// quota tracks the bytes uploaded to the bucket.
+	quota *quota
+
+	// trashPrefix is the prefix removed objects are moved under,
+	// or "" if soft delete is disabled.
+	trashPrefix string
 }
 
 // BucketConfig is the configuration for a Bucket.
@@ -42,8 +58,53 @@ type BucketConfig struct {
 	// If true, the bucket will store multiple versions of each object
 	// whenever it changes, as opposed to overwriting the old version.
 	Versioned bool
+
+	// LifecycleRules are rules for automatically expiring objects,
+	// or transitioning them to cheaper storage, as they age.
+	//
+	// The rules are provisioned as the bucket's lifecycle configuration
+	// in the cloud. When running locally, expiry is simulated
+	// but transitions have no effect.
+	LifecycleRules []LifecycleRule
+}
+
+// LifecycleRule is a rule for managing the objects in a bucket as they age.
+type LifecycleRule struct {
+	// Prefix limits the rule to objects whose name starts with it.
+	// If empty the rule applies to all objects in the bucket.
+	Prefix string
+
+	// ExpireAfterDays, if positive, deletes objects
+	// the given number of days after they were created.
+	ExpireAfterDays int
+
+	// TransitionAfterDays, if positive, moves objects to TransitionTo
+	// the given number of days after they were created.
+	TransitionAfterDays int
+
+	// TransitionTo is the storage class objects are moved to.
+	// It defaults to StorageClassCold.
+	TransitionTo StorageClass
 }
 
+// StorageClass is a class of storage, trading off
+// the cost of storing objects against the cost of accessing them.
+type StorageClass string
+
+const (
+	// StorageClassInfrequent is for objects accessed less than once a month
+	// (Standard-IA on S3, Nearline on GCS).
+	StorageClassInfrequent StorageClass = "infrequent"
+
+	// StorageClassCold is for objects accessed less than once a quarter
+	// (Glacier Instant Retrieval on S3, Coldline on GCS).
+	StorageClassCold StorageClass = "cold"
+
+	// StorageClassArchive is for objects accessed less than once a year
+	// (Glacier Deep Archive on S3, Archive on GCS).
+	StorageClassArchive StorageClass = "archive"
+)
+
 func newBucket(mgr *Manager, name string) *Bucket {
 	// Look up the bkt configuration
 	bkt, ok := mgr.runtime.Buckets[name]
@@ -54,36 +115,83 @@ func newBucket(mgr *Manager, name string) *Bucket {
 			runtimeCfg: &config.Bucket{EncoreName: name},
 			impl:       &noop.BucketImpl{},
 			name:       name,
+			quota:      mgr.quotaFor(name, 0),
 		}
 	}
 
-	// Look up the provider config
+	impl := newBucketImpl(mgr, bkt)
+
+	baseURL := bkt.PublicBaseURL
+	if p, ok := impl.(types.PublicBaseURLProvider); ok && baseURL == "" {
+		baseURL = p.DefaultPublicBaseURL()
+	}
+
+	var publicBaseURL *url.URL
+	if baseURL != "" {
+		var err error
+		publicBaseURL, err = url.Parse(baseURL)
+		if err != nil {
+			mgr.rootLogger.Fatal().Msgf("invalid public base url for bucket %s: %v", name, err)
+		}
+	}
+
+	if fo := bkt.Failover; fo != nil {
+		secondary := newBucketImpl(mgr, &config.Bucket{
+			ProviderID: fo.ProviderID,
+			EncoreName: bkt.EncoreName,
+			CloudName:  fo.CloudName,
+			KeyPrefix:  bkt.KeyPrefix,
+		})
+		impl = newFailoverImpl(mgr, name, impl, secondary)
+	}
+
+	var encKey *encryption.MasterKey
+	if enc := bkt.Encryption; enc != nil {
+		var err error
+		encKey, err = encryption.ParseMasterKey(enc.KeyID, enc.MasterKey)
+		if err != nil {
+			mgr.rootLogger.Fatal().Msgf("invalid encryption key for bucket %s: %v", name, err)
+		}
+	}
+
+	if rp := bkt.RequesterPays; rp != nil {
+		if mgr.runtime.BucketProviders[bkt.ProviderID].GCS != nil && rp.BillingProject == "" {
+			mgr.rootLogger.Fatal().Msgf("requester-pays bucket %s requires a billing project", name)
+		}
+		mgr.rootLogger.Info().Str("bucket", name).Str("billing_project", rp.BillingProject).
+			Msg("objects: billing requests to requester-pays bucket")
+	}
+
+	var trashPrefix string
+	if sd := bkt.SoftDelete; sd != nil {
+		trashPrefix = sd.TrashPrefix
+		if trashPrefix == "" {
+			trashPrefix = defaultTrashPrefix
+		}
+	}
+
+	return &Bucket{
+		mgr:             mgr,
+		runtimeCfg:      bkt,
+		impl:            impl,
+		name:            name,
+		baseCloudPrefix: bkt.KeyPrefix,
+		publicBaseURL:   publicBaseURL,
+		encKey:          encKey,
+		quota:           mgr.quotaFor(name, bkt.UploadQuota),
+		trashPrefix:     trashPrefix,
+	}
+}
+
+// newBucketImpl creates the provider implementation for the given bucket config.
+func newBucketImpl(mgr *Manager, bkt *config.Bucket) types.BucketImpl {
 	provider := mgr.runtime.BucketProviders[bkt.ProviderID]
 
 	tried := make([]string, 0, len(mgr.providers))
 	for _, p := range mgr.providers {
 		if p.Matches(provider) {
-			impl := p.NewBucket(provider, bkt)
-
-			var publicBaseURL *url.URL
-			if bkt.PublicBaseURL != "" {
-				var err error
-				publicBaseURL, err = url.Parse(bkt.PublicBaseURL)
-				if err != nil {
-					mgr.rootLogger.Fatal().Msgf("invalid public base url for bucket %s: %v", name, err)
-				}
-			}
-
-			return &Bucket{
-				mgr:             mgr,
-				runtimeCfg:      bkt,
-				impl:            impl,
-				name:            name,
-				baseCloudPrefix: bkt.KeyPrefix,
-				publicBaseURL:   publicBaseURL,
-			}
+			return p.NewBucket(provider, bkt)
 		}
-
 		tried = append(tried, p.ProviderName())
 	}
 
@@ -102,11 +210,18 @@ func (b *Bucket) Upload(ctx context.Context, object string, options ...UploadOpt
 		o.applyUpload(&opt)
 	}
 
+	ctx, release := b.mgr.trackOperation(ctx)
 	w := &Writer{
-		bkt: b,
-		ctx: ctx,
-		obj: object,
-		opt: opt,
+		bkt:     b,
+		ctx:     ctx,
+		release: release,
+		obj:     object,
+		opt:     opt,
+		start:   time.Now(),
+		stats:   newTransferStats(opt.stats),
+	}
+	if w.stats != nil {
+		w.ctx = transport.WithStats(ctx, w.stats)
 	}
 
 	curr := b.mgr.rt.Current()
@@ -123,7 +238,8 @@ func (b *Bucket) Upload(ctx context.Context, object string, options ...UploadOpt
 			Attrs: trace2.BucketObjectAttributes{
 				ContentType: ptrOrNil(opt.attrs.ContentType),
 			},
-			Stack: stack.Build(1),
+			Stack:  stack.Build(1),
+			KMSKey: ptrOrNil(opt.kmsKey),
 		})
 	}
 
@@ -153,13 +269,27 @@ func (b *Bucket) PublicURL(object string, options ...PublicURLOption) *url.URL {
 type Writer struct {
 	bkt *Bucket
 
-	ctx context.Context
-	obj string
+	ctx   context.Context
+	obj   string
+	start time.Time
+
+	// release marks the upload as completed for shutdown.
+	release func()
 
 	opt uploadOptions
 
 	// Initialized on first write
-	u types.Uploader
+	u     types.Uploader
+	hooks *hookRun
+
+	// reserved is the number of bytes reserved against the bucket's quota.
+	reserved int64
+
+	// sum is the checksum of the content as uploaded.
+	sum *checksum
+
+	// stats records the upload's requests, if requested with WithTransferStats.
+	stats *transport.Stats
 
 	// Set if tracing
 	curr         reqtrack.Current
@@ -169,7 +299,32 @@ type Writer struct {
 // Write writes data to the object being uploaded.
 func (w *Writer) Write(p []byte) (int, error) {
 	u := w.initUpload()
-	return u.Write(p)
+	if err := w.reserveQuota(u, len(p)); err != nil {
+		return 0, err
+	}
+	n, err := u.Write(p)
+	return n, mapTimeout(w.ctx, "upload", w.start, err)
+}
+
+// reserveQuota reserves n bytes against the bucket's quota.
+// If the quota is exceeded the upload is aborted, so that closing
+// the writer doesn't complete the upload with partial content.
+func (w *Writer) reserveQuota(u types.Uploader, n int) error {
+	switch u.(type) {
+	case *errUploader, *completedUploader:
+		// Nothing is being uploaded.
+		return nil
+	}
+	if w.opt.noQuota {
+		return nil
+	}
+	if err := w.bkt.quota.reserve(int64(n)); err != nil {
+		u.Abort(err)
+		w.u = &errUploader{err: err}
+		return err
+	}
+	w.reserved += int64(n)
+	return nil
 }
 
 // Abort aborts the upload.
@@ -177,14 +332,30 @@ func (w *Writer) Abort(err error) {
 	if err == nil {
 		err = errors.New("upload aborted")
 	}
+	defer w.release()
 	u := w.initUpload()
 	u.Abort(err)
+	w.hooks.end(err)
+	w.bkt.quota.release(w.reserved)
+	w.reserved = 0
+	w.opt.stats.report(w.stats)
 }
 
 // Close closes the upload, completing the upload if no errors occurred.
 func (w *Writer) Close() error {
+	defer w.release()
 	u := w.initUpload()
 	attrs, err := u.Complete()
+	w.bkt.observeThrottle("upload", 1, err)
+	err = mapTimeout(w.ctx, "upload", w.start, err)
+	w.hooks.end(err)
+	if err == nil {
+		w.bkt.quota.commit(w.reserved)
+	} else {
+		w.bkt.quota.release(w.reserved)
+	}
+	w.reserved = 0
+	w.opt.stats.report(w.stats)
 
 	if w.curr.Trace != nil {
 		params := trace2.BucketObjectUploadEndParams{
@@ -201,6 +372,10 @@ func (w *Writer) Close() error {
 			params.Size = uint64(attrs.Size)
 			params.Version = ptrOrNil(attrs.Version)
 		}
+		if err == nil && w.sum != nil {
+			sum := w.sum.String()
+			params.Checksum = &sum
+		}
 		w.curr.Trace.BucketObjectUploadEnd(params)
 	}
 
@@ -209,14 +384,97 @@ func (w *Writer) Close() error {
 
 func (w *Writer) initUpload() types.Uploader {
 	if w.u == nil {
+		hooks, err := w.bkt.startOperation(w.ctx, "upload", w.obj)
+		w.hooks = hooks
+		if err != nil {
+			w.u = &errUploader{err: err}
+			return w.u
+		}
+
+		if err := w.bkt.throttle.wait(w.ctx); err != nil {
+			w.u = &errUploader{err: err}
+			return w.u
+		}
+
+		if err := checkUploadAttrs(w.opt.attrs); err != nil {
+			w.u = &errUploader{err: err}
+			return w.u
+		}
+
+		object := w.bkt.toCloudObject(w.obj)
+		pre := w.preconditions()
+		var recheck func() (*types.ObjectAttrs, error)
+		if key := w.opt.idempotencyKey; key != "" {
+			done, idempotencyPre, err := checkIdempotency(w.ctx, w.bkt.impl, object, key)
+			if err != nil {
+				w.u = &errUploader{err: err}
+				return w.u
+			} else if done != nil {
+				// The object was already uploaded with this key.
+				w.u = &completedUploader{attrs: done}
+				return w.u
+			}
+
+			// Make the upload conditional on no other upload completing first,
+			// unless it has preconditions of its own, so concurrent retries
+			// don't both write the object.
+			if pre == (types.Preconditions{}) {
+				pre = idempotencyPre
+			}
+			recheck = func() (*types.ObjectAttrs, error) {
+				done, _, err := checkIdempotency(w.ctx, w.bkt.impl, object, key)
+				return done, err
+			}
+		}
+
+		// Fail early if the upload is known to exceed the quota.
+		if err := w.bkt.quota.check(w.opt.size); err != nil && !w.opt.noQuota {
+			w.u = &errUploader{err: err}
+			return w.u
+		}
+
+		attrs := w.opt.attrs
+		attrs.IdempotencyKey = w.opt.idempotencyKey
+		attrs.KMSKey, err = w.bkt.mgr.kmsKeyID(w.bkt.runtimeCfg, w.opt.kmsKey)
+		if err != nil {
+			w.u = &errUploader{err: err}
+			return w.u
+		}
+		size := w.opt.size
+
+		var dataKey []byte
+		if w.opt.encrypt {
+			var err error
+			dataKey, attrs.Encryption, err = w.bkt.newDataKey()
+			if err != nil {
+				w.u = &errUploader{err: err}
+				return w.u
+			}
+			if size > 0 {
+				size = encryption.EncryptedSize(size)
+			}
+		}
+
+		w.sum = newChecksum(w.opt.checksum)
 		u, err := w.bkt.impl.Upload(types.UploadData{
-			Ctx:    w.ctx,
-			Object: w.bkt.toCloudObject(w.obj),
-			Attrs:  w.opt.attrs,
-			Pre: types.Preconditions{
-				NotExists: w.opt.pre.NotExists,
-			},
+			Ctx:      w.ctx,
+			Object:   object,
+			Attrs:    attrs,
+			Pre:      pre,
+			Size:     size,
+			PartSize: w.opt.partSize,
+			Checksum: types.ChecksumAlgorithm(w.sum.alg),
 		})
+		if err == nil {
+			// Checksum the content as stored, after any encryption.
+			u = &checksumUploader{Uploader: u, object: w.obj, sum: w.sum}
+		}
+		if err == nil && dataKey != nil {
+			u, err = newEncryptingUploader(u, dataKey)
+		}
+		if err == nil && recheck != nil {
+			u = &idempotentUploader{Uploader: u, recheck: recheck}
+		}
 		if err != nil {
 			w.u = &errUploader{err: err}
 		} else {
@@ -227,6 +485,14 @@ func (w *Writer) initUpload() types.Uploader {
 	return w.u
 }
 
+func (w *Writer) preconditions() types.Preconditions {
+	pre := types.Preconditions{NotExists: w.opt.pre.NotExists}
+	if m := w.opt.match; m != nil {
+		pre.MatchETag, pre.MatchVersion = m.ETag, m.Version
+	}
+	return pre
+}
+
 type errUploader struct {
 	err error
 }
@@ -268,19 +534,80 @@ func (b *Bucket) Download(ctx context.Context, object string, options ...Downloa
 		})
 	}
 
-	r, err := b.impl.Download(types.DownloadData{
-		Ctx:     ctx,
-		Object:  b.toCloudObject(object),
-		Version: opt.version,
+	stats := newTransferStats(opt.stats)
+	if stats != nil {
+		ctx = transport.WithStats(ctx, stats)
+	}
+	ctx, release := b.mgr.trackOperation(ctx)
+
+	start := time.Now()
+	var (
+		r   types.Downloader
+		sum *checksumDownloader
+	)
+	err := b.do(ctx, "download", object, func() (err error) {
+		data := types.DownloadData{
+			Ctx:     ctx,
+			Object:  b.toCloudObject(object),
+			Version: opt.version,
+			Raw:     opt.raw,
+		}
+		if opt.byteRange != nil {
+			data.Range, err = b.resolveRange(ctx, object, opt)
+			if err != nil {
+				return err
+			}
+		}
+		r, err = b.impl.Download(data)
+		if err == nil && opt.byteRange == nil {
+			// Verify the content as stored, before decrypting and decompressing it.
+			if sum = newChecksumDownloader(r, object, opt.checksum); sum != nil {
+				r = sum
+			}
+		}
+		return err
 	})
-	return &Reader{r: r, err: err, curr: curr, startEventID: startEventID}
+
+	// Ranges are of the content as stored, and resolveRange
+	// has checked it's neither encrypted nor encoded.
+	var rc io.ReadCloser = r
+	if err == nil && !opt.raw && opt.byteRange == nil {
+		r, err = b.decrypt(ctx, object, opt.version, r)
+		if err == nil {
+			rc, err = decompress(r)
+		}
+	}
+	if err != nil {
+		// There's nothing to read, so the download is already complete.
+		release()
+	}
+	return &Reader{
+		ctx:          ctx,
+		release:      release,
+		start:        start,
+		r:            rc,
+		err:          err,
+		sum:          sum,
+		stats:        stats,
+		reportStats:  opt.stats,
+		curr:         curr,
+		startEventID: startEventID,
+	}
 }
 
 // Reader is the reader for an object being downloaded from a bucket.
 type Reader struct {
+	ctx       context.Context
+	release   func() // marks the download as completed for shutdown
+	start     time.Time
 	err       error // any error encountered
-	r         types.Downloader
+	r         io.ReadCloser
 	totalRead uint64
+	sum       *checksumDownloader // nil if the content can't be verified
+
+	// Set if requested with WithTransferStats
+	stats       *transport.Stats
+	reportStats *TransferStats
 
 	// Set if traced
 	traceCompleted bool
@@ -300,6 +627,9 @@ func (r *Reader) Read(p []byte) (int, error) {
 	}
 
 	n, err := r.r.Read(p)
+	if err != nil && err != io.EOF {
+		err = mapTimeout(r.ctx, "download", r.start, err)
+	}
 	r.err = err
 	r.totalRead += uint64(n)
 	return n, err
@@ -308,12 +638,15 @@ func (r *Reader) Read(p []byte) (int, error) {
 // Close closes the reader.
 // It must be called to release resources.
 func (r *Reader) Close() error {
+	defer r.release()
 	defer r.completeTrace()
 	if r.err != nil {
+		r.reportStats.report(r.stats)
 		return r.err
 	}
 
 	r.err = r.r.Close()
+	r.reportStats.report(r.stats)
 	return r.err
 }
 
@@ -324,7 +657,7 @@ func (r *Reader) completeTrace() {
 
 	r.traceCompleted = true
 	if r.curr.Trace != nil && r.startEventID != 0 {
-		r.curr.Trace.BucketObjectDownloadEnd(trace2.BucketObjectDownloadEndParams{
+		params := trace2.BucketObjectDownloadEndParams{
 			StartID: r.startEventID,
 			EventParams: trace2.EventParams{
 				TraceID: r.curr.Req.TraceID,
@@ -333,7 +666,12 @@ func (r *Reader) completeTrace() {
 			},
 			Err:  r.err,
 			Size: r.totalRead,
-		})
+		}
+		if r.sum != nil && r.sum.done {
+			sum := r.sum.sum.String()
+			params.Checksum = &sum
+		}
+		r.curr.Trace.BucketObjectDownloadEnd(params)
 	}
 }
 
@@ -345,13 +683,20 @@ type Query struct {
 
 	// Maximum number of objects to return. Zero means no limit.
 	Limit int64
+
+	// Delimiter, if set, lists objects like directories: objects whose names
+	// contain the delimiter after the prefix are listed as a single entry for
+	// their common prefix, up to and including the delimiter, with IsPrefix set.
+	// It's typically "/".
+	Delimiter string
 }
 
 func (b *Bucket) mapQuery(ctx context.Context, q *Query) types.ListData {
 	return types.ListData{
-		Ctx:    ctx,
-		Prefix: b.baseCloudPrefix + q.Prefix,
-		Limit:  ptrOrNil(q.Limit),
+		Ctx:       ctx,
+		Prefix:    b.baseCloudPrefix + q.Prefix,
+		Limit:     ptrOrNil(q.Limit),
+		Delimiter: q.Delimiter,
 	}
 }
 
@@ -366,21 +711,53 @@ type ObjectAttrs struct {
 	// The content type of the object, if set during upload.
 	ContentType string
 
+	// The content encoding of the object, such as "gzip", if any.
+	ContentEncoding string
+
 	// The size of the object, in bytes.
 	Size int64
 
 	// The computed ETag of the object.
 	ETag string
+
+	// Whether the object is encrypted client-side, using WithEncryption.
+	// If so, Size is the size of the encrypted content.
+	Encrypted bool
+
+	// For objects in the trash, the name of the object it was removed as
+	// and when it was removed. See Restore.
+	TrashedObject string
+	TrashedAt     time.Time
+
+	// The user-defined metadata and tags of the object,
+	// set during upload with WithMetadata and WithTags.
+	Metadata map[string]string
+	Tags     map[string]string
+
+	// The server-side encryption of the object as reported by the provider,
+	// such as "aws:kms" for objects encrypted with SSE-KMS on S3, if any.
+	ServerSideEncryption string
 }
 
 func (b *Bucket) mapAttrs(attrs *types.ObjectAttrs) *ObjectAttrs {
-	return &ObjectAttrs{
-		Name:        b.fromCloudObject(attrs.Object),
-		Version:     attrs.Version,
-		ContentType: attrs.ContentType,
-		Size:        attrs.Size,
-		ETag:        attrs.ETag,
+	a := &ObjectAttrs{
+		Name:            b.fromCloudObject(attrs.Object),
+		Version:         attrs.Version,
+		ContentType:     attrs.ContentType,
+		ContentEncoding: attrs.ContentEncoding,
+		Size:            attrs.Size,
+		ETag:            attrs.ETag,
+		Encrypted:       attrs.Encryption != nil,
+		Metadata:        attrs.UserMetadata,
+		Tags:            attrs.Tags,
+
+		ServerSideEncryption: attrs.ServerSideEncryption,
+	}
+	if t := attrs.Trash; t != nil {
+		a.TrashedObject = b.fromCloudObject(t.Object)
+		a.TrashedAt = t.At
 	}
+	return a
 }
 
 // ListEntry describes an objects during listing.
@@ -391,13 +768,27 @@ type ListEntry struct {
 	Size int64
 	// The computed ETag of the object.
 	ETag string
+	// When the object was last modified, or the zero time if unknown.
+	LastModified time.Time
+	// The user-defined metadata and tags of the object.
+	// They're only included by providers that return them when listing,
+	// which S3 does not; use Attrs to retrieve them there.
+	Metadata map[string]string
+	Tags     map[string]string
+	// Whether the entry is a common prefix of objects, when listing
+	// with a Query.Delimiter. If so only Name is set, to the prefix.
+	IsPrefix bool
 }
 
 func (b *Bucket) mapListEntry(entry *types.ListEntry) *ListEntry {
 	return &ListEntry{
-		Name: b.fromCloudObject(entry.Object),
-		Size: entry.Size,
-		ETag: entry.ETag,
+		Name:         b.fromCloudObject(entry.Object),
+		Size:         entry.Size,
+		ETag:         entry.ETag,
+		LastModified: entry.LastModified,
+		Metadata:     entry.UserMetadata,
+		Tags:         entry.Tags,
+		IsPrefix:     entry.Prefix,
 	}
 }
 
@@ -412,52 +803,57 @@ type SignedDownloadURL struct {
 }
 
 // List lists objects in the bucket.
+//
+// If soft delete is enabled for the bucket, objects in the trash
+// are not listed unless WithTrashed is given.
 func (b *Bucket) List(ctx context.Context, query *Query, options ...ListOption) iter.Seq2[*ListEntry, error] {
+	var opt listOptions
+	for _, o := range options {
+		o.applyList(&opt)
+	}
+	// Trashed objects are only listed if requested, or when listing the trash itself.
+	skipTrash := b.trashPrefix != "" && !opt.trashed && !b.inTrash(query.Prefix)
+
 	return func(yield func(*ListEntry, error) bool) {
+		start := time.Now()
+
 		// Tracing state
 		var (
 			listErr  error
 			observed uint64
 			hasMore  bool
 		)
+		endTrace := b.traceList(query)
+		defer func() { endTrace(listErr, observed, hasMore) }()
 
-		curr := b.mgr.rt.Current()
-		if curr.Req != nil && curr.Trace != nil {
-			startEventID := curr.Trace.BucketListObjectsStart(trace2.BucketListObjectsStartParams{
-				EventParams: trace2.EventParams{
-					TraceID: curr.Req.TraceID,
-					SpanID:  curr.Req.SpanID,
-					Goid:    curr.Goctr,
-				},
-				Bucket: b.name,
-				Prefix: ptrOrNil(query.Prefix),
-				Stack:  stack.Build(1),
-			})
-
-			defer curr.Trace.BucketListObjectsEnd(trace2.BucketListObjectsEndParams{
-				StartID: startEventID,
-				EventParams: trace2.EventParams{
-					TraceID: curr.Req.TraceID,
-					SpanID:  curr.Req.SpanID,
-					Goid:    curr.Goctr,
-				},
-				Err:      listErr,
-				Observed: observed,
-				HasMore:  hasMore,
-			})
+		hooks, err := b.startOperation(ctx, "list", query.Prefix)
+		defer func() { hooks.end(listErr) }()
+		if err != nil {
+			listErr = err
+			yield(nil, err)
+			return
 		}
 
-		iter := b.impl.List(b.mapQuery(ctx, query))
+		data := b.mapQuery(ctx, query)
+		data.PageSize = opt.pageSize
+		iter := b.impl.List(data)
 		for entry, err := range iter {
 			if err != nil {
+				err = mapTimeout(ctx, "list", start, err)
 				listErr = err
 				if !yield(nil, err) {
 					return
 				}
+				continue
+			}
+
+			e := b.mapListEntry(entry)
+			if skipTrash && b.inTrash(e.Name) {
+				continue
 			}
 
 			observed++
-			if !yield(b.mapListEntry(entry), nil) {
+			if !yield(e, nil) {
 				// Consumer didn't want any more entries; set hasMore = true
 				hasMore = true
 				return
@@ -466,6 +862,37 @@ func (b *Bucket) List(ctx context.Context, query *Query, options ...ListOption)
 	}
 }
 
+// traceList records the start of listing objects, if tracing,
+// and returns a function for recording its end.
+// It's called by the listing operation, which is left out of the stack.
+func (b *Bucket) traceList(query *Query) (end func(err error, observed uint64, hasMore bool)) {
+	curr := b.mgr.rt.Current()
+	if curr.Req == nil || curr.Trace == nil {
+		return func(error, uint64, bool) {}
+	}
+
+	eventParams := trace2.EventParams{
+		TraceID: curr.Req.TraceID,
+		SpanID:  curr.Req.SpanID,
+		Goid:    curr.Goctr,
+	}
+	startEventID := curr.Trace.BucketListObjectsStart(trace2.BucketListObjectsStartParams{
+		EventParams: eventParams,
+		Bucket:      b.name,
+		Prefix:      ptrOrNil(query.Prefix),
+		Stack:       stack.Build(2),
+	})
+	return func(err error, observed uint64, hasMore bool) {
+		curr.Trace.BucketListObjectsEnd(trace2.BucketListObjectsEndParams{
+			StartID:     startEventID,
+			EventParams: eventParams,
+			Err:         err,
+			Observed:    observed,
+			HasMore:     hasMore,
+		})
+	}
+}
+
 // Remove removes an object from the bucket.
 func (b *Bucket) Remove(ctx context.Context, object string, options ...RemoveOption) error {
 	var opts removeOptions
@@ -503,15 +930,25 @@ func (b *Bucket) Remove(ctx context.Context, object string, options ...RemoveOpt
 		})
 	}
 
-	removeErr = b.impl.Remove(types.RemoveData{
-		Ctx:     ctx,
-		Object:  b.toCloudObject(object),
-		Version: opts.version,
-	})
-
+	if b.trashPrefix != "" && opts.version == "" && !b.inTrash(object) {
+		removeErr = b.moveToTrash(ctx, object)
+	} else {
+		removeErr = b.removeNow(ctx, object, opts.version)
+	}
 	return removeErr
 }
 
+// removeNow permanently removes an object, regardless of soft delete.
+func (b *Bucket) removeNow(ctx context.Context, object, version string) error {
+	return b.do(ctx, "remove", object, func() error {
+		return b.impl.Remove(types.RemoveData{
+			Ctx:     ctx,
+			Object:  b.toCloudObject(object),
+			Version: version,
+		})
+	})
+}
+
 var (
 	// ErrObjectNotFound is returned when requested object does not exist in the bucket.
 	ErrObjectNotFound = types.ErrObjectNotExist
@@ -523,6 +960,29 @@ var (
 	// ErrInvalidArgument is returned when an argument for an operation is invalid or out
 	// of bounds. Such as when a too long time-to-live is passed to a sign URL operation.
 	ErrInvalidArgument = types.ErrInvalidArgument
+
+	// ErrThrottled is returned when the provider keeps rejecting requests
+	// due to rate limiting, even after backing off and retrying.
+	ErrThrottled = types.ErrThrottled
+
+	// ErrDecryptionFailed is returned when downloading an encrypted object
+	// that can't be decrypted, such as when the bucket doesn't have the key
+	// it was encrypted with or the content has been tampered with.
+	ErrDecryptionFailed = types.ErrDecryptionFailed
+
+	// ErrQuotaExceeded is returned when an upload would exceed
+	// the bucket's upload quota. See QuotaUsage.
+	ErrQuotaExceeded = types.ErrQuotaExceeded
+
+	// ErrUploadNotFound is returned when a multipart upload does not exist,
+	// such as when it has already been completed or aborted.
+	ErrUploadNotFound = types.ErrUploadNotExist
+
+	// ErrChecksumMismatch is returned when the checksum of an object's content,
+	// computed while uploading or downloading it, doesn't match the checksum the
+	// provider has for the stored object. It means the content was corrupted,
+	// in transit or at rest.
+	ErrChecksumMismatch = types.ErrChecksumMismatch
 )
 
 // Attrs returns the attributes of an object in the bucket.
@@ -575,10 +1035,13 @@ func (b *Bucket) Attrs(ctx context.Context, object string, options ...AttrsOptio
 		}()
 	}
 
-	attrs, attrsErr = b.impl.Attrs(types.AttrsData{
-		Ctx:     ctx,
-		Object:  b.toCloudObject(object),
-		Version: opt.version,
+	attrsErr = b.do(ctx, "attrs", object, func() (err error) {
+		attrs, err = b.impl.Attrs(types.AttrsData{
+			Ctx:     ctx,
+			Object:  b.toCloudObject(object),
+			Version: opt.version,
+		})
+		return err
 	})
 	if attrsErr != nil {
 		return nil, attrsErr
@@ -602,10 +1065,14 @@ func (b *Bucket) SignedUploadURL(ctx context.Context, object string, options ...
 	if opt.TTL > 7*24*time.Hour {
 		return nil, types.ErrInvalidArgument
 	}
-	url, err := b.impl.SignedUploadURL(types.UploadURLData{
-		Ctx:    ctx,
-		Object: b.toCloudObject(object),
-		TTL:    opt.TTL,
+	var url string
+	err := b.do(ctx, "signed_upload_url", object, func() (err error) {
+		url, err = b.impl.SignedUploadURL(types.UploadURLData{
+			Ctx:    ctx,
+			Object: b.toCloudObject(object),
+			TTL:    opt.TTL,
+		})
+		return err
 	})
 	if err != nil {
 		return nil, err
@@ -628,10 +1095,14 @@ func (b *Bucket) SignedDownloadURL(ctx context.Context, object string, options .
 	if opt.TTL > 7*24*time.Hour {
 		return nil, types.ErrInvalidArgument
 	}
-	url, err := b.impl.SignedDownloadURL(types.DownloadURLData{
-		Ctx:    ctx,
-		Object: b.toCloudObject(object),
-		TTL:    opt.TTL,
+	var url string
+	err := b.do(ctx, "signed_download_url", object, func() (err error) {
+		url, err = b.impl.SignedDownloadURL(types.DownloadURLData{
+			Ctx:    ctx,
+			Object: b.toCloudObject(object),
+			TTL:    opt.TTL,
+		})
+		return err
 	})
 	if err != nil {
 		return nil, err
@@ -688,10 +1159,13 @@ func (b *Bucket) Exists(ctx context.Context, object string, options ...ExistsOpt
 		}()
 	}
 
-	attrs, attrsErr = b.impl.Attrs(types.AttrsData{
-		Ctx:     ctx,
-		Object:  b.toCloudObject(object),
-		Version: opt.version,
+	attrsErr = b.do(ctx, "exists", object, func() (err error) {
+		attrs, err = b.impl.Attrs(types.AttrsData{
+			Ctx:     ctx,
+			Object:  b.toCloudObject(object),
+			Version: opt.version,
+		})
+		return err
 	})
 	if errors.Is(attrsErr, ErrObjectNotFound) {
 		return false, nil
@@ -0,0 +1,124 @@
+package objects
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// ChecksumAlgorithm is an algorithm for checksumming the content of objects
+// as they're uploaded and downloaded, to detect silent corruption.
+// See WithChecksum.
+type ChecksumAlgorithm string
+
+const (
+	// ChecksumCRC32C is the CRC32 checksum using the Castagnoli polynomial.
+	// It's cheap to compute and supported by both S3 and GCS. It's the default.
+	ChecksumCRC32C ChecksumAlgorithm = "crc32c"
+
+	// ChecksumSHA256 is the SHA-256 hash. It's only verified on S3,
+	// as GCS doesn't keep SHA-256 checksums of objects.
+	ChecksumSHA256 ChecksumAlgorithm = "sha256"
+)
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// checksum computes the checksum of the content written to it.
+type checksum struct {
+	alg ChecksumAlgorithm
+	h   hash.Hash
+}
+
+func newChecksum(alg ChecksumAlgorithm) *checksum {
+	if alg == ChecksumSHA256 {
+		return &checksum{alg: alg, h: sha256.New()}
+	}
+	return &checksum{alg: ChecksumCRC32C, h: crc32.New(crc32cTable)}
+}
+
+func (c *checksum) Write(p []byte) (int, error) {
+	return c.h.Write(p)
+}
+
+// value returns the checksum of the content written so far,
+// base64-encoded like providers report them.
+func (c *checksum) value() string {
+	return base64.StdEncoding.EncodeToString(c.h.Sum(nil))
+}
+
+// String formats the checksum for traces, like "crc32c:yZRlqg==".
+func (c *checksum) String() string {
+	return string(c.alg) + ":" + c.value()
+}
+
+// verify checks the checksum against the checksums the provider has for the object.
+// If the provider has none for the algorithm it can't be verified, and it returns nil.
+func (c *checksum) verify(object string, stored types.Checksums) error {
+	want := stored.Get(types.ChecksumAlgorithm(c.alg))
+	if want == "" {
+		return nil
+	} else if got := c.value(); got != want {
+		return fmt.Errorf("%w: object %s has %s checksum %s, but its content has %s",
+			types.ErrChecksumMismatch, object, c.alg, want, got)
+	}
+	return nil
+}
+
+// checksumUploader checksums the content written to the underlying uploader,
+// and verifies it against the checksum the provider reports once it completes.
+type checksumUploader struct {
+	types.Uploader
+	object string
+	sum    *checksum
+}
+
+func (u *checksumUploader) Write(p []byte) (int, error) {
+	n, err := u.Uploader.Write(p)
+	_, _ = u.sum.Write(p[:n])
+	return n, err
+}
+
+func (u *checksumUploader) Complete() (*types.ObjectAttrs, error) {
+	attrs, err := u.Uploader.Complete()
+	if err == nil && attrs != nil {
+		err = u.sum.verify(u.object, attrs.Checksums)
+	}
+	return attrs, err
+}
+
+// checksumDownloader checksums the content read from the underlying downloader,
+// and verifies it against the stored checksum once it's been read in full.
+type checksumDownloader struct {
+	types.Downloader
+	object string
+	sum    *checksum
+	stored types.Checksums
+	done   bool // whether the content has been read in full
+}
+
+// newChecksumDownloader wraps d to verify the content read from it,
+// if the provider reports the checksums of the object.
+func newChecksumDownloader(d types.Downloader, object string, alg ChecksumAlgorithm) *checksumDownloader {
+	r, ok := d.(types.ChecksumReporter)
+	if !ok {
+		return nil
+	}
+	return &checksumDownloader{Downloader: d, object: object, sum: newChecksum(alg), stored: r.Checksums()}
+}
+
+func (d *checksumDownloader) Read(p []byte) (int, error) {
+	n, err := d.Downloader.Read(p)
+	_, _ = d.sum.Write(p[:n])
+	if err == io.EOF && !d.done {
+		d.done = true
+		if verr := d.sum.verify(d.object, d.stored); verr != nil {
+			return n, verr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,121 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// checksumImpl is a bucket that reports the given checksums
+// for objects, as a provider would for their stored content.
+type checksumImpl struct {
+	*multiImpl
+	stored types.Checksums
+}
+
+func (c *checksumImpl) Upload(data types.UploadData) (types.Uploader, error) {
+	u, err := c.multiImpl.Upload(data)
+	return &checksumReportingUploader{Uploader: u, stored: c.stored}, err
+}
+
+func (c *checksumImpl) Download(data types.DownloadData) (types.Downloader, error) {
+	d, err := c.multiImpl.Download(data)
+	return &checksumReportingDownloader{Downloader: d, stored: c.stored}, err
+}
+
+type checksumReportingUploader struct {
+	types.Uploader
+	stored types.Checksums
+}
+
+func (u *checksumReportingUploader) Complete() (*types.ObjectAttrs, error) {
+	attrs, err := u.Uploader.Complete()
+	if attrs != nil {
+		attrs.Checksums = u.stored
+	}
+	return attrs, err
+}
+
+type checksumReportingDownloader struct {
+	types.Downloader
+	stored types.Checksums
+}
+
+func (d *checksumReportingDownloader) Checksums() types.Checksums { return d.stored }
+
+func newChecksumTestBucket(stored types.Checksums) *Bucket {
+	return newTestBucket(&checksumImpl{
+		multiImpl: &multiImpl{objects: map[types.CloudObject]*multiObject{
+			"a.txt": {data: []byte("test")},
+		}},
+		stored: stored,
+	})
+}
+
+func TestUpload_Checksum(t *testing.T) {
+	tests := []struct {
+		name    string
+		stored  types.Checksums
+		options []UploadOption
+		wantErr error
+	}{
+		{name: "match", stored: types.Checksums{CRC32C: "hqBywA=="}},
+		{name: "mismatch", stored: types.Checksums{CRC32C: "AAAAAA=="}, wantErr: ErrChecksumMismatch},
+		{name: "unknown", stored: types.Checksums{}},
+		{
+			name:    "sha256",
+			stored:  types.Checksums{CRC32C: "AAAAAA==", SHA256: "n4bQgYhMfWWaL+qgxVrQFaO/TxsrC4Is0V1sFbDwCgg="},
+			options: []UploadOption{WithChecksum(ChecksumSHA256)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bkt := newChecksumTestBucket(tt.stored)
+			w := bkt.Upload(context.Background(), "b.txt", tt.options...)
+			if _, err := w.Write([]byte("test")); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Close(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got err %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDownload_Checksum(t *testing.T) {
+	tests := []struct {
+		name    string
+		stored  types.Checksums
+		options []DownloadOption
+		wantErr error
+	}{
+		{name: "match", stored: types.Checksums{CRC32C: "hqBywA=="}},
+		{name: "mismatch", stored: types.Checksums{CRC32C: "AAAAAA=="}, wantErr: ErrChecksumMismatch},
+		{name: "unknown", stored: types.Checksums{}},
+		{
+			name:    "sha256 mismatch",
+			stored:  types.Checksums{CRC32C: "hqBywA==", SHA256: "AAAA"},
+			options: []DownloadOption{WithChecksum(ChecksumSHA256)},
+			wantErr: ErrChecksumMismatch,
+		},
+		{
+			// Ranges can't be verified against the object's checksum.
+			name:    "range",
+			stored:  types.Checksums{CRC32C: "AAAAAA=="},
+			options: []DownloadOption{WithRange(1, 3)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bkt := newChecksumTestBucket(tt.stored)
+			r := bkt.Download(context.Background(), "a.txt", tt.options...)
+			defer func() { _ = r.Close() }()
+			if _, err := io.ReadAll(r); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got err %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// Copy copies the object src to dst, replacing dst if it exists,
+// and returns the attributes of the copy.
+//
+// By default src is an object in the same bucket. To copy an object from
+// another bucket declared in the app, use FromBucket.
+//
+// Objects are copied server-side when the provider supports it, without
+// downloading and uploading their contents, and are otherwise streamed.
+// Objects encrypted with WithEncryption are only copied server-side within
+// the same bucket; copies from other buckets are re-encrypted for this bucket.
+//
+// If src does not exist, it returns ErrObjectNotFound.
+func (b *Bucket) Copy(ctx context.Context, src, dst string, options ...CopyOption) (*ObjectAttrs, error) {
+	var opt copyOptions
+	for _, o := range options {
+		o.applyCopy(&opt)
+	}
+	srcBkt := b
+	if opt.from != nil {
+		srcBkt = opt.from
+	}
+	return b.copyFrom(ctx, srcBkt, src, dst)
+}
+
+// Move moves the object src to dst within the bucket, replacing dst if it
+// exists, and returns the attributes of the moved object.
+//
+// The object is copied like with Copy and src is then removed. The removal
+// bypasses soft delete, since the object's contents are kept at dst.
+// If removing src fails, the error is returned and both objects exist.
+//
+// If src does not exist, it returns ErrObjectNotFound.
+func (b *Bucket) Move(ctx context.Context, src, dst string) (*ObjectAttrs, error) {
+	if src == dst {
+		return b.Attrs(ctx, src)
+	}
+	attrs, err := b.copyFrom(ctx, b, src, dst)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.removeNow(ctx, src, ""); err != nil && !errors.Is(err, ErrObjectNotFound) {
+		return nil, err
+	}
+	return attrs, nil
+}
+
+// copyFrom copies the object src in srcBkt to dst in b.
+func (b *Bucket) copyFrom(ctx context.Context, srcBkt *Bucket, src, dst string) (*ObjectAttrs, error) {
+	attrs, err := srcBkt.Attrs(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+
+	sameBucket := srcBkt == b
+	if c, ok := b.copierImpl(); ok && (sameBucket || !attrs.Encrypted) {
+		copied, err := b.copyServerSide(ctx, c, srcBkt, src, dst, attrs.Size)
+		if !errors.Is(err, types.ErrCopyUnsupported) {
+			return copied, err
+		}
+	}
+
+	if sameBucket {
+		err = b.copyObject(ctx, src, dst, attrs, copyObjectOptions{})
+	} else {
+		err = streamObject(ctx, srcBkt, b, src, dst, attrs)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return b.Attrs(ctx, dst)
+}
+
+// copierImpl returns the bucket's implementation of server-side copies, if any.
+// Copies are writes, so they always go to the primary bucket, and providers
+// can only copy from another bucket's primary bucket, given by primaryImpl.
+func (b *Bucket) copierImpl() (types.Copier, bool) {
+	c, ok := b.primaryImpl().(types.Copier)
+	return c, ok
+}
+
+// copyServerSide copies the object src in srcBkt to dst in b using the provider's
+// server-side copy. It returns types.ErrCopyUnsupported if the provider can't copy
+// from srcBkt. The copied bytes count towards the bucket's upload quota.
+func (b *Bucket) copyServerSide(ctx context.Context, c types.Copier, srcBkt *Bucket, src, dst string, size int64) (*ObjectAttrs, error) {
+	if err := b.quota.reserve(size); err != nil {
+		return nil, err
+	}
+
+	var attrs *types.ObjectAttrs
+	err := b.do(ctx, "copy", dst, func() (err error) {
+		attrs, err = c.Copy(types.CopyData{
+			Ctx:       ctx,
+			Src:       srcBkt.primaryImpl(),
+			SrcObject: srcBkt.toCloudObject(src),
+			Object:    b.toCloudObject(dst),
+		})
+		return err
+	})
+	if err != nil {
+		b.quota.release(size)
+		return nil, err
+	}
+	b.quota.commit(size)
+	return b.mapAttrs(attrs), nil
+}
+
+// streamObject copies the object src in srcBkt to dst in dstBkt by downloading
+// and uploading it. The object is decoded and decrypted, and re-encrypted for
+// dstBkt if it was encrypted.
+func streamObject(ctx context.Context, srcBkt, dstBkt *Bucket, src, dst string, attrs *ObjectAttrs, options ...UploadOption) error {
+	uploadOpts := append([]UploadOption{
+		WithUploadAttrs(UploadAttrs{ContentType: attrs.ContentType}),
+		WithMetadata(attrs.Metadata),
+		WithTags(attrs.Tags),
+	}, options...)
+	if attrs.Encrypted {
+		uploadOpts = append(uploadOpts, WithEncryption())
+	} else if attrs.ContentEncoding == "" {
+		uploadOpts = append(uploadOpts, WithSizeHint(attrs.Size))
+	}
+
+	r := srcBkt.Download(ctx, src, WithVersion(attrs.Version))
+	defer func() { _ = r.Close() }()
+	w := dstBkt.Upload(ctx, dst, uploadOpts...)
+	if _, err := io.Copy(w, r); err != nil {
+		if rerr := r.Err(); rerr != nil {
+			err = rerr
+		}
+		w.Abort(err)
+		return err
+	}
+	return w.Close()
+}
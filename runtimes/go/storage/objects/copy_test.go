@@ -0,0 +1,118 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+func TestCopy(t *testing.T) {
+	bkt, impl := newTrashTestBucket()
+	impl.objects["a.txt"].attrs.UserMetadata = map[string]string{"owner": "alice"}
+	ctx := context.Background()
+
+	attrs, err := bkt.Copy(ctx, "a.txt", "copy.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrs.Name != "copy.txt" || attrs.ContentType != "text/plain" || attrs.Metadata["owner"] != "alice" {
+		t.Errorf("got attrs %+v, want the copy with the original attributes", attrs)
+	}
+	if got := string(impl.objects["copy.txt"].data); got != "a" {
+		t.Errorf("got copied content %q, want %q", got, "a")
+	}
+	if _, ok := impl.objects["a.txt"]; !ok {
+		t.Error("source object was removed")
+	}
+
+	if _, err := bkt.Copy(ctx, "missing.txt", "copy.txt"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("got error %v copying a missing object, want ErrObjectNotFound", err)
+	}
+}
+
+func TestCopy_ServerSide(t *testing.T) {
+	impl := &copyImpl{multiImpl: &multiImpl{objects: map[types.CloudObject]*multiObject{
+		"a.txt": {data: []byte("a")},
+	}}}
+	bkt := newTestBucket(impl)
+
+	if _, err := bkt.Copy(context.Background(), "a.txt", "b.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if impl.copies != 1 {
+		t.Errorf("got %d server-side copies, want 1", impl.copies)
+	}
+	if got := string(impl.objects["b.txt"].data); got != "a" {
+		t.Errorf("got copied content %q, want %q", got, "a")
+	}
+}
+
+func TestCopy_FromBucket(t *testing.T) {
+	src, _ := newTrashTestBucket()
+	dstImpl := &multiImpl{objects: map[types.CloudObject]*multiObject{}}
+	dst := newTestBucket(dstImpl)
+
+	if _, err := dst.Copy(context.Background(), "b/c.txt", "c.txt", FromBucket(src)); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(dstImpl.objects["c.txt"].data); got != "c" {
+		t.Errorf("got copied content %q, want %q", got, "c")
+	}
+}
+
+func TestMove(t *testing.T) {
+	bkt, impl := newTrashTestBucket()
+	ctx := context.Background()
+
+	attrs, err := bkt.Move(ctx, "a.txt", "moved.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrs.Name != "moved.txt" {
+		t.Errorf("got attrs for %q, want moved.txt", attrs.Name)
+	}
+
+	// The source is removed without being moved to the trash.
+	got := make(map[string]string)
+	for name, obj := range impl.objects {
+		got[string(name)] = string(obj.data)
+	}
+	want := map[string]string{"b/c.txt": "c", "moved.txt": "a"}
+	if !maps.Equal(got, want) {
+		t.Errorf("got objects %v, want %v", got, want)
+	}
+}
+
+func TestCopy_Replica(t *testing.T) {
+	// withReplica returns a bucket reading from a replica if impl is unavailable.
+	withReplica := func(impl types.BucketImpl) *Bucket {
+		bkt := newTestBucket(impl)
+		replica := &multiImpl{objects: map[types.CloudObject]*multiObject{}}
+		bkt.impl = newFailoverImpl(bkt.mgr, bkt.name, impl, replica)
+		return bkt
+	}
+	srcImpl := &multiImpl{objects: map[types.CloudObject]*multiObject{
+		"a.txt": {data: []byte("a")},
+	}}
+	src := withReplica(srcImpl)
+	dstImpl := &copyImpl{multiImpl: &multiImpl{objects: map[types.CloudObject]*multiObject{}}}
+	dst := withReplica(dstImpl)
+	ctx := context.Background()
+
+	// Both the destination and the source are unwrapped to their primary bucket.
+	if _, err := dst.Copy(ctx, "a.txt", "b.txt", FromBucket(src)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dst.Move(ctx, "b.txt", "c.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if dstImpl.copies != 2 {
+		t.Errorf("got %d server-side copies, want 2", dstImpl.copies)
+	}
+	if got := string(dstImpl.objects["c.txt"].data); got != "a" {
+		t.Errorf("got copied content %q, want %q", got, "a")
+	}
+}
@@ -0,0 +1,76 @@
+package objects
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// decompress wraps r in a streaming decompressor if the content is
+// gzip or deflate encoded. Other content is returned as-is.
+func decompress(r types.Downloader) (io.ReadCloser, error) {
+	enc := strings.ToLower(strings.TrimSpace(r.ContentEncoding()))
+
+	var (
+		dec io.ReadCloser
+		err error
+	)
+	switch enc {
+	case "gzip", "x-gzip":
+		dec, err = gzip.NewReader(r)
+	case "deflate":
+		// The "deflate" content encoding is zlib-wrapped deflate data (RFC 9110).
+		dec, err = zlib.NewReader(r)
+	default:
+		return r, nil
+	}
+
+	if err != nil {
+		_ = r.Close()
+		return nil, decompressErr(enc, err)
+	}
+	return &decompressReader{enc: enc, dec: dec, src: r}, nil
+}
+
+type decompressReader struct {
+	enc string
+	dec io.ReadCloser    // the decompressor
+	src types.Downloader // the underlying download
+}
+
+func (d *decompressReader) Read(p []byte) (int, error) {
+	n, err := d.dec.Read(p)
+	if err != nil && err != io.EOF {
+		err = decompressErr(d.enc, err)
+	}
+	return n, err
+}
+
+func (d *decompressReader) Close() error {
+	err := d.dec.Close()
+	if err2 := d.src.Close(); err == nil {
+		err = err2
+	}
+	return err
+}
+
+// decompressErr annotates errors caused by corrupt compressed content,
+// so they aren't mistaken for errors reading from the provider.
+func decompressErr(enc string, err error) error {
+	var corrupt flate.CorruptInputError
+	switch {
+	case errors.Is(err, gzip.ErrHeader), errors.Is(err, gzip.ErrChecksum),
+		errors.Is(err, zlib.ErrHeader), errors.Is(err, zlib.ErrChecksum),
+		errors.Is(err, zlib.ErrDictionary), errors.Is(err, io.ErrUnexpectedEOF),
+		errors.As(err, &corrupt):
+		return fmt.Errorf("objects: corrupt %s-encoded content (use WithRawContent to download as stored): %w", enc, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,162 @@
+package objects
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// encodedDownloader is an in-memory downloader reporting a content encoding.
+type encodedDownloader struct {
+	*bytes.Reader
+	enc    string
+	closed bool
+}
+
+func (d *encodedDownloader) Close() error            { d.closed = true; return nil }
+func (d *encodedDownloader) ContentEncoding() string { return d.enc }
+
+// encodedImpl is a multiImpl whose downloads report the objects' content encoding.
+type encodedImpl struct {
+	*multiImpl
+}
+
+func (e *encodedImpl) Download(data types.DownloadData) (types.Downloader, error) {
+	obj, ok := e.objects[data.Object]
+	if !ok {
+		return nil, types.ErrObjectNotExist
+	}
+	return &encodedDownloader{Reader: bytes.NewReader(obj.data), enc: obj.encoding}, nil
+}
+
+func gzipped(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := io.WriteString(w, s); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func deflated(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	if _, err := io.WriteString(w, s); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecompress(t *testing.T) {
+	const content = "hello, hello, hello, world"
+	gz := gzipped(t, content)
+
+	tests := []struct {
+		name        string
+		enc         string
+		data        []byte
+		want        string
+		wantOpenErr bool // whether decompress itself fails
+		wantReadErr bool // whether reading the content fails
+	}{
+		{name: "gzip", enc: "gzip", data: gz, want: content},
+		{name: "x-gzip", enc: "x-gzip", data: gz, want: content},
+		{name: "case_and_space", enc: " GZIP ", data: gz, want: content},
+		{name: "deflate", enc: "deflate", data: deflated(t, content), want: content},
+		{name: "unknown", enc: "br", data: []byte("brotli"), want: "brotli"},
+
+		// GCS decompresses gzip-encoded objects itself unless they're downloaded raw,
+		// and reports no content encoding for them.
+		{name: "already_decompressed", enc: "", data: []byte(content), want: content},
+
+		{name: "corrupt_header", enc: "gzip", data: []byte("not gzip"), wantOpenErr: true},
+		{name: "truncated", enc: "gzip", data: gz[:len(gz)-6], wantReadErr: true},
+		{name: "corrupt_stream", enc: "deflate", data: append(deflated(t, content)[:2], 0xff, 0xff, 0xff, 0xff), wantReadErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &encodedDownloader{Reader: bytes.NewReader(tt.data), enc: tt.enc}
+			r, err := decompress(src)
+			if tt.wantOpenErr {
+				if err == nil || !strings.Contains(err.Error(), "WithRawContent") {
+					t.Errorf("got err %v, want a corrupt content error", err)
+				}
+				if !src.closed {
+					t.Error("the download wasn't closed")
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := io.ReadAll(r)
+			if tt.wantReadErr {
+				if err == nil || !strings.Contains(err.Error(), "WithRawContent") {
+					t.Errorf("got err %v, want a corrupt content error", err)
+				}
+			} else if err != nil || string(got) != tt.want {
+				t.Errorf("got (%q, %v), want %q", got, err, tt.want)
+			}
+
+			// The decompressor reports corrupt content again when closing it.
+			if err := r.Close(); err != nil && !tt.wantReadErr {
+				t.Fatal(err)
+			}
+			if !src.closed {
+				t.Error("the download wasn't closed")
+			}
+		})
+	}
+}
+
+func TestDecompressErr(t *testing.T) {
+	// Errors caused by corrupt content are annotated, keeping the cause.
+	err := decompressErr("gzip", gzip.ErrChecksum)
+	if !strings.Contains(err.Error(), "corrupt gzip-encoded content") || !strings.Contains(err.Error(), gzip.ErrChecksum.Error()) {
+		t.Errorf("got %v, want it annotated as corrupt content", err)
+	}
+	if got := decompressErr("deflate", io.ErrUnexpectedEOF); !strings.Contains(got.Error(), "corrupt deflate-encoded content") {
+		t.Errorf("got %v, want it annotated as corrupt content", got)
+	}
+
+	// Other errors, such as from reading from the provider, are returned as-is.
+	readErr := types.ErrUnavailable
+	if got := decompressErr("gzip", readErr); got != readErr {
+		t.Errorf("got %v, want %v", got, readErr)
+	}
+}
+
+func TestDownload_RawContent(t *testing.T) {
+	const content = "hello, world"
+	gz := gzipped(t, content)
+	bkt := newTestBucket(&encodedImpl{&multiImpl{objects: map[types.CloudObject]*multiObject{
+		"a.txt.gz": {data: gz, encoding: "gzip"},
+	}}})
+	ctx := context.Background()
+
+	// Encoded objects are decompressed while downloading.
+	got, err := io.ReadAll(bkt.Download(ctx, "a.txt.gz"))
+	if err != nil || string(got) != content {
+		t.Errorf("got (%q, %v), want %q", got, err, content)
+	}
+
+	// With WithRawContent they're downloaded as stored.
+	got, err = io.ReadAll(bkt.Download(ctx, "a.txt.gz", WithRawContent()))
+	if err != nil || !bytes.Equal(got, gz) {
+		t.Errorf("got (%q, %v), want the gzipped content", got, err)
+	}
+}
@@ -0,0 +1,60 @@
+package objects
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// UploadDeduplicated uploads the contents of r to the bucket, stored under
+// a key derived from its content: keyPrefix followed by the hex-encoded
+// SHA-256 hash of the content. If an object with that key already exists
+// the upload is skipped, so identical content is only stored once.
+//
+// The content is hashed while it's streamed to a temporary file,
+// so it's never held in memory in its entirety.
+//
+// It's safe to call concurrently with identical content: the upload only
+// succeeds if the object doesn't exist, and losing that race is not an error.
+//
+// It returns the key of the object, regardless of whether it was uploaded.
+func (b *Bucket) UploadDeduplicated(ctx context.Context, keyPrefix string, r io.Reader, options ...UploadOption) (key string, err error) {
+	f, err := os.CreateTemp("", "encore-upload-*")
+	if err != nil {
+		return "", fmt.Errorf("objects: create temp file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(f, h), r)
+	if err != nil {
+		return "", fmt.Errorf("objects: read content: %w", err)
+	} else if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("objects: read content: %w", err)
+	}
+	key = keyPrefix + hex.EncodeToString(h.Sum(nil))
+
+	if exists, err := b.Exists(ctx, key); err != nil {
+		return "", err
+	} else if exists {
+		return key, nil
+	}
+
+	options = append(options, WithSizeHint(size), WithPreconditions(Preconditions{NotExists: true}))
+	w := b.Upload(ctx, key, options...)
+	if _, err := io.Copy(w, f); err != nil {
+		w.Abort(err)
+		return "", err
+	}
+	if err := w.Close(); err != nil && !errors.Is(err, ErrPreconditionFailed) {
+		return "", err
+	}
+	return key, nil
+}
@@ -0,0 +1,87 @@
+package objects
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+func TestUploadDeduplicated(t *testing.T) {
+	impl := &countingImpl{multiImpl: &multiImpl{objects: map[types.CloudObject]*multiObject{}}}
+	bkt := newTestBucket(impl)
+	ctx := context.Background()
+
+	sum := sha256.Sum256([]byte("content"))
+	want := "blobs/" + hex.EncodeToString(sum[:])
+
+	for range 2 {
+		key, err := bkt.UploadDeduplicated(ctx, "blobs/", strings.NewReader("content"))
+		if err != nil {
+			t.Fatal(err)
+		} else if key != want {
+			t.Errorf("got key %q, want %q", key, want)
+		}
+	}
+
+	// Identical content is only uploaded and stored once.
+	if impl.uploads != 1 {
+		t.Errorf("got %d uploads to the provider, want 1", impl.uploads)
+	}
+	if len(impl.objects) != 1 {
+		t.Errorf("got %d objects, want 1", len(impl.objects))
+	}
+	if obj := impl.objects[types.CloudObject(want)]; obj == nil || string(obj.data) != "content" {
+		t.Errorf("got object %v, want the content", obj)
+	}
+
+	// Different content is stored separately.
+	if key, err := bkt.UploadDeduplicated(ctx, "blobs/", strings.NewReader("other")); err != nil || key == want {
+		t.Errorf("got (%q, %v), want a different key", key, err)
+	}
+	if len(impl.objects) != 2 {
+		t.Errorf("got %d objects, want 2", len(impl.objects))
+	}
+}
+
+func TestUploadDeduplicated_Race(t *testing.T) {
+	impl := &countingImpl{multiImpl: &multiImpl{objects: map[types.CloudObject]*multiObject{}}}
+	bkt := newTestBucket(impl)
+
+	sum := sha256.Sum256([]byte("content"))
+	want := "blobs/" + hex.EncodeToString(sum[:])
+
+	// A concurrent upload of the same content completes after this one
+	// checked the object didn't exist, but before this one completes.
+	impl.beforeComplete = func() {
+		impl.objects[types.CloudObject(want)] = &multiObject{data: []byte("content")}
+	}
+
+	// Losing the race isn't an error, since the content is stored either way.
+	key, err := bkt.UploadDeduplicated(context.Background(), "blobs/", strings.NewReader("content"))
+	if err != nil || key != want {
+		t.Errorf("got (%q, %v), want (%q, nil)", key, err, want)
+	}
+}
+
+func TestUploadDeduplicated_ReadError(t *testing.T) {
+	impl := &countingImpl{multiImpl: &multiImpl{objects: map[types.CloudObject]*multiObject{}}}
+	bkt := newTestBucket(impl)
+
+	readErr := errors.New("connection reset")
+	r := io.MultiReader(strings.NewReader("partial"), iotest.ErrReader(readErr))
+	if key, err := bkt.UploadDeduplicated(context.Background(), "blobs/", r); !errors.Is(err, readErr) || key != "" {
+		t.Errorf("got (%q, %v), want the read error", key, err)
+	}
+
+	// The partial content isn't uploaded.
+	if impl.uploads != 0 || len(impl.objects) != 0 {
+		t.Errorf("got %d uploads and %d objects, want none", impl.uploads, len(impl.objects))
+	}
+}
@@ -0,0 +1,145 @@
+package objects
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// DirDiff describes the changes needed to make the objects under
+// a prefix in a bucket match the files in a local directory.
+type DirDiff struct {
+	// Upload are the files that are new or have changed.
+	Upload []DiffEntry
+
+	// Delete are the objects that don't exist locally.
+	Delete []DiffEntry
+
+	// Skip are the files that are unchanged.
+	Skip []DiffEntry
+}
+
+// DiffEntry describes a single file or object in a DirDiff.
+type DiffEntry struct {
+	// Path is the path of the file, relative to the local directory
+	// and using forward slashes.
+	Path string
+
+	// Object is the name of the object in the bucket.
+	Object string
+
+	// Size is the size of the local file, or of the object
+	// for entries in Delete.
+	Size int64
+
+	// Reason describes why the entry is included, such as "new" or "changed".
+	Reason string
+}
+
+// DiffDir compares the files in the local directory dir with the objects
+// in the bucket whose names start with prefix, without modifying anything.
+//
+// A file at the path "a/b.txt" relative to dir corresponds to the object
+// named prefix + "a/b.txt", so prefix should usually end with "/".
+// Only regular files are considered; symlinks and other special files
+// are ignored.
+//
+// Files are compared to objects by size, and by MD5 checksum when the
+// object's ETag is one (which is the case for objects not uploaded in
+// multiple parts). Files whose checksum can't be compared are listed
+// for upload with the reason "unverified", to err on the side of
+// re-uploading them.
+//
+// The entries in each list are sorted by path.
+func (b *Bucket) DiffDir(ctx context.Context, dir, prefix string) (*DirDiff, error) {
+	local := make(map[string]int64) // path -> size
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		} else if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		local[filepath.ToSlash(rel)] = info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("objects: read local directory: %w", err)
+	}
+
+	diff := &DirDiff{}
+	for entry, err := range b.List(ctx, &Query{Prefix: prefix}) {
+		if err != nil {
+			return nil, err
+		}
+		path := strings.TrimPrefix(entry.Name, prefix)
+		size, ok := local[path]
+		if !ok {
+			diff.Delete = append(diff.Delete, DiffEntry{Path: path, Object: entry.Name, Size: entry.Size, Reason: "deleted"})
+			continue
+		}
+		delete(local, path)
+
+		de := DiffEntry{Path: path, Object: entry.Name, Size: size}
+		de.Reason, err = compareLocalFile(filepath.Join(dir, filepath.FromSlash(path)), size, entry)
+		if err != nil {
+			return nil, err
+		}
+		if de.Reason == "" {
+			de.Reason = "unchanged"
+			diff.Skip = append(diff.Skip, de)
+		} else {
+			diff.Upload = append(diff.Upload, de)
+		}
+	}
+
+	for path, size := range local {
+		diff.Upload = append(diff.Upload, DiffEntry{Path: path, Object: prefix + path, Size: size, Reason: "new"})
+	}
+
+	byPath := func(a, b DiffEntry) int { return strings.Compare(a.Path, b.Path) }
+	slices.SortFunc(diff.Upload, byPath)
+	slices.SortFunc(diff.Delete, byPath)
+	slices.SortFunc(diff.Skip, byPath)
+	return diff, nil
+}
+
+// compareLocalFile compares the local file at path to the given object,
+// returning the reason it needs to be uploaded or "" if it's unchanged.
+func compareLocalFile(path string, size int64, entry *ListEntry) (reason string, err error) {
+	if size != entry.Size {
+		return "changed", nil
+	}
+	etag := strings.Trim(entry.ETag, `"`)
+	if !isMD5Hex(etag) {
+		return "unverified", nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("objects: read local file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("objects: read local file: %w", err)
+	}
+	if hex.EncodeToString(h.Sum(nil)) != strings.ToLower(etag) {
+		return "changed", nil
+	}
+	return "", nil
+}
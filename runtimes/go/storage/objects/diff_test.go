@@ -0,0 +1,85 @@
+package objects
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"iter"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// listImpl is a bucket implementation that lists a fixed set of objects.
+type listImpl struct {
+	types.BucketImpl
+	entries []*types.ListEntry
+}
+
+func (l *listImpl) List(data types.ListData) iter.Seq2[*types.ListEntry, error] {
+	return func(yield func(*types.ListEntry, error) bool) {
+		for _, e := range l.entries {
+			if !yield(e, nil) {
+				return
+			}
+		}
+	}
+}
+
+func md5ETag(s string) string {
+	sum := md5.Sum([]byte(s))
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func TestDiffDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"same.txt":      "hello",
+		"changed.txt":   "new content",
+		"multipart.bin": "12345",
+		"sub/new.txt":   "brand new",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		} else if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	impl := &listImpl{entries: []*types.ListEntry{
+		{Object: "site/same.txt", Size: 5, ETag: md5ETag("hello")},
+		{Object: "site/changed.txt", Size: 11, ETag: md5ETag("old content")},
+		{Object: "site/multipart.bin", Size: 5, ETag: `"abc-2"`},
+		{Object: "site/gone.txt", Size: 3, ETag: md5ETag("bye")},
+	}}
+	diff, err := newTestBucket(impl).DiffDir(context.Background(), dir, "site/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(kind string, got []DiffEntry, want map[string]string) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Errorf("%s: got %v, want %v", kind, got, want)
+			return
+		}
+		for _, e := range got {
+			if reason, ok := want[e.Path]; !ok || reason != e.Reason {
+				t.Errorf("%s: unexpected entry %+v", kind, e)
+			} else if e.Object != "site/"+e.Path {
+				t.Errorf("%s: got object %q for path %q", kind, e.Object, e.Path)
+			}
+		}
+	}
+	check("upload", diff.Upload, map[string]string{
+		"changed.txt":   "changed",
+		"multipart.bin": "unverified",
+		"sub/new.txt":   "new",
+	})
+	check("delete", diff.Delete, map[string]string{"gone.txt": "deleted"})
+	check("skip", diff.Skip, map[string]string{"same.txt": "unchanged"})
+}
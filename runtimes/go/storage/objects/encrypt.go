@@ -0,0 +1,124 @@
+package objects
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"encore.dev/storage/objects/internal/encryption"
+	"encore.dev/storage/objects/internal/types"
+)
+
+// newDataKey generates a data key for encrypting an object,
+// wrapped by the bucket's encryption key.
+func (b *Bucket) newDataKey() ([]byte, *types.Encryption, error) {
+	if b.encKey == nil {
+		return nil, nil, fmt.Errorf("%w: bucket %s has no encryption key configured",
+			types.ErrInvalidArgument, b.name)
+	}
+	return b.encKey.NewDataKey()
+}
+
+// encryptingUploader encrypts the data written to it
+// before passing it on to the underlying uploader.
+type encryptingUploader struct {
+	types.Uploader
+	enc *encryption.Writer
+}
+
+func newEncryptingUploader(u types.Uploader, dataKey []byte) (types.Uploader, error) {
+	enc, err := encryption.NewWriter(u, dataKey)
+	if err != nil {
+		u.Abort(err)
+		return nil, err
+	}
+	return &encryptingUploader{Uploader: u, enc: enc}, nil
+}
+
+func (u *encryptingUploader) Write(p []byte) (int, error) {
+	return u.enc.Write(p)
+}
+
+func (u *encryptingUploader) Complete() (*types.ObjectAttrs, error) {
+	if err := u.enc.Close(); err != nil {
+		u.Uploader.Abort(err)
+		return nil, err
+	}
+	return u.Uploader.Complete()
+}
+
+// decrypt wraps r in a decrypting reader if the object is encrypted.
+// Other objects are returned as-is.
+//
+// Encrypted objects are recognized by their content, so that they're never
+// returned encrypted: if the object can't be decrypted because the wrapped
+// key is missing or the bucket doesn't have the key it was encrypted with,
+// it fails with ErrDecryptionFailed.
+func (b *Bucket) decrypt(ctx context.Context, object, version string, r types.Downloader) (types.Downloader, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(encryption.HeaderSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		_ = r.Close()
+		return nil, err
+	}
+	if !encryption.HasHeader(header) {
+		return &bufferedDownloader{Downloader: r, r: br}, nil
+	}
+
+	fail := func(format string, args ...any) (types.Downloader, error) {
+		_ = r.Close()
+		return nil, fmt.Errorf("%w: %s: %s", types.ErrDecryptionFailed, object, fmt.Sprintf(format, args...))
+	}
+
+	// The wrapped data key is stored in the object's metadata.
+	attrs, err := b.impl.Attrs(types.AttrsData{
+		Ctx:     ctx,
+		Object:  b.toCloudObject(object),
+		Version: version,
+	})
+	if err != nil {
+		_ = r.Close()
+		return nil, err
+	} else if attrs.Encryption == nil {
+		return fail("missing encryption metadata")
+	} else if b.encKey == nil {
+		return fail("bucket has no encryption key configured")
+	}
+
+	dataKey, err := b.encKey.UnwrapKey(attrs.Encryption)
+	if err != nil {
+		return fail("%v", err)
+	}
+	dec, err := encryption.NewReader(br, dataKey)
+	if err != nil {
+		return fail("%v", err)
+	}
+	return &bufferedDownloader{Downloader: r, r: &decryptReader{object: object, r: dec}}, nil
+}
+
+// bufferedDownloader is a downloader that reads from r
+// rather than the underlying download.
+type bufferedDownloader struct {
+	types.Downloader
+	r io.Reader
+}
+
+func (d *bufferedDownloader) Read(p []byte) (int, error) {
+	return d.r.Read(p)
+}
+
+// decryptReader reports decryption errors as ErrDecryptionFailed.
+type decryptReader struct {
+	object string
+	r      io.Reader
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	if errors.Is(err, encryption.ErrDecrypt) {
+		err = fmt.Errorf("%w: %s: %v", types.ErrDecryptionFailed, d.object, err)
+	}
+	return n, err
+}
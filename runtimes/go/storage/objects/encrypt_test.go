@@ -0,0 +1,97 @@
+package objects
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+
+	"encore.dev/storage/objects/internal/encryption"
+)
+
+func newTestKey(t *testing.T, id string) *encryption.MasterKey {
+	t.Helper()
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	k, err := encryption.NewMasterKey(id, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestEncryption_RoundTrip(t *testing.T) {
+	impl := &memImpl{}
+	bkt := newTestBucket(impl)
+	bkt.encKey = newTestKey(t, "key-1")
+
+	content := bytes.Repeat([]byte("secret "), 20_000)
+	w := bkt.Upload(context.Background(), "obj", WithEncryption())
+	if _, err := w.Write(content); err != nil {
+		t.Fatal(err)
+	} else if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(impl.data, []byte("secret")) {
+		t.Fatal("object stored in plaintext")
+	} else if impl.enc == nil || impl.enc.KeyID != "key-1" {
+		t.Fatalf("got encryption metadata %+v, want key-1", impl.enc)
+	}
+
+	r := bkt.Download(context.Background(), "obj")
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(got, content) {
+		t.Fatalf("got %d bytes, want the original %d bytes", len(got), len(content))
+	}
+}
+
+func TestEncryption_FailsClosed(t *testing.T) {
+	upload := func(t *testing.T) *memImpl {
+		impl := &memImpl{}
+		bkt := newTestBucket(impl)
+		bkt.encKey = newTestKey(t, "key-1")
+		w := bkt.Upload(context.Background(), "obj", WithEncryption())
+		_, _ = w.Write([]byte("secret"))
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return impl
+	}
+
+	tests := []struct {
+		name  string
+		setup func(impl *memImpl, bkt *Bucket)
+	}{
+		{name: "no_key", setup: func(impl *memImpl, bkt *Bucket) {}},
+		{name: "wrong_key", setup: func(impl *memImpl, bkt *Bucket) { bkt.encKey = newTestKey(t, "key-1") }},
+		{name: "missing_metadata", setup: func(impl *memImpl, bkt *Bucket) {
+			bkt.encKey = newTestKey(t, "key-1")
+			impl.enc = nil
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			impl := upload(t)
+			bkt := newTestBucket(impl)
+			tt.setup(impl, bkt)
+
+			_, err := io.ReadAll(bkt.Download(context.Background(), "obj"))
+			if !errors.Is(err, ErrDecryptionFailed) {
+				t.Fatalf("got err %v, want ErrDecryptionFailed", err)
+			}
+		})
+	}
+}
+
+func TestEncryption_NoKeyConfigured(t *testing.T) {
+	w := newTestBucket(&memImpl{}).Upload(context.Background(), "obj", WithEncryption())
+	_, _ = w.Write([]byte("secret"))
+	if err := w.Close(); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("got err %v, want ErrInvalidArgument", err)
+	}
+}
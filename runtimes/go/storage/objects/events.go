@@ -0,0 +1,190 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"encore.dev/appruntime/exported/config"
+	"encore.dev/storage/objects/internal/types"
+)
+
+// EventType describes the kind of change that happened to an object.
+type EventType string
+
+const (
+	// ObjectCreated is delivered when an object is created or overwritten.
+	ObjectCreated EventType = "object_created"
+
+	// ObjectDeleted is delivered when an object is deleted.
+	ObjectDeleted EventType = "object_deleted"
+)
+
+// ObjectEvent describes a change to an object in a bucket.
+type ObjectEvent struct {
+	// Type is the kind of change.
+	Type EventType
+
+	// Bucket is the name of the bucket the object belongs to.
+	Bucket string
+
+	// Name is the name of the object.
+	Name string
+
+	// Version is the version of the object, if bucket versioning is enabled.
+	Version string
+
+	// Size is the size of the object, in bytes.
+	// It's zero for ObjectDeleted events.
+	Size int64
+
+	// ETag is the computed ETag of the object, if known.
+	ETag string
+}
+
+// eventSubscription is a subscription to change events for the objects in a bucket.
+// Subscriptions are internal plumbing, used to implement bucket notifications
+// like NewTopicNotification.
+type eventSubscription struct {
+	bkt    *Bucket
+	name   string
+	events []EventType // the events to deliver; empty means all
+
+	// handler is called for each event delivered to the subscription.
+	// If it returns an error the event is redelivered later,
+	// if the bucket provider supports it.
+	handler func(ctx context.Context, event *ObjectEvent) error
+}
+
+// newEventSubscription creates a subscription to the given events for the objects in bkt,
+// and starts delivering them to handler. If events is empty, all events are delivered.
+//
+// If the static config declares the subscription, its events take precedence.
+func newEventSubscription(bkt *Bucket, name string, events []EventType, handler func(ctx context.Context, event *ObjectEvent) error) *eventSubscription {
+	sub := &eventSubscription{bkt: bkt, name: name, events: slices.Clone(events), handler: handler}
+	if staticCfg, ok := bkt.mgr.static.BucketSubscriptions[bkt.name][name]; ok {
+		sub.events = nil
+		for _, ev := range staticCfg.Events {
+			sub.events = append(sub.events, EventType(ev))
+		}
+	}
+
+	bkt.mgr.registerSubscription(sub)
+	return sub
+}
+
+func (s *eventSubscription) wants(typ EventType) bool {
+	return len(s.events) == 0 || slices.Contains(s.events, typ)
+}
+
+// validateBucketSubscriptions validates the static event subscription config.
+func validateBucketSubscriptions(static *config.Static) error {
+	var errs []error
+	for bucketName, subs := range static.BucketSubscriptions {
+		for subName, sub := range subs {
+			if sub == nil {
+				errs = append(errs, fmt.Errorf("bucket %s: subscription %s: missing config", bucketName, subName))
+				continue
+			}
+			if sub.Service == "" {
+				errs = append(errs, fmt.Errorf("bucket %s: subscription %s: missing service", bucketName, subName))
+			}
+			for _, ev := range sub.Events {
+				if typ := EventType(ev); typ != ObjectCreated && typ != ObjectDeleted {
+					errs = append(errs, fmt.Errorf("bucket %s: subscription %s: unknown event type %q", bucketName, subName, ev))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// registerSubscription registers sub with the manager and starts
+// receiving events for the bucket, if it's not already doing so.
+func (mgr *Manager) registerSubscription(sub *eventSubscription) {
+	mgr.subsMu.Lock()
+	defer mgr.subsMu.Unlock()
+
+	bkt := sub.bkt
+	_, started := mgr.subs[bkt.name]
+	mgr.subs[bkt.name] = append(mgr.subs[bkt.name], sub)
+	if started {
+		return
+	}
+
+	// Events are only delivered for the primary bucket.
+	src, ok := bkt.primaryImpl().(types.EventSource)
+	if !ok {
+		mgr.rootLogger.Warn().Str("bucket", bkt.name).Str("subscription", sub.name).
+			Msg("object storage provider does not support event notifications, subscription will not receive any events")
+		return
+	}
+
+	mgr.runningFetches.Add(1)
+	go func() {
+		defer mgr.runningFetches.Done()
+		err := src.SubscribeEvents(mgr.fetchCtx, func(ctx context.Context, ev *types.ObjectEvent) error {
+			return mgr.dispatchEvent(ctx, bkt, ev)
+		})
+		if err != nil && mgr.fetchCtx.Err() == nil {
+			mgr.rootLogger.Error().Err(err).Str("bucket", bkt.name).Msg("object event subscription stopped unexpectedly")
+		}
+	}()
+}
+
+// dispatchEvent delivers an event to all subscriptions for the bucket that want it.
+func (mgr *Manager) dispatchEvent(ctx context.Context, bkt *Bucket, ev *types.ObjectEvent) error {
+	if mgr.fetchCtx.Err() != nil {
+		return errors.New("objects: shutting down")
+	}
+
+	// Ignore events for objects outside of the bucket's key prefix.
+	if !strings.HasPrefix(ev.Object.String(), bkt.cloudPrefix()) {
+		return nil
+	}
+
+	event := &ObjectEvent{
+		Type:    EventType(ev.Type),
+		Bucket:  bkt.name,
+		Name:    bkt.fromCloudObject(ev.Object),
+		Version: ev.Version,
+		Size:    ev.Size,
+		ETag:    ev.ETag,
+	}
+
+	mgr.subsMu.Lock()
+	subs := slices.Clone(mgr.subs[bkt.name])
+	mgr.subsMu.Unlock()
+
+	var errs []error
+	for _, sub := range subs {
+		if !sub.wants(event.Type) {
+			continue
+		}
+		if err := mgr.runHandler(ctx, sub, event); err != nil {
+			errs = append(errs, fmt.Errorf("subscription %s: %w", sub.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (mgr *Manager) runHandler(ctx context.Context, sub *eventSubscription, event *ObjectEvent) (err error) {
+	mgr.runningHandlers.Add(1)
+	defer mgr.runningHandlers.Done()
+
+	// Let running handlers complete when we stop receiving events,
+	// and only cancel them if the manager is forcibly shut down.
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	stop := context.AfterFunc(mgr.ctx, cancel)
+	defer stop()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	return sub.handler(ctx, event)
+}
@@ -0,0 +1,100 @@
+package objects
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"encore.dev/appruntime/exported/config"
+	"encore.dev/storage/objects/internal/types"
+)
+
+func TestValidateBucketSubscriptions(t *testing.T) {
+	static := &config.Static{
+		BucketSubscriptions: map[string]map[string]*config.StaticBucketSubscription{
+			"uploads": {
+				"ok":         {Service: "svc", Events: []string{"object_created"}},
+				"no-service": {Events: []string{"object_deleted"}},
+				"bad-event":  {Service: "svc", Events: []string{"object_renamed"}},
+				"nil-config": nil,
+				"all-events": {Service: "svc"},
+			},
+		},
+	}
+	err := validateBucketSubscriptions(static)
+	if err == nil {
+		t.Fatal("got nil error, want validation errors")
+	}
+	for _, want := range []string{"no-service: missing service", `bad-event: unknown event type "object_renamed"`, "nil-config: missing config"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("got error %q, want it to contain %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "subscription ok") || strings.Contains(err.Error(), "all-events") {
+		t.Errorf("got error %q for valid subscriptions", err)
+	}
+
+	if err := validateBucketSubscriptions(&config.Static{}); err != nil {
+		t.Errorf("got error %v for empty config, want nil", err)
+	}
+}
+
+func TestDispatchEvent(t *testing.T) {
+	bkt := newTestBucket(&memImpl{})
+	bkt.mgr.fetchCtx = context.Background()
+	bkt.mgr.subs = make(map[string][]*eventSubscription)
+	bkt.mgr.static.BucketSubscriptions = map[string]map[string]*config.StaticBucketSubscription{
+		"test": {"deletes": {Service: "svc", Events: []string{"object_deleted"}}},
+	}
+
+	var created, deleted []string
+	newEventSubscription(bkt, "creates", []EventType{ObjectCreated}, func(ctx context.Context, ev *ObjectEvent) error {
+		created = append(created, ev.Name)
+		return nil
+	})
+	// The static config overrides the events given in code.
+	newEventSubscription(bkt, "deletes", []EventType{ObjectCreated}, func(ctx context.Context, ev *ObjectEvent) error {
+		deleted = append(deleted, ev.Name)
+		return nil
+	})
+
+	for _, ev := range []*types.ObjectEvent{
+		{Type: "object_created", Object: "a.jpg"},
+		{Type: "object_deleted", Object: "b.jpg"},
+	} {
+		if err := bkt.mgr.dispatchEvent(context.Background(), bkt, ev); err != nil {
+			t.Fatalf("dispatch %s: %v", ev.Object, err)
+		}
+	}
+	if want := []string{"a.jpg"}; !reflect.DeepEqual(created, want) {
+		t.Errorf("got created %v, want %v", created, want)
+	}
+	if want := []string{"b.jpg"}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("got deleted %v, want %v", deleted, want)
+	}
+}
+
+func TestDispatchEvent_HandlerPanic(t *testing.T) {
+	bkt := newTestBucket(&memImpl{})
+	bkt.mgr.fetchCtx = context.Background()
+	bkt.mgr.subs = make(map[string][]*eventSubscription)
+
+	newEventSubscription(bkt, "panics", nil, func(ctx context.Context, ev *ObjectEvent) error {
+		panic("boom")
+	})
+
+	// Panics are reported as errors so the event is redelivered.
+	err := bkt.mgr.dispatchEvent(context.Background(), bkt, &types.ObjectEvent{Type: "object_created", Object: "a.jpg"})
+	if err == nil || !strings.Contains(err.Error(), "handler panicked: boom") {
+		t.Errorf("got error %v, want handler panic", err)
+	}
+
+	// Events aren't dispatched once the manager stops receiving them.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	bkt.mgr.fetchCtx = ctx
+	if err := bkt.mgr.dispatchEvent(context.Background(), bkt, &types.ObjectEvent{Type: "object_created", Object: "a.jpg"}); err == nil {
+		t.Error("got nil error after shutdown, want error")
+	}
+}
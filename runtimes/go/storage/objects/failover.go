@@ -0,0 +1,113 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"iter"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// failoverImpl is a bucket implementation that sends reads to a replica
+// bucket when the primary bucket is unavailable.
+//
+// Writes are never sent to the replica, since that would make the buckets
+// diverge. They instead report the primary bucket's error.
+type failoverImpl struct {
+	mgr       *Manager
+	bucket    string
+	primary   types.BucketImpl
+	secondary types.BucketImpl
+}
+
+var _ types.BucketImpl = (*failoverImpl)(nil)
+
+func newFailoverImpl(mgr *Manager, bucket string, primary, secondary types.BucketImpl) *failoverImpl {
+	return &failoverImpl{
+		mgr:       mgr,
+		bucket:    bucket,
+		primary:   primary,
+		secondary: secondary,
+	}
+}
+
+// primaryImpl returns the implementation of the bucket's primary bucket,
+// without failing over to its replica. Writes and provider-specific
+// operations always go to the primary bucket.
+func (b *Bucket) primaryImpl() types.BucketImpl {
+	if f, ok := b.impl.(*failoverImpl); ok {
+		return f.primary
+	}
+	return b.impl
+}
+
+// shouldFailover reports whether a read that failed with err
+// should be retried against the replica, logging it if so.
+func (f *failoverImpl) shouldFailover(ctx context.Context, op string, err error) bool {
+	// Don't fail over if the caller gave up; the replica won't do any better.
+	if err == nil || ctx.Err() != nil || !errors.Is(err, types.ErrUnavailable) {
+		return false
+	}
+
+	f.mgr.rootLogger.Warn().
+		Err(err).
+		Str("bucket", f.bucket).
+		Str("operation", op).
+		Msg("object storage bucket unavailable, failing over to replica bucket")
+	return true
+}
+
+func (f *failoverImpl) Download(data types.DownloadData) (types.Downloader, error) {
+	d, err := f.primary.Download(data)
+	if f.shouldFailover(data.Ctx, "download", err) {
+		return f.secondary.Download(data)
+	}
+	return d, err
+}
+
+func (f *failoverImpl) Attrs(data types.AttrsData) (*types.ObjectAttrs, error) {
+	attrs, err := f.primary.Attrs(data)
+	if f.shouldFailover(data.Ctx, "attrs", err) {
+		return f.secondary.Attrs(data)
+	}
+	return attrs, err
+}
+
+func (f *failoverImpl) List(data types.ListData) iter.Seq2[*types.ListEntry, error] {
+	return func(yield func(*types.ListEntry, error) bool) {
+		// We can only fail over transparently before any entries have been
+		// yielded; after that we'd yield duplicate entries.
+		seen := false
+		for entry, err := range f.primary.List(data) {
+			if err != nil && !seen && f.shouldFailover(data.Ctx, "list", err) {
+				for entry, err := range f.secondary.List(data) {
+					if !yield(entry, err) {
+						return
+					}
+				}
+				return
+			}
+
+			seen = true
+			if !yield(entry, err) {
+				return
+			}
+		}
+	}
+}
+
+func (f *failoverImpl) SignedDownloadURL(data types.DownloadURLData) (string, error) {
+	return f.primary.SignedDownloadURL(data)
+}
+
+func (f *failoverImpl) Upload(data types.UploadData) (types.Uploader, error) {
+	return f.primary.Upload(data)
+}
+
+func (f *failoverImpl) Remove(data types.RemoveData) error {
+	return f.primary.Remove(data)
+}
+
+func (f *failoverImpl) SignedUploadURL(data types.UploadURLData) (string, error) {
+	return f.primary.SignedUploadURL(data)
+}
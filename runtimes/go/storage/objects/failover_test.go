@@ -0,0 +1,237 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// failingImpl is an in-memory bucket implementation whose
+// operations fail with err, if set.
+type failingImpl struct {
+	*multiImpl
+	err error
+
+	// listBefore is how many entries List yields before failing.
+	listBefore int
+}
+
+func (f *failingImpl) Attrs(data types.AttrsData) (*types.ObjectAttrs, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.multiImpl.Attrs(data)
+}
+
+func (f *failingImpl) Download(data types.DownloadData) (types.Downloader, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.multiImpl.Download(data)
+}
+
+func (f *failingImpl) Upload(data types.UploadData) (types.Uploader, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.multiImpl.Upload(data)
+}
+
+func (f *failingImpl) Remove(data types.RemoveData) error {
+	if f.err != nil {
+		return f.err
+	}
+	return f.multiImpl.Remove(data)
+}
+
+func (f *failingImpl) List(data types.ListData) iter.Seq2[*types.ListEntry, error] {
+	return func(yield func(*types.ListEntry, error) bool) {
+		n := 0
+		for entry, err := range f.multiImpl.List(data) {
+			if f.err != nil && n == f.listBefore {
+				break
+			}
+			n++
+			if !yield(entry, err) {
+				return
+			}
+		}
+		if f.err != nil {
+			yield(nil, f.err)
+		}
+	}
+}
+
+// copyingImpl is a failingImpl that supports server-side copies.
+type copyingImpl struct {
+	*failingImpl
+}
+
+func (c *copyingImpl) Copy(data types.CopyData) (*types.ObjectAttrs, error) {
+	return nil, types.ErrCopyUnsupported
+}
+
+var errTestUnavailable = fmt.Errorf("connection refused: %w", types.ErrUnavailable)
+
+func newFailoverTest(primaryErr error) (f *failoverImpl, primary, secondary *failingImpl) {
+	objects := func() map[types.CloudObject]*multiObject {
+		return map[types.CloudObject]*multiObject{
+			"a.txt": {data: []byte("a")},
+			"b.txt": {data: []byte("b")},
+		}
+	}
+	primary = &failingImpl{multiImpl: &multiImpl{objects: objects()}, err: primaryErr}
+	secondary = &failingImpl{multiImpl: &multiImpl{objects: objects()}}
+	// Tell the buckets apart by the content of their objects.
+	secondary.objects["a.txt"].data = []byte("replica")
+	mgr := &Manager{rootLogger: zerolog.Nop()}
+	return newFailoverImpl(mgr, "test", primary, secondary), primary, secondary
+}
+
+func TestFailover_Reads(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantFailover bool
+	}{
+		{name: "unavailable", err: errTestUnavailable, wantFailover: true},
+		{name: "not_found", err: types.ErrObjectNotExist, wantFailover: false},
+		{name: "other_error", err: errors.New("access denied"), wantFailover: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, _, _ := newFailoverTest(tt.err)
+			ctx := context.Background()
+
+			attrs, err := f.Attrs(types.AttrsData{Ctx: ctx, Object: "a.txt"})
+			if tt.wantFailover {
+				if err != nil || attrs.Size != int64(len("replica")) {
+					t.Errorf("Attrs: got (%+v, %v), want the replica's attributes", attrs, err)
+				}
+			} else if !errors.Is(err, tt.err) {
+				t.Errorf("Attrs: got err %v, want %v", err, tt.err)
+			}
+
+			d, err := f.Download(types.DownloadData{Ctx: ctx, Object: "a.txt"})
+			if tt.wantFailover {
+				if err != nil {
+					t.Fatalf("Download: got err %v, want the replica's object", err)
+				}
+				if data, _ := io.ReadAll(d); string(data) != "replica" {
+					t.Errorf("Download: got %q, want %q", data, "replica")
+				}
+			} else if !errors.Is(err, tt.err) {
+				t.Errorf("Download: got err %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestFailover_ContextDone(t *testing.T) {
+	f, _, _ := newFailoverTest(errTestUnavailable)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The caller gave up, so the replica isn't tried.
+	if _, err := f.Attrs(types.AttrsData{Ctx: ctx, Object: "a.txt"}); !errors.Is(err, types.ErrUnavailable) {
+		t.Errorf("Attrs: got err %v, want the primary's error", err)
+	}
+	if _, err := f.Download(types.DownloadData{Ctx: ctx, Object: "a.txt"}); !errors.Is(err, types.ErrUnavailable) {
+		t.Errorf("Download: got err %v, want the primary's error", err)
+	}
+	for _, err := range f.List(types.ListData{Ctx: ctx}) {
+		if !errors.Is(err, types.ErrUnavailable) {
+			t.Errorf("List: got err %v, want the primary's error", err)
+		}
+	}
+}
+
+func TestFailover_List(t *testing.T) {
+	list := func(f *failoverImpl) (names []string, err error) {
+		for entry, err := range f.List(types.ListData{Ctx: context.Background()}) {
+			if err != nil {
+				return names, err
+			}
+			names = append(names, string(entry.Object))
+		}
+		return names, nil
+	}
+
+	// Failing before the first entry, the replica is listed instead.
+	f, _, secondary := newFailoverTest(errTestUnavailable)
+	secondary.objects["c.txt"] = &multiObject{data: []byte("c")}
+	if names, err := list(f); err != nil || !slices.Equal(names, []string{"a.txt", "b.txt", "c.txt"}) {
+		t.Errorf("got (%v, %v), want the replica's objects", names, err)
+	}
+
+	// Failing after the first entry, the error is reported
+	// rather than listing the replica's entries again.
+	f, primary, _ := newFailoverTest(errTestUnavailable)
+	primary.listBefore = 1
+	if names, err := list(f); !errors.Is(err, types.ErrUnavailable) || !slices.Equal(names, []string{"a.txt"}) {
+		t.Errorf("got (%v, %v), want [a.txt] and the primary's error", names, err)
+	}
+}
+
+func TestFailover_Writes(t *testing.T) {
+	f, primary, secondary := newFailoverTest(errTestUnavailable)
+	ctx := context.Background()
+
+	// Writes aren't sent to the replica, even when the primary is unavailable.
+	if _, err := f.Upload(types.UploadData{Ctx: ctx, Object: "c.txt"}); !errors.Is(err, types.ErrUnavailable) {
+		t.Errorf("Upload: got err %v, want the primary's error", err)
+	}
+	if err := f.Remove(types.RemoveData{Ctx: ctx, Object: "a.txt"}); !errors.Is(err, types.ErrUnavailable) {
+		t.Errorf("Remove: got err %v, want the primary's error", err)
+	}
+	if _, ok := secondary.objects["a.txt"]; !ok || len(secondary.objects) != 2 {
+		t.Errorf("the replica was modified: %v", secondary.objects)
+	}
+
+	// Once the primary is available, writes go to it.
+	primary.err = nil
+	u, err := f.Upload(types.UploadData{Ctx: ctx, Object: "c.txt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.Write([]byte("c")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.Complete(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := primary.objects["c.txt"]; !ok {
+		t.Error("the upload wasn't written to the primary")
+	}
+	if _, ok := secondary.objects["c.txt"]; ok {
+		t.Error("the upload was written to the replica")
+	}
+}
+
+func TestFailover_OptionalInterfaces(t *testing.T) {
+	primary := &copyingImpl{&failingImpl{multiImpl: &multiImpl{}, err: errTestUnavailable}}
+	secondary := &copyingImpl{&failingImpl{multiImpl: &multiImpl{}}}
+	bkt := newTestBucket(newFailoverImpl(&Manager{rootLogger: zerolog.Nop()}, "test", primary, secondary))
+
+	// Provider-specific operations go to the primary, even when it's unavailable.
+	if got := bkt.primaryImpl(); got != primary {
+		t.Errorf("got primary impl %T, want the primary bucket's", got)
+	}
+	if c, ok := bkt.copierImpl(); !ok || c != primary {
+		t.Errorf("got copier (%v, %v), want the primary bucket's", c, ok)
+	}
+
+	// Buckets without failover use their implementation.
+	bkt = newTestBucket(secondary)
+	if got := bkt.primaryImpl(); got != secondary {
+		t.Errorf("got primary impl %T, want the bucket's", got)
+	}
+}
@@ -0,0 +1,125 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ForEach lists the objects in the bucket matching query and calls fn
+// for each of them, using up to concurrency concurrent calls.
+//
+// Objects are dispatched in listing order as the listing is paginated,
+// so the full listing is never held in memory.
+//
+// By default all objects are processed and any errors are combined and
+// returned once done. With WithStopOnError the remaining work is canceled
+// on the first error, which is then returned.
+//
+// If ctx is canceled no more objects are dispatched, and ForEach returns
+// once the running calls to fn have returned.
+func (b *Bucket) ForEach(ctx context.Context, query *Query, concurrency int, fn func(ctx context.Context, entry *ListEntry) error, options ...ForEachOption) error {
+	var opt forEachOptions
+	for _, o := range options {
+		o.applyForEach(&opt)
+	}
+	concurrency = max(concurrency, 1)
+
+	outer := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+		if opt.stopOnError {
+			cancel()
+		}
+	}
+
+	entries := make(chan *ListEntry)
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entry := range entries {
+				if err := fn(ctx, entry); err != nil {
+					fail(fmt.Errorf("%s: %w", entry.Name, err))
+				}
+			}
+		}()
+	}
+
+list:
+	for entry, err := range b.List(ctx, query) {
+		if err != nil {
+			if ctx.Err() == nil {
+				fail(err)
+			}
+			break
+		}
+
+		select {
+		case entries <- entry:
+		case <-ctx.Done():
+			break list
+		}
+	}
+	close(entries)
+	wg.Wait()
+
+	switch {
+	case len(errs) == 0:
+		return outer.Err()
+	case opt.stopOnError:
+		return errs[0]
+	default:
+		return errors.Join(errs...)
+	}
+}
+
+// ListChan is like List, but lists the objects in the background and sends
+// them on the returned channel, for consumers built around channels.
+//
+// The entry channel is closed once the listing completes. If it fails, or ctx
+// is canceled before it completes, the error is sent on the error channel
+// before the entry channel is closed. The error channel is closed after that,
+// so it's safe to receive from once the entry channel has been drained:
+//
+//	entries, errc := bkt.ListChan(ctx, query)
+//	for entry := range entries {
+//		// ...
+//	}
+//	if err := <-errc; err != nil {
+//		// ...
+//	}
+//
+// To stop consuming early, cancel ctx, which stops the listing.
+func (b *Bucket) ListChan(ctx context.Context, query *Query, options ...ListOption) (<-chan *ListEntry, <-chan error) {
+	entries := make(chan *ListEntry)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(entries)
+		for entry, err := range b.List(ctx, query, options...) {
+			if err != nil {
+				errc <- err
+				return
+			}
+			select {
+			case entries <- entry:
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+	}()
+	return entries, errc
+}
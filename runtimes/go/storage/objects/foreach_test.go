@@ -0,0 +1,47 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestListChan(t *testing.T) {
+	bkt, _ := newTrashTestBucket()
+
+	entries, errc := bkt.ListChan(context.Background(), &Query{})
+	var names []string
+	for entry := range entries {
+		names = append(names, entry.Name)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a.txt", "b/c.txt"}; !slices.Equal(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+}
+
+func TestListChan_Cancel(t *testing.T) {
+	bkt, _ := newTrashTestBucket()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	entries, errc := bkt.ListChan(ctx, &Query{})
+	<-entries
+	cancel()
+
+	// The listing stops without the remaining entries being received.
+	select {
+	case err := <-errc:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got err %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the listing to stop")
+	}
+	if _, ok := <-entries; ok {
+		t.Error("got entry after cancellation, want the channel closed")
+	}
+}
@@ -0,0 +1,97 @@
+package objects
+
+import (
+	"context"
+	"time"
+)
+
+// Operation describes an object storage operation, as passed to hooks.
+type Operation struct {
+	// Bucket is the name of the bucket.
+	Bucket string
+
+	// Name is the name of the operation, such as "upload", "download",
+	// "list", "remove", "attrs", "exists", "signed_upload_url" or
+	// "signed_download_url".
+	Name string
+
+	// Object is the name of the object, or the prefix being listed.
+	Object string
+
+	// Start is when the operation started.
+	Start time.Time
+}
+
+// Hook is a set of functions called around object storage operations,
+// for cross-cutting concerns like auditing or cost tracking.
+// See RegisterHook.
+type Hook struct {
+	// Before, if set, is called before each operation.
+	// If it returns an error the operation is not performed,
+	// and the error is returned from the operation.
+	Before func(ctx context.Context, op *Operation) error
+
+	// After, if set, is called when each operation has completed,
+	// with the error it failed with or nil.
+	After func(ctx context.Context, op *Operation, err error)
+}
+
+// registerHook registers a hook for all operations against buckets
+// managed by mgr.
+func (mgr *Manager) registerHook(h Hook) {
+	mgr.hooksMu.Lock()
+	defer mgr.hooksMu.Unlock()
+	// Copy on write, so running operations keep the hooks they started with.
+	mgr.hooks = append(mgr.hooks[:len(mgr.hooks):len(mgr.hooks)], h)
+}
+
+// hookRun tracks the hooks run for a single operation.
+type hookRun struct {
+	ctx   context.Context
+	op    *Operation
+	hooks []Hook // the hooks whose Before function was called
+}
+
+// startOperation calls the Before functions of the registered hooks.
+// It returns an error if a hook rejected the operation, in which case
+// the operation must not be performed, and end must still be called.
+//
+// Hooks run outside of retries, so they're called once per operation
+// regardless of how many requests it took.
+func (b *Bucket) startOperation(ctx context.Context, name, object string) (*hookRun, error) {
+	b.mgr.hooksMu.RLock()
+	hooks := b.mgr.hooks
+	b.mgr.hooksMu.RUnlock()
+	if len(hooks) == 0 {
+		return nil, nil
+	}
+
+	run := &hookRun{
+		ctx: ctx,
+		op:  &Operation{Bucket: b.name, Name: name, Object: object, Start: time.Now()},
+	}
+	for i, h := range hooks {
+		if h.Before != nil {
+			if err := h.Before(ctx, run.op); err != nil {
+				run.hooks = hooks[:i]
+				return run, err
+			}
+		}
+	}
+	run.hooks = hooks
+	return run, nil
+}
+
+// end calls the After functions of the hooks whose Before function was called,
+// in reverse order, so that hooks nest like middleware.
+func (r *hookRun) end(err error) {
+	if r == nil {
+		return
+	}
+	for i := len(r.hooks) - 1; i >= 0; i-- {
+		if after := r.hooks[i].After; after != nil {
+			after(r.ctx, r.op, err)
+		}
+	}
+	r.hooks = nil
+}
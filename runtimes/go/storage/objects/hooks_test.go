@@ -0,0 +1,69 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestHooks_Order(t *testing.T) {
+	bkt := newTestBucket(&memImpl{data: []byte("data")})
+
+	var calls []string
+	for _, name := range []string{"a", "b"} {
+		bkt.mgr.registerHook(Hook{
+			Before: func(ctx context.Context, op *Operation) error {
+				calls = append(calls, "before "+name+" "+op.Name+" "+op.Object)
+				return nil
+			},
+			After: func(ctx context.Context, op *Operation, err error) {
+				calls = append(calls, "after "+name+" "+op.Name)
+			},
+		})
+	}
+
+	if _, err := bkt.Attrs(context.Background(), "obj"); err != nil {
+		t.Fatal(err)
+	}
+	w := bkt.Upload(context.Background(), "obj")
+	_, _ = w.Write([]byte("new data"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"before a attrs obj", "before b attrs obj", "after b attrs", "after a attrs",
+		"before a upload obj", "before b upload obj", "after b upload", "after a upload",
+	}
+	if !slices.Equal(calls, want) {
+		t.Errorf("got calls:\n%q\nwant:\n%q", calls, want)
+	}
+}
+
+func TestHooks_Reject(t *testing.T) {
+	impl := &memImpl{data: []byte("data")}
+	bkt := newTestBucket(impl)
+
+	errDenied := errors.New("denied")
+	var afterErrs []error
+	bkt.mgr.registerHook(Hook{
+		After: func(ctx context.Context, op *Operation, err error) { afterErrs = append(afterErrs, err) },
+	})
+	bkt.mgr.registerHook(Hook{
+		Before: func(ctx context.Context, op *Operation) error { return errDenied },
+		After:  func(ctx context.Context, op *Operation, err error) { t.Error("After called for rejecting hook") },
+	})
+
+	w := bkt.Upload(context.Background(), "obj")
+	_, _ = w.Write([]byte("new data"))
+	if err := w.Close(); !errors.Is(err, errDenied) {
+		t.Fatalf("got err %v, want errDenied", err)
+	}
+	if string(impl.data) != "data" {
+		t.Errorf("rejected upload modified the object: %q", impl.data)
+	}
+	if len(afterErrs) != 1 || !errors.Is(afterErrs[0], errDenied) {
+		t.Errorf("got After errors %v, want [errDenied]", afterErrs)
+	}
+}
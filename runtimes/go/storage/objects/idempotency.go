@@ -0,0 +1,64 @@
+package objects
+
+import (
+	"context"
+	"errors"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// checkIdempotency checks whether object has already been uploaded
+// with the given idempotency key.
+//
+// It returns the attributes of the existing object if so. Otherwise it
+// returns the preconditions that make the upload fail if another upload
+// completes first: that the object still doesn't exist, or that it's still
+// the existing object uploaded without the key.
+func checkIdempotency(ctx context.Context, impl types.BucketImpl, object types.CloudObject, key string) (done *types.ObjectAttrs, pre types.Preconditions, err error) {
+	attrs, err := impl.Attrs(types.AttrsData{Ctx: ctx, Object: object})
+	if errors.Is(err, types.ErrObjectNotExist) {
+		return nil, types.Preconditions{NotExists: true}, nil
+	} else if err != nil {
+		return nil, types.Preconditions{}, err
+	}
+
+	if attrs.IdempotencyKey == key {
+		return attrs, types.Preconditions{}, nil
+	}
+	return nil, types.Preconditions{MatchETag: attrs.ETag, MatchVersion: attrs.Version}, nil
+}
+
+// completedUploader is an uploader for an upload that has already completed.
+// It discards the data written to it.
+type completedUploader struct {
+	attrs *types.ObjectAttrs
+}
+
+func (u *completedUploader) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+func (u *completedUploader) Abort(err error) {}
+func (u *completedUploader) Complete() (*types.ObjectAttrs, error) {
+	return u.attrs, nil
+}
+
+var _ types.Uploader = &completedUploader{}
+
+// idempotentUploader is an uploader for an upload with an idempotency key.
+// If the upload fails its preconditions because a concurrent upload
+// with the same key completed first, it's reported as completed
+// with the attributes of that upload.
+type idempotentUploader struct {
+	types.Uploader
+	recheck func() (*types.ObjectAttrs, error)
+}
+
+func (u *idempotentUploader) Complete() (*types.ObjectAttrs, error) {
+	attrs, err := u.Uploader.Complete()
+	if errors.Is(err, types.ErrPreconditionFailed) {
+		if done, checkErr := u.recheck(); checkErr == nil && done != nil {
+			return done, nil
+		}
+	}
+	return attrs, err
+}
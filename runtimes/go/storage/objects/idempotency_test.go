@@ -0,0 +1,157 @@
+package objects
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// attrsImpl is a bucket implementation that only supports Attrs.
+type attrsImpl struct {
+	types.BucketImpl
+	objects map[types.CloudObject]*types.ObjectAttrs
+}
+
+func (b *attrsImpl) Attrs(data types.AttrsData) (*types.ObjectAttrs, error) {
+	if attrs, ok := b.objects[data.Object]; ok {
+		return attrs, nil
+	}
+	return nil, types.ErrObjectNotExist
+}
+
+func TestCheckIdempotency(t *testing.T) {
+	existing := &types.ObjectAttrs{Object: "uploaded", Size: 4, ETag: "etag-1", Version: "1", IdempotencyKey: "req-1"}
+	impl := &attrsImpl{objects: map[types.CloudObject]*types.ObjectAttrs{
+		"uploaded": existing,
+		"plain":    {Object: "plain", Size: 4, ETag: "etag-2", Version: "2"},
+	}}
+
+	tests := []struct {
+		name    string
+		object  types.CloudObject
+		key     string
+		want    *types.ObjectAttrs
+		wantPre types.Preconditions
+	}{
+		{name: "not_exists", object: "missing", key: "req-1", want: nil, wantPre: types.Preconditions{NotExists: true}},
+		{name: "retry", object: "uploaded", key: "req-1", want: existing},
+		{name: "different_key", object: "uploaded", key: "req-2", want: nil, wantPre: types.Preconditions{MatchETag: "etag-1", MatchVersion: "1"}},
+		{name: "no_key", object: "plain", key: "req-1", want: nil, wantPre: types.Preconditions{MatchETag: "etag-2", MatchVersion: "2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, pre, err := checkIdempotency(context.Background(), impl, tt.object, tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("checkIdempotency() = %+v, want %+v", got, tt.want)
+			}
+			if pre != tt.wantPre {
+				t.Errorf("checkIdempotency() preconditions = %+v, want %+v", pre, tt.wantPre)
+			}
+		})
+	}
+}
+
+func TestCompletedUploader(t *testing.T) {
+	attrs := &types.ObjectAttrs{Object: "uploaded", Size: 4}
+	u := &completedUploader{attrs: attrs}
+
+	// A retried upload writes the data again; it should be discarded.
+	if n, err := u.Write([]byte("test")); n != 4 || err != nil {
+		t.Fatalf("Write() = %d, %v, want 4, nil", n, err)
+	}
+	if got, err := u.Complete(); got != attrs || err != nil {
+		t.Fatalf("Complete() = %+v, %v, want existing attrs", got, err)
+	}
+}
+
+// countingImpl counts the uploads that reach the underlying implementation.
+type countingImpl struct {
+	*multiImpl
+	uploads int
+
+	// beforeComplete, if set, is called before an upload completes.
+	beforeComplete func()
+}
+
+func (c *countingImpl) Upload(data types.UploadData) (types.Uploader, error) {
+	c.uploads++
+	u, err := c.multiImpl.Upload(data)
+	return &hookUploader{Uploader: u, before: c.beforeComplete}, err
+}
+
+type hookUploader struct {
+	types.Uploader
+	before func()
+}
+
+func (u *hookUploader) Complete() (*types.ObjectAttrs, error) {
+	if u.before != nil {
+		u.before()
+	}
+	return u.Uploader.Complete()
+}
+
+func upload(bkt *Bucket, object, data string, options ...UploadOption) error {
+	w := bkt.Upload(context.Background(), object, options...)
+	if _, err := io.WriteString(w, data); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func TestUpload_IdempotentRetry(t *testing.T) {
+	impl := &countingImpl{multiImpl: &multiImpl{objects: map[types.CloudObject]*multiObject{}}}
+	bkt := newTestBucket(impl)
+
+	if err := upload(bkt, "obj", "first", WithIdempotencyKey("req-1")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Retrying the upload with the same key doesn't reach the provider.
+	if err := upload(bkt, "obj", "second", WithIdempotencyKey("req-1")); err != nil {
+		t.Fatal(err)
+	}
+	if impl.uploads != 1 {
+		t.Errorf("got %d uploads to the provider, want 1", impl.uploads)
+	}
+	if got := string(impl.objects["obj"].data); got != "first" {
+		t.Errorf("got object %q, want the first upload", got)
+	}
+
+	// An upload with a different key is made.
+	if err := upload(bkt, "obj", "third", WithIdempotencyKey("req-2")); err != nil {
+		t.Fatal(err)
+	}
+	if impl.uploads != 2 {
+		t.Errorf("got %d uploads to the provider, want 2", impl.uploads)
+	}
+}
+
+func TestUpload_IdempotentConcurrentRetry(t *testing.T) {
+	impl := &countingImpl{multiImpl: &multiImpl{objects: map[types.CloudObject]*multiObject{}}}
+	bkt := newTestBucket(impl)
+
+	// Another retry of the upload completes after this one checked the object
+	// didn't exist, but before this one completes.
+	impl.beforeComplete = func() {
+		impl.beforeComplete = nil
+		if err := upload(bkt, "obj", "concurrent", WithIdempotencyKey("req-1")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := upload(bkt, "obj", "retry", WithIdempotencyKey("req-1")); err != nil {
+		t.Fatalf("got err %v, want the upload to be reported as completed", err)
+	}
+	if impl.uploads != 2 {
+		t.Errorf("got %d uploads to the provider, want 2", impl.uploads)
+	}
+	if got := string(impl.objects["obj"].data); got != "concurrent" {
+		t.Errorf("got object %q, want it not overwritten", got)
+	}
+}
@@ -0,0 +1,187 @@
+package objects
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// IndexFormat is the file format of an index generated by GenerateIndex.
+type IndexFormat int
+
+const (
+	// IndexJSON is a JSON object with the indexed prefix, when the index
+	// was generated, and the list of objects under "objects".
+	IndexJSON IndexFormat = iota
+
+	// IndexCSV is a CSV file with a header row and a row per object.
+	IndexCSV
+)
+
+func (f IndexFormat) ext() string {
+	if f == IndexCSV {
+		return "csv"
+	}
+	return "json"
+}
+
+func (f IndexFormat) contentType() string {
+	if f == IndexCSV {
+		return "text/csv"
+	}
+	return "application/json"
+}
+
+// IndexResult describes an index generated by GenerateIndex.
+type IndexResult struct {
+	// Key is the name of the index object.
+	Key string
+
+	// Objects is the number of objects listed in the index.
+	Objects int
+
+	// Unchanged is true if the existing index was up to date,
+	// and so wasn't rewritten. See WithIncrementalIndex.
+	Unchanged bool
+}
+
+// GenerateIndex lists the objects under prefix and writes an index of them,
+// with their names, sizes, ETags and when they were last modified,
+// to an object in the bucket. The index object itself isn't listed.
+//
+// The listing is streamed to the index as it's read, so it scales to
+// any number of objects. By default the index is written as JSON to
+// "index.json" under prefix; see WithIndexKey and WithIndexFormat.
+//
+// With WithIncrementalIndex, the objects are first listed to check
+// whether they've changed since the index was last generated with it,
+// and the index is only rewritten if they have.
+func (b *Bucket) GenerateIndex(ctx context.Context, prefix string, options ...IndexOption) (*IndexResult, error) {
+	var opt indexOptions
+	for _, o := range options {
+		o.applyIndex(&opt)
+	}
+	key := opt.key
+	if key == "" {
+		key = prefix + "index." + opt.format.ext()
+	}
+	query := &Query{Prefix: prefix}
+
+	uploadOpts := []UploadOption{WithUploadAttrs(UploadAttrs{ContentType: opt.format.contentType()})}
+	if opt.incremental {
+		digest, n, err := b.indexDigest(ctx, query, key)
+		if err != nil {
+			return nil, err
+		}
+		// The digest is stored as the index's idempotency key.
+		idemKey := fmt.Sprintf("index:%s:%s", opt.format.ext(), digest)
+		attrs, err := b.impl.Attrs(types.AttrsData{Ctx: ctx, Object: b.toCloudObject(key)})
+		if err == nil && attrs.IdempotencyKey == idemKey {
+			return &IndexResult{Key: key, Objects: n, Unchanged: true}, nil
+		} else if err != nil && !errors.Is(err, types.ErrObjectNotExist) {
+			return nil, err
+		}
+		uploadOpts = append(uploadOpts, WithIdempotencyKey(idemKey))
+	}
+
+	w := b.Upload(ctx, key, uploadOpts...)
+	n, err := b.writeIndex(ctx, w, query, key, opt.format)
+	if err != nil {
+		w.Abort(err)
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return &IndexResult{Key: key, Objects: n}, nil
+}
+
+// indexDigest lists the objects matching query, except the index itself,
+// and returns a digest of their names, sizes and ETags.
+func (b *Bucket) indexDigest(ctx context.Context, query *Query, key string) (digest string, n int, err error) {
+	h := sha256.New()
+	for entry, err := range b.List(ctx, query) {
+		if err != nil {
+			return "", 0, err
+		} else if entry.Name == key {
+			continue
+		}
+		_, _ = fmt.Fprintf(h, "%s\x00%d\x00%s\n", entry.Name, entry.Size, entry.ETag)
+		n++
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), n, nil
+}
+
+// indexEntry is an object listed in a JSON index.
+type indexEntry struct {
+	Name         string `json:"name"`
+	Size         int64  `json:"size"`
+	ETag         string `json:"etag"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// writeIndex writes the index of the objects matching query to w,
+// returning the number of objects listed.
+func (b *Bucket) writeIndex(ctx context.Context, w io.Writer, query *Query, key string, format IndexFormat) (n int, err error) {
+	bw := bufio.NewWriter(w)
+	var cw *csv.Writer
+	if format == IndexCSV {
+		cw = csv.NewWriter(bw)
+		_ = cw.Write([]string{"name", "size", "etag", "last_modified"})
+	} else {
+		header, _ := json.Marshal(map[string]any{
+			"prefix":       query.Prefix,
+			"generated_at": time.Now().UTC().Format(time.RFC3339),
+		})
+		// Leave the object open to add the objects to it.
+		_, _ = bw.Write(header[:len(header)-1])
+		_, _ = bw.WriteString(`,"objects":[`)
+	}
+
+	for entry, listErr := range b.List(ctx, query) {
+		if listErr != nil {
+			return 0, listErr
+		} else if entry.Name == key {
+			continue
+		}
+
+		var lastModified string
+		if !entry.LastModified.IsZero() {
+			lastModified = entry.LastModified.UTC().Format(time.RFC3339)
+		}
+		if cw != nil {
+			err = cw.Write([]string{entry.Name, strconv.FormatInt(entry.Size, 10), entry.ETag, lastModified})
+		} else {
+			var data []byte
+			data, err = json.Marshal(indexEntry{entry.Name, entry.Size, entry.ETag, lastModified})
+			if n > 0 {
+				_ = bw.WriteByte(',')
+			}
+			_ = bw.WriteByte('\n')
+			_, _ = bw.Write(data)
+		}
+		if err != nil {
+			return 0, err
+		}
+		n++
+	}
+
+	if cw != nil {
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return 0, err
+		}
+	} else {
+		_, _ = fmt.Fprintf(bw, "\n],\"count\":%d}\n", n)
+	}
+	return n, bw.Flush()
+}
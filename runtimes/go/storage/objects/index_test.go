@@ -0,0 +1,82 @@
+package objects
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestGenerateIndex(t *testing.T) {
+	bkt, impl := newTrashTestBucket()
+	ctx := context.Background()
+
+	res, err := bkt.GenerateIndex(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	} else if res.Key != "index.json" || res.Objects != 2 {
+		t.Fatalf("got result %+v, want 2 objects in index.json", res)
+	}
+
+	var index struct {
+		Objects []indexEntry `json:"objects"`
+		Count   int          `json:"count"`
+	}
+	if err := json.Unmarshal(impl.objects["index.json"].data, &index); err != nil {
+		t.Fatalf("invalid index: %v\n%s", err, impl.objects["index.json"].data)
+	}
+	want := []indexEntry{{Name: "a.txt", Size: 1, ETag: "a"}, {Name: "b/c.txt", Size: 1, ETag: "c"}}
+	if !slices.Equal(index.Objects, want) || index.Count != 2 {
+		t.Errorf("got index %+v, want %+v", index, want)
+	}
+
+	// Regenerating the index doesn't list the index itself.
+	if res, err := bkt.GenerateIndex(ctx, ""); err != nil {
+		t.Fatal(err)
+	} else if res.Objects != 2 {
+		t.Errorf("got %d objects, want 2", res.Objects)
+	}
+}
+
+func TestGenerateIndex_CSV(t *testing.T) {
+	bkt, impl := newTrashTestBucket()
+
+	_, err := bkt.GenerateIndex(context.Background(), "b/", WithIndexFormat(IndexCSV), WithIndexKey("indexes/b.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(impl.objects["indexes/b.csv"].data)
+	if want := "name,size,etag,last_modified\nb/c.txt,1,c,\n"; got != want {
+		t.Errorf("got index %q, want %q", got, want)
+	}
+	if ct := impl.objects["indexes/b.csv"].attrs.ContentType; ct != "text/csv" {
+		t.Errorf("got content type %q, want text/csv", ct)
+	}
+}
+
+func TestGenerateIndex_Incremental(t *testing.T) {
+	bkt, impl := newTrashTestBucket()
+	ctx := context.Background()
+
+	res, err := bkt.GenerateIndex(ctx, "", WithIncrementalIndex())
+	if err != nil {
+		t.Fatal(err)
+	} else if res.Unchanged {
+		t.Fatalf("got unchanged result for a new index")
+	}
+
+	res, err = bkt.GenerateIndex(ctx, "", WithIncrementalIndex())
+	if err != nil {
+		t.Fatal(err)
+	} else if !res.Unchanged || res.Objects != 2 {
+		t.Errorf("got result %+v, want the index to be unchanged", res)
+	}
+
+	impl.objects["d.txt"] = &multiObject{data: []byte("d")}
+	res, err = bkt.GenerateIndex(ctx, "", WithIncrementalIndex())
+	if err != nil {
+		t.Fatal(err)
+	} else if res.Unchanged || res.Objects != 3 {
+		t.Errorf("got result %+v, want the index to be rewritten with 3 objects", res)
+	}
+}
@@ -0,0 +1,325 @@
+// Package encryption implements client-side envelope encryption of objects.
+//
+// Each object is encrypted with its own random data key, which is stored with
+// the object wrapped (encrypted) under a master key. The content is encrypted
+// with AES-256-GCM in fixed-size chunks so it can be streamed, using the
+// STREAM construction to detect reordered, duplicated or truncated chunks.
+//
+// An encrypted object starts with a header consisting of a magic string
+// and a random nonce prefix, followed by the encrypted chunks. Each chunk
+// holds up to chunkSize bytes of content and is followed by its GCM tag.
+// The nonce of each chunk is the nonce prefix, the chunk index and a flag
+// indicating whether it's the last chunk.
+package encryption
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+const (
+	chunkSize       = 64 * 1024
+	tagSize         = 16
+	keySize         = 32
+	noncePrefixSize = 7
+
+	// HeaderSize is the size of the header of encrypted objects.
+	HeaderSize = len(magic) + noncePrefixSize
+)
+
+// magic identifies encrypted objects.
+const magic = "\x00encore\x01"
+
+// ErrDecrypt is returned when content can't be decrypted.
+var ErrDecrypt = errors.New("message authentication failed")
+
+// HasHeader reports whether p starts with the header of an encrypted object.
+func HasHeader(p []byte) bool {
+	return len(p) >= HeaderSize && string(p[:len(magic)]) == magic
+}
+
+// EncryptedSize reports the size of size bytes of content once encrypted.
+func EncryptedSize(size int64) int64 {
+	chunks := max((size+chunkSize-1)/chunkSize, 1)
+	return int64(HeaderSize) + size + chunks*tagSize
+}
+
+// MasterKey is a key that data keys are wrapped with.
+type MasterKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// NewMasterKey returns a master key with the given ID.
+// The key must be 32 bytes, for use with AES-256.
+func NewMasterKey(id string, key []byte) (*MasterKey, error) {
+	if id == "" {
+		return nil, errors.New("missing key id")
+	} else if len(key) != keySize {
+		return nil, fmt.Errorf("master key must be %d bytes, got %d", keySize, len(key))
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &MasterKey{id: id, aead: aead}, nil
+}
+
+// ParseMasterKey is like NewMasterKey but takes a base64-encoded key.
+func ParseMasterKey(id, key string) (*MasterKey, error) {
+	data, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid master key: %v", err)
+	}
+	return NewMasterKey(id, data)
+}
+
+// NewDataKey generates a new data key, and returns it along with
+// its wrapped form to store with the object.
+func (k *MasterKey) NewDataKey() (dataKey []byte, enc *types.Encryption, err error) {
+	dataKey = make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+
+	// Use the key ID as additional data so a wrapped key
+	// can't be passed off as being wrapped by another key.
+	wrapped := k.aead.Seal(nonce, nonce, dataKey, []byte(k.id))
+	return dataKey, &types.Encryption{
+		KeyID:      k.id,
+		WrappedKey: base64.StdEncoding.EncodeToString(wrapped),
+	}, nil
+}
+
+// UnwrapKey returns the data key wrapped in enc.
+func (k *MasterKey) UnwrapKey(enc *types.Encryption) ([]byte, error) {
+	if enc.KeyID != k.id {
+		return nil, fmt.Errorf("object is encrypted with key %q, but the configured key is %q", enc.KeyID, k.id)
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(enc.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wrapped key: %v", err)
+	} else if len(wrapped) < k.aead.NonceSize() {
+		return nil, errors.New("invalid wrapped key: too short")
+	}
+	nonce, wrapped := wrapped[:k.aead.NonceSize()], wrapped[k.aead.NonceSize():]
+	dataKey, err := k.aead.Open(nil, nonce, wrapped, []byte(k.id))
+	if err != nil {
+		return nil, fmt.Errorf("unwrap key: %w", ErrDecrypt)
+	}
+	return dataKey, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// stream computes the nonces for the chunks of an object.
+type stream struct {
+	aead  cipher.AEAD
+	nonce [12]byte
+	index uint32
+}
+
+func newStream(dataKey, noncePrefix []byte) (*stream, error) {
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	s := &stream{aead: aead}
+	copy(s.nonce[:], noncePrefix)
+	return s, nil
+}
+
+// next returns the nonce for the next chunk.
+func (s *stream) next(last bool) ([]byte, error) {
+	if s.index == math.MaxUint32 {
+		return nil, errors.New("object too large to encrypt")
+	}
+	binary.BigEndian.PutUint32(s.nonce[noncePrefixSize:], s.index)
+	s.nonce[len(s.nonce)-1] = 0
+	if last {
+		s.nonce[len(s.nonce)-1] = 1
+	}
+	s.index++
+	return s.nonce[:], nil
+}
+
+// Writer encrypts content written to it.
+type Writer struct {
+	dst    io.Writer
+	stream *stream
+	buf    []byte // buffered content of the current chunk
+	out    []byte // scratch space for sealed chunks
+	err    error
+}
+
+// NewWriter returns a writer that writes the encrypted form
+// of the content written to it to dst.
+//
+// Close must be called to write the final chunk.
+// It does not close dst.
+func NewWriter(dst io.Writer, dataKey []byte) (*Writer, error) {
+	header := make([]byte, HeaderSize)
+	copy(header, magic)
+	if _, err := rand.Read(header[len(magic):]); err != nil {
+		return nil, err
+	}
+	s, err := newStream(dataKey, header[len(magic):])
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dst.Write(header); err != nil {
+		return nil, err
+	}
+	return &Writer{
+		dst:    dst,
+		stream: s,
+		buf:    make([]byte, 0, chunkSize),
+		out:    make([]byte, 0, chunkSize+tagSize),
+	}, nil
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	n := 0
+	for len(p) > 0 {
+		// Only seal a full chunk once there's more content,
+		// since the last chunk must be sealed as such.
+		if len(w.buf) == chunkSize {
+			if w.err = w.seal(false); w.err != nil {
+				return n, w.err
+			}
+		}
+		c := copy(w.buf[len(w.buf):chunkSize], p)
+		w.buf = w.buf[:len(w.buf)+c]
+		p = p[c:]
+		n += c
+	}
+	return n, nil
+}
+
+// Close writes the final chunk.
+func (w *Writer) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	w.err = w.seal(true)
+	if w.err == nil {
+		w.err = errors.New("write to closed writer")
+		return nil
+	}
+	return w.err
+}
+
+func (w *Writer) seal(last bool) error {
+	nonce, err := w.stream.next(last)
+	if err != nil {
+		return err
+	}
+	w.out = w.stream.aead.Seal(w.out[:0], nonce, w.buf, nil)
+	w.buf = w.buf[:0]
+	_, err = w.dst.Write(w.out)
+	return err
+}
+
+// Reader decrypts content read from it.
+type Reader struct {
+	src    *bufio.Reader
+	stream *stream
+	buf    []byte // decrypted content not yet read
+	chunk  []byte // scratch space for encrypted chunks
+	done   bool   // whether the last chunk has been read
+	err    error
+}
+
+// NewReader returns a reader that decrypts the encrypted object read from src.
+// Reads return an error wrapping ErrDecrypt if the content has been modified
+// or truncated.
+func NewReader(src io.Reader, dataKey []byte) (*Reader, error) {
+	header := make([]byte, HeaderSize)
+	if _, err := io.ReadFull(src, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("truncated header: %w", ErrDecrypt)
+		}
+		return nil, err
+	} else if !HasHeader(header) {
+		return nil, fmt.Errorf("not an encrypted object: %w", ErrDecrypt)
+	}
+	s, err := newStream(dataKey, header[len(magic):])
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{
+		src:    bufio.NewReaderSize(src, chunkSize+tagSize+1),
+		stream: s,
+		chunk:  make([]byte, chunkSize+tagSize),
+	}, nil
+}
+
+func (r *Reader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		} else if r.done {
+			return 0, io.EOF
+		}
+		r.err = r.next()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// next reads and decrypts the next chunk.
+func (r *Reader) next() error {
+	n, err := io.ReadFull(r.src, r.chunk)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("truncated content: %w", ErrDecrypt)
+		}
+		return err
+	}
+
+	// It's the last chunk if there's no more content after it.
+	last := n < len(r.chunk)
+	if !last {
+		if _, err := r.src.Peek(1); errors.Is(err, io.EOF) {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	nonce, err := r.stream.next(last)
+	if err != nil {
+		return err
+	}
+	r.buf, err = r.stream.aead.Open(r.chunk[:0], nonce, r.chunk[:n], nil)
+	if err != nil {
+		return ErrDecrypt
+	}
+	r.done = last
+	return nil
+}
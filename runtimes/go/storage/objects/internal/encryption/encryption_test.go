@@ -0,0 +1,118 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+)
+
+func newKey(t *testing.T) []byte {
+	t.Helper()
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func encrypt(t *testing.T, key, content []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Write in odd-sized pieces to exercise the chunk buffering.
+	for p := content; len(p) > 0; {
+		n := min(len(p), 1000)
+		if _, err := w.Write(p[:n]); err != nil {
+			t.Fatal(err)
+		}
+		p = p[n:]
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	key := newKey(t)
+	for _, size := range []int{0, 1, chunkSize - 1, chunkSize, chunkSize + 1, 3*chunkSize + 17} {
+		content := make([]byte, size)
+		_, _ = rand.Read(content)
+
+		enc := encrypt(t, key, content)
+		if got, want := int64(len(enc)), EncryptedSize(int64(size)); got != want {
+			t.Errorf("size %d: encrypted to %d bytes, EncryptedSize reports %d", size, got, want)
+		}
+		if !HasHeader(enc) {
+			t.Errorf("size %d: missing header", size)
+		}
+
+		r, err := NewReader(bytes.NewReader(enc), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("size %d: %v", size, err)
+		} else if !bytes.Equal(got, content) {
+			t.Errorf("size %d: content mismatch", size)
+		}
+	}
+}
+
+func TestTampering(t *testing.T) {
+	key := newKey(t)
+	content := make([]byte, 2*chunkSize+100)
+	enc := encrypt(t, key, content)
+
+	tests := map[string][]byte{
+		"flipped_bit":       append(bytes.Clone(enc[:HeaderSize+10]), append([]byte{enc[HeaderSize+10] ^ 1}, enc[HeaderSize+11:]...)...),
+		"truncated_chunk":   enc[:len(enc)-1],
+		"dropped_last":      enc[:HeaderSize+2*(chunkSize+tagSize)],
+		"wrong_key":         nil,
+		"appended_trailing": append(bytes.Clone(enc), 0),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			k := key
+			if data == nil {
+				data, k = enc, newKey(t)
+			}
+			r, err := NewReader(bytes.NewReader(data), k)
+			if err == nil {
+				_, err = io.ReadAll(r)
+			}
+			if !errors.Is(err, ErrDecrypt) {
+				t.Fatalf("got err %v, want ErrDecrypt", err)
+			}
+		})
+	}
+}
+
+func TestWrapKey(t *testing.T) {
+	mk, err := NewMasterKey("key-1", newKey(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataKey, enc, err := mk.NewDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := mk.UnwrapKey(enc)
+	if err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(got, dataKey) {
+		t.Fatal("unwrapped key mismatch")
+	}
+
+	// A key with the same ID but different key material must fail.
+	other, _ := NewMasterKey("key-1", newKey(t))
+	if _, err := other.UnwrapKey(enc); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("got err %v, want ErrDecrypt", err)
+	}
+}
@@ -2,11 +2,16 @@ package gcs
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"iter"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -15,21 +20,34 @@ import (
 	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"encore.dev/appruntime/exported/config"
+	"encore.dev/storage/objects/internal/transport"
 	"encore.dev/storage/objects/internal/types"
 )
 
 type Manager struct {
-	ctx     context.Context
-	runtime *config.Runtime
-	clients map[*config.BucketProvider]*storage.Client
+	ctx       context.Context
+	runtime   *config.Runtime
+	transport http.RoundTripper // nil means the default
+	clients   map[*config.BucketProvider]*storage.Client
+
+	// httpClients are the authenticated HTTP clients of the storage clients,
+	// for requests the storage client doesn't support.
+	httpClients map[*config.BucketProvider]*http.Client
 }
 
-func NewManager(ctx context.Context, runtime *config.Runtime) *Manager {
-	return &Manager{ctx: ctx, runtime: runtime, clients: make(map[*config.BucketProvider]*storage.Client)}
+func NewManager(ctx context.Context, runtime *config.Runtime, transport http.RoundTripper) *Manager {
+	return &Manager{
+		ctx:         ctx,
+		runtime:     runtime,
+		transport:   transport,
+		clients:     make(map[*config.BucketProvider]*storage.Client),
+		httpClients: make(map[*config.BucketProvider]*http.Client),
+	}
 }
 
 type localSignOptions struct {
@@ -43,6 +61,14 @@ type bucket struct {
 	cfg       *config.Bucket
 	handle    *storage.BucketHandle
 	localSign *localSignOptions
+
+	// httpClient and uploadURL are used for resumable upload sessions.
+	httpClient *http.Client
+	uploadURL  string
+
+	// notificationsURL is the local emulator's feed of
+	// the bucket's notifications, when running locally.
+	notificationsURL string
 }
 
 func (mgr *Manager) ProviderName() string { return "gcs" }
@@ -56,7 +82,20 @@ func (mgr *Manager) NewBucket(provider *config.BucketProvider, runtimeCfg *confi
 
 	localSign := localSignOptionsForProvider(provider)
 	handle := client.Bucket(runtimeCfg.CloudName)
-	return &bucket{client, runtimeCfg, handle, localSign}
+	if rp := runtimeCfg.RequesterPays; rp != nil {
+		// Bill requests to the requester-pays bucket to the given project.
+		handle = handle.UserProject(rp.BillingProject)
+	}
+	return &bucket{
+		client:     client,
+		cfg:        runtimeCfg,
+		handle:     handle,
+		localSign:  localSign,
+		httpClient: mgr.httpClients[provider],
+		uploadURL:  uploadURL(provider.GCS, runtimeCfg),
+
+		notificationsURL: notificationsURL(provider.GCS, runtimeCfg),
+	}
 }
 
 func (b *bucket) Download(data types.DownloadData) (types.Downloader, error) {
@@ -66,8 +105,50 @@ func (b *bucket) Download(data types.DownloadData) (types.Downloader, error) {
 			obj = obj.Generation(gen)
 		}
 	}
-	r, err := obj.NewReader(data.Ctx)
-	return r, mapErr(err)
+	if data.Raw {
+		obj = obj.ReadCompressed(true)
+	}
+	offset, length := int64(0), int64(-1)
+	if r := data.Range; r != nil {
+		offset, length = r.Start, r.End-r.Start
+	}
+	r, err := obj.NewRangeReader(data.Ctx, offset, length)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &downloader{Reader: r, full: data.Range == nil}, nil
+}
+
+type downloader struct {
+	*storage.Reader
+	full bool // whether the whole object is being read
+}
+
+func (d *downloader) ContentEncoding() string {
+	// GCS transparently decompresses gzip-encoded objects
+	// unless we explicitly asked for the compressed data.
+	if d.Attrs.Decompressed {
+		return ""
+	}
+	return d.Attrs.ContentEncoding
+}
+
+func (d *downloader) Checksums() types.Checksums {
+	// The checksum is of the object as stored, so it can't
+	// verify parts of it, or its decompressed content.
+	if !d.full || d.Attrs.Decompressed {
+		return types.Checksums{}
+	}
+	return types.Checksums{CRC32C: crc32c(d.Attrs.CRC32C, d.Attrs.Size)}
+}
+
+// crc32c formats a CRC32C checksum as GCS reports it: base64 of the big-endian bytes.
+// GCS keeps the checksum of all objects, so zero means it's unknown unless the object is empty.
+func crc32c(sum uint32, size int64) string {
+	if sum == 0 && size > 0 {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(binary.BigEndian.AppendUint32(nil, sum))
 }
 
 func (b *bucket) Upload(data types.UploadData) (types.Uploader, error) {
@@ -78,10 +159,26 @@ func (b *bucket) Upload(data types.UploadData) (types.Uploader, error) {
 		obj = obj.If(storage.Conditions{
 			DoesNotExist: true,
 		})
+	} else if data.Pre.MatchVersion != "" {
+		gen, err := strconv.ParseInt(data.Pre.MatchVersion, 10, 64)
+		if err != nil {
+			cancel(err)
+			return nil, types.ErrInvalidArgument
+		}
+		obj = obj.If(storage.Conditions{
+			GenerationMatch: gen,
+		})
 	}
 
 	w := obj.NewWriter(ctx)
 	w.ContentType = data.Attrs.ContentType
+	w.Metadata = data.Attrs.Metadata()
+	w.KMSKeyName = data.Attrs.KMSKey
+	if data.PartSize > 0 {
+		// GCS has no limit on the number of chunks,
+		// so only use the part size if it's explicitly set.
+		w.ChunkSize = int(data.PartSize)
+	}
 
 	u := &uploader{
 		cancel: cancel,
@@ -118,26 +215,49 @@ func mapAttrs(attrs *storage.ObjectAttrs) *types.ObjectAttrs {
 		return nil
 	}
 	return &types.ObjectAttrs{
-		Object:      types.CloudObject(attrs.Name),
-		Version:     strconv.FormatInt(attrs.Generation, 10),
-		ContentType: attrs.ContentType,
-		Size:        attrs.Size,
-		ETag:        attrs.Etag,
+		Object:          types.CloudObject(attrs.Name),
+		Version:         strconv.FormatInt(attrs.Generation, 10),
+		ContentType:     attrs.ContentType,
+		ContentEncoding: attrs.ContentEncoding,
+		Size:            attrs.Size,
+		ETag:            attrs.Etag,
+		IdempotencyKey:  attrs.Metadata[types.IdempotencyKeyMetadata],
+		Encryption:      types.EncryptionFromMetadata(attrs.Metadata),
+		Trash:           types.TrashFromMetadata(attrs.Metadata),
+		UserMetadata:    types.UserMetadataFrom(attrs.Metadata),
+		Tags:            types.TagsFromMetadata(attrs.Metadata),
+		Checksums:       types.Checksums{CRC32C: crc32c(attrs.CRC32C, attrs.Size)},
 	}
 }
 
 func mapListEntry(attrs *storage.ObjectAttrs) *types.ListEntry {
+	if attrs.Prefix != "" {
+		// A common prefix when listing with a delimiter.
+		return &types.ListEntry{Object: types.CloudObject(attrs.Prefix), Prefix: true}
+	}
 	return &types.ListEntry{
-		Object: types.CloudObject(attrs.Name),
-		Size:   attrs.Size,
-		ETag:   attrs.Etag,
+		Object:       types.CloudObject(attrs.Name),
+		Size:         attrs.Size,
+		ETag:         attrs.Etag,
+		LastModified: attrs.Updated,
+		UserMetadata: types.UserMetadataFrom(attrs.Metadata),
+		Tags:         types.TagsFromMetadata(attrs.Metadata),
 	}
 }
 
-func (b *bucket) List(data types.ListData) iter.Seq2[*types.ListEntry, error] {
-	iter := b.handle.Objects(data.Ctx, &storage.Query{
-		Prefix: data.Prefix,
+func (b *bucket) objects(data types.ListData) *storage.ObjectIterator {
+	it := b.handle.Objects(data.Ctx, &storage.Query{
+		Prefix:    data.Prefix,
+		Delimiter: data.Delimiter,
 	})
+	if data.PageSize > 0 {
+		it.PageInfo().MaxSize = int(data.PageSize)
+	}
+	return it
+}
+
+func (b *bucket) List(data types.ListData) iter.Seq2[*types.ListEntry, error] {
+	iter := b.objects(data)
 	var n int64
 	return func(yield func(*types.ListEntry, error) bool) {
 		for {
@@ -164,6 +284,30 @@ func (b *bucket) List(data types.ListData) iter.Seq2[*types.ListEntry, error] {
 	}
 }
 
+var _ types.Pager = (*bucket)(nil)
+
+func (b *bucket) ListPage(data types.ListData) (*types.ListPage, error) {
+	pageSize := int(data.PageSize)
+	if pageSize <= 0 {
+		pageSize = 1000
+	}
+	var objs []*storage.ObjectAttrs
+	next, err := iterator.NewPager(b.objects(data), pageSize, data.PageToken).NextPage(&objs)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	page := &types.ListPage{NextPageToken: next}
+	for _, obj := range objs {
+		page.Entries = append(page.Entries, mapListEntry(obj))
+	}
+	// GCS returns the common prefixes after the objects.
+	slices.SortFunc(page.Entries, func(a, b *types.ListEntry) int {
+		return strings.Compare(string(a.Object), string(b.Object))
+	})
+	return page, nil
+}
+
 func (b *bucket) Remove(data types.RemoveData) error {
 	obj := b.handle.Object(data.Object.String())
 
@@ -190,6 +334,38 @@ func (b *bucket) Attrs(data types.AttrsData) (*types.ObjectAttrs, error) {
 	return mapAttrs(resp), mapErr(err)
 }
 
+// Copy copies an object server-side from another bucket of the same provider.
+func (b *bucket) Copy(data types.CopyData) (*types.ObjectAttrs, error) {
+	src, ok := data.Src.(*bucket)
+	if !ok || src.client != b.client {
+		return nil, types.ErrCopyUnsupported
+	}
+
+	dst := b.handle.Object(data.Object.String())
+	if data.Pre.NotExists {
+		dst = dst.If(storage.Conditions{
+			DoesNotExist: true,
+		})
+	} else if data.Pre.MatchVersion != "" {
+		gen, err := strconv.ParseInt(data.Pre.MatchVersion, 10, 64)
+		if err != nil {
+			return nil, types.ErrInvalidArgument
+		}
+		dst = dst.If(storage.Conditions{
+			GenerationMatch: gen,
+		})
+	}
+
+	copier := dst.CopierFrom(src.handle.Object(data.SrcObject.String()))
+	if a := data.Attrs; a != nil {
+		copier.ContentType = a.ContentType
+		copier.ContentEncoding = data.ContentEncoding
+		copier.Metadata = a.Metadata()
+	}
+	attrs, err := copier.Run(data.Ctx)
+	return mapAttrs(attrs), mapErr(err)
+}
+
 func (b *bucket) SignedUploadURL(data types.UploadURLData) (string, error) {
 	opts := &storage.SignedURLOptions{
 		Scheme:  storage.SigningSchemeV4,
@@ -259,12 +435,38 @@ func (mgr *Manager) clientForProvider(prov *config.BucketProvider) *storage.Clie
 		opts = append(opts, option.WithEndpoint(prov.GCS.Endpoint))
 	}
 
+	// Count the requests made for each operation, for WithTransferStats.
+	base := mgr.transport
+	if base == nil {
+		// Match the storage client's default transport.
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.MaxIdleConnsPerHost = 100
+		base = t
+	}
+
+	// Wrap the transport with authentication, since a custom
+	// HTTP client replaces the one the storage client would create.
+	authOpts := append([]option.ClientOption{
+		option.WithScopes(storage.ScopeFullControl, "https://www.googleapis.com/auth/cloud-platform"),
+	}, opts...)
+	if os.Getenv("STORAGE_EMULATOR_HOST") != "" {
+		// The storage client doesn't authenticate against the emulator.
+		authOpts = append(authOpts, option.WithoutAuthentication())
+	}
+	rt, err := htransport.NewTransport(mgr.ctx, transport.Count(base), authOpts...)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create object storage transport: %s", err))
+	}
+	httpClient := &http.Client{Transport: rt}
+	opts = append(opts, option.WithHTTPClient(httpClient))
+
 	client, err := storage.NewClient(mgr.ctx, opts...)
 	if err != nil {
 		panic(fmt.Sprintf("failed to create object storage client: %s", err))
 	}
 
 	mgr.clients[prov] = client
+	mgr.httpClients[prov] = httpClient
 	return client
 }
 
@@ -301,6 +503,40 @@ func mapErr(err error) error {
 			}
 		}
 
+		// Handle unsatisfiable download ranges
+		{
+			var e *googleapi.Error
+			if ok := errors.As(err, &e); ok && e.Code == http.StatusRequestedRangeNotSatisfiable {
+				return fmt.Errorf("%w: %w", types.ErrInvalidArgument, err)
+			}
+		}
+
+		// Handle rate limiting
+		{
+			var e *googleapi.Error
+			if ok := errors.As(err, &e); ok && e.Code == http.StatusTooManyRequests {
+				return fmt.Errorf("%w: %w", types.ErrThrottled, err)
+			}
+			if s, ok := status.FromError(err); ok && s.Code() == codes.ResourceExhausted {
+				return fmt.Errorf("%w: %w", types.ErrThrottled, err)
+			}
+		}
+
+		// Handle server errors and connection failures
+		{
+			var e *googleapi.Error
+			if ok := errors.As(err, &e); ok && e.Code >= http.StatusInternalServerError {
+				return fmt.Errorf("%w: %w", types.ErrUnavailable, err)
+			}
+			if s, ok := status.FromError(err); ok && s.Code() == codes.Unavailable {
+				return fmt.Errorf("%w: %w", types.ErrUnavailable, err)
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) {
+				return fmt.Errorf("%w: %w", types.ErrUnavailable, err)
+			}
+		}
+
 		return err
 	}
 }
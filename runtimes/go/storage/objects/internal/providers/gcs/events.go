@@ -0,0 +1,158 @@
+package gcs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/pubsub/v2"
+
+	"encore.dev/appruntime/exported/config"
+	"encore.dev/storage/objects/internal/types"
+)
+
+var _ types.EventSource = (*bucket)(nil)
+
+// pollRetryDelay is how long to wait before polling the local emulator
+// for notifications again after a failed poll or delivery.
+const pollRetryDelay = 5 * time.Second
+
+// notificationsURL returns the URL of the local emulator's feed of the bucket's
+// notifications, or "" if the bucket isn't served by the local emulator.
+func notificationsURL(prov *config.GCSBucketProvider, bkt *config.Bucket) string {
+	if prov.LocalSign == nil || prov.Endpoint == "" {
+		return ""
+	}
+	base := strings.TrimSuffix(strings.TrimSuffix(prov.Endpoint, "/"), "/storage/v1")
+	return base + "/_emulator/notifications/" + url.PathEscape(bkt.CloudName)
+}
+
+// SubscribeEvents receives the bucket's GCS Pub/Sub notifications from the
+// Pub/Sub subscription they are delivered to, or from the local emulator.
+func (b *bucket) SubscribeEvents(ctx context.Context, deliver func(ctx context.Context, ev *types.ObjectEvent) error) error {
+	if cfg := b.cfg.Events; cfg != nil && cfg.GCPSubscriptionID != "" {
+		return b.receiveNotifications(ctx, cfg, deliver)
+	} else if b.notificationsURL != "" {
+		return b.pollNotifications(ctx, deliver)
+	}
+	return errors.New("objects: no event notification subscription configured for bucket")
+}
+
+// receiveNotifications receives notifications from a Pub/Sub subscription.
+// Notifications that fail to be delivered are nacked, so Pub/Sub redelivers them.
+func (b *bucket) receiveNotifications(ctx context.Context, cfg *config.BucketEvents, deliver func(ctx context.Context, ev *types.ObjectEvent) error) error {
+	client, err := pubsub.NewClient(ctx, cfg.GCPProjectID)
+	if err != nil {
+		return fmt.Errorf("objects: create pubsub client: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	sub := client.Subscriber(cfg.GCPSubscriptionID)
+	return sub.Receive(ctx, func(ctx context.Context, msg *pubsub.Message) {
+		if ev, ok := parseNotification(msg.Attributes, msg.Data, b.cfg.CloudName); ok {
+			if err := deliver(ctx, ev); err != nil {
+				msg.Nack()
+				return
+			}
+		}
+		msg.Ack()
+	})
+}
+
+// pollNotifications polls the local emulator for notifications.
+// Notifications that fail to be delivered are retried after a delay.
+func (b *bucket) pollNotifications(ctx context.Context, deliver func(ctx context.Context, ev *types.ObjectEvent) error) error {
+	after := "" // start from the emulator's current notification
+	for ctx.Err() == nil {
+		var resp struct {
+			Notifications []struct {
+				ID         int64             `json:"id"`
+				Attributes map[string]string `json:"attributes"`
+				Data       json.RawMessage   `json:"data"`
+			} `json:"notifications"`
+			Next int64 `json:"next"`
+		}
+		if err := b.getJSON(ctx, b.notificationsURL+"?after="+after, &resp); err != nil {
+			sleep(ctx, pollRetryDelay)
+			continue
+		}
+
+		next := resp.Next
+		for _, n := range resp.Notifications {
+			if ev, ok := parseNotification(n.Attributes, n.Data, b.cfg.CloudName); ok {
+				if err := deliver(ctx, ev); err != nil {
+					next = n.ID - 1
+					sleep(ctx, pollRetryDelay)
+					break
+				}
+			}
+		}
+		after = strconv.FormatInt(next, 10)
+	}
+	return ctx.Err()
+}
+
+func (b *bucket) getJSON(ctx context.Context, url string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("objects: unexpected response polling notifications: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
+
+// parseNotification parses the object event for the given bucket from a GCS Pub/Sub
+// notification, with the JSON_API_V1 payload format. It reports false for notifications
+// that aren't object events, like metadata updates, and for other buckets.
+func parseNotification(attrs map[string]string, data []byte, bucketName string) (*types.ObjectEvent, bool) {
+	if attrs["bucketId"] != bucketName || attrs["objectId"] == "" {
+		return nil, false
+	}
+
+	ev := &types.ObjectEvent{
+		Object:  types.CloudObject(attrs["objectId"]),
+		Version: attrs["objectGeneration"],
+	}
+	switch attrs["eventType"] {
+	case "OBJECT_FINALIZE":
+		ev.Type = "object_created"
+		var obj struct {
+			Size int64  `json:"size,string"`
+			Etag string `json:"etag"`
+		}
+		if err := json.Unmarshal(data, &obj); err == nil {
+			ev.Size = obj.Size
+			ev.ETag = obj.Etag
+		}
+	case "OBJECT_DELETE":
+		// Overwriting an object deletes the previous generation,
+		// but the object still exists.
+		if attrs["overwrittenByGeneration"] != "" {
+			return nil, false
+		}
+		ev.Type = "object_deleted"
+	default:
+		return nil, false
+	}
+	return ev, true
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
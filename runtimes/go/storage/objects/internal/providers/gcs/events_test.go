@@ -0,0 +1,66 @@
+package gcs
+
+import (
+	"reflect"
+	"testing"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+func TestParseNotification(t *testing.T) {
+	attrs := func(eventType string, extra ...string) map[string]string {
+		m := map[string]string{
+			"eventType":        eventType,
+			"bucketId":         "photos",
+			"objectId":         "raw/a.jpg",
+			"objectGeneration": "42",
+		}
+		for i := 0; i < len(extra); i += 2 {
+			m[extra[i]] = extra[i+1]
+		}
+		return m
+	}
+
+	tests := []struct {
+		name  string
+		attrs map[string]string
+		data  string
+		want  *types.ObjectEvent
+	}{
+		{
+			name:  "finalize",
+			attrs: attrs("OBJECT_FINALIZE"),
+			data:  `{"name": "raw/a.jpg", "size": "1024", "etag": "abc"}`,
+			want:  &types.ObjectEvent{Type: "object_created", Object: "raw/a.jpg", Version: "42", Size: 1024, ETag: "abc"},
+		},
+		{
+			name:  "delete",
+			attrs: attrs("OBJECT_DELETE"),
+			data:  `{"name": "raw/a.jpg", "size": "1024"}`,
+			want:  &types.ObjectEvent{Type: "object_deleted", Object: "raw/a.jpg", Version: "42"},
+		},
+		{
+			name:  "overwritten",
+			attrs: attrs("OBJECT_DELETE", "overwrittenByGeneration", "43"),
+		},
+		{
+			name:  "metadata_update",
+			attrs: attrs("OBJECT_METADATA_UPDATE"),
+		},
+		{
+			name:  "other_bucket",
+			attrs: attrs("OBJECT_FINALIZE", "bucketId", "other"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseNotification(tt.attrs, []byte(tt.data), "photos")
+			if ok != (tt.want != nil) {
+				t.Fatalf("got ok=%v, want %v", ok, tt.want != nil)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got event %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
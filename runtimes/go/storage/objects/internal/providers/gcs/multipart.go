@@ -0,0 +1,294 @@
+package gcs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+
+	"encore.dev/appruntime/exported/config"
+	"encore.dev/storage/objects/internal/types"
+)
+
+// Multipart uploads are implemented with resumable uploads, which the storage
+// client only exposes through its Writer. Each part is written to the upload
+// session at the offset given by its part number, which requires all parts
+// but the last to have the same size, and the parts to be uploaded in order.
+//
+// The upload ID is the part size and the session URI, separated by a colon.
+
+var _ types.MultipartUploader = (*bucket)(nil)
+
+const (
+	// partAlignment is the size parts must be a multiple of,
+	// except for the last part.
+	partAlignment = 256 * 1024
+
+	// defaultPartSize is the part size used when none is given.
+	defaultPartSize = 16 * 1024 * 1024
+
+	// statusResumeIncomplete is the status GCS responds with
+	// when a resumable upload is not yet complete.
+	statusResumeIncomplete = http.StatusPermanentRedirect
+
+	// statusCanceled is the status GCS responds with
+	// when a resumable upload is canceled.
+	statusCanceled = 499
+)
+
+// uploadURL returns the URL for starting resumable uploads to the bucket.
+func uploadURL(prov *config.GCSBucketProvider, bkt *config.Bucket) string {
+	base := "https://storage.googleapis.com"
+	if prov.Endpoint != "" {
+		base = strings.TrimSuffix(strings.TrimSuffix(prov.Endpoint, "/"), "/storage/v1")
+	} else if host := os.Getenv("STORAGE_EMULATOR_HOST"); host != "" {
+		base = host
+		if !strings.Contains(host, "://") {
+			base = "http://" + host
+		}
+	}
+
+	q := url.Values{"uploadType": {"resumable"}}
+	if rp := bkt.RequesterPays; rp != nil {
+		q.Set("userProject", rp.BillingProject)
+	}
+	return base + "/upload/storage/v1/b/" + url.PathEscape(bkt.CloudName) + "/o?" + q.Encode()
+}
+
+func (b *bucket) BeginMultipart(data types.BeginMultipartData) (string, error) {
+	partSize := data.PartSize
+	if partSize == 0 {
+		partSize = defaultPartSize
+	} else if partSize < 0 || partSize%partAlignment != 0 {
+		return "", fmt.Errorf("%w: part size must be a multiple of %d bytes", types.ErrInvalidArgument, partAlignment)
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"name":        data.Object.String(),
+		"contentType": data.Attrs.ContentType,
+		"metadata":    data.Attrs.Metadata(),
+	})
+	if err != nil {
+		return "", err
+	}
+	u := b.uploadURL + "&name=" + url.QueryEscape(data.Object.String())
+	if key := data.Attrs.KMSKey; key != "" {
+		u += "&kmsKeyName=" + url.QueryEscape(key)
+	}
+	req, err := http.NewRequestWithContext(data.Ctx, "POST", u, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	if ct := data.Attrs.ContentType; ct != "" {
+		req.Header.Set("X-Upload-Content-Type", ct)
+	}
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return "", mapErr(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if err := googleapi.CheckResponse(resp); err != nil {
+		return "", mapErr(err)
+	}
+	session := resp.Header.Get("Location")
+	if session == "" {
+		return "", fmt.Errorf("objects: no upload session in response")
+	}
+	return strconv.FormatInt(partSize, 10) + ":" + session, nil
+}
+
+func (b *bucket) UploadPart(data types.UploadPartData) (*types.UploadedPart, error) {
+	partSize, session, err := parseUploadID(data.UploadID)
+	if err != nil {
+		return nil, err
+	} else if data.Size > partSize {
+		return nil, fmt.Errorf("%w: part of %d bytes exceeds the part size of %d bytes",
+			types.ErrInvalidArgument, data.Size, partSize)
+	}
+
+	// A part smaller than the part size is the last one,
+	// which completes the upload since the object's size is then known.
+	start := int64(data.PartNumber-1) * partSize
+	end := start + data.Size - 1
+	contentRange := fmt.Sprintf("bytes %d-%d/*", start, end)
+	if data.Size < partSize {
+		contentRange = fmt.Sprintf("bytes %d-%d/%d", start, end, end+1)
+		if data.Size == 0 {
+			contentRange = fmt.Sprintf("bytes */%d", start)
+		}
+	}
+
+	resp, err := b.sessionRequest(data.Ctx, "PUT", session, contentRange, data.Data, data.Size)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == statusResumeIncomplete && data.Size > 0 {
+		// Check the part was persisted; GCS ignores data past what it has persisted.
+		if persisted, _ := persistedBytes(resp); persisted <= end {
+			return nil, fmt.Errorf("%w: part %d was not persisted, parts must be uploaded in order",
+				types.ErrInvalidArgument, data.PartNumber)
+		}
+	}
+	return &types.UploadedPart{PartNumber: data.PartNumber, Size: data.Size}, nil
+}
+
+func (b *bucket) ListParts(data types.MultipartData) ([]types.UploadedPart, error) {
+	partSize, session, err := parseUploadID(data.UploadID)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.sessionRequest(data.Ctx, "PUT", session, "bytes */*", nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Only whole parts are reported, unless the upload is complete
+	// in which case the remaining bytes are the last part.
+	var persisted int64
+	complete := resp.StatusCode != statusResumeIncomplete
+	if complete {
+		var obj struct {
+			Size int64 `json:"size,string"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&obj); err != nil {
+			return nil, fmt.Errorf("objects: decode upload session status: %w", err)
+		}
+		persisted = obj.Size
+	} else if persisted, err = persistedBytes(resp); err != nil {
+		return nil, err
+	}
+
+	var parts []types.UploadedPart
+	for n := int64(0); n < persisted/partSize; n++ {
+		parts = append(parts, types.UploadedPart{PartNumber: int(n) + 1, Size: partSize})
+	}
+	if rem := persisted % partSize; complete && rem > 0 {
+		parts = append(parts, types.UploadedPart{PartNumber: len(parts) + 1, Size: rem})
+	}
+	return parts, nil
+}
+
+func (b *bucket) CompleteMultipart(data types.CompleteMultipartData) (*types.ObjectAttrs, error) {
+	_, session, err := parseUploadID(data.UploadID)
+	if err != nil {
+		return nil, err
+	}
+	var size int64
+	for _, p := range data.Parts {
+		size += p.Size
+	}
+
+	// Finalize the upload with its total size. If the last part already
+	// completed it, GCS responds with the uploaded object.
+	resp, err := b.sessionRequest(data.Ctx, "PUT", session, fmt.Sprintf("bytes */%d", size), nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == statusResumeIncomplete {
+		persisted, _ := persistedBytes(resp)
+		return nil, fmt.Errorf("%w: only %d of %d bytes have been uploaded",
+			types.ErrInvalidArgument, persisted, size)
+	}
+
+	var obj struct {
+		Generation int64 `json:"generation,string"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&obj); err != nil {
+		return nil, fmt.Errorf("objects: decode uploaded object: %w", err)
+	}
+	return b.Attrs(types.AttrsData{
+		Ctx:     data.Ctx,
+		Object:  data.Object,
+		Version: strconv.FormatInt(obj.Generation, 10),
+	})
+}
+
+func (b *bucket) AbortMultipart(data types.MultipartData) error {
+	_, session, err := parseUploadID(data.UploadID)
+	if err != nil {
+		return err
+	}
+	resp, err := b.sessionRequest(data.Ctx, "DELETE", session, "", nil, 0)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// sessionRequest makes a request to a resumable upload session.
+// Responses other than success or an incomplete upload are returned as errors.
+func (b *bucket) sessionRequest(ctx context.Context, method, session, contentRange string, body io.Reader, size int64) (*http.Response, error) {
+	if body == nil {
+		body = http.NoBody
+	}
+	req, err := http.NewRequestWithContext(ctx, method, session, body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	if contentRange != "" {
+		req.Header.Set("Content-Range", contentRange)
+	}
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, statusResumeIncomplete:
+		return resp, nil
+	case statusCanceled:
+		if method == "DELETE" {
+			return resp, nil
+		}
+		_ = resp.Body.Close()
+		return nil, types.ErrUploadNotExist
+	case http.StatusNotFound, http.StatusGone:
+		_ = resp.Body.Close()
+		return nil, types.ErrUploadNotExist
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if err := googleapi.CheckResponse(resp); err != nil {
+		return nil, mapErr(err)
+	}
+	return nil, fmt.Errorf("objects: unexpected response from upload session: %s", resp.Status)
+}
+
+// persistedBytes reports how many bytes of an incomplete upload
+// have been persisted, from the Range header of its response.
+func persistedBytes(resp *http.Response) (int64, error) {
+	r := resp.Header.Get("Range")
+	if r == "" {
+		return 0, nil
+	}
+	end, err := strconv.ParseInt(strings.TrimPrefix(r, "bytes=0-"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("objects: invalid range %q in upload session status", r)
+	}
+	return end + 1, nil
+}
+
+// parseUploadID parses an upload ID into its part size and session URI.
+func parseUploadID(id string) (partSize int64, session string, err error) {
+	size, session, ok := strings.Cut(id, ":")
+	if ok {
+		partSize, err = strconv.ParseInt(size, 10, 64)
+	}
+	if !ok || err != nil || partSize <= 0 {
+		return 0, "", fmt.Errorf("%w: invalid upload id", types.ErrInvalidArgument)
+	}
+	return partSize, session, nil
+}
@@ -25,6 +25,10 @@ func (b *BucketImpl) List(data types.ListData) iter.Seq2[*types.ListEntry, error
 	}
 }
 
+func (b *BucketImpl) ListPage(data types.ListData) (*types.ListPage, error) {
+	return nil, fmt.Errorf("cannot list objects from noop bucket")
+}
+
 func (b *BucketImpl) Remove(data types.RemoveData) error {
 	return fmt.Errorf("cannot remove from noop bucket")
 }
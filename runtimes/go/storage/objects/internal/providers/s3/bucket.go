@@ -4,43 +4,64 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"iter"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 	"sync"
 
 	"cloud.google.com/go/storage"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	awsConfig "github.com/aws/aws-sdk-go-v2/config"
 	awsCreds "github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/rs/zerolog"
 
 	"encore.dev/appruntime/exported/config"
+	"encore.dev/storage/objects/internal/transport"
 	"encore.dev/storage/objects/internal/types"
 )
 
 type Manager struct {
-	ctx     context.Context
-	runtime *config.Runtime
-	clients map[*config.BucketProvider]*clientSet
+	ctx       context.Context
+	runtime   *config.Runtime
+	transport http.RoundTripper // nil means the default
+	logger    zerolog.Logger
+	clients   map[*config.BucketProvider]*clientSet
 
 	cfgOnce          sync.Once
 	awsDefaultConfig aws.Config
 }
 
-func NewManager(ctx context.Context, runtime *config.Runtime) *Manager {
-	return &Manager{ctx: ctx, runtime: runtime, clients: make(map[*config.BucketProvider]*clientSet)}
+func NewManager(ctx context.Context, runtime *config.Runtime, transport http.RoundTripper, logger zerolog.Logger) *Manager {
+	return &Manager{ctx: ctx, runtime: runtime, transport: transport, logger: logger, clients: make(map[*config.BucketProvider]*clientSet)}
 }
 
 type bucket struct {
 	client        *s3.Client
 	presignClient *s3.PresignClient
+	provider      *config.S3BucketProvider
 	cfg           *config.Bucket
+
+	// sqsClient receives the bucket's event notifications, if configured.
+	sqsClient *sqs.Client
 }
 
 type clientSet struct {
 	client        *s3.Client
 	presignClient *s3.PresignClient
+	sqsClient     *sqs.Client
+
+	// creds are the assumed role's credentials, if a role is assumed.
+	creds *aws.CredentialsCache
 }
 
 func (mgr *Manager) ProviderName() string { return "s3" }
@@ -51,24 +72,193 @@ func (mgr *Manager) Matches(cfg *config.BucketProvider) bool {
 
 func (mgr *Manager) NewBucket(provider *config.BucketProvider, runtimeCfg *config.Bucket) types.BucketImpl {
 	clients := mgr.clientForProvider(provider)
+	client := clients.client
+	if runtimeCfg.RequesterPays != nil {
+		// Acknowledge that the requester is charged for the bucket's requests,
+		// which S3 otherwise rejects with an access error.
+		client = s3.New(client.Options(), func(o *s3.Options) {
+			o.APIOptions = append(o.APIOptions, smithyhttp.SetHeaderValue("x-amz-request-payer", "requester"))
+		})
+	}
 	return &bucket{
-		client:        clients.client,
+		client:        client,
 		presignClient: clients.presignClient,
+		provider:      provider.S3,
 		cfg:           runtimeCfg,
+		sqsClient:     clients.sqsClient,
 	}
 }
 
+// DefaultPublicBaseURL returns the public base URL of the bucket
+// when using a custom endpoint, so that public URLs use the custom host.
+func (b *bucket) DefaultPublicBaseURL() string {
+	if b.provider.Endpoint == nil {
+		return ""
+	}
+	u, err := url.Parse(*b.provider.Endpoint)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+
+	if b.provider.PathStyle {
+		u = u.JoinPath(b.cfg.CloudName)
+	} else {
+		u.Host = b.cfg.CloudName + "." + u.Host
+	}
+	if b.cfg.KeyPrefix != "" {
+		u = u.JoinPath(b.cfg.KeyPrefix)
+	}
+	return u.String()
+}
+
+// CheckEndpoints checks that any custom endpoints configured for
+// S3-compatible providers are reachable.
+func (mgr *Manager) CheckEndpoints(ctx context.Context) error {
+	var errs []error
+	for _, prov := range mgr.runtime.BucketProviders {
+		if prov.S3 == nil || prov.S3.Endpoint == nil {
+			continue
+		}
+
+		endpoint := *prov.S3.Endpoint
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Host == "" {
+			errs = append(errs, fmt.Errorf("invalid endpoint %q", endpoint))
+			continue
+		}
+		addr := u.Host
+		if u.Port() == "" {
+			if u.Scheme == "http" {
+				addr = net.JoinHostPort(u.Hostname(), "80")
+			} else {
+				addr = net.JoinHostPort(u.Hostname(), "443")
+			}
+		}
+
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("endpoint %s: %w", endpoint, err))
+			continue
+		}
+		_ = conn.Close()
+	}
+	return errors.Join(errs...)
+}
+
 func (b *bucket) Download(data types.DownloadData) (types.Downloader, error) {
 	object := string(data.Object)
-	resp, err := b.client.GetObject(data.Ctx, &s3.GetObjectInput{
+	input := &s3.GetObjectInput{
 		Bucket:    &b.cfg.CloudName,
 		Key:       &object,
 		VersionId: ptrOrNil(data.Version),
-	})
+	}
+	if r := data.Range; r != nil {
+		input.Range = ptrOrNil(fmt.Sprintf("bytes=%d-%d", r.Start, r.End-1))
+	} else {
+		input.ChecksumMode = s3types.ChecksumModeEnabled
+	}
+	resp, err := b.client.GetObject(data.Ctx, input)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	d := &downloader{ReadCloser: resp.Body, encoding: valOrZero(resp.ContentEncoding)}
+	if data.Range == nil {
+		d.checksums = types.Checksums{
+			CRC32C: fullChecksum(resp.ChecksumCRC32C),
+			SHA256: fullChecksum(resp.ChecksumSHA256),
+		}
+	}
+	return d, nil
+}
+
+type downloader struct {
+	io.ReadCloser
+	encoding  string
+	checksums types.Checksums
+}
+
+func (d *downloader) ContentEncoding() string { return d.encoding }
+
+func (d *downloader) Checksums() types.Checksums { return d.checksums }
+
+// fullChecksum returns the checksum if it's of the full object.
+// Objects uploaded in multiple parts have a checksum of the parts'
+// checksums instead, like "yZRlqg==-3", which can't be verified.
+func fullChecksum(sum *string) string {
+	if s := valOrZero(sum); !strings.Contains(s, "-") {
+		return s
+	}
+	return ""
+}
+
+// checksumAlgorithm returns the algorithm to request S3 to verify an upload with.
+func checksumAlgorithm(alg types.ChecksumAlgorithm) s3types.ChecksumAlgorithm {
+	switch alg {
+	case types.ChecksumCRC32C:
+		return s3types.ChecksumAlgorithmCrc32c
+	case types.ChecksumSHA256:
+		return s3types.ChecksumAlgorithmSha256
+	}
+	return ""
+}
+
+// Copy copies an object server-side from another bucket of the same provider.
+func (b *bucket) Copy(data types.CopyData) (*types.ObjectAttrs, error) {
+	src, ok := data.Src.(*bucket)
+	if !ok || src.client != b.client {
+		return nil, types.ErrCopyUnsupported
+	}
+
+	switch {
+	case data.Pre.MatchETag != "":
+		// S3 doesn't support conditional copies, so the object must be streamed.
+		return nil, types.ErrCopyUnsupported
+	case data.Pre.NotExists:
+		// S3 doesn't support conditional copies, so check that the object doesn't
+		// exist beforehand. Unlike for uploads, this doesn't guard against races.
+		if err := b.checkNotExists(data.Ctx, data.Object); err != nil {
+			return nil, err
+		}
+	}
+
+	// The copy source must be URL-encoded.
+	source := src.cfg.CloudName + "/" + strings.ReplaceAll(url.PathEscape(data.SrcObject.String()), "%2F", "/")
+	object := data.Object.String()
+	input := &s3.CopyObjectInput{
+		Bucket:     &b.cfg.CloudName,
+		Key:        &object,
+		CopySource: &source,
+	}
+	if attrs := data.Attrs; attrs != nil {
+		input.MetadataDirective = s3types.MetadataDirectiveReplace
+		input.Metadata = attrs.Metadata()
+		input.ContentType = ptrOrNil(attrs.ContentType)
+		input.ContentEncoding = ptrOrNil(data.ContentEncoding)
+	}
+	_, err := b.client.CopyObject(data.Ctx, input)
 	if err != nil {
 		return nil, mapErr(err)
 	}
-	return resp.Body, nil
+	return b.Attrs(types.AttrsData{Ctx: data.Ctx, Object: data.Object})
+}
+
+// checkNotExists returns types.ErrPreconditionFailed if object exists.
+func (b *bucket) checkNotExists(ctx context.Context, object types.CloudObject) error {
+	key := object.String()
+	_, err := b.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: &b.cfg.CloudName,
+		Key:    &key,
+	})
+	var notFound *s3types.NotFound
+	switch {
+	case err == nil:
+		return types.ErrPreconditionFailed
+	case errors.As(err, &notFound):
+		return nil
+	default:
+		return mapErr(err)
+	}
 }
 
 func (b *bucket) Upload(data types.UploadData) (types.Uploader, error) {
@@ -94,27 +284,18 @@ func (b *bucket) List(data types.ListData) iter.Seq2[*types.ListEntry, error] {
 				return
 			}
 
-			maxKeys := int32(1000)
+			maxKeys := pageSize(data)
 			if data.Limit != nil {
-				maxKeys = min(int32(*data.Limit-n), 1000)
+				maxKeys = min(int32(*data.Limit-n), maxKeys)
 			}
-			resp, err := b.client.ListObjectsV2(data.Ctx, &s3.ListObjectsV2Input{
-				Bucket:            &b.cfg.CloudName,
-				MaxKeys:           &maxKeys,
-				ContinuationToken: ptrOrNil(continuationToken),
-				Prefix:            ptrOrNil(data.Prefix),
-			})
+			resp, err := b.listObjects(data, maxKeys, continuationToken)
 			if err != nil {
 				yield(nil, mapErr(err))
 				return
 			}
 
-			for _, obj := range resp.Contents {
-				if !yield(&types.ListEntry{
-					Object: types.CloudObject(*obj.Key),
-					Size:   *obj.Size,
-					ETag:   *obj.ETag,
-				}, nil) {
+			for _, entry := range listEntries(resp) {
+				if !yield(entry, nil) {
 					return
 				}
 				n++
@@ -129,6 +310,61 @@ func (b *bucket) List(data types.ListData) iter.Seq2[*types.ListEntry, error] {
 	}
 }
 
+var _ types.Pager = (*bucket)(nil)
+
+func (b *bucket) ListPage(data types.ListData) (*types.ListPage, error) {
+	resp, err := b.listObjects(data, pageSize(data), data.PageToken)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &types.ListPage{
+		Entries:       listEntries(resp),
+		NextPageToken: valOrZero(resp.NextContinuationToken),
+	}, nil
+}
+
+func (b *bucket) listObjects(data types.ListData, maxKeys int32, continuationToken string) (*s3.ListObjectsV2Output, error) {
+	return b.client.ListObjectsV2(data.Ctx, &s3.ListObjectsV2Input{
+		Bucket:            &b.cfg.CloudName,
+		MaxKeys:           &maxKeys,
+		ContinuationToken: ptrOrNil(continuationToken),
+		Prefix:            ptrOrNil(data.Prefix),
+		Delimiter:         ptrOrNil(data.Delimiter),
+	})
+}
+
+// pageSize returns the number of keys to request per page, which S3 limits to 1000.
+func pageSize(data types.ListData) int32 {
+	if data.PageSize > 0 && data.PageSize < 1000 {
+		return int32(data.PageSize)
+	}
+	return 1000
+}
+
+// listEntries returns the objects and common prefixes of a listing,
+// which S3 returns separately, in lexicographic order.
+func listEntries(resp *s3.ListObjectsV2Output) []*types.ListEntry {
+	entries := make([]*types.ListEntry, 0, len(resp.Contents)+len(resp.CommonPrefixes))
+	for _, obj := range resp.Contents {
+		entries = append(entries, &types.ListEntry{
+			Object:       types.CloudObject(*obj.Key),
+			Size:         *obj.Size,
+			ETag:         *obj.ETag,
+			LastModified: aws.ToTime(obj.LastModified),
+		})
+	}
+	if len(resp.CommonPrefixes) == 0 {
+		return entries
+	}
+	for _, p := range resp.CommonPrefixes {
+		entries = append(entries, &types.ListEntry{Object: types.CloudObject(*p.Prefix), Prefix: true})
+	}
+	slices.SortFunc(entries, func(a, b *types.ListEntry) int {
+		return strings.Compare(string(a.Object), string(b.Object))
+	})
+	return entries
+}
+
 func (b *bucket) Remove(data types.RemoveData) error {
 	object := string(data.Object)
 	_, err := b.client.DeleteObject(data.Ctx, &s3.DeleteObjectInput{
@@ -150,14 +386,43 @@ func (b *bucket) Attrs(data types.AttrsData) (*types.ObjectAttrs, error) {
 		return nil, mapErr(err)
 	}
 	return &types.ObjectAttrs{
-		Object:      data.Object,
-		Version:     valOrZero(resp.VersionId),
-		ContentType: valOrZero(resp.ContentType),
-		Size:        valOrZero(resp.ContentLength),
-		ETag:        valOrZero(resp.ETag),
+		Object:          data.Object,
+		Version:         valOrZero(resp.VersionId),
+		ContentType:     valOrZero(resp.ContentType),
+		ContentEncoding: valOrZero(resp.ContentEncoding),
+		Size:            valOrZero(resp.ContentLength),
+		ETag:            valOrZero(resp.ETag),
+		IdempotencyKey:  resp.Metadata[types.IdempotencyKeyMetadata],
+		Encryption:      types.EncryptionFromMetadata(resp.Metadata),
+		Trash:           types.TrashFromMetadata(resp.Metadata),
+		UserMetadata:    types.UserMetadataFrom(resp.Metadata),
+		Tags:            types.TagsFromMetadata(resp.Metadata),
+
+		ServerSideEncryption: string(resp.ServerSideEncryption),
 	}, nil
 }
 
+// tagging encodes tags as the URL query string S3 expects, or nil if there are none.
+func tagging(tags map[string]string) *string {
+	if len(tags) == 0 {
+		return nil
+	}
+	q := make(url.Values, len(tags))
+	for k, v := range tags {
+		q.Set(k, v)
+	}
+	return ptr(q.Encode())
+}
+
+// sseKMS returns the server-side encryption to request for an upload:
+// SSE-KMS with the given key if set, and otherwise the bucket's default.
+func sseKMS(attrs types.UploadAttrs) (s3types.ServerSideEncryption, *string) {
+	if attrs.KMSKey == "" {
+		return "", nil
+	}
+	return s3types.ServerSideEncryptionAwsKms, &attrs.KMSKey
+}
+
 func (b *bucket) SignedUploadURL(data types.UploadURLData) (string, error) {
 	object := string(data.Object)
 	params := s3.PutObjectInput{
@@ -213,15 +478,42 @@ func (mgr *Manager) clientForProvider(prov *config.BucketProvider) *clientSet {
 		cfg = mgr.defaultConfig()
 	}
 
-	client := s3.New(s3.Options{
-		Region:       prov.S3.Region,
+	region := prov.S3.Region
+	if region == "" && prov.S3.Endpoint != nil {
+		// S3-compatible providers generally ignore the region,
+		// but requests must still be signed with one.
+		region = "us-east-1"
+	}
+
+	opts := s3.Options{
+		Region:       region,
 		BaseEndpoint: prov.S3.Endpoint,
+		UsePathStyle: prov.S3.PathStyle,
 		Credentials:  cfg.Credentials,
-	})
+	}
+	// Count the requests made for each operation, for WithTransferStats.
+	base := mgr.transport
+	if base == nil {
+		base = awshttp.NewBuildableClient().GetTransport()
+	}
+	opts.HTTPClient = &http.Client{Transport: transport.Count(base)}
+
+	var creds *aws.CredentialsCache
+	if role := prov.S3.AssumeRole; role != nil {
+		creds = assumeRoleCredentials(cfg, prov.S3.Region, role, mgr.logger)
+		opts.Credentials = creds
+		opts.APIOptions = append(opts.APIOptions, refreshExpiredCredentials(creds))
+	}
+	client := s3.New(opts)
 
 	clients := &clientSet{
 		client:        client,
 		presignClient: s3.NewPresignClient(client),
+		sqsClient: sqs.New(sqs.Options{
+			Region:      region,
+			Credentials: opts.Credentials,
+		}),
+		creds: creds,
 	}
 
 	mgr.clients[prov] = clients
@@ -242,23 +534,44 @@ func (mgr *Manager) defaultConfig() aws.Config {
 
 func mapErr(err error) error {
 	var (
-		noSuchKey *s3types.NoSuchKey
-		generic   smithy.APIError
+		noSuchKey    *s3types.NoSuchKey
+		noSuchUpload *s3types.NoSuchUpload
+		generic      smithy.APIError
 	)
 	switch {
 	case err == nil:
 		return nil
 	case errors.As(err, &noSuchKey):
 		return types.ErrObjectNotExist
+	case errors.As(err, &noSuchUpload):
+		return types.ErrUploadNotExist
 	case errors.As(err, &generic):
-		if generic.ErrorCode() == "PreconditionFailed" {
+		switch generic.ErrorCode() {
+		case "PreconditionFailed", "ConditionalRequestConflict":
 			return types.ErrPreconditionFailed
+		case "InvalidRange":
+			return fmt.Errorf("%w: %w", types.ErrInvalidArgument, err)
+		case "SlowDown", "Throttling", "ThrottlingException", "RequestLimitExceeded",
+			"RequestThrottled", "TooManyRequests", "TooManyRequestsException":
+			return fmt.Errorf("%w: %w", types.ErrThrottled, err)
 		}
-		return err
-	default:
-		return err
+	}
+
+	if isUnavailable(err) {
+		return fmt.Errorf("%w: %w", types.ErrUnavailable, err)
+	}
+	return err
+}
 
+// isUnavailable reports whether err indicates the provider
+// couldn't be reached or responded with a server error.
+func isUnavailable(err error) bool {
+	var respErr *smithyhttp.ResponseError
+	if errors.As(err, &respErr) && respErr.HTTPStatusCode() >= 500 {
+		return true
 	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
 }
 
 func ptrOrNil[T comparable](val T) *T {
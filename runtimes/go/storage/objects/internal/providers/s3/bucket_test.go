@@ -0,0 +1,317 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rs/zerolog"
+
+	"encore.dev/appruntime/exported/config"
+	"encore.dev/storage/objects/internal/types"
+)
+
+func TestRequesterPays(t *testing.T) {
+	payer := make(map[string]string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payer[r.URL.Path] = r.Header.Get("X-Amz-Request-Payer")
+		w.Header().Set("ETag", `"etag"`)
+	}))
+	defer srv.Close()
+
+	provider := &config.BucketProvider{S3: &config.S3BucketProvider{
+		Endpoint:        aws.String(srv.URL),
+		PathStyle:       true,
+		AccessKeyID:     aws.String("key"),
+		SecretAccessKey: aws.String("secret"),
+	}}
+	mgr := NewManager(context.Background(), &config.Runtime{}, nil, zerolog.Nop())
+	pays := mgr.NewBucket(provider, &config.Bucket{CloudName: "dataset", RequesterPays: &config.BucketRequesterPays{}})
+	owned := mgr.NewBucket(provider, &config.Bucket{CloudName: "owned"})
+
+	for _, bkt := range []types.BucketImpl{pays, owned} {
+		if _, err := bkt.Attrs(types.AttrsData{Ctx: context.Background(), Object: "key"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := payer["/dataset/key"]; got != "requester" {
+		t.Errorf("got request payer %q for the requester-pays bucket, want requester", got)
+	}
+	if got := payer["/owned/key"]; got != "" {
+		t.Errorf("got request payer %q for the other bucket, want none", got)
+	}
+}
+
+func TestMultipartUpload(t *testing.T) {
+	var completed string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case r.Method == "POST" && q.Has("uploads"):
+			fmt.Fprint(w, `<InitiateMultipartUploadResult><UploadId>upload-1</UploadId></InitiateMultipartUploadResult>`)
+		case r.Method == "PUT" && q.Get("uploadId") == "upload-1":
+			w.Header().Set("ETag", `"part-`+q.Get("partNumber")+`"`)
+		case r.Method == "GET" && q.Get("uploadId") == "upload-1":
+			fmt.Fprint(w, `<ListPartsResult><IsTruncated>false</IsTruncated>`+
+				`<Part><PartNumber>1</PartNumber><ETag>"part-1"</ETag><Size>5</Size></Part></ListPartsResult>`)
+		case r.Method == "POST" && q.Get("uploadId") == "upload-1":
+			body, _ := io.ReadAll(r.Body)
+			completed = string(body)
+			fmt.Fprint(w, `<CompleteMultipartUploadResult><ETag>"etag"</ETag></CompleteMultipartUploadResult>`)
+		case r.Method == "HEAD":
+			w.Header().Set("Content-Length", "5")
+			w.Header().Set("ETag", `"etag"`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<Error><Code>NoSuchUpload</Code></Error>`)
+		}
+	}))
+	defer srv.Close()
+
+	provider := &config.BucketProvider{S3: &config.S3BucketProvider{
+		Endpoint:        aws.String(srv.URL),
+		PathStyle:       true,
+		AccessKeyID:     aws.String("key"),
+		SecretAccessKey: aws.String("secret"),
+	}}
+	mgr := NewManager(context.Background(), &config.Runtime{}, nil, zerolog.Nop())
+	bkt := mgr.NewBucket(provider, &config.Bucket{CloudName: "bucket"}).(types.MultipartUploader)
+	ctx := context.Background()
+
+	id, err := bkt.BeginMultipart(types.BeginMultipartData{Ctx: ctx, Object: "key"})
+	if err != nil {
+		t.Fatal(err)
+	} else if id != "upload-1" {
+		t.Fatalf("got upload id %q, want upload-1", id)
+	}
+
+	part, err := bkt.UploadPart(types.UploadPartData{Ctx: ctx, Object: "key", UploadID: id,
+		PartNumber: 1, Data: strings.NewReader("hello"), Size: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := types.UploadedPart{PartNumber: 1, ETag: `"part-1"`, Size: 5}
+	if *part != want {
+		t.Errorf("got part %+v, want %+v", *part, want)
+	}
+
+	parts, err := bkt.ListParts(types.MultipartData{Ctx: ctx, Object: "key", UploadID: id})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(parts) != 1 || parts[0] != want {
+		t.Errorf("got parts %+v, want [%+v]", parts, want)
+	}
+
+	attrs, err := bkt.CompleteMultipart(types.CompleteMultipartData{Ctx: ctx, Object: "key", UploadID: id, Parts: parts})
+	if err != nil {
+		t.Fatal(err)
+	} else if attrs.Size != 5 {
+		t.Errorf("got size %d, want 5", attrs.Size)
+	}
+	if !strings.Contains(completed, `<PartNumber>1</PartNumber>`) {
+		t.Errorf("complete request doesn't list part 1: %s", completed)
+	}
+
+	err = bkt.AbortMultipart(types.MultipartData{Ctx: ctx, Object: "key", UploadID: "unknown"})
+	if !errors.Is(err, types.ErrUploadNotExist) {
+		t.Errorf("got error %v aborting an unknown upload, want ErrUploadNotExist", err)
+	}
+}
+
+func TestCopy_ReplaceAttrs(t *testing.T) {
+	exists := false
+	var copyHeaders http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "HEAD" && !exists:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == "HEAD":
+			w.Header().Set("Content-Length", "5")
+			w.Header().Set("ETag", `"etag"`)
+		case r.Method == "PUT" && r.Header.Get("X-Amz-Copy-Source") != "":
+			copyHeaders = r.Header.Clone()
+			exists = true
+			fmt.Fprint(w, `<CopyObjectResult><ETag>"etag"</ETag></CopyObjectResult>`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	provider := &config.BucketProvider{S3: &config.S3BucketProvider{
+		Endpoint:        aws.String(srv.URL),
+		PathStyle:       true,
+		AccessKeyID:     aws.String("key"),
+		SecretAccessKey: aws.String("secret"),
+	}}
+	mgr := NewManager(context.Background(), &config.Runtime{}, nil, zerolog.Nop())
+	bkt := mgr.NewBucket(provider, &config.Bucket{CloudName: "bucket"})
+	data := types.CopyData{
+		Ctx:             context.Background(),
+		Src:             bkt,
+		SrcObject:       "a.txt.gz",
+		Object:          "b.txt.gz",
+		Attrs:           &types.UploadAttrs{ContentType: "text/plain", UserMetadata: map[string]string{"k": "v"}},
+		ContentEncoding: "gzip",
+		Pre:             types.Preconditions{NotExists: true},
+	}
+
+	if _, err := bkt.(types.Copier).Copy(data); err != nil {
+		t.Fatal(err)
+	}
+	for header, want := range map[string]string{
+		"X-Amz-Copy-Source":        "bucket/a.txt.gz",
+		"X-Amz-Metadata-Directive": "REPLACE",
+		"X-Amz-Meta-K":             "v",
+		"Content-Type":             "text/plain",
+		"Content-Encoding":         "gzip",
+	} {
+		if got := copyHeaders.Get(header); got != want {
+			t.Errorf("got header %s %q, want %q", header, got, want)
+		}
+	}
+
+	// The destination now exists, failing the precondition.
+	if _, err := bkt.(types.Copier).Copy(data); !errors.Is(err, types.ErrPreconditionFailed) {
+		t.Errorf("got err %v, want ErrPreconditionFailed", err)
+	}
+}
+
+func TestDownloadRange(t *testing.T) {
+	var rangeHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rangeHeader = r.Header.Get("Range")
+		w.Header().Set("Content-Range", "bytes 2-4/10")
+		w.WriteHeader(http.StatusPartialContent)
+		fmt.Fprint(w, "cde")
+	}))
+	defer srv.Close()
+
+	provider := &config.BucketProvider{S3: &config.S3BucketProvider{
+		Endpoint:        aws.String(srv.URL),
+		PathStyle:       true,
+		AccessKeyID:     aws.String("key"),
+		SecretAccessKey: aws.String("secret"),
+	}}
+	mgr := NewManager(context.Background(), &config.Runtime{}, nil, zerolog.Nop())
+	bkt := mgr.NewBucket(provider, &config.Bucket{CloudName: "bucket"})
+
+	r, err := bkt.Download(types.DownloadData{
+		Ctx:    context.Background(),
+		Object: "key",
+		Range:  &types.ByteRange{Start: 2, End: 5},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = r.Close() }()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rangeHeader != "bytes=2-4" || string(data) != "cde" {
+		t.Errorf("got range %q and content %q, want bytes=2-4 and cde", rangeHeader, data)
+	}
+}
+
+func TestDownloadChecksums(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   types.Checksums
+	}{
+		{name: "full", header: "hqBywA==", want: types.Checksums{CRC32C: "hqBywA=="}},
+		{name: "multipart", header: "hqBywA==-3", want: types.Checksums{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var checksumMode string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				checksumMode = r.Header.Get("X-Amz-Checksum-Mode")
+				w.Header().Set("X-Amz-Checksum-Crc32c", tt.header)
+				fmt.Fprint(w, "test")
+			}))
+			defer srv.Close()
+
+			provider := &config.BucketProvider{S3: &config.S3BucketProvider{
+				Endpoint:        aws.String(srv.URL),
+				PathStyle:       true,
+				AccessKeyID:     aws.String("key"),
+				SecretAccessKey: aws.String("secret"),
+			}}
+			mgr := NewManager(context.Background(), &config.Runtime{}, nil, zerolog.Nop())
+			bkt := mgr.NewBucket(provider, &config.Bucket{CloudName: "bucket"})
+
+			r, err := bkt.Download(types.DownloadData{Ctx: context.Background(), Object: "key"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = r.Close() }()
+			if _, err := io.ReadAll(r); err != nil {
+				t.Fatal(err)
+			}
+			if checksumMode != "ENABLED" {
+				t.Errorf("got checksum mode %q, want ENABLED", checksumMode)
+			}
+			if got := r.(types.ChecksumReporter).Checksums(); got != tt.want {
+				t.Errorf("got checksums %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListPage(t *testing.T) {
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprint(w, `<ListBucketResult>
+			<Contents><Key>b/c.txt</Key><Size>1</Size><ETag>"c"</ETag></Contents>
+			<Contents><Key>b/f.txt</Key><Size>2</Size><ETag>"f"</ETag></Contents>
+			<CommonPrefixes><Prefix>b/d/</Prefix></CommonPrefixes>
+			<IsTruncated>true</IsTruncated>
+			<NextContinuationToken>next</NextContinuationToken>
+		</ListBucketResult>`)
+	}))
+	defer srv.Close()
+
+	provider := &config.BucketProvider{S3: &config.S3BucketProvider{
+		Endpoint:        aws.String(srv.URL),
+		PathStyle:       true,
+		AccessKeyID:     aws.String("key"),
+		SecretAccessKey: aws.String("secret"),
+	}}
+	mgr := NewManager(context.Background(), &config.Runtime{}, nil, zerolog.Nop())
+	bkt := mgr.NewBucket(provider, &config.Bucket{CloudName: "bucket"}).(*bucket)
+
+	page, err := bkt.ListPage(types.ListData{
+		Ctx:       context.Background(),
+		Prefix:    "b/",
+		Delimiter: "/",
+		PageSize:  3,
+		PageToken: "token",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := query.Get("delimiter") + " " + query.Get("max-keys") + " " + query.Get("continuation-token"); got != "/ 3 token" {
+		t.Errorf("got delimiter, max keys and token %q, want %q", got, "/ 3 token")
+	}
+
+	var names []string
+	for _, e := range page.Entries {
+		names = append(names, fmt.Sprintf("%s:%v", e.Object, e.Prefix))
+	}
+	if got, want := strings.Join(names, " "), "b/c.txt:false b/d/:true b/f.txt:false"; got != want {
+		t.Errorf("got entries %q, want %q", got, want)
+	}
+	if page.NextPageToken != "next" {
+		t.Errorf("got next page token %q, want next", page.NextPageToken)
+	}
+}
@@ -0,0 +1,120 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/rs/zerolog"
+
+	"encore.dev/appruntime/exported/config"
+)
+
+// assumeRoleExpiryWindow is how long before the assumed role's
+// credentials expire that they're refreshed.
+const assumeRoleExpiryWindow = 5 * time.Minute
+
+// assumeRoleCredentials returns a credentials provider that assumes the given role
+// using the base config's credentials, and refreshes the credentials before they expire.
+func assumeRoleCredentials(base aws.Config, region string, role *config.S3AssumeRole, logger zerolog.Logger) *aws.CredentialsCache {
+	stsClient := sts.NewFromConfig(base, func(o *sts.Options) {
+		if region != "" {
+			o.Region = region
+		}
+	})
+	p := stscreds.NewAssumeRoleProvider(stsClient, role.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+		if role.ExternalID != "" {
+			o.ExternalID = &role.ExternalID
+		}
+		if role.SessionName != "" {
+			o.RoleSessionName = role.SessionName
+		}
+	})
+	return aws.NewCredentialsCache(&loggingCredentials{
+		CredentialsProvider: p,
+		roleARN:             role.RoleARN,
+		logger:              logger,
+	}, func(o *aws.CredentialsCacheOptions) {
+		o.ExpiryWindow = assumeRoleExpiryWindow
+		o.ExpiryWindowJitterFrac = 0.5
+	})
+}
+
+// loggingCredentials logs each time the assumed role's credentials are retrieved.
+type loggingCredentials struct {
+	aws.CredentialsProvider
+	roleARN string
+	logger  zerolog.Logger
+}
+
+func (c *loggingCredentials) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	creds, err := c.CredentialsProvider.Retrieve(ctx)
+	if err != nil {
+		c.logger.Error().Err(err).Str("role_arn", c.roleARN).Msg("objects: failed to assume role")
+		return creds, fmt.Errorf("assume role %s: %w", c.roleARN, err)
+	}
+	c.logger.Info().Str("role_arn", c.roleARN).Time("expires", creds.Expires).Msg("objects: refreshed assumed role credentials")
+	return creds, nil
+}
+
+// CheckCredentials checks that the roles configured
+// for S3 providers can be assumed.
+func (mgr *Manager) CheckCredentials(ctx context.Context) error {
+	var errs []error
+	for _, prov := range mgr.runtime.BucketProviders {
+		if prov.S3 == nil || prov.S3.AssumeRole == nil {
+			continue
+		}
+		cs := mgr.clientForProvider(prov)
+		if _, err := cs.creds.Retrieve(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// refreshExpiredCredentials returns an API option that retries a request once,
+// with refreshed credentials, if it failed because the credentials have expired.
+func refreshExpiredCredentials(creds *aws.CredentialsCache) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		// Add it first in the finalize step, so the retried request
+		// resolves the credentials and is signed again.
+		return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("RefreshExpiredCredentials",
+			func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+				out, md, err := next.HandleFinalize(ctx, in)
+				if !isExpiredCredentials(err) {
+					return out, md, err
+				}
+
+				creds.Invalidate()
+				if req, ok := in.Request.(*smithyhttp.Request); ok {
+					if rerr := req.RewindStream(); rerr != nil {
+						return out, md, err
+					}
+				}
+				return next.HandleFinalize(ctx, in)
+			},
+		), middleware.Before)
+	}
+}
+
+// isExpiredCredentials reports whether err indicates
+// that the request was signed with expired credentials.
+func isExpiredCredentials(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "ExpiredToken", "ExpiredTokenException", "TokenRefreshRequired":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go/middleware"
+)
+
+func TestRefreshExpiredCredentials(t *testing.T) {
+	var retrieved atomic.Int32
+	creds := aws.NewCredentialsCache(aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		n := retrieved.Add(1)
+		return aws.Credentials{
+			AccessKeyID:     "key",
+			SecretAccessKey: "secret",
+			SessionToken:    fmt.Sprintf("token-%d", n),
+			CanExpire:       true,
+			Expires:         time.Now().Add(time.Hour),
+		}, nil
+	}))
+
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if r.Header.Get("X-Amz-Security-Token") == "token-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `<Error><Code>ExpiredToken</Code><Message>The provided token has expired.</Message></Error>`)
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+	}))
+	defer srv.Close()
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(srv.URL),
+		UsePathStyle: true,
+		Credentials:  creds,
+		APIOptions:   []func(*middleware.Stack) error{refreshExpiredCredentials(creds)},
+	})
+
+	_, err := client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("key"),
+		Body:   strings.NewReader("content"),
+	})
+	if err != nil {
+		t.Fatalf("got err %v, want the request to succeed after refreshing", err)
+	}
+	if got := retrieved.Load(); got != 2 {
+		t.Errorf("got %d credential retrievals, want 2", got)
+	}
+	if len(bodies) != 2 || bodies[1] != "content" {
+		t.Errorf("got request bodies %q, want the content to be sent again", bodies)
+	}
+}
+
+func TestRefreshExpiredCredentials_Once(t *testing.T) {
+	var retrieved atomic.Int32
+	creds := aws.NewCredentialsCache(aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		retrieved.Add(1)
+		return aws.Credentials{AccessKeyID: "key", SecretAccessKey: "secret", SessionToken: "token"}, nil
+	}))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `<Error><Code>ExpiredToken</Code><Message>The provided token has expired.</Message></Error>`)
+	}))
+	defer srv.Close()
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(srv.URL),
+		UsePathStyle: true,
+		Credentials:  creds,
+		APIOptions:   []func(*middleware.Stack) error{refreshExpiredCredentials(creds)},
+	})
+
+	_, err := client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("key"),
+	})
+	if !isExpiredCredentials(err) {
+		t.Fatalf("got err %v, want an expired credentials error", err)
+	}
+	if got := retrieved.Load(); got != 2 {
+		t.Errorf("got %d credential retrievals, want 2", got)
+	}
+}
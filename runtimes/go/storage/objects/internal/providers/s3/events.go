@@ -0,0 +1,143 @@
+package s3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+var _ types.EventSource = (*bucket)(nil)
+
+// receiveRetryDelay is how long to wait before receiving
+// from the event queue again after a failed attempt.
+const receiveRetryDelay = 5 * time.Second
+
+// SubscribeEvents receives the bucket's S3 event notifications
+// from the SQS queue they are delivered to.
+//
+// Messages are deleted from the queue once all their events have been delivered,
+// and are otherwise redelivered by SQS after the queue's visibility timeout.
+func (b *bucket) SubscribeEvents(ctx context.Context, deliver func(ctx context.Context, ev *types.ObjectEvent) error) error {
+	if b.cfg.Events == nil || b.cfg.Events.SQSQueueURL == "" {
+		return errors.New("objects: no event notification queue configured for bucket")
+	}
+	queueURL := b.cfg.Events.SQSQueueURL
+
+	for ctx.Err() == nil {
+		resp, err := b.sqsClient.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:            &queueURL,
+			MaxNumberOfMessages: 10,
+			WaitTimeSeconds:     20,
+		})
+		if err != nil {
+			select {
+			case <-ctx.Done():
+			case <-time.After(receiveRetryDelay):
+			}
+			continue
+		}
+
+		for _, msg := range resp.Messages {
+			// Messages that aren't event notifications are dropped,
+			// as redelivering them won't help.
+			events, _ := parseEvents(aws.ToString(msg.Body), b.cfg.CloudName)
+			if !deliverAll(ctx, events, deliver) {
+				continue
+			}
+
+			// Delete the message even if we're shutting down, since it's been delivered.
+			_, _ = b.sqsClient.DeleteMessage(context.WithoutCancel(ctx), &sqs.DeleteMessageInput{
+				QueueUrl:      &queueURL,
+				ReceiptHandle: msg.ReceiptHandle,
+			})
+		}
+	}
+	return ctx.Err()
+}
+
+// deliverAll delivers events in order, stopping at the first failure.
+// It reports whether all events were delivered.
+func deliverAll(ctx context.Context, events []*types.ObjectEvent, deliver func(ctx context.Context, ev *types.ObjectEvent) error) bool {
+	for _, ev := range events {
+		if err := deliver(ctx, ev); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// eventNotification is an S3 event notification.
+type eventNotification struct {
+	Records []struct {
+		EventName string `json:"eventName"`
+		S3        struct {
+			Bucket struct {
+				Name string `json:"name"`
+			} `json:"bucket"`
+			Object struct {
+				Key       string `json:"key"`
+				Size      int64  `json:"size"`
+				ETag      string `json:"eTag"`
+				VersionID string `json:"versionId"`
+			} `json:"object"`
+		} `json:"s3"`
+	} `json:"Records"`
+
+	// Type and Message are set when the notification
+	// is delivered through an SNS topic.
+	Type    string `json:"Type"`
+	Message string `json:"Message"`
+}
+
+// parseEvents parses the object events for the given bucket from an S3 event notification.
+// Objects expired by lifecycle rules are reported as deleted.
+// Events for other buckets, and other kinds of events like restores, are ignored.
+func parseEvents(body, bucketName string) ([]*types.ObjectEvent, error) {
+	var n eventNotification
+	if err := json.Unmarshal([]byte(body), &n); err != nil {
+		return nil, fmt.Errorf("objects: parse event notification: %w", err)
+	}
+	if n.Type == "Notification" && n.Message != "" {
+		return parseEvents(n.Message, bucketName)
+	}
+
+	var events []*types.ObjectEvent
+	for _, r := range n.Records {
+		var typ string
+		switch {
+		case strings.HasPrefix(r.EventName, "ObjectCreated:"):
+			typ = "object_created"
+		case strings.HasPrefix(r.EventName, "ObjectRemoved:"),
+			strings.HasPrefix(r.EventName, "LifecycleExpiration:"):
+			typ = "object_deleted"
+		default:
+			continue
+		}
+		if r.S3.Bucket.Name != bucketName {
+			continue
+		}
+
+		// Object keys are URL-encoded in event notifications.
+		key, err := url.QueryUnescape(r.S3.Object.Key)
+		if err != nil {
+			return nil, fmt.Errorf("objects: parse event notification: invalid key %q", r.S3.Object.Key)
+		}
+		events = append(events, &types.ObjectEvent{
+			Type:    typ,
+			Object:  types.CloudObject(key),
+			Version: r.S3.Object.VersionID,
+			Size:    r.S3.Object.Size,
+			ETag:    r.S3.Object.ETag,
+		})
+	}
+	return events, nil
+}
@@ -0,0 +1,74 @@
+package s3
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+func TestParseEvents(t *testing.T) {
+	const created = `{"Records":[{
+		"eventName": "ObjectCreated:Put",
+		"s3": {
+			"bucket": {"name": "photos"},
+			"object": {"key": "raw/my+photo%21.jpg", "size": 1024, "eTag": "abc", "versionId": "v1"}
+		}
+	}]}`
+	sns, _ := json.Marshal(map[string]string{"Type": "Notification", "Message": created})
+
+	tests := []struct {
+		name string
+		body string
+		want []*types.ObjectEvent
+	}{
+		{
+			name: "created",
+			body: created,
+			want: []*types.ObjectEvent{{Type: "object_created", Object: "raw/my photo!.jpg", Version: "v1", Size: 1024, ETag: "abc"}},
+		},
+		{
+			name: "removed",
+			body: `{"Records":[{"eventName":"ObjectRemoved:Delete","s3":{"bucket":{"name":"photos"},"object":{"key":"a.jpg"}}}]}`,
+			want: []*types.ObjectEvent{{Type: "object_deleted", Object: "a.jpg"}},
+		},
+		{
+			name: "expired",
+			body: `{"Records":[{"eventName":"LifecycleExpiration:Delete","s3":{"bucket":{"name":"photos"},"object":{"key":"tmp/a.jpg"}}}]}`,
+			want: []*types.ObjectEvent{{Type: "object_deleted", Object: "tmp/a.jpg"}},
+		},
+		{
+			name: "sns",
+			body: string(sns),
+			want: []*types.ObjectEvent{{Type: "object_created", Object: "raw/my photo!.jpg", Version: "v1", Size: 1024, ETag: "abc"}},
+		},
+		{
+			name: "other_bucket",
+			body: `{"Records":[{"eventName":"ObjectCreated:Put","s3":{"bucket":{"name":"other"},"object":{"key":"a.jpg"}}}]}`,
+		},
+		{
+			name: "other_event",
+			body: `{"Records":[{"eventName":"ObjectRestore:Completed","s3":{"bucket":{"name":"photos"},"object":{"key":"a.jpg"}}}]}`,
+		},
+		{
+			name: "test_event",
+			body: `{"Service":"Amazon S3","Event":"s3:TestEvent","Bucket":"photos"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEvents(tt.body, "photos")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got events %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parseEvents("not json", "photos"); err == nil {
+		t.Error("got no error parsing an invalid notification")
+	}
+}
@@ -0,0 +1,124 @@
+package s3
+
+import (
+	"fmt"
+	"io"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+var _ types.MultipartUploader = (*bucket)(nil)
+
+func (b *bucket) BeginMultipart(data types.BeginMultipartData) (string, error) {
+	if data.PartSize != 0 && (data.PartSize < minPartSize || data.PartSize > maxPartSize) {
+		return "", fmt.Errorf("%w: part size must be between %d and %d bytes",
+			types.ErrInvalidArgument, minPartSize, maxPartSize)
+	}
+	object := data.Object.String()
+	sse, kmsKey := sseKMS(data.Attrs)
+	resp, err := b.client.CreateMultipartUpload(data.Ctx, &s3.CreateMultipartUploadInput{
+		Bucket:               &b.cfg.CloudName,
+		Key:                  &object,
+		ContentType:          ptrOrNil(data.Attrs.ContentType),
+		Metadata:             data.Attrs.Metadata(),
+		Tagging:              tagging(data.Attrs.Tags),
+		ServerSideEncryption: sse,
+		SSEKMSKeyId:          kmsKey,
+	})
+	if err != nil {
+		return "", mapErr(err)
+	}
+	return valOrZero(resp.UploadId), nil
+}
+
+func (b *bucket) UploadPart(data types.UploadPartData) (*types.UploadedPart, error) {
+	if data.PartNumber > maxPartCount || data.Size > maxPartSize {
+		return nil, fmt.Errorf("%w: parts are limited to %d parts of %d bytes",
+			types.ErrInvalidArgument, maxPartCount, maxPartSize)
+	}
+
+	var optFns []func(*s3.Options)
+	if _, ok := data.Data.(io.Seeker); !ok {
+		// The payload can't be read twice to hash it before signing the request.
+		optFns = append(optFns, s3.WithAPIOptions(v4.SwapComputePayloadSHA256ForUnsignedPayloadMiddleware))
+	}
+
+	object := data.Object.String()
+	resp, err := b.client.UploadPart(data.Ctx, &s3.UploadPartInput{
+		Bucket:        &b.cfg.CloudName,
+		Key:           &object,
+		UploadId:      &data.UploadID,
+		PartNumber:    ptr(int32(data.PartNumber)),
+		Body:          data.Data,
+		ContentLength: &data.Size,
+	}, optFns...)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &types.UploadedPart{
+		PartNumber: data.PartNumber,
+		ETag:       valOrZero(resp.ETag),
+		Size:       data.Size,
+	}, nil
+}
+
+func (b *bucket) ListParts(data types.MultipartData) ([]types.UploadedPart, error) {
+	object := data.Object.String()
+	pages := s3.NewListPartsPaginator(b.client, &s3.ListPartsInput{
+		Bucket:   &b.cfg.CloudName,
+		Key:      &object,
+		UploadId: &data.UploadID,
+	})
+
+	var parts []types.UploadedPart
+	for pages.HasMorePages() {
+		resp, err := pages.NextPage(data.Ctx)
+		if err != nil {
+			return nil, mapErr(err)
+		}
+		for _, p := range resp.Parts {
+			parts = append(parts, types.UploadedPart{
+				PartNumber: int(valOrZero(p.PartNumber)),
+				ETag:       valOrZero(p.ETag),
+				Size:       valOrZero(p.Size),
+			})
+		}
+	}
+	return parts, nil
+}
+
+func (b *bucket) CompleteMultipart(data types.CompleteMultipartData) (*types.ObjectAttrs, error) {
+	completed := make([]s3types.CompletedPart, len(data.Parts))
+	for i, p := range data.Parts {
+		completed[i] = s3types.CompletedPart{
+			PartNumber: ptr(int32(p.PartNumber)),
+			ETag:       ptrOrNil(p.ETag),
+		}
+	}
+
+	object := data.Object.String()
+	resp, err := b.client.CompleteMultipartUpload(data.Ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          &b.cfg.CloudName,
+		Key:             &object,
+		UploadId:        &data.UploadID,
+		MultipartUpload: &s3types.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return b.Attrs(types.AttrsData{Ctx: data.Ctx, Object: data.Object, Version: valOrZero(resp.VersionId)})
+}
+
+func (b *bucket) AbortMultipart(data types.MultipartData) error {
+	object := data.Object.String()
+	_, err := b.client.AbortMultipartUpload(data.Ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   &b.cfg.CloudName,
+		Key:      &object,
+		UploadId: &data.UploadID,
+	})
+	return mapErr(err)
+}
@@ -6,11 +6,13 @@ import (
 	"crypto/md5"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
 	"encore.dev/storage/objects/internal/types"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -21,10 +23,11 @@ type uploader struct {
 	ctx    context.Context
 	out    chan uploadEvent
 
-	init  sync.Once
-	done  chan struct{}
-	attrs *types.ObjectAttrs
-	err   error
+	init     sync.Once
+	partSize int // size of each part; set on init
+	done     chan struct{}
+	attrs    *types.ObjectAttrs
+	err      error
 
 	curr *buffer
 }
@@ -56,7 +59,7 @@ func (u *uploader) Write(p []byte) (n int, err error) {
 	for len(p) > 0 {
 		curr := u.curr
 		if curr == nil {
-			curr = getBuf()
+			curr = getBuf(u.partSize)
 		}
 
 		copied := copy(curr.buf[curr.n:], p)
@@ -120,6 +123,7 @@ func (u *uploader) Abort(err error) {
 
 func (u *uploader) initUpload() {
 	u.init.Do(func() {
+		u.partSize = partSizeFor(u.data.Size, u.data.PartSize)
 		go func() {
 			defer close(u.done)
 			attrs, err := u.doUpload()
@@ -163,34 +167,52 @@ func (u *uploader) singlePartUpload(buf []byte) (*types.ObjectAttrs, error) {
 		ifNoneMatch = ptr("*")
 	}
 
+	sse, kmsKey := sseKMS(u.data.Attrs)
 	resp, err := u.client.PutObject(u.ctx, &s3.PutObjectInput{
-		Bucket:        &u.bucket,
-		Key:           key,
-		Body:          bytes.NewReader(buf),
-		ContentType:   ptrOrNil(u.data.Attrs.ContentType),
-		ContentMD5:    &contentMD5,
-		ContentLength: ptr(int64(len(buf))),
-		IfNoneMatch:   ifNoneMatch,
-	})
+		Bucket:               &u.bucket,
+		Key:                  key,
+		Body:                 bytes.NewReader(buf),
+		ContentType:          ptrOrNil(u.data.Attrs.ContentType),
+		ContentMD5:           &contentMD5,
+		ContentLength:        ptr(int64(len(buf))),
+		IfNoneMatch:          ifNoneMatch,
+		Metadata:             u.data.Attrs.Metadata(),
+		Tagging:              tagging(u.data.Attrs.Tags),
+		ServerSideEncryption: sse,
+		SSEKMSKeyId:          kmsKey,
+		ChecksumAlgorithm:    checksumAlgorithm(u.data.Checksum),
+	}, u.optFns()...)
 	if err != nil {
 		return nil, err
 	}
 
 	return &types.ObjectAttrs{
-		Object:      u.data.Object,
-		Version:     valOrZero(resp.VersionId),
-		ContentType: u.data.Attrs.ContentType,
-		Size:        int64(len(buf)),
-		ETag:        valOrZero(resp.ETag),
+		Object:         u.data.Object,
+		Version:        valOrZero(resp.VersionId),
+		ContentType:    u.data.Attrs.ContentType,
+		Size:           int64(len(buf)),
+		ETag:           valOrZero(resp.ETag),
+		IdempotencyKey: u.data.Attrs.IdempotencyKey,
+		UserMetadata:   u.data.Attrs.UserMetadata,
+		Tags:           u.data.Attrs.Tags,
+		Checksums: types.Checksums{
+			CRC32C: valOrZero(resp.ChecksumCRC32C),
+			SHA256: valOrZero(resp.ChecksumSHA256),
+		},
 	}, nil
 }
 
 func (u *uploader) multiPartUpload(initial *buffer) (attrs *types.ObjectAttrs, err error) {
 	key := ptr(u.data.Object.String())
+	sse, kmsKey := sseKMS(u.data.Attrs)
 	resp, err := u.client.CreateMultipartUpload(u.ctx, &s3.CreateMultipartUploadInput{
-		Bucket:      &u.bucket,
-		Key:         key,
-		ContentType: ptrOrNil(u.data.Attrs.ContentType),
+		Bucket:               &u.bucket,
+		Key:                  key,
+		ContentType:          ptrOrNil(u.data.Attrs.ContentType),
+		Metadata:             u.data.Attrs.Metadata(),
+		Tagging:              tagging(u.data.Attrs.Tags),
+		ServerSideEncryption: sse,
+		SSEKMSKeyId:          kmsKey,
 	})
 	if err != nil {
 		return nil, err
@@ -251,6 +273,10 @@ func (u *uploader) multiPartUpload(initial *buffer) (attrs *types.ObjectAttrs, e
 		}
 
 		if ev.data != nil {
+			if partNumber > maxPartCount {
+				putBuf(ev.data)
+				return nil, fmt.Errorf("object exceeds the maximum of %d parts of %d bytes each, use a larger part size", maxPartCount, u.partSize)
+			}
 			uploadPart(ev.data)
 		}
 
@@ -276,20 +302,64 @@ func (u *uploader) multiPartUpload(initial *buffer) (attrs *types.ObjectAttrs, e
 		Key:         key,
 		UploadId:    &uploadID,
 		IfNoneMatch: ifNoneMatch,
-	})
+	}, u.optFns()...)
 	if err != nil {
 		return nil, err
 	}
 	return &types.ObjectAttrs{
-		Object:      u.data.Object,
-		Version:     valOrZero(completeResp.VersionId),
-		ContentType: u.data.Attrs.ContentType,
-		Size:        totalSize,
-		ETag:        valOrZero(completeResp.ETag),
+		Object:         u.data.Object,
+		Version:        valOrZero(completeResp.VersionId),
+		ContentType:    u.data.Attrs.ContentType,
+		Size:           totalSize,
+		ETag:           valOrZero(completeResp.ETag),
+		IdempotencyKey: u.data.Attrs.IdempotencyKey,
+		UserMetadata:   u.data.Attrs.UserMetadata,
+		Tags:           u.data.Attrs.Tags,
 	}, nil
 }
 
-// bufSize is the size of buffers allocated by bufPool.
+// optFns returns the options to use for the request that creates the object.
+func (u *uploader) optFns() []func(*s3.Options) {
+	if etag := u.data.Pre.MatchETag; etag != "" {
+		// The SDK doesn't support If-Match for uploads yet, so set the header directly.
+		return []func(*s3.Options){func(o *s3.Options) {
+			o.APIOptions = append(o.APIOptions, smithyhttp.SetHeaderValue("If-Match", etag))
+		}}
+	}
+	return nil
+}
+
+// Multipart upload limits imposed by S3.
+const (
+	minPartSize  = 5 * 1024 * 1024
+	maxPartSize  = 5 * 1024 * 1024 * 1024
+	maxPartCount = 10000
+)
+
+// partSizeFor computes the part size to use for an upload.
+//
+// It defaults to bufSize, but is increased if needed to fit an object
+// of the given total size (if known) within maxPartCount parts.
+// An explicit override is clamped to the part size limits.
+func partSizeFor(totalSize, override int64) int {
+	size := int64(bufSize)
+	if override > 0 {
+		size = min(max(override, minPartSize), maxPartSize)
+	}
+
+	if totalSize > 0 {
+		// Round up to the nearest MiB to keep part sizes tidy.
+		const mib = 1024 * 1024
+		needed := (totalSize + maxPartCount - 1) / maxPartCount
+		if needed > size {
+			size = min((needed+mib-1)/mib*mib, maxPartSize)
+		}
+	}
+	return int(size)
+}
+
+// bufSize is the size of buffers allocated by bufPool,
+// and the default part size for multipart uploads.
 // It's a variable for testing purposes.
 var bufSize = 10 * 1024 * 1024
 
@@ -301,12 +371,19 @@ var bufPool = sync.Pool{
 	},
 }
 
-func getBuf() *buffer {
+// getBuf returns a buffer of the given size.
+// Buffers of the default size are pooled.
+func getBuf(size int) *buffer {
+	if size != bufSize {
+		return &buffer{buf: make([]byte, size)}
+	}
 	buf := bufPool.Get().(*buffer)
 	buf.n = 0
 	return buf
 }
 
 func putBuf(buf *buffer) {
-	bufPool.Put(buf)
+	if len(buf.buf) == bufSize {
+		bufPool.Put(buf)
+	}
 }
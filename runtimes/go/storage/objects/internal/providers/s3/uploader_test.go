@@ -10,6 +10,7 @@ import (
 
 	"encore.dev/storage/objects/internal/types"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	qt "github.com/frankban/quicktest"
 	"github.com/golang/mock/gomock"
 )
@@ -194,3 +195,176 @@ func (m *partMatcher) Matches(x interface{}) bool {
 func (m *partMatcher) String() string {
 	return fmt.Sprintf("is part %d with data %q", m.num, m.data)
 }
+
+func TestPartSizeFor(t *testing.T) {
+	const mib = 1024 * 1024
+	def := int64(bufSize)
+	tests := []struct {
+		name     string
+		total    int64
+		override int64
+		want     int
+	}{
+		{name: "unknown_size", want: bufSize},
+		{name: "small", total: 3 * mib, want: bufSize},
+		{name: "exactly_max_parts", total: maxPartCount * def, want: bufSize},
+		{name: "one_byte_over_max_parts", total: maxPartCount*def + 1, want: bufSize + mib},
+		{name: "huge", total: maxPartCount * maxPartSize * 2, want: maxPartSize},
+		{name: "override", override: 64 * mib, want: 64 * mib},
+		{name: "override_below_min", override: 1024, want: minPartSize},
+		{name: "override_above_max", override: maxPartSize + 1, want: maxPartSize},
+		{name: "override_at_max_parts", total: maxPartCount * 6 * mib, override: 6 * mib, want: 6 * mib},
+		{name: "override_too_small_for_size", total: maxPartCount*6*mib + 1, override: 6 * mib, want: 7 * mib},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			got := partSizeFor(tt.total, tt.override)
+			c.Assert(got, qt.Equals, tt.want)
+			if tt.total > 0 && got < maxPartSize {
+				parts := (tt.total + int64(got) - 1) / int64(got)
+				c.Assert(parts <= maxPartCount, qt.IsTrue, qt.Commentf("%d parts", parts))
+			}
+		})
+	}
+}
+
+func TestUploader_PartSize(t *testing.T) {
+	c := qt.New(t)
+
+	ctrl := gomock.NewController(c)
+	client := NewMocks3Client(ctrl)
+
+	withBufSize(c, 4)
+	u := newUploader(client, "bucket", types.UploadData{
+		Ctx:    context.Background(),
+		Object: "object",
+		// The override is clamped to the minimum part size,
+		// so the object is uploaded in a single part.
+		PartSize: 8,
+	})
+
+	client.EXPECT().PutObject(gomock.Any(), gomock.Any()).Return(&s3.PutObjectOutput{}, nil)
+
+	n, err := u.Write([]byte("abcdefghij"))
+	c.Assert(n, qt.Equals, 10)
+	c.Assert(err, qt.Equals, nil)
+
+	_, err = u.Complete()
+	c.Assert(err, qt.Equals, nil)
+	c.Assert(u.partSize, qt.Equals, minPartSize)
+}
+
+func TestUploader_IdempotencyKey(t *testing.T) {
+	c := qt.New(t)
+
+	ctrl := gomock.NewController(c)
+	client := NewMocks3Client(ctrl)
+
+	u := newUploader(client, "bucket", types.UploadData{
+		Ctx:    context.Background(),
+		Object: "object",
+		Attrs: types.UploadAttrs{
+			IdempotencyKey: "req-1",
+		},
+	})
+
+	client.EXPECT().PutObject(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+			c.Assert(params.Metadata, qt.DeepEquals, map[string]string{types.IdempotencyKeyMetadata: "req-1"})
+			return &s3.PutObjectOutput{}, nil
+		})
+
+	_, err := u.Write([]byte("test"))
+	c.Assert(err, qt.IsNil)
+	attrs, err := u.Complete()
+	c.Assert(err, qt.IsNil)
+	c.Assert(attrs.IdempotencyKey, qt.Equals, "req-1")
+}
+
+func TestUploader_MetadataAndTags(t *testing.T) {
+	c := qt.New(t)
+
+	ctrl := gomock.NewController(c)
+	client := NewMocks3Client(ctrl)
+
+	u := newUploader(client, "bucket", types.UploadData{
+		Ctx:    context.Background(),
+		Object: "object",
+		Attrs: types.UploadAttrs{
+			UserMetadata: map[string]string{"owner": "alice"},
+			Tags:         map[string]string{"env": "test", "team": "a&b"},
+		},
+	})
+
+	client.EXPECT().PutObject(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+			c.Assert(params.Metadata, qt.DeepEquals, map[string]string{
+				"owner":                          "alice",
+				types.TagMetadataPrefix + "env":  "test",
+				types.TagMetadataPrefix + "team": "a&b",
+			})
+			c.Assert(params.Tagging, qt.DeepEquals, ptr("env=test&team=a%26b"))
+			return &s3.PutObjectOutput{}, nil
+		})
+
+	_, err := u.Write([]byte("test"))
+	c.Assert(err, qt.IsNil)
+	attrs, err := u.Complete()
+	c.Assert(err, qt.IsNil)
+	c.Assert(attrs.UserMetadata, qt.DeepEquals, map[string]string{"owner": "alice"})
+	c.Assert(attrs.Tags, qt.DeepEquals, map[string]string{"env": "test", "team": "a&b"})
+}
+
+func TestUploader_KMSKey(t *testing.T) {
+	c := qt.New(t)
+
+	ctrl := gomock.NewController(c)
+	client := NewMocks3Client(ctrl)
+
+	const keyARN = "arn:aws:kms:us-east-1:123456789012:key/pii"
+	u := newUploader(client, "bucket", types.UploadData{
+		Ctx:    context.Background(),
+		Object: "object",
+		Attrs: types.UploadAttrs{
+			KMSKey: keyARN,
+		},
+	})
+
+	client.EXPECT().PutObject(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+			c.Assert(params.ServerSideEncryption, qt.Equals, s3types.ServerSideEncryptionAwsKms)
+			c.Assert(params.SSEKMSKeyId, qt.DeepEquals, ptr(keyARN))
+			return &s3.PutObjectOutput{}, nil
+		})
+
+	_, err := u.Write([]byte("test"))
+	c.Assert(err, qt.IsNil)
+	_, err = u.Complete()
+	c.Assert(err, qt.IsNil)
+}
+
+func TestUploader_Checksum(t *testing.T) {
+	c := qt.New(t)
+
+	ctrl := gomock.NewController(c)
+	client := NewMocks3Client(ctrl)
+
+	u := newUploader(client, "bucket", types.UploadData{
+		Ctx:      context.Background(),
+		Object:   "object",
+		Checksum: types.ChecksumSHA256,
+	})
+
+	client.EXPECT().PutObject(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+			c.Assert(params.ChecksumAlgorithm, qt.Equals, s3types.ChecksumAlgorithmSha256)
+			return &s3.PutObjectOutput{ChecksumSHA256: ptr("n4bQgYhMfWWaL+qgxVrQFaO/TxsrC4Is0V1sFbDwCgg=")}, nil
+		})
+
+	_, err := u.Write([]byte("test"))
+	c.Assert(err, qt.IsNil)
+	attrs, err := u.Complete()
+	c.Assert(err, qt.IsNil)
+	c.Assert(attrs.Checksums, qt.Equals, types.Checksums{SHA256: "n4bQgYhMfWWaL+qgxVrQFaO/TxsrC4Is0V1sFbDwCgg="})
+}
@@ -0,0 +1,102 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Stats accumulates the requests made, and the bytes transferred,
+// by the requests whose context carries it. See WithStats.
+//
+// It's safe for concurrent use, since multipart transfers
+// make requests concurrently.
+type Stats struct {
+	Requests      atomic.Int64
+	Retries       atomic.Int64
+	BytesSent     atomic.Int64
+	BytesReceived atomic.Int64
+}
+
+type statsKey struct{}
+
+// WithStats returns a context that records the requests made with it in s,
+// if they're made through a transport returned by Count.
+func WithStats(ctx context.Context, s *Stats) context.Context {
+	return context.WithValue(ctx, statsKey{}, s)
+}
+
+// StatsFrom returns the Stats carried by ctx, or nil if there are none.
+func StatsFrom(ctx context.Context) *Stats {
+	s, _ := ctx.Value(statsKey{}).(*Stats)
+	return s
+}
+
+// Count returns a transport that records the requests made through base
+// in the Stats carried by their context, if any. Only the bodies of
+// requests and responses are counted as bytes transferred.
+func Count(base http.RoundTripper) http.RoundTripper {
+	return &countingTransport{base: base}
+}
+
+type countingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s := StatsFrom(req.Context())
+	if s == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	s.Requests.Add(1)
+	if attempt(req) > 1 {
+		s.Retries.Add(1)
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		// Don't modify the caller's request.
+		req = req.WithContext(req.Context())
+		req.Body = &countingBody{ReadCloser: req.Body, n: &s.BytesSent}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if resp != nil && resp.Body != nil {
+		resp.Body = &countingBody{ReadCloser: resp.Body, n: &s.BytesReceived}
+	}
+	return resp, err
+}
+
+// attempt returns the attempt number the provider's SDK sent with req,
+// or 0 if it's unknown.
+func attempt(req *http.Request) int {
+	// The AWS SDK sends "amz-sdk-request: attempt=1; max=3".
+	for _, part := range strings.Split(req.Header.Get("Amz-Sdk-Request"), ";") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(part), "attempt="); ok {
+			n, _ := strconv.Atoi(v)
+			return n
+		}
+	}
+	// The GCS client sends "gccl-attempt-count/1" in x-goog-api-client.
+	for _, field := range strings.Fields(req.Header.Get("X-Goog-Api-Client")) {
+		if v, ok := strings.CutPrefix(field, "gccl-attempt-count/"); ok {
+			n, _ := strconv.Atoi(v)
+			return n
+		}
+	}
+	return 0
+}
+
+// countingBody counts the bytes read from a request or response body.
+type countingBody struct {
+	io.ReadCloser
+	n *atomic.Int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n.Add(int64(n))
+	return n, err
+}
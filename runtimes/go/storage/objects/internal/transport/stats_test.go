@@ -0,0 +1,62 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCount(t *testing.T) {
+	tr := Count(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		_, _ = io.Copy(io.Discard, req.Body)
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader("response"))}, nil
+	}))
+
+	var s Stats
+	ctx := WithStats(context.Background(), &s)
+	for _, header := range []string{"attempt=1; max=3", "attempt=2; max=3"} {
+		req, _ := http.NewRequestWithContext(ctx, "PUT", "http://example.com", strings.NewReader("request"))
+		req.Header.Set("Amz-Sdk-Request", header)
+		resp, err := tr.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		if _, ok := req.Body.(*countingBody); ok {
+			t.Error("got the caller's request body replaced")
+		}
+	}
+
+	if got := s.Requests.Load(); got != 2 {
+		t.Errorf("got %d requests, want 2", got)
+	}
+	if got := s.Retries.Load(); got != 1 {
+		t.Errorf("got %d retries, want 1", got)
+	}
+	if got, want := s.BytesSent.Load(), int64(2*len("request")); got != want {
+		t.Errorf("got %d bytes sent, want %d", got, want)
+	}
+	if got, want := s.BytesReceived.Load(), int64(2*len("response")); got != want {
+		t.Errorf("got %d bytes received, want %d", got, want)
+	}
+}
+
+func TestAttempt(t *testing.T) {
+	tests := []struct {
+		header, value string
+		want          int
+	}{
+		{"Amz-Sdk-Request", "attempt=3; max=3", 3},
+		{"X-Goog-Api-Client", "gccl-invocation-id/abc gccl-attempt-count/2 gl-go/1.23", 2},
+		{"X-Goog-Api-Client", "gl-go/1.23", 0},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest("GET", "http://example.com", nil)
+		req.Header.Set(tt.header, tt.value)
+		if got := attempt(req); got != tt.want {
+			t.Errorf("attempt(%s: %s) = %d, want %d", tt.header, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Package transport provides the HTTP transport shared by object storage providers.
+package transport
+
+import (
+	"net/http"
+
+	"encore.dev/appruntime/exported/config"
+)
+
+// New returns the HTTP transport to use for object storage providers,
+// configured with the given settings.
+//
+// It returns nil if settings is nil, in which case providers should
+// use their default transport.
+func New(settings *config.ObjectStorageSettings) http.RoundTripper {
+	if settings == nil {
+		return nil
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if settings.MaxIdleConns > 0 {
+		t.MaxIdleConns = settings.MaxIdleConns
+	}
+	if settings.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = settings.MaxIdleConnsPerHost
+	}
+	if settings.MaxConnsPerHost > 0 {
+		t.MaxConnsPerHost = settings.MaxConnsPerHost
+	}
+	if settings.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = settings.IdleConnTimeout
+	}
+
+	if n := settings.MaxConcurrentRequests; n > 0 {
+		return &limitedTransport{base: t, sem: make(chan struct{}, n)}
+	}
+	return t
+}
+
+// limitedTransport limits the number of concurrent requests.
+//
+// Requests beyond the limit wait for a slot until their context is done.
+// A request holds its slot until the response headers have been received,
+// so that open response bodies (like object downloads being read)
+// don't hold slots indefinitely.
+type limitedTransport struct {
+	base http.RoundTripper
+	sem  chan struct{}
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.sem <- struct{}{}:
+	case <-req.Context().Done():
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, req.Context().Err()
+	}
+	defer func() { <-t.sem }()
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,85 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"encore.dev/appruntime/exported/config"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestNew_Defaults(t *testing.T) {
+	if got := New(nil); got != nil {
+		t.Fatalf("New(nil) = %v, want nil", got)
+	}
+
+	tr, ok := New(&config.ObjectStorageSettings{MaxConnsPerHost: 7}).(*http.Transport)
+	if !ok {
+		t.Fatalf("want *http.Transport without a concurrency limit")
+	}
+	if tr.MaxConnsPerHost != 7 {
+		t.Errorf("MaxConnsPerHost = %d, want 7", tr.MaxConnsPerHost)
+	}
+}
+
+func TestLimitedTransport_Queues(t *testing.T) {
+	const limit = 2
+	var active, peak atomic.Int32
+	lt := &limitedTransport{
+		sem: make(chan struct{}, limit),
+		base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			n := active.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			active.Add(-1)
+			return &http.Response{StatusCode: 200}, nil
+		}),
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest("GET", "http://example.com", nil)
+			if _, err := lt.RoundTrip(req); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got > limit {
+		t.Errorf("peak concurrency = %d, want <= %d", got, limit)
+	}
+}
+
+func TestLimitedTransport_ContextDeadline(t *testing.T) {
+	lt := &limitedTransport{
+		sem: make(chan struct{}, 1),
+		base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: 200}, nil
+		}),
+	}
+	lt.sem <- struct{}{} // occupy the only slot
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "http://example.com", nil)
+	if _, err := lt.RoundTrip(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got err %v, want context.DeadlineExceeded", err)
+	}
+}
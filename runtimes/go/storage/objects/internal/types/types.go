@@ -5,6 +5,7 @@ import (
 	"errors"
 	"io"
 	"iter"
+	"strings"
 	"time"
 )
 
@@ -30,14 +31,162 @@ type UploadData struct {
 
 	Attrs UploadAttrs
 	Pre   Preconditions
+
+	// Size is the total size of the object, if known in advance.
+	// It's zero if unknown.
+	Size int64
+
+	// PartSize is the part size to use for multipart uploads.
+	// It's zero to let the provider decide.
+	PartSize int64
+
+	// Checksum is the algorithm to have the provider checksum the object with,
+	// so it's reported in the attributes returned by Complete.
+	Checksum ChecksumAlgorithm
 }
 
 type Preconditions struct {
 	NotExists bool
+
+	// MatchETag and MatchVersion, if set, require the object to currently
+	// have the given ETag or version. Providers check whichever they support:
+	// S3 checks the ETag and GCS checks the version (generation).
+	MatchETag    string
+	MatchVersion string
 }
 
 type UploadAttrs struct {
 	ContentType string
+
+	// UserMetadata is user-defined metadata to store with the object.
+	UserMetadata map[string]string
+
+	// Tags are user-defined tags to set on the object. They're stored with
+	// the object under TagMetadataPrefix, and set as object tags by providers
+	// that support them.
+	Tags map[string]string
+
+	// IdempotencyKey, if set, is stored with the object under IdempotencyKeyMetadata.
+	IdempotencyKey string
+
+	// Encryption, if set, describes the client-side encryption of the object.
+	// It's stored with the object under the encryption metadata keys.
+	Encryption *Encryption
+
+	// Trash, if set, describes the object the uploaded object is the trashed copy of.
+	// It's stored with the object under the trash metadata keys.
+	Trash *Trash
+
+	// KMSKey, if set, is the provider's ID of the KMS key to encrypt the object
+	// with server-side (SSE-KMS on S3, a customer-managed key on GCS).
+	KMSKey string
+}
+
+// Metadata returns the object metadata to store for the attributes.
+func (a UploadAttrs) Metadata() map[string]string {
+	var md map[string]string
+	set := func(k, v string) {
+		if md == nil {
+			md = make(map[string]string)
+		}
+		md[k] = v
+	}
+	for k, v := range a.UserMetadata {
+		set(k, v)
+	}
+	for k, v := range a.Tags {
+		set(TagMetadataPrefix+k, v)
+	}
+	if a.IdempotencyKey != "" {
+		set(IdempotencyKeyMetadata, a.IdempotencyKey)
+	}
+	if a.Encryption != nil {
+		set(EncryptionKeyIDMetadata, a.Encryption.KeyID)
+		set(EncryptionWrappedKeyMetadata, a.Encryption.WrappedKey)
+	}
+	if a.Trash != nil {
+		set(TrashedObjectMetadata, string(a.Trash.Object))
+		set(TrashedAtMetadata, a.Trash.At.UTC().Format(time.RFC3339Nano))
+	}
+	return md
+}
+
+// Object metadata keys that attributes are stored under.
+const (
+	IdempotencyKeyMetadata       = "encore-idempotency-key"
+	EncryptionKeyIDMetadata      = "encore-encryption-key-id"
+	EncryptionWrappedKeyMetadata = "encore-encryption-wrapped-key"
+	TrashedObjectMetadata        = "encore-trashed-object"
+	TrashedAtMetadata            = "encore-trashed-at"
+
+	// TagMetadataPrefix is the prefix tags are stored under.
+	TagMetadataPrefix = "encore-tag-"
+
+	// ReservedMetadataPrefix is the prefix of all metadata keys used by Encore.
+	ReservedMetadataPrefix = "encore-"
+)
+
+// UserMetadataFrom returns the user-defined metadata in the given object metadata,
+// or nil if there is none.
+func UserMetadataFrom(md map[string]string) map[string]string {
+	var res map[string]string
+	for k, v := range md {
+		if !strings.HasPrefix(strings.ToLower(k), ReservedMetadataPrefix) {
+			if res == nil {
+				res = make(map[string]string)
+			}
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// TagsFromMetadata returns the tags stored in the given object metadata,
+// or nil if there are none.
+func TagsFromMetadata(md map[string]string) map[string]string {
+	var res map[string]string
+	for k, v := range md {
+		if len(k) > len(TagMetadataPrefix) && strings.EqualFold(k[:len(TagMetadataPrefix)], TagMetadataPrefix) {
+			if res == nil {
+				res = make(map[string]string)
+			}
+			res[k[len(TagMetadataPrefix):]] = v
+		}
+	}
+	return res
+}
+
+// Encryption describes the client-side encryption of an object.
+type Encryption struct {
+	KeyID      string // the ID of the master key the data key is wrapped with
+	WrappedKey string // the wrapped data key, base64-encoded
+}
+
+// EncryptionFromMetadata returns the encryption stored in the given object metadata,
+// or nil if the object is not encrypted.
+func EncryptionFromMetadata(md map[string]string) *Encryption {
+	id, key := md[EncryptionKeyIDMetadata], md[EncryptionWrappedKeyMetadata]
+	if id == "" && key == "" {
+		return nil
+	}
+	return &Encryption{KeyID: id, WrappedKey: key}
+}
+
+// Trash describes the object that a trashed object was removed as.
+type Trash struct {
+	Object CloudObject // the cloud name of the removed object
+	At     time.Time   // when it was removed
+}
+
+// TrashFromMetadata returns the trash information stored in the given
+// object metadata, or nil if the object is not in the trash.
+func TrashFromMetadata(md map[string]string) *Trash {
+	obj := md[TrashedObjectMetadata]
+	if obj == "" {
+		return nil
+	}
+	at, _ := time.Parse(time.RFC3339Nano, md[TrashedAtMetadata])
+	return &Trash{Object: CloudObject(obj), At: at}
 }
 
 type Uploader interface {
@@ -52,31 +201,165 @@ type DownloadData struct {
 
 	// Non-zero to download a specific version
 	Version string
+
+	// Raw, if true, requests the content exactly as stored,
+	// without the provider decoding it based on its content encoding.
+	Raw bool
+
+	// Range, if set, limits the download to a range of the object's content as stored.
+	Range *ByteRange
+}
+
+// ByteRange is the range of bytes [Start, End) of an object.
+type ByteRange struct {
+	Start, End int64
+}
+
+// CopyData describes a server-side copy of an object from another bucket.
+type CopyData struct {
+	Ctx context.Context
+
+	// Src is the bucket to copy from, and SrcObject the object in it.
+	Src       BucketImpl
+	SrcObject CloudObject
+
+	// Object is the object to copy to.
+	Object CloudObject
+
+	// Attrs, if set, replaces the attributes of the copy instead
+	// of copying them from the source object, and ContentEncoding
+	// is the content encoding to keep for the copy.
+	Attrs           *UploadAttrs
+	ContentEncoding string
+
+	// Pre are the preconditions on the object to copy to.
+	Pre Preconditions
+}
+
+// Copier is implemented by bucket implementations
+// that can copy objects from other buckets server-side.
+type Copier interface {
+	// Copy copies an object server-side. It returns ErrCopyUnsupported
+	// if it can't copy from data.Src, such as if it belongs to another provider.
+	Copy(data CopyData) (*ObjectAttrs, error)
+}
+
+// ErrCopyUnsupported is returned by Copier.Copy when the object
+// can't be copied server-side, in which case it must be streamed.
+var ErrCopyUnsupported = errors.New("objects: server-side copy not supported")
+
+// Pager is implemented by bucket implementations
+// that can list objects a page at a time.
+type Pager interface {
+	// ListPage lists the page of objects starting at data.PageToken,
+	// with up to data.PageSize entries.
+	ListPage(data ListData) (*ListPage, error)
+}
+
+type ListPage struct {
+	Entries []*ListEntry // in lexicographic order
+
+	// NextPageToken is the token of the next page, or "" if this is the last page.
+	NextPageToken string
 }
 
 type Downloader interface {
 	io.Reader
 	io.Closer
+
+	// ContentEncoding reports the content encoding of the data being read,
+	// or "" if the data is not encoded.
+	ContentEncoding() string
+}
+
+// ChecksumReporter is implemented by downloaders that know the checksums
+// of the object being downloaded, for verifying its content.
+type ChecksumReporter interface {
+	// Checksums returns the checksums the provider has for the object.
+	// They're only set when the whole object is downloaded as stored.
+	Checksums() Checksums
+}
+
+// ChecksumAlgorithm is an algorithm for checksumming an object's content.
+type ChecksumAlgorithm string
+
+const (
+	ChecksumCRC32C ChecksumAlgorithm = "crc32c"
+	ChecksumSHA256 ChecksumAlgorithm = "sha256"
+)
+
+// Checksums are the checksums of an object's content as stored, as reported
+// by the provider. Each is the base64-encoded big-endian digest, or "" if unknown.
+type Checksums struct {
+	CRC32C string
+	SHA256 string
+}
+
+// Get returns the checksum for the given algorithm, or "" if unknown.
+func (c Checksums) Get(alg ChecksumAlgorithm) string {
+	switch alg {
+	case ChecksumCRC32C:
+		return c.CRC32C
+	case ChecksumSHA256:
+		return c.SHA256
+	default:
+		return ""
+	}
 }
 
 type ObjectAttrs struct {
-	Object      CloudObject
-	Version     string
-	ContentType string
-	Size        int64
-	ETag        string
+	Object          CloudObject
+	Version         string
+	ContentType     string
+	ContentEncoding string
+	Size            int64
+	ETag            string
+	IdempotencyKey  string      // the idempotency key the object was uploaded with, if any
+	Encryption      *Encryption // the client-side encryption of the object, if any
+	Trash           *Trash      // set for objects in the trash
+
+	UserMetadata map[string]string // the user-defined metadata, if any
+	Tags         map[string]string // the user-defined tags, if any
+
+	Checksums Checksums // the checksums of the content as stored, if known
+
+	// ServerSideEncryption is the server-side encryption of the object
+	// as reported by the provider, such as "aws:kms" on S3, if any.
+	ServerSideEncryption string
 }
 
 type ListData struct {
 	Ctx    context.Context
 	Prefix string
 	Limit  *int64
+
+	// Delimiter, if set, groups objects whose names contain it after the prefix
+	// into a single entry for their common prefix, up to and including the delimiter.
+	Delimiter string
+
+	// PageSize is the number of entries to request per page.
+	// It's zero to let the provider decide.
+	PageSize int64
+
+	// PageToken is the token of the page to list, from a previous ListPage.
+	// It's only used by Pager.ListPage.
+	PageToken string
 }
 
 type ListEntry struct {
-	Object CloudObject
-	Size   int64
-	ETag   string
+	Object       CloudObject
+	Size         int64
+	ETag         string
+	LastModified time.Time // zero if unknown
+
+	// Prefix is set if the entry is a common prefix of objects when
+	// listing with a delimiter, in which case Object is the prefix.
+	Prefix bool
+
+	// UserMetadata and Tags are the user-defined metadata and tags,
+	// if the provider includes them in listings.
+	UserMetadata map[string]string
+	Tags         map[string]string
 }
 
 type RemoveData struct {
@@ -107,6 +390,85 @@ type DownloadURLData struct {
 	TTL time.Duration
 }
 
+// PublicBaseURLProvider is implemented by bucket implementations that can
+// determine the public base URL of a bucket when it's not explicitly configured.
+type PublicBaseURLProvider interface {
+	// DefaultPublicBaseURL returns the public base URL of the bucket,
+	// or "" if it's not known.
+	DefaultPublicBaseURL() string
+}
+
+// EventSource is implemented by bucket implementations
+// that can deliver object change notifications.
+type EventSource interface {
+	// SubscribeEvents delivers object events to deliver until ctx is canceled.
+	// If deliver returns an error the event should be redelivered later.
+	SubscribeEvents(ctx context.Context, deliver func(ctx context.Context, ev *ObjectEvent) error) error
+}
+
+// MultipartUploader is implemented by bucket implementations that support
+// uploading an object in separately uploaded parts, which can be resumed
+// across processes using the upload ID.
+type MultipartUploader interface {
+	// BeginMultipart starts a multipart upload and returns its ID.
+	BeginMultipart(data BeginMultipartData) (uploadID string, err error)
+	// UploadPart uploads a single part of a multipart upload.
+	UploadPart(data UploadPartData) (*UploadedPart, error)
+	// ListParts lists the parts uploaded so far, ordered by part number.
+	ListParts(data MultipartData) ([]UploadedPart, error)
+	// CompleteMultipart assembles the given parts into the object.
+	CompleteMultipart(data CompleteMultipartData) (*ObjectAttrs, error)
+	// AbortMultipart aborts a multipart upload, discarding any uploaded parts.
+	AbortMultipart(data MultipartData) error
+}
+
+type BeginMultipartData struct {
+	Ctx    context.Context
+	Object CloudObject
+	Attrs  UploadAttrs
+
+	// PartSize is the size of each part but the last, if known in advance.
+	// It's zero to let the provider decide.
+	PartSize int64
+}
+
+type UploadPartData struct {
+	Ctx        context.Context
+	Object     CloudObject
+	UploadID   string
+	PartNumber int // starting at 1
+	Data       io.Reader
+	Size       int64 // the number of bytes in Data
+}
+
+type MultipartData struct {
+	Ctx      context.Context
+	Object   CloudObject
+	UploadID string
+}
+
+type CompleteMultipartData struct {
+	Ctx      context.Context
+	Object   CloudObject
+	UploadID string
+	Parts    []UploadedPart // ordered by part number
+}
+
+// UploadedPart is a part of a multipart upload that has been uploaded.
+type UploadedPart struct {
+	PartNumber int
+	ETag       string // empty if the provider doesn't identify parts
+	Size       int64
+}
+
+type ObjectEvent struct {
+	Type    string // "object_created" or "object_deleted"
+	Object  CloudObject
+	Version string
+	Size    int64
+	ETag    string
+}
+
 //publicapigen:keep
 var (
 	//publicapigen:keep
@@ -115,4 +477,18 @@ var (
 	ErrPreconditionFailed = errors.New("objects: precondition failed")
 	//publicapigen:keep
 	ErrInvalidArgument = errors.New("objects: invalid argument")
+	//publicapigen:keep
+	ErrThrottled = errors.New("objects: request throttled")
+	//publicapigen:keep
+	ErrDecryptionFailed = errors.New("objects: decryption failed")
+	//publicapigen:keep
+	ErrQuotaExceeded = errors.New("objects: upload quota exceeded")
+	//publicapigen:keep
+	ErrUploadNotExist = errors.New("objects: multipart upload doesn't exist")
+	//publicapigen:keep
+	ErrChecksumMismatch = errors.New("objects: checksum mismatch")
 )
+
+// ErrUnavailable is returned (wrapped) by providers when the bucket
+// couldn't be reached, or it responded with a server error.
+var ErrUnavailable = errors.New("objects: provider unavailable")
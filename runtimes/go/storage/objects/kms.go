@@ -0,0 +1,25 @@
+package objects
+
+import (
+	"fmt"
+
+	"encore.dev/appruntime/exported/config"
+	"encore.dev/storage/objects/internal/types"
+)
+
+// kmsKeyID returns the provider's ID of the KMS key the application refers to
+// as ref, which must be configured for the bucket in the infrastructure config.
+//
+// Running locally there's no KMS, so any reference is accepted and objects
+// are stored without server-side encryption.
+func (mgr *Manager) kmsKeyID(bkt *config.Bucket, ref string) (string, error) {
+	if ref == "" || mgr.runtime.EnvCloud == "local" {
+		return "", nil
+	}
+	id := bkt.KMSKeys[ref]
+	if id == "" {
+		return "", fmt.Errorf("%w: bucket %s has no KMS key %q configured",
+			types.ErrInvalidArgument, bkt.EncoreName, ref)
+	}
+	return id, nil
+}
@@ -0,0 +1,54 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"encore.dev/appruntime/exported/config"
+)
+
+func TestUpload_KMSKey(t *testing.T) {
+	const keyARN = "arn:aws:kms:us-east-1:123456789012:key/pii"
+	newBucket := func(cloud string) (*Bucket, *partsImpl) {
+		impl := newPartsImpl()
+		bkt := newTestBucket(impl)
+		bkt.mgr.runtime = &config.Runtime{EnvCloud: cloud}
+		bkt.runtimeCfg = &config.Bucket{EncoreName: "test", KMSKeys: map[string]string{"pii": keyARN}}
+		return bkt, impl
+	}
+	upload := func(bkt *Bucket, options ...UploadOption) error {
+		w := bkt.Upload(context.Background(), "a.txt", options...)
+		if _, err := w.Write([]byte("a")); err != nil {
+			return err
+		}
+		return w.Close()
+	}
+
+	bkt, impl := newBucket("aws")
+	if err := upload(bkt, WithKMSKey("pii")); err != nil {
+		t.Fatal(err)
+	}
+	if got := impl.objects["a.txt"].attrs.KMSKey; got != keyARN {
+		t.Errorf("got KMS key %q, want %q", got, keyARN)
+	}
+
+	if err := upload(bkt, WithKMSKey("unknown")); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("got err %v for an unknown key, want ErrInvalidArgument", err)
+	}
+	if _, err := bkt.BeginMultipartUpload(context.Background(), "b.txt", WithKMSKey("pii")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bkt.BeginMultipartUpload(context.Background(), "b.txt", WithKMSKey("unknown")); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("got err %v for an unknown key, want ErrInvalidArgument", err)
+	}
+
+	// Running locally, keys aren't used.
+	bkt, impl = newBucket("local")
+	if err := upload(bkt, WithKMSKey("unknown")); err != nil {
+		t.Fatal(err)
+	}
+	if got := impl.objects["a.txt"].attrs.KMSKey; got != "" {
+		t.Errorf("got KMS key %q running locally, want none", got)
+	}
+}
@@ -2,6 +2,12 @@ package objects
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/rs/zerolog"
 
@@ -9,6 +15,7 @@ import (
 	"encore.dev/appruntime/shared/reqtrack"
 	"encore.dev/appruntime/shared/shutdown"
 	"encore.dev/appruntime/shared/testsupport"
+	"encore.dev/storage/objects/internal/transport"
 )
 
 type Manager struct {
@@ -20,29 +27,124 @@ type Manager struct {
 	ts         *testsupport.Manager
 	rootLogger zerolog.Logger
 	providers  []provider
+
+	// transport is the HTTP transport shared by all providers,
+	// or nil to use the providers' defaults.
+	transport http.RoundTripper
+
+	// hooks are called around each operation, in registration order.
+	hooksMu sync.RWMutex
+	hooks   []Hook
+
+	// quotas track the bytes uploaded to each bucket, keyed by bucket name.
+	quotasMu sync.Mutex
+	quotas   map[string]*quota
+
+	// fetchCtx is canceled to stop receiving new object events.
+	fetchCtx        context.Context
+	stopFetching    func()
+	subsMu          sync.Mutex
+	subs            map[string][]*eventSubscription // bucket name -> subscriptions
+	runningFetches  sync.WaitGroup
+	runningHandlers sync.WaitGroup
+
+	// runningOps tracks the uploads and downloads in progress.
+	// See trackOperation.
+	runningOps sync.WaitGroup
 }
 
 func NewManager(static *config.Static, runtime *config.Runtime, rt *reqtrack.RequestTracker,
 	ts *testsupport.Manager, rootLogger zerolog.Logger) *Manager {
+	if err := checkDeps(static, runtime, rt, ts); err != nil {
+		panic("internal encore error: objects: " + err.Error())
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
+	fetchCtx, stopFetching := context.WithCancel(ctx)
 	mgr := &Manager{
-		ctx:        ctx,
-		cancelCtx:  cancel,
-		static:     static,
-		runtime:    runtime,
-		rt:         rt,
-		ts:         ts,
-		rootLogger: rootLogger,
+		ctx:          ctx,
+		cancelCtx:    cancel,
+		static:       static,
+		runtime:      runtime,
+		rt:           rt,
+		ts:           ts,
+		rootLogger:   rootLogger,
+		fetchCtx:     fetchCtx,
+		stopFetching: stopFetching,
+		subs:         make(map[string][]*eventSubscription),
+		transport:    transport.New(runtime.ObjectStorage),
+	}
+
+	if err := validateBucketSubscriptions(static); err != nil {
+		rootLogger.Fatal().Err(err).Msg("invalid object event subscription config")
+	}
+
+	if s := runtime.ObjectStorage; s != nil && s.AuditLog != nil {
+		mgr.registerHook(newAuditLog(s.AuditLog, rt, rootLogger).hook())
 	}
 
 	for _, p := range providerRegistry {
-		mgr.providers = append(mgr.providers, p(mgr.ctx, mgr.runtime))
+		mgr.providers = append(mgr.providers, p(mgr.ctx, mgr.runtime, mgr.transport, rootLogger))
+	}
+
+	if !static.Testing {
+		for _, p := range mgr.providers {
+			if c, ok := p.(endpointChecker); ok {
+				ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+				err := c.CheckEndpoints(ctx)
+				cancel()
+				if err != nil {
+					rootLogger.Fatal().Err(err).Msgf("%s object storage endpoint is not reachable", p.ProviderName())
+				}
+			}
+			if c, ok := p.(credentialChecker); ok {
+				ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+				err := c.CheckCredentials(ctx)
+				cancel()
+				if err != nil {
+					rootLogger.Fatal().Err(err).Msgf("%s object storage credentials are misconfigured", p.ProviderName())
+				}
+			}
+		}
 	}
 
 	return mgr
 }
 
-// Shutdown stops the manager from fetching new messages and processing them.
+// errNotInitialized is reported when the objects subsystem is used
+// before it has been initialized.
+var errNotInitialized = errors.New("objects subsystem not initialized")
+
+// checkDeps checks that the manager's dependencies are set.
+// They're set up by other packages' init functions, so a missing
+// dependency means the packages were initialized in the wrong order.
+func checkDeps(static *config.Static, runtime *config.Runtime, rt *reqtrack.RequestTracker, ts *testsupport.Manager) error {
+	var missing []string
+	if static == nil {
+		missing = append(missing, "static config")
+	}
+	if runtime == nil {
+		missing = append(missing, "runtime config")
+	}
+	if rt == nil {
+		missing = append(missing, "request tracker")
+	}
+	if ts == nil {
+		missing = append(missing, "test support")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing %s; the objects package was initialized before "+
+			"the runtime packages it depends on", errNotInitialized, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Shutdown stops the manager from receiving new object events
+// and waits for running event handlers and operations to complete.
+//
+// Once it's time to force-close tasks the running operations are canceled,
+// and if they still haven't completed by the time the shutdown is forced
+// it stops waiting on them and reports an error.
 func (mgr *Manager) Shutdown(p *shutdown.Process) error {
 	// Once it's time to force-close tasks, cancel the base context.
 	go func() {
@@ -50,5 +152,59 @@ func (mgr *Manager) Shutdown(p *shutdown.Process) error {
 		mgr.cancelCtx()
 	}()
 
+	p.Log.Trace().Msg("objects: stop receiving new events")
+	mgr.stopFetching()
+	if err := waitUntil(p.ForceShutdown, &mgr.runningFetches); err != nil {
+		return fmt.Errorf("objects: stop receiving events: %w", err)
+	}
+
+	p.Log.Trace().Msg("objects: waiting on running event handlers and operations")
+	if err := waitUntil(p.ForceShutdown, &mgr.runningHandlers, &mgr.runningOps); err != nil {
+		return fmt.Errorf("objects: wait on running event handlers and operations: %w", err)
+	}
+
 	return nil
 }
+
+// waitUntil waits for the wait groups to complete, or for ctx to be done.
+func waitUntil(ctx context.Context, wgs ...*sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		for _, wg := range wgs {
+			wg.Wait()
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// errShutdown is the cause of the cancellation of operations
+// that are still running when it's time to force-close tasks.
+var errShutdown = errors.New("objects: operation canceled by shutdown")
+
+// trackOperation tracks a long-running operation, like an upload or a download,
+// so that shutdown waits for it to complete. The returned context is canceled
+// once it's time to force-close tasks.
+//
+// The returned function must be called when the operation completes.
+// It may be called more than once.
+func (mgr *Manager) trackOperation(ctx context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(mgr.ctx, func() { cancel(errShutdown) })
+	mgr.runningOps.Add(1)
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			stop()
+			cancel(nil)
+			mgr.runningOps.Done()
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"encore.dev/appruntime/exported/config"
+	"encore.dev/appruntime/shared/reqtrack"
+	"encore.dev/appruntime/shared/shutdown"
+	"encore.dev/appruntime/shared/testsupport"
+	"encore.dev/storage/objects/internal/types"
+)
+
+func TestCheckDeps(t *testing.T) {
+	static, runtime := &config.Static{}, &config.Runtime{}
+	rt, ts := &reqtrack.RequestTracker{}, &testsupport.Manager{}
+	if err := checkDeps(static, runtime, rt, ts); err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+
+	err := checkDeps(static, nil, rt, nil)
+	if !errors.Is(err, errNotInitialized) {
+		t.Fatalf("got err %v, want errNotInitialized", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "missing runtime config, test support") {
+		t.Errorf("got err %q, want it to list the missing dependencies", msg)
+	}
+}
+
+func TestNewManager_MissingDeps(t *testing.T) {
+	defer func() {
+		r := recover()
+		if msg, _ := r.(string); !strings.Contains(msg, "objects subsystem not initialized") {
+			t.Errorf("got panic %v, want an objects subsystem not initialized panic", r)
+		}
+	}()
+	NewManager(&config.Static{}, nil, nil, nil, zerolog.Nop())
+}
+
+// slowImpl is a bucket implementation whose operations block.
+// Downloads block until their context is canceled,
+// and uploads block on completion until unblock is closed.
+type slowImpl struct {
+	types.BucketImpl
+	started chan struct{}
+	unblock chan struct{}
+}
+
+func (s *slowImpl) Download(data types.DownloadData) (types.Downloader, error) {
+	s.started <- struct{}{}
+	<-data.Ctx.Done()
+	return nil, data.Ctx.Err()
+}
+
+func (s *slowImpl) Upload(data types.UploadData) (types.Uploader, error) {
+	return &slowUploader{s: s}, nil
+}
+
+type slowUploader struct{ s *slowImpl }
+
+func (u *slowUploader) Write(p []byte) (int, error) { return len(p), nil }
+func (u *slowUploader) Abort(err error)             {}
+func (u *slowUploader) Complete() (*types.ObjectAttrs, error) {
+	u.s.started <- struct{}{}
+	<-u.s.unblock
+	return &types.ObjectAttrs{}, nil
+}
+
+// newShutdownTestBucket returns a bucket whose manager can be shut down.
+func newShutdownTestBucket(impl types.BucketImpl) *Bucket {
+	bkt := newTestBucket(impl)
+	mgr := bkt.mgr
+	mgr.ctx, mgr.cancelCtx = context.WithCancel(context.Background())
+	mgr.fetchCtx, mgr.stopFetching = context.WithCancel(mgr.ctx)
+	return bkt
+}
+
+// newShutdownProcess returns a shutdown process that force-closes tasks
+// after forceClose, and forces the shutdown after forceShutdown.
+func newShutdownProcess(t *testing.T, forceClose, forceShutdown time.Duration) *shutdown.Process {
+	log := zerolog.Nop()
+	forceCloseCtx, cancelForceClose := context.WithTimeout(context.Background(), forceClose)
+	forceShutdownCtx, cancelForceShutdown := context.WithTimeout(context.Background(), forceShutdown)
+	t.Cleanup(func() {
+		cancelForceClose()
+		cancelForceShutdown()
+	})
+	return &shutdown.Process{Log: &log, ForceCloseTasks: forceCloseCtx, ForceShutdown: forceShutdownCtx}
+}
+
+func TestShutdown_CancelsOperations(t *testing.T) {
+	impl := &slowImpl{started: make(chan struct{}, 1)}
+	bkt := newShutdownTestBucket(impl)
+
+	downloaded := make(chan error, 1)
+	go func() {
+		r := bkt.Download(context.Background(), "a.txt")
+		downloaded <- r.Err()
+		_ = r.Close()
+	}()
+	<-impl.started
+
+	start := time.Now()
+	if err := bkt.mgr.Shutdown(newShutdownProcess(t, 50*time.Millisecond, 5*time.Second)); err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("shutdown took %v, want it to return once the download was canceled", d)
+	}
+	if err := <-downloaded; !errors.Is(err, context.Canceled) {
+		t.Errorf("got download err %v, want context.Canceled", err)
+	}
+}
+
+func TestShutdown_Deadline(t *testing.T) {
+	impl := &slowImpl{started: make(chan struct{}, 1), unblock: make(chan struct{})}
+	defer close(impl.unblock)
+	bkt := newShutdownTestBucket(impl)
+
+	// The upload ignores its context being canceled.
+	go func() {
+		w := bkt.Upload(context.Background(), "a.txt")
+		_, _ = w.Write([]byte("data"))
+		_ = w.Close()
+	}()
+	<-impl.started
+
+	start := time.Now()
+	err := bkt.mgr.Shutdown(newShutdownProcess(t, 50*time.Millisecond, 200*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got err %v, want context.DeadlineExceeded", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("shutdown took %v, want it to return at the deadline", d)
+	}
+}
@@ -0,0 +1,63 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+func TestUploadMetadata(t *testing.T) {
+	impl := &multiImpl{objects: map[types.CloudObject]*multiObject{}}
+	bkt := newTestBucket(impl)
+	ctx := context.Background()
+
+	md := map[string]string{"owner": "alice"}
+	tags := map[string]string{"env": "test"}
+	w := bkt.Upload(ctx, "a.txt",
+		WithMetadata(md),
+		WithTags(tags),
+		WithUploadAttrs(UploadAttrs{ContentType: "text/plain"}),
+		WithIdempotencyKey("key"),
+	)
+	if _, err := w.Write([]byte("a")); err != nil {
+		t.Fatal(err)
+	} else if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	attrs, err := bkt.Attrs(ctx, "a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrs.ContentType != "text/plain" {
+		t.Errorf("got content type %q, want text/plain", attrs.ContentType)
+	}
+	// The metadata used by Encore itself is not included.
+	if !maps.Equal(attrs.Metadata, md) {
+		t.Errorf("got metadata %v, want %v", attrs.Metadata, md)
+	}
+	if !maps.Equal(attrs.Tags, tags) {
+		t.Errorf("got tags %v, want %v", attrs.Tags, tags)
+	}
+
+	for entry, err := range bkt.List(ctx, &Query{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !maps.Equal(entry.Metadata, md) || !maps.Equal(entry.Tags, tags) {
+			t.Errorf("got metadata %v and tags %v when listing, want %v and %v", entry.Metadata, entry.Tags, md, tags)
+		}
+	}
+}
+
+func TestUploadMetadata_Reserved(t *testing.T) {
+	bkt := newTestBucket(&multiImpl{objects: map[types.CloudObject]*multiObject{}})
+	w := bkt.Upload(context.Background(), "a.txt", WithMetadata(map[string]string{"Encore-Foo": "x"}))
+	if _, err := w.Write([]byte("a")); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("got error %v, want ErrInvalidArgument", err)
+	}
+	_ = w.Close()
+}
@@ -0,0 +1,138 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// defaultMigrateConcurrency is the number of objects Migrate copies concurrently by default.
+const defaultMigrateConcurrency = 8
+
+// MigrateReport summarizes a migration of objects between buckets.
+type MigrateReport struct {
+	// Copied is the number of objects that were copied.
+	Copied int
+
+	// Skipped is the number of objects that were already
+	// in the destination bucket, such as from a previous run.
+	Skipped int
+
+	// Failures are the objects that couldn't be copied.
+	Failures []MigrateFailure
+}
+
+// MigrateFailure describes an object that couldn't be copied.
+type MigrateFailure struct {
+	// Name is the name of the object.
+	Name string
+
+	// Err is the reason it couldn't be copied.
+	Err error
+}
+
+// MigrateProgress describes the progress of a migration. See WithProgress.
+type MigrateProgress struct {
+	// Object is the name of the object that was just processed.
+	Object string
+
+	// Copied, Skipped and Failed are the number of objects
+	// processed so far, by outcome.
+	Copied, Skipped, Failed int
+}
+
+// Migrate copies the objects in src matching query to dst, keeping their names.
+//
+// Objects are copied server-side when both buckets use the same provider,
+// and are otherwise streamed from src to dst. Encrypted objects are
+// always streamed, so that they're encrypted with dst's encryption key.
+//
+// Migrate is resumable: objects already copied to dst, by a previous run or
+// as identical objects, are skipped. Objects changed in src since they were
+// copied are copied again.
+//
+// Failing to copy an object doesn't stop the migration; the failures are
+// listed in the report. The returned error is only set if listing src
+// fails or ctx is canceled, in which case the report covers the objects
+// processed until then.
+func Migrate(ctx context.Context, src, dst *Bucket, query *Query, options ...MigrateOption) (*MigrateReport, error) {
+	opt := migrateOptions{concurrency: defaultMigrateConcurrency}
+	for _, o := range options {
+		o.applyMigrate(&opt)
+	}
+
+	var (
+		mu     sync.Mutex
+		report MigrateReport
+	)
+	err := src.ForEach(ctx, query, opt.concurrency, func(ctx context.Context, entry *ListEntry) error {
+		copied, err := migrateObject(ctx, src, dst, entry)
+		if ctx.Err() != nil {
+			// The object wasn't processed; ForEach reports the cancellation.
+			return nil
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case errors.Is(err, ErrObjectNotFound):
+			// Removed from src since it was listed.
+		case err != nil:
+			report.Failures = append(report.Failures, MigrateFailure{Name: entry.Name, Err: err})
+		case copied:
+			report.Copied++
+		default:
+			report.Skipped++
+		}
+		if opt.progress != nil {
+			opt.progress(MigrateProgress{
+				Object:  entry.Name,
+				Copied:  report.Copied,
+				Skipped: report.Skipped,
+				Failed:  len(report.Failures),
+			})
+		}
+		return nil
+	})
+	return &report, err
+}
+
+// migrateKey returns the idempotency key objects are copied with,
+// which identifies the version of the source object that was copied.
+func migrateKey(entry *ListEntry) string {
+	return fmt.Sprintf("migrate:%d:%s", entry.Size, entry.ETag)
+}
+
+// migrateObject copies a single object from src to dst,
+// reporting false if it was already copied.
+func migrateObject(ctx context.Context, src, dst *Bucket, entry *ListEntry) (copied bool, err error) {
+	key := migrateKey(entry)
+	existing, err := dst.impl.Attrs(types.AttrsData{Ctx: ctx, Object: dst.toCloudObject(entry.Name)})
+	if err == nil {
+		if existing.IdempotencyKey == key || (existing.Size == entry.Size && existing.ETag == entry.ETag) {
+			return false, nil
+		}
+	} else if !errors.Is(err, types.ErrObjectNotExist) {
+		return false, err
+	}
+
+	attrs, err := src.Attrs(ctx, entry.Name)
+	if err != nil {
+		return false, err
+	}
+
+	if c, ok := dst.copierImpl(); ok && !attrs.Encrypted {
+		_, err := dst.copyServerSide(ctx, c, src, entry.Name, entry.Name, attrs.Size)
+		if !errors.Is(err, types.ErrCopyUnsupported) {
+			return err == nil, err
+		}
+	}
+
+	if err := streamObject(ctx, src, dst, entry.Name, entry.Name, attrs, WithIdempotencyKey(key)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
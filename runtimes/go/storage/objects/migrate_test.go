@@ -0,0 +1,94 @@
+package objects
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// copyImpl is a multiImpl supporting server-side copies
+// from other multiImpls and from itself.
+type copyImpl struct {
+	*multiImpl
+	copies int
+}
+
+func (c *copyImpl) Copy(data types.CopyData) (*types.ObjectAttrs, error) {
+	src, ok := data.Src.(*multiImpl)
+	if data.Src == c {
+		src, ok = c.multiImpl, true
+	}
+	if !ok {
+		return nil, types.ErrCopyUnsupported
+	}
+	obj, ok := src.objects[data.SrcObject]
+	if !ok {
+		return nil, types.ErrObjectNotExist
+	}
+	if _, exists := c.objects[data.Object]; exists && data.Pre.NotExists {
+		return nil, types.ErrPreconditionFailed
+	}
+	c.copies++
+	copied := &multiObject{data: obj.data, attrs: obj.attrs, encoding: obj.encoding}
+	if data.Attrs != nil {
+		copied.attrs, copied.encoding = *data.Attrs, data.ContentEncoding
+	}
+	c.objects[data.Object] = copied
+	return c.Attrs(types.AttrsData{Object: data.Object})
+}
+
+func TestMigrate(t *testing.T) {
+	src, srcImpl := newTrashTestBucket()
+	dstImpl := &multiImpl{objects: map[types.CloudObject]*multiObject{}}
+	dst := newTestBucket(dstImpl)
+	ctx := context.Background()
+
+	var progress []MigrateProgress
+	report, err := Migrate(ctx, src, dst, &Query{}, WithConcurrency(1), WithProgress(func(p MigrateProgress) {
+		progress = append(progress, p)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Copied != 2 || report.Skipped != 0 || len(report.Failures) != 0 {
+		t.Fatalf("got report %+v, want 2 copied", report)
+	}
+	if obj := dstImpl.objects["a.txt"]; obj == nil || string(obj.data) != "a" || obj.attrs.ContentType != "text/plain" {
+		t.Errorf("got copied object %+v, want the original content and attributes", obj)
+	}
+	if len(progress) != 2 || progress[1].Copied != 2 {
+		t.Errorf("got progress %+v, want two updates", progress)
+	}
+
+	// Running it again only copies what changed.
+	srcImpl.objects["a.txt"].data = []byte("changed")
+	report, err = Migrate(ctx, src, dst, &Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Copied != 1 || report.Skipped != 1 || len(report.Failures) != 0 {
+		t.Errorf("got report %+v, want 1 copied and 1 skipped", report)
+	}
+	if got := string(dstImpl.objects["a.txt"].data); got != "changed" {
+		t.Errorf("got object %q, want the changed content", got)
+	}
+}
+
+func TestMigrate_ServerSideCopy(t *testing.T) {
+	src, _ := newTrashTestBucket()
+	dstImpl := &copyImpl{multiImpl: &multiImpl{objects: map[types.CloudObject]*multiObject{}}}
+	dst := newTestBucket(dstImpl)
+
+	report, err := Migrate(context.Background(), src, dst, &Query{Prefix: "b/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Copied != 1 || dstImpl.copies != 1 {
+		t.Errorf("got report %+v with %d server-side copies, want 1", report, dstImpl.copies)
+	}
+	if got := listNames(t, dst, &Query{}); !slices.Equal(got, []string{"b/c.txt"}) {
+		t.Errorf("got listing %v, want b/c.txt", got)
+	}
+}
@@ -0,0 +1,255 @@
+package objects
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// MultipartUpload is an upload of an object in separately uploaded parts,
+// started with BeginMultipartUpload.
+//
+// Multipart uploads are useful for very large objects, such as those larger
+// than 5GB, and for uploads that must survive restarts: the object and ID
+// can be stored and used to resume the upload later, possibly in another process.
+type MultipartUpload struct {
+	// Object is the name of the object being uploaded.
+	Object string
+
+	// ID identifies the upload with the provider.
+	ID string
+}
+
+// UploadedPart is a part of a multipart upload that has been uploaded.
+type UploadedPart struct {
+	// PartNumber is the number of the part, starting at 1.
+	PartNumber int
+
+	// ETag identifies the part's content, if the provider reports it.
+	ETag string
+
+	// Size is the size of the part, in bytes.
+	Size int64
+}
+
+// BeginMultipartUpload starts uploading an object in multiple parts.
+//
+// The parts are uploaded with UploadPart, after which the upload is completed
+// with CompleteMultipartUpload, creating the object. Until then the object
+// doesn't exist, and uploaded parts are kept by the provider (and count towards
+// storage costs) until the upload is completed or aborted with AbortMultipartUpload.
+//
+// On S3 it starts a multipart upload, and on GCS a resumable upload.
+// Buckets without support for multipart uploads return ErrInvalidArgument.
+func (b *Bucket) BeginMultipartUpload(ctx context.Context, object string, options ...MultipartUploadOption) (*MultipartUpload, error) {
+	var opt multipartUploadOptions
+	for _, o := range options {
+		o.applyMultipartUpload(&opt)
+	}
+
+	impl, err := b.multipartImpl()
+	if err != nil {
+		return nil, err
+	} else if err := checkUploadAttrs(opt.attrs); err != nil {
+		return nil, err
+	}
+	if opt.attrs.KMSKey, err = b.mgr.kmsKeyID(b.runtimeCfg, opt.kmsKey); err != nil {
+		return nil, err
+	}
+
+	var id string
+	err = b.do(ctx, "begin_multipart_upload", object, func() (err error) {
+		id, err = impl.BeginMultipart(types.BeginMultipartData{
+			Ctx:      ctx,
+			Object:   b.toCloudObject(object),
+			Attrs:    opt.attrs,
+			PartSize: opt.partSize,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MultipartUpload{Object: object, ID: id}, nil
+}
+
+// UploadPart uploads a part of a multipart upload, reading size bytes from data.
+//
+// Part numbers start at 1. Uploading a part with the same number again
+// replaces it. All parts but the last must have the same size, which should be
+// declared with WithPartSize when beginning the upload: S3 requires parts of
+// at least 5MiB, and GCS parts must be a multiple of 256KiB.
+// GCS also requires the parts to be uploaded in order, so that on GCS a part
+// can only be uploaded once all parts before it have been.
+//
+// If the provider throttles the request it's retried, as long as data
+// implements io.Seeker so that the part can be read again.
+//
+// The part counts towards the bucket's upload quota once it's uploaded.
+func (b *Bucket) UploadPart(ctx context.Context, upload *MultipartUpload, partNumber int, data io.Reader, size int64) (*UploadedPart, error) {
+	if partNumber < 1 || size < 0 {
+		return nil, fmt.Errorf("%w: invalid part number %d or size %d", types.ErrInvalidArgument, partNumber, size)
+	}
+	impl, err := b.multipartImpl()
+	if err != nil {
+		return nil, err
+	}
+	if err := b.quota.reserve(size); err != nil {
+		return nil, err
+	}
+
+	// Retries read the part again from where it started, if possible.
+	retries := 0
+	var offset int64
+	seeker, ok := data.(io.Seeker)
+	if ok {
+		if offset, err = seeker.Seek(0, io.SeekCurrent); err == nil {
+			retries = throttleMaxRetries
+		}
+	}
+
+	var part *types.UploadedPart
+	attempt := 0
+	err = b.doRetries(ctx, "upload_part", upload.Object, retries, func() (err error) {
+		if attempt++; attempt > 1 {
+			if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+				return err
+			}
+		}
+		part, err = impl.UploadPart(types.UploadPartData{
+			Ctx:        ctx,
+			Object:     b.toCloudObject(upload.Object),
+			UploadID:   upload.ID,
+			PartNumber: partNumber,
+			Data:       data,
+			Size:       size,
+		})
+		return err
+	})
+	if err != nil {
+		b.quota.release(size)
+		return nil, err
+	}
+	b.quota.commit(size)
+	return &UploadedPart{PartNumber: part.PartNumber, ETag: part.ETag, Size: part.Size}, nil
+}
+
+// UploadedParts lists the parts of a multipart upload that have been uploaded,
+// ordered by part number.
+//
+// It's used to resume an upload, such as after a restart,
+// by only uploading the parts that are missing.
+func (b *Bucket) UploadedParts(ctx context.Context, upload *MultipartUpload) ([]UploadedPart, error) {
+	impl, err := b.multipartImpl()
+	if err != nil {
+		return nil, err
+	}
+
+	var parts []types.UploadedPart
+	err = b.do(ctx, "list_uploaded_parts", upload.Object, func() (err error) {
+		parts, err = impl.ListParts(types.MultipartData{
+			Ctx:      ctx,
+			Object:   b.toCloudObject(upload.Object),
+			UploadID: upload.ID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]UploadedPart, len(parts))
+	for i, p := range parts {
+		res[i] = UploadedPart{PartNumber: p.PartNumber, ETag: p.ETag, Size: p.Size}
+	}
+	return res, nil
+}
+
+// CompleteMultipartUpload completes a multipart upload,
+// creating the object from the given parts.
+//
+// If parts is nil, the object is created from all the parts uploaded
+// so far, as reported by UploadedParts. The parts must be ordered
+// by part number and be without gaps.
+func (b *Bucket) CompleteMultipartUpload(ctx context.Context, upload *MultipartUpload, parts []UploadedPart) (*ObjectAttrs, error) {
+	impl, err := b.multipartImpl()
+	if err != nil {
+		return nil, err
+	}
+	if parts == nil {
+		if parts, err = b.UploadedParts(ctx, upload); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkParts(parts); err != nil {
+		return nil, err
+	}
+
+	cloudParts := make([]types.UploadedPart, len(parts))
+	for i, p := range parts {
+		cloudParts[i] = types.UploadedPart{PartNumber: p.PartNumber, ETag: p.ETag, Size: p.Size}
+	}
+
+	var attrs *types.ObjectAttrs
+	err = b.do(ctx, "complete_multipart_upload", upload.Object, func() (err error) {
+		attrs, err = impl.CompleteMultipart(types.CompleteMultipartData{
+			Ctx:      ctx,
+			Object:   b.toCloudObject(upload.Object),
+			UploadID: upload.ID,
+			Parts:    cloudParts,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b.mapAttrs(attrs), nil
+}
+
+// AbortMultipartUpload aborts a multipart upload,
+// discarding the parts uploaded so far.
+func (b *Bucket) AbortMultipartUpload(ctx context.Context, upload *MultipartUpload) error {
+	impl, err := b.multipartImpl()
+	if err != nil {
+		return err
+	}
+	return b.do(ctx, "abort_multipart_upload", upload.Object, func() error {
+		return impl.AbortMultipart(types.MultipartData{
+			Ctx:      ctx,
+			Object:   b.toCloudObject(upload.Object),
+			UploadID: upload.ID,
+		})
+	})
+}
+
+// multipartImpl returns the bucket's implementation of multipart uploads.
+// Multipart uploads are writes, so they always go to the primary bucket.
+func (b *Bucket) multipartImpl() (types.MultipartUploader, error) {
+	if m, ok := b.primaryImpl().(types.MultipartUploader); ok {
+		return m, nil
+	}
+	return nil, fmt.Errorf("%w: bucket %s doesn't support multipart uploads", types.ErrInvalidArgument, b.name)
+}
+
+// checkParts reports an error unless parts are numbered 1, 2, 3 and so on.
+func checkParts(parts []UploadedPart) error {
+	if len(parts) == 0 {
+		return fmt.Errorf("%w: no parts uploaded", types.ErrInvalidArgument)
+	}
+	nums := make([]int, len(parts))
+	for i, p := range parts {
+		nums[i] = p.PartNumber
+	}
+	for i, n := range nums {
+		if n != i+1 {
+			if !slices.IsSorted(nums) {
+				return fmt.Errorf("%w: parts must be ordered by part number", types.ErrInvalidArgument)
+			}
+			return fmt.Errorf("%w: part %d is missing", types.ErrInvalidArgument, i+1)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package objects
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// partsImpl is an in-memory bucket implementation supporting multipart uploads.
+type partsImpl struct {
+	multiImpl
+	uploads map[string]map[int][]byte
+	nextID  int
+
+	// throttle is the number of part uploads to throttle.
+	throttle int
+}
+
+func newPartsImpl() *partsImpl {
+	return &partsImpl{
+		multiImpl: multiImpl{objects: map[types.CloudObject]*multiObject{}},
+		uploads:   map[string]map[int][]byte{},
+	}
+}
+
+func (m *partsImpl) BeginMultipart(data types.BeginMultipartData) (string, error) {
+	m.nextID++
+	id := strconv.Itoa(m.nextID)
+	m.uploads[id] = map[int][]byte{}
+	return id, nil
+}
+
+func (m *partsImpl) UploadPart(data types.UploadPartData) (*types.UploadedPart, error) {
+	parts, ok := m.uploads[data.UploadID]
+	if !ok {
+		return nil, types.ErrUploadNotExist
+	}
+	buf, err := io.ReadAll(io.LimitReader(data.Data, data.Size))
+	if err != nil {
+		return nil, err
+	}
+	if m.throttle > 0 {
+		m.throttle--
+		return nil, types.ErrThrottled
+	}
+	parts[data.PartNumber] = buf
+	return &types.UploadedPart{PartNumber: data.PartNumber, ETag: string(buf), Size: int64(len(buf))}, nil
+}
+
+func (m *partsImpl) ListParts(data types.MultipartData) ([]types.UploadedPart, error) {
+	parts, ok := m.uploads[data.UploadID]
+	if !ok {
+		return nil, types.ErrUploadNotExist
+	}
+	var res []types.UploadedPart
+	for _, n := range slices.Sorted(maps.Keys(parts)) {
+		res = append(res, types.UploadedPart{PartNumber: n, ETag: string(parts[n]), Size: int64(len(parts[n]))})
+	}
+	return res, nil
+}
+
+func (m *partsImpl) CompleteMultipart(data types.CompleteMultipartData) (*types.ObjectAttrs, error) {
+	parts, ok := m.uploads[data.UploadID]
+	if !ok {
+		return nil, types.ErrUploadNotExist
+	}
+	var buf []byte
+	for _, p := range data.Parts {
+		buf = append(buf, parts[p.PartNumber]...)
+	}
+	delete(m.uploads, data.UploadID)
+	m.objects[data.Object] = &multiObject{data: buf}
+	return m.Attrs(types.AttrsData{Object: data.Object})
+}
+
+func (m *partsImpl) AbortMultipart(data types.MultipartData) error {
+	if _, ok := m.uploads[data.UploadID]; !ok {
+		return types.ErrUploadNotExist
+	}
+	delete(m.uploads, data.UploadID)
+	return nil
+}
+
+func TestMultipartUpload(t *testing.T) {
+	impl := newPartsImpl()
+	bkt := newTestBucket(impl)
+	bkt.quota = bkt.mgr.quotaFor("test", 100)
+	ctx := context.Background()
+
+	upload, err := bkt.BeginMultipartUpload(ctx, "big.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, data := range []string{"aaa", "bbb"} {
+		if _, err := bkt.UploadPart(ctx, upload, i+1, strings.NewReader(data), 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Resume the upload from its object and ID, as if in another process.
+	resumed := &MultipartUpload{Object: upload.Object, ID: upload.ID}
+	parts, err := bkt.UploadedParts(ctx, resumed)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(parts) != 2 {
+		t.Fatalf("got %d uploaded parts, want 2", len(parts))
+	}
+	if _, err := bkt.UploadPart(ctx, resumed, len(parts)+1, strings.NewReader("c"), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	attrs, err := bkt.CompleteMultipartUpload(ctx, resumed, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if attrs.Name != "big.bin" || attrs.Size != 7 {
+		t.Errorf("got object %s of size %d, want big.bin of size 7", attrs.Name, attrs.Size)
+	}
+	if got := string(impl.objects["big.bin"].data); got != "aaabbbc" {
+		t.Errorf("got content %q, want %q", got, "aaabbbc")
+	}
+	if got := bkt.QuotaUsage(); got.Used != 7 {
+		t.Errorf("got %d bytes of quota used, want 7", got.Used)
+	}
+
+	// Aborted uploads are gone.
+	upload, err = bkt.BeginMultipartUpload(ctx, "other.bin")
+	if err != nil {
+		t.Fatal(err)
+	} else if err := bkt.AbortMultipartUpload(ctx, upload); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bkt.UploadPart(ctx, upload, 1, strings.NewReader("x"), 1); !errors.Is(err, ErrUploadNotFound) {
+		t.Errorf("got error %v uploading to an aborted upload, want ErrUploadNotFound", err)
+	}
+}
+
+func TestMultipartUpload_Parts(t *testing.T) {
+	bkt := newTestBucket(newPartsImpl())
+	ctx := context.Background()
+	upload, err := bkt.BeginMultipartUpload(ctx, "big.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		parts []UploadedPart
+	}{
+		{name: "none", parts: []UploadedPart{}},
+		{name: "gap", parts: []UploadedPart{{PartNumber: 1}, {PartNumber: 3}}},
+		{name: "unordered", parts: []UploadedPart{{PartNumber: 2}, {PartNumber: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := bkt.CompleteMultipartUpload(ctx, upload, tt.parts); !errors.Is(err, ErrInvalidArgument) {
+				t.Errorf("got error %v, want ErrInvalidArgument", err)
+			}
+		})
+	}
+
+	if _, err := bkt.UploadPart(ctx, upload, 0, strings.NewReader("x"), 1); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("got error %v for part number 0, want ErrInvalidArgument", err)
+	}
+}
+
+func TestMultipartUpload_Retry(t *testing.T) {
+	impl := newPartsImpl()
+	bkt := newTestBucket(impl)
+	ctx := context.Background()
+	upload, err := bkt.BeginMultipartUpload(ctx, "big.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Seekable parts are read again when retried.
+	impl.throttle = 1
+	if _, err := bkt.UploadPart(ctx, upload, 1, bytes.NewReader([]byte("abc")), 3); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(impl.uploads[upload.ID][1]); got != "abc" {
+		t.Errorf("got part %q after retrying, want %q", got, "abc")
+	}
+
+	// Other parts can't be retried.
+	impl.throttle = 1
+	r := struct{ io.Reader }{strings.NewReader("def")}
+	if _, err := bkt.UploadPart(ctx, upload, 2, r, 3); !errors.Is(err, ErrThrottled) {
+		t.Errorf("got error %v, want ErrThrottled", err)
+	}
+}
+
+func TestMultipartUpload_Unsupported(t *testing.T) {
+	bkt := newTestBucket(&memImpl{})
+	if _, err := bkt.BeginMultipartUpload(context.Background(), "big.bin"); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("got error %v, want ErrInvalidArgument", err)
+	}
+}
@@ -0,0 +1,62 @@
+package objects
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"encore.dev/pubsub"
+)
+
+// TopicNotificationConfig is the configuration for a TopicNotification.
+type TopicNotificationConfig struct {
+	// Events are the kinds of changes to publish.
+	// If empty, all changes are published.
+	Events []EventType
+
+	// Prefix, if set, only publishes changes to objects
+	// whose name begins with the prefix, like "uploads/".
+	Prefix string
+}
+
+// TopicNotification publishes change events for the objects in a bucket to a Pub/Sub topic.
+type TopicNotification struct {
+	sub *eventSubscription
+}
+
+// NewTopicNotification declares that change events for the objects in bkt
+// are published to topic, so they can be processed by the topic's subscriptions.
+//
+// Events are received from the bucket provider's native event notifications,
+// like S3 event notifications or GCS Pub/Sub notifications, which Encore provisions
+// when the notification is declared. When running locally they are emulated.
+//
+// A call to NewTopicNotification can only be made when declaring a package level variable
+// within a service, and each topic can only be notified once per bucket.
+func NewTopicNotification(bkt *Bucket, topic *pubsub.Topic[*ObjectEvent], cfg TopicNotificationConfig) *TopicNotification {
+	return newTopicNotification(bkt, topic.Meta().Name, topic, cfg)
+}
+
+// eventPublisher is the subset of *pubsub.Topic[*ObjectEvent] used by topic notifications.
+type eventPublisher interface {
+	Publish(ctx context.Context, event *ObjectEvent, options ...pubsub.PublishOption) (string, error)
+}
+
+func newTopicNotification(bkt *Bucket, topicName string, topic eventPublisher, cfg TopicNotificationConfig) *TopicNotification {
+	for _, ev := range cfg.Events {
+		if ev != ObjectCreated && ev != ObjectDeleted {
+			panic(fmt.Sprintf("objects: notification of topic %s for bucket %s has unknown event type %q",
+				topicName, bkt.name, ev))
+		}
+	}
+
+	prefix := cfg.Prefix
+	sub := newEventSubscription(bkt, "topic:"+topicName, cfg.Events, func(ctx context.Context, event *ObjectEvent) error {
+		if !strings.HasPrefix(event.Name, prefix) {
+			return nil
+		}
+		_, err := topic.Publish(ctx, event)
+		return err
+	})
+	return &TopicNotification{sub: sub}
+}
@@ -0,0 +1,83 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"encore.dev/pubsub"
+	"encore.dev/storage/objects/internal/types"
+)
+
+// eventsImpl is a bucket implementation delivering a fixed list of events.
+type eventsImpl struct {
+	memImpl
+	events []*types.ObjectEvent
+	done   chan struct{} // closed when all events have been delivered
+}
+
+func (m *eventsImpl) SubscribeEvents(ctx context.Context, deliver func(ctx context.Context, ev *types.ObjectEvent) error) error {
+	for _, ev := range m.events {
+		_ = deliver(ctx, ev)
+	}
+	close(m.done)
+	<-ctx.Done()
+	return nil
+}
+
+// publisherFunc is an eventPublisher calling a function.
+type publisherFunc func(ctx context.Context, event *ObjectEvent) (string, error)
+
+func (f publisherFunc) Publish(ctx context.Context, event *ObjectEvent, _ ...pubsub.PublishOption) (string, error) {
+	return f(ctx, event)
+}
+
+func TestTopicNotification(t *testing.T) {
+	impl := &eventsImpl{
+		events: []*types.ObjectEvent{
+			{Type: "object_created", Object: "uploads/a.jpg", Size: 3},
+			{Type: "object_deleted", Object: "uploads/a.jpg"},
+			{Type: "object_created", Object: "other/b.jpg", Size: 5},
+		},
+		done: make(chan struct{}),
+	}
+	bkt := newTestBucket(impl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bkt.mgr.ctx, bkt.mgr.fetchCtx = ctx, ctx
+	bkt.mgr.subs = make(map[string][]*eventSubscription)
+
+	var published []*ObjectEvent
+	pub := publisherFunc(func(ctx context.Context, event *ObjectEvent) (string, error) {
+		published = append(published, event)
+		return "id", nil
+	})
+	newTopicNotification(bkt, "uploads", pub, TopicNotificationConfig{
+		Events: []EventType{ObjectCreated},
+		Prefix: "uploads/",
+	})
+	<-impl.done
+
+	want := []*ObjectEvent{{Type: ObjectCreated, Bucket: "test", Name: "uploads/a.jpg", Size: 3}}
+	if !reflect.DeepEqual(published, want) {
+		t.Errorf("got published events %+v, want %+v", published, want)
+	}
+}
+
+func TestTopicNotification_PublishError(t *testing.T) {
+	bkt := newTestBucket(&memImpl{})
+	bkt.mgr.fetchCtx = context.Background()
+	bkt.mgr.subs = make(map[string][]*eventSubscription)
+
+	publishErr := errors.New("publish failed")
+	newTopicNotification(bkt, "uploads", publisherFunc(func(context.Context, *ObjectEvent) (string, error) {
+		return "", publishErr
+	}), TopicNotificationConfig{})
+
+	// Publish errors are returned so the event is redelivered.
+	ev := &types.ObjectEvent{Type: "object_created", Object: "a.jpg"}
+	if err := bkt.mgr.dispatchEvent(context.Background(), bkt, ev); !errors.Is(err, publishErr) {
+		t.Errorf("got error %v, want %v", err, publishErr)
+	}
+}
@@ -6,7 +6,26 @@ package objects
 //
 // See https://encore.dev/docs/primitives/object-storage for more information.
 func NewBucket(name string, cfg BucketConfig) *Bucket {
-	return newBucket(Singleton, name)
+	return newBucket(singleton(), name)
+}
+
+// RegisterHook registers a hook that's called around every operation
+// against the app's buckets, such as for auditing or cost tracking.
+//
+// Hooks are called once per operation, outside of any retries. The Before
+// functions are called in the order the hooks were registered, and the After
+// functions in reverse order, so hooks registered first wrap those registered
+// later. If a Before function rejects an operation, only the After functions of
+// the hooks before it are called.
+//
+// For uploads the operation completes when the Writer is closed or aborted.
+// For downloads it completes once the download has started; errors reading
+// the content are reported by the Reader. For listing it completes when the
+// iteration stops.
+//
+// Hooks should be registered during initialization, such as in an init function.
+func RegisterHook(hook Hook) {
+	singleton().registerHook(hook)
 }
 
 // constStr is a string that can only be provided as a constant.
@@ -18,5 +37,5 @@ type constStr string
 //
 // The name must be a string literal constant, to facilitate static analysis.
 func Named(name constStr) *Bucket {
-	return newBucket(Singleton, string(name))
+	return newBucket(singleton(), string(name))
 }
@@ -0,0 +1,45 @@
+package objects
+
+import (
+	"context"
+	"time"
+
+	"encore.dev/storage/objects/internal/transport"
+)
+
+// do runs a single operation against the bucket.
+//
+// It runs any registered hooks around the operation, retries the operation
+// with backoff if the provider throttles it, and reports deadline errors
+// as ErrOperationTimeout.
+func (b *Bucket) do(ctx context.Context, op, object string, fn func() error) error {
+	return b.doRetries(ctx, op, object, throttleMaxRetries, fn)
+}
+
+// doRetries is like do, but retries the operation at most maxRetries times
+// if it's throttled. It's used for operations that can't always be retried.
+func (b *Bucket) doRetries(ctx context.Context, op, object string, maxRetries int, fn func() error) (err error) {
+	hooks, err := b.startOperation(ctx, op, object)
+	defer func() { hooks.end(err) }()
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		if err := b.throttle.wait(ctx); err != nil {
+			return mapTimeout(ctx, op, start, err)
+		}
+
+		if attempt > 1 {
+			if stats := transport.StatsFrom(ctx); stats != nil {
+				stats.Retries.Add(1)
+			}
+		}
+
+		err := fn()
+		if !b.observeThrottle(op, attempt, err) || attempt > maxRetries {
+			return mapTimeout(ctx, op, start, err)
+		}
+	}
+}
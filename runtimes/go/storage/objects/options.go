@@ -1,6 +1,9 @@
 package objects
 
 import (
+	"fmt"
+	"maps"
+	"strings"
 	"time"
 
 	"encore.dev/storage/objects/internal/types"
@@ -37,6 +40,9 @@ func (o withVersionOption) attrsOption() {}
 //publicapigen:keep
 func (o withVersionOption) existsOption() {}
 
+//publicapigen:keep
+func (o withVersionOption) waitOption() {}
+
 //publicapigen:keep
 func (o withTTLOption) uploadURLOption() {}
 
@@ -47,6 +53,7 @@ func (o withVersionOption) applyDownload(opts *downloadOptions)   { opts.version
 func (o withVersionOption) applyRemove(opts *removeOptions)       { opts.version = o.version }
 func (o withVersionOption) applyAttrs(opts *attrsOptions)         { opts.version = o.version }
 func (o withVersionOption) applyExists(opts *existsOptions)       { opts.version = o.version }
+func (o withVersionOption) applyWait(opts *waitOptions)           { opts.version = o.version }
 func (o withTTLOption) applyUploadURL(opts *uploadURLOptions)     { opts.TTL = o.TTL }
 func (o withTTLOption) applyDownloadURL(opts *downloadURLOptions) { opts.TTL = o.TTL }
 
@@ -62,9 +69,54 @@ type withTTLOption struct {
 	TTL time.Duration
 }
 
+// WithRawContent is a DownloadOption for downloading the object exactly as stored.
+//
+// By default, objects stored with a gzip or deflate content encoding
+// are transparently decompressed while downloading.
+func WithRawContent() withRawContentOption {
+	return withRawContentOption{}
+}
+
+//publicapigen:keep
+type withRawContentOption struct{}
+
+//publicapigen:keep
+func (o withRawContentOption) downloadOption() {}
+
+func (o withRawContentOption) applyDownload(opts *downloadOptions) { opts.raw = true }
+
+// WithRange is a DownloadOption for downloading only the bytes in the range
+// [start, end) of the object. If end is negative the range extends to the end
+// of the object, and an end beyond the end of the object is truncated to it.
+//
+// The range is fetched from the provider, so the rest of the object isn't read.
+// It's a range of the object's content as stored, so objects with a content
+// encoding must be downloaded with WithRawContent. Objects encrypted with
+// WithEncryption can't be downloaded in part. Empty ranges and ranges starting
+// beyond the end of the object return ErrInvalidArgument.
+func WithRange(start, end int64) withRangeOption {
+	return withRangeOption{start: start, end: end}
+}
+
+//publicapigen:keep
+type withRangeOption struct {
+	start, end int64
+}
+
+//publicapigen:keep
+func (o withRangeOption) downloadOption() {}
+
+func (o withRangeOption) applyDownload(opts *downloadOptions) {
+	opts.byteRange = &types.ByteRange{Start: o.start, End: o.end}
+}
+
 //publicapigen:keep
 type downloadOptions struct {
-	version string
+	version   string
+	raw       bool
+	stats     *TransferStats
+	byteRange *types.ByteRange
+	checksum  ChecksumAlgorithm
 }
 
 // UploadOption describes available options for the Upload operation.
@@ -118,15 +170,314 @@ type withUploadAttrsOption struct {
 //publicapigen:keep
 func (o withUploadAttrsOption) uploadOption() {}
 
+//publicapigen:keep
+func (o withUploadAttrsOption) multipartUploadOption() {}
+
 func (o withUploadAttrsOption) applyUpload(opts *uploadOptions) {
-	opts.attrs = types.UploadAttrs{
-		ContentType: o.attrs.ContentType,
+	opts.attrs.ContentType = o.attrs.ContentType
+}
+
+func (o withUploadAttrsOption) applyMultipartUpload(opts *multipartUploadOptions) {
+	opts.attrs.ContentType = o.attrs.ContentType
+}
+
+// WithMetadata is an UploadOption for storing user-defined metadata with the object.
+// The metadata is returned in ObjectAttrs and ListEntry.
+//
+// Keys must not start with "encore-", which is reserved. Providers may
+// treat keys as case-insensitive: S3 returns them in lowercase.
+func WithMetadata(md map[string]string) withMetadataOption {
+	return withMetadataOption{md: md}
+}
+
+//publicapigen:keep
+type withMetadataOption struct {
+	md map[string]string
+}
+
+//publicapigen:keep
+func (o withMetadataOption) uploadOption() {}
+
+//publicapigen:keep
+func (o withMetadataOption) multipartUploadOption() {}
+
+func (o withMetadataOption) applyUpload(opts *uploadOptions) {
+	opts.attrs.UserMetadata = maps.Clone(o.md)
+}
+
+func (o withMetadataOption) applyMultipartUpload(opts *multipartUploadOptions) {
+	opts.attrs.UserMetadata = maps.Clone(o.md)
+}
+
+// WithTags is an UploadOption for tagging the object.
+//
+// On providers with object tags (S3) the tags are set on the object,
+// where they can be used by lifecycle rules and access policies.
+// They're also stored with the object's metadata, and are returned
+// in ObjectAttrs and ListEntry on all providers.
+func WithTags(tags map[string]string) withTagsOption {
+	return withTagsOption{tags: tags}
+}
+
+//publicapigen:keep
+type withTagsOption struct {
+	tags map[string]string
+}
+
+//publicapigen:keep
+func (o withTagsOption) uploadOption() {}
+
+//publicapigen:keep
+func (o withTagsOption) multipartUploadOption() {}
+
+func (o withTagsOption) applyUpload(opts *uploadOptions) {
+	opts.attrs.Tags = maps.Clone(o.tags)
+}
+
+func (o withTagsOption) applyMultipartUpload(opts *multipartUploadOptions) {
+	opts.attrs.Tags = maps.Clone(o.tags)
+}
+
+// checkUploadAttrs checks the user-defined metadata and tags are valid.
+func checkUploadAttrs(attrs types.UploadAttrs) error {
+	for k := range attrs.UserMetadata {
+		if k == "" || strings.HasPrefix(strings.ToLower(k), types.ReservedMetadataPrefix) {
+			return fmt.Errorf("%w: invalid metadata key %q", ErrInvalidArgument, k)
+		}
+	}
+	for k := range attrs.Tags {
+		if k == "" {
+			return fmt.Errorf("%w: empty tag key", ErrInvalidArgument)
+		}
 	}
+	return nil
+}
+
+// WithSizeHint is an UploadOption for specifying the total size of the object
+// being uploaded, in bytes, if it's known in advance.
+//
+// It's used to pick a suitable part size for large uploads.
+func WithSizeHint(size int64) withSizeHintOption {
+	return withSizeHintOption{size: size}
+}
+
+//publicapigen:keep
+type withSizeHintOption struct {
+	size int64
+}
+
+//publicapigen:keep
+func (o withSizeHintOption) uploadOption() {}
+
+func (o withSizeHintOption) applyUpload(opts *uploadOptions) {
+	opts.size = o.size
+}
+
+// WithPartSize is an UploadOption for overriding the size of each part,
+// in bytes, when the object is uploaded in multiple parts.
+//
+// The size is adjusted to the limits of the provider, and increased if needed
+// to fit an object of the size given by WithSizeHint.
+// By default the part size is chosen automatically.
+//
+// It's also a MultipartUploadOption, declaring the size of the parts
+// that will be passed to UploadPart.
+func WithPartSize(size int64) withPartSizeOption {
+	return withPartSizeOption{size: size}
+}
+
+//publicapigen:keep
+type withPartSizeOption struct {
+	size int64
+}
+
+//publicapigen:keep
+func (o withPartSizeOption) uploadOption() {}
+
+//publicapigen:keep
+func (o withPartSizeOption) multipartUploadOption() {}
+
+func (o withPartSizeOption) applyUpload(opts *uploadOptions) {
+	opts.partSize = o.size
+}
+
+func (o withPartSizeOption) applyMultipartUpload(opts *multipartUploadOptions) {
+	opts.partSize = o.size
+}
+
+// WithIdempotencyKey is an UploadOption for making retried uploads idempotent.
+//
+// The key is stored with the object. If the object already exists and was
+// uploaded with the same key, the upload is skipped and the existing object
+// is kept, so retrying an upload that already succeeded has no effect.
+// Use a key that identifies the logical upload, like an ID derived from
+// the request that triggered it, so that retries reuse the same key.
+//
+// Unless the upload has other preconditions, it's also made conditional on no
+// other upload completing first, so concurrent retries only write the object once.
+func WithIdempotencyKey(key string) withIdempotencyKeyOption {
+	return withIdempotencyKeyOption{key: key}
+}
+
+//publicapigen:keep
+type withIdempotencyKeyOption struct {
+	key string
+}
+
+//publicapigen:keep
+func (o withIdempotencyKeyOption) uploadOption() {}
+
+func (o withIdempotencyKeyOption) applyUpload(opts *uploadOptions) {
+	opts.idempotencyKey = o.key
+}
+
+// WithEncryption is an UploadOption for encrypting the object before it's
+// uploaded, using the encryption key configured for the bucket.
+//
+// The object is encrypted with a data key generated for it, which is stored
+// with the object wrapped (encrypted) by the bucket's key. Downloading the
+// object decrypts it transparently.
+//
+// If the bucket has no encryption key configured, the upload fails.
+func WithEncryption() withEncryptionOption {
+	return withEncryptionOption{}
+}
+
+//publicapigen:keep
+type withEncryptionOption struct{}
+
+//publicapigen:keep
+func (o withEncryptionOption) uploadOption() {}
+
+func (o withEncryptionOption) applyUpload(opts *uploadOptions) {
+	opts.encrypt = true
+}
+
+// WithKMSKey is an UploadOption for encrypting the object server-side with a
+// customer-managed KMS key: SSE-KMS on S3, and a Cloud KMS key (CMEK) on GCS.
+// It's also a MultipartUploadOption.
+//
+// The key is referred to by the name it's configured with for the bucket in
+// the infrastructure config, so the application doesn't depend on key IDs.
+// The upload fails with ErrInvalidArgument if the bucket has no key with
+// that name. When running locally it has no effect.
+//
+// Only the uploaded object is encrypted with the key: copies of it are
+// encrypted with the destination bucket's default key.
+func WithKMSKey(keyRef string) withKMSKeyOption {
+	return withKMSKeyOption{ref: keyRef}
+}
+
+//publicapigen:keep
+type withKMSKeyOption struct {
+	ref string
+}
+
+//publicapigen:keep
+func (o withKMSKeyOption) uploadOption() {}
+
+//publicapigen:keep
+func (o withKMSKeyOption) multipartUploadOption() {}
+
+func (o withKMSKeyOption) applyUpload(opts *uploadOptions) {
+	opts.kmsKey = o.ref
+}
+
+func (o withKMSKeyOption) applyMultipartUpload(opts *multipartUploadOptions) {
+	opts.kmsKey = o.ref
+}
+
+// WithChecksum is an UploadOption and DownloadOption for choosing the algorithm
+// the content is checksummed with. It defaults to ChecksumCRC32C.
+//
+// Uploads and downloads are always checksummed, and the checksum is verified
+// against the one the provider has for the object when possible, returning
+// ErrChecksumMismatch if they differ. Downloads are only verified when the
+// object is read in full.
+func WithChecksum(alg ChecksumAlgorithm) withChecksumOption {
+	return withChecksumOption{alg: alg}
+}
+
+//publicapigen:keep
+type withChecksumOption struct {
+	alg ChecksumAlgorithm
+}
+
+//publicapigen:keep
+func (o withChecksumOption) uploadOption() {}
+
+//publicapigen:keep
+func (o withChecksumOption) downloadOption() {}
+
+func (o withChecksumOption) applyUpload(opts *uploadOptions) {
+	opts.checksum = o.alg
+}
+
+func (o withChecksumOption) applyDownload(opts *downloadOptions) {
+	opts.checksum = o.alg
+}
+
+// WithTransferStats is an UploadOption and DownloadOption that reports the
+// requests the operation made to the provider, and the bytes it transferred,
+// in stats. It's set once the operation completes: when the Writer is closed
+// or aborted, or when the Reader is closed.
+//
+// It's useful for attributing storage costs to individual operations.
+func WithTransferStats(stats *TransferStats) withTransferStatsOption {
+	return withTransferStatsOption{stats: stats}
+}
+
+//publicapigen:keep
+type withTransferStatsOption struct {
+	stats *TransferStats
+}
+
+//publicapigen:keep
+func (o withTransferStatsOption) uploadOption() {}
+
+//publicapigen:keep
+func (o withTransferStatsOption) downloadOption() {}
+
+func (o withTransferStatsOption) applyUpload(opts *uploadOptions) {
+	opts.stats = o.stats
+}
+
+func (o withTransferStatsOption) applyDownload(opts *downloadOptions) {
+	opts.stats = o.stats
 }
 
 type uploadOptions struct {
-	attrs types.UploadAttrs
-	pre   Preconditions
+	attrs          types.UploadAttrs
+	pre            Preconditions
+	size           int64
+	partSize       int64
+	idempotencyKey string
+	encrypt        bool
+	kmsKey         string // reference to the KMS key, from WithKMSKey
+	checksum       ChecksumAlgorithm
+	stats          *TransferStats
+
+	// match, if set, requires the object to currently have these attributes.
+	// It's used internally for read-modify-write operations.
+	match *types.ObjectAttrs
+
+	// noQuota, if set, doesn't count the upload towards the bucket's quota.
+	// It's used internally when moving objects within the bucket.
+	noQuota bool
+}
+
+// MultipartUploadOption describes available options for the BeginMultipartUpload operation.
+type MultipartUploadOption interface {
+	//publicapigen:keep
+	multipartUploadOption()
+
+	applyMultipartUpload(*multipartUploadOptions)
+}
+
+type multipartUploadOptions struct {
+	attrs    types.UploadAttrs
+	partSize int64
+	kmsKey   string // reference to the KMS key, from WithKMSKey
 }
 
 // ListOption describes available options for the List operation.
@@ -137,7 +488,228 @@ type ListOption interface {
 	applyList(*listOptions)
 }
 
-type listOptions struct{}
+type listOptions struct {
+	trashed  bool
+	pageSize int64
+}
+
+// WithTrashed is a ListOption for including objects in the trash
+// when soft delete is enabled for the bucket. By default they're
+// only listed when listing a prefix within the trash.
+func WithTrashed() withTrashedOption {
+	return withTrashedOption{}
+}
+
+//publicapigen:keep
+type withTrashedOption struct{}
+
+//publicapigen:keep
+func (o withTrashedOption) listOption() {}
+
+func (o withTrashedOption) applyList(opts *listOptions) { opts.trashed = true }
+
+// WithPageSize is a ListOption for the number of entries to list per request
+// to the provider, or per page with ListPage. It defaults to 1000,
+// which is also the most providers return per request.
+func WithPageSize(n int) withPageSizeOption {
+	return withPageSizeOption{n: n}
+}
+
+//publicapigen:keep
+type withPageSizeOption struct {
+	n int
+}
+
+//publicapigen:keep
+func (o withPageSizeOption) listOption() {}
+
+func (o withPageSizeOption) applyList(opts *listOptions) { opts.pageSize = int64(o.n) }
+
+// ForEachOption describes available options for the ForEach operation.
+type ForEachOption interface {
+	//publicapigen:keep
+	forEachOption()
+
+	applyForEach(*forEachOptions)
+}
+
+// WithStopOnError is a ForEachOption for canceling the remaining work
+// as soon as processing an object fails.
+func WithStopOnError() withStopOnErrorOption {
+	return withStopOnErrorOption{}
+}
+
+//publicapigen:keep
+type withStopOnErrorOption struct{}
+
+//publicapigen:keep
+func (o withStopOnErrorOption) forEachOption() {}
+
+func (o withStopOnErrorOption) applyForEach(opts *forEachOptions) { opts.stopOnError = true }
+
+type forEachOptions struct {
+	stopOnError bool
+}
+
+// VerifyOption describes available options for the Verify operation.
+type VerifyOption interface {
+	//publicapigen:keep
+	verifyOption()
+
+	applyVerify(*verifyOptions)
+}
+
+// WithRedownload is a VerifyOption for downloading each object
+// to verify its contents, rather than only checking its attributes.
+func WithRedownload() withRedownloadOption {
+	return withRedownloadOption{}
+}
+
+//publicapigen:keep
+type withRedownloadOption struct{}
+
+//publicapigen:keep
+func (o withRedownloadOption) verifyOption() {}
+
+func (o withRedownloadOption) applyVerify(opts *verifyOptions) { opts.redownload = true }
+
+type verifyOptions struct {
+	redownload bool
+}
+
+// MigrateOption describes available options for Migrate.
+type MigrateOption interface {
+	//publicapigen:keep
+	migrateOption()
+
+	applyMigrate(*migrateOptions)
+}
+
+// WithConcurrency is a MigrateOption for setting how many objects
+// are copied concurrently. It defaults to 8.
+func WithConcurrency(n int) withConcurrencyOption {
+	return withConcurrencyOption{n: n}
+}
+
+//publicapigen:keep
+type withConcurrencyOption struct {
+	n int
+}
+
+//publicapigen:keep
+func (o withConcurrencyOption) migrateOption() {}
+
+func (o withConcurrencyOption) applyMigrate(opts *migrateOptions) { opts.concurrency = o.n }
+
+// WithProgress is a MigrateOption for reporting progress.
+// The function is called after each object has been processed,
+// never concurrently, so it must not block for long.
+func WithProgress(fn func(MigrateProgress)) withProgressOption {
+	return withProgressOption{fn: fn}
+}
+
+//publicapigen:keep
+type withProgressOption struct {
+	fn func(MigrateProgress)
+}
+
+//publicapigen:keep
+func (o withProgressOption) migrateOption() {}
+
+func (o withProgressOption) applyMigrate(opts *migrateOptions) { opts.progress = o.fn }
+
+type migrateOptions struct {
+	concurrency int
+	progress    func(MigrateProgress)
+}
+
+// CopyOption describes available options for the Copy operation.
+type CopyOption interface {
+	//publicapigen:keep
+	copyOption()
+
+	applyCopy(*copyOptions)
+}
+
+// FromBucket is a CopyOption for copying an object from another bucket,
+// rather than from within the bucket being copied to.
+func FromBucket(bkt *Bucket) fromBucketOption {
+	return fromBucketOption{bkt: bkt}
+}
+
+//publicapigen:keep
+type fromBucketOption struct {
+	bkt *Bucket
+}
+
+//publicapigen:keep
+func (o fromBucketOption) copyOption() {}
+
+func (o fromBucketOption) applyCopy(opts *copyOptions) { opts.from = o.bkt }
+
+type copyOptions struct {
+	from *Bucket
+}
+
+// IndexOption describes available options for the GenerateIndex operation.
+type IndexOption interface {
+	//publicapigen:keep
+	indexOption()
+
+	applyIndex(*indexOptions)
+}
+
+// WithIndexKey is an IndexOption for setting the name of the index object.
+// It defaults to "index.json" or "index.csv" under the prefix being indexed.
+func WithIndexKey(key string) withIndexKeyOption {
+	return withIndexKeyOption{key: key}
+}
+
+//publicapigen:keep
+type withIndexKeyOption struct {
+	key string
+}
+
+//publicapigen:keep
+func (o withIndexKeyOption) indexOption() {}
+
+func (o withIndexKeyOption) applyIndex(opts *indexOptions) { opts.key = o.key }
+
+// WithIndexFormat is an IndexOption for setting the format of the index.
+// It defaults to IndexJSON.
+func WithIndexFormat(format IndexFormat) withIndexFormatOption {
+	return withIndexFormatOption{format: format}
+}
+
+//publicapigen:keep
+type withIndexFormatOption struct {
+	format IndexFormat
+}
+
+//publicapigen:keep
+func (o withIndexFormatOption) indexOption() {}
+
+func (o withIndexFormatOption) applyIndex(opts *indexOptions) { opts.format = o.format }
+
+// WithIncrementalIndex is an IndexOption for only rewriting the index
+// if the objects it lists have changed since it was generated.
+func WithIncrementalIndex() withIncrementalIndexOption {
+	return withIncrementalIndexOption{}
+}
+
+//publicapigen:keep
+type withIncrementalIndexOption struct{}
+
+//publicapigen:keep
+func (o withIncrementalIndexOption) indexOption() {}
+
+func (o withIncrementalIndexOption) applyIndex(opts *indexOptions) { opts.incremental = true }
+
+type indexOptions struct {
+	key         string
+	format      IndexFormat
+	incremental bool
+}
 
 // RemoveOption describes available options for the Remove operation.
 type RemoveOption interface {
@@ -199,6 +771,76 @@ type existsOptions struct {
 	version string
 }
 
+// WaitOption describes available options for the WaitUntilExists operation.
+type WaitOption interface {
+	//publicapigen:keep
+	waitOption()
+
+	applyWait(*waitOptions)
+}
+
+// WithPollInterval is a WaitOption for setting the initial interval
+// between checks for the object. The interval doubles after each check.
+func WithPollInterval(interval time.Duration) withPollIntervalOption {
+	return withPollIntervalOption{interval: interval}
+}
+
+//publicapigen:keep
+type withPollIntervalOption struct {
+	interval time.Duration
+}
+
+//publicapigen:keep
+func (o withPollIntervalOption) waitOption() {}
+
+func (o withPollIntervalOption) applyWait(opts *waitOptions) {
+	if o.interval > 0 {
+		opts.interval = o.interval
+	}
+}
+
+// WithMaxWait is a WaitOption for setting the maximum time to wait
+// for the object to become visible.
+func WithMaxWait(d time.Duration) withMaxWaitOption {
+	return withMaxWaitOption{d: d}
+}
+
+//publicapigen:keep
+type withMaxWaitOption struct {
+	d time.Duration
+}
+
+//publicapigen:keep
+func (o withMaxWaitOption) waitOption() {}
+
+func (o withMaxWaitOption) applyWait(opts *waitOptions) {
+	if o.d > 0 {
+		opts.maxWait = o.d
+	}
+}
+
+// WithETag is a WaitOption for waiting until the object has the given ETag.
+func WithETag(etag string) withETagOption {
+	return withETagOption{etag: etag}
+}
+
+//publicapigen:keep
+type withETagOption struct {
+	etag string
+}
+
+//publicapigen:keep
+func (o withETagOption) waitOption() {}
+
+func (o withETagOption) applyWait(opts *waitOptions) { opts.etag = o.etag }
+
+type waitOptions struct {
+	interval time.Duration
+	maxWait  time.Duration
+	etag     string
+	version  string
+}
+
 // PublicURLOption describes available options for the PublicURL operation.
 type PublicURLOption interface {
 	//publicapigen:keep
@@ -0,0 +1,79 @@
+package objects
+
+import (
+	"context"
+	"fmt"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// Page is a page of objects listed with ListPage.
+type Page struct {
+	// The entries in the page, in lexicographic order.
+	Entries []*ListEntry
+
+	// NextPageToken is the token for listing the next page,
+	// or "" if there are no more objects.
+	NextPageToken string
+}
+
+// ListPage lists a single page of objects in the bucket, for callers that
+// page through objects themselves rather than iterating over all of them,
+// such as file browsers.
+//
+// The pageToken is "" for the first page, and the NextPageToken of the
+// previous page for the pages after it. Page tokens are opaque and specific
+// to the bucket's provider. Pages have up to WithPageSize entries, and may
+// have fewer even if there are more objects, such as when skipping objects
+// in the trash. The query's Limit is not used.
+//
+// Combined with a Query.Delimiter, it lists the bucket like a directory tree.
+func (b *Bucket) ListPage(ctx context.Context, query *Query, pageToken string, options ...ListOption) (page *Page, err error) {
+	var opt listOptions
+	for _, o := range options {
+		o.applyList(&opt)
+	}
+	skipTrash := b.trashPrefix != "" && !opt.trashed && !b.inTrash(query.Prefix)
+
+	page = &Page{}
+	endTrace := b.traceList(query)
+	defer func() { endTrace(err, uint64(len(page.Entries)), page.NextPageToken != "") }()
+
+	data := b.mapQuery(ctx, query)
+	data.Limit = nil
+	data.PageSize = opt.pageSize
+	data.PageToken = pageToken
+
+	var res *types.ListPage
+	err = b.do(ctx, "list", query.Prefix, func() error {
+		pager, err := b.pagerImpl()
+		if err != nil {
+			return err
+		}
+		res, err = pager.ListPage(data)
+		return err
+	})
+	if err != nil {
+		return page, err
+	}
+
+	for _, entry := range res.Entries {
+		e := b.mapListEntry(entry)
+		if skipTrash && b.inTrash(e.Name) {
+			continue
+		}
+		page.Entries = append(page.Entries, e)
+	}
+	page.NextPageToken = res.NextPageToken
+	return page, nil
+}
+
+// pagerImpl returns the bucket's implementation of listing pages.
+// Page tokens are specific to the provider, so pages are always
+// listed from the primary bucket.
+func (b *Bucket) pagerImpl() (types.Pager, error) {
+	if p, ok := b.primaryImpl().(types.Pager); ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("%w: bucket %s doesn't support listing pages", types.ErrInvalidArgument, b.name)
+}
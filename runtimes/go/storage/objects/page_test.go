@@ -0,0 +1,134 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// pagingImpl is a multiImpl that lists pages,
+// with page tokens being the index of the page's first entry.
+type pagingImpl struct {
+	*multiImpl
+	throttle int // the number of list calls to throttle
+}
+
+func (p *pagingImpl) ListPage(data types.ListData) (*types.ListPage, error) {
+	if p.throttle > 0 {
+		p.throttle--
+		return nil, types.ErrThrottled
+	}
+	var entries []*types.ListEntry
+	for _, name := range slices.Sorted(maps.Keys(p.objects)) {
+		rest, ok := strings.CutPrefix(string(name), data.Prefix)
+		if !ok {
+			continue
+		}
+		if i := strings.Index(rest, data.Delimiter); data.Delimiter != "" && i >= 0 {
+			prefix := types.CloudObject(data.Prefix + rest[:i+len(data.Delimiter)])
+			if n := len(entries); n == 0 || entries[n-1].Object != prefix {
+				entries = append(entries, &types.ListEntry{Object: prefix, Prefix: true})
+			}
+			continue
+		}
+		entries = append(entries, &types.ListEntry{Object: name, Size: int64(len(p.objects[name].data))})
+	}
+
+	start, _ := strconv.Atoi(data.PageToken)
+	end := min(start+int(data.PageSize), len(entries))
+	page := &types.ListPage{Entries: entries[start:end]}
+	if end < len(entries) {
+		page.NextPageToken = strconv.Itoa(end)
+	}
+	return page, nil
+}
+
+func newPagingTestBucket() *Bucket {
+	impl := &pagingImpl{multiImpl: &multiImpl{objects: map[types.CloudObject]*multiObject{
+		"a.txt":       {data: []byte("a")},
+		"b/c.txt":     {data: []byte("c")},
+		"b/d/e.txt":   {data: []byte("e")},
+		"f.txt":       {data: []byte("f")},
+		"g/h.txt":     {data: []byte("h")},
+		".trash/x/1":  {data: []byte("x")},
+		"b/.trash/y":  {data: []byte("y")},
+		"b/zzz/a.txt": {data: []byte("z")},
+	}}}
+	bkt := newTestBucket(impl)
+	bkt.trashPrefix = defaultTrashPrefix
+	return bkt
+}
+
+func TestListPage(t *testing.T) {
+	bkt := newPagingTestBucket()
+	ctx := context.Background()
+
+	type entry struct {
+		name     string
+		isPrefix bool
+	}
+	listAll := func(query *Query, options ...ListOption) (pages [][]entry) {
+		t.Helper()
+		token := ""
+		for {
+			page, err := bkt.ListPage(ctx, query, token, options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var entries []entry
+			for _, e := range page.Entries {
+				entries = append(entries, entry{e.Name, e.IsPrefix})
+			}
+			pages = append(pages, entries)
+			if token = page.NextPageToken; token == "" {
+				return pages
+			}
+		}
+	}
+
+	// The trash is skipped, leaving the first page short.
+	got := listAll(&Query{Delimiter: "/"}, WithPageSize(2))
+	want := [][]entry{
+		{{"a.txt", false}},
+		{{"b/", true}, {"f.txt", false}},
+		{{"g/", true}},
+	}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("got pages %v, want %v", got, want)
+	}
+
+	got = listAll(&Query{Prefix: "b/", Delimiter: "/"}, WithPageSize(10))
+	want = [][]entry{
+		{{"b/.trash/", true}, {"b/c.txt", false}, {"b/d/", true}, {"b/zzz/", true}},
+	}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("got pages %v, want %v", got, want)
+	}
+}
+
+func TestListPage_Throttled(t *testing.T) {
+	bkt := newPagingTestBucket()
+	bkt.impl.(*pagingImpl).throttle = 1
+
+	// Throttled listings are retried like other operations.
+	page, err := bkt.ListPage(context.Background(), &Query{Prefix: "g/"}, "", WithPageSize(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Entries) != 1 || page.Entries[0].Name != "g/h.txt" {
+		t.Errorf("got entries %v, want [g/h.txt]", page.Entries)
+	}
+}
+
+func TestListPage_Unsupported(t *testing.T) {
+	bkt, _ := newTrashTestBucket()
+	if _, err := bkt.ListPage(context.Background(), &Query{}, ""); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("got err %v, want ErrInvalidArgument", err)
+	}
+}
@@ -4,13 +4,16 @@ package objects
 
 import (
 	"context"
+	"net/http"
+
+	"github.com/rs/zerolog"
 
 	"encore.dev/appruntime/exported/config"
 	"encore.dev/storage/objects/internal/providers/gcs"
 )
 
 func init() {
-	registerProvider(func(ctx context.Context, runtimeCfg *config.Runtime) provider {
-		return gcs.NewManager(ctx, runtimeCfg)
+	registerProvider(func(ctx context.Context, runtimeCfg *config.Runtime, transport http.RoundTripper, logger zerolog.Logger) provider {
+		return gcs.NewManager(ctx, runtimeCfg, transport)
 	})
 }
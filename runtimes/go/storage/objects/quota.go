@@ -0,0 +1,127 @@
+package objects
+
+import (
+	"fmt"
+	"sync"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// QuotaUsage describes the bytes uploaded to a bucket by this process.
+type QuotaUsage struct {
+	// Used is the number of bytes uploaded by completed uploads.
+	Used int64
+
+	// Pending is the number of bytes written by uploads still in progress.
+	// They count towards the quota until the upload completes or fails.
+	Pending int64
+
+	// Limit is the upload quota, or 0 if the bucket has none.
+	Limit int64
+}
+
+// QuotaUsage reports the bytes uploaded to the bucket by this process,
+// and the bucket's upload quota if one is configured.
+//
+// Usage is tracked per process, across all uploads made
+// through Upload (including UploadDeduplicated, RemoveRange and Truncate).
+// Uploads made by clients using signed upload URLs are not included, and neither
+// are objects moved to and restored from the trash with soft delete.
+func (b *Bucket) QuotaUsage() QuotaUsage {
+	return b.quota.usage()
+}
+
+// quota tracks the bytes uploaded to a bucket against its upload quota.
+//
+// Bytes are reserved as they're written to a Writer and only become used once
+// the upload completes, so failed and aborted uploads don't count towards the
+// quota. Since bytes are counted as written by the application, retries of
+// individual requests and the parts of multipart uploads are counted once.
+type quota struct {
+	bucket string
+	limit  int64 // 0 means unlimited
+
+	mu       sync.Mutex
+	used     int64
+	reserved int64
+}
+
+// quotaFor returns the quota tracker for the given bucket,
+// so that it's shared by all Bucket instances with the same name.
+func (mgr *Manager) quotaFor(bucket string, limit int64) *quota {
+	mgr.quotasMu.Lock()
+	defer mgr.quotasMu.Unlock()
+	if mgr.quotas == nil {
+		mgr.quotas = make(map[string]*quota)
+	}
+	q, ok := mgr.quotas[bucket]
+	if !ok {
+		q = &quota{bucket: bucket, limit: max(limit, 0)}
+		mgr.quotas[bucket] = q
+	}
+	return q
+}
+
+// check reports an error if n more bytes would exceed the quota,
+// without reserving them.
+func (q *quota) check(n int64) error {
+	if q == nil {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.exceeded(n)
+}
+
+// reserve reserves n bytes for an upload in progress,
+// or returns an error matching ErrQuotaExceeded if there's not enough left.
+func (q *quota) reserve(n int64) error {
+	if q == nil {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.exceeded(n); err != nil {
+		return err
+	}
+	q.reserved += n
+	return nil
+}
+
+// commit marks n reserved bytes as used, once their upload has completed.
+func (q *quota) commit(n int64) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.reserved -= n
+	q.used += n
+}
+
+// release releases n reserved bytes, when their upload failed.
+func (q *quota) release(n int64) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.reserved -= n
+}
+
+func (q *quota) exceeded(n int64) error {
+	if q.limit > 0 && q.used+q.reserved+n > q.limit {
+		return fmt.Errorf("%w: bucket %s: uploading %d more bytes would exceed the quota of %d bytes (%d used, %d pending)",
+			types.ErrQuotaExceeded, q.bucket, n, q.limit, q.used, q.reserved)
+	}
+	return nil
+}
+
+func (q *quota) usage() QuotaUsage {
+	if q == nil {
+		return QuotaUsage{}
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return QuotaUsage{Used: q.used, Pending: q.reserved, Limit: q.limit}
+}
@@ -0,0 +1,94 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestQuota(t *testing.T) {
+	impl := &memImpl{data: []byte("data")}
+	bkt := newTestBucket(impl)
+	bkt.quota = bkt.mgr.quotaFor("test", 10)
+	ctx := context.Background()
+
+	upload := func(data string, options ...UploadOption) error {
+		w := bkt.Upload(ctx, "obj", options...)
+		if _, err := w.Write([]byte(data)); err != nil {
+			w.Abort(err)
+			return err
+		}
+		return w.Close()
+	}
+	checkUsage := func(want QuotaUsage) {
+		t.Helper()
+		if got := bkt.QuotaUsage(); got != want {
+			t.Errorf("got usage %+v, want %+v", got, want)
+		}
+	}
+
+	if err := upload("123456"); err != nil {
+		t.Fatal(err)
+	}
+	checkUsage(QuotaUsage{Used: 6, Limit: 10})
+
+	// Aborted uploads don't count towards the quota.
+	w := bkt.Upload(ctx, "obj")
+	_, _ = w.Write([]byte("12"))
+	checkUsage(QuotaUsage{Used: 6, Pending: 2, Limit: 10})
+	w.Abort(nil)
+	checkUsage(QuotaUsage{Used: 6, Limit: 10})
+
+	// Exceeding the quota fails the upload without completing it.
+	if err := upload("12345"); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("got err %v, want ErrQuotaExceeded", err)
+	}
+	if got := string(impl.data); got != "123456" {
+		t.Errorf("got object %q, want it unchanged", got)
+	}
+	checkUsage(QuotaUsage{Used: 6, Limit: 10})
+
+	// Closing the writer after a rejected write doesn't complete the upload.
+	w = bkt.Upload(ctx, "obj")
+	if _, err := w.Write([]byte("12345")); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("got err %v, want ErrQuotaExceeded", err)
+	}
+	if err := w.Close(); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("got err %v, want ErrQuotaExceeded", err)
+	}
+	if got := string(impl.data); got != "123456" {
+		t.Errorf("got object %q, want it unchanged", got)
+	}
+
+	// A size hint beyond the quota fails before uploading anything.
+	if err := upload("1", WithSizeHint(5)); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("got err %v, want ErrQuotaExceeded", err)
+	}
+
+	if err := upload("1234"); err != nil {
+		t.Fatal(err)
+	}
+	checkUsage(QuotaUsage{Used: 10, Limit: 10})
+
+	// The quota is shared by all instances of the bucket.
+	other := newTestBucket(impl)
+	other.mgr = bkt.mgr
+	other.quota = bkt.mgr.quotaFor("test", 10)
+	if got := other.QuotaUsage(); got.Used != 10 {
+		t.Errorf("got usage %+v for other instance, want 10 bytes used", got)
+	}
+}
+
+func TestQuota_Unlimited(t *testing.T) {
+	bkt := newTestBucket(&memImpl{})
+	bkt.quota = bkt.mgr.quotaFor("test", 0)
+
+	w := bkt.Upload(context.Background(), "obj")
+	_, _ = w.Write(make([]byte, 1<<20))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := bkt.QuotaUsage(), (QuotaUsage{Used: 1 << 20}); got != want {
+		t.Errorf("got usage %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,40 @@
+package objects
+
+import (
+	"context"
+	"fmt"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// resolveRange resolves the range of a download requested with WithRange
+// against the object's attributes, truncating it to the size of the object.
+//
+// Only ranges of content that is returned as stored can be downloaded, so it
+// returns ErrInvalidArgument for encrypted objects and, unless downloading the
+// raw content, objects with a content encoding.
+func (b *Bucket) resolveRange(ctx context.Context, object string, opt downloadOptions) (*types.ByteRange, error) {
+	attrs, err := b.impl.Attrs(types.AttrsData{
+		Ctx:     ctx,
+		Object:  b.toCloudObject(object),
+		Version: opt.version,
+	})
+	if err != nil {
+		return nil, err
+	} else if attrs.Encryption != nil {
+		return nil, fmt.Errorf("%w: encrypted objects cannot be downloaded in part", types.ErrInvalidArgument)
+	} else if attrs.ContentEncoding != "" && !opt.raw {
+		return nil, fmt.Errorf("%w: objects with content encoding %q must be downloaded with WithRawContent to download a range",
+			types.ErrInvalidArgument, attrs.ContentEncoding)
+	}
+
+	start, end := opt.byteRange.Start, opt.byteRange.End
+	if end < 0 || end > attrs.Size {
+		end = attrs.Size
+	}
+	if start < 0 || start >= end {
+		return nil, fmt.Errorf("%w: range [%d, %d) outside of object of size %d",
+			types.ErrInvalidArgument, opt.byteRange.Start, opt.byteRange.End, attrs.Size)
+	}
+	return &types.ByteRange{Start: start, End: end}, nil
+}
@@ -0,0 +1,53 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+func TestDownloadRange(t *testing.T) {
+	impl := &multiImpl{objects: map[types.CloudObject]*multiObject{
+		"obj":       {data: []byte("0123456789")},
+		"encrypted": {data: []byte("0123456789"), attrs: types.UploadAttrs{Encryption: &types.Encryption{KeyID: "key-1"}}},
+	}}
+	bkt := newTestBucket(impl)
+
+	tests := []struct {
+		name       string
+		object     string
+		start, end int64
+		want       string
+		wantErr    error
+	}{
+		{name: "middle", object: "obj", start: 2, end: 5, want: "234"},
+		{name: "to_end", object: "obj", start: 7, end: -1, want: "789"},
+		{name: "past_end", object: "obj", start: 7, end: 100, want: "789"},
+		{name: "empty", object: "obj", start: 4, end: 4, wantErr: ErrInvalidArgument},
+		{name: "outside", object: "obj", start: 10, end: -1, wantErr: ErrInvalidArgument},
+		{name: "negative", object: "obj", start: -1, end: 5, wantErr: ErrInvalidArgument},
+		{name: "encrypted", object: "encrypted", start: 0, end: 5, wantErr: ErrInvalidArgument},
+		{name: "missing", object: "missing", start: 0, end: 5, wantErr: ErrObjectNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := bkt.Download(context.Background(), tt.object, WithRange(tt.start, tt.end))
+			defer func() { _ = r.Close() }()
+			data, err := io.ReadAll(r)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			} else if string(data) != tt.want {
+				t.Errorf("got content %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package objects
 
 import (
 	"context"
+	"io"
 	"iter"
 	"net/url"
 )
@@ -40,6 +41,21 @@ type Uploader interface {
 	// Upload begins uploading an object to the bucket.
 	Upload(ctx context.Context, object string, options ...UploadOption) *Writer
 
+	// BeginMultipartUpload begins uploading an object to the bucket in multiple parts.
+	BeginMultipartUpload(ctx context.Context, object string, options ...MultipartUploadOption) (*MultipartUpload, error)
+
+	// UploadPart uploads a part of a multipart upload.
+	UploadPart(ctx context.Context, upload *MultipartUpload, partNumber int, data io.Reader, size int64) (*UploadedPart, error)
+
+	// UploadedParts lists the uploaded parts of a multipart upload.
+	UploadedParts(ctx context.Context, upload *MultipartUpload) ([]UploadedPart, error)
+
+	// CompleteMultipartUpload completes a multipart upload, creating the object.
+	CompleteMultipartUpload(ctx context.Context, upload *MultipartUpload, parts []UploadedPart) (*ObjectAttrs, error)
+
+	// AbortMultipartUpload aborts a multipart upload.
+	AbortMultipartUpload(ctx context.Context, upload *MultipartUpload) error
+
 	perms()
 }
 
@@ -119,6 +135,9 @@ type Lister interface {
 	// List lists objects in the bucket.
 	List(ctx context.Context, query *Query, options ...ListOption) iter.Seq2[*ListEntry, error]
 
+	// ForEach lists objects in the bucket and processes them concurrently.
+	ForEach(ctx context.Context, query *Query, concurrency int, fn func(ctx context.Context, entry *ListEntry) error, options ...ForEachOption) error
+
 	perms()
 }
 
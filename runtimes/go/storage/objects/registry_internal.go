@@ -2,6 +2,9 @@ package objects
 
 import (
 	"context"
+	"net/http"
+
+	"github.com/rs/zerolog"
 
 	"encore.dev/appruntime/exported/config"
 	"encore.dev/storage/objects/internal/types"
@@ -13,8 +16,24 @@ type provider interface {
 	NewBucket(providerCfg *config.BucketProvider, runtimeCfg *config.Bucket) types.BucketImpl
 }
 
-var providerRegistry []func(context.Context, *config.Runtime) provider
+// endpointChecker is implemented by providers that can check
+// that the endpoints they're configured with are reachable.
+type endpointChecker interface {
+	CheckEndpoints(ctx context.Context) error
+}
+
+// credentialChecker is implemented by providers that can check
+// that the credentials they're configured with are usable.
+type credentialChecker interface {
+	CheckCredentials(ctx context.Context) error
+}
+
+// providerFactory creates a provider. The transport is shared by all providers,
+// and is nil if the providers should use their default transport.
+type providerFactory func(ctx context.Context, runtimeCfg *config.Runtime, transport http.RoundTripper, logger zerolog.Logger) provider
+
+var providerRegistry []providerFactory
 
-func registerProvider(p func(context.Context, *config.Runtime) provider) {
+func registerProvider(p providerFactory) {
 	providerRegistry = append(providerRegistry, p)
 }
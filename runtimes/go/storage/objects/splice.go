@@ -0,0 +1,109 @@
+package objects
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// RemoveRange removes the bytes in the range [start, end) from an object,
+// shifting any subsequent bytes down.
+//
+// Object stores don't support modifying objects in place, so this rewrites
+// the whole object: it's downloaded, the range is spliced out as the data is
+// streamed back, and the result is uploaded in its place. The cost is therefore
+// proportional to the size of the object, not the size of the range.
+//
+// The upload only succeeds if the object hasn't changed since it was read.
+// If it was modified concurrently, RemoveRange returns ErrPreconditionFailed
+// and leaves the object untouched, and the caller may retry.
+//
+// Objects stored with a content encoding (such as gzip) or encrypted with
+// WithEncryption are not supported, and return ErrInvalidArgument,
+// as are ranges outside of the object.
+func (b *Bucket) RemoveRange(ctx context.Context, object string, start, end int64) error {
+	return b.rewrite(ctx, object, func(size int64) (int64, int64, error) {
+		if start < 0 || start > end || end > size {
+			return 0, 0, fmt.Errorf("%w: range [%d, %d) outside of object of size %d",
+				types.ErrInvalidArgument, start, end, size)
+		}
+		return start, end, nil
+	})
+}
+
+// Truncate truncates an object to the given size, in bytes.
+//
+// Like RemoveRange it rewrites the whole object, and returns
+// ErrPreconditionFailed if the object was modified concurrently.
+// Truncating an object to its current size or larger is an error.
+func (b *Bucket) Truncate(ctx context.Context, object string, size int64) error {
+	return b.rewrite(ctx, object, func(objSize int64) (int64, int64, error) {
+		if size < 0 || size >= objSize {
+			return 0, 0, fmt.Errorf("%w: cannot truncate object of size %d to %d",
+				types.ErrInvalidArgument, objSize, size)
+		}
+		return size, objSize, nil
+	})
+}
+
+// rewrite rewrites an object with the range [start, end) removed.
+// The range is computed by rangeFn from the object's current size.
+func (b *Bucket) rewrite(ctx context.Context, object string, rangeFn func(size int64) (start, end int64, err error)) error {
+	attrs, err := b.Attrs(ctx, object)
+	if err != nil {
+		return err
+	} else if attrs.ContentEncoding != "" {
+		return fmt.Errorf("%w: objects with content encoding %q cannot be modified",
+			types.ErrInvalidArgument, attrs.ContentEncoding)
+	} else if attrs.Encrypted {
+		return fmt.Errorf("%w: encrypted objects cannot be modified", types.ErrInvalidArgument)
+	}
+
+	start, end, err := rangeFn(attrs.Size)
+	if err != nil {
+		return err
+	}
+
+	r := b.Download(ctx, object, WithVersion(attrs.Version), WithRawContent())
+	defer func() { _ = r.Close() }()
+
+	w := b.Upload(ctx, object,
+		WithUploadAttrs(UploadAttrs{ContentType: attrs.ContentType}),
+		WithSizeHint(attrs.Size-(end-start)),
+		withMatchOption{attrs: &types.ObjectAttrs{Version: attrs.Version, ETag: attrs.ETag}},
+	)
+
+	// Copy the data before the range, skip the range,
+	// and then copy the remaining data.
+	err = func() error {
+		if _, err := io.CopyN(w, r, start); err != nil {
+			return err
+		} else if _, err := io.CopyN(io.Discard, r, end-start); err != nil {
+			return err
+		}
+		_, err := io.Copy(w, r)
+		return err
+	}()
+	if err != nil {
+		if rerr := r.Err(); rerr != nil {
+			err = rerr
+		}
+		w.Abort(err)
+		return err
+	}
+	return w.Close()
+}
+
+// withMatchOption is an UploadOption that requires the object
+// to currently have the given attributes.
+type withMatchOption struct {
+	attrs *types.ObjectAttrs
+}
+
+func (o withMatchOption) uploadOption() {}
+
+func (o withMatchOption) applyUpload(opts *uploadOptions) {
+	opts.match = o.attrs
+}
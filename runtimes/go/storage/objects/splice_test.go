@@ -0,0 +1,154 @@
+package objects
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"iter"
+	"strconv"
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"encore.dev/appruntime/exported/config"
+	"encore.dev/appruntime/shared/reqtrack"
+	"encore.dev/storage/objects/internal/types"
+)
+
+// memImpl is an in-memory bucket implementation supporting
+// the operations used for rewriting objects.
+type memImpl struct {
+	types.BucketImpl
+	data    []byte
+	version int
+	enc     *types.Encryption
+
+	// onDownload, if set, is called when the object is downloaded.
+	onDownload func()
+}
+
+func (m *memImpl) attrs() *types.ObjectAttrs {
+	v := strconv.Itoa(m.version)
+	return &types.ObjectAttrs{Object: "obj", Version: v, ETag: "etag-" + v, Size: int64(len(m.data)), Encryption: m.enc}
+}
+
+func (m *memImpl) Attrs(data types.AttrsData) (*types.ObjectAttrs, error) {
+	return m.attrs(), nil
+}
+
+func (m *memImpl) Download(data types.DownloadData) (types.Downloader, error) {
+	r := memDownloader{bytes.NewReader(bytes.Clone(m.data))}
+	if m.onDownload != nil {
+		m.onDownload()
+	}
+	return r, nil
+}
+
+func (m *memImpl) Upload(data types.UploadData) (types.Uploader, error) {
+	return &memUploader{m: m, pre: data.Pre, enc: data.Attrs.Encryption}, nil
+}
+
+func (m *memImpl) List(data types.ListData) iter.Seq2[*types.ListEntry, error] {
+	return func(yield func(*types.ListEntry, error) bool) {}
+}
+
+type memDownloader struct{ *bytes.Reader }
+
+func (memDownloader) Close() error            { return nil }
+func (memDownloader) ContentEncoding() string { return "" }
+
+type memUploader struct {
+	m   *memImpl
+	pre types.Preconditions
+	enc *types.Encryption
+	buf bytes.Buffer
+}
+
+func (u *memUploader) Write(p []byte) (int, error) { return u.buf.Write(p) }
+func (u *memUploader) Abort(err error)             {}
+func (u *memUploader) Complete() (*types.ObjectAttrs, error) {
+	if u.pre.MatchETag != "" && u.pre.MatchETag != u.m.attrs().ETag {
+		return nil, types.ErrPreconditionFailed
+	}
+	u.m.data = u.buf.Bytes()
+	u.m.enc = u.enc
+	u.m.version++
+	return u.m.attrs(), nil
+}
+
+func newTestBucket(impl types.BucketImpl) *Bucket {
+	return &Bucket{
+		mgr: &Manager{
+			ctx:        context.Background(),
+			static:     &config.Static{},
+			rt:         reqtrack.New(zerolog.Nop(), nil, nil),
+			rootLogger: zerolog.Nop(),
+		},
+		impl: impl,
+		name: "test",
+	}
+}
+
+func TestRemoveRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end int64
+		want       string
+		wantErr    error
+	}{
+		{name: "middle", start: 2, end: 5, want: "01567"},
+		{name: "prefix", start: 0, end: 3, want: "34567"},
+		{name: "suffix", start: 6, end: 8, want: "012345"},
+		{name: "empty", start: 4, end: 4, want: "01234567"},
+		{name: "out_of_range", start: 4, end: 9, wantErr: ErrInvalidArgument},
+		{name: "reversed", start: 4, end: 3, wantErr: ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			impl := &memImpl{data: []byte("01234567")}
+			err := newTestBucket(impl).RemoveRange(context.Background(), "obj", tt.start, tt.end)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got err %v, want %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(impl.data); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	impl := &memImpl{data: []byte("01234567")}
+	bkt := newTestBucket(impl)
+	if err := bkt.Truncate(context.Background(), "obj", 3); err != nil {
+		t.Fatal(err)
+	} else if got := string(impl.data); got != "012" {
+		t.Errorf("got %q, want %q", got, "012")
+	}
+
+	if err := bkt.Truncate(context.Background(), "obj", 3); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("truncating to the current size: got err %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestRemoveRange_ConcurrentModification(t *testing.T) {
+	impl := &memImpl{data: []byte("01234567")}
+	impl.onDownload = func() {
+		// Simulate a concurrent writer modifying the object.
+		impl.data = append(impl.data, "89"...)
+		impl.version++
+	}
+
+	err := newTestBucket(impl).RemoveRange(context.Background(), "obj", 0, 2)
+	if !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("got err %v, want ErrPreconditionFailed", err)
+	}
+	if got := string(impl.data); got != "0123456789" {
+		t.Errorf("object was clobbered: %q", got)
+	}
+}
@@ -0,0 +1,45 @@
+package objects
+
+import (
+	"encore.dev/storage/objects/internal/transport"
+)
+
+// TransferStats reports the requests an upload or download made to the
+// provider, and the bytes it transferred. See WithTransferStats.
+type TransferStats struct {
+	// BytesSent and BytesReceived are the number of bytes of content sent
+	// to and received from the provider, including that of retried requests.
+	// For encrypted or compressed objects, they're the bytes as stored.
+	BytesSent     int64
+	BytesReceived int64
+
+	// Requests is the number of requests made to the provider, including
+	// retries and each of the requests of multipart uploads.
+	Requests int
+
+	// Retries is the number of requests that retried a failed request,
+	// either by the provider's SDK or due to throttling.
+	Retries int
+}
+
+// newTransferStats returns the stats to record an operation's requests in,
+// or nil if they're not requested.
+func newTransferStats(stats *TransferStats) *transport.Stats {
+	if stats == nil {
+		return nil
+	}
+	return &transport.Stats{}
+}
+
+// report sets the stats reported to the caller from the recorded stats.
+func (s *TransferStats) report(from *transport.Stats) {
+	if s == nil || from == nil {
+		return
+	}
+	*s = TransferStats{
+		BytesSent:     from.BytesSent.Load(),
+		BytesReceived: from.BytesReceived.Load(),
+		Requests:      int(from.Requests.Load()),
+		Retries:       int(from.Retries.Load()),
+	}
+}
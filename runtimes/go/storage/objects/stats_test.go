@@ -0,0 +1,58 @@
+package objects
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"encore.dev/storage/objects/internal/transport"
+	"encore.dev/storage/objects/internal/types"
+)
+
+// statsImpl records a request for each upload and download,
+// as the counting transport does for the providers.
+type statsImpl struct {
+	*multiImpl
+}
+
+func (s statsImpl) Upload(data types.UploadData) (types.Uploader, error) {
+	if stats := transport.StatsFrom(data.Ctx); stats != nil {
+		stats.Requests.Add(1)
+		stats.BytesSent.Add(data.Size)
+	}
+	return s.multiImpl.Upload(data)
+}
+
+func (s statsImpl) Download(data types.DownloadData) (types.Downloader, error) {
+	if stats := transport.StatsFrom(data.Ctx); stats != nil {
+		stats.Requests.Add(1)
+		stats.BytesReceived.Add(int64(len(s.objects[data.Object].data)))
+	}
+	return s.multiImpl.Download(data)
+}
+
+func TestTransferStats(t *testing.T) {
+	_, impl := newTrashTestBucket()
+	bkt := newTestBucket(statsImpl{impl})
+	ctx := context.Background()
+
+	var upload TransferStats
+	w := bkt.Upload(ctx, "stats.txt", WithSizeHint(5), WithTransferStats(&upload))
+	_, _ = io.WriteString(w, "hello")
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if want := (TransferStats{BytesSent: 5, Requests: 1}); upload != want {
+		t.Errorf("got upload stats %+v, want %+v", upload, want)
+	}
+
+	var download TransferStats
+	r := bkt.Download(ctx, "stats.txt", WithTransferStats(&download))
+	if _, err := io.ReadAll(r); err != nil {
+		t.Fatal(err)
+	}
+	_ = r.Close()
+	if want := (TransferStats{BytesReceived: 5, Requests: 1}); download != want {
+		t.Errorf("got download stats %+v, want %+v", download, want)
+	}
+}
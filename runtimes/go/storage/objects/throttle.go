@@ -0,0 +1,85 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+const (
+	throttleBaseDelay  = 500 * time.Millisecond
+	throttleMaxDelay   = 30 * time.Second
+	throttleMaxRetries = 5
+)
+
+// throttler keeps track of throttling responses from the provider for a bucket.
+//
+// Throttled operations are retried with exponential backoff and jitter,
+// separately from any retries the provider SDK does for other errors.
+// While backing off, subsequent operations against the bucket are delayed
+// as well, to reduce the request rate rather than making things worse.
+type throttler struct {
+	mu          sync.Mutex
+	consecutive int       // number of consecutive throttled responses
+	until       time.Time // operations are delayed until this time
+}
+
+// wait blocks until any ongoing backoff period has passed.
+func (t *throttler) wait(ctx context.Context) error {
+	t.mu.Lock()
+	d := time.Until(t.until)
+	t.mu.Unlock()
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// observe records the outcome of an operation.
+// If the operation was throttled it reports the delay to wait before retrying.
+func (t *throttler) observe(err error) (delay time.Duration, throttled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !errors.Is(err, types.ErrThrottled) {
+		if err == nil {
+			t.consecutive = 0
+		}
+		return 0, false
+	}
+
+	t.consecutive++
+	backoff := min(throttleBaseDelay<<min(t.consecutive-1, 16), throttleMaxDelay)
+
+	// Use "equal jitter" so we always wait for at least half the backoff.
+	delay = backoff/2 + rand.N(backoff/2+1)
+	t.until = time.Now().Add(delay)
+	return delay, true
+}
+
+// observeThrottle records the outcome of an operation with the bucket's throttler,
+// logging any throttle event. It reports whether the operation was throttled.
+func (b *Bucket) observeThrottle(op string, attempt int, err error) bool {
+	delay, throttled := b.throttle.observe(err)
+	if throttled {
+		b.mgr.rootLogger.Warn().
+			Str("bucket", b.name).
+			Str("operation", op).
+			Int("attempt", attempt).
+			Dur("backoff", delay).
+			Msg("object storage request was throttled by the provider, backing off")
+	}
+	return throttled
+}
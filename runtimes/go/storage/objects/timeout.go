@@ -0,0 +1,45 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrOperationTimeout is returned when an operation does not complete
+// before the deadline of its context.
+//
+// Errors matching ErrOperationTimeout also match context.DeadlineExceeded,
+// and include the name of the operation and how long it ran for.
+var ErrOperationTimeout = errors.New("objects: operation timed out")
+
+type timeoutError struct {
+	op      string
+	elapsed time.Duration
+	err     error // the underlying error
+}
+
+func (e *timeoutError) Error() string {
+	return fmt.Sprintf("objects: %s timed out after %v: %v", e.op, e.elapsed.Round(time.Millisecond), e.err)
+}
+
+func (e *timeoutError) Is(target error) bool { return target == ErrOperationTimeout }
+func (e *timeoutError) Unwrap() error        { return e.err }
+
+// mapTimeout turns err into a timeout error if it was caused by
+// the deadline of ctx expiring. Other errors are returned as-is.
+func mapTimeout(ctx context.Context, op string, start time.Time, err error) error {
+	if err == nil || errors.Is(err, ErrOperationTimeout) {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			// Make sure the error matches context.DeadlineExceeded regardless
+			// of how the provider reported it.
+			err = fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+		}
+		return &timeoutError{op: op, elapsed: time.Since(start), err: err}
+	}
+	return err
+}
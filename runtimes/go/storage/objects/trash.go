@@ -0,0 +1,224 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// defaultTrashPrefix is the prefix removed objects are moved under
+// when soft delete is enabled without a prefix.
+const defaultTrashPrefix = ".trash/"
+
+// trashTimeFormat is the format of the removal time in the names of trashed objects.
+// It's fixed-width so that trashed copies of an object sort by removal time.
+const trashTimeFormat = "20060102T150405.000000000Z"
+
+// trashName returns the name of the trashed copy of object removed at the given time.
+func (b *Bucket) trashName(object string, at time.Time) string {
+	return b.trashPrefix + object + "/" + at.UTC().Format(trashTimeFormat)
+}
+
+// inTrash reports whether object is a trashed object.
+func (b *Bucket) inTrash(object string) bool {
+	return b.trashPrefix != "" && strings.HasPrefix(object, b.trashPrefix)
+}
+
+// parseTrashName parses the name of a trashed object into
+// the name of the removed object and when it was removed.
+func (b *Bucket) parseTrashName(name string) (object string, at time.Time, ok bool) {
+	rest, ok := strings.CutPrefix(name, b.trashPrefix)
+	if !ok {
+		return "", time.Time{}, false
+	}
+	idx := strings.LastIndexByte(rest, '/')
+	if idx < 0 {
+		return "", time.Time{}, false
+	}
+	at, err := time.Parse(trashTimeFormat, rest[idx+1:])
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return rest[:idx], at, true
+}
+
+// moveToTrash moves an object to the trash, by copying it there
+// and then removing the original. Moving an object doesn't count
+// towards the bucket's upload quota.
+func (b *Bucket) moveToTrash(ctx context.Context, object string) error {
+	attrs, err := b.Attrs(ctx, object)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	err = b.copyObject(ctx, object, b.trashName(object, now), attrs, copyObjectOptions{
+		trash:   &types.Trash{Object: b.toCloudObject(object), At: now},
+		noQuota: true,
+	})
+	if err != nil {
+		return fmt.Errorf("objects: move %s to trash: %w", object, err)
+	}
+	return b.removeNow(ctx, object, "")
+}
+
+// Restore restores an object removed while soft delete was enabled
+// for the bucket, from its most recently trashed copy.
+//
+// It returns ErrObjectNotFound if the object isn't in the trash, and
+// ErrPreconditionFailed if an object with the same name exists,
+// in which case that object is left untouched.
+func (b *Bucket) Restore(ctx context.Context, object string) error {
+	if b.trashPrefix == "" {
+		return fmt.Errorf("%w: soft delete is not enabled for bucket %s", types.ErrInvalidArgument, b.name)
+	}
+
+	// Find the most recently trashed copy of the object.
+	var latest string
+	var latestAt time.Time
+	for entry, err := range b.List(ctx, &Query{Prefix: b.trashPrefix + object + "/"}) {
+		if err != nil {
+			return err
+		}
+		name, at, ok := b.parseTrashName(entry.Name)
+		if ok && name == object && !at.Before(latestAt) {
+			latest, latestAt = entry.Name, at
+		}
+	}
+	if latest == "" {
+		return fmt.Errorf("%w: %s is not in the trash", types.ErrObjectNotExist, object)
+	}
+
+	attrs, err := b.Attrs(ctx, latest)
+	if err != nil {
+		return err
+	}
+	err = b.copyObject(ctx, latest, object, attrs, copyObjectOptions{notExists: true, noQuota: true})
+	if err != nil {
+		return fmt.Errorf("objects: restore %s: %w", object, err)
+	}
+	return b.removeNow(ctx, latest, "")
+}
+
+// PurgeTrash permanently deletes the objects that were moved
+// to the trash more than olderThan ago, and reports how many were deleted.
+func (b *Bucket) PurgeTrash(ctx context.Context, olderThan time.Duration) (int, error) {
+	if b.trashPrefix == "" {
+		return 0, fmt.Errorf("%w: soft delete is not enabled for bucket %s", types.ErrInvalidArgument, b.name)
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	var expired []string
+	for entry, err := range b.List(ctx, &Query{Prefix: b.trashPrefix}) {
+		if err != nil {
+			return 0, err
+		}
+		if _, at, ok := b.parseTrashName(entry.Name); ok && at.Before(cutoff) {
+			expired = append(expired, entry.Name)
+		}
+	}
+
+	var errs []error
+	purged := 0
+	for _, name := range expired {
+		if err := b.removeNow(ctx, name, ""); err != nil && !errors.Is(err, ErrObjectNotFound) {
+			errs = append(errs, err)
+			continue
+		}
+		purged++
+	}
+	return purged, errors.Join(errs...)
+}
+
+type copyObjectOptions struct {
+	trash     *types.Trash // the trash information to store with the copy
+	notExists bool         // whether the destination must not exist
+	noQuota   bool         // whether the copy is exempt from the bucket's upload quota
+}
+
+// copyObject copies the object src, with the given attributes, to dst.
+//
+// The object is copied server-side if the provider supports it, keeping its content
+// and content encoding as stored. Otherwise it's streamed: objects are then also copied
+// as stored, so encrypted objects stay encrypted with the same data key, but objects
+// with a content encoding are copied decoded, since uploads can't set the content encoding.
+func (b *Bucket) copyObject(ctx context.Context, src, dst string, attrs *ObjectAttrs, opts copyObjectOptions) error {
+	uploadAttrs := types.UploadAttrs{
+		ContentType:  attrs.ContentType,
+		UserMetadata: attrs.Metadata,
+		Tags:         attrs.Tags,
+		Trash:        opts.trash,
+	}
+	if attrs.Encrypted {
+		// Keep the wrapped data key, as the content is copied encrypted.
+		srcAttrs, err := b.impl.Attrs(types.AttrsData{Ctx: ctx, Object: b.toCloudObject(src), Version: attrs.Version})
+		if err != nil {
+			return err
+		}
+		uploadAttrs.Encryption = srcAttrs.Encryption
+	}
+
+	if c, ok := b.copierImpl(); ok {
+		err := b.do(ctx, "copy", dst, func() error {
+			_, err := c.Copy(types.CopyData{
+				Ctx:             ctx,
+				Src:             b.primaryImpl(),
+				SrcObject:       b.toCloudObject(src),
+				Object:          b.toCloudObject(dst),
+				Attrs:           &uploadAttrs,
+				ContentEncoding: attrs.ContentEncoding,
+				Pre:             types.Preconditions{NotExists: opts.notExists},
+			})
+			return err
+		})
+		if !errors.Is(err, types.ErrCopyUnsupported) {
+			return err
+		}
+	}
+
+	raw := attrs.ContentEncoding == ""
+	downloadOpts := []DownloadOption{WithVersion(attrs.Version)}
+	var uploadOpts []UploadOption
+	if raw {
+		downloadOpts = append(downloadOpts, WithRawContent())
+		uploadOpts = append(uploadOpts, WithSizeHint(attrs.Size))
+	} else if attrs.Encrypted {
+		// The content is decrypted when decoded, so encrypt it again.
+		uploadAttrs.Encryption = nil
+		uploadOpts = append(uploadOpts, WithEncryption())
+	}
+	if opts.notExists {
+		uploadOpts = append(uploadOpts, WithPreconditions(Preconditions{NotExists: true}))
+	}
+	uploadOpts = append(uploadOpts, withRawAttrsOption{attrs: uploadAttrs, noQuota: opts.noQuota})
+
+	r := b.Download(ctx, src, downloadOpts...)
+	defer func() { _ = r.Close() }()
+	w := b.Upload(ctx, dst, uploadOpts...)
+	if _, err := io.Copy(w, r); err != nil {
+		if rerr := r.Err(); rerr != nil {
+			err = rerr
+		}
+		w.Abort(err)
+		return err
+	}
+	return w.Close()
+}
+
+// withRawAttrsOption is an UploadOption that sets the attributes
+// stored with the object, including internal ones.
+type withRawAttrsOption struct {
+	attrs   types.UploadAttrs
+	noQuota bool // whether the upload is exempt from the bucket's quota
+}
+
+func (o withRawAttrsOption) uploadOption() {}
+
+func (o withRawAttrsOption) applyUpload(opts *uploadOptions) {
+	opts.attrs = o.attrs
+	opts.noQuota = o.noQuota
+}
@@ -0,0 +1,295 @@
+package objects
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// multiImpl is an in-memory bucket implementation holding multiple objects.
+type multiImpl struct {
+	types.BucketImpl
+	objects map[types.CloudObject]*multiObject
+}
+
+type multiObject struct {
+	data     []byte
+	attrs    types.UploadAttrs
+	encoding string // the content encoding
+}
+
+func (m *multiImpl) Attrs(data types.AttrsData) (*types.ObjectAttrs, error) {
+	obj, ok := m.objects[data.Object]
+	if !ok {
+		return nil, types.ErrObjectNotExist
+	}
+	return &types.ObjectAttrs{
+		Object:          data.Object,
+		ContentType:     obj.attrs.ContentType,
+		ContentEncoding: obj.encoding,
+		Size:            int64(len(obj.data)),
+		Encryption:      obj.attrs.Encryption,
+		Trash:           obj.attrs.Trash,
+		IdempotencyKey:  obj.attrs.IdempotencyKey,
+		UserMetadata:    types.UserMetadataFrom(obj.attrs.Metadata()),
+		Tags:            types.TagsFromMetadata(obj.attrs.Metadata()),
+	}, nil
+}
+
+func (m *multiImpl) Download(data types.DownloadData) (types.Downloader, error) {
+	obj, ok := m.objects[data.Object]
+	if !ok {
+		return nil, types.ErrObjectNotExist
+	}
+	if r := data.Range; r != nil {
+		return memDownloader{bytes.NewReader(obj.data[r.Start:r.End])}, nil
+	}
+	return memDownloader{bytes.NewReader(obj.data)}, nil
+}
+
+func (m *multiImpl) Upload(data types.UploadData) (types.Uploader, error) {
+	return &multiUploader{m: m, data: data}, nil
+}
+
+func (m *multiImpl) Remove(data types.RemoveData) error {
+	if _, ok := m.objects[data.Object]; !ok {
+		return types.ErrObjectNotExist
+	}
+	delete(m.objects, data.Object)
+	return nil
+}
+
+func (m *multiImpl) List(data types.ListData) iter.Seq2[*types.ListEntry, error] {
+	return func(yield func(*types.ListEntry, error) bool) {
+		for _, name := range slices.Sorted(maps.Keys(m.objects)) {
+			if strings.HasPrefix(string(name), data.Prefix) {
+				obj := m.objects[name]
+				entry := &types.ListEntry{
+					Object:       name,
+					Size:         int64(len(obj.data)),
+					ETag:         string(obj.data),
+					UserMetadata: types.UserMetadataFrom(obj.attrs.Metadata()),
+					Tags:         types.TagsFromMetadata(obj.attrs.Metadata()),
+				}
+				if !yield(entry, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+type multiUploader struct {
+	m    *multiImpl
+	data types.UploadData
+	buf  bytes.Buffer
+}
+
+func (u *multiUploader) Write(p []byte) (int, error) { return u.buf.Write(p) }
+func (u *multiUploader) Abort(err error)             {}
+func (u *multiUploader) Complete() (*types.ObjectAttrs, error) {
+	if _, exists := u.m.objects[u.data.Object]; exists && u.data.Pre.NotExists {
+		return nil, types.ErrPreconditionFailed
+	}
+	u.m.objects[u.data.Object] = &multiObject{data: u.buf.Bytes(), attrs: u.data.Attrs}
+	return u.m.Attrs(types.AttrsData{Object: u.data.Object})
+}
+
+func newTrashTestBucket() (*Bucket, *multiImpl) {
+	impl := &multiImpl{objects: map[types.CloudObject]*multiObject{
+		"a.txt":   {data: []byte("a"), attrs: types.UploadAttrs{ContentType: "text/plain"}},
+		"b/c.txt": {data: []byte("c")},
+	}}
+	bkt := newTestBucket(impl)
+	bkt.trashPrefix = defaultTrashPrefix
+	return bkt, impl
+}
+
+func listNames(t *testing.T, bkt *Bucket, query *Query, options ...ListOption) []string {
+	t.Helper()
+	var names []string
+	for entry, err := range bkt.List(context.Background(), query, options...) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, entry.Name)
+	}
+	return names
+}
+
+func TestSoftDelete(t *testing.T) {
+	bkt, impl := newTrashTestBucket()
+	ctx := context.Background()
+
+	if err := bkt.Remove(ctx, "a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := listNames(t, bkt, &Query{}), []string{"b/c.txt"}; !slices.Equal(got, want) {
+		t.Errorf("got listing %v, want %v", got, want)
+	}
+
+	trashed := listNames(t, bkt, &Query{Prefix: ".trash/"})
+	if len(trashed) != 1 || !strings.HasPrefix(trashed[0], ".trash/a.txt/") {
+		t.Fatalf("got trash %v, want a.txt in the trash", trashed)
+	}
+	if got := listNames(t, bkt, &Query{}, WithTrashed()); len(got) != 2 {
+		t.Errorf("got listing %v with WithTrashed, want both objects", got)
+	}
+
+	attrs, err := bkt.Attrs(ctx, trashed[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrs.TrashedObject != "a.txt" || time.Since(attrs.TrashedAt) > time.Minute {
+		t.Errorf("got trashed object %q at %v, want a.txt just now", attrs.TrashedObject, attrs.TrashedAt)
+	}
+
+	if err := bkt.Restore(ctx, "a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	obj := impl.objects["a.txt"]
+	if obj == nil || string(obj.data) != "a" || obj.attrs.ContentType != "text/plain" {
+		t.Errorf("got restored object %+v, want the original content and attributes", obj)
+	}
+	if got := listNames(t, bkt, &Query{Prefix: ".trash/"}); len(got) != 0 {
+		t.Errorf("got trash %v after restoring, want it empty", got)
+	}
+
+	if err := bkt.Restore(ctx, "a.txt"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("got err %v restoring again, want ErrObjectNotFound", err)
+	}
+}
+
+func TestSoftDelete_RestoreExisting(t *testing.T) {
+	bkt, impl := newTrashTestBucket()
+	ctx := context.Background()
+
+	if err := bkt.Remove(ctx, "a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	w := bkt.Upload(ctx, "a.txt")
+	_, _ = io.WriteString(w, "new")
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bkt.Restore(ctx, "a.txt"); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("got err %v, want ErrPreconditionFailed", err)
+	}
+	if got := string(impl.objects["a.txt"].data); got != "new" {
+		t.Errorf("got object %q, want it untouched", got)
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	bkt, impl := newTrashTestBucket()
+	ctx := context.Background()
+
+	old := bkt.trashName("old.txt", time.Now().Add(-48*time.Hour))
+	impl.objects[types.CloudObject(old)] = &multiObject{data: []byte("old")}
+	if err := bkt.Remove(ctx, "a.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := bkt.PurgeTrash(ctx, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Errorf("got %d purged, want 1", n)
+	}
+	trash := listNames(t, bkt, &Query{Prefix: ".trash/"})
+	if len(trash) != 1 || !strings.HasPrefix(trash[0], ".trash/a.txt/") {
+		t.Errorf("got trash %v, want only the recently removed object", trash)
+	}
+
+	// Removing a trashed object deletes it permanently.
+	if err := bkt.Remove(ctx, trash[0]); err != nil {
+		t.Fatal(err)
+	}
+	if got := listNames(t, bkt, &Query{}, WithTrashed()); !slices.Equal(got, []string{"b/c.txt"}) {
+		t.Errorf("got listing %v, want only b/c.txt", got)
+	}
+}
+
+func TestSoftDelete_ServerSide(t *testing.T) {
+	bkt, impl := newTrashTestBucket()
+	copier := &copyImpl{multiImpl: impl}
+	bkt.impl = copier
+	bkt.quota = bkt.mgr.quotaFor("test", 1)
+	ctx := context.Background()
+
+	impl.objects["big.txt.gz"] = &multiObject{
+		data:     []byte("compressed"),
+		attrs:    types.UploadAttrs{ContentType: "text/plain", UserMetadata: map[string]string{"k": "v"}},
+		encoding: "gzip",
+	}
+
+	// Trashing and restoring objects copies them server-side,
+	// exempt from the quota and keeping their content encoding.
+	if err := bkt.Remove(ctx, "big.txt.gz"); err != nil {
+		t.Fatal(err)
+	}
+	trashed := listNames(t, bkt, &Query{Prefix: ".trash/"})
+	if len(trashed) != 1 {
+		t.Fatalf("got trash %v, want one object", trashed)
+	}
+	obj := impl.objects[types.CloudObject(trashed[0])]
+	if obj.encoding != "gzip" || obj.attrs.Trash == nil || obj.attrs.Trash.Object != "big.txt.gz" {
+		t.Errorf("got trashed object %+v, want gzip encoding and trash info", obj)
+	}
+
+	if err := bkt.Restore(ctx, "big.txt.gz"); err != nil {
+		t.Fatal(err)
+	}
+	obj = impl.objects["big.txt.gz"]
+	if obj == nil || string(obj.data) != "compressed" || obj.encoding != "gzip" ||
+		obj.attrs.Trash != nil || obj.attrs.UserMetadata["k"] != "v" {
+		t.Errorf("got restored object %+v, want the original content and attributes", obj)
+	}
+	if copier.copies != 2 {
+		t.Errorf("got %d server-side copies, want 2", copier.copies)
+	}
+	if usage := bkt.QuotaUsage(); usage.Used != 0 || usage.Pending != 0 {
+		t.Errorf("got quota usage %+v, want none", usage)
+	}
+
+	// Restoring over an existing object fails.
+	if err := bkt.Remove(ctx, "a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	impl.objects["a.txt"] = &multiObject{data: []byte("new")}
+	if err := bkt.Restore(ctx, "a.txt"); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("got err %v, want ErrPreconditionFailed", err)
+	}
+}
+
+func TestSoftDelete_StreamedQuota(t *testing.T) {
+	bkt, _ := newTrashTestBucket()
+	bkt.quota = bkt.mgr.quotaFor("test", 1)
+	ctx := context.Background()
+
+	// Objects streamed to the trash don't count towards the quota either.
+	w := bkt.Upload(ctx, "big.txt")
+	_, _ = io.WriteString(w, "x")
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bkt.Remove(ctx, "b/c.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := bkt.Restore(ctx, "b/c.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if usage := bkt.QuotaUsage(); usage.Used != 1 {
+		t.Errorf("got quota usage %+v, want 1 byte used", usage)
+	}
+}
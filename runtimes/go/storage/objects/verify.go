@@ -0,0 +1,119 @@
+package objects
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// VerifyReport is the result of verifying the objects in a bucket.
+type VerifyReport struct {
+	// Checked is the number of objects that were checked.
+	Checked int
+
+	// Failures are the objects that failed verification.
+	Failures []VerifyFailure
+}
+
+// VerifyFailure describes an object that failed verification.
+type VerifyFailure struct {
+	// Name is the name of the object.
+	Name string
+
+	// Reason describes why the object failed verification.
+	Reason string
+}
+
+// Verify checks the integrity of the objects in the bucket matching query,
+// using up to concurrency concurrent checks.
+//
+// For each object it checks that the size and ETag reported by the listing
+// match the object's attributes. With WithRedownload the object is also
+// downloaded to check its size and the checksums the provider stores for it,
+// as well as its MD5 checksum if the provider reports it as the ETag.
+//
+// Objects removed while verifying are ignored. Like ForEach, it streams
+// the listing and honors cancellation, so it's suitable for running
+// periodically in a cron job. The returned report covers the objects
+// checked before any error occurred.
+func (b *Bucket) Verify(ctx context.Context, query *Query, concurrency int, options ...VerifyOption) (*VerifyReport, error) {
+	var opt verifyOptions
+	for _, o := range options {
+		o.applyVerify(&opt)
+	}
+
+	var (
+		mu     sync.Mutex
+		report VerifyReport
+	)
+	err := b.ForEach(ctx, query, concurrency, func(ctx context.Context, entry *ListEntry) error {
+		reason, err := b.verifyObject(ctx, entry, opt)
+		if errors.Is(err, ErrObjectNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		report.Checked++
+		if reason != "" {
+			report.Failures = append(report.Failures, VerifyFailure{Name: entry.Name, Reason: reason})
+		}
+		return nil
+	})
+	return &report, err
+}
+
+// verifyObject verifies a single object, returning the reason
+// it failed verification or "" if it passed.
+func (b *Bucket) verifyObject(ctx context.Context, entry *ListEntry, opt verifyOptions) (reason string, err error) {
+	attrs, err := b.Attrs(ctx, entry.Name)
+	if err != nil {
+		return "", err
+	}
+	if attrs.Size != entry.Size {
+		return fmt.Sprintf("size mismatch: listed as %d bytes, attributes report %d bytes", entry.Size, attrs.Size), nil
+	} else if attrs.ETag != entry.ETag {
+		return fmt.Sprintf("etag mismatch: listed as %s, attributes report %s", entry.ETag, attrs.ETag), nil
+	}
+
+	if !opt.redownload {
+		return "", nil
+	}
+
+	r := b.Download(ctx, entry.Name, WithVersion(attrs.Version), WithRawContent())
+	defer func() { _ = r.Close() }()
+	h := md5.New()
+	n, err := io.Copy(h, r)
+	if errors.Is(err, ErrChecksumMismatch) {
+		return fmt.Sprintf("checksum mismatch: %v", err), nil
+	} else if err != nil {
+		return "", err
+	}
+	if n != attrs.Size {
+		return fmt.Sprintf("size mismatch: downloaded %d bytes, attributes report %d bytes", n, attrs.Size), nil
+	}
+
+	// The ETag is the MD5 checksum of the content for objects not uploaded
+	// in multiple parts, unless they're encrypted with SSE-KMS on S3.
+	if etag := strings.Trim(attrs.ETag, `"`); isMD5Hex(etag) && !strings.HasPrefix(attrs.ServerSideEncryption, "aws:kms") {
+		if sum := hex.EncodeToString(h.Sum(nil)); sum != strings.ToLower(etag) {
+			return fmt.Sprintf("checksum mismatch: content has MD5 %s, etag is %s", sum, etag), nil
+		}
+	}
+	return "", nil
+}
+
+func isMD5Hex(s string) bool {
+	if len(s) != hex.EncodedLen(md5.Size) {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
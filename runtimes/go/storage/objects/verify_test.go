@@ -0,0 +1,172 @@
+package objects
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"iter"
+	"slices"
+	"testing"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// verifyImpl is an in-memory bucket implementation that reports
+// the MD5 checksum of objects as their ETag, like S3 does.
+type verifyImpl struct {
+	*multiImpl
+	etags map[types.CloudObject]string // overrides the ETag reported by Attrs
+	sse   map[types.CloudObject]string // the server-side encryption reported by Attrs
+
+	// onAttrs, if set, is called before the attributes of an object are returned.
+	onAttrs func(object types.CloudObject)
+}
+
+func md5Hex(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (v *verifyImpl) Attrs(data types.AttrsData) (*types.ObjectAttrs, error) {
+	if v.onAttrs != nil {
+		v.onAttrs(data.Object)
+	}
+	attrs, err := v.multiImpl.Attrs(data)
+	if err != nil {
+		return nil, err
+	}
+	attrs.ETag = md5Hex(v.objects[data.Object].data)
+	if etag, ok := v.etags[data.Object]; ok {
+		attrs.ETag = etag
+	}
+	attrs.ServerSideEncryption = v.sse[data.Object]
+	return attrs, nil
+}
+
+func (v *verifyImpl) List(data types.ListData) iter.Seq2[*types.ListEntry, error] {
+	return func(yield func(*types.ListEntry, error) bool) {
+		for entry, err := range v.multiImpl.List(data) {
+			if err == nil {
+				entry.ETag = md5Hex(v.objects[entry.Object].data)
+			}
+			if !yield(entry, err) {
+				return
+			}
+		}
+	}
+}
+
+func newVerifyTestBucket() (*Bucket, *verifyImpl) {
+	impl := &verifyImpl{
+		multiImpl: &multiImpl{objects: map[types.CloudObject]*multiObject{
+			"a.txt": {data: []byte("a")},
+			"b.txt": {data: []byte("b")},
+		}},
+		etags: make(map[types.CloudObject]string),
+		sse:   make(map[types.CloudObject]string),
+	}
+	return newTestBucket(impl), impl
+}
+
+func verifyFailures(t *testing.T, bkt *Bucket, wantChecked int, options ...VerifyOption) []string {
+	t.Helper()
+	report, err := bkt.Verify(context.Background(), &Query{}, 1, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Checked != wantChecked {
+		t.Errorf("got %d objects checked, want %d", report.Checked, wantChecked)
+	}
+	var failed []string
+	for _, f := range report.Failures {
+		failed = append(failed, f.Name)
+	}
+	return failed
+}
+
+func TestVerify(t *testing.T) {
+	bkt, _ := newVerifyTestBucket()
+	if failed := verifyFailures(t, bkt, 2, WithRedownload()); len(failed) > 0 {
+		t.Errorf("got failures for %v, want none", failed)
+	}
+}
+
+func TestVerify_SizeMismatch(t *testing.T) {
+	bkt, impl := newVerifyTestBucket()
+	// The object changes size between being listed and its attributes being read.
+	impl.onAttrs = func(object types.CloudObject) {
+		if object == "a.txt" {
+			impl.objects[object].data = []byte("aaa")
+		}
+	}
+	if failed := verifyFailures(t, bkt, 2); !slices.Equal(failed, []string{"a.txt"}) {
+		t.Errorf("got failures for %v, want [a.txt]", failed)
+	}
+}
+
+func TestVerify_ETagMismatch(t *testing.T) {
+	bkt, impl := newVerifyTestBucket()
+	impl.etags["b.txt"] = "other"
+	if failed := verifyFailures(t, bkt, 2); !slices.Equal(failed, []string{"b.txt"}) {
+		t.Errorf("got failures for %v, want [b.txt]", failed)
+	}
+}
+
+func TestVerify_RemovedWhileVerifying(t *testing.T) {
+	bkt, impl := newVerifyTestBucket()
+	impl.onAttrs = func(object types.CloudObject) {
+		if object == "a.txt" {
+			delete(impl.objects, object)
+		}
+	}
+	// The removed object is neither checked nor reported as a failure.
+	if failed := verifyFailures(t, bkt, 1); len(failed) > 0 {
+		t.Errorf("got failures for %v, want none", failed)
+	}
+}
+
+func TestVerify_ChecksumMismatch(t *testing.T) {
+	bkt, impl := newVerifyTestBucket()
+	// The content doesn't match the MD5 checksum reported as the ETag.
+	impl.etags["a.txt"] = md5Hex([]byte("corrupt"))
+
+	// The listing reports the same ETag, so only redownloading finds the corruption.
+	bkt.impl = &listETagImpl{verifyImpl: impl}
+	if failed := verifyFailures(t, bkt, 2); len(failed) > 0 {
+		t.Errorf("got failures for %v without redownloading, want none", failed)
+	}
+	if failed := verifyFailures(t, bkt, 2, WithRedownload()); !slices.Equal(failed, []string{"a.txt"}) {
+		t.Errorf("got failures for %v, want [a.txt]", failed)
+	}
+}
+
+func TestVerify_KMS(t *testing.T) {
+	bkt, impl := newVerifyTestBucket()
+	bkt.impl = &listETagImpl{verifyImpl: impl}
+
+	// Objects encrypted with SSE-KMS have an ETag that isn't the MD5 of their content.
+	impl.etags["a.txt"] = md5Hex([]byte("encrypted"))
+	impl.sse["a.txt"] = "aws:kms"
+	if failed := verifyFailures(t, bkt, 2, WithRedownload()); len(failed) > 0 {
+		t.Errorf("got failures for %v, want none", failed)
+	}
+}
+
+// listETagImpl is a verifyImpl that reports the same ETag
+// when listing objects as when reading their attributes.
+type listETagImpl struct {
+	*verifyImpl
+}
+
+func (l *listETagImpl) List(data types.ListData) iter.Seq2[*types.ListEntry, error] {
+	return func(yield func(*types.ListEntry, error) bool) {
+		for entry, err := range l.verifyImpl.List(data) {
+			if etag, ok := l.etags[entry.Object]; err == nil && ok {
+				entry.ETag = etag
+			}
+			if !yield(entry, err) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultWaitInterval    = 100 * time.Millisecond
+	defaultWaitMaxInterval = 5 * time.Second
+	defaultWaitTimeout     = time.Minute
+)
+
+// WaitUntilExists waits until an object is visible in the bucket,
+// and returns its attributes.
+//
+// It's useful when uploading an object and then immediately triggering
+// processing elsewhere that reads it, for providers or caches that don't
+// guarantee read-after-write consistency.
+//
+// The object's attributes are polled with exponential backoff, starting at
+// the interval given by WithPollInterval (100ms by default) and doubling up
+// to five seconds. With WithETag or WithVersion it also waits until the
+// object has the given ETag or version.
+//
+// Polling stops after the duration given by WithMaxWait (one minute by default)
+// or when ctx is done, whichever happens first. If the max wait or the deadline
+// of ctx is reached it returns an error matching ErrOperationTimeout, and if ctx
+// is canceled an error matching context.Canceled.
+func (b *Bucket) WaitUntilExists(ctx context.Context, object string, options ...WaitOption) (*ObjectAttrs, error) {
+	opt := waitOptions{
+		interval: defaultWaitInterval,
+		maxWait:  defaultWaitTimeout,
+	}
+	for _, o := range options {
+		o.applyWait(&opt)
+	}
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, opt.maxWait)
+	defer cancel()
+
+	interval := opt.interval
+	for {
+		attrs, err := b.Attrs(ctx, object)
+		if err == nil && opt.matches(attrs) {
+			return attrs, nil
+		} else if err != nil && !errors.Is(err, ErrObjectNotFound) {
+			return nil, mapTimeout(ctx, "wait_until_exists", start, err)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			err := fmt.Errorf("object %q not visible: %w", object, ctx.Err())
+			return nil, mapTimeout(ctx, "wait_until_exists", start, err)
+		case <-timer.C:
+		}
+		interval = min(interval*2, max(defaultWaitMaxInterval, opt.interval))
+	}
+}
+
+// matches reports whether attrs match the ETag and version being waited for.
+func (o *waitOptions) matches(attrs *ObjectAttrs) bool {
+	return (o.etag == "" || attrs.ETag == o.etag) &&
+		(o.version == "" || attrs.Version == o.version)
+}
@@ -0,0 +1,70 @@
+package objects
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// laggingImpl is a bucket implementation where an object
+// only becomes visible after a number of Attrs calls.
+type laggingImpl struct {
+	types.BucketImpl
+	visibleAfter int
+	calls        int
+}
+
+func (l *laggingImpl) Attrs(data types.AttrsData) (*types.ObjectAttrs, error) {
+	l.calls++
+	if l.calls <= l.visibleAfter {
+		return nil, types.ErrObjectNotExist
+	}
+	v := strconv.Itoa(l.calls)
+	return &types.ObjectAttrs{Object: data.Object, Version: v, ETag: "etag-" + v}, nil
+}
+
+func TestWaitUntilExists(t *testing.T) {
+	impl := &laggingImpl{visibleAfter: 3}
+	attrs, err := newTestBucket(impl).WaitUntilExists(context.Background(), "obj", WithPollInterval(time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	} else if impl.calls != 4 {
+		t.Errorf("got %d calls, want 4", impl.calls)
+	} else if attrs.ETag != "etag-4" {
+		t.Errorf("got etag %q, want etag-4", attrs.ETag)
+	}
+}
+
+func TestWaitUntilExists_ETag(t *testing.T) {
+	impl := &laggingImpl{}
+	_, err := newTestBucket(impl).WaitUntilExists(context.Background(), "obj",
+		WithPollInterval(time.Millisecond), WithETag("etag-3"))
+	if err != nil {
+		t.Fatal(err)
+	} else if impl.calls != 3 {
+		t.Errorf("got %d calls, want 3", impl.calls)
+	}
+}
+
+func TestWaitUntilExists_Timeout(t *testing.T) {
+	impl := &laggingImpl{visibleAfter: 1 << 30}
+	_, err := newTestBucket(impl).WaitUntilExists(context.Background(), "obj",
+		WithPollInterval(time.Millisecond), WithMaxWait(20*time.Millisecond))
+	if !errors.Is(err, ErrOperationTimeout) {
+		t.Fatalf("got err %v, want ErrOperationTimeout", err)
+	}
+}
+
+func TestWaitUntilExists_Canceled(t *testing.T) {
+	impl := &laggingImpl{visibleAfter: 1 << 30}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, err := newTestBucket(impl).WaitUntilExists(ctx, "obj", WithPollInterval(time.Millisecond))
+	if !errors.Is(err, context.Canceled) || errors.Is(err, ErrOperationTimeout) {
+		t.Fatalf("got err %v, want context.Canceled", err)
+	}
+}
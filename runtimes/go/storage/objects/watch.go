@@ -0,0 +1,113 @@
+package objects
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	defaultWatchInterval = 10 * time.Second
+	minWatchInterval     = time.Second
+)
+
+// Watch watches the objects matching query for changes by periodically
+// listing them, and sends an event on the returned channel for each object
+// that is added, changed (as ObjectCreated) or removed (as ObjectDeleted)
+// between successive listings.
+//
+// Objects that exist when Watch is called don't generate events.
+// The channel is closed when ctx is canceled.
+//
+// The interval defaults to ten seconds if zero, and is at least a second.
+// If listing fails, the error is logged and the changes are picked up
+// by the next successful listing.
+//
+// Watch is polling-based: every interval it lists all objects matching the
+// query, and it keeps the name and ETag of each of them in memory. It's meant
+// for development and low-volume use, like processing files dropped in a bucket.
+// For production use, and to be notified of changes as they happen, use
+// NewTopicNotification.
+func (b *Bucket) Watch(ctx context.Context, query *Query, interval time.Duration) <-chan *ObjectEvent {
+	if interval == 0 {
+		interval = defaultWatchInterval
+	}
+	interval = max(interval, minWatchInterval)
+
+	ch := make(chan *ObjectEvent)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		b.watch(ctx, query, ticker.C, ch)
+	}()
+	return ch
+}
+
+// watchState is the state of a watched object: its ETag.
+type watchState map[string]string
+
+// watch lists the objects matching query on each tick,
+// sending the changes since the last listing to ch.
+func (b *Bucket) watch(ctx context.Context, query *Query, tick <-chan time.Time, ch chan<- *ObjectEvent) {
+	var prev watchState
+	for {
+		curr, err := b.watchList(ctx, query)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			b.mgr.rootLogger.Warn().Err(err).Str("bucket", b.name).Str("prefix", query.Prefix).
+				Msg("objects: watch failed to list objects, retrying")
+		} else {
+			if prev != nil {
+				for _, ev := range b.diffWatchState(prev, curr) {
+					select {
+					case ch <- ev:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+			prev = curr
+		}
+
+		select {
+		case <-tick:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// watchList lists the objects matching query.
+// It returns an error if the listing is incomplete.
+func (b *Bucket) watchList(ctx context.Context, query *Query) (watchState, error) {
+	state := make(watchState)
+	for entry, err := range b.List(ctx, query) {
+		if err != nil {
+			return nil, err
+		}
+		state[entry.Name] = entry.ETag
+	}
+	return state, nil
+}
+
+// diffWatchState returns the events for the changes between prev and curr,
+// sorted by object name.
+func (b *Bucket) diffWatchState(prev, curr watchState) []*ObjectEvent {
+	var events []*ObjectEvent
+	for name, etag := range curr {
+		if prevETag, ok := prev[name]; !ok || prevETag != etag {
+			events = append(events, &ObjectEvent{Type: ObjectCreated, Bucket: b.name, Name: name, ETag: etag})
+		}
+	}
+	for name := range prev {
+		if _, ok := curr[name]; !ok {
+			events = append(events, &ObjectEvent{Type: ObjectDeleted, Bucket: b.name, Name: name})
+		}
+	}
+	slices.SortFunc(events, func(a, b *ObjectEvent) int { return strings.Compare(a.Name, b.Name) })
+	return events
+}
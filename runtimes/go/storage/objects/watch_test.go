@@ -0,0 +1,66 @@
+package objects
+
+import (
+	"context"
+	"iter"
+	"testing"
+	"time"
+
+	"encore.dev/storage/objects/internal/types"
+)
+
+// listHookImpl calls onList before each listing.
+type listHookImpl struct {
+	*multiImpl
+	calls  int
+	onList func(call int)
+}
+
+func (l *listHookImpl) List(data types.ListData) iter.Seq2[*types.ListEntry, error] {
+	l.calls++
+	l.onList(l.calls)
+	return l.multiImpl.List(data)
+}
+
+func TestWatch(t *testing.T) {
+	_, impl := newTrashTestBucket()
+	hooked := &listHookImpl{multiImpl: impl, onList: func(call int) {
+		if call == 2 {
+			impl.objects["new.txt"] = &multiObject{data: []byte("new")}
+			impl.objects["a.txt"] = &multiObject{data: []byte("changed")}
+			delete(impl.objects, "b/c.txt")
+		}
+	}}
+	bkt := newTestBucket(hooked)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tick := make(chan time.Time)
+	close(tick) // tick continuously
+	ch := make(chan *ObjectEvent)
+	go func() {
+		defer close(ch)
+		bkt.watch(ctx, &Query{}, tick, ch)
+	}()
+
+	want := []ObjectEvent{
+		{Type: ObjectCreated, Bucket: "test", Name: "a.txt", ETag: "changed"},
+		{Type: ObjectDeleted, Bucket: "test", Name: "b/c.txt"},
+		{Type: ObjectCreated, Bucket: "test", Name: "new.txt", ETag: "new"},
+	}
+	for _, w := range want {
+		select {
+		case got := <-ch:
+			if *got != w {
+				t.Errorf("got event %+v, want %+v", *got, w)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for event %+v", w)
+		}
+	}
+
+	cancel()
+	for ev := range ch {
+		t.Errorf("got unexpected event %+v", *ev)
+	}
+}
@@ -23,3 +23,13 @@ func init() {
 		testsupport.Singleton, logging.RootLogger)
 	shutdown.Singleton.RegisterShutdownHandler(Singleton.Shutdown)
 }
+
+// singleton returns the singleton manager, panicking with
+// an actionable message if it hasn't been initialized yet.
+func singleton() *Manager {
+	if Singleton == nil {
+		panic("internal encore error: objects: " + errNotInitialized.Error() +
+			"; buckets can't be used until the objects package has been initialized")
+	}
+	return Singleton
+}
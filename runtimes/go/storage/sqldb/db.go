@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"slices"
 	"strings"
 	"sync"
 
@@ -30,6 +31,18 @@ type Database struct {
 
 	noopDB bool // true if this is a dummy database that does nothing and returns errors for all operations
 
+	// replica is the name of the read replica queries are routed to,
+	// or "" for the primary database.
+	replica string
+	primary *Database // the primary database, if this is a replica
+
+	replicasMu sync.Mutex
+	replicas   map[string]*Database // replica name -> handle
+
+	listenMu     sync.Mutex
+	listenClosed bool
+	listeners    map[*listener]context.CancelFunc
+
 	initOnce sync.Once
 	pool     *pgxpool.Pool
 	connStr  string
@@ -86,7 +99,7 @@ func (db *Database) init() {
 
 	db.initOnce.Do(func() {
 		if db.pool == nil {
-			pool, found := db.mgr.getPool(db.origName, db.name, db.hooks)
+			pool, found := db.mgr.getPool(db.origName, db.replica, db.name, db.hooks)
 			db.pool, db.noopDB = pool, !found
 		}
 
@@ -134,6 +147,14 @@ func (db *Database) Stdlib() *sql.DB {
 }
 
 func (db *Database) shutdown() {
+	db.stopListeners()
+
+	db.replicasMu.Lock()
+	for _, r := range db.replicas {
+		r.shutdown()
+	}
+	db.replicasMu.Unlock()
+
 	if db.pool != nil {
 		db.pool.Close()
 	}
@@ -142,9 +163,76 @@ func (db *Database) shutdown() {
 	}
 }
 
+// ReadOnly returns a handle to the database that routes queries to a read replica,
+// for reads that can tolerate the replica lagging behind the primary database.
+// Replicas reject writes, so the handle must only be used for reads.
+//
+// If multiple read replicas are configured it uses the first one; use ReadReplica
+// to select a specific one. If none are configured, such as when running locally,
+// it returns db itself so queries go to the primary database.
+func (db *Database) ReadOnly() *Database {
+	if db.primary != nil {
+		return db
+	}
+	if cfg := db.mgr.dbConfig(db.origName); cfg != nil && len(cfg.ReadReplicas) > 0 {
+		return db.ReadReplica(cfg.ReadReplicas[0].Name)
+	}
+	return db
+}
+
+// ReadReplica returns a handle to the database that routes queries to the read replica
+// with the given name, as configured in the infrastructure configuration.
+//
+// If no replica with that name is configured, it returns the primary database.
+func (db *Database) ReadReplica(name string) *Database {
+	if db.primary != nil {
+		return db.primary.ReadReplica(name)
+	} else if db.noopDB {
+		return db
+	}
+
+	db.replicasMu.Lock()
+	defer db.replicasMu.Unlock()
+	if r, ok := db.replicas[name]; ok {
+		return r
+	}
+
+	cfg := db.mgr.dbConfig(db.origName)
+	if cfg == nil || !slices.ContainsFunc(cfg.ReadReplicas, func(r *config.SQLReadReplica) bool {
+		return r.Name == name
+	}) {
+		return db
+	}
+
+	r := &Database{
+		name:     db.name,
+		origName: db.origName,
+		mgr:      db.mgr,
+		hooks:    db.hooks,
+		replica:  name,
+		primary:  db,
+	}
+	if db.replicas == nil {
+		db.replicas = make(map[string]*Database)
+	}
+	db.replicas[name] = r
+	return r
+}
+
+// replicaName returns the name of the read replica queries are routed to,
+// or nil if they're routed to the primary database.
+func (db *Database) replicaName() *string {
+	if db.replica == "" {
+		return nil
+	}
+	return &db.replica
+}
+
 // dbConf computes a suitable pgxpool config given a database config.
 // If dbNameOverride is provided, it overrides the database name used when connecting,
 // for testing purposes.
+//
+// To connect to a read replica, srv is the server of the replica.
 func dbConf(srv *config.SQLServer, db *config.SQLDatabase, dbNameOverride string) (*pgxpool.Config, error) {
 	dbName := dbNameOverride
 	if dbName == "" {
@@ -210,6 +298,7 @@ func (db *Database) Exec(ctx context.Context, query string, args ...interface{})
 	}
 
 	db.init()
+	opts, args := splitQueryOptions(args)
 
 	var (
 		startEventID model.TraceEventID
@@ -229,11 +318,12 @@ func (db *Database) Exec(ctx context.Context, query string, args ...interface{})
 			Query:       query,
 			TxStartID:   0,
 			Stack:       stack.Build(4),
+			Replica:     db.replicaName(),
+			Name:        opts.statementName(),
 		})
 	}
 
-	res, err := db.pool.Exec(markTraced(ctx), query, args...)
-	err = convertErr(err)
+	res, err := db.mgr.execWith(markTraced(ctx), db.origName, db.poolQuerier, opts, query, args)
 
 	if curr.Trace != nil {
 		curr.Trace.DBQueryEnd(eventParams, startEventID, err)
@@ -252,6 +342,7 @@ func (db *Database) Query(ctx context.Context, query string, args ...interface{}
 	}
 
 	db.init()
+	opts, args := splitQueryOptions(args)
 
 	var (
 		startEventID model.TraceEventID
@@ -270,11 +361,12 @@ func (db *Database) Query(ctx context.Context, query string, args ...interface{}
 			EventParams: eventParams,
 			Query:       query,
 			Stack:       stack.Build(4),
+			Replica:     db.replicaName(),
+			Name:        opts.statementName(),
 		})
 	}
 
-	rows, err := db.pool.Query(markTraced(ctx), query, args...)
-	err = convertErr(err)
+	rows, err := db.mgr.queryWith(markTraced(ctx), db.origName, db.poolQuerier, opts, query, args)
 
 	if curr.Trace != nil {
 		curr.Trace.DBQueryEnd(eventParams, startEventID, err)
@@ -295,6 +387,7 @@ func (db *Database) QueryRow(ctx context.Context, query string, args ...interfac
 	}
 
 	db.init()
+	opts, args := splitQueryOptions(args)
 
 	var (
 		startEventID model.TraceEventID
@@ -313,11 +406,12 @@ func (db *Database) QueryRow(ctx context.Context, query string, args ...interfac
 			EventParams: eventParams,
 			Query:       query,
 			Stack:       stack.Build(4),
+			Replica:     db.replicaName(),
+			Name:        opts.statementName(),
 		})
 	}
 
-	rows, err := db.pool.Query(markTraced(ctx), query, args...)
-	err = convertErr(err)
+	rows, err := db.mgr.queryWith(markTraced(ctx), db.origName, db.poolQuerier, opts, query, args)
 	r := &Row{rows: rows, err: err}
 
 	if curr.Trace != nil {
@@ -327,16 +421,81 @@ func (db *Database) QueryRow(ctx context.Context, query string, args ...interfac
 	return r
 }
 
+// CopyFrom inserts rows into table using the PostgreSQL COPY protocol,
+// and returns the number of rows inserted. Each row contains the values
+// of the given columns, in the same order.
+//
+// It's much faster than inserting the rows with individual INSERT statements,
+// which makes it suitable for loading large amounts of data. If any row fails
+// to be inserted, none of them are.
+//
+// The table name may be qualified with a schema, like "myschema.mytable".
+//
+// See https://www.postgresql.org/docs/current/sql-copy.html for more information.
+func (db *Database) CopyFrom(ctx context.Context, table string, columns []string, rows [][]any) (int64, error) {
+	if db.noopDB {
+		return 0, errNoopDB
+	}
+
+	db.init()
+	tableName := pgx.Identifier(strings.Split(table, "."))
+
+	var (
+		startEventID model.TraceEventID
+		eventParams  trace2.EventParams
+	)
+
+	curr := db.mgr.rt.Current()
+	if curr.Req != nil && curr.Trace != nil {
+		eventParams = trace2.EventParams{
+			TraceID: curr.Req.TraceID,
+			SpanID:  curr.Req.SpanID,
+			Goid:    curr.Goctr,
+			DefLoc:  0,
+		}
+		startEventID = curr.Trace.DBQueryStart(trace2.DBQueryStartParams{
+			EventParams: eventParams,
+			Query:       copyQuery(tableName, columns),
+			Stack:       stack.Build(4),
+			Replica:     db.replicaName(),
+		})
+	}
+
+	n, err := db.pool.CopyFrom(markTraced(ctx), tableName, columns, pgx.CopyFromRows(rows))
+	err = convertErr(err)
+
+	if curr.Trace != nil {
+		curr.Trace.DBQueryEndWithRows(eventParams, startEventID, uint64(n), err)
+	}
+
+	return n, err
+}
+
+// copyQuery returns the COPY statement equivalent to a CopyFrom call, for tracing.
+func copyQuery(table pgx.Identifier, columns []string) string {
+	cols := make([]string, len(columns))
+	for i, col := range columns {
+		cols[i] = pgx.Identifier{col}.Sanitize()
+	}
+	return fmt.Sprintf("COPY %s (%s) FROM STDIN", table.Sanitize(), strings.Join(cols, ", "))
+}
+
 // Begin opens a new database transaction.
 //
 // See (*database/sql.DB).Begin() for additional documentation.
 func (db *Database) Begin(ctx context.Context) (*Tx, error) {
+	return db.begin(ctx, pgx.TxOptions{}, 5)
+}
+
+// begin opens a new database transaction with the given options,
+// skipping stackSkip frames when recording the trace event's stack.
+func (db *Database) begin(ctx context.Context, opts pgx.TxOptions, stackSkip int) (*Tx, error) {
 	if db.noopDB {
 		return nil, errNoopDB
 	}
 
 	db.init()
-	tx, err := db.pool.Begin(markTraced(ctx))
+	tx, err := db.pool.BeginTx(markTraced(ctx), opts)
 	err = convertErr(err)
 	if err != nil {
 		return nil, err
@@ -349,10 +508,10 @@ func (db *Database) Begin(ctx context.Context) (*Tx, error) {
 			TraceID: curr.Req.TraceID,
 			SpanID:  curr.Req.SpanID,
 			Goid:    curr.Goctr,
-		}, stack.Build(4))
+		}, stack.Build(stackSkip))
 	}
 
-	return &Tx{mgr: db.mgr, std: tx, startID: startID}, nil
+	return &Tx{mgr: db.mgr, std: tx, startID: startID, replica: db.replicaName(), dbName: db.origName}, nil
 }
 
 // Driver returns the underlying database driver for this database connection pool.
@@ -0,0 +1,222 @@
+package sqldb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"encore.dev/appruntime/exported/model"
+	"encore.dev/appruntime/exported/stack"
+	"encore.dev/appruntime/exported/trace2"
+)
+
+// Notification is a notification sent with NOTIFY (or pg_notify)
+// to a channel being listened on with Listen.
+type Notification struct {
+	// Channel is the name of the channel the notification was sent to.
+	Channel string
+
+	// Payload is the payload of the notification, which may be empty.
+	Payload string
+}
+
+// Listen starts listening for notifications sent to the given channel with NOTIFY,
+// and returns a Go channel on which they're received.
+//
+// It listens on a dedicated connection to the database, separate from the connection pool,
+// which is transparently re-established if it's lost. Notifications sent while reconnecting
+// are missed, so Listen is best suited for uses that tolerate missing the occasional
+// notification, like cache invalidation, or that can catch up by querying the database.
+//
+// Listening stops and the returned channel is closed when ctx is canceled or the
+// application shuts down. To listen for longer than a request, use a context not
+// tied to the request, like context.Background().
+//
+// Notifications must be received promptly, as no more are read from the
+// database until the previous one has been received.
+//
+// Read replicas don't receive notifications, so on a handle returned by
+// ReadOnly or ReadReplica it listens on the primary database.
+func (db *Database) Listen(ctx context.Context, channel string) (<-chan Notification, error) {
+	if db.noopDB {
+		return nil, errNoopDB
+	}
+
+	if db.primary != nil {
+		// Standbys reject LISTEN and never receive notifications,
+		// so listen on the primary database.
+		db = db.primary
+	}
+
+	db.init()
+	l := &listener{
+		db:   db,
+		stmt: "LISTEN " + pgx.Identifier{channel}.Sanitize(),
+	}
+
+	var (
+		startEventID model.TraceEventID
+		eventParams  trace2.EventParams
+	)
+
+	curr := db.mgr.rt.Current()
+	if curr.Req != nil && curr.Trace != nil {
+		eventParams = trace2.EventParams{
+			TraceID: curr.Req.TraceID,
+			SpanID:  curr.Req.SpanID,
+			Goid:    curr.Goctr,
+			DefLoc:  0,
+		}
+		startEventID = curr.Trace.DBQueryStart(trace2.DBQueryStartParams{
+			EventParams: eventParams,
+			Query:       l.stmt,
+			Stack:       stack.Build(4),
+		})
+	}
+
+	conn, err := l.connect(ctx)
+	err = convertErr(err)
+
+	if curr.Trace != nil {
+		curr.Trace.DBQueryEnd(eventParams, startEventID, err)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	if !db.addListener(l, cancel) {
+		cancel()
+		_ = conn.Close(context.Background())
+		return nil, errDBClosed
+	}
+
+	ch := make(chan Notification)
+	go l.run(ctx, conn, ch)
+	return ch, nil
+}
+
+var errDBClosed = errors.New("sqldb: database is shutting down")
+
+// listener listens for notifications on a dedicated database connection.
+type listener struct {
+	db   *Database
+	stmt string // the LISTEN statement
+}
+
+const (
+	listenMinBackoff = 100 * time.Millisecond
+	listenMaxBackoff = 10 * time.Second
+)
+
+// connect opens a new connection to the database and starts listening on it.
+func (l *listener) connect(ctx context.Context) (*pgx.Conn, error) {
+	// The connection is long-lived and outlives the request that
+	// started listening, so don't trace the queries made on it.
+	cfg := l.db.pool.Config().ConnConfig.Copy()
+	cfg.Tracer = nil
+
+	conn, err := pgx.ConnectConfig(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.db.hooks.runAfterConnectHooks(ctx, conn); err != nil {
+		_ = conn.Close(context.Background())
+		return nil, err
+	}
+	if _, err := conn.Exec(ctx, l.stmt); err != nil {
+		_ = conn.Close(context.Background())
+		return nil, err
+	}
+	return conn, nil
+}
+
+// run delivers notifications received on conn to ch until ctx is canceled,
+// reconnecting if the connection is lost.
+func (l *listener) run(ctx context.Context, conn *pgx.Conn, ch chan<- Notification) {
+	defer func() {
+		l.db.removeListener(l)
+		close(ch)
+		if conn != nil {
+			_ = conn.Close(context.Background())
+		}
+	}()
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err == nil {
+			select {
+			case ch <- Notification{Channel: n.Channel, Payload: n.Payload}:
+			case <-ctx.Done():
+				return
+			}
+			continue
+		} else if ctx.Err() != nil {
+			return
+		}
+
+		l.db.mgr.rootLogger.Warn().Err(err).Str("database", l.db.origName).
+			Msg("sqldb: lost connection listening for notifications, reconnecting")
+		_ = conn.Close(context.Background())
+		if conn = l.reconnect(ctx); conn == nil {
+			return
+		}
+	}
+}
+
+// reconnect reconnects to the database with exponential backoff until it succeeds,
+// or until ctx is canceled in which case it returns nil.
+func (l *listener) reconnect(ctx context.Context) *pgx.Conn {
+	backoff := listenMinBackoff
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+
+		conn, err := l.connect(ctx)
+		if err == nil {
+			return conn
+		} else if ctx.Err() != nil {
+			return nil
+		}
+		l.db.mgr.rootLogger.Warn().Err(err).Str("database", l.db.origName).Dur("backoff", backoff).
+			Msg("sqldb: failed to reconnect to listen for notifications")
+		backoff = min(backoff*2, listenMaxBackoff)
+	}
+}
+
+// addListener registers a listener to be stopped with cancel when the database shuts down.
+// It reports false if the database has already been shut down.
+func (db *Database) addListener(l *listener, cancel context.CancelFunc) bool {
+	db.listenMu.Lock()
+	defer db.listenMu.Unlock()
+	if db.listenClosed {
+		return false
+	}
+	if db.listeners == nil {
+		db.listeners = make(map[*listener]context.CancelFunc)
+	}
+	db.listeners[l] = cancel
+	return true
+}
+
+func (db *Database) removeListener(l *listener) {
+	db.listenMu.Lock()
+	defer db.listenMu.Unlock()
+	delete(db.listeners, l)
+}
+
+// stopListeners stops all listeners and prevents new ones from being started.
+func (db *Database) stopListeners() {
+	db.listenMu.Lock()
+	defer db.listenMu.Unlock()
+	db.listenClosed = true
+	for _, cancel := range db.listeners {
+		cancel()
+	}
+}
@@ -0,0 +1,210 @@
+package sqldb
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgproto3"
+	"github.com/rs/zerolog"
+
+	"encore.dev/appruntime/exported/config"
+	"encore.dev/appruntime/shared/reqtrack"
+)
+
+func TestListen(t *testing.T) {
+	srv := newFakePostgres(t)
+	rt := reqtrack.New(zerolog.Nop(), nil, nil)
+	mgr := NewManager(&config.Runtime{
+		SQLServers: []*config.SQLServer{{Host: srv.addr()}},
+		SQLDatabases: []*config.SQLDatabase{{
+			EncoreName:   "mydb",
+			DatabaseName: "mydb",
+			User:         "user",
+			Password:     "password",
+		}},
+	}, rt, nil, zerolog.Nop(), nil)
+	db := mgr.GetDB("mydb")
+	t.Cleanup(db.shutdown)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := db.Listen(ctx, "my channel")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn := srv.nextListener(t)
+	if want := `LISTEN "my channel"`; conn.query != want {
+		t.Fatalf("got query %q, want %q", conn.query, want)
+	}
+
+	conn.notify("my channel", "hello")
+	if got := receive(t, ch); got != (Notification{Channel: "my channel", Payload: "hello"}) {
+		t.Fatalf("got notification %+v, want payload %q", got, "hello")
+	}
+
+	// Drop the connection; the listener should reconnect and listen again.
+	conn.close()
+	conn = srv.nextListener(t)
+	conn.notify("my channel", "again")
+	if got := receive(t, ch); got.Payload != "again" {
+		t.Fatalf("got payload %q after reconnecting, want %q", got.Payload, "again")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Fatal("got notification after canceling")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("channel not closed after canceling")
+	}
+}
+
+func TestListen_ReadReplica(t *testing.T) {
+	srv := newFakePostgres(t)
+	mgr := NewManager(&config.Runtime{
+		SQLServers: []*config.SQLServer{
+			{Host: srv.addr()},
+			{Host: "127.0.0.1:1"}, // the replica, which is never connected to
+		},
+		SQLDatabases: []*config.SQLDatabase{{
+			EncoreName:   "mydb",
+			DatabaseName: "mydb",
+			User:         "user",
+			Password:     "password",
+			ReadReplicas: []*config.SQLReadReplica{{Name: "replica-1", ServerID: 1}},
+		}},
+	}, reqtrack.New(zerolog.Nop(), nil, nil), nil, zerolog.Nop(), nil)
+	db := mgr.GetDB("mydb")
+	t.Cleanup(db.shutdown)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := db.ReadOnly().Listen(ctx, "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn := srv.nextListener(t)
+	conn.notify("events", "hello")
+	if got := receive(t, ch); got.Payload != "hello" {
+		t.Fatalf("got payload %q, want %q", got.Payload, "hello")
+	}
+}
+
+func receive(t *testing.T, ch <-chan Notification) Notification {
+	t.Helper()
+	select {
+	case n, ok := <-ch:
+		if !ok {
+			t.Fatal("notification channel closed")
+		}
+		return n
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for notification")
+		return Notification{}
+	}
+}
+
+// fakePostgres is a minimal server speaking the Postgres protocol,
+// which accepts any simple query.
+type fakePostgres struct {
+	ln        net.Listener
+	listeners chan *fakeConn // connections that have run a query
+}
+
+func newFakePostgres(t *testing.T) *fakePostgres {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	s := &fakePostgres{ln: ln, listeners: make(chan *fakeConn, 10)}
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(c)
+		}
+	}()
+	return s
+}
+
+func (s *fakePostgres) addr() string { return s.ln.Addr().String() }
+
+func (s *fakePostgres) nextListener(t *testing.T) *fakeConn {
+	t.Helper()
+	select {
+	case c := <-s.listeners:
+		return c
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for connection")
+		return nil
+	}
+}
+
+func (s *fakePostgres) serve(c net.Conn) {
+	defer func() { _ = c.Close() }()
+	fc := &fakeConn{conn: c, backend: pgproto3.NewBackend(c, c)}
+
+	for {
+		msg, err := fc.backend.ReceiveStartupMessage()
+		if err != nil {
+			return
+		}
+		if _, ok := msg.(*pgproto3.SSLRequest); ok {
+			if _, err := c.Write([]byte("N")); err != nil {
+				return
+			}
+			continue
+		}
+		break
+	}
+	fc.send(&pgproto3.AuthenticationOk{}, &pgproto3.BackendKeyData{ProcessID: 1, SecretKey: 1}, &pgproto3.ReadyForQuery{TxStatus: 'I'})
+
+	for {
+		msg, err := fc.backend.Receive()
+		if err != nil {
+			return
+		}
+		switch msg := msg.(type) {
+		case *pgproto3.Query:
+			fc.query = msg.String
+			fc.send(&pgproto3.CommandComplete{CommandTag: []byte("LISTEN")}, &pgproto3.ReadyForQuery{TxStatus: 'I'})
+			s.listeners <- fc
+		case *pgproto3.Terminate:
+			return
+		}
+	}
+}
+
+type fakeConn struct {
+	conn    net.Conn
+	backend *pgproto3.Backend
+	query   string
+
+	mu sync.Mutex // protects writes to backend
+}
+
+func (c *fakeConn) send(msgs ...pgproto3.BackendMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, msg := range msgs {
+		c.backend.Send(msg)
+	}
+	_ = c.backend.Flush()
+}
+
+func (c *fakeConn) notify(channel, payload string) {
+	c.send(&pgproto3.NotificationResponse{PID: 1, Channel: channel, Payload: payload})
+}
+
+func (c *fakeConn) close() { _ = c.conn.Close() }
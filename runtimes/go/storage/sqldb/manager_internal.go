@@ -2,6 +2,7 @@ package sqldb
 
 import (
 	"context"
+	"slices"
 	"sync"
 
 	"github.com/jackc/pgx/v5"
@@ -12,6 +13,7 @@ import (
 	"encore.dev/appruntime/shared/reqtrack"
 	"encore.dev/appruntime/shared/shutdown"
 	"encore.dev/appruntime/shared/testsupport"
+	"encore.dev/metrics"
 )
 
 // Manager manages database connections.
@@ -21,18 +23,24 @@ type Manager struct {
 	ts         *testsupport.Manager
 	rootLogger zerolog.Logger
 
+	statementsTotal *metrics.CounterGroup[statementsTotalLabels, uint64] // nil if metrics are disabled
+
 	mu  sync.RWMutex
 	dbs map[string]*Database
 }
 
-func NewManager(runtime *config.Runtime, rt *reqtrack.RequestTracker, ts *testsupport.Manager, rootLogger zerolog.Logger) *Manager {
-	return &Manager{
+func NewManager(runtime *config.Runtime, rt *reqtrack.RequestTracker, ts *testsupport.Manager, rootLogger zerolog.Logger, reg *metrics.Registry) *Manager {
+	mgr := &Manager{
 		runtime:    runtime,
 		rt:         rt,
 		ts:         ts,
 		rootLogger: rootLogger,
 		dbs:        make(map[string]*Database),
 	}
+	if reg != nil {
+		mgr.statementsTotal = newStatementsTotal(reg)
+	}
+	return mgr
 }
 
 // GetCurrentDB gets the database for the current request.
@@ -63,7 +71,7 @@ func (mgr *Manager) GetDB(dbName string) *Database {
 		return db
 	}
 	hooks := &hookList{}
-	pool, found := mgr.getPool(dbName, "", hooks)
+	pool, found := mgr.getPool(dbName, "", "", hooks)
 	db = &Database{
 		name:     dbName,
 		origName: dbName,
@@ -76,27 +84,48 @@ func (mgr *Manager) GetDB(dbName string) *Database {
 	return db
 }
 
-// getPool returns a database connection pool for the given database name.
-// Each time it's called it returns a new pool.
-func (mgr *Manager) getPool(encoreName, dbNameOverride string, hooks *hookList) (pool *pgxpool.Pool, found bool) {
-	var db *config.SQLDatabase
+// dbConfig returns the configuration for the database with the given name,
+// or nil if it's not configured.
+func (mgr *Manager) dbConfig(encoreName string) *config.SQLDatabase {
 	for _, d := range mgr.runtime.SQLDatabases {
 		if d.EncoreName == encoreName {
-			db = d
-			break
+			return d
 		}
 	}
+	return nil
+}
+
+// getPool returns a database connection pool for the given database name,
+// connected to the named read replica if replica is non-empty.
+// Each time it's called it returns a new pool.
+func (mgr *Manager) getPool(encoreName, replica, dbNameOverride string, hooks *hookList) (pool *pgxpool.Pool, found bool) {
+	db := mgr.dbConfig(encoreName)
 	if db == nil {
 		return nil, false
 	}
 
-	srv := mgr.runtime.SQLServers[db.ServerID]
+	serverID := db.ServerID
+	if replica != "" {
+		idx := slices.IndexFunc(db.ReadReplicas, func(r *config.SQLReadReplica) bool {
+			return r.Name == replica
+		})
+		if idx < 0 {
+			return nil, false
+		}
+		serverID = db.ReadReplicas[idx].ServerID
+	}
+
+	srv := mgr.runtime.SQLServers[serverID]
 	cfg, err := dbConf(srv, db, dbNameOverride)
 	if err != nil {
 		panic("sqldb: " + err.Error())
 	}
 
-	cfg.ConnConfig.Tracer = &pgxTracer{mgr: mgr}
+	tracer := &pgxTracer{mgr: mgr}
+	if replica != "" {
+		tracer.replica = &replica
+	}
+	cfg.ConnConfig.Tracer = tracer
 	cfg.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
 		return hooks.runAfterConnectHooks(ctx, conn)
 	}
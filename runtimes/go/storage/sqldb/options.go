@@ -0,0 +1,241 @@
+package sqldb
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"encore.dev/metrics"
+)
+
+// QueryOption configures how a single query is run. Query options are passed
+// together with the query's args to Exec, Query, and QueryRow, and aren't
+// sent to the database as placeholder parameters:
+//
+//	db.Query(ctx, "SELECT ...", sqldb.WithTimeout(5*time.Second), arg1, arg2)
+type QueryOption func(*queryOptions)
+
+type queryOptions struct {
+	timeout time.Duration
+	name    string
+}
+
+// WithTimeout sets the statement timeout of the query, so the database
+// cancels it if it runs for longer than d. Queries canceled because of the
+// timeout report an error with the code sqlerr.QueryCanceled.
+//
+// The timeout is set with PostgreSQL's statement_timeout setting for just the
+// query, which takes additional round trips to the database.
+// It has millisecond granularity, and is ignored if d is less than a millisecond.
+func WithTimeout(d time.Duration) QueryOption {
+	return func(o *queryOptions) { o.timeout = d }
+}
+
+// WithStatementName labels the query with the given name, which is recorded
+// in traces and in the e_sqldb_statements_total metric.
+//
+// The name should identify what the query does, like "list-orders",
+// and must not be derived from the query's parameters.
+func WithStatementName(name string) QueryOption {
+	return func(o *queryOptions) { o.name = name }
+}
+
+// splitQueryOptions separates the query options in args from the placeholder parameters.
+func splitQueryOptions(args []any) (opts queryOptions, params []any) {
+	for i, arg := range args {
+		if _, ok := arg.(QueryOption); ok {
+			params = append(params, args[:i]...)
+			for _, arg := range args[i:] {
+				if opt, ok := arg.(QueryOption); ok {
+					opt(&opts)
+				} else {
+					params = append(params, arg)
+				}
+			}
+			return opts, params
+		}
+	}
+	return opts, args
+}
+
+// statementName returns the name of the query, or nil if it's not named.
+func (o *queryOptions) statementName() *string {
+	if o.name == "" {
+		return nil
+	}
+	return &o.name
+}
+
+// querier is implemented by both *pgxpool.Pool and pgx.Tx.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// poolQuerier is the querierFunc for queries run on the database's connection pool.
+// If the query has a timeout it's run in a transaction with the timeout set.
+func (db *Database) poolQuerier(ctx context.Context, opts queryOptions) (q querier, done func(error) error, err error) {
+	if opts.timeout < time.Millisecond {
+		return db.pool, noopDone, nil
+	}
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := tx.Exec(ctx, "SET LOCAL statement_timeout = "+strconv.FormatInt(opts.timeout.Milliseconds(), 10)); err != nil {
+		_ = tx.Rollback(context.Background())
+		return nil, nil, err
+	}
+
+	return tx, func(err error) error {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+			return err
+		}
+		return tx.Commit(context.Background())
+	}, nil
+}
+
+// txQuerier is the querierFunc for queries run in the transaction tx.
+//
+// If the query has a timeout, it's set for the transaction before running the query
+// and the previous timeout is restored afterwards.
+func txQuerier(ctx context.Context, tx pgx.Tx, opts queryOptions) (q querier, done func(error) error, err error) {
+	if opts.timeout < time.Millisecond {
+		return tx, noopDone, nil
+	}
+
+	var prev string
+	if err := tx.QueryRow(ctx, "SHOW statement_timeout").Scan(&prev); err != nil {
+		return nil, nil, err
+	}
+	if _, err := tx.Exec(ctx, "SET LOCAL statement_timeout = "+strconv.FormatInt(opts.timeout.Milliseconds(), 10)); err != nil {
+		return nil, nil, err
+	}
+
+	return tx, func(err error) error {
+		if err != nil {
+			// The transaction has failed, so the timeout can't be restored,
+			// and doesn't matter anymore.
+			return err
+		}
+		_, err = tx.Exec(context.Background(), "SELECT set_config('statement_timeout', $1, true)", prev)
+		return err
+	}, nil
+}
+
+func noopDone(err error) error { return err }
+
+// doneRows calls done when the rows are closed, either explicitly
+// or by reading all of them.
+type doneRows struct {
+	pgx.Rows
+	done   func(error) error
+	err    error
+	closed bool
+}
+
+func (r *doneRows) Next() bool {
+	if r.closed {
+		return false
+	}
+	if r.Rows.Next() {
+		return true
+	}
+	r.Close()
+	return false
+}
+
+func (r *doneRows) Close() {
+	if r.closed {
+		return
+	}
+	r.closed = true
+	r.Rows.Close()
+	r.err = r.done(r.Rows.Err())
+}
+
+func (r *doneRows) Err() error {
+	if r.closed {
+		return r.err
+	}
+	return r.Rows.Err()
+}
+
+// querierFunc returns the querier to run a query with the given options with, and a function to
+// call with the query's error once it has finished that returns the final error.
+type querierFunc func(ctx context.Context, opts queryOptions) (q querier, done func(error) error, err error)
+
+// execWith runs an Exec query with the given options on the querier returned by setup.
+func (mgr *Manager) execWith(ctx context.Context, dbName string, setup querierFunc, opts queryOptions, query string, args []any) (pgconn.CommandTag, error) {
+	var res pgconn.CommandTag
+	q, done, err := setup(ctx, opts)
+	if err == nil {
+		res, err = q.Exec(ctx, query, args...)
+		err = done(err)
+	}
+	err = convertErr(err)
+	mgr.recordStatement(dbName, opts, err)
+	return res, err
+}
+
+// queryWith runs a query returning rows with the given options on the querier returned by setup.
+// The query is finished when the rows are closed.
+func (mgr *Manager) queryWith(ctx context.Context, dbName string, setup querierFunc, opts queryOptions, query string, args []any) (pgx.Rows, error) {
+	q, done, err := setup(ctx, opts)
+	if err == nil {
+		var rows pgx.Rows
+		if rows, err = q.Query(ctx, query, args...); err == nil {
+			if opts != (queryOptions{}) {
+				rows = &doneRows{Rows: rows, done: func(err error) error {
+					err = done(err)
+					mgr.recordStatement(dbName, opts, convertErr(err))
+					return err
+				}}
+			}
+			return rows, nil
+		}
+		err = done(err)
+	}
+	err = convertErr(err)
+	mgr.recordStatement(dbName, opts, err)
+	return nil, err
+}
+
+type statementsTotalLabels struct {
+	database  string // Encore name of the database.
+	statement string // Name of the statement, as given by WithStatementName.
+	code      string // "ok", or the sqlerr.Code of the error.
+}
+
+func newStatementsTotal(reg *metrics.Registry) *metrics.CounterGroup[statementsTotalLabels, uint64] {
+	return metrics.NewCounterGroupInternal[statementsTotalLabels, uint64](reg, "e_sqldb_statements_total", metrics.CounterConfig{
+		EncoreInternal_LabelMapper: func(labels statementsTotalLabels) []metrics.KeyValue {
+			return []metrics.KeyValue{
+				{Key: "database", Value: labels.database},
+				{Key: "statement", Value: labels.statement},
+				{Key: "code", Value: labels.code},
+			}
+		},
+	})
+}
+
+// recordStatement records the outcome of a query in the metrics, if it's named.
+func (mgr *Manager) recordStatement(dbName string, opts queryOptions, err error) {
+	if opts.name == "" || mgr.statementsTotal == nil {
+		return
+	}
+	code := "ok"
+	if err != nil {
+		code = string(ErrCode(err))
+	}
+	mgr.statementsTotal.With(statementsTotalLabels{
+		database:  dbName,
+		statement: opts.name,
+		code:      code,
+	}).Increment()
+}
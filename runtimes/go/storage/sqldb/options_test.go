@@ -0,0 +1,128 @@
+package sqldb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog"
+
+	"encore.dev/appruntime/exported/config"
+	"encore.dev/appruntime/exported/model"
+	"encore.dev/appruntime/shared/reqtrack"
+	"encore.dev/metrics"
+)
+
+func TestSplitQueryOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []any
+		wantOpts   queryOptions
+		wantParams []any
+	}{
+		{
+			name:       "no_options",
+			args:       []any{1, "two"},
+			wantParams: []any{1, "two"},
+		},
+		{
+			name:       "options_first",
+			args:       []any{WithTimeout(time.Second), WithStatementName("list"), 1, "two"},
+			wantOpts:   queryOptions{timeout: time.Second, name: "list"},
+			wantParams: []any{1, "two"},
+		},
+		{
+			name:       "options_interleaved",
+			args:       []any{1, WithStatementName("list"), "two", WithTimeout(time.Second)},
+			wantOpts:   queryOptions{timeout: time.Second, name: "list"},
+			wantParams: []any{1, "two"},
+		},
+		{
+			name:     "only_options",
+			args:     []any{WithTimeout(time.Second)},
+			wantOpts: queryOptions{timeout: time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, params := splitQueryOptions(tt.args)
+			if opts != tt.wantOpts {
+				t.Errorf("got opts %+v, want %+v", opts, tt.wantOpts)
+			}
+			if len(params) != 0 || len(tt.wantParams) != 0 {
+				if !reflect.DeepEqual(params, tt.wantParams) {
+					t.Errorf("got params %v, want %v", params, tt.wantParams)
+				}
+			}
+		})
+	}
+}
+
+func TestRecordStatement(t *testing.T) {
+	rt := reqtrack.New(zerolog.Nop(), nil, nil)
+	reg := metrics.NewRegistry(rt, 1)
+	mgr := NewManager(&config.Runtime{}, rt, nil, zerolog.Nop(), reg)
+
+	// Metrics are recorded for the service handling the current request.
+	rt.BeginRequest(&model.Request{SvcNum: 1})
+	defer rt.FinishRequest(false)
+
+	named := queryOptions{name: "list"}
+	mgr.recordStatement("mydb", named, nil)
+	mgr.recordStatement("mydb", named, nil)
+	mgr.recordStatement("mydb", named, &Error{Code: "query_canceled"})
+	mgr.recordStatement("mydb", queryOptions{}, nil) // not recorded
+
+	got := make(map[string]uint64)
+	for _, m := range reg.Collect() {
+		if m.Info.Name() != "e_sqldb_statements_total" {
+			continue
+		}
+		var key string
+		for _, l := range m.Labels {
+			key += l.Key + "=" + l.Value + ","
+		}
+		for _, v := range m.Val.([]uint64) {
+			got[key] += v
+		}
+	}
+	want := map[string]uint64{
+		"database=mydb,statement=list,code=ok,":             2,
+		"database=mydb,statement=list,code=query_canceled,": 1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestExecWithDone(t *testing.T) {
+	mgr := NewManager(&config.Runtime{}, nil, nil, zerolog.Nop(), nil)
+	queryErr := errors.New("query failed")
+
+	var doneErr error
+	setup := func(ctx context.Context, opts queryOptions) (querier, func(error) error, error) {
+		return &fakeQuerier{err: queryErr}, func(err error) error {
+			doneErr = err
+			return err
+		}, nil
+	}
+	_, err := mgr.execWith(context.Background(), "mydb", setup, queryOptions{}, "SELECT 1", nil)
+	if !errors.Is(err, queryErr) {
+		t.Errorf("got err %v, want %v", err, queryErr)
+	}
+	if doneErr != queryErr {
+		t.Errorf("done called with %v, want %v", doneErr, queryErr)
+	}
+}
+
+type fakeQuerier struct {
+	querier
+	err error
+}
+
+func (q *fakeQuerier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, q.err
+}
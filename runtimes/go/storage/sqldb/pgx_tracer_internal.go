@@ -11,7 +11,8 @@ import (
 )
 
 type pgxTracer struct {
-	mgr *Manager
+	mgr     *Manager
+	replica *string // the read replica the pool is connected to, if any
 }
 
 type ctxKey string
@@ -51,6 +52,7 @@ func (t *pgxTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pg
 			EventParams: eventParams,
 			Query:       data.SQL,
 			Stack:       stack.Build(5),
+			Replica:     t.replica,
 		})
 		ctx = context.WithValue(ctx, pgxQueryKey, &queryValue{
 			trace:       curr.Trace,
@@ -0,0 +1,182 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	mathrand "math/rand" // nosemgrep
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"encore.dev/appruntime/exported/model"
+	"encore.dev/appruntime/exported/stack"
+	"encore.dev/appruntime/exported/trace2"
+	"encore.dev/storage/sqldb/sqlerr"
+)
+
+// TxOptions configures transactions run with ExecuteTx.
+// The zero value uses the defaults documented for each field.
+type TxOptions struct {
+	// IsoLevel is the isolation level of the transaction.
+	// If zero, the database's default isolation level is used.
+	IsoLevel sql.IsolationLevel
+
+	// ReadOnly specifies whether the transaction is read-only.
+	ReadOnly bool
+
+	// MaxAttempts is the maximum number of times the transaction is attempted,
+	// including the first attempt. If zero it defaults to 5.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry, which doubles
+	// with each retry up to MaxBackoff. Delays are randomly reduced by up to
+	// half, to spread out retries of conflicting transactions.
+	// If zero it defaults to 10ms.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the maximum delay between retries. If zero it defaults to 1s.
+	MaxBackoff time.Duration
+}
+
+const (
+	defaultTxMaxAttempts    = 5
+	defaultTxInitialBackoff = 10 * time.Millisecond
+	defaultTxMaxBackoff     = time.Second
+)
+
+// ExecuteTx runs fn in a transaction on db and commits it.
+//
+// If the transaction fails because it couldn't be serialized with concurrent
+// transactions or because of a deadlock, as reported by fn or when committing,
+// it's rolled back and fn is run again in a new transaction, after a delay that
+// grows exponentially with each attempt. Other errors returned by fn roll back
+// the transaction and are returned as-is.
+//
+// Since fn may be run multiple times it must not have side effects
+// outside the transaction. If opts is nil the defaults are used.
+//
+// Each attempt is traced as a separate transaction.
+func ExecuteTx(ctx context.Context, db *Database, opts *TxOptions, fn func(tx *Tx) error) error {
+	if opts == nil {
+		opts = &TxOptions{}
+	}
+	isoLevel, err := pgxIsoLevel(opts.IsoLevel)
+	if err != nil {
+		return err
+	}
+	txOpts := pgx.TxOptions{IsoLevel: isoLevel}
+	if opts.ReadOnly {
+		txOpts.AccessMode = pgx.ReadOnly
+	}
+
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultTxMaxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := db.attemptTx(ctx, txOpts, fn)
+		if err == nil || !isRetryableTxErr(err) || attempt >= maxAttempts {
+			return err
+		}
+
+		delay := txBackoff(opts, attempt)
+		db.traceTxRetry(attempt, delay, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// attemptTx runs fn in a new transaction and commits it,
+// rolling it back if fn returns an error or panics.
+func (db *Database) attemptTx(ctx context.Context, opts pgx.TxOptions, fn func(tx *Tx) error) (err error) {
+	tx, err := db.begin(ctx, opts, 6)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		_ = tx.rollback()
+		return err
+	}
+	return tx.commit()
+}
+
+// traceTxRetry records in the trace that a transaction attempt
+// failed with err and is retried after delay.
+func (db *Database) traceTxRetry(attempt int, delay time.Duration, err error) {
+	curr := db.mgr.rt.Current()
+	if curr.Req == nil || curr.Trace == nil {
+		return
+	}
+	curr.Trace.LogMessage(trace2.LogMessageParams{
+		EventParams: trace2.EventParams{
+			TraceID: curr.Req.TraceID,
+			SpanID:  curr.Req.SpanID,
+			Goid:    curr.Goctr,
+		},
+		Level: model.LevelWarn,
+		Msg:   "retrying database transaction",
+		Stack: stack.Build(5),
+		Fields: []trace2.LogField{
+			{Key: "attempt", Value: attempt},
+			{Key: "backoff", Value: delay},
+			{Key: "error", Value: err},
+		},
+	})
+}
+
+// isRetryableTxErr reports whether a transaction that failed with err can be retried.
+func isRetryableTxErr(err error) bool {
+	switch ErrCode(err) {
+	case sqlerr.SerializationFailure, sqlerr.DeadlockDetected:
+		return true
+	default:
+		return false
+	}
+}
+
+// txBackoff returns the delay before retrying a transaction after the given failed attempt.
+func txBackoff(opts *TxOptions, attempt int) time.Duration {
+	backoff, maxBackoff := opts.InitialBackoff, opts.MaxBackoff
+	if backoff <= 0 {
+		backoff = defaultTxInitialBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultTxMaxBackoff
+	}
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, maxBackoff)
+	return backoff/2 + time.Duration(mathrand.Int63n(int64(backoff/2)+1))
+}
+
+// pgxIsoLevel maps a database/sql isolation level to the pgx equivalent.
+func pgxIsoLevel(level sql.IsolationLevel) (pgx.TxIsoLevel, error) {
+	switch level {
+	case sql.LevelDefault:
+		return "", nil
+	case sql.LevelReadUncommitted:
+		return pgx.ReadUncommitted, nil
+	case sql.LevelReadCommitted:
+		return pgx.ReadCommitted, nil
+	case sql.LevelRepeatableRead:
+		return pgx.RepeatableRead, nil
+	case sql.LevelSerializable:
+		return pgx.Serializable, nil
+	default:
+		return "", fmt.Errorf("sqldb: unsupported isolation level %v", level)
+	}
+}
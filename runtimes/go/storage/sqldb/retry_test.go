@@ -0,0 +1,73 @@
+package sqldb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"encore.dev/storage/sqldb/sqlerr"
+)
+
+func TestIsRetryableTxErr(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: errors.New("some error"), want: false},
+		{err: &Error{Code: sqlerr.UniqueViolation}, want: false},
+		{err: &Error{Code: sqlerr.SerializationFailure}, want: true},
+		{err: &Error{Code: sqlerr.DeadlockDetected}, want: true},
+		{err: fmt.Errorf("wrapped: %w", &Error{Code: sqlerr.SerializationFailure}), want: true},
+	}
+	for _, tt := range tests {
+		if got := isRetryableTxErr(tt.err); got != tt.want {
+			t.Errorf("isRetryableTxErr(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestTxBackoff(t *testing.T) {
+	tests := []struct {
+		opts    TxOptions
+		attempt int
+		max     time.Duration
+	}{
+		{opts: TxOptions{}, attempt: 1, max: 10 * time.Millisecond},
+		{opts: TxOptions{}, attempt: 3, max: 40 * time.Millisecond},
+		{opts: TxOptions{}, attempt: 20, max: time.Second},
+		{opts: TxOptions{InitialBackoff: time.Second, MaxBackoff: 3 * time.Second}, attempt: 2, max: 2 * time.Second},
+		{opts: TxOptions{InitialBackoff: time.Second, MaxBackoff: 3 * time.Second}, attempt: 5, max: 3 * time.Second},
+	}
+	for _, tt := range tests {
+		for range 100 {
+			if got := txBackoff(&tt.opts, tt.attempt); got < tt.max/2 || got > tt.max {
+				t.Fatalf("txBackoff(%+v, %d) = %v, want between %v and %v", tt.opts, tt.attempt, got, tt.max/2, tt.max)
+			}
+		}
+	}
+}
+
+func TestPgxIsoLevel(t *testing.T) {
+	tests := []struct {
+		level   sql.IsolationLevel
+		want    pgx.TxIsoLevel
+		wantErr bool
+	}{
+		{level: sql.LevelDefault, want: ""},
+		{level: sql.LevelReadCommitted, want: pgx.ReadCommitted},
+		{level: sql.LevelSerializable, want: pgx.Serializable},
+		{level: sql.LevelLinearizable, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := pgxIsoLevel(tt.level)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("pgxIsoLevel(%v): got err %v, want err %v", tt.level, err, tt.wantErr)
+		} else if got != tt.want {
+			t.Errorf("pgxIsoLevel(%v) = %q, want %q", tt.level, got, tt.want)
+		}
+	}
+}
@@ -34,6 +34,13 @@ type Tx struct {
 	std pgx.Tx
 
 	startID model.TraceEventID
+	replica *string // the read replica the transaction is on, if any
+	dbName  string  // the Encore name of the database
+}
+
+// querier is the querierFunc for queries run in the transaction.
+func (tx *Tx) querier(ctx context.Context, opts queryOptions) (querier, func(error) error, error) {
+	return txQuerier(ctx, tx.std, opts)
 }
 
 // Commit commits the given transaction.
@@ -95,6 +102,7 @@ func (tx *Tx) Exec(ctx context.Context, query string, args ...interface{}) (Exec
 }
 
 func (tx *Tx) exec(ctx context.Context, query string, args ...interface{}) (ExecResult, error) {
+	opts, args := splitQueryOptions(args)
 	curr := tx.mgr.rt.Current()
 
 	var (
@@ -114,11 +122,12 @@ func (tx *Tx) exec(ctx context.Context, query string, args ...interface{}) (Exec
 			TxStartID:   tx.startID,
 			Query:       query,
 			Stack:       stack.Build(4),
+			Replica:     tx.replica,
+			Name:        opts.statementName(),
 		})
 	}
 
-	res, err := tx.std.Exec(markTraced(ctx), query, args...)
-	err = convertErr(err)
+	res, err := tx.mgr.execWith(markTraced(ctx), tx.dbName, tx.querier, opts, query, args)
 
 	if startEventID > 0 {
 		curr.Trace.DBQueryEnd(eventParams, startEventID, err)
@@ -128,6 +137,7 @@ func (tx *Tx) exec(ctx context.Context, query string, args ...interface{}) (Exec
 }
 
 func (tx *Tx) Query(ctx context.Context, query string, args ...interface{}) (*Rows, error) {
+	opts, args := splitQueryOptions(args)
 	curr := tx.mgr.rt.Current()
 
 	var (
@@ -147,11 +157,12 @@ func (tx *Tx) Query(ctx context.Context, query string, args ...interface{}) (*Ro
 			Query:       query,
 			TxStartID:   tx.startID,
 			Stack:       stack.Build(4),
+			Replica:     tx.replica,
+			Name:        opts.statementName(),
 		})
 	}
 
-	rows, err := tx.std.Query(markTraced(ctx), query, args...)
-	err = convertErr(err)
+	rows, err := tx.mgr.queryWith(markTraced(ctx), tx.dbName, tx.querier, opts, query, args)
 
 	if startEventID > 0 {
 		curr.Trace.DBQueryEnd(eventParams, startEventID, err)
@@ -164,6 +175,7 @@ func (tx *Tx) Query(ctx context.Context, query string, args ...interface{}) (*Ro
 }
 
 func (tx *Tx) QueryRow(ctx context.Context, query string, args ...interface{}) *Row {
+	opts, args := splitQueryOptions(args)
 	curr := tx.mgr.rt.Current()
 
 	var (
@@ -183,13 +195,14 @@ func (tx *Tx) QueryRow(ctx context.Context, query string, args ...interface{}) *
 			Query:       query,
 			TxStartID:   tx.startID,
 			Stack:       stack.Build(4),
+			Replica:     tx.replica,
+			Name:        opts.statementName(),
 		})
 	}
 
 	// pgx currently does not support .Err() on Row.
 	// Work around this by using Query.
-	rows, err := tx.std.Query(markTraced(ctx), query, args...)
-	err = convertErr(err)
+	rows, err := tx.mgr.queryWith(markTraced(ctx), tx.dbName, tx.querier, opts, query, args)
 	r := &Row{rows: rows, err: err}
 
 	if startEventID > 0 {
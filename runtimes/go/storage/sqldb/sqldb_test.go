@@ -6,6 +6,9 @@ import (
 	"testing"
 	_ "unsafe" // for go:linkname
 
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+
 	"encore.dev/appruntime/exported/config"
 )
 
@@ -91,3 +94,76 @@ func TestDBConf(t *testing.T) {
 		}
 	}
 }
+
+func TestReadReplica(t *testing.T) {
+	mgr := NewManager(&config.Runtime{
+		SQLServers: []*config.SQLServer{
+			{Host: "primary:5432"},
+			{Host: "replica:5433"},
+		},
+		SQLDatabases: []*config.SQLDatabase{
+			{
+				ServerID:     0,
+				EncoreName:   "replicated",
+				DatabaseName: "replicated",
+				User:         "user",
+				Password:     "password",
+				ReadReplicas: []*config.SQLReadReplica{{Name: "replica-1", ServerID: 1}},
+			},
+			{
+				ServerID:     0,
+				EncoreName:   "single",
+				DatabaseName: "single",
+				User:         "user",
+				Password:     "password",
+			},
+		},
+	}, nil, nil, zerolog.Nop(), nil)
+
+	db := mgr.GetDB("replicated")
+	t.Cleanup(db.shutdown)
+
+	ro := db.ReadOnly()
+	if ro == db {
+		t.Fatal("ReadOnly returned the primary database")
+	} else if ro.replica != "replica-1" {
+		t.Fatalf("got replica %q, want %q", ro.replica, "replica-1")
+	}
+	ro.init()
+	if host, port := ro.pool.Config().ConnConfig.Host, ro.pool.Config().ConnConfig.Port; host != "replica" || port != 5433 {
+		t.Fatalf("got replica host %s:%d, want replica:5433", host, port)
+	}
+
+	if got := db.ReadReplica("replica-1"); got != ro {
+		t.Error("ReadReplica returned a different handle than ReadOnly")
+	}
+	if got := ro.ReadOnly(); got != ro {
+		t.Error("ReadOnly on a replica returned a different handle")
+	}
+	if got := db.ReadReplica("unknown"); got != db {
+		t.Error("ReadReplica with an unknown name didn't return the primary database")
+	}
+
+	single := mgr.GetDB("single")
+	t.Cleanup(single.shutdown)
+	if got := single.ReadOnly(); got != single {
+		t.Error("ReadOnly without replicas didn't return the primary database")
+	}
+}
+
+func TestCopyQuery(t *testing.T) {
+	tests := []struct {
+		table   string
+		columns []string
+		want    string
+	}{
+		{table: "events", columns: []string{"id", "name"}, want: `COPY "events" ("id", "name") FROM STDIN`},
+		{table: "analytics.events", columns: []string{"id"}, want: `COPY "analytics"."events" ("id") FROM STDIN`},
+		{table: `weird"table`, columns: []string{`weird"col`}, want: `COPY "weird""table" ("weird""col") FROM STDIN`},
+	}
+	for _, tt := range tests {
+		if got := copyQuery(pgx.Identifier(strings.Split(tt.table, ".")), tt.columns); got != tt.want {
+			t.Errorf("copyQuery(%q, %q) = %s, want %s", tt.table, tt.columns, got, tt.want)
+		}
+	}
+}
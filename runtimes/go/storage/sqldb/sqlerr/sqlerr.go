@@ -41,11 +41,19 @@ const (
 	// due to some previous command failure.
 	TransactionFailed Code = "transaction_failed"
 
+	// SerializationFailure is reported when a transaction can't be serialized
+	// with concurrent transactions, and must be retried.
+	SerializationFailure Code = "serialization_failure"
+
 	// DeadlockDetected is reported when a deadlock is detected.
 	// Deadlock detection is done on a best-effort basis and not all deadlocks
 	// can be detected.
 	DeadlockDetected Code = "deadlock_detected"
 
+	// QueryCanceled is reported when a query is canceled, for example because
+	// it ran for longer than its statement timeout.
+	QueryCanceled Code = "query_canceled"
+
 	// TooManyConnections is reported when the database rejects a connection request
 	// due to reaching the maximum number of connections.
 	// This is different from blocking waiting on a connection pool.
@@ -69,8 +77,12 @@ func MapCode(code string) Code {
 		return ExcludeViolation
 	case "25P02":
 		return TransactionFailed
+	case "40001":
+		return SerializationFailure
 	case "40P01":
 		return DeadlockDetected
+	case "57014":
+		return QueryCanceled
 	case "53300":
 		return TooManyConnections
 	default:
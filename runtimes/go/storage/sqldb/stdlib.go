@@ -76,13 +76,18 @@ func (r *adapterRegistry) Register(db *Database) string {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	name := db.name
+	if db.replica != "" {
+		name += "@" + db.replica
+	}
+
 	// If it's already registered, return the same identifier.
-	if id, ok := r.nameToID[db.name]; ok {
+	if id, ok := r.nameToID[name]; ok {
 		return id
 	}
 
-	ident := fmt.Sprintf("encore/stdlibdriver/%s", db.name)
-	r.nameToID[db.name] = ident
+	ident := fmt.Sprintf("encore/stdlibdriver/%s", name)
+	r.nameToID[name] = ident
 	r.idToDB[ident] = db
 	return ident
 }
@@ -867,6 +867,7 @@ impl MetaBuilder<'_> {
                 max_backoff: sub.config.max_retry_backoff.as_nanos() as i64,
                 max_retries: sub.config.max_retries as i64,
             }),
+            ack_deduplication: false,
         })
     }
 
@@ -908,6 +909,9 @@ impl MetaBuilder<'_> {
             doc: bkt.doc.clone(),
             versioned: bkt.versioned,
             public: bkt.public,
+            // Lifecycle rules and topic notifications are not yet supported for TypeScript apps.
+            lifecycle_rules: vec![],
+            topic_notifications: vec![],
         }
     }
 
@@ -203,6 +203,10 @@ func (b *builder) Build() *meta.Data {
 
 		topicMap   = make(map[pkginfo.QualifiedName]*meta.PubSubTopic)
 		clusterMap = make(map[pkginfo.QualifiedName]*meta.CacheCluster)
+
+		// bucketNotifications are the topic notifications of each bucket,
+		// resolved once all topics are known.
+		bucketNotifications = make(map[*meta.Bucket][]*objects.NotificationUsage)
 	)
 
 	selectorLookup := computeSelectorLookup(b.app)
@@ -332,6 +336,14 @@ func (b *builder) Build() *meta.Data {
 				Versioned: r.Versioned,
 				Public:    r.Public,
 			}
+			for _, rule := range r.LifecycleRules {
+				bkt.LifecycleRules = append(bkt.LifecycleRules, &meta.Bucket_LifecycleRule{
+					Prefix:              rule.Prefix,
+					ExpireAfterDays:     int32(rule.ExpireAfterDays),
+					TransitionAfterDays: int32(rule.TransitionAfterDays),
+					TransitionTo:        rule.TransitionTo,
+				})
+			}
 			md.Buckets = append(md.Buckets, bkt)
 
 			permsBySvc := make(map[string][]objects.Perm)
@@ -344,12 +356,14 @@ func (b *builder) Build() *meta.Data {
 				switch u := u.(type) {
 				case *objects.MethodUsage:
 					if svc, ok := b.app.ServiceForPath(u.DeclaredIn().FSPath); ok {
-						addPerms(svc.Name, u.Perm)
+						addPerms(svc.Name, u.Perms()...)
 					}
 				case *objects.RefUsage:
 					if svc, ok := b.app.ServiceForPath(u.DeclaredIn().FSPath); ok {
 						addPerms(svc.Name, u.Perms...)
 					}
+				case *objects.NotificationUsage:
+					bucketNotifications[bkt] = append(bucketNotifications[bkt], u)
 				}
 			}
 
@@ -495,6 +509,7 @@ func (b *builder) Build() *meta.Data {
 					MaxBackoff: r.Cfg.MaxRetryBackoff.Nanoseconds(),
 					MaxRetries: int64(r.Cfg.MaxRetries),
 				},
+				AckDeduplication: r.Cfg.AckDeduplication,
 			})
 
 			b.nodes.addSub(r, svc.Name, topic.Name)
@@ -523,6 +538,33 @@ func (b *builder) Build() *meta.Data {
 		}
 	}
 
+	// Resolve the topics the buckets' object events are published to.
+	for bkt, uses := range bucketNotifications {
+		for _, u := range uses {
+			topic, ok := topicMap[u.Topic]
+			if !ok {
+				b.errs.Addf(u.Pos(), "topic %q not found", u.Topic.NaiveDisplayName())
+				continue
+			}
+
+			svc, ok := b.app.ServiceForPath(u.DeclaredIn().FSPath)
+			if !ok {
+				b.errs.Addf(u.Pos(), "bucket topic notification must be defined within a service")
+				continue
+			}
+
+			bkt.TopicNotifications = append(bkt.TopicNotifications, &meta.Bucket_TopicNotification{
+				TopicName:   topic.Name,
+				ServiceName: svc.Name,
+				Events:      u.Events,
+				Prefix:      u.Prefix,
+			})
+		}
+		slices.SortFunc(bkt.TopicNotifications, func(a, b *meta.Bucket_TopicNotification) int {
+			return cmp.Compare(a.TopicName, b.TopicName)
+		})
+	}
+
 	// Add the allocated trace nodes to each package.
 	for pkgPath, pkg := range pkgByPath {
 		pkg.TraceNodes = b.nodes.forPkg(pkgPath)
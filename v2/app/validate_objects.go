@@ -3,6 +3,7 @@ package app
 import (
 	"encr.dev/pkg/errors"
 	"encr.dev/v2/internals/parsectx"
+	"encr.dev/v2/internals/pkginfo"
 	"encr.dev/v2/parser"
 	"encr.dev/v2/parser/infra/objects"
 )
@@ -22,9 +23,26 @@ func (d *Desc) validateObjects(pc *parsectx.Context, result *parser.Result) {
 				buckets[res.Name] = res
 			}
 
-			// Make sure any BucketRef calls are within a service.
+			// Make sure any BucketRef calls are within a service,
+			// and each topic is only notified once.
+			notified := make(map[pkginfo.QualifiedName]*objects.NotificationUsage)
 			for _, use := range d.Parse.Usages(res) {
 				switch use := use.(type) {
+				case *objects.NotificationUsage:
+					if existing, ok := notified[use.Topic]; ok {
+						pc.Errs.Add(objects.ErrDuplicateTopicNotification.
+							AtGoNode(existing, errors.AsHelp("originally declared here")).
+							AtGoNode(use, errors.AsError("duplicated here")),
+						)
+					} else {
+						notified[use.Topic] = use
+					}
+					if _, ok := d.ServiceForPath(use.DeclaredIn().FSPath); !ok && !use.DeclaredIn().TestFile {
+						pc.Errs.Add(objects.ErrTopicNotificationOutsideService.
+							AtGoNode(use, errors.AsError("used here")),
+						)
+					}
+
 				case *objects.RefUsage:
 					if use.HasPerm(objects.GetPublicURL) && !res.Public {
 						pc.Errs.Add(objects.ErrBucketNotPublic.
@@ -271,10 +271,10 @@ func parse(ts *testscript.TestScript, neg bool, args []string) {
 			if !found {
 				ts.Fatalf("could not find service for path %s", res.File.FSPath)
 			}
-			printf("pubsubSubscriber %s %s %s %d %d %d %d %d",
+			printf("pubsubSubscriber %s %s %s %d %d %d %d %d %t",
 				topicsByName[res.Topic].Name, res.Name, svc.Name, res.Cfg.AckDeadline,
 				res.Cfg.MessageRetention, res.Cfg.MaxRetries, res.Cfg.MinRetryBackoff,
-				res.Cfg.MaxRetryBackoff)
+				res.Cfg.MaxRetryBackoff, res.Cfg.AckDeduplication)
 		case *metrics.Metric:
 			printf("metric %s %s %s %s", res.Name, strings.ToUpper(res.ValueType.String()), strings.ToUpper(res.Type.String()), res.Labels)
 		}
@@ -110,9 +110,11 @@ var keyspaceConstructors = []cacheKeyspaceConstructor{
 	{"NewStringKeyspace", implicitValue, schema.BuiltinType{Kind: schema.String}},
 	{"NewIntKeyspace", implicitValue, schema.BuiltinType{Kind: schema.Int64}},
 	{"NewFloatKeyspace", implicitValue, schema.BuiltinType{Kind: schema.Float64}},
+	{"NewLockKeyspace", implicitValue, schema.BuiltinType{Kind: schema.String}},
 	{"NewListKeyspace", basicValue, nil},
 	{"NewSetKeyspace", basicValue, nil},
 	{"NewStructKeyspace", structValue, nil},
+	{"NewQueueKeyspace", structValue, nil},
 }
 
 func parseKeyspace(c cacheKeyspaceConstructor, d parseutil.ReferenceInfo) {
@@ -137,6 +137,52 @@ var x = cache.NewStructKeyspace[string, Foo](cluster, cache.KeyspaceConfig{
 				},
 			},
 		},
+		{
+			Name: "lock",
+			Code: `
+var cluster = cache.NewCluster("cluster", cache.ClusterConfig{})
+
+var x = cache.NewLockKeyspace[int](cluster, cache.KeyspaceConfig{
+	KeyPattern: "locks/:key",
+})
+`,
+			Want: &Keyspace{
+				KeyType:   schematest.Int(),
+				ValueType: schematest.String(),
+				Cluster:   pkginfo.Q("example.com", "cluster"),
+				Path: &resourcepaths.Path{
+					Segments: []resourcepaths.Segment{
+						{Type: resourcepaths.Literal, Value: "locks", ValueType: schema.String},
+						{Type: resourcepaths.Param, Value: "key", ValueType: schema.String},
+					},
+				},
+			},
+		},
+		{
+			Name: "queue",
+			Code: `
+type Job struct {
+	ID int
+}
+
+var cluster = cache.NewCluster("cluster", cache.ClusterConfig{})
+
+var x = cache.NewQueueKeyspace[string, Job](cluster, cache.KeyspaceConfig{
+	KeyPattern: "jobs/:key",
+})
+`,
+			Want: &Keyspace{
+				KeyType:   schematest.String(),
+				ValueType: schematest.Named(schematest.TypeInfo("Job")),
+				Cluster:   pkginfo.Q("example.com", "cluster"),
+				Path: &resourcepaths.Path{
+					Segments: []resourcepaths.Segment{
+						{Type: resourcepaths.Literal, Value: "jobs", ValueType: schema.String},
+						{Type: resourcepaths.Param, Value: "key", ValueType: schema.String},
+					},
+				},
+			},
+		},
 	}
 
 	resourcetest.Run(t, KeyspaceParser, tests)
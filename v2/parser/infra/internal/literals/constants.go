@@ -29,6 +29,13 @@ var constants = map[paths.Pkg]map[string]any{
 		"VolatileRandom": string(cache.VolatileRandom),
 		"NoEviction":     string(cache.NoEviction),
 	},
+	"encore.dev/storage/objects": {
+		"StorageClassInfrequent": "infrequent",
+		"StorageClassCold":       "cold",
+		"StorageClassArchive":    "archive",
+		"ObjectCreated":          "object_created",
+		"ObjectDeleted":          "object_deleted",
+	},
 	"time": {
 		"Nanosecond":  int64(time.Nanosecond),
 		"Microsecond": int64(time.Microsecond),
@@ -153,6 +153,21 @@ func decodeField(errs *perr.List, literal *Struct, fieldType reflect.StructField
 		}
 		return fieldPaths
 
+	case reflect.Slice:
+		elems, ok := literal.ChildSlice(fieldPath)
+		if !ok || fieldType.Type.Elem().Kind() != reflect.Struct {
+			errs.Add(errWrongDynamicType(fieldPath, "slice of struct").AtGoNode(literal.Expr(fieldPath)))
+			return
+		}
+		field.Set(reflect.MakeSlice(fieldType.Type, len(elems), len(elems)))
+		for i, elem := range elems {
+			childPaths := decodeStruct(errs, elem, field.Index(i), reflect.Value{})
+			for _, p := range childPaths {
+				fieldPaths = append(fieldPaths, fieldPath+"."+p)
+			}
+		}
+		return fieldPaths
+
 	default:
 		errs.Assert(errUnsupportedType(fieldType.Type.Kind()).AtGoNode(literal.Expr(fieldPath)))
 	}
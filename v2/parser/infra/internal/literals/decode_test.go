@@ -70,3 +70,50 @@ var x = pubsub.SubscriptionConfig{
 	})
 
 }
+
+func TestDecode_Slice(t *testing.T) {
+	c := qt.New(t)
+	tc := testutil.NewContext(c, false, testutil.ParseTxtar(`
+-- go.mod --
+module example.com
+require encore.dev v1.52.0
+-- foo.go --
+package foo
+
+import "encore.dev/storage/objects"
+
+var x = objects.BucketConfig{
+	LifecycleRules: []objects.LifecycleRule{
+		{Prefix: "tmp/", ExpireAfterDays: 7},
+		{TransitionAfterDays: 2 * 30},
+	},
+}
+`))
+	tc.FailTestOnErrors()
+	tc.GoModTidy()
+
+	loader := pkginfo.New(tc.Context)
+	pkg := loader.MustLoadPkg(0, "example.com")
+
+	cfgLit, ok := ParseStruct(tc.Errs, pkg.Files[0], "objects.BucketConfig",
+		pkg.Names().PkgDecls["x"].Spec.(*ast.ValueSpec).Values[0])
+	c.Assert(ok, qt.IsTrue)
+
+	type rule struct {
+		Prefix              string `literal:",optional"`
+		ExpireAfterDays     int    `literal:",optional"`
+		TransitionAfterDays int    `literal:",optional"`
+	}
+	type decodedConfig struct {
+		LifecycleRules []rule `literal:",optional"`
+	}
+
+	cfg := Decode[decodedConfig](tc.Errs, cfgLit, nil)
+
+	c.Assert(cfg, qt.DeepEquals, decodedConfig{
+		LifecycleRules: []rule{
+			{Prefix: "tmp/", ExpireAfterDays: 7},
+			{TransitionAfterDays: 60},
+		},
+	})
+}
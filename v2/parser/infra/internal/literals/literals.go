@@ -46,6 +46,7 @@ func ParseStruct(errs *perr.List, file *pkginfo.File, expectedType string, node
 		constantFields: make(map[string]constant.Value),
 		allFields:      make(map[string]ast.Expr),
 		childStructs:   make(map[string]*Struct),
+		childSlices:    make(map[string]*sliceLit),
 	}
 	ok = true
 
@@ -64,20 +65,22 @@ elemLoop:
 			}
 
 			// Parse any sub data structures
-			var subStruct *ast.CompositeLit
-			switch value := elem.Value.(type) {
-			case *ast.UnaryExpr:
-				if value.Op == token.AND {
-					if compositeLiteral, ok := value.X.(*ast.CompositeLit); ok {
-						subStruct = compositeLiteral
+			subStruct := compositeLit(elem.Value)
+			if subStruct != nil && isSliceLit(subStruct) {
+				slice := &sliceLit{ast: subStruct}
+				for _, elt := range subStruct.Elts {
+					eltStruct := compositeLit(elt)
+					if eltStruct == nil {
+						errs.Add(errNotLiteral("struct", PrettyPrint(elt)).AtGoNode(elt))
+						ok = false
+						continue
 					}
+					eltLit, eltOk := ParseStruct(errs, file, "struct", eltStruct)
+					ok = ok && eltOk
+					slice.elems = append(slice.elems, eltLit)
 				}
-
-			case *ast.CompositeLit:
-				subStruct = value
-			}
-
-			if subStruct != nil {
+				lit.childSlices[ident.Name] = slice
+			} else if subStruct != nil {
 				subLit, subOk := ParseStruct(errs, file, "struct", subStruct)
 				ok = ok && subOk
 				lit.childStructs[ident.Name] = subLit
@@ -99,6 +102,28 @@ elemLoop:
 	return
 }
 
+// compositeLit returns the composite literal node is, or takes the address of.
+// If it's neither it returns nil.
+func compositeLit(node ast.Expr) *ast.CompositeLit {
+	switch node := node.(type) {
+	case *ast.UnaryExpr:
+		if node.Op == token.AND {
+			if cl, ok := node.X.(*ast.CompositeLit); ok {
+				return cl
+			}
+		}
+	case *ast.CompositeLit:
+		return node
+	}
+	return nil
+}
+
+// isSliceLit reports whether cl is a slice or array literal.
+func isSliceLit(cl *ast.CompositeLit) bool {
+	_, ok := cl.Type.(*ast.ArrayType)
+	return ok
+}
+
 func ParseConstant(errs *perr.List, file *pkginfo.File, value ast.Expr) (rtn constant.Value) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -234,6 +259,13 @@ type Struct struct {
 	constantFields map[string]constant.Value // All found constant expressions
 	allFields      map[string]ast.Expr       // All field expressions (constant or otherwise)
 	childStructs   map[string]*Struct        // Any child struct literals
+	childSlices    map[string]*sliceLit      // Any child slices of struct literals
+}
+
+// sliceLit represents a slice literal of struct literals
+type sliceLit struct {
+	ast   *ast.CompositeLit
+	elems []*Struct
 }
 
 func (l *Struct) Lit() *ast.CompositeLit {
@@ -248,6 +280,13 @@ func (l *Struct) FullyConstant() bool {
 			return false
 		}
 	}
+	for _, slice := range l.childSlices {
+		for _, elem := range slice.elems {
+			if !elem.FullyConstant() {
+				return false
+			}
+		}
+	}
 	return len(l.constantFields) == len(l.allFields)
 }
 
@@ -268,6 +307,13 @@ func (l *Struct) DynamicFields() map[string]ast.Expr {
 			fields[name+"."+k] = v
 		}
 	}
+	for name, slice := range l.childSlices {
+		for _, elem := range slice.elems {
+			for k, v := range elem.DynamicFields() {
+				fields[name+"."+k] = v
+			}
+		}
+	}
 
 	return fields
 }
@@ -286,6 +332,8 @@ func (l *Struct) IsSet(fieldName string) bool {
 		}
 	} else if _, found := l.childStructs[fieldName]; found {
 		return true
+	} else if _, found := l.childSlices[fieldName]; found {
+		return true
 	}
 
 	_, found = l.allFields[fieldName]
@@ -303,6 +351,13 @@ func (l *Struct) IsConstant(fieldName string) bool {
 		}
 	} else if child, found := l.childStructs[fieldName]; found {
 		return child.FullyConstant()
+	} else if slice, found := l.childSlices[fieldName]; found {
+		for _, elem := range slice.elems {
+			if !elem.FullyConstant() {
+				return false
+			}
+		}
+		return true
 	}
 
 	_, found = l.constantFields[fieldName]
@@ -314,6 +369,15 @@ func (l *Struct) ChildStruct(fieldName string) (st *Struct, ok bool) {
 	return
 }
 
+// ChildSlice returns the struct literals of the slice literal in the given field.
+func (l *Struct) ChildSlice(fieldName string) (elems []*Struct, ok bool) {
+	slice, ok := l.childSlices[fieldName]
+	if !ok {
+		return nil, false
+	}
+	return slice.elems, true
+}
+
 // Pos returns the position of the field in the source code
 //
 // If the field is not found, the closest position to where
@@ -330,6 +394,9 @@ func (l *Struct) Pos(fieldName string) token.Pos {
 		}
 	}
 
+	if slice, found := l.childSlices[fieldName]; found {
+		return slice.ast.Pos()
+	}
 	value, found := l.allFields[fieldName]
 	if found {
 		return value.Pos()
@@ -350,6 +417,13 @@ func (l *Struct) FieldPaths() []string {
 			res = append(res, name+"."+path)
 		}
 	}
+	for name, slice := range l.childSlices {
+		for _, elem := range slice.elems {
+			for _, path := range elem.FieldPaths() {
+				res = append(res, name+"."+path)
+			}
+		}
+	}
 	return res
 }
 
@@ -370,6 +444,9 @@ func (l *Struct) Expr(fieldName string) ast.Expr {
 		}
 	}
 
+	if slice, found := l.childSlices[fieldName]; found {
+		return slice.ast
+	}
 	value, found := l.allFields[fieldName]
 	if found {
 		return value
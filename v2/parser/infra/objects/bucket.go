@@ -1,8 +1,10 @@
 package objects
 
 import (
+	"fmt"
 	"go/ast"
 	"go/token"
+	"slices"
 
 	"encr.dev/pkg/paths"
 	"encr.dev/v2/internals/pkginfo"
@@ -19,8 +21,21 @@ type Bucket struct {
 	Doc       string // The documentation on the bucket
 	Versioned bool
 	Public    bool
+
+	LifecycleRules []LifecycleRule
+}
+
+// LifecycleRule is a rule for managing the objects in a bucket as they age.
+type LifecycleRule struct {
+	Prefix              string // The object name prefix the rule applies to
+	ExpireAfterDays     int    // Days after creation to delete objects, or 0
+	TransitionAfterDays int    // Days after creation to transition objects, or 0
+	TransitionTo        string // The storage class to transition objects to
 }
 
+// storageClasses are the valid values for LifecycleRule.TransitionTo.
+var storageClasses = []string{"infrequent", "cold", "archive"}
+
 func (t *Bucket) Kind() resource.Kind       { return resource.Bucket }
 func (t *Bucket) Package() *pkginfo.Package { return t.File.Pkg }
 func (t *Bucket) ASTExpr() ast.Expr         { return t.AST }
@@ -73,20 +88,58 @@ func parseBucket(d parseutil.ReferenceInfo) {
 	}
 
 	// Decode the config
+	type decodedLifecycleRule struct {
+		Prefix              string `literal:",optional"`
+		ExpireAfterDays     int    `literal:",optional"`
+		TransitionAfterDays int    `literal:",optional"`
+		TransitionTo        string `literal:",optional"`
+	}
 	type decodedConfig struct {
-		Versioned bool `literal:",optional"`
-		Public    bool `literal:",optional"`
+		Versioned      bool                   `literal:",optional"`
+		Public         bool                   `literal:",optional"`
+		LifecycleRules []decodedLifecycleRule `literal:",optional"`
 	}
 	config := literals.Decode[decodedConfig](d.Pass.Errs, cfgLit, nil)
 
+	var rules []LifecycleRule
+	if elems, ok := cfgLit.ChildSlice("LifecycleRules"); ok {
+		for i, r := range config.LifecycleRules {
+			rule := LifecycleRule(r)
+			if rule.TransitionTo == "" {
+				rule.TransitionTo = "cold"
+			}
+			if msg := validateLifecycleRule(rule); msg != "" {
+				errs.Add(errInvalidLifecycleRule(msg).AtGoNode(elems[i].Lit()))
+				continue
+			}
+			rules = append(rules, rule)
+		}
+	}
+
 	bkt := &Bucket{
-		AST:       d.Call,
-		File:      d.File,
-		Name:      bucketName,
-		Doc:       d.Doc,
-		Versioned: config.Versioned,
-		Public:    config.Public,
+		AST:            d.Call,
+		File:           d.File,
+		Name:           bucketName,
+		Doc:            d.Doc,
+		Versioned:      config.Versioned,
+		Public:         config.Public,
+		LifecycleRules: rules,
 	}
 	d.Pass.RegisterResource(bkt)
 	d.Pass.AddBind(d.File, d.Ident, bkt)
 }
+
+// validateLifecycleRule reports what is wrong with the rule, or "" if it's valid.
+func validateLifecycleRule(r LifecycleRule) string {
+	switch {
+	case r.ExpireAfterDays < 0 || r.TransitionAfterDays < 0:
+		return "The number of days in a lifecycle rule must not be negative."
+	case r.ExpireAfterDays == 0 && r.TransitionAfterDays == 0:
+		return "A lifecycle rule must set ExpireAfterDays, TransitionAfterDays, or both."
+	case r.ExpireAfterDays > 0 && r.TransitionAfterDays >= r.ExpireAfterDays:
+		return "A lifecycle rule must transition objects before they expire."
+	case !slices.Contains(storageClasses, r.TransitionTo):
+		return fmt.Sprintf("Unknown storage class %q; the supported storage classes are objects.{StorageClassInfrequent,StorageClassCold,StorageClassArchive}.", r.TransitionTo)
+	}
+	return ""
+}
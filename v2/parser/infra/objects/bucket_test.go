@@ -0,0 +1,73 @@
+package objects
+
+import (
+	"testing"
+
+	"encr.dev/v2/parser/resource/resourcetest"
+)
+
+func TestParseBucket(t *testing.T) {
+	tests := []resourcetest.Case[*Bucket]{
+		{
+			Name: "basic",
+			Code: `
+// Bucket docs
+var x = objects.NewBucket("name", objects.BucketConfig{Versioned: true})
+`,
+			Want: &Bucket{
+				Name:      "name",
+				Doc:       "Bucket docs\n",
+				Versioned: true,
+			},
+		},
+		{
+			Name: "with_lifecycle_rules",
+			Code: `
+var x = objects.NewBucket("name", objects.BucketConfig{
+	LifecycleRules: []objects.LifecycleRule{
+		{Prefix: "tmp/", ExpireAfterDays: 7},
+		{TransitionAfterDays: 30, TransitionTo: objects.StorageClassArchive, ExpireAfterDays: 365},
+		{TransitionAfterDays: 90},
+	},
+})
+`,
+			Want: &Bucket{
+				Name: "name",
+				LifecycleRules: []LifecycleRule{
+					{Prefix: "tmp/", ExpireAfterDays: 7, TransitionTo: "cold"},
+					{TransitionAfterDays: 30, TransitionTo: "archive", ExpireAfterDays: 365},
+					{TransitionAfterDays: 90, TransitionTo: "cold"},
+				},
+			},
+		},
+		{
+			Name: "with_empty_lifecycle_rule",
+			Code: `
+var x = objects.NewBucket("name", objects.BucketConfig{
+	LifecycleRules: []objects.LifecycleRule{{Prefix: "tmp/"}},
+})
+`,
+			WantErrs: []string{`.*must set ExpireAfterDays, TransitionAfterDays, or both.*`},
+		},
+		{
+			Name: "with_transition_after_expiry",
+			Code: `
+var x = objects.NewBucket("name", objects.BucketConfig{
+	LifecycleRules: []objects.LifecycleRule{{TransitionAfterDays: 30, ExpireAfterDays: 7}},
+})
+`,
+			WantErrs: []string{`.*must transition objects before they expire.*`},
+		},
+		{
+			Name: "with_unknown_storage_class",
+			Code: `
+var x = objects.NewBucket("name", objects.BucketConfig{
+	LifecycleRules: []objects.LifecycleRule{{TransitionAfterDays: 30, TransitionTo: "frozen"}},
+})
+`,
+			WantErrs: []string{`.*Unknown storage class "frozen".*`},
+		},
+	}
+
+	resourcetest.Run(t, BucketParser, tests)
+}
@@ -7,6 +7,10 @@ import (
 const (
 	objectsNewBucketHelp = "For example `objects.NewBucket(\"my-bucket\", objects.BucketConfig{ Versioned: false })`"
 
+	lifecycleRuleHelp = "For example `objects.LifecycleRule{ Prefix: \"tmp/\", ExpireAfterDays: 7 }`"
+
+	topicNotificationHelp = "For example `objects.NewTopicNotification(bucket, topic, objects.TopicNotificationConfig{ Events: []objects.EventType{objects.ObjectCreated} })`"
+
 	objectsBucketUsageHelp = "The bucket can only be referenced by calling methods on it, or by using objects.BucketRef."
 )
 
@@ -59,4 +63,38 @@ var (
 		"Call to PublicURL for non-public objects.Bucket",
 		"The PublicURL method can only be called on a public bucket.",
 	)
+
+	errInvalidLifecycleRule = errRange.Newf(
+		"Invalid lifecycle rule",
+		"%s",
+		errors.PrependDetails(lifecycleRuleHelp),
+	)
+
+	errNewTopicNotificationArgCount = errRange.Newf(
+		"Invalid objects.NewTopicNotification call",
+		"A call to objects.NewTopicNotification requires 3 arguments; the bucket, the topic and the config object, got %d arguments.",
+		errors.PrependDetails(topicNotificationHelp),
+	)
+
+	errNotificationTopicNotResource = errRange.New(
+		"Invalid objects.NewTopicNotification call",
+		"The topic argument must refer to a package level pubsub.Topic.",
+		errors.PrependDetails(topicNotificationHelp),
+	)
+
+	errInvalidTopicNotificationConfig = errRange.Newf(
+		"Invalid objects.TopicNotificationConfig",
+		"%s",
+		errors.PrependDetails(topicNotificationHelp),
+	)
+
+	ErrTopicNotificationOutsideService = errRange.New(
+		"Call to objects.NewTopicNotification outside service",
+		"objects.NewTopicNotification can only be called from within a service.",
+	)
+
+	ErrDuplicateTopicNotification = errRange.New(
+		"Duplicate topic notification",
+		"A topic can only be notified of a bucket's events once.",
+	)
 )
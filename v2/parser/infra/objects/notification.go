@@ -0,0 +1,99 @@
+package objects
+
+import (
+	"go/ast"
+	"go/constant"
+	"slices"
+
+	"encr.dev/v2/internals/perr"
+	"encr.dev/v2/internals/pkginfo"
+	"encr.dev/v2/parser/infra/internal/literals"
+	"encr.dev/v2/parser/resource/usage"
+)
+
+// NotificationUsage is a bucket passed to objects.NewTopicNotification,
+// which publishes the bucket's object events to a Pub/Sub topic.
+type NotificationUsage struct {
+	usage.Base
+
+	// Topic is the topic the events are published to.
+	Topic pkginfo.QualifiedName
+
+	// Events are the kinds of events to publish, like "object_created".
+	// If empty, all events are published.
+	Events []string
+
+	// Prefix, if set, limits the events to objects whose name begins with it.
+	Prefix string
+}
+
+// eventTypes are the values of the objects.EventType constants.
+var eventTypes = []string{"object_created", "object_deleted"}
+
+// parseTopicNotification parses a call to objects.NewTopicNotification(bkt, topic, cfg).
+func parseTopicNotification(errs *perr.List, expr *usage.FuncArg) usage.Usage {
+	call := expr.Call
+	if len(call.Args) != 3 {
+		errs.Add(errNewTopicNotificationArgCount(len(call.Args)).AtGoNode(call))
+		return nil
+	}
+
+	topic, ok := expr.File.Names().ResolvePkgLevelRef(call.Args[1])
+	if !ok {
+		errs.Add(errNotificationTopicNotResource.AtGoNode(call.Args[1]))
+		return nil
+	}
+
+	u := &NotificationUsage{
+		Base: usage.Base{
+			File: expr.File,
+			Bind: expr.Bind,
+			Expr: expr,
+		},
+		Topic: topic,
+	}
+
+	// The config can't be decoded with the literals package
+	// as it doesn't support slices of constants.
+	cfg, ok := call.Args[2].(*ast.CompositeLit)
+	if !ok {
+		errs.Add(errInvalidTopicNotificationConfig("the config must be a struct literal").AtGoNode(call.Args[2]))
+		return nil
+	}
+	for _, elt := range cfg.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			errs.Add(errInvalidTopicNotificationConfig("the config fields must be named").AtGoNode(elt))
+			return nil
+		}
+		key, _ := kv.Key.(*ast.Ident)
+		switch {
+		case key != nil && key.Name == "Events":
+			events, ok := kv.Value.(*ast.CompositeLit)
+			if !ok {
+				errs.Add(errInvalidTopicNotificationConfig("Events must be a slice literal").AtGoNode(kv.Value))
+				return nil
+			}
+			for _, ev := range events.Elts {
+				val := literals.ParseConstant(errs, expr.File, ev)
+				if val.Kind() != constant.String || !slices.Contains(eventTypes, constant.StringVal(val)) {
+					errs.Add(errInvalidTopicNotificationConfig("Events must be objects.ObjectCreated or objects.ObjectDeleted").AtGoNode(ev))
+					return nil
+				}
+				u.Events = append(u.Events, constant.StringVal(val))
+			}
+			slices.Sort(u.Events)
+			u.Events = slices.Compact(u.Events)
+
+		case key != nil && key.Name == "Prefix":
+			val := literals.ParseConstant(errs, expr.File, kv.Value)
+			if val.Kind() != constant.String {
+				errs.Add(errInvalidTopicNotificationConfig("Prefix must be a constant string").AtGoNode(kv.Value))
+				return nil
+			}
+			u.Prefix = constant.StringVal(val)
+		}
+	}
+
+	return u
+}
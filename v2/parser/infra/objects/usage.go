@@ -1,6 +1,7 @@
 package objects
 
 import (
+	"go/ast"
 	"slices"
 
 	"encr.dev/pkg/option"
@@ -17,6 +18,49 @@ type MethodUsage struct {
 	usage.Base
 	Method string
 	Perm   Perm
+
+	// Idempotent is true if the call is an Upload with an idempotency key,
+	// which requires looking up the existing object's metadata.
+	Idempotent bool
+
+	// CrossBucket is true if the call is a Copy from another bucket,
+	// in which case the object's contents are read from that bucket.
+	CrossBucket bool
+}
+
+// Perms returns all the permissions required by the method call,
+// including any beyond Perm for methods that perform multiple operations.
+func (u *MethodUsage) Perms() []Perm {
+	switch u.Method {
+	case "UploadDeduplicated":
+		return []Perm{u.Perm, GetObjectMetadata}
+	case "Verify", "RemoveRange", "Truncate":
+		return []Perm{u.Perm, GetObjectMetadata, ReadObjectContents}
+	case "Remove":
+		// With soft delete enabled, which is configured at runtime, removing
+		// an object first copies it to the trash.
+		return []Perm{u.Perm, GetObjectMetadata, ReadObjectContents, WriteObject}
+	case "Restore":
+		return []Perm{u.Perm, ListObjects, GetObjectMetadata, ReadObjectContents, DeleteObject}
+	case "PurgeTrash":
+		return []Perm{u.Perm, ListObjects}
+	case "GenerateIndex":
+		return []Perm{u.Perm, WriteObject, GetObjectMetadata}
+	case "Copy":
+		if u.CrossBucket {
+			return []Perm{u.Perm, GetObjectMetadata}
+		}
+		return []Perm{u.Perm, GetObjectMetadata, ReadObjectContents}
+	case "Move":
+		return []Perm{u.Perm, GetObjectMetadata, ReadObjectContents, DeleteObject}
+	case "Upload":
+		if u.Idempotent {
+			return []Perm{u.Perm, GetObjectMetadata}
+		}
+		return []Perm{u.Perm}
+	default:
+		return []Perm{u.Perm}
+	}
 }
 
 type RefUsage struct {
@@ -77,13 +121,15 @@ func ResolveBucketUsage(data usage.ResolveData, bkt *Bucket) usage.Usage {
 	case *usage.MethodCall:
 		var perm Perm
 		switch expr.Method {
-		case "Upload":
+		case "Upload", "UploadDeduplicated", "RemoveRange", "Truncate", "Restore",
+			"BeginMultipartUpload", "UploadPart", "UploadedParts", "CompleteMultipartUpload", "AbortMultipartUpload",
+			"Copy", "Move":
 			perm = WriteObject
 		case "Download":
 			perm = ReadObjectContents
-		case "List":
+		case "List", "ListChan", "ListPage", "ForEach", "Verify", "DiffDir", "Watch", "GenerateIndex":
 			perm = ListObjects
-		case "Remove":
+		case "Remove", "PurgeTrash":
 			perm = DeleteObject
 		case "PublicURL":
 			perm = GetPublicURL
@@ -91,7 +137,7 @@ func ResolveBucketUsage(data usage.ResolveData, bkt *Bucket) usage.Usage {
 			perm = SignedUploadURL
 		case "SignedDownloadURL":
 			perm = SignedDownloadURL
-		case "Attrs", "Exists":
+		case "Attrs", "Exists", "WaitUntilExists":
 			perm = GetObjectMetadata
 		default:
 			return nil
@@ -103,14 +149,32 @@ func ResolveBucketUsage(data usage.ResolveData, bkt *Bucket) usage.Usage {
 				Bind: expr.Bind,
 				Expr: expr,
 			},
-			Method: expr.Method,
-			Perm:   perm,
+			Method:      expr.Method,
+			Perm:        perm,
+			Idempotent:  expr.Method == "Upload" && hasOptionCall(expr.Args, "WithIdempotencyKey"),
+			CrossBucket: expr.Method == "Copy" && hasOptionCall(expr.Args, "FromBucket"),
 		}
 
 	case *usage.FuncArg:
 		switch {
 		case option.Contains(expr.PkgFunc, pkginfo.Q("encore.dev/storage/objects", "BucketRef")):
 			return parseBucketRef(data.Errs, expr)
+		case option.Contains(expr.PkgFunc, pkginfo.Q("encore.dev/storage/objects", "Migrate")):
+			if u := parseMigrate(expr); u != nil {
+				return u
+			}
+		case option.Contains(expr.PkgFunc, pkginfo.Q("encore.dev/storage/objects", "FromBucket")):
+			// The bucket is the source of a Copy from another bucket.
+			return &RefUsage{
+				Base: usage.Base{
+					File: expr.File,
+					Bind: expr.Bind,
+					Expr: expr,
+				},
+				Perms: []Perm{GetObjectMetadata, ReadObjectContents},
+			}
+		case option.Contains(expr.PkgFunc, pkginfo.Q("encore.dev/storage/objects", "NewTopicNotification")) && expr.ArgIdx == 0:
+			return parseTopicNotification(data.Errs, expr)
 		}
 	}
 
@@ -118,6 +182,41 @@ func ResolveBucketUsage(data usage.ResolveData, bkt *Bucket) usage.Usage {
 	return nil
 }
 
+// hasOptionCall reports whether any of the args is a direct call
+// to the objects package function with the given name, like objects.WithIdempotencyKey(...).
+func hasOptionCall(args []ast.Expr, name string) bool {
+	for _, arg := range args {
+		if call, ok := arg.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseMigrate returns the usage of a bucket passed to objects.Migrate,
+// which reads from the source bucket and writes to the destination bucket.
+func parseMigrate(expr *usage.FuncArg) usage.Usage {
+	var perms []Perm
+	switch expr.ArgIdx {
+	case 1:
+		perms = []Perm{GetObjectMetadata, ListObjects, ReadObjectContents}
+	case 2:
+		perms = []Perm{GetObjectMetadata, WriteObject}
+	default:
+		return nil
+	}
+	return &RefUsage{
+		Base: usage.Base{
+			File: expr.File,
+			Bind: expr.Bind,
+			Expr: expr,
+		},
+		Perms: perms,
+	}
+}
+
 func parseBucketRef(errs *perr.List, expr *usage.FuncArg) usage.Usage {
 	if len(expr.TypeArgs) < 1 {
 		errs.Add(errBucketRefNoTypeArgs.AtGoNode(expr.Call))
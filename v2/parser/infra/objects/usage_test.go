@@ -1,9 +1,12 @@
 package objects_test
 
 import (
+	"slices"
 	"testing"
 
+	"encr.dev/v2/internals/pkginfo"
 	"encr.dev/v2/parser/infra/objects"
+	"encr.dev/v2/parser/infra/pubsub"
 	"encr.dev/v2/parser/resource/usage"
 	"encr.dev/v2/parser/resource/usage/usagetest"
 )
@@ -66,6 +69,173 @@ func Foo() { bkt.Exists(context.Background(), "key") }
 `,
 			Want: []usage.Usage{&objects.MethodUsage{Method: "Exists", Perm: objects.GetObjectMetadata}},
 		},
+		{
+			Name: "wait_until_exists",
+			Code: `
+var bkt = objects.NewBucket("bucket", objects.BucketConfig{})
+
+func Foo() { bkt.WaitUntilExists(context.Background(), "key") }
+`,
+			Want: []usage.Usage{&objects.MethodUsage{Method: "WaitUntilExists", Perm: objects.GetObjectMetadata}},
+		},
+		{
+			Name: "verify",
+			Code: `
+var bkt = objects.NewBucket("bucket", objects.BucketConfig{})
+
+func Foo() { bkt.Verify(context.Background(), &objects.Query{}, 4) }
+`,
+			Want: []usage.Usage{&objects.MethodUsage{Method: "Verify", Perm: objects.ListObjects}},
+		},
+		{
+			Name: "diff_dir",
+			Code: `
+var bkt = objects.NewBucket("bucket", objects.BucketConfig{})
+
+func Foo() { bkt.DiffDir(context.Background(), "./public", "site/") }
+`,
+			Want: []usage.Usage{&objects.MethodUsage{Method: "DiffDir", Perm: objects.ListObjects}},
+		},
+		{
+			Name: "watch",
+			Code: `
+var bkt = objects.NewBucket("bucket", objects.BucketConfig{})
+
+func Foo() { bkt.Watch(context.Background(), &objects.Query{Prefix: "inbox/"}, 0) }
+`,
+			Want: []usage.Usage{&objects.MethodUsage{Method: "Watch", Perm: objects.ListObjects}},
+		},
+		{
+			Name: "list_chan",
+			Code: `
+var bkt = objects.NewBucket("bucket", objects.BucketConfig{})
+
+func Foo() { bkt.ListChan(context.Background(), &objects.Query{}) }
+`,
+			Want: []usage.Usage{&objects.MethodUsage{Method: "ListChan", Perm: objects.ListObjects}},
+		},
+		{
+			Name: "list_page",
+			Code: `
+var bkt = objects.NewBucket("bucket", objects.BucketConfig{})
+
+func Foo() { bkt.ListPage(context.Background(), &objects.Query{Delimiter: "/"}, "") }
+`,
+			Want: []usage.Usage{&objects.MethodUsage{Method: "ListPage", Perm: objects.ListObjects}},
+		},
+		{
+			Name: "remove",
+			Code: `
+var bkt = objects.NewBucket("bucket", objects.BucketConfig{})
+
+func Foo() { bkt.Remove(context.Background(), "key") }
+`,
+			Want: []usage.Usage{&objects.MethodUsage{Method: "Remove", Perm: objects.DeleteObject}},
+		},
+		{
+			Name: "restore",
+			Code: `
+var bkt = objects.NewBucket("bucket", objects.BucketConfig{})
+
+func Foo() { bkt.Restore(context.Background(), "key") }
+`,
+			Want: []usage.Usage{&objects.MethodUsage{Method: "Restore", Perm: objects.WriteObject}},
+		},
+		{
+			Name: "purge_trash",
+			Code: `
+var bkt = objects.NewBucket("bucket", objects.BucketConfig{})
+
+func Foo() { bkt.PurgeTrash(context.Background(), 0) }
+`,
+			Want: []usage.Usage{&objects.MethodUsage{Method: "PurgeTrash", Perm: objects.DeleteObject}},
+		},
+		{
+			Name: "upload_deduplicated",
+			Code: `
+var bkt = objects.NewBucket("bucket", objects.BucketConfig{})
+
+func Foo() { bkt.UploadDeduplicated(context.Background(), "blobs/", nil) }
+`,
+			Want: []usage.Usage{&objects.MethodUsage{Method: "UploadDeduplicated", Perm: objects.WriteObject}},
+		},
+		{
+			Name: "upload_idempotent",
+			Code: `
+var bkt = objects.NewBucket("bucket", objects.BucketConfig{})
+
+func Foo() { bkt.Upload(context.Background(), "key", objects.WithIdempotencyKey("req-1")) }
+`,
+			Want: []usage.Usage{&objects.MethodUsage{Method: "Upload", Perm: objects.WriteObject, Idempotent: true}},
+		},
+		{
+			Name: "multipart_upload",
+			Code: `
+var bkt = objects.NewBucket("bucket", objects.BucketConfig{})
+
+func Foo() { bkt.BeginMultipartUpload(context.Background(), "video.mp4") }
+`,
+			Want: []usage.Usage{&objects.MethodUsage{Method: "BeginMultipartUpload", Perm: objects.WriteObject}},
+		},
+		{
+			Name: "truncate",
+			Code: `
+var bkt = objects.NewBucket("bucket", objects.BucketConfig{})
+
+func Foo() { bkt.Truncate(context.Background(), "log", 10) }
+`,
+			Want: []usage.Usage{&objects.MethodUsage{Method: "Truncate", Perm: objects.WriteObject}},
+		},
+		{
+			Name: "for_each",
+			Code: `
+var bkt = objects.NewBucket("bucket", objects.BucketConfig{})
+
+func Foo() { bkt.ForEach(context.Background(), &objects.Query{}, 4, nil) }
+`,
+			Want: []usage.Usage{&objects.MethodUsage{Method: "ForEach", Perm: objects.ListObjects}},
+		},
+		{
+			Name: "generate_index",
+			Code: `
+var bkt = objects.NewBucket("bucket", objects.BucketConfig{})
+
+func Foo() { bkt.GenerateIndex(context.Background(), "site/") }
+`,
+			Want: []usage.Usage{&objects.MethodUsage{Method: "GenerateIndex", Perm: objects.ListObjects}},
+		},
+		{
+			Name: "copy",
+			Code: `
+var bkt = objects.NewBucket("bucket", objects.BucketConfig{})
+
+func Foo() { bkt.Copy(context.Background(), "a", "b") }
+`,
+			Want: []usage.Usage{&objects.MethodUsage{Method: "Copy", Perm: objects.WriteObject}},
+		},
+		{
+			Name: "copy_from_bucket",
+			Code: `
+var src = objects.NewBucket("src", objects.BucketConfig{})
+
+var dst = objects.NewBucket("dst", objects.BucketConfig{})
+
+func Foo() { dst.Copy(context.Background(), "a", "b", objects.FromBucket(src)) }
+`,
+			Want: []usage.Usage{
+				&objects.RefUsage{Perms: []objects.Perm{objects.GetObjectMetadata, objects.ReadObjectContents}},
+				&objects.MethodUsage{Method: "Copy", Perm: objects.WriteObject, CrossBucket: true},
+			},
+		},
+		{
+			Name: "move",
+			Code: `
+var bkt = objects.NewBucket("bucket", objects.BucketConfig{})
+
+func Foo() { bkt.Move(context.Background(), "a", "b") }
+`,
+			Want: []usage.Usage{&objects.MethodUsage{Method: "Move", Perm: objects.WriteObject}},
+		},
 		{
 			Name: "ref",
 			Code: `
@@ -139,6 +309,67 @@ var ref = objects.BucketRef[MyRef](bkt)
 					objects.WriteObject},
 			}},
 		},
+		{
+			Name: "migrate",
+			Code: `
+var src = objects.NewBucket("src", objects.BucketConfig{})
+
+func Foo() { objects.Migrate(context.Background(), src, nil, &objects.Query{}) }
+`,
+			Want: []usage.Usage{&objects.RefUsage{
+				Perms: []objects.Perm{
+					objects.GetObjectMetadata,
+					objects.ListObjects,
+					objects.ReadObjectContents},
+			}},
+		},
+		{
+			Name: "migrate_dst",
+			Code: `
+var dst = objects.NewBucket("dst", objects.BucketConfig{})
+
+func Foo() { objects.Migrate(context.Background(), nil, dst, &objects.Query{}) }
+`,
+			Want: []usage.Usage{&objects.RefUsage{
+				Perms: []objects.Perm{objects.GetObjectMetadata, objects.WriteObject},
+			}},
+		},
+		{
+			Name: "topic_notification",
+			Code: `
+var bkt = objects.NewBucket("bucket", objects.BucketConfig{})
+
+var topic = pubsub.NewTopic[*objects.ObjectEvent]("topic", pubsub.TopicConfig{DeliveryGuarantee: pubsub.AtLeastOnce})
+
+var _ = objects.NewTopicNotification(bkt, topic, objects.TopicNotificationConfig{
+	Events: []objects.EventType{objects.ObjectCreated},
+	Prefix: "uploads/",
+})
+`,
+			Imports: []string{"encore.dev/pubsub"},
+			Want: []usage.Usage{
+				&objects.NotificationUsage{
+					Topic:  pkginfo.QualifiedName{PkgPath: "example.com", Name: "topic"},
+					Events: []string{"object_created"},
+					Prefix: "uploads/",
+				},
+				&pubsub.RefUsage{Perms: []pubsub.Perm{pubsub.PublishPerm}},
+			},
+		},
+		{
+			Name: "invalid_topic_notification_event",
+			Code: `
+var bkt = objects.NewBucket("bucket", objects.BucketConfig{})
+
+var topic = pubsub.NewTopic[*objects.ObjectEvent]("topic", pubsub.TopicConfig{DeliveryGuarantee: pubsub.AtLeastOnce})
+
+var _ = objects.NewTopicNotification(bkt, topic, objects.TopicNotificationConfig{
+	Events: []objects.EventType{"object_renamed"},
+})
+`,
+			Imports:  []string{"encore.dev/pubsub"},
+			WantErrs: []string{"Invalid objects.TopicNotificationConfig"},
+		},
 		{
 			Name: "invalid_ref",
 			Code: `
@@ -163,3 +394,34 @@ var ref = objects.BucketRef[string](bkt)
 
 	usagetest.Run(t, []string{"encore.dev/storage/objects"}, tests)
 }
+
+func TestMethodUsagePerms(t *testing.T) {
+	tests := []struct {
+		Usage objects.MethodUsage
+		Want  []objects.Perm
+	}{
+		{
+			Usage: objects.MethodUsage{Method: "Download", Perm: objects.ReadObjectContents},
+			Want:  []objects.Perm{objects.ReadObjectContents},
+		},
+		{
+			// Removing an object moves it to the trash if soft delete is enabled.
+			Usage: objects.MethodUsage{Method: "Remove", Perm: objects.DeleteObject},
+			Want: []objects.Perm{
+				objects.DeleteObject, objects.GetObjectMetadata,
+				objects.ReadObjectContents, objects.WriteObject,
+			},
+		},
+		{
+			Usage: objects.MethodUsage{Method: "Upload", Perm: objects.WriteObject, Idempotent: true},
+			Want:  []objects.Perm{objects.WriteObject, objects.GetObjectMetadata},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Usage.Method, func(t *testing.T) {
+			if got := tt.Usage.Perms(); !slices.Equal(got, tt.Want) {
+				t.Errorf("got perms %v, want %v", got, tt.Want)
+			}
+		})
+	}
+}
@@ -49,6 +49,7 @@ type SubscriptionConfig struct {
 	MaxRetryBackoff  time.Duration
 	MaxRetries       int
 	MaxConcurrency   int
+	AckDeduplication bool
 }
 
 func (s *Subscription) Kind() resource.Kind       { return resource.PubSubSubscription }
@@ -126,6 +127,7 @@ func parsePubSubSubscription(d parseutil.ReferenceInfo) {
 		AckDeadline      time.Duration `literal:",optional,default"`
 		MessageRetention time.Duration `literal:",optional,default"`
 		RetryPolicy      retryConfig   `literal:",optional,default"`
+		AckDeduplication bool          `literal:",optional"`
 	}
 	defaults := decodedConfig{
 		MaxConcurrency:   100,
@@ -168,6 +170,7 @@ func parsePubSubSubscription(d parseutil.ReferenceInfo) {
 		MaxRetryBackoff:  cfg.RetryPolicy.MaxRetryBackoff,
 		MaxRetries:       cfg.RetryPolicy.MaxRetries,
 		MaxConcurrency:   cfg.MaxConcurrency,
+		AckDeduplication: cfg.AckDeduplication,
 	}
 
 	if cfg.Handler == nil {
@@ -36,7 +36,7 @@ const (
 func ResolveTopicUsage(data usage.ResolveData, topic *Topic) usage.Usage {
 	switch expr := data.Expr.(type) {
 	case *usage.MethodCall:
-		if expr.Method == "Publish" {
+		if expr.Method == "Publish" || expr.Method == "PublishBatch" {
 			return &PublishUsage{
 				Base: usage.Base{
 					File: expr.File,
@@ -58,6 +58,16 @@ func ResolveTopicUsage(data usage.ResolveData, topic *Topic) usage.Usage {
 			return nil
 		case option.Contains(expr.PkgFunc, pkginfo.Q("encore.dev/pubsub", "TopicRef")):
 			return parseTopicRef(data.Errs, expr)
+		case option.Contains(expr.PkgFunc, pkginfo.Q("encore.dev/storage/objects", "NewTopicNotification")):
+			// The bucket's object events are published to the topic.
+			return &RefUsage{
+				Base: usage.Base{
+					File: expr.File,
+					Bind: expr.Bind,
+					Expr: expr,
+				},
+				Perms: []Perm{PublishPerm},
+			}
 		}
 	}
 
@@ -29,6 +29,18 @@ var topic = pubsub.NewTopic[Msg]("topic", pubsub.TopicConfig{DeliveryGuarantee:
 
 func Foo() { topic.Publish(context.Background(), Msg{}) }
 
+`,
+			Want: []usage.Usage{&pubsub.PublishUsage{}},
+		},
+		{
+			Name: "publish_batch",
+			Code: `
+type Msg struct{}
+
+var topic = pubsub.NewTopic[Msg]("topic", pubsub.TopicConfig{DeliveryGuarantee: pubsub.AtLeastOnce})
+
+func Foo() { topic.PublishBatch(context.Background(), Msg{}, Msg{}) }
+
 `,
 			Want: []usage.Usage{&pubsub.PublishUsage{}},
 		},